@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/repository/postgres"
+	"github.com/pivaldi/mmw/todo/internal/pkg/preflight"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// maxClockSkew is the largest drift tolerated between the clocks of the
+// service and the database; due dates and token expiries compare both
+const maxClockSkew = 5 * time.Second
+
+// minAdminTokenLength is the length from which an admin token is not
+// reported as guessable
+const minAdminTokenLength = 32
+
+// pinger is implemented by the dispatchers connected to a broker
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// preflightChecks lists the checks run before serving, so that a broken
+// deploy fails at startup with the way to fix it rather than at the first
+// request
+// Without a pool, on SQLite which is migrated as it is opened or on MongoDB
+// whose indexes are created as it is connected, only the configuration and
+// the broker are checked
+func preflightChecks(config Config, pool *pgxpool.Pool, dispatcher ports.EventDispatcher) []preflight.Check {
+	var maxConns int32
+	if pool != nil {
+		maxConns = pool.Config().MaxConns
+	}
+	checks := []preflight.Check{
+		{Name: "config", Run: func(ctx context.Context) error {
+			return checkConfig(config, maxConns)
+		}},
+		{Name: "config_advice", Warning: true, Run: func(ctx context.Context) error {
+			return adviseConfig(config)
+		}},
+	}
+	if pool != nil {
+		checks = append(checks,
+			preflight.Check{Name: "migrations", Run: func(ctx context.Context) error {
+				return checkMigrations(ctx, pool)
+			}},
+			preflight.Check{Name: "migrations_pending", Warning: true, Run: func(ctx context.Context) error {
+				return checkPendingMigrations(ctx, pool)
+			}},
+			preflight.Check{Name: "indexes", Run: func(ctx context.Context) error {
+				return checkIndexes(ctx, pool)
+			}},
+			preflight.Check{Name: "clock_skew", Run: func(ctx context.Context) error {
+				return checkClockSkew(ctx, pool)
+			}},
+		)
+	}
+
+	// Dispatches are retried until the broker is reached, so an unreachable
+	// broker does not prevent the startup
+	if broker, ok := dispatcher.(pinger); ok {
+		checks = append(checks, preflight.Check{Name: "event_broker", Warning: true, Run: func(ctx context.Context) error {
+			return preflight.Hint(broker.Ping(ctx), fmt.Sprintf(
+				"check that the %s broker is up and reachable, see the %s_* settings; events are not published until it is",
+				config.EventDispatcher, strings.ToUpper(config.EventDispatcher)))
+		}})
+	}
+
+	return checks
+}
+
+// checkConfig parses every setting, reporting all the invalid ones at once,
+// and the settings that conflict
+func checkConfig(config Config, maxConns int32) error {
+	var errs []error
+	if _, err := parseReminderOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseAnomalyOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseCalendarOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, err := parseWebhookOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseTodoCache(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseEventRelayOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, err := newSIEMExporter(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseReconcilerOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseWatchOptions(config); err != nil {
+		errs = append(errs, err)
+	}
+	if config.QueryGuard {
+		if _, err := parseQueryGuardOptions(config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return preflight.Hint(errors.Join(errs...),
+			"correct the variables named above, see the configuration table of docs/DEVELOPMENT.md")
+	}
+
+	if _, err := parseOwnerBulkhead(config); err != nil {
+		return preflight.Hint(err, "set OWNER_MAX_QUERIES to a positive number, or 0 to disable the cap")
+	}
+	if reconciler, _ := parseReconcilerOptions(config); reconciler.Interval > 0 && !config.EventOutbox {
+		return preflight.Hint(
+			errors.New("RECONCILE_INTERVAL is set while EVENT_OUTBOX is false, so every todo would be reported unrecorded"),
+			"set EVENT_OUTBOX=true, or RECONCILE_INTERVAL=0")
+	}
+	if ownerMaxQueries, _ := strconv.Atoi(config.OwnerMaxQueries); ownerMaxQueries > 0 && int32(ownerMaxQueries) >= maxConns {
+		return preflight.Hint(
+			fmt.Errorf("OWNER_MAX_QUERIES (%s) is not below the pool size (%d), so one user can still use up the pool", config.OwnerMaxQueries, maxConns),
+			"lower OWNER_MAX_QUERIES, or raise pool_max_conns in DATABASE_URL")
+	}
+
+	return nil
+}
+
+// adviseConfig reports the settings that work but are likely mistakes
+func adviseConfig(config Config) error {
+	var advice []error
+	if config.AdminToken != "" && len(config.AdminToken) < minAdminTokenLength {
+		advice = append(advice, fmt.Errorf("ADMIN_TOKEN is shorter than %d characters", minAdminTokenLength))
+	}
+	if config.ReconcileDryRun && config.ReconcileInterval == "0" {
+		advice = append(advice, errors.New("RECONCILE_DRY_RUN has no effect while RECONCILE_INTERVAL is 0"))
+	}
+	if len(advice) > 0 {
+		return preflight.Hint(errors.Join(advice...),
+			"generate the admin token with `openssl rand -hex 32`, and set RECONCILE_INTERVAL to reconcile the outbox")
+	}
+
+	return nil
+}
+
+// checkMigrations fails when no migration ran or the last one failed halfway
+func checkMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	status, err := postgres.ReadMigrationStatus(ctx, pool)
+	if err != nil {
+		return preflight.Hint(err, "check that the DATABASE_URL user may read the schema")
+	}
+	if !status.Applied {
+		return preflight.Hint(errors.New("no migration applied"), "run `make db-migrate-up`")
+	}
+	if status.Dirty {
+		return preflight.Hint(fmt.Errorf("migration %d failed halfway", status.Version), fmt.Sprintf(
+			"finish or revert migration %d by hand, then run `migrate -path ./scripts/migrations -database \"$DATABASE_URL\" force %d` and `make db-migrate-up`",
+			status.Version, status.Version-1))
+	}
+
+	return nil
+}
+
+// checkPendingMigrations reports the migrations this binary knows about that
+// did not run yet
+// During a blue/green deploy the binary legitimately runs before its
+// migrations, with their schema features disabled
+func checkPendingMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	status, err := postgres.ReadMigrationStatus(ctx, pool)
+	if err != nil || !status.Applied || status.Dirty {
+		// Reported by the migrations check
+		return nil
+	}
+	if status.Version < postgres.LatestMigration {
+		return preflight.Hint(
+			fmt.Errorf("schema at migration %d, %d pending", status.Version, postgres.LatestMigration-status.Version),
+			"run `make db-migrate-up` once every instance runs this version")
+	}
+
+	return nil
+}
+
+// checkIndexes fails when an index the queries rely on is missing, which
+// would turn them into sequential scans
+func checkIndexes(ctx context.Context, pool *pgxpool.Pool) error {
+	status, err := postgres.ReadMigrationStatus(ctx, pool)
+	if err != nil || !status.Applied {
+		// Reported by the migrations check
+		return nil
+	}
+
+	missing, err := postgres.MissingIndexes(ctx, pool, min(status.Version, postgres.LatestMigration))
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(missing))
+	migrations := make([]string, 0, len(missing))
+	for i, index := range missing {
+		names[i] = index.Name
+		migration := fmt.Sprintf("scripts/migrations/%06d_*.up.sql", index.Migration)
+		if len(migrations) == 0 || migrations[len(migrations)-1] != migration {
+			migrations = append(migrations, migration)
+		}
+	}
+	return preflight.Hint(fmt.Errorf("missing indexes: %s", strings.Join(names, ", ")), fmt.Sprintf(
+		"recreate them with the CREATE INDEX statements of %s",
+		strings.Join(migrations, ", ")))
+}
+
+// checkClockSkew fails when the database clock drifted from the local one
+func checkClockSkew(ctx context.Context, pool *pgxpool.Pool) error {
+	skew, err := postgres.DatabaseClockSkew(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if skew.Abs() > maxClockSkew {
+		return preflight.Hint(
+			fmt.Errorf("database clock is %v off the local one, more than %v", skew.Round(time.Millisecond), maxClockSkew),
+			"synchronize the clocks of this host and of the database server with NTP")
+	}
+
+	return nil
+}
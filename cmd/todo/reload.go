@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// readEnvFile reads the KEY=VALUE lines of path, skipping blank lines and #
+// comments; an empty path has no settings
+func readEnvFile(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening ENV_FILE: %w", err)
+	}
+	defer file.Close()
+
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid ENV_FILE line %d, want KEY=VALUE", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		settings[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ENV_FILE: %w", err)
+	}
+
+	return settings, nil
+}
+
+// parseLogLevel reads LOG_LEVEL, debug by default outside production
+func parseLogLevel(config Config) (slog.Level, error) {
+	if config.LogLevel == "" {
+		if config.Environment == "production" {
+			return slog.LevelInfo, nil
+		}
+		return slog.LevelDebug, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		return 0, fmt.Errorf("invalid LOG_LEVEL: %q, want debug, info, warn or error", config.LogLevel)
+	}
+	return level, nil
+}
+
+// parseCORSOrigins reads the comma-separated origins of
+// CORS_ALLOWED_ORIGINS, where * allows any origin
+func parseCORSOrigins(config Config) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(config.CORSAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin == "" {
+			continue
+		}
+		if origin != "*" {
+			parsed, err := url.Parse(origin)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+				parsed.Path != "" || parsed.RawQuery != "" {
+				return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS origin %q, want scheme://host[:port]", origin)
+			}
+		}
+		origins = append(origins, origin)
+	}
+	return origins, nil
+}
+
+// corsOrigins holds the origins browsers may call the API from; it is safe
+// for concurrent use
+type corsOrigins struct {
+	allowed atomic.Pointer[[]string]
+}
+
+// newCORSOrigins allows the origins allowed
+func newCORSOrigins(allowed []string) *corsOrigins {
+	origins := &corsOrigins{}
+	origins.Set(allowed)
+	return origins
+}
+
+// Set replaces the allowed origins
+func (c *corsOrigins) Set(allowed []string) {
+	c.allowed.Store(&allowed)
+}
+
+// setHeaders allows origin in header when it is allowed
+func (c *corsOrigins) setHeaders(header http.Header, origin string) {
+	allowed := *c.allowed.Load()
+	if slices.Contains(allowed, "*") {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	// The response differs by origin, so caches must not share it
+	header.Add("Vary", "Origin")
+	if origin != "" && slices.Contains(allowed, origin) {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+}
+
+// reloadTargets are the components whose settings apply without restart;
+// the jobs and the query guard are nil when disabled at startup
+type reloadTargets struct {
+	logLevel    *slog.LevelVar
+	cors        *corsOrigins
+	maintenance *application.MaintenanceMode
+	queryGuard  *application.QueryGuard
+	reminders   *application.ReminderScheduler
+	reconciler  *application.OutboxReconciler
+}
+
+// reloadableSettings lists the settings of targets applied on reload
+// Enabling or disabling the query guard or a job takes a restart
+func reloadableSettings(targets reloadTargets) []application.ReloadableSetting {
+	settings := []application.ReloadableSetting{
+		{Names: []string{"LOG_LEVEL"}, Prepare: func(values map[string]string) (func(), error) {
+			level, err := parseLogLevel(configFrom(values))
+			if err != nil {
+				return nil, err
+			}
+			return func() { targets.logLevel.Set(level) }, nil
+		}},
+		{Names: []string{"CORS_ALLOWED_ORIGINS"}, Prepare: func(values map[string]string) (func(), error) {
+			origins, err := parseCORSOrigins(configFrom(values))
+			if err != nil {
+				return nil, err
+			}
+			return func() { targets.cors.Set(origins) }, nil
+		}},
+		{Names: []string{"MAINTENANCE_MODE", "MAINTENANCE_MESSAGE"}, Prepare: func(values map[string]string) (func(), error) {
+			config := configFrom(values)
+			return func() {
+				if config.MaintenanceMode {
+					targets.maintenance.Enable(config.MaintenanceMessage)
+				} else {
+					targets.maintenance.Disable()
+				}
+			}, nil
+		}},
+	}
+
+	if targets.queryGuard != nil {
+		settings = append(settings, application.ReloadableSetting{
+			Names: []string{"QUERY_MAX_LIMIT", "QUERY_MAX_OFFSET", "QUERY_EXPENSIVE_BUDGET"},
+			Prepare: func(values map[string]string) (func(), error) {
+				options, err := parseQueryGuardOptions(configFrom(values))
+				if err != nil {
+					return nil, err
+				}
+				return func() { targets.queryGuard.SetOptions(options) }, nil
+			},
+		})
+	}
+
+	if targets.reminders != nil {
+		settings = append(settings, application.ReloadableSetting{
+			Names: []string{"REMINDER_INTERVAL", "REMINDER_LEAD"},
+			Prepare: func(values map[string]string) (func(), error) {
+				options, err := parseReminderOptions(configFrom(values))
+				if err != nil {
+					return nil, err
+				}
+				if options.Interval <= 0 {
+					return nil, errors.New("REMINDER_INTERVAL must stay positive, disabling reminders takes a restart")
+				}
+				return func() { targets.reminders.SetOptions(options) }, nil
+			},
+		})
+	}
+
+	if targets.reconciler != nil {
+		settings = append(settings, application.ReloadableSetting{
+			Names: []string{"RECONCILE_INTERVAL", "RECONCILE_DRY_RUN"},
+			Prepare: func(values map[string]string) (func(), error) {
+				options, err := parseReconcilerOptions(configFrom(values))
+				if err != nil {
+					return nil, err
+				}
+				if options.Interval <= 0 {
+					return nil, errors.New("RECONCILE_INTERVAL must stay positive, disabling reconciliation takes a restart")
+				}
+				return func() { targets.reconciler.SetOptions(options) }, nil
+			},
+		})
+	}
+
+	return settings
+}
+
+// reloadOnSignal reloads the configuration on every SIGHUP until ctx is done
+func reloadOnSignal(ctx context.Context, reloader *application.ConfigReloader, logger *slog.Logger) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+			logger.Info("SIGHUP received, reloading configuration")
+			_, err := reloader.Reload(ctx, application.ConfigReloadRequest{Actor: "SIGHUP", Reason: "Reload on SIGHUP"})
+			if err != nil {
+				logger.Error("configuration reload failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// TokenVerifier validates a bearer token and returns the identity it was
+// issued to
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (Identity, error)
+}
+
+// APIKeyAuthenticator resolves the API key of a secret
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*application.APIKeyResponse, error)
+}
+
+// CredentialError is a Bearer credential Authenticator refused
+type CredentialError struct {
+	// Reason is safe to return to the caller
+	Reason string
+	// Unavailable reports a credential that could not be checked, and may
+	// well be valid
+	Unavailable bool
+}
+
+// Error returns the reason of the refusal
+func (e *CredentialError) Error() string {
+	return e.Reason
+}
+
+// Authenticator authenticates callers with a Bearer JWT or API key, for the
+// Connect and REST handlers alike
+type Authenticator struct {
+	// JWTs verifies Bearer JWTs, refused when nil
+	JWTs TokenVerifier
+	// APIKeys resolves Bearer API keys, told apart from JWTs by
+	// application.APIKeyPrefix, refused when nil
+	APIKeys APIKeyAuthenticator
+}
+
+// Enabled reports whether the authenticator accepts any credential
+func (a Authenticator) Enabled() bool {
+	return a.JWTs != nil || a.APIKeys != nil
+}
+
+// Authenticate verifies the Bearer credential of an Authorization header
+// value and returns ctx carrying the caller identity, or a *CredentialError
+// The subject of a JWT or the user of an API key becomes the user ID; the
+// scopes and roles are set when the credential carries them, and API keys
+// always grant their scopes, possibly none
+func (a Authenticator) Authenticate(ctx context.Context, authorization string) (context.Context, error) {
+	token, ok := BearerToken(authorization)
+	if !ok {
+		return ctx, &CredentialError{Reason: "missing bearer token"}
+	}
+
+	if strings.HasPrefix(token, application.APIKeyPrefix) {
+		return a.authenticateAPIKey(ctx, token)
+	}
+	if a.JWTs == nil {
+		return ctx, &CredentialError{Reason: "invalid bearer token"}
+	}
+
+	identity, err := a.JWTs.Verify(ctx, token)
+	if errors.Is(err, ErrInvalidToken) {
+		return ctx, &CredentialError{Reason: "invalid bearer token"}
+	}
+	if err != nil {
+		// The key set could not be fetched: the token may well be valid
+		return ctx, &CredentialError{Reason: "cannot verify bearer token", Unavailable: true}
+	}
+
+	ctx = application.ContextWithUserID(ctx, identity.Subject)
+	if identity.HasScopes {
+		ctx = application.ContextWithScopes(ctx, identity.Scopes)
+	}
+	if identity.HasRoles {
+		ctx = application.ContextWithRoles(ctx, identity.Roles)
+	}
+	return ctx, nil
+}
+
+// authenticateAPIKey returns ctx carrying the user, key and scopes of the API
+// key of secret
+func (a Authenticator) authenticateAPIKey(ctx context.Context, secret string) (context.Context, error) {
+	if a.APIKeys == nil {
+		return ctx, &CredentialError{Reason: "API keys are not accepted"}
+	}
+
+	key, err := a.APIKeys.Authenticate(ctx, secret)
+	if errors.Is(err, application.ErrInvalidAPIKey) {
+		return ctx, &CredentialError{Reason: "invalid API key"}
+	}
+	if err != nil {
+		return ctx, &CredentialError{Reason: "cannot verify API key", Unavailable: true}
+	}
+
+	ctx = application.ContextWithUserID(ctx, key.UserID)
+	ctx = application.ContextWithAPIKeyID(ctx, key.ID)
+	return application.ContextWithScopes(ctx, key.Scopes), nil
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed,
+// expired, or issued by or for someone else
+var ErrInvalidToken = errors.New("invalid token")
+
+// signingMethods are the asymmetric algorithms accepted in token headers
+// Symmetric ones are refused: a JWKS only publishes public keys
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Identity is the caller a valid token was issued to
+type Identity struct {
+	// Subject is the sub claim, the caller's user ID
+	Subject string
+	// Scopes are read from the space separated scope claim, or the scp array
+	// HasScopes is false when the token carries neither
+	Scopes    []string
+	HasScopes bool
+	// Roles are read from the roles claim, an array or a space separated
+	// string; HasRoles is false when the token does not carry it
+	Roles    []string
+	HasRoles bool
+}
+
+// DefaultRolesClaim is the claim roles are read from by default
+const DefaultRolesClaim = "roles"
+
+// JWTVerifierOptions configures a JWTVerifier
+type JWTVerifierOptions struct {
+	// Issuer is the required iss claim
+	Issuer string
+	// Audience is a value the aud claim must contain, not checked when empty
+	Audience string
+	// Leeway tolerates clock skew on exp, nbf and iat
+	Leeway time.Duration
+	// RolesClaim names the top-level claim carrying roles, DefaultRolesClaim
+	// when empty
+	RolesClaim string
+}
+
+// JWTVerifier validates Bearer JWTs signed by keys published in a JWKS
+type JWTVerifier struct {
+	keys    *JWKS
+	options JWTVerifierOptions
+}
+
+// NewJWTVerifier creates a verifier checking signatures with keys
+func NewJWTVerifier(keys *JWKS, options JWTVerifierOptions) *JWTVerifier {
+	if options.RolesClaim == "" {
+		options.RolesClaim = DefaultRolesClaim
+	}
+	return &JWTVerifier{keys: keys, options: options}
+}
+
+// claims are the registered claims plus the scope conventions, and every
+// claim in raw, for those named by configuration
+type claims struct {
+	jwt.RegisteredClaims
+	Scope *string  `json:"scope,omitempty"`
+	Scp   []string `json:"scp,omitempty"`
+	raw   map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes the known claims and keeps them all in raw
+func (c *claims) UnmarshalJSON(data []byte) error {
+	type known claims
+	if err := json.Unmarshal(data, (*known)(c)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.raw)
+}
+
+// roles returns the roles carried by claim, and false when the token does
+// not carry it
+func (c *claims) roles(claim string) ([]string, bool, error) {
+	raw, ok := c.raw[claim]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		if list == nil {
+			list = []string{}
+		}
+		return list, true, nil
+	}
+	var spaced string
+	if err := json.Unmarshal(raw, &spaced); err != nil {
+		return nil, false, fmt.Errorf("%s claim is neither a string nor an array of strings", claim)
+	}
+	return strings.Fields(spaced), true, nil
+}
+
+// Verify checks token and returns the identity it was issued to
+// Every failure wraps ErrInvalidToken, except ErrKeySetUnavailable: the
+// token could not be checked
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(v.options.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.options.Leeway),
+	}
+	if v.options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(v.options.Audience))
+	}
+
+	var parsed claims
+	_, err := jwt.ParseWithClaims(token, &parsed, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	}, parserOptions...)
+	if errors.Is(err, ErrKeySetUnavailable) {
+		return Identity{}, err
+	}
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if parsed.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+
+	identity := Identity{Subject: parsed.Subject}
+	switch {
+	case parsed.Scp != nil:
+		identity.Scopes, identity.HasScopes = parsed.Scp, true
+	case parsed.Scope != nil:
+		identity.Scopes, identity.HasScopes = strings.Fields(*parsed.Scope), true
+	}
+	identity.Roles, identity.HasRoles, err = parsed.roles(v.options.RolesClaim)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return identity, nil
+}
+
+// BearerToken extracts the token of an "Authorization: Bearer" header value
+func BearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
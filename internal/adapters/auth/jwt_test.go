@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testIdP serves a JWKS holding one RSA and one EC key
+type testIdP struct {
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	fetches atomic.Int32
+	server  *httptest.Server
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &testIdP{rsaKey: rsaKey, ecKey: ecKey}
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X.Bytes()), "y": encode(ecKey.Y.Bytes())},
+		{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+	}}
+	idp.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *testIdP) sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	idp := newTestIdP(t)
+	verifier := NewJWTVerifier(NewJWKS(idp.server.URL), JWTVerifierOptions{
+		Issuer:   "https://idp.example.com",
+		Audience: "todo-api",
+	})
+
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://idp.example.com",
+			"aud": "todo-api",
+			"sub": "alice",
+			"exp": now.Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value any) jwt.MapClaims {
+		claims := valid()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantErr    bool
+		wantScopes []string
+	}{
+		{"RSA", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, valid()), false, nil},
+		{"EC", idp.sign(t, jwt.SigningMethodES256, "ec-1", idp.ecKey, valid()), false, nil},
+		{"scope claim", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("scope", "read write")), false, []string{"read", "write"}},
+		{"scp claim", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("scp", []string{"admin"})), false, []string{"admin"}},
+		{"wrong issuer", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("iss", "https://evil.example.com")), true, nil},
+		{"wrong audience", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("aud", "other-api")), true, nil},
+		{"expired", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("exp", now.Add(-time.Hour).Unix())), true, nil},
+		{"no expiry", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("exp", nil)), true, nil},
+		{"no subject", idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, with("sub", nil)), true, nil},
+		{"key mismatch", idp.sign(t, jwt.SigningMethodRS256, "ec-1", idp.rsaKey, valid()), true, nil},
+		{"unknown key", idp.sign(t, jwt.SigningMethodRS256, "rsa-2", idp.rsaKey, valid()), true, nil},
+		{"symmetric", idp.sign(t, jwt.SigningMethodHS256, "hmac", []byte("secret"), valid()), true, nil},
+		{"garbage", "not.a.token", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if identity.Subject != "alice" {
+				t.Errorf("Subject = %q, want alice", identity.Subject)
+			}
+			if identity.HasScopes != (tt.wantScopes != nil) || len(identity.Scopes) != len(tt.wantScopes) {
+				t.Fatalf("Scopes = %v (%v), want %v", identity.Scopes, identity.HasScopes, tt.wantScopes)
+			}
+			for i, scope := range tt.wantScopes {
+				if identity.Scopes[i] != scope {
+					t.Errorf("Scopes = %v, want %v", identity.Scopes, tt.wantScopes)
+				}
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_Roles(t *testing.T) {
+	idp := newTestIdP(t)
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{"iss": "https://idp.example.com", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}
+		for key, value := range extra {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name      string
+		claim     string
+		extra     jwt.MapClaims
+		wantErr   bool
+		wantRoles []string
+	}{
+		{"no roles", "", nil, false, nil},
+		{"array", "", jwt.MapClaims{"roles": []string{"viewer", "editor"}}, false, []string{"viewer", "editor"}},
+		{"space separated", "", jwt.MapClaims{"roles": "admin viewer"}, false, []string{"admin", "viewer"}},
+		{"empty array", "", jwt.MapClaims{"roles": []string{}}, false, []string{}},
+		{"custom claim", "https://todo.example.com/roles", jwt.MapClaims{"roles": "admin", "https://todo.example.com/roles": []string{"viewer"}}, false, []string{"viewer"}},
+		{"malformed", "", jwt.MapClaims{"roles": 42}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewJWTVerifier(NewJWKS(idp.server.URL), JWTVerifierOptions{
+				Issuer:     "https://idp.example.com",
+				RolesClaim: tt.claim,
+			})
+			identity, err := verifier.Verify(context.Background(), idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, claims(tt.extra)))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if identity.HasRoles != (tt.wantRoles != nil) || !slices.Equal(identity.Roles, tt.wantRoles) {
+				t.Errorf("Roles = %v (%v), want %v", identity.Roles, identity.HasRoles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestJWKS_RefetchesUnknownKeysAtMostOncePerInterval(t *testing.T) {
+	idp := newTestIdP(t)
+	jwks := NewJWKS(idp.server.URL)
+	now := time.Now()
+	jwks.now = func() time.Time { return now }
+
+	if _, err := jwks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("Key() unexpected error: %v", err)
+	}
+	for range 3 {
+		if _, err := jwks.Key(context.Background(), "rolled"); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("Key() error = %v, want ErrUnknownKey", err)
+		}
+	}
+	if got := idp.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+
+	now = now.Add(DefaultJWKSRefreshInterval)
+	_, _ = jwks.Key(context.Background(), "rolled")
+	if got := idp.fetches.Load(); got != 2 {
+		t.Errorf("fetches after interval = %d, want 2", got)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		wantOK bool
+	}{
+		{"Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"bearer abc", "abc", true},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := BearerToken(tt.header)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("BearerToken(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestJWTVerifier_KeySetUnavailable(t *testing.T) {
+	idp := newTestIdP(t)
+	token := idp.sign(t, jwt.SigningMethodRS256, "rsa-1", idp.rsaKey, jwt.MapClaims{
+		"iss": "https://idp.example.com",
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	idp.server.Close()
+
+	verifier := NewJWTVerifier(NewJWKS(idp.server.URL), JWTVerifierOptions{Issuer: "https://idp.example.com"})
+	_, err := verifier.Verify(context.Background(), token)
+	if !errors.Is(err, ErrKeySetUnavailable) || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() error = %v, want ErrKeySetUnavailable only", err)
+	}
+}
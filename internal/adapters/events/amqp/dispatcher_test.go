@@ -0,0 +1,208 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// published is a message published on a fakeChannel
+type published struct {
+	Exchange string
+	Key      string
+	Msg      amqp091.Publishing
+}
+
+// fakeConnection opens fakeChannels sharing its record of publications
+type fakeConnection struct {
+	Channels  []*fakeChannel
+	Published []published
+	Err       error
+	closed    bool
+}
+
+func (c *fakeConnection) Channel() (channel, error) {
+	ch := &fakeChannel{conn: c, Err: c.Err}
+	c.Channels = append(c.Channels, ch)
+	return ch, nil
+}
+
+func (c *fakeConnection) IsClosed() bool { return c.closed }
+
+func (c *fakeConnection) Close() error {
+	c.closed = true
+	return nil
+}
+
+// fakeChannel records the messages published, or fails with Err
+type fakeChannel struct {
+	conn   *fakeConnection
+	Err    error
+	closed bool
+}
+
+func (c *fakeChannel) Publish(ctx context.Context, exchange, key string, msg amqp091.Publishing) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.conn.Published = append(c.conn.Published, published{exchange, key, msg})
+	return nil
+}
+
+func (c *fakeChannel) IsClosed() bool { return c.closed || c.conn.closed }
+
+func (c *fakeChannel) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRoutingKey(t *testing.T) {
+	tests := map[string]string{
+		"TodoCreated":             "todo.created",
+		"TodoDueSoon":             "todo.due_soon",
+		"SecurityAnomalyDetected": "todo.security_anomaly_detected",
+	}
+	for eventType, want := range tests {
+		if got := RoutingKey("todo", eventType); got != want {
+			t.Errorf("RoutingKey(%q) = %q, want %q", eventType, got, want)
+		}
+	}
+}
+
+func TestEventDispatcher_Dispatch(t *testing.T) {
+	conn := &fakeConnection{}
+	dispatcher := newEventDispatcher(func() (connection, error) { return conn, nil }, DefaultOptions())
+
+	id := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Test Todo")
+	events := []domain.DomainEvent{
+		domain.NewTodoCreatedEvent(id, title, "Description", domain.PriorityMedium, nil),
+		domain.NewTodoDeletedEvent(id, ""),
+	}
+	if err := dispatcher.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+	if err := dispatcher.Dispatch(context.Background(), events[1:]); err != nil {
+		t.Fatalf("second Dispatch() unexpected error: %v", err)
+	}
+
+	if len(conn.Channels) != 1 {
+		t.Errorf("opened %d channels, want the first one reused", len(conn.Channels))
+	}
+	if len(conn.Published) != 3 {
+		t.Fatalf("published %d messages, want 3", len(conn.Published))
+	}
+	for i, want := range []string{"todo.created", "todo.deleted"} {
+		msg := conn.Published[i]
+		if msg.Exchange != "todo.events" || msg.Key != want {
+			t.Errorf("message %d went to %s with %q, want todo.events with %q", i, msg.Exchange, msg.Key, want)
+		}
+		if msg.Msg.Type != events[i].EventType() || msg.Msg.DeliveryMode != amqp091.Persistent || len(msg.Msg.Body) == 0 {
+			t.Errorf("message %d = %+v, want the persistent encoded %s", i, msg.Msg, events[i].EventType())
+		}
+	}
+
+	if err := dispatcher.Close(); err != nil {
+		t.Errorf("Close() unexpected error: %v", err)
+	}
+	if !conn.closed || !conn.Channels[0].closed {
+		t.Error("Close() left the connection or its channel open")
+	}
+	if err := dispatcher.Dispatch(context.Background(), events); !errors.Is(err, ErrClosed) {
+		t.Errorf("Dispatch() after Close() error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestEventDispatcher_Dispatch_NotConfirmed_ClosesChannel(t *testing.T) {
+	conn := &fakeConnection{Err: errNacked}
+	dispatcher := newEventDispatcher(func() (connection, error) { return conn, nil }, DefaultOptions())
+
+	err := dispatcher.Dispatch(context.Background(), []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")})
+	if !errors.Is(err, errNacked) {
+		t.Errorf("Dispatch() error = %v, want the confirmation error", err)
+	}
+	if !conn.Channels[0].closed {
+		t.Error("failed channel kept open, want it closed")
+	}
+}
+
+func TestEventDispatcher_Reconnects(t *testing.T) {
+	unreachable := errors.New("connection refused")
+	conn := &fakeConnection{}
+	dials := 0
+	dispatcher := newEventDispatcher(func() (connection, error) {
+		dials++
+		if dials == 1 {
+			return nil, unreachable
+		}
+		return conn, nil
+	}, DefaultOptions())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	events := []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")}
+
+	if err := dispatcher.Dispatch(context.Background(), events); !errors.Is(err, unreachable) {
+		t.Fatalf("Dispatch() error = %v, want the dial error", err)
+	}
+	// Within ReconnectWait the last error is returned without dialing
+	if err := dispatcher.Dispatch(context.Background(), events); !errors.Is(err, unreachable) || dials != 1 {
+		t.Fatalf("Dispatch() error = %v after %d dials, want the dial error after 1", err, dials)
+	}
+
+	now = now.Add(DefaultOptions().ReconnectWait)
+	if err := dispatcher.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() after ReconnectWait unexpected error: %v", err)
+	}
+
+	// A lost connection is replaced along with its channels
+	conn.closed = true
+	conn = &fakeConnection{}
+	if err := dispatcher.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() after a lost connection unexpected error: %v", err)
+	}
+	if dials != 3 || len(conn.Published) != 1 {
+		t.Errorf("dials = %d, published = %d, want 3 dials and the event on the new connection", dials, len(conn.Published))
+	}
+}
+
+func TestEventDispatcher_Ping(t *testing.T) {
+	unreachable := errors.New("connection refused")
+	dialErr := unreachable
+	dispatcher := newEventDispatcher(func() (connection, error) {
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		return &fakeConnection{}, nil
+	}, Options{})
+
+	if err := dispatcher.Ping(context.Background()); !errors.Is(err, unreachable) {
+		t.Errorf("Ping() error = %v, want the dial error", err)
+	}
+
+	dialErr = nil
+	if err := dispatcher.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() unexpected error: %v", err)
+	}
+
+	_ = dispatcher.Close()
+	if err := dispatcher.Ping(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Ping() after Close() error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestMessageID_IdentifiesEvents(t *testing.T) {
+	id := domain.NewTodoID()
+	deleted := domain.NewTodoDeletedEvent(id, "")
+
+	if messageID(deleted) != messageID(deleted) {
+		t.Error("messageID() differs for the same event")
+	}
+	if messageID(deleted) == messageID(domain.NewTodoUnarchivedEvent(id)) {
+		t.Error("messageID() is the same for different events")
+	}
+}
@@ -0,0 +1,200 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// DefaultSubscriberBuffer is the number of events a subscriber may lag behind
+// before it is dropped
+const DefaultSubscriberBuffer = 64
+
+// DefaultReplayBuffer is the number of recent events kept to resume
+// subscriptions
+const DefaultReplayBuffer = 1024
+
+// Broadcaster decorates an EventDispatcher, fanning dispatched events out to
+// live subscribers in this process
+// Subscribers see the events even when the next dispatcher fails, since the
+// changes they describe are already saved
+// Events are numbered and the most recent ones kept, so subscribers can
+// resume after a disconnection; positions are only valid in this process
+type Broadcaster struct {
+	next   ports.EventDispatcher
+	buffer int
+	replay int
+	epoch  string
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	sequence    uint64
+	recent      []ports.PositionedEvent
+}
+
+// subscriber receives events on the channel of its kind: plain events for
+// Subscribe, positioned events for SubscribeFrom
+type subscriber struct {
+	events     chan domain.DomainEvent
+	positioned chan ports.PositionedEvent
+}
+
+// send delivers event without blocking, reporting whether it fit the buffer
+func (s *subscriber) send(event ports.PositionedEvent) bool {
+	if s.positioned != nil {
+		select {
+		case s.positioned <- event:
+			return true
+		default:
+			return false
+		}
+	}
+	select {
+	case s.events <- event.Event:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the channel of the subscriber
+func (s *subscriber) close() {
+	if s.positioned != nil {
+		close(s.positioned)
+		return
+	}
+	close(s.events)
+}
+
+// NewBroadcaster creates a Broadcaster in front of next
+func NewBroadcaster(next ports.EventDispatcher) *Broadcaster {
+	epoch := make([]byte, 4)
+	_, _ = rand.Read(epoch)
+
+	return &Broadcaster{
+		next:        next,
+		buffer:      DefaultSubscriberBuffer,
+		replay:      DefaultReplayBuffer,
+		epoch:       hex.EncodeToString(epoch),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Dispatch publishes events to the next dispatcher and to every subscriber
+// A subscriber whose buffer is full is dropped rather than blocking writes
+func (b *Broadcaster) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	err := b.next.Dispatch(ctx, events)
+
+	b.mu.Lock()
+	positioned := make([]ports.PositionedEvent, len(events))
+	for i, event := range events {
+		b.sequence++
+		positioned[i] = ports.PositionedEvent{Event: event, Position: b.position(b.sequence)}
+	}
+	b.recent = append(b.recent, positioned...)
+	if excess := len(b.recent) - b.replay; excess > 0 {
+		b.recent = append(b.recent[:0:0], b.recent[excess:]...)
+	}
+
+	for sub := range b.subscribers {
+		for _, event := range positioned {
+			if !sub.send(event) {
+				delete(b.subscribers, sub)
+				sub.close()
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	return err
+}
+
+// Subscribe returns a channel receiving the events dispatched from now on,
+// closed when ctx is done or the subscriber falls behind
+func (b *Broadcaster) Subscribe(ctx context.Context) <-chan domain.DomainEvent {
+	sub := &subscriber{events: make(chan domain.DomainEvent, b.buffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	b.unsubscribeOnDone(ctx, sub)
+	return sub.events
+}
+
+// SubscribeFrom returns a channel receiving the retained events dispatched
+// after position, then the new ones, closed like the channel of Subscribe
+func (b *Broadcaster) SubscribeFrom(ctx context.Context, position string) (<-chan ports.PositionedEvent, string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	after := b.sequence
+	if position != "" {
+		var err error
+		after, err = b.parsePosition(position)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// recent holds the events numbered from oldest to b.sequence
+	oldest := b.sequence - uint64(len(b.recent)) + 1
+	if after+1 < oldest {
+		return nil, "", fmt.Errorf("%w: events after %s were discarded", ports.ErrEventPositionExpired, position)
+	}
+	missed := b.recent[len(b.recent)-int(b.sequence-after):]
+
+	sub := &subscriber{positioned: make(chan ports.PositionedEvent, b.buffer+len(missed))}
+	for _, event := range missed {
+		sub.positioned <- event
+	}
+	b.subscribers[sub] = struct{}{}
+
+	b.unsubscribeOnDone(ctx, sub)
+	return sub.positioned, b.position(after), nil
+}
+
+// ActiveStreams returns the number of live subscribers
+func (b *Broadcaster) ActiveStreams() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+// unsubscribeOnDone removes sub once ctx is done
+func (b *Broadcaster) unsubscribeOnDone(ctx context.Context, sub *subscriber) {
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			sub.close()
+		}
+	}()
+}
+
+// position formats the position of the event numbered sequence
+func (b *Broadcaster) position(sequence uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(sequence, 10)
+}
+
+// parsePosition returns the sequence number of a position of this
+// broadcaster, b.mu held
+func (b *Broadcaster) parsePosition(position string) (uint64, error) {
+	epoch, raw, ok := strings.Cut(position, "-")
+	sequence, err := strconv.ParseUint(raw, 10, 64)
+	if !ok || err != nil || epoch != b.epoch || sequence > b.sequence {
+		return 0, fmt.Errorf("%w: %q is not a position of this server", ports.ErrEventPositionExpired, position)
+	}
+	return sequence, nil
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// failingDispatcher fails every dispatch
+type failingDispatcher struct{}
+
+func (failingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	return errors.New("broker unavailable")
+}
+
+func TestBroadcaster_Dispatch_DeliversToSubscribers(t *testing.T) {
+	broadcaster := NewBroadcaster(failingDispatcher{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := broadcaster.Subscribe(ctx)
+	second := broadcaster.Subscribe(ctx)
+
+	event := domain.NewTodoUpdatedEvent(domain.NewTodoID())
+	if err := broadcaster.Dispatch(context.Background(), []domain.DomainEvent{event}); err == nil {
+		t.Error("Dispatch() expected the next dispatcher error")
+	}
+
+	for _, ch := range []<-chan domain.DomainEvent{first, second} {
+		if got := <-ch; got.AggregateID() != event.AggregateID() {
+			t.Errorf("received %v, want %v", got.AggregateID(), event.AggregateID())
+		}
+	}
+}
+
+func TestBroadcaster_Subscribe_ClosedOnCancel(t *testing.T) {
+	broadcaster := NewBroadcaster(failingDispatcher{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := broadcaster.Subscribe(ctx)
+	if got := broadcaster.ActiveStreams(); got != 1 {
+		t.Errorf("ActiveStreams() = %d, want 1", got)
+	}
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Error("channel received an event, want it closed")
+	}
+	if got := broadcaster.ActiveStreams(); got != 0 {
+		t.Errorf("ActiveStreams() after cancel = %d, want 0", got)
+	}
+}
+
+func TestBroadcaster_Dispatch_DropsSlowSubscriber(t *testing.T) {
+	broadcaster := NewBroadcaster(failingDispatcher{})
+	broadcaster.buffer = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := broadcaster.Subscribe(ctx)
+
+	events := []domain.DomainEvent{
+		domain.NewTodoUpdatedEvent(domain.NewTodoID()),
+		domain.NewTodoUpdatedEvent(domain.NewTodoID()),
+	}
+	_ = broadcaster.Dispatch(context.Background(), events)
+
+	if _, ok := <-ch; !ok {
+		t.Fatal("buffered event was not delivered")
+	}
+	if _, ok := <-ch; ok {
+		t.Error("slow subscriber was not dropped")
+	}
+}
+
+func TestBroadcaster_SubscribeFrom_ReplaysMissedEvents(t *testing.T) {
+	broadcaster := NewBroadcaster(failingDispatcher{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, start, err := broadcaster.SubscribeFrom(ctx, "")
+	if err != nil {
+		t.Fatalf("SubscribeFrom() unexpected error: %v", err)
+	}
+
+	missed := domain.NewTodoUpdatedEvent(domain.NewTodoID())
+	_ = broadcaster.Dispatch(context.Background(), []domain.DomainEvent{
+		domain.NewTodoUpdatedEvent(domain.NewTodoID()),
+		missed,
+	})
+
+	seen := <-first
+	if seen.Position == start {
+		t.Errorf("event position = %q, want it after %q", seen.Position, start)
+	}
+
+	// Reconnect after the first event, missing the second
+	resumed, from, err := broadcaster.SubscribeFrom(ctx, seen.Position)
+	if err != nil {
+		t.Fatalf("SubscribeFrom(%q) unexpected error: %v", seen.Position, err)
+	}
+	if from != seen.Position {
+		t.Errorf("start = %q, want %q", from, seen.Position)
+	}
+	if got := <-resumed; got.Event.AggregateID() != missed.AggregateID() {
+		t.Errorf("replayed %v, want %v", got.Event.AggregateID(), missed.AggregateID())
+	}
+
+	live := domain.NewTodoDeletedEvent(domain.NewTodoID(), "")
+	_ = broadcaster.Dispatch(context.Background(), []domain.DomainEvent{live})
+	if got := <-resumed; got.Event.AggregateID() != live.AggregateID() {
+		t.Errorf("received %v, want %v", got.Event.AggregateID(), live.AggregateID())
+	}
+}
+
+func TestBroadcaster_SubscribeFrom_ExpiredPositions(t *testing.T) {
+	broadcaster := NewBroadcaster(failingDispatcher{})
+	broadcaster.replay = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, _, _ := broadcaster.SubscribeFrom(ctx, "")
+	_ = broadcaster.Dispatch(context.Background(), []domain.DomainEvent{domain.NewTodoUpdatedEvent(domain.NewTodoID())})
+	first := (<-sub).Position
+	_ = broadcaster.Dispatch(context.Background(), []domain.DomainEvent{
+		domain.NewTodoUpdatedEvent(domain.NewTodoID()),
+		domain.NewTodoUpdatedEvent(domain.NewTodoID()),
+	})
+	<-sub
+	second := (<-sub).Position
+
+	tests := []struct {
+		name     string
+		position string
+		wantErr  bool
+	}{
+		{"retained", second, false},
+		{"discarded", strings.Replace(first, "-1", "-0", 1), true},
+		{"future", strings.Replace(first, "-1", "-9", 1), true},
+		{"other process", "deadbeef-1", true},
+		{"malformed", "yesterday", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := broadcaster.SubscribeFrom(ctx, tt.position)
+			if tt.wantErr != errors.Is(err, ports.ErrEventPositionExpired) {
+				t.Errorf("SubscribeFrom(%q) error = %v, want expired: %v", tt.position, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 // InMemoryEventDispatcher is a simple event dispatcher that logs events
-// In production, this would publish to a message broker (RabbitMQ, Kafka, etc.)
+// In production, the kafka, nats or amqp package publishes them to a
+// message broker
 type InMemoryEventDispatcher struct {
 	logger *slog.Logger
 }
@@ -22,7 +23,7 @@ func NewInMemoryEventDispatcher(logger *slog.Logger) *InMemoryEventDispatcher {
 }
 
 // Dispatch publishes domain events
-// Currently logs events; in production would publish to message broker
+// Events are only logged
 func (d *InMemoryEventDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
 	for _, event := range events {
 		// Serialize event data for logging
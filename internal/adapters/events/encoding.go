@@ -0,0 +1,108 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// EventEncoding is the payload format of the events published to a broker
+type EventEncoding string
+
+const (
+	// EncodingJSON publishes the event envelope as a JSON object
+	EncodingJSON EventEncoding = "json"
+	// EncodingProtobuf publishes the event envelope as a binary
+	// google.protobuf.Struct
+	EncodingProtobuf EventEncoding = "protobuf"
+)
+
+// ParseEventEncoding parses "json" or "protobuf"
+func ParseEventEncoding(s string) (EventEncoding, error) {
+	switch encoding := EventEncoding(s); encoding {
+	case EncodingJSON, EncodingProtobuf:
+		return encoding, nil
+	default:
+		return "", fmt.Errorf("unknown event encoding %q, want json or protobuf", s)
+	}
+}
+
+// ContentType returns the MIME type of the payloads
+func (e EventEncoding) ContentType() string {
+	if e == EncodingProtobuf {
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+// eventEnvelope is the published form of a domain event; Data holds the
+// fields of the event, with snake_case keys
+type eventEnvelope struct {
+	EventType   string         `json:"event_type"`
+	AggregateID string         `json:"aggregate_id"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	Data        map[string]any `json:"data"`
+}
+
+// EncodeEvent returns the payload of event in encoding, for the brokers
+// events are published to
+func EncodeEvent(event domain.DomainEvent, encoding EventEncoding) ([]byte, error) {
+	fields, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %s: %w", event.EventType(), err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(fields, &data); err != nil {
+		return nil, fmt.Errorf("reading %s fields: %w", event.EventType(), err)
+	}
+
+	envelope := eventEnvelope{
+		EventType:   event.EventType(),
+		AggregateID: event.AggregateID(),
+		OccurredAt:  event.OccurredAt().UTC(),
+		Data:        make(map[string]any, len(data)),
+	}
+	for name, value := range data {
+		envelope.Data[SnakeCase(name)] = value
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil || encoding != EncodingProtobuf {
+		return payload, err
+	}
+
+	// Struct values are JSON values, so the envelope goes through JSON
+	var values map[string]any
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+	message, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, fmt.Errorf("converting %s to protobuf: %w", event.EventType(), err)
+	}
+	return proto.Marshal(message)
+}
+
+// SnakeCase converts a Go name such as CanonicalID to canonical_id
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previousLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestEncodeEvent(t *testing.T) {
+	id := domain.NewTodoID()
+	canonical := domain.NewTodoID()
+	event := domain.NewTodoMergedEvent(id, canonical)
+
+	for _, encoding := range []EventEncoding{EncodingJSON, EncodingProtobuf} {
+		t.Run(string(encoding), func(t *testing.T) {
+			payload, err := EncodeEvent(event, encoding)
+			if err != nil {
+				t.Fatalf("EncodeEvent() unexpected error: %v", err)
+			}
+
+			var envelope map[string]any
+			if encoding == EncodingProtobuf {
+				var message structpb.Struct
+				if err := proto.Unmarshal(payload, &message); err != nil {
+					t.Fatalf("decoding protobuf payload: %v", err)
+				}
+				envelope = message.AsMap()
+			} else if err := json.Unmarshal(payload, &envelope); err != nil {
+				t.Fatalf("decoding JSON payload: %v", err)
+			}
+
+			if envelope["event_type"] != "TodoMerged" || envelope["aggregate_id"] != id.String() {
+				t.Errorf("envelope = %v, want the TodoMerged event of %s", envelope, id)
+			}
+			if _, err := time.Parse(time.RFC3339Nano, envelope["occurred_at"].(string)); err != nil {
+				t.Errorf("occurred_at = %v, want an RFC 3339 time", envelope["occurred_at"])
+			}
+			data, _ := envelope["data"].(map[string]any)
+			if data["canonical_id"] != canonical.String() {
+				t.Errorf("data = %v, want canonical_id %s", data, canonical)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Title":          "title",
+		"DueDate":        "due_date",
+		"CanonicalID":    "canonical_id",
+		"PreviousStatus": "previous_status",
+		"HTTPStatus":     "http_status",
+	}
+	for name, want := range tests {
+		if got := SnakeCase(name); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseEventEncoding(t *testing.T) {
+	if _, err := ParseEventEncoding("avro"); err == nil {
+		t.Error("ParseEventEncoding(avro) succeeded, want an error")
+	}
+	if encoding, err := ParseEventEncoding("protobuf"); err != nil || encoding != EncodingProtobuf {
+		t.Errorf("ParseEventEncoding(protobuf) = %q, %v", encoding, err)
+	}
+}
@@ -0,0 +1,202 @@
+// Package kafka publishes domain events to Kafka
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// DefaultTopic is the topic of the events without a topic of their own
+const DefaultTopic = "todo-events"
+
+// Options controls the publication of events to Kafka
+type Options struct {
+	// Brokers are the host:port addresses used to bootstrap the client
+	Brokers []string
+	// Topic receives the events whose type is not in Topics
+	Topic string
+	// Topics maps event types, such as TodoCreated, to their own topic
+	Topics map[string]string
+	// Encoding is the format of the message values
+	Encoding events.EventEncoding
+	// Acks is the number of acknowledgements a write waits for
+	Acks kafkago.RequiredAcks
+	// MaxAttempts is the number of tries of a write before it fails
+	MaxAttempts int
+	// WriteTimeout bounds each try
+	WriteTimeout time.Duration
+}
+
+// DefaultOptions publishes JSON events to todo-events, acknowledged by
+// all in-sync replicas
+func DefaultOptions() Options {
+	return Options{
+		Topic:        DefaultTopic,
+		Encoding:     events.EncodingJSON,
+		Acks:         kafkago.RequireAll,
+		MaxAttempts:  5,
+		WriteTimeout: 10 * time.Second,
+	}
+}
+
+// ParseTopics parses a comma-separated list of EventType=topic pairs
+func ParseTopics(s string) (map[string]string, error) {
+	topics := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		eventType, topic, ok := strings.Cut(pair, "=")
+		eventType, topic = strings.TrimSpace(eventType), strings.TrimSpace(topic)
+		if !ok || eventType == "" || topic == "" {
+			return nil, fmt.Errorf("invalid topic %q, want EventType=topic", pair)
+		}
+		topics[eventType] = topic
+	}
+	return topics, nil
+}
+
+// ParseAcks parses "all", "leader" or "none"
+func ParseAcks(s string) (kafkago.RequiredAcks, error) {
+	switch s {
+	case "all":
+		return kafkago.RequireAll, nil
+	case "leader":
+		return kafkago.RequireOne, nil
+	case "none":
+		return kafkago.RequireNone, nil
+	default:
+		return 0, fmt.Errorf("unknown acks %q, want all, leader or none", s)
+	}
+}
+
+// messageWriter is the part of the kafka-go Writer used by the dispatcher
+type messageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafkago.Message) error
+	Close() error
+}
+
+// EventDispatcher publishes domain events to Kafka
+// Messages are keyed by aggregate ID, so the events of a todo keep their
+// order within a partition; their event_type and content-type headers let
+// consumers filter them without decoding the value
+type EventDispatcher struct {
+	writer  messageWriter
+	options Options
+}
+
+// NewEventDispatcher creates a EventDispatcher; the connections
+// are opened on the first dispatch
+func NewEventDispatcher(options Options) (*EventDispatcher, error) {
+	if len(options.Brokers) == 0 {
+		return nil, fmt.Errorf("no Kafka brokers")
+	}
+
+	writer := &kafkago.Writer{
+		Addr:         kafkago.TCP(options.Brokers...),
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: options.Acks,
+		MaxAttempts:  options.MaxAttempts,
+		WriteTimeout: options.WriteTimeout,
+		// Dispatch is synchronous, so batches are not held back to fill up
+		BatchTimeout: 10 * time.Millisecond,
+	}
+	return newEventDispatcher(writer, options), nil
+}
+
+// newEventDispatcher creates a EventDispatcher writing to writer
+func newEventDispatcher(writer messageWriter, options Options) *EventDispatcher {
+	if options.Topic == "" {
+		options.Topic = DefaultTopic
+	}
+	if options.Encoding == "" {
+		options.Encoding = events.EncodingJSON
+	}
+	return &EventDispatcher{writer: writer, options: options}
+}
+
+// Dispatch publishes events in one write, retried up to MaxAttempts times
+// An event that cannot be encoded fails the whole dispatch, before anything
+// is written
+func (d *EventDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	messages := make([]kafkago.Message, len(events))
+	for i, event := range events {
+		message, err := d.message(event)
+		if err != nil {
+			return err
+		}
+		messages[i] = message
+	}
+
+	if err := d.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("publishing %d events to Kafka: %w", len(messages), err)
+	}
+	return nil
+}
+
+// message returns the message of event
+func (d *EventDispatcher) message(event domain.DomainEvent) (kafkago.Message, error) {
+	value, err := events.EncodeEvent(event, d.options.Encoding)
+	if err != nil {
+		return kafkago.Message{}, fmt.Errorf("encoding event: %w", err)
+	}
+
+	message := kafkago.Message{
+		Topic: d.topic(event.EventType()),
+		Value: value,
+		Headers: []kafkago.Header{
+			{Key: "event_type", Value: []byte(event.EventType())},
+			{Key: "content-type", Value: []byte(d.options.Encoding.ContentType())},
+		},
+	}
+	// Events without an aggregate are spread over the partitions
+	if id := event.AggregateID(); id != "" {
+		message.Key = []byte(id)
+	}
+	return message, nil
+}
+
+// topic returns the topic of the events of eventType
+func (d *EventDispatcher) topic(eventType string) string {
+	if topic, ok := d.options.Topics[eventType]; ok {
+		return topic
+	}
+	return d.options.Topic
+}
+
+// Ping connects to the first reachable broker and checks that it knows the
+// default topic
+func (d *EventDispatcher) Ping(ctx context.Context) error {
+	var errs []error
+	for _, broker := range d.options.Brokers {
+		conn, err := kafkago.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defer conn.Close()
+
+		if _, err := conn.ReadPartitions(d.options.Topic); err != nil {
+			return fmt.Errorf("reading partitions of %s: %w", d.options.Topic, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no Kafka broker reachable: %w", errors.Join(errs...))
+}
+
+// Close flushes the pending writes and closes the connections
+func (d *EventDispatcher) Close() error {
+	return d.writer.Close()
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeMessageWriter records the messages written, or fails with Err
+type fakeMessageWriter struct {
+	Messages []kafkago.Message
+	Err      error
+}
+
+func (w *fakeMessageWriter) WriteMessages(ctx context.Context, messages ...kafkago.Message) error {
+	if w.Err != nil {
+		return w.Err
+	}
+	w.Messages = append(w.Messages, messages...)
+	return nil
+}
+
+func (w *fakeMessageWriter) Close() error {
+	return nil
+}
+
+func TestKafkaEventDispatcher_Dispatch(t *testing.T) {
+	writer := &fakeMessageWriter{}
+	dispatcher := newEventDispatcher(writer, Options{
+		Topics: map[string]string{"TodoDeleted": "todo-deletions"},
+	})
+
+	id := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Test Todo")
+	events := []domain.DomainEvent{
+		domain.NewTodoCreatedEvent(id, title, "Description", domain.PriorityMedium, nil),
+		domain.NewTodoDeletedEvent(id, ""),
+	}
+	if err := dispatcher.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	if len(writer.Messages) != 2 {
+		t.Fatalf("wrote %d messages, want 2", len(writer.Messages))
+	}
+	for i, want := range []string{DefaultTopic, "todo-deletions"} {
+		message := writer.Messages[i]
+		if message.Topic != want {
+			t.Errorf("message %d topic = %q, want %q", i, message.Topic, want)
+		}
+		if string(message.Key) != id.String() {
+			t.Errorf("message %d key = %q, want the aggregate ID", i, message.Key)
+		}
+		if len(message.Headers) == 0 || string(message.Headers[0].Value) != events[i].EventType() {
+			t.Errorf("message %d headers = %v, want the event type", i, message.Headers)
+		}
+	}
+}
+
+func TestKafkaEventDispatcher_Dispatch_WriteFailure(t *testing.T) {
+	dispatcher := newEventDispatcher(&fakeMessageWriter{Err: errors.New("broker down")}, Options{})
+
+	err := dispatcher.Dispatch(context.Background(), []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")})
+	if err == nil {
+		t.Error("Dispatch() succeeded, want the write error")
+	}
+}
+
+func TestKafkaEventDispatcher_Ping_Unreachable(t *testing.T) {
+	dispatcher := newEventDispatcher(&fakeMessageWriter{}, Options{Brokers: []string{"127.0.0.1:1"}})
+
+	if err := dispatcher.Ping(context.Background()); err == nil {
+		t.Error("Ping() succeeded, want the dial error")
+	}
+}
+
+func TestParseKafkaTopics(t *testing.T) {
+	topics, err := ParseTopics("TodoCreated=todo-created, TodoDeleted = todo-deleted,")
+	if err != nil {
+		t.Fatalf("ParseTopics() unexpected error: %v", err)
+	}
+	if len(topics) != 2 || topics["TodoDeleted"] != "todo-deleted" {
+		t.Errorf("ParseTopics() = %v", topics)
+	}
+
+	if _, err := ParseTopics("TodoCreated"); err == nil {
+		t.Error("ParseTopics() without a topic succeeded, want an error")
+	}
+}
+
+func TestParseKafkaAcks(t *testing.T) {
+	tests := map[string]kafkago.RequiredAcks{
+		"all":    kafkago.RequireAll,
+		"leader": kafkago.RequireOne,
+		"none":   kafkago.RequireNone,
+	}
+	for s, want := range tests {
+		if got, err := ParseAcks(s); err != nil || got != want {
+			t.Errorf("ParseAcks(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseAcks("some"); err == nil {
+		t.Error("ParseAcks(some) succeeded, want an error")
+	}
+}
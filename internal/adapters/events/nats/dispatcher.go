@@ -0,0 +1,208 @@
+// Package nats publishes domain events to NATS JetStream
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	natsio "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// Options controls the connection to NATS and the publication of events
+type Options struct {
+	// URL is a comma-separated list of NATS server URLs
+	URL string
+	// Stream is the JetStream stream capturing the subjects of the events
+	Stream string
+	// SubjectPrefix starts the subject of every event, e.g. todo.created
+	SubjectPrefix string
+	// CreateStream creates Stream, capturing SubjectPrefix.>, when missing
+	CreateStream bool
+	// Encoding is the format of the message payloads
+	Encoding events.EventEncoding
+	// AckTimeout bounds the wait for the acknowledgement of each event
+	AckTimeout time.Duration
+	// ReconnectWait is the delay between two reconnection attempts, which
+	// go on until the dispatcher is closed
+	ReconnectWait time.Duration
+}
+
+// DefaultOptions publishes JSON events to the TODO_EVENTS stream of a local
+// server
+func DefaultOptions() Options {
+	return Options{
+		URL:           natsio.DefaultURL,
+		Stream:        "TODO_EVENTS",
+		SubjectPrefix: "todo",
+		Encoding:      events.EncodingJSON,
+		AckTimeout:    5 * time.Second,
+		ReconnectWait: 2 * time.Second,
+	}
+}
+
+// publisher is the part of jetstream.JetStream used by the dispatcher
+type publisher interface {
+	PublishMsg(ctx context.Context, msg *natsio.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// EventDispatcher publishes domain events to JetStream subjects named after
+// their type, such as todo.created or todo.due_soon
+// Each event is acknowledged by the stream before the next one is sent, so
+// the events of a dispatch are stored in order; its Nats-Msg-Id lets the
+// stream drop the copies of a retried publication
+type EventDispatcher struct {
+	conn      *natsio.Conn
+	publisher publisher
+	options   Options
+}
+
+// NewEventDispatcher connects to NATS, and creates the stream if asked to
+// A server that cannot be reached yet is retried in the background, so the
+// service starts and dispatches fail until it is reached
+func NewEventDispatcher(ctx context.Context, options Options, logger *slog.Logger) (*EventDispatcher, error) {
+	conn, err := natsio.Connect(options.URL,
+		natsio.Name("todo-service"),
+		natsio.RetryOnFailedConnect(true),
+		natsio.MaxReconnects(-1),
+		natsio.ReconnectWait(options.ReconnectWait),
+		natsio.DisconnectErrHandler(func(conn *natsio.Conn, err error) {
+			if err != nil {
+				logger.Warn("NATS connection lost", "error", err)
+			}
+		}),
+		natsio.ReconnectHandler(func(conn *natsio.Conn) {
+			logger.Info("NATS connection restored", "server", conn.ConnectedUrlRedacted())
+		}),
+		natsio.ClosedHandler(func(conn *natsio.Conn) {
+			logger.Info("NATS connection closed")
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating JetStream context: %w", err)
+	}
+
+	if options.CreateStream {
+		if !conn.IsConnected() {
+			logger.Warn("NATS unreachable, stream not checked", "stream", options.Stream)
+		} else if err := ensureStream(ctx, js, options); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return newEventDispatcher(conn, js, options), nil
+}
+
+// newEventDispatcher creates an EventDispatcher publishing with publisher
+func newEventDispatcher(conn *natsio.Conn, publisher publisher, options Options) *EventDispatcher {
+	if options.SubjectPrefix == "" {
+		options.SubjectPrefix = DefaultOptions().SubjectPrefix
+	}
+	if options.Encoding == "" {
+		options.Encoding = events.EncodingJSON
+	}
+	return &EventDispatcher{conn: conn, publisher: publisher, options: options}
+}
+
+// ensureStream creates the stream of the events unless it exists
+func ensureStream(ctx context.Context, js jetstream.JetStream, options Options) error {
+	_, err := js.Stream(ctx, options.Stream)
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		if err != nil {
+			return fmt.Errorf("looking up stream %s: %w", options.Stream, err)
+		}
+		return nil
+	}
+
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     options.Stream,
+		Subjects: []string{options.SubjectPrefix + ".>"},
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("creating stream %s: %w", options.Stream, err)
+	}
+	return nil
+}
+
+// Dispatch publishes events, waiting up to AckTimeout for the
+// acknowledgement of each
+// The events published before a failure stay published
+func (d *EventDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	for _, event := range events {
+		if err := d.publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish publishes event and waits for its acknowledgement
+func (d *EventDispatcher) publish(ctx context.Context, event domain.DomainEvent) error {
+	data, err := events.EncodeEvent(event, d.options.Encoding)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	msg := natsio.NewMsg(Subject(d.options.SubjectPrefix, event.EventType()))
+	msg.Data = data
+	msg.Header.Set("Event-Type", event.EventType())
+	msg.Header.Set("Content-Type", d.options.Encoding.ContentType())
+
+	opts := []jetstream.PublishOpt{jetstream.WithMsgID(messageID(event))}
+	if d.options.Stream != "" {
+		opts = append(opts, jetstream.WithExpectStream(d.options.Stream))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.options.AckTimeout)
+	defer cancel()
+	if _, err := d.publisher.PublishMsg(ctx, msg, opts...); err != nil {
+		return fmt.Errorf("publishing %s to %s: %w", event.EventType(), msg.Subject, err)
+	}
+	return nil
+}
+
+// Ping makes a round trip to the server
+func (d *EventDispatcher) Ping(ctx context.Context) error {
+	if d.conn == nil || !d.conn.IsConnected() {
+		return errors.New("not connected to NATS")
+	}
+	if err := d.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("pinging NATS: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection; every dispatched event was acknowledged, so
+// none is left to flush
+func (d *EventDispatcher) Close() error {
+	if d.conn != nil {
+		d.conn.Close()
+	}
+	return nil
+}
+
+// Subject returns the subject of the events of eventType: prefix followed by
+// the type in snake_case, without its Todo prefix
+func Subject(prefix, eventType string) string {
+	return prefix + "." + events.SnakeCase(strings.TrimPrefix(eventType, "Todo"))
+}
+
+// messageID identifies event for the deduplication of the stream
+func messageID(event domain.DomainEvent) string {
+	return fmt.Sprintf("%s-%s-%d", event.EventType(), event.AggregateID(), event.OccurredAt().UnixNano())
+}
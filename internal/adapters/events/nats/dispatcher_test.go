@@ -0,0 +1,94 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	natsio "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakePublisher records the messages published, or fails with Err
+type fakePublisher struct {
+	Messages []*natsio.Msg
+	Err      error
+}
+
+func (p *fakePublisher) PublishMsg(ctx context.Context, msg *natsio.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	p.Messages = append(p.Messages, msg)
+	return &jetstream.PubAck{Stream: "TODO_EVENTS", Sequence: uint64(len(p.Messages))}, nil
+}
+
+func TestSubject(t *testing.T) {
+	tests := map[string]string{
+		"TodoCreated":             "todo.created",
+		"TodoCompleted":           "todo.completed",
+		"TodoDueSoon":             "todo.due_soon",
+		"SecurityAnomalyDetected": "todo.security_anomaly_detected",
+	}
+	for eventType, want := range tests {
+		if got := Subject("todo", eventType); got != want {
+			t.Errorf("Subject(%q) = %q, want %q", eventType, got, want)
+		}
+	}
+}
+
+func TestEventDispatcher_Dispatch(t *testing.T) {
+	publisher := &fakePublisher{}
+	dispatcher := newEventDispatcher(nil, publisher, DefaultOptions())
+
+	id := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Test Todo")
+	events := []domain.DomainEvent{
+		domain.NewTodoCreatedEvent(id, title, "Description", domain.PriorityMedium, nil),
+		domain.NewTodoDeletedEvent(id, ""),
+	}
+	if err := dispatcher.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	if len(publisher.Messages) != 2 {
+		t.Fatalf("published %d messages, want 2", len(publisher.Messages))
+	}
+	for i, want := range []string{"todo.created", "todo.deleted"} {
+		msg := publisher.Messages[i]
+		if msg.Subject != want {
+			t.Errorf("message %d subject = %q, want %q", i, msg.Subject, want)
+		}
+		if msg.Header.Get("Event-Type") != events[i].EventType() || len(msg.Data) == 0 {
+			t.Errorf("message %d = %v, want the encoded %s", i, msg.Header, events[i].EventType())
+		}
+	}
+
+	if err := dispatcher.Close(); err != nil {
+		t.Errorf("Close() unexpected error: %v", err)
+	}
+}
+
+func TestEventDispatcher_Dispatch_NotAcknowledged(t *testing.T) {
+	publisher := &fakePublisher{Err: jetstream.ErrNoStreamResponse}
+	dispatcher := newEventDispatcher(nil, publisher, DefaultOptions())
+
+	err := dispatcher.Dispatch(context.Background(), []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")})
+	if !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		t.Errorf("Dispatch() error = %v, want the publication error", err)
+	}
+}
+
+func TestMessageID_IdentifiesEvents(t *testing.T) {
+	id := domain.NewTodoID()
+	deleted := domain.NewTodoDeletedEvent(id, "")
+
+	if messageID(deleted) != messageID(deleted) {
+		t.Error("messageID() differs for the same event")
+	}
+	if messageID(deleted) == messageID(domain.NewTodoUnarchivedEvent(id)) {
+		t.Error("messageID() is the same for different events")
+	}
+}
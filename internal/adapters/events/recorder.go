@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// RecordingDispatcher is an event dispatcher keeping every event dispatched,
+// for tests and examples to check what the application service published
+// It is safe for concurrent use
+type RecordingDispatcher struct {
+	mu     sync.Mutex
+	events []domain.DomainEvent
+	err    error
+}
+
+// NewRecordingDispatcher creates a new, empty RecordingDispatcher
+func NewRecordingDispatcher() *RecordingDispatcher {
+	return &RecordingDispatcher{}
+}
+
+// Dispatch records events, or fails with the error set by FailWith without
+// recording them
+func (d *RecordingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return d.err
+	}
+	d.events = append(d.events, events...)
+
+	return nil
+}
+
+// FailWith makes the next dispatches fail with err, until it is called
+// again with nil
+func (d *RecordingDispatcher) FailWith(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.err = err
+}
+
+// Events returns the events dispatched so far, in dispatch order
+func (d *RecordingDispatcher) Events() []domain.DomainEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.events)
+}
+
+// EventTypes returns the type of the events dispatched so far, in dispatch
+// order
+func (d *RecordingDispatcher) EventTypes() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	types := make([]string, len(d.events))
+	for i, event := range d.events {
+		types[i] = event.EventType()
+	}
+	return types
+}
+
+// Reset forgets the events dispatched so far
+func (d *RecordingDispatcher) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.events = nil
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestRecordingDispatcher(t *testing.T) {
+	dispatcher := NewRecordingDispatcher()
+	ctx := context.Background()
+
+	todoID := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Test Todo")
+	created := domain.NewTodoCreatedEvent(todoID, title, "Description", domain.PriorityMedium, nil)
+	updated := domain.NewTodoUpdatedEvent(todoID)
+
+	if err := dispatcher.Dispatch(ctx, []domain.DomainEvent{created, updated}); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+	if types := dispatcher.EventTypes(); len(types) != 2 || types[0] != created.EventType() || types[1] != updated.EventType() {
+		t.Errorf("EventTypes() = %v, want the created then the updated event", types)
+	}
+
+	// Failed dispatches are not recorded
+	failure := errors.New("broker down")
+	dispatcher.FailWith(failure)
+	if err := dispatcher.Dispatch(ctx, []domain.DomainEvent{updated}); !errors.Is(err, failure) {
+		t.Errorf("Dispatch() error = %v, want %v", err, failure)
+	}
+	if events := dispatcher.Events(); len(events) != 2 {
+		t.Errorf("Events() = %d events after a failed dispatch, want 2", len(events))
+	}
+
+	dispatcher.FailWith(nil)
+	dispatcher.Reset()
+	if events := dispatcher.Events(); len(events) != 0 {
+		t.Errorf("Events() = %d events after Reset(), want 0", len(events))
+	}
+}
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// JSONSchemaDialect is the JSON Schema version of the event schemas
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// timeType is the type of the fields encoded as RFC 3339 strings
+var timeType = reflect.TypeFor[time.Time]()
+
+// EventSchema returns the JSON Schema of the payloads EncodeEvent writes for
+// the events of the type of sample, derived from its fields
+// Data fields are required, but for the replayed events backfilling a
+// webhook endpoint, which only carry the fields recorded and
+// "replayed": true
+func EventSchema(sample domain.DomainEvent) map[string]any {
+	properties := map[string]any{
+		"replayed": map[string]any{"const": true},
+	}
+	typ := reflect.TypeOf(sample)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	var required []string
+	for _, field := range jsonFields(typ) {
+		name := SnakeCase(field.name)
+		properties[name] = field.schema
+		if field.required {
+			required = append(required, name)
+		}
+	}
+
+	data := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		data["if"] = map[string]any{
+			"properties": map[string]any{"replayed": map[string]any{"const": true}},
+			"required":   []string{"replayed"},
+		}
+		data["else"] = map[string]any{"required": required}
+	}
+
+	return EnvelopeSchema(sample.EventType(), data)
+}
+
+// EnvelopeSchema returns the JSON Schema of the envelopes of eventType whose
+// data match the data schema
+func EnvelopeSchema(eventType string, data map[string]any) map[string]any {
+	return map[string]any{
+		"$schema":  JSONSchemaDialect,
+		"title":    eventType,
+		"type":     "object",
+		"required": []string{"event_type", "aggregate_id", "occurred_at", "data"},
+		"properties": map[string]any{
+			"event_type":   map[string]any{"const": eventType},
+			"aggregate_id": map[string]any{"type": "string"},
+			"occurred_at":  map[string]any{"type": "string", "format": "date-time"},
+			"data":         data,
+		},
+	}
+}
+
+// jsonField is a field of a struct as encoding/json writes it
+type jsonField struct {
+	name     string
+	schema   map[string]any
+	required bool
+}
+
+// jsonFields returns the fields encoding/json writes for the structs of typ,
+// embedded structs without tag promoted
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || len(field.Index) > 1 && !promoted(typ, field) {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		schema, nullable := typeSchema(field.Type)
+		fields = append(fields, jsonField{
+			name:     name,
+			schema:   schema,
+			required: !nullable && !strings.Contains(options, "omitempty"),
+		})
+	}
+	return fields
+}
+
+// promoted reports whether field, of a struct embedded in typ, is written
+// by encoding/json: its embedding structs are untagged
+func promoted(typ reflect.Type, field reflect.StructField) bool {
+	for _, i := range field.Index[:len(field.Index)-1] {
+		embedded := typ.Field(i)
+		if !embedded.Anonymous || embedded.Tag.Get("json") != "" {
+			return false
+		}
+		typ = embedded.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+	return true
+}
+
+// typeSchema returns the JSON Schema of the values of typ, and whether they
+// may be null
+func typeSchema(typ reflect.Type) (map[string]any, bool) {
+	if typ == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}, false
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		schema, _ := typeSchema(typ.Elem())
+		return nullable(schema), true
+	case reflect.String:
+		return map[string]any{"type": "string"}, false
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, false
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, false
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return nullable(map[string]any{"type": "string", "contentEncoding": "base64"}), true
+		}
+		items, _ := typeSchema(typ.Elem())
+		return nullable(map[string]any{"type": "array", "items": items}), true
+	case reflect.Array:
+		items, _ := typeSchema(typ.Elem())
+		return map[string]any{"type": "array", "items": items}, false
+	case reflect.Map:
+		values, _ := typeSchema(typ.Elem())
+		return nullable(map[string]any{"type": "object", "additionalProperties": values}), true
+	case reflect.Struct:
+		properties := make(map[string]any)
+		var required []string
+		for _, field := range jsonFields(typ) {
+			properties[field.name] = field.schema
+			if field.required {
+				required = append(required, field.name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema, false
+	default:
+		// Interfaces hold any JSON value
+		return map[string]any{}, true
+	}
+}
+
+// nullable returns schema accepting null too
+func nullable(schema map[string]any) map[string]any {
+	typ, ok := schema["type"].(string)
+	if !ok {
+		return schema
+	}
+	schema["type"] = []string{typ, "null"}
+	return schema
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// checkValue checks value against the type, const, properties, required and
+// items keywords of schema, the ones EventSchema writes
+func checkValue(t *testing.T, path string, schema map[string]any, value any) {
+	t.Helper()
+
+	if want, ok := schema["const"]; ok && value != want {
+		t.Errorf("%s = %v, want %v", path, value, want)
+	}
+
+	var types []string
+	switch typ := schema["type"].(type) {
+	case string:
+		types = []string{typ}
+	case []string:
+		types = typ
+	}
+	if len(types) > 0 && !slices.Contains(types, jsonType(value)) {
+		t.Errorf("%s is %s, want %v", path, jsonType(value), types)
+		return
+	}
+
+	object, _ := value.(map[string]any)
+	required, _ := schema["required"].([]string)
+	if elseSchema, ok := schema["else"].(map[string]any); ok && object["replayed"] != true {
+		required = append(required, elseSchema["required"].([]string)...)
+	}
+	for _, name := range required {
+		if _, ok := object[name]; !ok {
+			t.Errorf("%s.%s is missing", path, name)
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		for name, field := range object {
+			fieldSchema, ok := properties[name].(map[string]any)
+			if !ok {
+				t.Errorf("%s.%s is not in the schema", path, name)
+				continue
+			}
+			checkValue(t, path+"."+name, fieldSchema, field)
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		array, _ := value.([]any)
+		for _, item := range array {
+			checkValue(t, path+"[]", items, item)
+		}
+	}
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestEventSchema_MatchesEncoding(t *testing.T) {
+	id := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Buy milk")
+	dueDate, _ := domain.NewDueDate(time.Now().Add(time.Hour))
+	title2 := "Buy oat milk"
+	updated := domain.NewTodoUpdatedEvent(id)
+	updated.Title = &title2
+
+	tests := []domain.DomainEvent{
+		domain.NewTodoCreatedEvent(id, title, "", domain.PriorityHigh, &dueDate),
+		domain.NewTodoCreatedEvent(id, title, "", domain.PriorityHigh, nil),
+		updated,
+		domain.NewTodoCompletedEvent(id, time.Now()),
+		domain.NewTodoForceUpdatedEvent(id, "admin", "support request", []string{"title"}),
+		domain.NewTodoForceUpdatedEvent(id, "admin", "support request", nil),
+		domain.NewTodoMergedEvent(id, domain.NewTodoID()),
+		domain.NewTodoDeletedEvent(id, ""),
+		domain.NewTodoOverdueEvent(id, dueDate),
+	}
+
+	for _, event := range tests {
+		t.Run(event.EventType(), func(t *testing.T) {
+			payload, err := EncodeEvent(event, EncodingJSON)
+			if err != nil {
+				t.Fatalf("EncodeEvent() unexpected error: %v", err)
+			}
+			var envelope map[string]any
+			if err := json.Unmarshal(payload, &envelope); err != nil {
+				t.Fatalf("decoding payload: %v", err)
+			}
+
+			checkValue(t, "payload", EventSchema(event), envelope)
+		})
+	}
+}
+
+func TestEventSchema_Fields(t *testing.T) {
+	schema := EventSchema(domain.TodoCreated{})
+	data := schema["properties"].(map[string]any)["data"].(map[string]any)
+	properties := data["properties"].(map[string]any)
+
+	for _, name := range []string{"title", "description", "priority", "due_date", "replayed"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("data.%s is not in the schema", name)
+		}
+	}
+	if required := data["else"].(map[string]any)["required"]; !slices.Equal(required.([]string), []string{"title", "description", "priority"}) {
+		t.Errorf("required = %v, want the non-nullable fields", required)
+	}
+	if dueDate := properties["due_date"].(map[string]any); !slices.Equal(dueDate["type"].([]string), []string{"string", "null"}) || dueDate["format"] != "date-time" {
+		t.Errorf("due_date = %v, want a nullable date-time", dueDate)
+	}
+}
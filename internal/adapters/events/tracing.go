@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"strings"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// TracingDispatcher times the dispatches of traced requests, for slow
+// request dumps
+type TracingDispatcher struct {
+	next ports.EventDispatcher
+}
+
+// NewTracingDispatcher creates a TracingDispatcher dispatching to next
+func NewTracingDispatcher(next ports.EventDispatcher) *TracingDispatcher {
+	return &TracingDispatcher{next: next}
+}
+
+// Dispatch dispatches events to the next dispatcher and records the time it
+// took, described by the event types
+func (d *TracingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	if reqtrace.FromContext(ctx) == nil {
+		return d.next.Dispatch(ctx, events)
+	}
+
+	types := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.EventType()
+	}
+	defer reqtrace.Start(ctx, reqtrace.StageDispatch, strings.Join(types, ","))()
+
+	return d.next.Dispatch(ctx, events)
+}
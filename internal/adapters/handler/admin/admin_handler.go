@@ -0,0 +1,173 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Handler serves the operator-facing administration API over plain HTTP/JSON
+// Every route requires the configured admin bearer token
+type Handler struct {
+	token       string
+	logger      *slog.Logger
+	maintenance *application.MaintenanceMode
+	todos       TodoAdministration
+	hooks       InboundHookAdministration
+	webhooks    WebhookAdministration
+	legalHolds  LegalHoldAdministration
+	calendar    CalendarAdministration
+	reports     ComplianceReporting
+	canaries    CanaryAdministration
+	status      StatusReporting
+	slow        SlowRequestReporting
+	config      ConfigReloading
+}
+
+// Option configures the features exposed by the admin Handler
+type Option func(*Handler)
+
+// WithMaintenanceMode exposes the maintenance mode switch
+func WithMaintenanceMode(maintenance *application.MaintenanceMode) Option {
+	return func(h *Handler) {
+		h.maintenance = maintenance
+	}
+}
+
+// NewHandler creates a new admin Handler protected by token
+func NewHandler(token string, logger *slog.Logger, opts ...Option) *Handler {
+	h := &Handler{
+		token:  token,
+		logger: logger,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// RegisterRoutes registers the admin routes of the configured features on mux
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	if h.maintenance != nil {
+		mux.Handle("GET /admin/maintenance", h.authorize(h.getMaintenance))
+		mux.Handle("PUT /admin/maintenance", h.authorize(h.putMaintenance))
+	}
+
+	if h.todos != nil {
+		mux.Handle("POST /admin/todos/{id}/force-update", h.authorize(h.forceUpdateTodo))
+		mux.Handle("POST /admin/todos/purge/preview", h.authorize(h.previewPurge))
+		mux.Handle("POST /admin/todos/purge", h.authorize(h.purgeTodos))
+	}
+
+	if h.hooks != nil {
+		mux.Handle("POST /admin/hooks", h.authorize(h.createInboundHook))
+		mux.Handle("GET /admin/hooks", h.authorize(h.listInboundHooks))
+		mux.Handle("DELETE /admin/hooks/{id}", h.authorize(h.deleteInboundHook))
+	}
+
+	if h.webhooks != nil {
+		mux.Handle("POST /admin/webhooks", h.authorize(h.createWebhook))
+		mux.Handle("GET /admin/webhooks", h.authorize(h.listWebhooks))
+		mux.Handle("DELETE /admin/webhooks/{id}", h.authorize(h.deleteWebhook))
+		mux.Handle("GET /admin/webhooks/{id}/deliveries", h.authorize(h.listWebhookDeliveries))
+		mux.Handle("POST /admin/webhooks/{id}/deliveries/{delivery}/redeliver", h.authorize(h.redeliverWebhook))
+	}
+
+	if h.legalHolds != nil {
+		mux.Handle("GET /admin/legal-holds", h.authorize(h.listLegalHolds))
+		mux.Handle("PUT /admin/todos/{id}/legal-hold", h.authorize(h.placeLegalHold))
+		mux.Handle("POST /admin/todos/{id}/legal-hold/release", h.authorize(h.releaseLegalHold))
+	}
+
+	if h.calendar != nil {
+		mux.Handle("GET /admin/calendar", h.authorize(h.getCalendar))
+		mux.Handle("PUT /admin/calendar/holidays/{day}", h.authorize(h.putHoliday))
+		mux.Handle("DELETE /admin/calendar/holidays/{day}", h.authorize(h.deleteHoliday))
+	}
+
+	if h.canaries != nil {
+		mux.Handle("POST /admin/canaries", h.authorize(h.createCanary))
+		mux.Handle("GET /admin/canaries", h.authorize(h.listCanaries))
+		mux.Handle("DELETE /admin/canaries/{id}", h.authorize(h.deleteCanary))
+	}
+
+	if h.reports != nil {
+		mux.Handle("GET /admin/reports/compliance", h.authorize(h.getComplianceReport))
+	}
+
+	if h.status != nil {
+		mux.Handle("GET /admin/status", h.authorize(h.getSystemStatus))
+	}
+	if h.slow != nil {
+		mux.Handle("GET /admin/slow-requests", h.authorize(h.listSlowRequests))
+	}
+	if h.config != nil {
+		mux.Handle("POST /admin/config/reload", h.authorize(h.reloadConfig))
+	}
+}
+
+// authorize rejects requests that do not carry the admin bearer token and
+// lifts the owner scope of accepted requests
+func (h *Handler) authorize(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid admin token")
+			return
+		}
+
+		// Operators act on the todos of every user
+		next(w, r.WithContext(ports.ContextWithSystemAccess(r.Context())))
+	})
+}
+
+// maintenanceStatus is the JSON representation of the maintenance switch
+type maintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+// getMaintenance reports the current maintenance mode
+func (h *Handler) getMaintenance(w http.ResponseWriter, r *http.Request) {
+	enabled, message := h.maintenance.Status()
+	writeJSON(w, http.StatusOK, maintenanceStatus{Enabled: enabled, Message: message})
+}
+
+// putMaintenance turns maintenance mode on or off
+func (h *Handler) putMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req maintenanceStatus
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if req.Enabled {
+		h.maintenance.Enable(req.Message)
+	} else {
+		h.maintenance.Disable()
+	}
+
+	enabled, message := h.maintenance.Status()
+	h.logger.Warn("maintenance mode changed", "enabled", enabled, "message", message)
+
+	writeJSON(w, http.StatusOK, maintenanceStatus{Enabled: enabled, Message: message})
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mux := http.NewServeMux()
+	NewHandler(testToken, logger, opts...).RegisterRoutes(mux)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func doRequest(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("sending request: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	server := newTestServer(t, WithMaintenanceMode(application.NewMaintenanceMode(false, "")))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/maintenance", "wrong", "")
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_Maintenance_Toggle(t *testing.T) {
+	maintenance := application.NewMaintenanceMode(false, "")
+	server := newTestServer(t, WithMaintenanceMode(maintenance))
+
+	resp := doRequest(t, http.MethodPut, server.URL+"/admin/maintenance", testToken,
+		`{"enabled":true,"message":"migrating"}`)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var status maintenanceStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if !status.Enabled || status.Message != "migrating" {
+		t.Errorf("Response = %+v, want enabled with message", status)
+	}
+
+	if enabled, _ := maintenance.Status(); !enabled {
+		t.Error("Expected maintenance mode to be enabled")
+	}
+}
+
+func TestHandler_UnconfiguredFeature_NotRouted(t *testing.T) {
+	server := newTestServer(t)
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/maintenance", testToken, "")
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// CalendarAdministration is the part of the application service managing
+// the business calendar
+type CalendarAdministration interface {
+	GetBusinessCalendar(ctx context.Context) (*application.BusinessCalendarResponse, error)
+	SetHoliday(ctx context.Context, day string, req application.HolidayRequest) (*application.HolidayResponse, error)
+	DeleteHoliday(ctx context.Context, day string) error
+}
+
+// WithCalendar exposes the business calendar and the management of its
+// holidays
+func WithCalendar(calendar CalendarAdministration) Option {
+	return func(h *Handler) {
+		h.calendar = calendar
+	}
+}
+
+// holidayRequest is the JSON body naming a holiday
+type holidayRequest struct {
+	Name string `json:"name"`
+}
+
+// holiday is the JSON representation of a holiday
+type holiday struct {
+	Day  string `json:"day"`
+	Name string `json:"name"`
+}
+
+// businessCalendar is the JSON representation of the business calendar
+type businessCalendar struct {
+	WorkingDays []string  `json:"working_days"`
+	TimeZone    string    `json:"time_zone"`
+	Holidays    []holiday `json:"holidays"`
+}
+
+// getCalendar returns the working week and the holidays
+func (h *Handler) getCalendar(w http.ResponseWriter, r *http.Request) {
+	calendar, err := h.calendar.GetBusinessCalendar(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := businessCalendar{
+		WorkingDays: calendar.WorkingDays,
+		TimeZone:    calendar.TimeZone,
+		Holidays:    make([]holiday, len(calendar.Holidays)),
+	}
+	for i, day := range calendar.Holidays {
+		body.Holidays[i] = holiday(*day)
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// putHoliday makes a day a holiday, or renames its holiday
+func (h *Handler) putHoliday(w http.ResponseWriter, r *http.Request) {
+	var req holidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	saved, err := h.calendar.SetHoliday(r.Context(), r.PathValue("day"), application.HolidayRequest(req))
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("holiday set", "day", saved.Day, "name", saved.Name)
+	writeJSON(w, http.StatusOK, holiday(*saved))
+}
+
+// deleteHoliday makes a holiday an ordinary day again
+func (h *Handler) deleteHoliday(w http.ResponseWriter, r *http.Request) {
+	day := r.PathValue("day")
+	if err := h.calendar.DeleteHoliday(r.Context(), day); err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("holiday deleted", "day", day)
+	w.WriteHeader(http.StatusNoContent)
+}
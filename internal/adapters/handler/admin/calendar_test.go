@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeCalendar records the requests it receives
+type fakeCalendar struct {
+	gotDay string
+	gotReq application.HolidayRequest
+	err    error
+}
+
+func (f *fakeCalendar) GetBusinessCalendar(ctx context.Context) (*application.BusinessCalendarResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.BusinessCalendarResponse{
+		WorkingDays: []string{"Monday", "Tuesday"},
+		TimeZone:    "UTC",
+		Holidays:    []*application.HolidayResponse{{Day: "2026-12-25", Name: "Christmas"}},
+	}, nil
+}
+
+func (f *fakeCalendar) SetHoliday(ctx context.Context, day string, req application.HolidayRequest) (*application.HolidayResponse, error) {
+	f.gotDay, f.gotReq = day, req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.HolidayResponse{Day: day, Name: req.Name}, nil
+}
+
+func (f *fakeCalendar) DeleteHoliday(ctx context.Context, day string) error {
+	f.gotDay = day
+	return f.err
+}
+
+func TestHandler_GetCalendar(t *testing.T) {
+	server := newTestServer(t, WithCalendar(&fakeCalendar{}))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/calendar", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body businessCalendar
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.WorkingDays) != 2 || body.TimeZone != "UTC" || len(body.Holidays) != 1 || body.Holidays[0].Name != "Christmas" {
+		t.Errorf("Response = %+v, want the calendar", body)
+	}
+}
+
+func TestHandler_PutHoliday(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"set", `{"name":"Christmas"}`, nil, http.StatusOK},
+		{"invalid body", `{`, nil, http.StatusBadRequest},
+		{"invalid day", `{"name":"Christmas"}`, domain.NewValidationError("day", "must be a day as YYYY-MM-DD"), http.StatusBadRequest},
+		{"no store", `{"name":"Christmas"}`, application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := &fakeCalendar{err: tt.err}
+			server := newTestServer(t, WithCalendar(calendar))
+
+			resp := doRequest(t, http.MethodPut, server.URL+"/admin/calendar/holidays/2026-12-25", testToken, tt.body)
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusOK && (calendar.gotDay != "2026-12-25" || calendar.gotReq.Name != "Christmas") {
+				t.Errorf("SetHoliday() got %q %+v", calendar.gotDay, calendar.gotReq)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteHoliday(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not a holiday", application.ErrHolidayNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := &fakeCalendar{err: tt.err}
+			server := newTestServer(t, WithCalendar(calendar))
+
+			resp := doRequest(t, http.MethodDelete, server.URL+"/admin/calendar/holidays/2026-12-25", testToken, "")
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if calendar.gotDay != "2026-12-25" {
+				t.Errorf("DeleteHoliday() got %q, want 2026-12-25", calendar.gotDay)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// CanaryAdministration is the part of the application service managing
+// canary todos
+type CanaryAdministration interface {
+	CreateCanaryTodo(ctx context.Context, req application.CreateCanaryRequest) (*application.TodoResponse, error)
+	ListCanaryTodos(ctx context.Context) ([]*application.TodoResponse, error)
+	DeleteCanaryTodo(ctx context.Context, id string) error
+}
+
+// WithCanaries exposes the management of canary todos
+func WithCanaries(canaries CanaryAdministration) Option {
+	return func(h *Handler) {
+		h.canaries = canaries
+	}
+}
+
+// createCanaryRequest is the JSON body creating a canary todo
+type createCanaryRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// createCanary creates a canary todo
+func (h *Handler) createCanary(w http.ResponseWriter, r *http.Request) {
+	var body createCanaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	todo, err := h.canaries.CreateCanaryTodo(r.Context(), application.CreateCanaryRequest(body))
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Info("canary todo created", "todo_id", todo.ID)
+	writeJSON(w, http.StatusCreated, mapTodo(todo))
+}
+
+// listCanaries returns every canary todo
+func (h *Handler) listCanaries(w http.ResponseWriter, r *http.Request) {
+	todos, err := h.canaries.ListCanaryTodos(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := make([]todoResponse, len(todos))
+	for i, todo := range todos {
+		body[i] = mapTodo(todo)
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]todoResponse{"canaries": body})
+}
+
+// deleteCanary deletes a canary todo without raising an alert
+func (h *Handler) deleteCanary(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.canaries.DeleteCanaryTodo(r.Context(), id); err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Info("canary todo deleted", "todo_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
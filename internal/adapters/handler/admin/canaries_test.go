@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeCanaries records the requests it receives
+type fakeCanaries struct {
+	gotReq application.CreateCanaryRequest
+	gotID  string
+	err    error
+}
+
+func (f *fakeCanaries) CreateCanaryTodo(ctx context.Context, req application.CreateCanaryRequest) (*application.TodoResponse, error) {
+	f.gotReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.TodoResponse{ID: "canary-1", Title: req.Title, Status: "pending"}, nil
+}
+
+func (f *fakeCanaries) ListCanaryTodos(ctx context.Context) ([]*application.TodoResponse, error) {
+	return []*application.TodoResponse{{ID: "canary-1", Title: "Payroll credentials"}}, f.err
+}
+
+func (f *fakeCanaries) DeleteCanaryTodo(ctx context.Context, id string) error {
+	f.gotID = id
+	return f.err
+}
+
+func TestHandler_CreateCanary(t *testing.T) {
+	canaries := &fakeCanaries{}
+	server := newTestServer(t, WithCanaries(canaries))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/canaries", testToken,
+		`{"title":"Payroll credentials","description":"Decoy"}`)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if canaries.gotReq.Title != "Payroll credentials" || canaries.gotReq.Description != "Decoy" {
+		t.Errorf("CreateCanaryTodo() got %+v", canaries.gotReq)
+	}
+
+	var body todoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.ID != "canary-1" {
+		t.Errorf("ID = %q, want canary-1", body.ID)
+	}
+}
+
+func TestHandler_ListCanaries(t *testing.T) {
+	server := newTestServer(t, WithCanaries(&fakeCanaries{}))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/canaries", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var body map[string][]todoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body["canaries"]) != 1 {
+		t.Errorf("canaries = %+v, want one", body["canaries"])
+	}
+}
+
+func TestHandler_DeleteCanary(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not a canary", application.ErrCanaryNotFound, http.StatusNotFound},
+		{"not supported", application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canaries := &fakeCanaries{err: tt.err}
+			server := newTestServer(t, WithCanaries(canaries))
+
+			resp := doRequest(t, http.MethodDelete, server.URL+"/admin/canaries/canary-1", testToken, "")
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if canaries.gotID != "canary-1" {
+				t.Errorf("DeleteCanaryTodo() got %q", canaries.gotID)
+			}
+		})
+	}
+}
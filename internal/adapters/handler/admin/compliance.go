@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	"github.com/pivaldi/mmw/todo/internal/pkg/pdf"
+)
+
+// ComplianceReporting is the part of the application service compiling
+// compliance reports
+type ComplianceReporting interface {
+	GenerateComplianceReport(ctx context.Context, req application.ComplianceReportRequest) (*application.ComplianceReport, error)
+}
+
+// WithComplianceReports exposes the compliance report download
+func WithComplianceReports(reports ComplianceReporting) Option {
+	return func(h *Handler) {
+		h.reports = reports
+	}
+}
+
+// reportSignatureHeader carries the HMAC-SHA256 of the report body, keyed by
+// the admin token, so a downloaded report can be checked for tampering
+const reportSignatureHeader = "Report-Signature"
+
+// complianceColumns is the header row of the CSV compliance report
+var complianceColumns = []string{"occurred_at", "actor", "action", "todo_id", "reason", "changes"}
+
+// getComplianceReport renders the accesses and modifications of a period as a signed
+// CSV or PDF download
+func (h *Handler) getComplianceReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var req application.ComplianceReportRequest
+	for name, target := range map[string]*time.Time{"from": &req.From, "to": &req.To} {
+		value, err := time.Parse(time.RFC3339, query.Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+name+": "+err.Error())
+			return
+		}
+		*target = value
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "pdf" {
+		writeError(w, http.StatusBadRequest, "format must be csv or pdf")
+		return
+	}
+
+	report, err := h.reports.GenerateComplianceReport(r.Context(), req)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "pdf" {
+		contentType = "application/pdf"
+		_, err = renderCompliancePDF(report).WriteTo(&buf)
+	} else {
+		err = writeComplianceCSV(&buf, report)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "rendering report: "+err.Error())
+		return
+	}
+
+	h.logger.Info("compliance report generated",
+		"from", report.From, "to", report.To, "entries", len(report.Entries), "format", format)
+
+	mac := hmac.New(sha256.New, []byte(h.token))
+	mac.Write(buf.Bytes())
+
+	filename := fmt.Sprintf("compliance-%s-%s.%s",
+		report.From.UTC().Format("20060102T150405Z"), report.To.UTC().Format("20060102T150405Z"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set(reportSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// writeComplianceCSV writes one row per audit entry
+func writeComplianceCSV(buf *bytes.Buffer, report *application.ComplianceReport) error {
+	out := csv.NewWriter(buf)
+	if err := out.Write(complianceColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range report.Entries {
+		row := []string{
+			entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+			entry.Actor,
+			entry.Action,
+			entry.TodoID,
+			entry.Reason,
+			formatChanges(entry.Changes),
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// renderCompliancePDF lays the report out as one paragraph per audit entry
+func renderCompliancePDF(report *application.ComplianceReport) *pdf.Document {
+	title := "Compliance report"
+	out := pdf.New(title)
+	out.Text(title, 16, 0, true)
+	out.Text(fmt.Sprintf("%s to %s - generated %s - %d entries",
+		report.From.UTC().Format(time.RFC3339),
+		report.To.UTC().Format(time.RFC3339),
+		report.GeneratedAt.UTC().Format(time.RFC3339),
+		len(report.Entries),
+	), 9, 0, false)
+	out.Space(12)
+
+	if len(report.Entries) == 0 {
+		out.Text("No access or modification in this period.", 11, 0, false)
+	}
+
+	for _, entry := range report.Entries {
+		out.Text(entry.OccurredAt.UTC().Format(time.RFC3339)+" - "+entry.Actor+" - "+entry.Action, 10, 0, true)
+		out.Text("Todo "+entry.TodoID, 9, 29, false)
+		if entry.Reason != "" {
+			out.Text("Reason: "+entry.Reason, 9, 29, false)
+		}
+		if changes := formatChanges(entry.Changes); changes != "" {
+			out.Text("Changes: "+changes, 9, 29, false)
+		}
+		out.Space(6)
+	}
+
+	return out
+}
+
+// formatChanges renders changes as "key=value" pairs sorted by key
+func formatChanges(changes map[string]string) string {
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + changes[key]
+	}
+
+	return strings.Join(pairs, "; ")
+}
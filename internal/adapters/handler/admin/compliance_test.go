@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeComplianceReports records the period it is asked for
+type fakeComplianceReports struct {
+	gotReq application.ComplianceReportRequest
+	err    error
+}
+
+func (f *fakeComplianceReports) GenerateComplianceReport(
+	ctx context.Context,
+	req application.ComplianceReportRequest,
+) (*application.ComplianceReport, error) {
+	f.gotReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.ComplianceReport{
+		From:        req.From,
+		To:          req.To,
+		GeneratedAt: time.Now(),
+		Entries: []application.ComplianceEntry{{
+			OccurredAt: req.From.Add(time.Hour),
+			Actor:      "ops",
+			Action:     application.AuditActionForceUpdate,
+			TodoID:     "todo-1",
+			Reason:     "Fix, urgently",
+			Changes:    map[string]string{"status": "pending", "priority": "high"},
+		}},
+	}, nil
+}
+
+func TestHandler_GetComplianceReport_CSV(t *testing.T) {
+	reports := &fakeComplianceReports{}
+	server := newTestServer(t, WithComplianceReports(reports))
+
+	resp := doRequest(t, http.MethodGet,
+		server.URL+"/admin/reports/compliance?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC); !reports.gotReq.From.Equal(want) {
+		t.Errorf("From = %v, want %v", reports.gotReq.From, want)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testToken))
+	mac.Write(body)
+	if got, want := resp.Header.Get(reportSignatureHeader), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("%s = %q, want %q", reportSignatureHeader, got, want)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want header and one entry", len(rows))
+	}
+	want := []string{"2026-03-01T01:00:00Z", "ops", "force_update", "todo-1", "Fix, urgently", "priority=high; status=pending"}
+	for i, cell := range want {
+		if rows[1][i] != cell {
+			t.Errorf("column %s = %q, want %q", rows[0][i], rows[1][i], cell)
+		}
+	}
+}
+
+func TestHandler_GetComplianceReport_PDF(t *testing.T) {
+	server := newTestServer(t, WithComplianceReports(&fakeComplianceReports{}))
+
+	resp := doRequest(t, http.MethodGet,
+		server.URL+"/admin/reports/compliance?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=pdf", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !bytes.HasPrefix(body, []byte("%PDF-")) {
+		t.Error("body is not a PDF")
+	}
+	if resp.Header.Get(reportSignatureHeader) == "" {
+		t.Errorf("missing %s header", reportSignatureHeader)
+	}
+}
+
+func TestHandler_GetComplianceReport_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"missing to", "?from=2026-03-01T00:00:00Z", nil, http.StatusBadRequest},
+		{"unknown format", "?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=xlsx", nil, http.StatusBadRequest},
+		{"period too large", "?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+			domain.NewValidationError("period", "narrow it"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithComplianceReports(&fakeComplianceReports{err: tt.err}))
+			resp := doRequest(t, http.MethodGet, server.URL+"/admin/reports/compliance"+tt.query, testToken, "")
+			if resp.StatusCode != tt.status {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
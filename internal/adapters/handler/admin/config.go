@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// ConfigReloading applies the changed settings without restart
+type ConfigReloading interface {
+	Reload(ctx context.Context, req application.ConfigReloadRequest) (*application.ConfigReloadResponse, error)
+}
+
+// WithConfigReloader exposes the reload of the configuration
+func WithConfigReloader(reloader ConfigReloading) Option {
+	return func(h *Handler) {
+		h.config = reloader
+	}
+}
+
+// configReloadRequest is the JSON body reloading the configuration
+type configReloadRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// configReloadResponse is the JSON representation of a reload
+type configReloadResponse struct {
+	Applied         map[string]string `json:"applied"`
+	RestartRequired []string          `json:"restart_required"`
+}
+
+// reloadConfig applies the changed settings, and reports those only read
+// at startup
+func (h *Handler) reloadConfig(w http.ResponseWriter, r *http.Request) {
+	var body configReloadRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	response, err := h.config.Reload(r.Context(), application.ConfigReloadRequest(body))
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, configReloadResponse(*response))
+}
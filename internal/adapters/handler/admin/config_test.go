@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeConfigReloader records the requests it receives
+type fakeConfigReloader struct {
+	gotReq application.ConfigReloadRequest
+	err    error
+}
+
+func (f *fakeConfigReloader) Reload(ctx context.Context, req application.ConfigReloadRequest) (*application.ConfigReloadResponse, error) {
+	f.gotReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.ConfigReloadResponse{
+		Applied:         map[string]string{"LOG_LEVEL": "debug"},
+		RestartRequired: []string{"PORT"},
+	}, nil
+}
+
+func TestHandler_ReloadConfig(t *testing.T) {
+	reloader := &fakeConfigReloader{}
+	server := newTestServer(t, WithConfigReloader(reloader))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/config/reload", testToken,
+		`{"actor":"ops@example.com","reason":"Investigate slow scans"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body configReloadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Applied["LOG_LEVEL"] != "debug" || len(body.RestartRequired) != 1 {
+		t.Errorf("response = %+v, want LOG_LEVEL applied and PORT to restart", body)
+	}
+	if reloader.gotReq.Actor != "ops@example.com" || reloader.gotReq.Reason != "Investigate slow scans" {
+		t.Errorf("request = %+v, want the actor and reason", reloader.gotReq)
+	}
+}
+
+func TestHandler_ReloadConfig_Invalid(t *testing.T) {
+	server := newTestServer(t, WithConfigReloader(&fakeConfigReloader{
+		err: fmt.Errorf("%w: invalid REMINDER_INTERVAL: %q", application.ErrInvalidConfig, "soon"),
+	}))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/config/reload", testToken,
+		`{"actor":"ops@example.com","reason":"Reload"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
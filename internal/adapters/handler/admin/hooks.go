@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// InboundHookAdministration is the part of the application service managing
+// inbound webhooks
+type InboundHookAdministration interface {
+	CreateInboundHook(ctx context.Context, req application.InboundHookRequest) (*application.InboundHookCreated, error)
+	ListInboundHooks(ctx context.Context) ([]*application.InboundHookResponse, error)
+	DeleteInboundHook(ctx context.Context, id string) error
+}
+
+// WithInboundHooks exposes the management of inbound webhooks
+func WithInboundHooks(hooks InboundHookAdministration) Option {
+	return func(h *Handler) {
+		h.hooks = hooks
+	}
+}
+
+// inboundHookRequest is the JSON body creating an inbound hook
+type inboundHookRequest struct {
+	Name                string `json:"name"`
+	TitleTemplate       string `json:"title_template"`
+	DescriptionTemplate string `json:"description_template"`
+	PriorityTemplate    string `json:"priority_template"`
+	DueDateTemplate     string `json:"due_date_template"`
+}
+
+// inboundHook is the JSON representation of an inbound hook
+// Token is only set in the response creating the hook
+type inboundHook struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	TitleTemplate       string    `json:"title_template"`
+	DescriptionTemplate string    `json:"description_template,omitempty"`
+	PriorityTemplate    string    `json:"priority_template,omitempty"`
+	DueDateTemplate     string    `json:"due_date_template,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	Token               string    `json:"token,omitempty"`
+}
+
+// mapInboundHook converts an application InboundHookResponse to its JSON representation
+func mapInboundHook(hook *application.InboundHookResponse) inboundHook {
+	return inboundHook{
+		ID:                  hook.ID,
+		Name:                hook.Name,
+		TitleTemplate:       hook.TitleTemplate,
+		DescriptionTemplate: hook.DescriptionTemplate,
+		PriorityTemplate:    hook.PriorityTemplate,
+		DueDateTemplate:     hook.DueDateTemplate,
+		CreatedAt:           hook.CreatedAt,
+	}
+}
+
+// createInboundHook registers an inbound hook and returns its token, once
+func (h *Handler) createInboundHook(w http.ResponseWriter, r *http.Request) {
+	var body inboundHookRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	created, err := h.hooks.CreateInboundHook(r.Context(), application.InboundHookRequest(body))
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("inbound hook created", "hook_id", created.Hook.ID, "name", created.Hook.Name)
+
+	response := mapInboundHook(created.Hook)
+	response.Token = created.Token
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// listInboundHooks lists the inbound hooks, without their tokens
+func (h *Handler) listInboundHooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.hooks.ListInboundHooks(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := make([]inboundHook, len(hooks))
+	for i, hook := range hooks {
+		body[i] = mapInboundHook(hook)
+	}
+	writeJSON(w, http.StatusOK, map[string][]inboundHook{"hooks": body})
+}
+
+// deleteInboundHook removes an inbound hook, revoking its token
+func (h *Handler) deleteInboundHook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.hooks.DeleteInboundHook(r.Context(), id); err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("inbound hook deleted", "hook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeInboundHooks records the requests it receives
+type fakeInboundHooks struct {
+	gotReq application.InboundHookRequest
+	gotID  string
+	err    error
+}
+
+func (f *fakeInboundHooks) CreateInboundHook(
+	ctx context.Context,
+	req application.InboundHookRequest,
+) (*application.InboundHookCreated, error) {
+	f.gotReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.InboundHookCreated{
+		Hook:  &application.InboundHookResponse{ID: "hook-1", Name: req.Name, TitleTemplate: req.TitleTemplate},
+		Token: "tok",
+	}, nil
+}
+
+func (f *fakeInboundHooks) ListInboundHooks(ctx context.Context) ([]*application.InboundHookResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*application.InboundHookResponse{{ID: "hook-1", Name: "Alertmanager"}}, nil
+}
+
+func (f *fakeInboundHooks) DeleteInboundHook(ctx context.Context, id string) error {
+	f.gotID = id
+	return f.err
+}
+
+func TestHandler_CreateInboundHook_ReturnsToken(t *testing.T) {
+	hooks := &fakeInboundHooks{}
+	server := newTestServer(t, WithInboundHooks(hooks))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/hooks", testToken,
+		`{"name":"Alertmanager","title_template":"{{.alert}}"}`)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	if hooks.gotReq.Name != "Alertmanager" || hooks.gotReq.TitleTemplate != "{{.alert}}" {
+		t.Errorf("CreateInboundHook() request = %+v", hooks.gotReq)
+	}
+
+	var body inboundHook
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.ID != "hook-1" || body.Token != "tok" {
+		t.Errorf("Response = %+v, want the hook with its token", body)
+	}
+}
+
+func TestHandler_ListInboundHooks_OmitsTokens(t *testing.T) {
+	server := newTestServer(t, WithInboundHooks(&fakeInboundHooks{}))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/hooks", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body struct {
+		Hooks []inboundHook `json:"hooks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Hooks) != 1 || body.Hooks[0].Token != "" {
+		t.Errorf("Hooks = %+v, want one hook without token", body.Hooks)
+	}
+}
+
+func TestHandler_InboundHooks_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		err    error
+		want   int
+	}{
+		{"invalid template", http.MethodPost, "/admin/hooks", testToken, `{"name":"x","title_template":"{{"}`,
+			domain.NewValidationError("title_template", "unclosed action"), http.StatusBadRequest},
+		{"unknown hook", http.MethodDelete, "/admin/hooks/nope", testToken, "", application.ErrInboundHookNotFound, http.StatusNotFound},
+		{"missing token", http.MethodGet, "/admin/hooks", "", "", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithInboundHooks(&fakeInboundHooks{err: tt.err}))
+
+			resp := doRequest(t, tt.method, server.URL+tt.path, tt.token, tt.body)
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// LegalHoldAdministration is the part of the application service managing
+// legal holds
+type LegalHoldAdministration interface {
+	PlaceLegalHold(ctx context.Context, id string, req application.LegalHoldRequest) (*application.LegalHoldResponse, error)
+	ReleaseLegalHold(ctx context.Context, id string, req application.LegalHoldRequest) error
+	ListLegalHolds(ctx context.Context) ([]*application.LegalHoldResponse, error)
+}
+
+// WithLegalHolds exposes the management of legal holds
+func WithLegalHolds(holds LegalHoldAdministration) Option {
+	return func(h *Handler) {
+		h.legalHolds = holds
+	}
+}
+
+// legalHoldRequest is the JSON body placing or releasing a legal hold
+type legalHoldRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// legalHold is the JSON representation of a legal hold
+type legalHold struct {
+	TodoID   string    `json:"todo_id"`
+	Reason   string    `json:"reason"`
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// decodeLegalHoldRequest reads a legal hold request body, writing a 400 on failure
+func decodeLegalHoldRequest(w http.ResponseWriter, r *http.Request) (application.LegalHoldRequest, bool) {
+	var body legalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return application.LegalHoldRequest{}, false
+	}
+
+	if strings.TrimSpace(body.Actor) == "" {
+		writeError(w, http.StatusBadRequest, "actor is required")
+		return application.LegalHoldRequest{}, false
+	}
+
+	return application.LegalHoldRequest(body), true
+}
+
+// placeLegalHold puts a todo on legal hold
+func (h *Handler) placeLegalHold(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLegalHoldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	hold, err := h.legalHolds.PlaceLegalHold(r.Context(), id, req)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("legal hold placed", "todo_id", hold.TodoID, "actor", req.Actor, "reason", req.Reason)
+	writeJSON(w, http.StatusOK, legalHold(*hold))
+}
+
+// releaseLegalHold releases the legal hold on a todo
+func (h *Handler) releaseLegalHold(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLegalHoldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.legalHolds.ReleaseLegalHold(r.Context(), id, req); err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("legal hold released", "todo_id", id, "actor", req.Actor, "reason", req.Reason)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listLegalHolds returns every legal hold
+func (h *Handler) listLegalHolds(w http.ResponseWriter, r *http.Request) {
+	holds, err := h.legalHolds.ListLegalHolds(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := make([]legalHold, len(holds))
+	for i, hold := range holds {
+		body[i] = legalHold(*hold)
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]legalHold{"legal_holds": body})
+}
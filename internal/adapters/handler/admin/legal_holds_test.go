@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeLegalHolds records the requests it receives
+type fakeLegalHolds struct {
+	gotID  string
+	gotReq application.LegalHoldRequest
+	err    error
+}
+
+func (f *fakeLegalHolds) PlaceLegalHold(
+	ctx context.Context,
+	id string,
+	req application.LegalHoldRequest,
+) (*application.LegalHoldResponse, error) {
+	f.gotID, f.gotReq = id, req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.LegalHoldResponse{TodoID: id, Reason: req.Reason, PlacedBy: req.Actor, PlacedAt: time.Now()}, nil
+}
+
+func (f *fakeLegalHolds) ReleaseLegalHold(ctx context.Context, id string, req application.LegalHoldRequest) error {
+	f.gotID, f.gotReq = id, req
+	return f.err
+}
+
+func (f *fakeLegalHolds) ListLegalHolds(ctx context.Context) ([]*application.LegalHoldResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*application.LegalHoldResponse{{TodoID: "todo-1", Reason: "Case 42", PlacedBy: "legal"}}, nil
+}
+
+func TestHandler_PlaceLegalHold(t *testing.T) {
+	holds := &fakeLegalHolds{}
+	server := newTestServer(t, WithLegalHolds(holds))
+
+	resp := doRequest(t, http.MethodPut, server.URL+"/admin/todos/TD-7/legal-hold", testToken,
+		`{"actor":"legal","reason":"Case 42"}`)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if holds.gotID != "TD-7" || holds.gotReq.Actor != "legal" || holds.gotReq.Reason != "Case 42" {
+		t.Errorf("PlaceLegalHold() got %q %+v", holds.gotID, holds.gotReq)
+	}
+
+	var body legalHold
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.TodoID != "TD-7" || body.PlacedBy != "legal" {
+		t.Errorf("Response = %+v, want the placed hold", body)
+	}
+}
+
+func TestHandler_ReleaseLegalHold(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"released", `{"actor":"legal","reason":"Case closed"}`, nil, http.StatusNoContent},
+		{"missing actor", `{"reason":"Case closed"}`, nil, http.StatusBadRequest},
+		{"not held", `{"actor":"legal","reason":"Case closed"}`, application.ErrLegalHoldNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithLegalHolds(&fakeLegalHolds{err: tt.err}))
+
+			resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/TD-7/legal-hold/release", testToken, tt.body)
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_ListLegalHolds(t *testing.T) {
+	server := newTestServer(t, WithLegalHolds(&fakeLegalHolds{}))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/legal-holds", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body struct {
+		LegalHolds []legalHold `json:"legal_holds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.LegalHolds) != 1 || body.LegalHolds[0].TodoID != "todo-1" {
+		t.Errorf("Response = %+v, want the hold on todo-1", body)
+	}
+}
@@ -0,0 +1,137 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// purgeRequest is the JSON body of a purge preview or confirmation
+type purgeRequest struct {
+	Status            *string `json:"status"`
+	Priority          *string `json:"priority"`
+	UpdatedBefore     *string `json:"updated_before"`
+	Actor             string  `json:"actor"`
+	Reason            string  `json:"reason"`
+	ConfirmationToken string  `json:"confirmation_token"`
+}
+
+// purgePreview is the JSON response of a purge preview
+type purgePreview struct {
+	MatchCount        int       `json:"match_count"`
+	HeldCount         int       `json:"held_count"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// tombstone is the JSON representation of a purged todo
+type tombstone struct {
+	ID        string    `json:"id"`
+	ShortCode string    `json:"short_code,omitempty"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Priority  string    `json:"priority"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// purgeReport is the JSON tombstone report of a purge
+type purgeReport struct {
+	Actor      string      `json:"actor"`
+	Reason     string      `json:"reason"`
+	PurgedAt   time.Time   `json:"purged_at"`
+	Count      int         `json:"count"`
+	Tombstones []tombstone `json:"tombstones"`
+}
+
+// decodePurgeRequest reads a purge request body, writing a 400 on failure
+func decodePurgeRequest(w http.ResponseWriter, r *http.Request) (application.PurgeTodosRequest, bool) {
+	var body purgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return application.PurgeTodosRequest{}, false
+	}
+
+	req := application.PurgeTodosRequest{
+		Status:            body.Status,
+		Priority:          body.Priority,
+		Actor:             body.Actor,
+		Reason:            body.Reason,
+		ConfirmationToken: body.ConfirmationToken,
+	}
+
+	if body.UpdatedBefore != nil {
+		updatedBefore, err := time.Parse(time.RFC3339, *body.UpdatedBefore)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid updated_before: "+err.Error())
+			return application.PurgeTodosRequest{}, false
+		}
+		req.UpdatedBefore = &updatedBefore
+	}
+
+	return req, true
+}
+
+// previewPurge answers the first step of a purge: how many todos the filter
+// matches, and the token confirming their deletion
+func (h *Handler) previewPurge(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodePurgeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := h.todos.PreviewPurge(r.Context(), req)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, purgePreview{
+		MatchCount:        preview.MatchCount,
+		HeldCount:         preview.HeldCount,
+		ConfirmationToken: preview.ConfirmationToken,
+		ExpiresAt:         preview.ExpiresAt,
+	})
+}
+
+// purgeTodos hard-deletes the todos of a confirmed preview and returns the
+// tombstone report as a downloadable file
+func (h *Handler) purgeTodos(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodePurgeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if strings.TrimSpace(req.Actor) == "" {
+		writeError(w, http.StatusBadRequest, "actor is required")
+		return
+	}
+
+	report, err := h.todos.PurgeTodos(r.Context(), req)
+	if err != nil {
+		h.logger.Error("purge failed", "actor", req.Actor, "error", err)
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("todos purged", "count", len(report.Tombstones), "actor", req.Actor, "reason", req.Reason)
+
+	body := purgeReport{
+		Actor:      report.Actor,
+		Reason:     report.Reason,
+		PurgedAt:   report.PurgedAt,
+		Count:      len(report.Tombstones),
+		Tombstones: make([]tombstone, len(report.Tombstones)),
+	}
+	for i, t := range report.Tombstones {
+		body.Tombstones[i] = tombstone(t)
+	}
+
+	filename := fmt.Sprintf("purge-%s.json", report.PurgedAt.UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	writeJSON(w, http.StatusOK, body)
+}
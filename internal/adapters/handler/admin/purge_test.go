@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_PreviewPurge_Success(t *testing.T) {
+	todos := &fakeTodoAdministration{}
+	server := newTestServer(t, WithTodoAdministration(todos))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/purge/preview", testToken,
+		`{"status":"cancelled","updated_before":"2023-01-01T00:00:00Z"}`)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var got purgePreview
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.MatchCount != 2 || got.ConfirmationToken != "token" {
+		t.Errorf("Response = %+v, want 2 matches and a token", got)
+	}
+
+	want := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := todos.gotPurgeReq
+	if req.Status == nil || *req.Status != "cancelled" || req.UpdatedBefore == nil || !req.UpdatedBefore.Equal(want) {
+		t.Errorf("PreviewPurge() request = %+v, want cancelled before %v", req, want)
+	}
+}
+
+func TestHandler_PurgeTodos_ReturnsTombstoneReport(t *testing.T) {
+	todos := &fakeTodoAdministration{}
+	server := newTestServer(t, WithTodoAdministration(todos))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/purge", testToken,
+		`{"status":"cancelled","actor":"ops@example.com","reason":"Retention","confirmation_token":"token"}`)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment;") {
+		t.Errorf("Content-Disposition = %q, want an attachment", disposition)
+	}
+
+	var got purgeReport
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.Count != 1 || got.Tombstones[0].ID != "abc" || got.Actor != "ops@example.com" {
+		t.Errorf("Response = %+v, want the tombstone of abc", got)
+	}
+
+	if todos.gotPurgeReq.ConfirmationToken != "token" {
+		t.Errorf("ConfirmationToken = %q, want %q", todos.gotPurgeReq.ConfirmationToken, "token")
+	}
+}
+
+func TestHandler_PurgeTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"missing actor", `{"status":"cancelled","reason":"Retention"}`, nil, http.StatusBadRequest},
+		{"invalid date", `{"updated_before":"2023","actor":"ops"}`, nil, http.StatusBadRequest},
+		{"missing filter", `{"actor":"ops"}`, domain.NewValidationError("filter", "at least one criterion is required"), http.StatusBadRequest},
+		{"stale confirmation", `{"status":"cancelled","actor":"ops"}`, application.ErrPurgeConfirmation, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithTodoAdministration(&fakeTodoAdministration{err: tt.err}))
+
+			resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/purge", testToken, tt.body)
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
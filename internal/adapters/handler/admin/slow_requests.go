@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+)
+
+// SlowRequestReporting lists the latest slow requests
+type SlowRequestReporting interface {
+	Recent() []reqtrace.Dump
+}
+
+// WithSlowRequests exposes the dumps of the latest slow requests
+func WithSlowRequests(slow SlowRequestReporting) Option {
+	return func(h *Handler) {
+		h.slow = slow
+	}
+}
+
+// stageResponse is the JSON representation of a stage of a slow request
+type stageResponse struct {
+	Count   int     `json:"count"`
+	TotalMS float64 `json:"total_ms"`
+	MaxMS   float64 `json:"max_ms"`
+	Slowest string  `json:"slowest,omitempty"`
+}
+
+// slowRequestResponse is the JSON representation of a slow request
+type slowRequestResponse struct {
+	At        time.Time                `json:"at"`
+	Method    string                   `json:"method"`
+	Path      string                   `json:"path"`
+	Status    int                      `json:"status"`
+	TotalMS   float64                  `json:"total_ms"`
+	HandlerMS float64                  `json:"handler_ms"`
+	Stages    map[string]stageResponse `json:"stages"`
+}
+
+// listSlowRequests lists the latest slow requests, latest first
+func (h *Handler) listSlowRequests(w http.ResponseWriter, r *http.Request) {
+	dumps := h.slow.Recent()
+	body := make([]slowRequestResponse, len(dumps))
+	for i, dump := range dumps {
+		body[i] = mapSlowRequest(dump)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]any{"requests": body})
+}
+
+// mapSlowRequest converts a Dump to its JSON representation
+func mapSlowRequest(dump reqtrace.Dump) slowRequestResponse {
+	body := slowRequestResponse{
+		At:        dump.At,
+		Method:    dump.Method,
+		Path:      dump.Path,
+		Status:    dump.Status,
+		TotalMS:   milliseconds(dump.Total),
+		HandlerMS: milliseconds(dump.Handler),
+		Stages:    make(map[string]stageResponse, len(dump.Stages)),
+	}
+	for name, stage := range dump.Stages {
+		body.Stages[name] = stageResponse{
+			Count:   stage.Count,
+			TotalMS: milliseconds(stage.Total),
+			MaxMS:   milliseconds(stage.Max),
+			Slowest: stage.Slowest,
+		}
+	}
+	return body
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+)
+
+func TestHandler_ListSlowRequests(t *testing.T) {
+	slow := reqtrace.NewSlowLog(10)
+	slow.Add(reqtrace.Dump{
+		At:      time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Method:  http.MethodPost,
+		Path:    "/todo.v1.TodoService/ListTodos",
+		Status:  http.StatusOK,
+		Total:   1500 * time.Millisecond,
+		Handler: 250 * time.Millisecond,
+		Stages: map[string]reqtrace.Stage{
+			reqtrace.StageSQL: {Count: 2, Total: 1200 * time.Millisecond, Max: time.Second, Slowest: "SELECT * FROM todos"},
+		},
+	})
+	server := newTestServer(t, WithSlowRequests(slow))
+
+	if resp := doRequest(t, http.MethodGet, server.URL+"/admin/slow-requests", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Status without token = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/slow-requests", testToken, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body struct {
+		Requests []slowRequestResponse `json:"requests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Requests) != 1 {
+		t.Fatalf("requests = %+v, want 1", body.Requests)
+	}
+	got := body.Requests[0]
+	if got.TotalMS != 1500 || got.HandlerMS != 250 {
+		t.Errorf("total = %v, handler = %v, want 1500 and 250", got.TotalMS, got.HandlerMS)
+	}
+	if sql := got.Stages[reqtrace.StageSQL]; sql.Count != 2 || sql.MaxMS != 1000 || sql.Slowest != "SELECT * FROM todos" {
+		t.Errorf("sql = %+v, want the slowest query", sql)
+	}
+}
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// StatusReporting reports the state of the instance
+type StatusReporting interface {
+	SystemStatus(ctx context.Context) (*application.SystemStatus, error)
+}
+
+// WithSystemStatus exposes the state of the instance for on-call triage
+func WithSystemStatus(status StatusReporting) Option {
+	return func(h *Handler) {
+		h.status = status
+	}
+}
+
+// jobStatusResponse is the JSON representation of a background job
+type jobStatusResponse struct {
+	Name            string           `json:"name"`
+	IntervalSeconds float64          `json:"interval_seconds"`
+	LastRunAt       *time.Time       `json:"last_run_at"`
+	LastSuccessAt   *time.Time       `json:"last_success_at"`
+	LastError       string           `json:"last_error,omitempty"`
+	Runs            int64            `json:"runs"`
+	Failures        int64            `json:"failures"`
+	Counters        map[string]int64 `json:"counters,omitempty"`
+}
+
+// outboxStatusResponse is the JSON representation of the outbox backlog
+type outboxStatusResponse struct {
+	Pending         int        `json:"pending"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at"`
+	LagSeconds      float64    `json:"lag_seconds"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// eventDeliveryResponse is the JSON representation of the delivery of the
+// events to the broker
+type eventDeliveryResponse struct {
+	Status             string     `json:"status"`
+	DegradedSince      *time.Time `json:"degraded_since"`
+	DegradedForSeconds float64    `json:"degraded_for_seconds"`
+	Buffered           int        `json:"buffered"`
+	Relayed            int64      `json:"relayed"`
+}
+
+// cacheStatusResponse is the JSON representation of a cache
+type cacheStatusResponse struct {
+	Name     string  `json:"name"`
+	Entries  int     `json:"entries"`
+	Capacity int     `json:"capacity"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRate  float64 `json:"hit_rate"`
+}
+
+// poolStatusResponse is the JSON representation of the connection pool
+type poolStatusResponse struct {
+	MaxConns               int32   `json:"max_conns"`
+	TotalConns             int32   `json:"total_conns"`
+	AcquiredConns          int32   `json:"acquired_conns"`
+	IdleConns              int32   `json:"idle_conns"`
+	Saturation             float64 `json:"saturation"`
+	EmptyAcquires          int64   `json:"empty_acquires"`
+	AcquireDurationSeconds float64 `json:"acquire_duration_seconds"`
+}
+
+// systemStatusResponse is the JSON representation of the instance state
+type systemStatusResponse struct {
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Maintenance   bool                   `json:"maintenance"`
+	Jobs          []jobStatusResponse    `json:"jobs"`
+	Outbox        *outboxStatusResponse  `json:"outbox,omitempty"`
+	Events        *eventDeliveryResponse `json:"events,omitempty"`
+	Caches        []cacheStatusResponse  `json:"caches"`
+	Pool          *poolStatusResponse    `json:"pool,omitempty"`
+	ActiveStreams *int                   `json:"active_streams,omitempty"`
+}
+
+// getSystemStatus reports the state of the instance
+func (h *Handler) getSystemStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.status.SystemStatus(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, mapSystemStatus(status))
+}
+
+// mapSystemStatus converts a SystemStatus to its JSON representation
+func mapSystemStatus(status *application.SystemStatus) systemStatusResponse {
+	body := systemStatusResponse{
+		GeneratedAt:   status.GeneratedAt,
+		Maintenance:   status.Maintenance,
+		Jobs:          make([]jobStatusResponse, len(status.Jobs)),
+		Caches:        make([]cacheStatusResponse, len(status.Caches)),
+		ActiveStreams: status.ActiveStreams,
+	}
+
+	for i, job := range status.Jobs {
+		body.Jobs[i] = jobStatusResponse{
+			Name:            job.Name,
+			IntervalSeconds: job.Interval.Seconds(),
+			LastRunAt:       job.LastRunAt,
+			LastSuccessAt:   job.LastSuccessAt,
+			LastError:       job.LastError,
+			Runs:            job.Runs,
+			Failures:        job.Failures,
+			Counters:        job.Counters,
+		}
+	}
+
+	if outbox := status.Outbox; outbox != nil {
+		body.Outbox = &outboxStatusResponse{
+			Pending:         outbox.Pending,
+			OldestPendingAt: outbox.OldestPendingAt,
+			LagSeconds:      outbox.Lag.Seconds(),
+			Error:           outbox.Error,
+		}
+	}
+
+	if events := status.Events; events != nil {
+		body.Events = &eventDeliveryResponse{
+			Status:             "ok",
+			DegradedSince:      events.DegradedSince,
+			DegradedForSeconds: events.DegradedFor.Seconds(),
+			Buffered:           events.Buffered,
+			Relayed:            events.Relayed,
+		}
+		if events.Degraded {
+			body.Events.Status = "degraded"
+		}
+	}
+
+	for i, cache := range status.Caches {
+		body.Caches[i] = cacheStatusResponse(cache)
+	}
+
+	if pool := status.Pool; pool != nil {
+		body.Pool = &poolStatusResponse{
+			MaxConns:               pool.MaxConns,
+			TotalConns:             pool.TotalConns,
+			AcquiredConns:          pool.AcquiredConns,
+			IdleConns:              pool.IdleConns,
+			Saturation:             pool.Saturation,
+			EmptyAcquires:          pool.EmptyAcquires,
+			AcquireDurationSeconds: pool.AcquireDuration.Seconds(),
+		}
+	}
+
+	return body
+}
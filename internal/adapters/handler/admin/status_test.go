@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// fakeStatus reports a fixed status
+type fakeStatus struct {
+	status *application.SystemStatus
+}
+
+func (f fakeStatus) SystemStatus(ctx context.Context) (*application.SystemStatus, error) {
+	return f.status, nil
+}
+
+func TestHandler_GetSystemStatus(t *testing.T) {
+	ran := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	streams := 4
+	server := newTestServer(t, WithSystemStatus(fakeStatus{&application.SystemStatus{
+		GeneratedAt: ran,
+		Jobs:        []application.JobStatus{{Name: "reminders", Interval: time.Minute, LastRunAt: &ran, Runs: 1}},
+		Outbox:      &application.OutboxStatus{Pending: 2, Lag: 90 * time.Second},
+		Events: &application.EventDeliveryStatus{
+			EventDeliveryStats: ports.EventDeliveryStats{Degraded: true, DegradedSince: &ran, Buffered: 7},
+			DegradedFor:        time.Minute,
+		},
+		Caches:        []application.CacheStatus{{Name: "heatmaps", Hits: 3, Misses: 1, HitRate: 0.75}},
+		Pool:          &application.PoolStatus{PoolStats: ports.PoolStats{MaxConns: 10, AcquiredConns: 5}, Saturation: 0.5},
+		ActiveStreams: &streams,
+	}}))
+
+	if resp := doRequest(t, http.MethodGet, server.URL+"/admin/status", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Status without token = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/status", testToken, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body systemStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Jobs) != 1 || body.Jobs[0].IntervalSeconds != 60 || body.Jobs[0].LastRunAt == nil {
+		t.Errorf("jobs = %+v, want the reminders run", body.Jobs)
+	}
+	if body.Outbox == nil || body.Outbox.LagSeconds != 90 {
+		t.Errorf("outbox = %+v, want a lag of 90s", body.Outbox)
+	}
+	if body.Events == nil || body.Events.Status != "degraded" || body.Events.Buffered != 7 || body.Events.DegradedForSeconds != 60 {
+		t.Errorf("events = %+v, want 7 events buffered for a minute", body.Events)
+	}
+	if len(body.Caches) != 1 || body.Caches[0].HitRate != 0.75 {
+		t.Errorf("caches = %+v, want the heatmap hit rate", body.Caches)
+	}
+	if body.Pool == nil || body.Pool.Saturation != 0.5 || body.ActiveStreams == nil || *body.ActiveStreams != 4 {
+		t.Errorf("pool = %+v, streams = %v, want a half-saturated pool and 4 streams", body.Pool, body.ActiveStreams)
+	}
+}
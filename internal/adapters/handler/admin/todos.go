@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+)
+
+// TodoAdministration is the part of the application service used by the
+// privileged todo routes
+type TodoAdministration interface {
+	ForceUpdateTodo(ctx context.Context, id string, req application.ForceUpdateTodoRequest) (*application.TodoResponse, error)
+	PreviewPurge(ctx context.Context, req application.PurgeTodosRequest) (*application.PurgePreview, error)
+	PurgeTodos(ctx context.Context, req application.PurgeTodosRequest) (*application.PurgeReport, error)
+}
+
+// WithTodoAdministration exposes privileged todo operations
+func WithTodoAdministration(todos TodoAdministration) Option {
+	return func(h *Handler) {
+		h.todos = todos
+	}
+}
+
+// forceUpdateRequest is the JSON body of a forced update
+// An empty due_date clears the due date
+type forceUpdateRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Priority    *string `json:"priority"`
+	DueDate     *string `json:"due_date"`
+	Status      *string `json:"status"`
+	Actor       string  `json:"actor"`
+	Reason      string  `json:"reason"`
+}
+
+// todoResponse is the JSON representation of a todo
+type todoResponse struct {
+	ID          string     `json:"id"`
+	ShortCode   string     `json:"short_code,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	OwnerID     string     `json:"owner_id,omitempty"`
+}
+
+// forceUpdateTodo overrides a todo regardless of its status
+func (h *Handler) forceUpdateTodo(w http.ResponseWriter, r *http.Request) {
+	var body forceUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if strings.TrimSpace(body.Actor) == "" {
+		writeError(w, http.StatusBadRequest, "actor is required")
+		return
+	}
+
+	req := application.ForceUpdateTodoRequest{
+		UpdateTodoRequest: application.UpdateTodoRequest{
+			Title:       body.Title,
+			Description: body.Description,
+			Priority:    body.Priority,
+			Status:      body.Status,
+		},
+		Actor:  body.Actor,
+		Reason: body.Reason,
+	}
+
+	if body.DueDate != nil {
+		var dueDate time.Time
+		if *body.DueDate != "" {
+			parsed, err := time.Parse(time.RFC3339, *body.DueDate)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid due_date: "+err.Error())
+				return
+			}
+			dueDate = parsed
+		}
+		req.DueDate = &dueDate
+	}
+
+	id := r.PathValue("id")
+	todo, err := h.todos.ForceUpdateTodo(r.Context(), id, req)
+	if err != nil {
+		h.logger.Error("forced update failed", "todo_id", id, "actor", body.Actor, "error", err)
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("todo force-updated", "todo_id", id, "actor", body.Actor, "reason", body.Reason)
+
+	writeJSON(w, http.StatusOK, mapTodo(todo))
+}
+
+// mapTodo converts an application TodoResponse to its JSON representation
+func mapTodo(todo *application.TodoResponse) todoResponse {
+	return todoResponse{
+		ID:          todo.ID,
+		ShortCode:   todo.ShortCode,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      todo.Status,
+		Priority:    todo.Priority,
+		DueDate:     todo.DueDate,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		OwnerID:     todo.OwnerID,
+	}
+}
+
+// statusForError maps application and domain errors to HTTP status codes
+func statusForError(err error) int {
+	var validationErr domain.ValidationError
+
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound),
+		errors.Is(err, application.ErrInboundHookNotFound),
+		errors.Is(err, application.ErrWebhookNotFound),
+		errors.Is(err, application.ErrWebhookDeliveryNotFound),
+		errors.Is(err, application.ErrLegalHoldNotFound),
+		errors.Is(err, application.ErrHolidayNotFound),
+		errors.Is(err, application.ErrCanaryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, application.ErrMaintenanceMode),
+		errors.Is(err, application.ErrWebhookBackfillsBusy),
+		errors.Is(err, circuitbreaker.ErrOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrInvalidID),
+		errors.Is(err, domain.ErrInvalidTitle),
+		errors.Is(err, domain.ErrInvalidDueDate),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrMissingReason),
+		errors.Is(err, application.ErrInvalidConfig),
+		errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, application.ErrPurgeConfirmation),
+		errors.Is(err, application.ErrWebhookNotRedeliverable):
+		return http.StatusConflict
+	case errors.Is(err, application.ErrNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeTodoAdministration records the requests it receives
+type fakeTodoAdministration struct {
+	gotID       string
+	gotReq      application.ForceUpdateTodoRequest
+	gotPurgeReq application.PurgeTodosRequest
+	err         error
+}
+
+func (f *fakeTodoAdministration) ForceUpdateTodo(
+	ctx context.Context,
+	id string,
+	req application.ForceUpdateTodoRequest,
+) (*application.TodoResponse, error) {
+	f.gotID, f.gotReq = id, req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.TodoResponse{ID: id, Title: "Corrected", Status: "completed"}, nil
+}
+
+func (f *fakeTodoAdministration) PreviewPurge(
+	ctx context.Context,
+	req application.PurgeTodosRequest,
+) (*application.PurgePreview, error) {
+	f.gotPurgeReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.PurgePreview{MatchCount: 2, ConfirmationToken: "token"}, nil
+}
+
+func (f *fakeTodoAdministration) PurgeTodos(
+	ctx context.Context,
+	req application.PurgeTodosRequest,
+) (*application.PurgeReport, error) {
+	f.gotPurgeReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.PurgeReport{
+		Actor:      req.Actor,
+		Reason:     req.Reason,
+		Tombstones: []application.TodoTombstone{{ID: "abc", Title: "Old", Status: "cancelled"}},
+	}, nil
+}
+
+func TestHandler_ForceUpdateTodo_Success(t *testing.T) {
+	todos := &fakeTodoAdministration{}
+	server := newTestServer(t, WithTodoAdministration(todos))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/abc/force-update", testToken,
+		`{"title":"Corrected","due_date":"","actor":"ops@example.com","reason":"Typo"}`)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var got todoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if got.ID != "abc" || got.Title != "Corrected" {
+		t.Errorf("Response = %+v, want the updated todo", got)
+	}
+
+	if todos.gotID != "abc" || todos.gotReq.Reason != "Typo" || todos.gotReq.Actor != "ops@example.com" {
+		t.Errorf("ForceUpdateTodo() called with id=%q req=%+v", todos.gotID, todos.gotReq)
+	}
+
+	if todos.gotReq.DueDate == nil || !todos.gotReq.DueDate.IsZero() {
+		t.Errorf("DueDate = %v, want zero time to clear the due date", todos.gotReq.DueDate)
+	}
+}
+
+func TestHandler_ForceUpdateTodo_RequiresActor(t *testing.T) {
+	server := newTestServer(t, WithTodoAdministration(&fakeTodoAdministration{}))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/abc/force-update", testToken,
+		`{"title":"Corrected","reason":"Typo"}`)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_ForceUpdateTodo_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"missing reason", domain.ErrMissingReason, http.StatusBadRequest},
+		{"not found", domain.ErrTodoNotFound, http.StatusNotFound},
+		{"maintenance", application.ErrMaintenanceMode, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithTodoAdministration(&fakeTodoAdministration{err: tt.err}))
+
+			resp := doRequest(t, http.MethodPost, server.URL+"/admin/todos/abc/force-update", testToken,
+				`{"status":"pending","actor":"ops@example.com"}`)
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
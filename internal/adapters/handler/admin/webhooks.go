@@ -0,0 +1,230 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// WebhookAdministration manages the endpoints domain events are posted to
+type WebhookAdministration interface {
+	Register(ctx context.Context, req application.WebhookRequest) (*application.WebhookCreated, error)
+	List(ctx context.Context) ([]*application.WebhookResponse, error)
+	Delete(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, endpointID string, req application.WebhookDeliveriesRequest) (*application.WebhookDeliveryPage, error)
+	Redeliver(ctx context.Context, endpointID string, deliveryID int64) (*application.WebhookDeliveryResponse, error)
+}
+
+// WithWebhooks exposes the management of outbound webhooks
+func WithWebhooks(webhooks WebhookAdministration) Option {
+	return func(h *Handler) {
+		h.webhooks = webhooks
+	}
+}
+
+// webhookRequest is the JSON body registering a webhook endpoint
+// DigestWindow is a Go duration such as "30s", none when empty
+type webhookRequest struct {
+	URL          string           `json:"url"`
+	EventTypes   []string         `json:"event_types"`
+	DigestWindow string           `json:"digest_window"`
+	Backfill     *webhookBackfill `json:"backfill"`
+}
+
+// webhookBackfill is the JSON body of the backfill of a new endpoint
+// To is the registration when omitted, and Rate is in events per second
+type webhookBackfill struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Rate int       `json:"rate"`
+}
+
+// webhook is the JSON representation of a webhook endpoint
+// Secret and Backfilling are only set in the response registering the
+// endpoint
+type webhook struct {
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	EventTypes   []string  `json:"event_types"`
+	DigestWindow string    `json:"digest_window"`
+	CreatedAt    time.Time `json:"created_at"`
+	Secret       string    `json:"secret,omitempty"`
+	Backfilling  bool      `json:"backfilling,omitempty"`
+}
+
+// webhookDelivery is the JSON representation of an attempt to post an event
+// to a webhook endpoint
+// StatusCode is 0 when no response was received
+type webhookDelivery struct {
+	ID              int64     `json:"id"`
+	EventID         string    `json:"event_id"`
+	EventType       string    `json:"event_type"`
+	Attempt         int       `json:"attempt"`
+	StatusCode      int       `json:"status_code"`
+	Error           string    `json:"error,omitempty"`
+	ResponseSnippet string    `json:"response_snippet"`
+	RedeliveryOf    int64     `json:"redelivery_of,omitempty"`
+	DurationMs      int64     `json:"duration_ms"`
+	AttemptedAt     time.Time `json:"attempted_at"`
+}
+
+// webhookDeliveryPage is the JSON representation of a page of delivery attempts
+type webhookDeliveryPage struct {
+	Deliveries []webhookDelivery `json:"deliveries"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// mapWebhook converts an application WebhookResponse to its JSON representation
+func mapWebhook(endpoint *application.WebhookResponse) webhook {
+	eventTypes := endpoint.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return webhook{
+		ID:           endpoint.ID,
+		URL:          endpoint.URL,
+		EventTypes:   eventTypes,
+		DigestWindow: endpoint.DigestWindow.String(),
+		CreatedAt:    endpoint.CreatedAt,
+	}
+}
+
+// createWebhook registers a webhook endpoint and returns its secret, once
+func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var body webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	req := application.WebhookRequest{URL: body.URL, EventTypes: body.EventTypes}
+	if body.DigestWindow != "" {
+		window, err := time.ParseDuration(body.DigestWindow)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid digest_window: "+body.DigestWindow)
+			return
+		}
+		req.DigestWindow = window
+	}
+	if body.Backfill != nil {
+		req.Backfill = &application.WebhookBackfillRequest{From: body.Backfill.From, To: body.Backfill.To, Rate: body.Backfill.Rate}
+	}
+
+	created, err := h.webhooks.Register(r.Context(), req)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("webhook registered", "webhook_id", created.Webhook.ID, "url", created.Webhook.URL)
+
+	response := mapWebhook(created.Webhook)
+	response.Secret = created.Secret
+	response.Backfilling = created.Backfilling
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// listWebhooks lists the webhook endpoints, without their secrets
+func (h *Handler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.webhooks.List(r.Context())
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := make([]webhook, len(endpoints))
+	for i, endpoint := range endpoints {
+		body[i] = mapWebhook(endpoint)
+	}
+	writeJSON(w, http.StatusOK, map[string][]webhook{"webhooks": body})
+}
+
+// deleteWebhook removes a webhook endpoint
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.webhooks.Delete(r.Context(), id); err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("webhook deleted", "webhook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listWebhookDeliveries answers
+// GET /admin/webhooks/{id}/deliveries?failed=true&cursor=<next_cursor>&limit=<n>
+// with the delivery attempts of an endpoint, most recent first
+func (h *Handler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := application.WebhookDeliveriesRequest{Cursor: query.Get("cursor")}
+
+	if raw := query.Get("failed"); raw != "" {
+		failed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid failed: "+raw)
+			return
+		}
+		req.FailedOnly = failed
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid limit: "+raw)
+			return
+		}
+		req.Limit = limit
+	}
+
+	page, err := h.webhooks.ListDeliveries(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	body := webhookDeliveryPage{Deliveries: make([]webhookDelivery, len(page.Deliveries)), NextCursor: page.NextCursor}
+	for i, delivery := range page.Deliveries {
+		body.Deliveries[i] = mapWebhookDelivery(delivery)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// redeliverWebhook posts the payload of a delivery attempt to its endpoint
+// again and returns the new attempt, failed or not
+func (h *Handler) redeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	deliveryID, err := strconv.ParseInt(r.PathValue("delivery"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, application.ErrWebhookDeliveryNotFound.Error())
+		return
+	}
+
+	delivery, err := h.webhooks.Redeliver(r.Context(), id, deliveryID)
+	if err != nil {
+		writeError(w, statusForError(err), err.Error())
+		return
+	}
+
+	h.logger.Warn("webhook redelivered", "webhook_id", id, "delivery_id", deliveryID, "status", delivery.StatusCode)
+	writeJSON(w, http.StatusOK, mapWebhookDelivery(delivery))
+}
+
+// mapWebhookDelivery converts an application WebhookDeliveryResponse to its
+// JSON representation
+func mapWebhookDelivery(delivery *application.WebhookDeliveryResponse) webhookDelivery {
+	return webhookDelivery{
+		ID:              delivery.ID,
+		EventID:         delivery.EventID,
+		EventType:       delivery.EventType,
+		Attempt:         delivery.Attempt,
+		StatusCode:      delivery.StatusCode,
+		Error:           delivery.Error,
+		ResponseSnippet: delivery.ResponseSnippet,
+		RedeliveryOf:    delivery.RedeliveryOf,
+		DurationMs:      delivery.Duration.Milliseconds(),
+		AttemptedAt:     delivery.AttemptedAt,
+	}
+}
@@ -0,0 +1,298 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// fakeWebhooks records the requests it receives
+type fakeWebhooks struct {
+	gotReq        application.WebhookRequest
+	gotID         string
+	gotDeliveries application.WebhookDeliveriesRequest
+	gotDeliveryID int64
+	err           error
+}
+
+func (f *fakeWebhooks) Register(ctx context.Context, req application.WebhookRequest) (*application.WebhookCreated, error) {
+	f.gotReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.WebhookCreated{
+		Webhook:     &application.WebhookResponse{ID: "webhook-1", URL: req.URL, EventTypes: req.EventTypes, DigestWindow: req.DigestWindow},
+		Secret:      "shh",
+		Backfilling: req.Backfill != nil,
+	}, nil
+}
+
+func (f *fakeWebhooks) List(ctx context.Context) ([]*application.WebhookResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*application.WebhookResponse{{ID: "webhook-1", URL: "https://example.com/hooks"}}, nil
+}
+
+func (f *fakeWebhooks) Delete(ctx context.Context, id string) error {
+	f.gotID = id
+	return f.err
+}
+
+func (f *fakeWebhooks) ListDeliveries(ctx context.Context, endpointID string, req application.WebhookDeliveriesRequest) (*application.WebhookDeliveryPage, error) {
+	f.gotID = endpointID
+	f.gotDeliveries = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.WebhookDeliveryPage{
+		Deliveries: []*application.WebhookDeliveryResponse{{
+			ID: 7, EventID: "event-1", EventType: "TodoDeleted", Attempt: 3, StatusCode: http.StatusBadGateway,
+			Error: "endpoint answered 502 Bad Gateway", ResponseSnippet: "upstream down", Duration: 1500 * time.Millisecond,
+		}},
+		NextCursor: "7",
+	}, nil
+}
+
+func (f *fakeWebhooks) Redeliver(ctx context.Context, endpointID string, deliveryID int64) (*application.WebhookDeliveryResponse, error) {
+	f.gotID = endpointID
+	f.gotDeliveryID = deliveryID
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.WebhookDeliveryResponse{ID: 8, EventID: "event-1", Attempt: 1, StatusCode: http.StatusOK, RedeliveryOf: deliveryID}, nil
+}
+
+func TestHandler_CreateWebhook_ReturnsSecret(t *testing.T) {
+	webhooks := &fakeWebhooks{}
+	server := newTestServer(t, WithWebhooks(webhooks))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/webhooks", testToken,
+		`{"url":"https://example.com/hooks","event_types":["TodoCompleted"],"digest_window":"30s"}`)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if webhooks.gotReq.URL != "https://example.com/hooks" || len(webhooks.gotReq.EventTypes) != 1 || webhooks.gotReq.DigestWindow != 30*time.Second {
+		t.Errorf("Register() request = %+v", webhooks.gotReq)
+	}
+
+	var body webhook
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.ID != "webhook-1" || body.Secret != "shh" || body.DigestWindow != "30s" {
+		t.Errorf("Response = %+v, want the webhook with its secret", body)
+	}
+}
+
+func TestHandler_CreateWebhook_Backfill(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "queued", wantStatus: http.StatusCreated},
+		{name: "busy", err: application.ErrWebhookBackfillsBusy, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := &fakeWebhooks{err: tt.err}
+			server := newTestServer(t, WithWebhooks(webhooks))
+
+			resp := doRequest(t, http.MethodPost, server.URL+"/admin/webhooks", testToken,
+				`{"url":"https://example.com/hooks","backfill":{"from":"2030-01-01T00:00:00Z","rate":5}}`)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			backfill := webhooks.gotReq.Backfill
+			if backfill == nil || !backfill.From.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) || !backfill.To.IsZero() || backfill.Rate != 5 {
+				t.Errorf("Register() backfill = %+v", backfill)
+			}
+			if tt.err != nil {
+				return
+			}
+			var body webhook
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if !body.Backfilling {
+				t.Errorf("Response = %+v, want backfilling", body)
+			}
+		})
+	}
+}
+
+func TestHandler_CreateWebhook_InvalidURL(t *testing.T) {
+	server := newTestServer(t, WithWebhooks(&fakeWebhooks{err: domain.NewValidationError("url", "must be an absolute http or https URL")}))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/webhooks", testToken, `{"url":"/hooks"}`)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_CreateWebhook_InvalidDigestWindow(t *testing.T) {
+	webhooks := &fakeWebhooks{}
+	server := newTestServer(t, WithWebhooks(webhooks))
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/admin/webhooks", testToken,
+		`{"url":"https://example.com/hooks","digest_window":"soon"}`)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if webhooks.gotReq.URL != "" {
+		t.Errorf("Register() called with %+v, want no call", webhooks.gotReq)
+	}
+}
+
+func TestHandler_ListWebhooks_OmitsSecrets(t *testing.T) {
+	server := newTestServer(t, WithWebhooks(&fakeWebhooks{}))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/webhooks", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body struct {
+		Webhooks []map[string]any `json:"webhooks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Webhooks) != 1 || body.Webhooks[0]["secret"] != nil {
+		t.Errorf("Response = %+v, want one webhook without its secret", body.Webhooks)
+	}
+	if eventTypes, ok := body.Webhooks[0]["event_types"].([]any); !ok || len(eventTypes) != 0 {
+		t.Errorf("event_types = %v, want an empty list for every event", body.Webhooks[0]["event_types"])
+	}
+}
+
+func TestHandler_DeleteWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "deleted", wantStatus: http.StatusNoContent},
+		{name: "unknown", err: application.ErrWebhookNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := &fakeWebhooks{err: tt.err}
+			server := newTestServer(t, WithWebhooks(webhooks))
+
+			resp := doRequest(t, http.MethodDelete, server.URL+"/admin/webhooks/webhook-1", testToken, "")
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if webhooks.gotID != "webhook-1" {
+				t.Errorf("Delete() id = %q, want webhook-1", webhooks.gotID)
+			}
+		})
+	}
+}
+
+func TestHandler_ListWebhookDeliveries(t *testing.T) {
+	webhooks := &fakeWebhooks{}
+	server := newTestServer(t, WithWebhooks(webhooks))
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/webhooks/webhook-1/deliveries?failed=true&cursor=12&limit=5", testToken, "")
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	want := application.WebhookDeliveriesRequest{FailedOnly: true, Cursor: "12", Limit: 5}
+	if webhooks.gotID != "webhook-1" || webhooks.gotDeliveries != want {
+		t.Errorf("ListDeliveries() = %q, %+v, want webhook-1, %+v", webhooks.gotID, webhooks.gotDeliveries, want)
+	}
+
+	var body webhookDeliveryPage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Deliveries) != 1 || body.NextCursor != "7" {
+		t.Fatalf("Response = %+v, want one delivery and a cursor", body)
+	}
+	if got := body.Deliveries[0]; got.StatusCode != http.StatusBadGateway || got.ResponseSnippet != "upstream down" || got.DurationMs != 1500 {
+		t.Errorf("delivery = %+v, want the status, response and latency", got)
+	}
+}
+
+func TestHandler_ListWebhookDeliveries_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+	}{
+		{name: "invalid failed", query: "?failed=maybe", wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: "?limit=ten", wantStatus: http.StatusBadRequest},
+		{name: "invalid cursor", err: domain.NewValidationError("cursor", "is invalid"), wantStatus: http.StatusBadRequest},
+		{name: "unknown", err: application.ErrWebhookNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, WithWebhooks(&fakeWebhooks{err: tt.err}))
+
+			resp := doRequest(t, http.MethodGet, server.URL+"/admin/webhooks/webhook-1/deliveries"+tt.query, testToken, "")
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_RedeliverWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		delivery   string
+		err        error
+		wantStatus int
+	}{
+		{name: "redelivered", delivery: "7", wantStatus: http.StatusOK},
+		{name: "invalid delivery ID", delivery: "seven", wantStatus: http.StatusNotFound},
+		{name: "unknown delivery", delivery: "7", err: application.ErrWebhookDeliveryNotFound, wantStatus: http.StatusNotFound},
+		{name: "no payload", delivery: "7", err: application.ErrWebhookNotRedeliverable, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := &fakeWebhooks{err: tt.err}
+			server := newTestServer(t, WithWebhooks(webhooks))
+
+			resp := doRequest(t, http.MethodPost, server.URL+"/admin/webhooks/webhook-1/deliveries/"+tt.delivery+"/redeliver", testToken, "")
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if webhooks.gotID != "webhook-1" || webhooks.gotDeliveryID != 7 {
+				t.Errorf("Redeliver() = %q, %d, want webhook-1, 7", webhooks.gotID, webhooks.gotDeliveryID)
+			}
+			var body webhookDelivery
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if body.ID != 8 || body.RedeliveryOf != 7 {
+				t.Errorf("Response = %+v, want the new attempt", body)
+			}
+		})
+	}
+}
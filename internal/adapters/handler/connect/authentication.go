@@ -0,0 +1,92 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// authInterceptor authenticates handler calls with Bearer tokens
+type authInterceptor struct {
+	authenticator auth.Authenticator
+}
+
+// AuthOption configures the credentials accepted by the auth interceptor
+type AuthOption func(*authInterceptor)
+
+// WithJWTs accepts Bearer JWTs checked by verifier
+func WithJWTs(verifier auth.TokenVerifier) AuthOption {
+	return func(i *authInterceptor) {
+		i.authenticator.JWTs = verifier
+	}
+}
+
+// WithAPIKeys accepts Bearer API keys, told apart from JWTs by
+// application.APIKeyPrefix
+func WithAPIKeys(keys auth.APIKeyAuthenticator) AuthOption {
+	return func(i *authInterceptor) {
+		i.authenticator.APIKeys = keys
+	}
+}
+
+// NewAuthInterceptor creates an interceptor requiring a valid Bearer token on
+// every call, answering CodeUnauthenticated otherwise
+// The token subject becomes the user ID of the call, and its scopes, when it
+// carries any, are checked by the scope interceptor placed after it; its
+// roles are checked by the application service
+func NewAuthInterceptor(opts ...AuthOption) connect.Interceptor {
+	i := authInterceptor{}
+	for _, opt := range opts {
+		opt(&i)
+	}
+	return i
+}
+
+// WrapUnary authenticates unary calls
+func (i authInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := i.authenticate(ctx, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves client streams untouched
+func (authInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler authenticates streaming calls before the first message
+func (i authInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authenticate(ctx, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// authenticate verifies the Bearer token of header and returns ctx carrying
+// the caller identity
+func (i authInterceptor) authenticate(ctx context.Context, header http.Header) (context.Context, error) {
+	ctx, err := i.authenticator.Authenticate(ctx, header.Get("Authorization"))
+	var credentialErr *auth.CredentialError
+	if errors.As(err, &credentialErr) && credentialErr.Unavailable {
+		return ctx, withRule(connect.NewError(connect.CodeUnavailable, err), application.RuleAuthUnavailable, nil)
+	}
+	if err != nil {
+		return ctx, withRule(connect.NewError(connect.CodeUnauthenticated, err), application.RuleUnauthenticated, nil)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,126 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// stubVerifier accepts the tokens it maps to an identity
+type stubVerifier map[string]auth.Identity
+
+func (v stubVerifier) Verify(_ context.Context, token string) (auth.Identity, error) {
+	if token == "unreachable" {
+		return auth.Identity{}, fmt.Errorf("%w: connection refused", auth.ErrKeySetUnavailable)
+	}
+	identity, ok := v[token]
+	if !ok {
+		return auth.Identity{}, fmt.Errorf("%w: bad signature", auth.ErrInvalidToken)
+	}
+	return identity, nil
+}
+
+// stubAPIKeys accepts the secrets it maps to a key
+type stubAPIKeys map[string]*application.APIKeyResponse
+
+func (k stubAPIKeys) Authenticate(_ context.Context, secret string) (*application.APIKeyResponse, error) {
+	key, ok := k[secret]
+	if !ok {
+		return nil, application.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func TestAuthInterceptor_Authenticate(t *testing.T) {
+	interceptor := NewAuthInterceptor(
+		WithJWTs(stubVerifier{
+			"alice-token": {Subject: "alice"},
+			"bob-token":   {Subject: "bob", Scopes: []string{"read"}, HasScopes: true},
+			"carol-token": {Subject: "carol", Roles: []string{"viewer"}, HasRoles: true},
+		}),
+		WithAPIKeys(stubAPIKeys{
+			"tdk_cron": {ID: "key-cron", UserID: "cron", Scopes: []string{"write"}},
+			"tdk_none": {ID: "key-none", UserID: "audit", Scopes: []string{}},
+		}),
+	).(authInterceptor)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      connect.Code
+		wantUser      string
+		wantScopes    []string
+		wantRoles     []string
+		wantKey       string
+	}{
+		{"no header", "", connect.CodeUnauthenticated, "", nil, nil, ""},
+		{"basic auth", "Basic dXNlcjpwYXNz", connect.CodeUnauthenticated, "", nil, nil, ""},
+		{"invalid token", "Bearer forged", connect.CodeUnauthenticated, "", nil, nil, ""},
+		{"key set down", "Bearer unreachable", connect.CodeUnavailable, "", nil, nil, ""},
+		{"token without scopes", "Bearer alice-token", 0, "alice", nil, nil, ""},
+		{"token with scopes", "Bearer bob-token", 0, "bob", []string{"read"}, nil, ""},
+		{"token with roles", "Bearer carol-token", 0, "carol", nil, []string{"viewer"}, ""},
+		{"API key", "Bearer tdk_cron", 0, "cron", []string{"write"}, nil, "key-cron"},
+		{"API key without scopes", "Bearer tdk_none", 0, "audit", []string{}, nil, "key-none"},
+		{"revoked API key", "Bearer tdk_revoked", connect.CodeUnauthenticated, "", nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.authorization != "" {
+				header.Set("Authorization", tt.authorization)
+			}
+
+			ctx, err := interceptor.authenticate(context.Background(), header)
+			if tt.wantCode != 0 {
+				var connectErr *connect.Error
+				if !errors.As(err, &connectErr) || connectErr.Code() != tt.wantCode {
+					t.Fatalf("authenticate() error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authenticate() unexpected error: %v", err)
+			}
+
+			if userID, _ := application.UserIDFromContext(ctx); userID != tt.wantUser {
+				t.Errorf("user ID = %q, want %q", userID, tt.wantUser)
+			}
+			scopes, ok := application.ScopesFromContext(ctx)
+			if ok != (tt.wantScopes != nil) || !slices.Equal(scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v (%v), want %v", scopes, ok, tt.wantScopes)
+			}
+			if keyID, _ := application.APIKeyIDFromContext(ctx); keyID != tt.wantKey {
+				t.Errorf("API key ID = %q, want %q", keyID, tt.wantKey)
+			}
+			roles, ok := application.RolesFromContext(ctx)
+			if ok != (tt.wantRoles != nil) || !slices.Equal(roles, tt.wantRoles) {
+				t.Errorf("roles = %v (%v), want %v", roles, ok, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_RejectsDisabledCredentials(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer tdk_cron")
+	jwtOnly := NewAuthInterceptor(WithJWTs(stubVerifier{})).(authInterceptor)
+	if _, err := jwtOnly.authenticate(context.Background(), header); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("API key without WithAPIKeys: error = %v, want unauthenticated", err)
+	}
+
+	header.Set("Authorization", "Bearer alice-token")
+	keysOnly := NewAuthInterceptor(WithAPIKeys(stubAPIKeys{})).(authInterceptor)
+	if _, err := keysOnly.authenticate(context.Background(), header); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("JWT without WithJWTs: error = %v, want unauthenticated", err)
+	}
+}
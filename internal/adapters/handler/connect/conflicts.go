@@ -0,0 +1,65 @@
+package connect
+
+import (
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// editConflictError maps an edit conflict to CodeAborted, detailed with a
+// google.protobuf.Struct for clients presenting a merge UI:
+//
+//	{"rule": "todo.edit_conflict", "strategy": "merge", "current_etag": "...", "current": {todo fields},
+//	 "fields": [{"field": "title", "base": "...", "current": "...", "requested": "..."}]}
+//
+// base is null when the version the client edited is unknown
+func editConflictError(conflict *application.EditConflictError, ruleID string) error {
+	connectErr := connect.NewError(connect.CodeAborted, conflict)
+
+	fields := make([]any, len(conflict.Fields))
+	for i, field := range conflict.Fields {
+		var base any
+		if field.Base != nil {
+			base = *field.Base
+		}
+		fields[i] = map[string]any{
+			"field":     field.Field,
+			"base":      base,
+			"current":   field.Current,
+			"requested": field.Requested,
+		}
+	}
+
+	current := conflict.Current
+	var dueDate any
+	if current.DueDate != nil {
+		dueDate = current.DueDate.UTC().Format(time.RFC3339Nano)
+	}
+	details, err := structpb.NewStruct(map[string]any{
+		"rule":         ruleID,
+		"strategy":     conflict.Strategy,
+		"current_etag": todoETag(current),
+		"current": map[string]any{
+			"id":          current.ID,
+			"title":       current.Title,
+			"description": current.Description,
+			"status":      current.Status,
+			"priority":    current.Priority,
+			"due_date":    dueDate,
+			"updated_at":  current.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		},
+		"fields": fields,
+	})
+	if err != nil {
+		return connectErr
+	}
+
+	if detail, err := connect.NewErrorDetail(details); err == nil {
+		connectErr.AddDetail(detail)
+	}
+	connectErr.Meta().Set("ETag", todoETag(current))
+	return connectErr
+}
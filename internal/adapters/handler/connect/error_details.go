@@ -0,0 +1,100 @@
+package connect
+
+import (
+	"errors"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// invalidFields maps the domain errors of invalid values to the field they
+// are about
+var invalidFields = []struct {
+	err   error
+	field string
+}{
+	{domain.ErrInvalidTitle, "title"},
+	{domain.ErrInvalidDueDate, "due_date"},
+	{domain.ErrInvalidPriority, "priority"},
+	{domain.ErrInvalidStatus, "status"},
+	{domain.ErrInvalidID, "id"},
+}
+
+// invalidArgument returns the field and message of the validation error err
+// is, and false when it is none
+func invalidArgument(err error) (field, message string, ok bool) {
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Field, validationErr.Message, true
+	}
+	var validationPtr *domain.ValidationError
+	if errors.As(err, &validationPtr) {
+		return validationPtr.Field, validationPtr.Message, true
+	}
+	for _, invalid := range invalidFields {
+		if errors.Is(err, invalid.err) {
+			return invalid.field, invalid.err.Error(), true
+		}
+	}
+	return "", "", false
+}
+
+// ruleError maps err to the Connect code of its rule in the error catalog,
+// detailed with a google.protobuf.Struct of the rule ID and fields:
+//
+//	{"rule": "todo.not_found"}
+func ruleError(err error, rule application.ErrorRule, fields map[string]any) *connect.Error {
+	var code connect.Code
+	if code.UnmarshalText([]byte(rule.Code)) != nil {
+		code = connect.CodeInternal
+	}
+	return withRule(connect.NewError(code, err), rule.ID, fields)
+}
+
+// withRule details connectErr with a google.protobuf.Struct of the rule ID
+// and fields, for the errors whose code is set outside the catalog
+func withRule(connectErr *connect.Error, ruleID string, fields map[string]any) *connect.Error {
+	details := map[string]any{"rule": ruleID}
+	for name, value := range fields {
+		details[name] = value
+	}
+	return withDetail(connectErr, details)
+}
+
+// validationError maps a validation error to CodeInvalidArgument, detailed
+// naming the invalid field:
+//
+//	{"rule": "request.invalid_field", "field": "title", "message": "..."}
+func validationError(err error, rule application.ErrorRule, field, message string) error {
+	return ruleError(err, rule, map[string]any{
+		"field":   field,
+		"message": message,
+	})
+}
+
+// quotaError maps a spent quota to CodeResourceExhausted, detailed naming
+// the quota:
+//
+//	{"rule": "quota.expensive_queries", "quota": "expensive_queries" | "concurrent_queries"}
+func quotaError(err error, rule application.ErrorRule) error {
+	return ruleError(err, rule, map[string]any{
+		"quota": strings.TrimPrefix(rule.ID, "quota."),
+	})
+}
+
+// withDetail adds fields to connectErr as a google.protobuf.Struct detail,
+// leaving it undetailed when they cannot be encoded
+func withDetail(connectErr *connect.Error, fields map[string]any) *connect.Error {
+	details, err := structpb.NewStruct(fields)
+	if err != nil {
+		return connectErr
+	}
+	if detail, err := connect.NewErrorDetail(details); err == nil {
+		connectErr.AddDetail(detail)
+	}
+	return connectErr
+}
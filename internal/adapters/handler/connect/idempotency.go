@@ -0,0 +1,52 @@
+package connect
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+// IdempotentHeader is set on every response (and error) of a procedure that
+// can safely be retried by clients
+const IdempotentHeader = "Todo-Idempotent"
+
+// idempotentProcedures lists the TodoService procedures whose repeated
+// execution has the same effect as a single one
+var idempotentProcedures = map[string]bool{
+	todov1connect.TodoServiceGetTodoProcedure:      true,
+	todov1connect.TodoServiceListTodosProcedure:    true,
+	todov1connect.TodoServiceCompleteTodoProcedure: true, // Completing twice is a no-op
+	todov1connect.TodoServiceReopenTodoProcedure:   true, // Reopening twice is a no-op
+}
+
+// IsIdempotent reports whether a procedure can safely be retried
+func IsIdempotent(procedure string) bool {
+	return idempotentProcedures[procedure]
+}
+
+// NewIdempotencyInterceptor creates an interceptor that marks responses of
+// idempotent procedures so clients know they may retry them
+func NewIdempotencyInterceptor() connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if !IsIdempotent(req.Spec().Procedure) {
+				return next(ctx, req)
+			}
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				var connectErr *connect.Error
+				if errors.As(err, &connectErr) {
+					connectErr.Meta().Set(IdempotentHeader, "true")
+				}
+				return nil, err
+			}
+
+			resp.Header().Set(IdempotentHeader, "true")
+			return resp, nil
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package connect
+
+import (
+	"testing"
+
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		procedure string
+		want      bool
+	}{
+		{todov1connect.TodoServiceGetTodoProcedure, true},
+		{todov1connect.TodoServiceListTodosProcedure, true},
+		{todov1connect.TodoServiceCompleteTodoProcedure, true},
+		{todov1connect.TodoServiceReopenTodoProcedure, true},
+		{todov1connect.TodoServiceCreateTodoProcedure, false},
+		{todov1connect.TodoServiceUpdateTodoProcedure, false},
+		{todov1connect.TodoServiceDeleteTodoProcedure, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.procedure, func(t *testing.T) {
+			if got := IsIdempotent(tt.procedure); got != tt.want {
+				t.Errorf("IsIdempotent(%s) = %v, want %v", tt.procedure, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package connect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/grpcreflect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
+	"github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+// SchemaHandler serves the proto descriptors the server was built with, for
+// tooling and dynamic clients to speak its exact schema version
+type SchemaHandler struct {
+	binary []byte
+	json   []byte
+	etag   string
+}
+
+// NewSchemaHandler creates a SchemaHandler of the descriptors of the Todo
+// API, encoded once
+func NewSchemaHandler() (*SchemaHandler, error) {
+	files := schemaFiles(todov1.File_todo_v1_todo_proto)
+
+	binary, err := proto.MarshalOptions{Deterministic: true}.Marshal(files)
+	if err != nil {
+		return nil, fmt.Errorf("encoding schema: %w", err)
+	}
+	json, err := protojson.Marshal(files)
+	if err != nil {
+		return nil, fmt.Errorf("encoding schema: %w", err)
+	}
+	sum := sha256.Sum256(binary)
+
+	return &SchemaHandler{
+		binary: binary,
+		json:   json,
+		etag:   `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}, nil
+}
+
+// RegisterRoutes registers GET /api/schema and the gRPC server reflection
+// services, v1 and v1alpha, used by buf curl and grpcurl
+func (h *SchemaHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/schema", h.getSchema)
+
+	reflector := grpcreflect.NewStaticReflector(todov1connect.TodoServiceName)
+	mux.Handle(grpcreflect.NewHandlerV1(reflector))
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
+}
+
+// getSchema answers GET /api/schema?format=binpb|json with the
+// google.protobuf.FileDescriptorSet of the API, as `buf build` writes it
+// The ETag changes with the schema only
+func (h *SchemaHandler) getSchema(w http.ResponseWriter, r *http.Request) {
+	body, contentType := h.binary, "application/x-protobuf"
+	switch r.URL.Query().Get("format") {
+	case "", "binpb":
+	case "json":
+		body, contentType = h.json, "application/json"
+	default:
+		http.Error(w, "format must be binpb or json", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("ETag", h.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == h.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+// schemaFiles returns the descriptors of file and of the files it imports,
+// transitively, each after its imports
+func schemaFiles(file protoreflect.FileDescriptor) *descriptorpb.FileDescriptorSet {
+	files := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		if seen[file.Path()] || file.IsPlaceholder() {
+			return
+		}
+		seen[file.Path()] = true
+
+		imports := file.Imports()
+		for i := range imports.Len() {
+			add(imports.Get(i).FileDescriptor)
+		}
+		files.File = append(files.File, protodesc.ToFileDescriptorProto(file))
+	}
+	add(file)
+
+	return files
+}
@@ -0,0 +1,127 @@
+package connect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+// serveSchema serves req with the routes of a SchemaHandler
+func serveSchema(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler, err := NewSchemaHandler()
+	if err != nil {
+		t.Fatalf("NewSchemaHandler() failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// checkSchema checks that files declares the Todo service, each file after
+// the files it imports
+func checkSchema(t *testing.T, files *descriptorpb.FileDescriptorSet) {
+	t.Helper()
+
+	declared := make(map[string]bool)
+	var service bool
+	for _, file := range files.GetFile() {
+		for _, dependency := range file.GetDependency() {
+			if !declared[dependency] {
+				t.Errorf("%s comes before its import %s", file.GetName(), dependency)
+			}
+		}
+		declared[file.GetName()] = true
+		for _, svc := range file.GetService() {
+			if file.GetPackage()+"."+svc.GetName() == todov1connect.TodoServiceName {
+				service = true
+			}
+		}
+	}
+	if !service {
+		t.Errorf("schema does not declare %s", todov1connect.TodoServiceName)
+	}
+}
+
+func TestSchemaHandler_Formats(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		unmarshal   func(b []byte, m proto.Message) error
+	}{
+		{name: "binary by default", target: "/api/schema", contentType: "application/x-protobuf", unmarshal: proto.Unmarshal},
+		{name: "binpb", target: "/api/schema?format=binpb", contentType: "application/x-protobuf", unmarshal: proto.Unmarshal},
+		{name: "json", target: "/api/schema?format=json", contentType: "application/json", unmarshal: protojson.Unmarshal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveSchema(t, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if rec.Header().Get("ETag") == "" {
+				t.Error("ETag is missing")
+			}
+
+			var files descriptorpb.FileDescriptorSet
+			if err := tt.unmarshal(rec.Body.Bytes(), &files); err != nil {
+				t.Fatalf("decoding schema: %v", err)
+			}
+			checkSchema(t, &files)
+		})
+	}
+}
+
+func TestSchemaHandler_NotModified(t *testing.T) {
+	etag := serveSchema(t, httptest.NewRequest(http.MethodGet, "/api/schema", nil)).Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/schema", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := serveSchema(t, req)
+
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("Status = %d with %d bytes, want %d without body", rec.Code, rec.Body.Len(), http.StatusNotModified)
+	}
+}
+
+func TestSchemaHandler_InvalidFormat(t *testing.T) {
+	rec := serveSchema(t, httptest.NewRequest(http.MethodGet, "/api/schema?format=yaml", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSchemaHandler_Reflection(t *testing.T) {
+	mux := http.NewServeMux()
+	handler, err := NewSchemaHandler()
+	if err != nil {
+		t.Fatalf("NewSchemaHandler() failed: %v", err)
+	}
+	handler.RegisterRoutes(mux)
+
+	for _, path := range []string{
+		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	} {
+		if _, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil)); pattern == "" {
+			t.Errorf("%s is not served", path)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"connectrpc.com/connect"
+
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// Scope is a permission granted to a caller's token
+type Scope string
+
+const (
+	// ScopeRead allows reading and listing todos
+	ScopeRead Scope = "read"
+	// ScopeWrite allows creating, changing and deleting todos
+	ScopeWrite Scope = "write"
+	// ScopeAdmin grants every other scope
+	ScopeAdmin Scope = "admin"
+)
+
+// procedureScopes lists the scope each TodoService procedure requires
+// Procedures missing from it require ScopeAdmin, so a new RPC stays closed
+// to regular tokens until it is annotated here
+var procedureScopes = map[string]Scope{
+	todov1connect.TodoServiceGetTodoProcedure:      ScopeRead,
+	todov1connect.TodoServiceListTodosProcedure:    ScopeRead,
+	todov1connect.TodoServiceCreateTodoProcedure:   ScopeWrite,
+	todov1connect.TodoServiceUpdateTodoProcedure:   ScopeWrite,
+	todov1connect.TodoServiceCompleteTodoProcedure: ScopeWrite,
+	todov1connect.TodoServiceReopenTodoProcedure:   ScopeWrite,
+	todov1connect.TodoServiceDeleteTodoProcedure:   ScopeWrite,
+}
+
+// RequiredScope returns the scope a procedure requires
+func RequiredScope(procedure string) Scope {
+	if scope, ok := procedureScopes[procedure]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// HasScope reports whether granted scopes allow what required protects
+func HasScope(granted []string, required Scope) bool {
+	return slices.Contains(granted, string(required)) || slices.Contains(granted, string(ScopeAdmin))
+}
+
+// scopeInterceptor enforces procedureScopes on handlers
+type scopeInterceptor struct{}
+
+// NewScopeInterceptor creates an interceptor rejecting calls whose token
+// lacks the scope required by the procedure, with CodePermissionDenied
+// Calls carrying no scopes (see application.ScopesFromContext) are not
+// checked: their authentication mechanism does not grant scopes
+func NewScopeInterceptor() connect.Interceptor {
+	return scopeInterceptor{}
+}
+
+// WrapUnary checks unary calls
+func (scopeInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := checkScope(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves client streams untouched
+func (scopeInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler checks streaming calls before the first message
+func (scopeInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := checkScope(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// checkScope returns a permission denied error when the caller's scopes do
+// not cover procedure
+func checkScope(ctx context.Context, procedure string) error {
+	granted, ok := application.ScopesFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	required := RequiredScope(procedure)
+	if !HasScope(granted, required) {
+		return withRule(connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s requires the %s scope", procedure, required)), application.RuleMissingScope, nil)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package connect
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		procedure string
+		want      Scope
+	}{
+		{todov1connect.TodoServiceGetTodoProcedure, ScopeRead},
+		{todov1connect.TodoServiceListTodosProcedure, ScopeRead},
+		{todov1connect.TodoServiceCreateTodoProcedure, ScopeWrite},
+		{todov1connect.TodoServiceDeleteTodoProcedure, ScopeWrite},
+		{"/todo.v1.TodoService/Unannotated", ScopeAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.procedure, func(t *testing.T) {
+			if got := RequiredScope(tt.procedure); got != tt.want {
+				t.Errorf("RequiredScope(%s) = %v, want %v", tt.procedure, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	withScopes := func(scopes ...string) context.Context {
+		return application.ContextWithScopes(context.Background(), scopes)
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		procedure string
+		allowed   bool
+	}{
+		{"no scope mechanism", context.Background(), todov1connect.TodoServiceDeleteTodoProcedure, true},
+		{"read token reads", withScopes("read"), todov1connect.TodoServiceGetTodoProcedure, true},
+		{"read token writes", withScopes("read"), todov1connect.TodoServiceUpdateTodoProcedure, false},
+		{"write token writes", withScopes("read", "write"), todov1connect.TodoServiceUpdateTodoProcedure, true},
+		{"admin token", withScopes("admin"), todov1connect.TodoServiceDeleteTodoProcedure, true},
+		{"no scopes granted", withScopes(), todov1connect.TodoServiceListTodosProcedure, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScope(tt.ctx, tt.procedure)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("checkScope() unexpected error: %v", err)
+				}
+				return
+			}
+			if connect.CodeOf(err) != connect.CodePermissionDenied {
+				t.Errorf("checkScope() error = %v, want permission denied", err)
+			}
+		})
+	}
+}
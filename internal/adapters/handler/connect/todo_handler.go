@@ -3,15 +3,65 @@ package connect
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
 	"github.com/pivaldi/mmw/todo/internal/application"
-	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
 )
 
+// ShortCodeHeader carries the human-friendly short code (e.g. TD-1042) of the
+// returned todo, which the v1 Todo message has no field for
+const ShortCodeHeader = "Todo-Short-Code"
+
+// ConflictStrategyHeader selects how UpdateTodo settles the changes made
+// since the version named by If-Match: reject (the default),
+// last_writer_wins or merge
+const ConflictStrategyHeader = "Todo-Conflict-Strategy"
+
+// ArchivedHeader selects whether ListTodos returns archived todos, which the
+// v1 ListTodosRequest has no field for: exclude (the default), only or
+// include
+const ArchivedHeader = "Todo-Archived"
+
+// DescriptionHeader selects how ListTodos returns the descriptions, which
+// the v1 ListTodosRequest has no field for: full (the default), omit, or the
+// number of characters to truncate them to
+const DescriptionHeader = "Todo-Description"
+
+// SortHeader selects the field ListTodos sorts by, which the v1
+// ListTodosRequest has no field for: created_at (the default), updated_at,
+// due_date, priority or title
+const SortHeader = "Todo-Sort"
+
+// SortOrderHeader selects the order of SortHeader: asc or desc, defaulting
+// to asc for due_date and title and to desc otherwise
+const SortOrderHeader = "Todo-Sort-Order"
+
+// MoreDescriptionHeader lists, once per todo, the IDs of the todos whose
+// description ListTodos omitted or truncated, the has_more_description flag
+// the v1 Todo message has no field for
+const MoreDescriptionHeader = "Todo-More-Description"
+
+// QueryWarningHeader tells, once per warning, how ListTodos rewrote a query
+// too costly for the database, which the v1 ListTodosResponse has no field
+// for
+const QueryWarningHeader = "Todo-Query-Warning"
+
+// EditLockHolderHeader names the user editing the todo returned by GetTodo,
+// the holder of its edit lock, which the v1 Todo message has no field for
+const EditLockHolderHeader = "Todo-Edit-Lock-Holder"
+
+// EditLockExpiresHeader tells when the edit lock named by
+// EditLockHolderHeader expires, in RFC 3339
+const EditLockExpiresHeader = "Todo-Edit-Lock-Expires"
+
 // TodoHandler implements the Connect TodoServiceHandler interface
 // It bridges HTTP/gRPC requests to the application service
 type TodoHandler struct {
@@ -50,11 +100,12 @@ func (h *TodoHandler) CreateTodo(
 	}
 
 	// Convert response to protobuf
-	response := &todov1.CreateTodoResponse{
+	response := connect.NewResponse(&todov1.CreateTodoResponse{
 		Todo: mapTodoToProto(todo),
-	}
+	})
+	setShortCodeHeader(response.Header(), todo)
 
-	return connect.NewResponse(response), nil
+	return response, nil
 }
 
 // GetTodo retrieves a todo by ID
@@ -67,11 +118,24 @@ func (h *TodoHandler) GetTodo(
 		return nil, mapDomainError(err)
 	}
 
-	response := &todov1.GetTodoResponse{
-		Todo: mapTodoToProto(todo),
+	// Conditional request: an unchanged todo is answered without a body
+	etag := todoETag(todo)
+	if req.Header().Get("If-None-Match") == etag {
+		response := connect.NewResponse(&todov1.GetTodoResponse{})
+		response.Header().Set("ETag", etag)
+		setShortCodeHeader(response.Header(), todo)
+		setEditLockHeaders(response.Header(), todo)
+		return response, nil
 	}
 
-	return connect.NewResponse(response), nil
+	response := connect.NewResponse(&todov1.GetTodoResponse{
+		Todo: mapTodoToProto(todo),
+	})
+	response.Header().Set("ETag", etag)
+	setShortCodeHeader(response.Header(), todo)
+	setEditLockHeaders(response.Header(), todo)
+
+	return response, nil
 }
 
 // UpdateTodo updates an existing todo
@@ -105,17 +169,29 @@ func (h *TodoHandler) UpdateTodo(
 		appReq.DueDate = &dueDate
 	}
 
+	// Conditional update: If-Match holds the ETag of the version edited
+	if ifMatch := req.Header().Get("If-Match"); ifMatch != "" {
+		base, err := parseTodoETag(ifMatch)
+		if err != nil {
+			return nil, withRule(connect.NewError(connect.CodeInvalidArgument, err), application.RuleInvalidETag, nil)
+		}
+		appReq.BaseUpdatedAt = &base
+	}
+	appReq.ConflictStrategy = req.Header().Get(ConflictStrategyHeader)
+
 	// Call application service
 	todo, err := h.service.UpdateTodo(ctx, req.Msg.Id, appReq)
 	if err != nil {
 		return nil, mapDomainError(err)
 	}
 
-	response := &todov1.UpdateTodoResponse{
+	response := connect.NewResponse(&todov1.UpdateTodoResponse{
 		Todo: mapTodoToProto(todo),
-	}
+	})
+	response.Header().Set("ETag", todoETag(todo))
+	setShortCodeHeader(response.Header(), todo)
 
-	return connect.NewResponse(response), nil
+	return response, nil
 }
 
 // CompleteTodo marks a todo as completed
@@ -195,6 +271,22 @@ func (h *TodoHandler) ListTodos(
 		filters.Priority = &priority
 	}
 
+	if archived := req.Header().Get(ArchivedHeader); archived != "" {
+		filters.Archived = &archived
+	}
+
+	if description := req.Header().Get(DescriptionHeader); description != "" {
+		filters.Description = &description
+	}
+
+	if sortBy := req.Header().Get(SortHeader); sortBy != "" {
+		filters.SortBy = &sortBy
+	}
+
+	if sortOrder := req.Header().Get(SortOrderHeader); sortOrder != "" {
+		filters.SortOrder = &sortOrder
+	}
+
 	// Call application service
 	result, err := h.service.ListTodos(ctx, filters)
 	if err != nil {
@@ -207,12 +299,56 @@ func (h *TodoHandler) ListTodos(
 		protoTodos[i] = mapTodoToProto(todo)
 	}
 
-	response := &todov1.ListTodosResponse{
+	response := connect.NewResponse(&todov1.ListTodosResponse{
 		Todos:      protoTodos,
 		TotalCount: int32(result.TotalCount),
+	})
+	for _, warning := range result.Warnings {
+		response.Header().Add(QueryWarningHeader, warning)
+	}
+	for _, todo := range result.Todos {
+		if todo.HasMoreDescription {
+			response.Header().Add(MoreDescriptionHeader, todo.ID)
+		}
 	}
 
-	return connect.NewResponse(response), nil
+	return response, nil
+}
+
+// todoETag derives an entity tag identifying the current version of a todo
+func todoETag(todo *application.TodoResponse) string {
+	return fmt.Sprintf(`"%s-%d"`, todo.ID, todo.UpdatedAt.UnixNano())
+}
+
+// parseTodoETag returns the UpdatedAt of the version named by a todoETag
+func parseTodoETag(etag string) (time.Time, error) {
+	invalid := fmt.Errorf("If-Match is not an ETag of a todo: %s", etag)
+	dash := strings.LastIndex(etag, "-")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' || dash < 0 {
+		return time.Time{}, invalid
+	}
+
+	nanos, err := strconv.ParseInt(etag[dash+1:len(etag)-1], 10, 64)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// setShortCodeHeader exposes the short code of todo, when it has one
+func setShortCodeHeader(header http.Header, todo *application.TodoResponse) {
+	if todo.ShortCode != "" {
+		header.Set(ShortCodeHeader, todo.ShortCode)
+	}
+}
+
+// setEditLockHeaders exposes who is editing the todo, if anyone
+// The lock is not part of the ETag, so an unchanged todo still tells it
+func setEditLockHeaders(header http.Header, todo *application.TodoResponse) {
+	if todo.EditLock != nil {
+		header.Set(EditLockHolderHeader, todo.EditLock.HolderID)
+		header.Set(EditLockExpiresHeader, todo.EditLock.ExpiresAt.UTC().Format(time.RFC3339))
+	}
 }
 
 // mapTodoToProto converts an application TodoResponse to protobuf Todo
@@ -298,29 +434,32 @@ func mapPriorityFromProto(priority todov1.Priority) string {
 	}
 }
 
-// mapDomainError converts domain errors to Connect errors with appropriate codes
+// mapDomainError converts domain errors to Connect errors with the code of
+// their rule in the error catalog, detailed with the rule ID
 func mapDomainError(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	// Check for domain-specific errors
-	if errors.Is(err, domain.ErrTodoNotFound) {
-		return connect.NewError(connect.CodeNotFound, err)
-	}
+	rule := application.ErrorRuleOf(err)
 
-	// Check for validation errors
-	var validationErr *domain.ValidationError
-	if errors.As(err, &validationErr) {
-		return connect.NewError(connect.CodeInvalidArgument, err)
+	// The todo changed since the version the client edited
+	var conflict *application.EditConflictError
+	if errors.As(err, &conflict) {
+		return editConflictError(conflict, rule.ID)
 	}
 
-	// Check for business rule errors
-	var businessErr *domain.BusinessRuleError
-	if errors.As(err, &businessErr) {
-		return connect.NewError(connect.CodeFailedPrecondition, err)
+	switch rule.Code {
+	case "invalid_argument":
+		// Validation errors are detailed with the invalid field
+		if field, message, ok := invalidArgument(err); ok {
+			return validationError(err, rule, field, message)
+		}
+	case "resource_exhausted":
+		// The caller spent its budget of expensive queries, or the owner
+		// already runs as many heavy queries as allowed
+		return quotaError(err, rule)
 	}
 
-	// Default to internal error
-	return connect.NewError(connect.CodeInternal, err)
+	return ruleError(err, rule, nil)
 }
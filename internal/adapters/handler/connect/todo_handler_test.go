@@ -3,15 +3,19 @@ package connect
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
 	"github.com/pivaldi/mmw/todo/internal/application"
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
 )
 
 // MockTodoService is a mock implementation of application.TodoService
@@ -198,6 +202,114 @@ func TestTodoHandler_GetTodo_Success(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_GetTodo_MatchingETag_ReturnsEmptyBody(t *testing.T) {
+	updatedAt := time.Now()
+	mockService := &MockTodoService{
+		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return &application.TodoResponse{
+				ID:        "123",
+				Title:     "Test Todo",
+				Status:    "pending",
+				Priority:  "medium",
+				CreatedAt: updatedAt,
+				UpdatedAt: updatedAt,
+			}, nil
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	first, err := handler.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+	if err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header to be set")
+	}
+
+	req := connect.NewRequest(&todov1.GetTodoRequest{Id: "123"})
+	req.Header().Set("If-None-Match", etag)
+
+	resp, err := handler.GetTodo(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	if resp.Msg.Todo != nil {
+		t.Error("Expected no todo in body for a matching ETag")
+	}
+
+	if resp.Header().Get("ETag") != etag {
+		t.Errorf("ETag = %v, want %v", resp.Header().Get("ETag"), etag)
+	}
+}
+
+func TestTodoHandler_GetTodo_ExposesShortCode(t *testing.T) {
+	mockService := &MockTodoService{
+		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return &application.TodoResponse{
+				ID:        "123",
+				ShortCode: "TD-1042",
+				Title:     "Test Todo",
+				Status:    "pending",
+				Priority:  "medium",
+			}, nil
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	resp, err := handler.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "TD-1042"}))
+	if err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	if got := resp.Header().Get(ShortCodeHeader); got != "TD-1042" {
+		t.Errorf("%s = %q, want %q", ShortCodeHeader, got, "TD-1042")
+	}
+}
+
+func TestTodoHandler_GetTodo_ExposesEditLock(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	mockService := &MockTodoService{
+		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return &application.TodoResponse{
+				ID:       "123",
+				Title:    "Test Todo",
+				Status:   "pending",
+				Priority: "medium",
+				EditLock: &application.EditLockResponse{TodoID: "123", HolderID: "alice", ExpiresAt: expiresAt},
+			}, nil
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	// An unchanged todo still tells who is editing it
+	etag := ""
+	for range 2 {
+		req := connect.NewRequest(&todov1.GetTodoRequest{Id: "123"})
+		if etag != "" {
+			req.Header().Set("If-None-Match", etag)
+		}
+
+		resp, err := handler.GetTodo(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GetTodo() unexpected error: %v", err)
+		}
+		etag = resp.Header().Get("ETag")
+
+		if got := resp.Header().Get(EditLockHolderHeader); got != "alice" {
+			t.Errorf("%s = %q, want %q", EditLockHolderHeader, got, "alice")
+		}
+		if got := resp.Header().Get(EditLockExpiresHeader); got != "2026-10-18T09:30:00Z" {
+			t.Errorf("%s = %q, want %q", EditLockExpiresHeader, got, "2026-10-18T09:30:00Z")
+		}
+	}
+}
+
 func TestTodoHandler_GetTodo_NotFound_ReturnsNotFoundError(t *testing.T) {
 	mockService := &MockTodoService{
 		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
@@ -266,6 +378,80 @@ func TestTodoHandler_UpdateTodo_Success(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_UpdateTodo_IfMatch(t *testing.T) {
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 123456000, time.UTC)
+	var got application.UpdateTodoRequest
+	mockService := &MockTodoService{
+		UpdateTodoFunc: func(ctx context.Context, id string, req application.UpdateTodoRequest) (*application.TodoResponse, error) {
+			got = req
+			return &application.TodoResponse{ID: id, Title: "Updated", UpdatedAt: edited.Add(time.Minute)}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	title := "Updated"
+	req := connect.NewRequest(&todov1.UpdateTodoRequest{Id: "123", Title: &title})
+	req.Header().Set("If-Match", todoETag(&application.TodoResponse{ID: "0b6e4a4e-5f1c-4c47-9d61-3f0c1f6f7c11", UpdatedAt: edited}))
+	req.Header().Set(ConflictStrategyHeader, application.ConflictMerge)
+
+	resp, err := handler.UpdateTodo(context.Background(), req)
+	if err != nil {
+		t.Fatalf("UpdateTodo() unexpected error: %v", err)
+	}
+	if got.BaseUpdatedAt == nil || !got.BaseUpdatedAt.Equal(edited) || got.ConflictStrategy != application.ConflictMerge {
+		t.Errorf("request base, strategy = %v, %q, want %v, merge", got.BaseUpdatedAt, got.ConflictStrategy, edited)
+	}
+	if resp.Header().Get("ETag") == "" {
+		t.Error("Expected the ETag of the updated todo")
+	}
+
+	req.Header().Set("If-Match", "*")
+	if _, err := handler.UpdateTodo(context.Background(), req); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("UpdateTodo(If-Match: *) error = %v, want invalid argument", err)
+	}
+}
+
+func TestTodoHandler_UpdateTodo_Conflict_ReturnsDetails(t *testing.T) {
+	base := "Whole"
+	mockService := &MockTodoService{
+		UpdateTodoFunc: func(ctx context.Context, id string, req application.UpdateTodoRequest) (*application.TodoResponse, error) {
+			return nil, &application.EditConflictError{
+				Strategy: application.ConflictMerge,
+				Current:  &application.TodoResponse{ID: id, Title: "Buy milk", Description: "Skimmed", UpdatedAt: time.Now()},
+				Fields:   []application.FieldConflict{{Field: "description", Base: &base, Current: "Skimmed", Requested: "Oat"}},
+			}
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.UpdateTodo(context.Background(), connect.NewRequest(&todov1.UpdateTodoRequest{Id: "123"}))
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeAborted {
+		t.Fatalf("UpdateTodo() error = %v, want aborted", err)
+	}
+	if connectErr.Meta().Get("ETag") == "" {
+		t.Error("Expected the ETag of the current todo")
+	}
+	if len(connectErr.Details()) != 1 {
+		t.Fatalf("Details() = %d, want 1", len(connectErr.Details()))
+	}
+	detail, err := connectErr.Details()[0].Value()
+	if err != nil {
+		t.Fatalf("decoding detail: %v", err)
+	}
+	details, ok := detail.(*structpb.Struct)
+	if !ok {
+		t.Fatalf("detail is a %T, want a Struct", detail)
+	}
+	fields := details.Fields["fields"].GetListValue().GetValues()
+	if len(fields) != 1 || fields[0].GetStructValue().Fields["requested"].GetStringValue() != "Oat" {
+		t.Errorf("conflicting fields = %v, want the description", fields)
+	}
+	if got := details.Fields["current"].GetStructValue().Fields["description"].GetStringValue(); got != "Skimmed" {
+		t.Errorf("current description = %q, want Skimmed", got)
+	}
+}
+
 func TestTodoHandler_CompleteTodo_Success(t *testing.T) {
 	mockService := &MockTodoService{
 		CompleteTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
@@ -414,6 +600,151 @@ func TestTodoHandler_ListTodos_Success(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_ListTodos_ArchivedHeader(t *testing.T) {
+	var got *string
+	mockService := &MockTodoService{
+		ListTodosFunc: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+			got = filters.Archived
+			return &application.ListTodosResponse{}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	if _, err := handler.ListTodos(context.Background(), connect.NewRequest(&todov1.ListTodosRequest{})); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Errorf("Archived filter without header = %q, want none", *got)
+	}
+
+	req := connect.NewRequest(&todov1.ListTodosRequest{})
+	req.Header().Set(ArchivedHeader, "only")
+	if _, err := handler.ListTodos(context.Background(), req); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got == nil || *got != "only" {
+		t.Errorf("Archived filter = %v, want only", got)
+	}
+}
+
+func TestTodoHandler_ListTodos_DescriptionHeaders(t *testing.T) {
+	var got *string
+	mockService := &MockTodoService{
+		ListTodosFunc: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+			got = filters.Description
+			return &application.ListTodosResponse{Todos: []*application.TodoResponse{
+				{ID: "aaa", Description: "Long", HasMoreDescription: true},
+				{ID: "bbb"},
+				{ID: "ccc", HasMoreDescription: true},
+			}}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	req := connect.NewRequest(&todov1.ListTodosRequest{})
+	req.Header().Set(DescriptionHeader, "omit")
+	resp, err := handler.ListTodos(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got == nil || *got != "omit" {
+		t.Errorf("Description filter = %v, want omit", got)
+	}
+	if more := resp.Header().Values(MoreDescriptionHeader); len(more) != 2 || more[0] != "aaa" || more[1] != "ccc" {
+		t.Errorf("%s = %v, want [aaa ccc]", MoreDescriptionHeader, more)
+	}
+}
+
+func TestTodoHandler_ListTodos_SortHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		wantCode  connect.Code
+	}{
+		{name: "no sort"},
+		{name: "field", sortBy: "due_date"},
+		{name: "field and order", sortBy: "priority", sortOrder: "asc"},
+		{name: "unknown field", sortBy: "color", wantCode: connect.CodeInvalidArgument},
+		{name: "unknown order", sortBy: "title", sortOrder: "up", wantCode: connect.CodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got application.ListFilters
+			mockService := &MockTodoService{
+				ListTodosFunc: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+					got = filters
+					if filters.SortBy != nil && *filters.SortBy == "color" {
+						return nil, domain.NewValidationError("sort_by", "must be one of created_at, updated_at, due_date, priority or title")
+					}
+					if filters.SortOrder != nil && *filters.SortOrder == "up" {
+						return nil, domain.NewValidationError("sort_order", "must be asc or desc")
+					}
+					return &application.ListTodosResponse{}, nil
+				},
+			}
+			handler := NewTodoHandler(mockService)
+
+			req := connect.NewRequest(&todov1.ListTodosRequest{})
+			if tt.sortBy != "" {
+				req.Header().Set(SortHeader, tt.sortBy)
+			}
+			if tt.sortOrder != "" {
+				req.Header().Set(SortOrderHeader, tt.sortOrder)
+			}
+			_, err := handler.ListTodos(context.Background(), req)
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Errorf("ListTodos() code = %v, want %v", connect.CodeOf(err), tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTodos() unexpected error: %v", err)
+			}
+
+			if (got.SortBy == nil) != (tt.sortBy == "") || got.SortBy != nil && *got.SortBy != tt.sortBy {
+				t.Errorf("SortBy = %v, want %q", got.SortBy, tt.sortBy)
+			}
+			if (got.SortOrder == nil) != (tt.sortOrder == "") || got.SortOrder != nil && *got.SortOrder != tt.sortOrder {
+				t.Errorf("SortOrder = %v, want %q", got.SortOrder, tt.sortOrder)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_ListTodos_QueryWarnings(t *testing.T) {
+	mockService := &MockTodoService{
+		ListTodosFunc: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+			return &application.ListTodosResponse{Warnings: []string{"limit 5000 lowered to 500"}}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	resp, err := handler.ListTodos(context.Background(), connect.NewRequest(&todov1.ListTodosRequest{}))
+	if err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got := resp.Header().Values(QueryWarningHeader); len(got) != 1 || got[0] != "limit 5000 lowered to 500" {
+		t.Errorf("%s = %q, want the warning", QueryWarningHeader, got)
+	}
+}
+
+func TestTodoHandler_ListTodos_Throttled_ReturnsResourceExhausted(t *testing.T) {
+	mockService := &MockTodoService{
+		ListTodosFunc: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+			return nil, fmt.Errorf("%w: retry in 30s", application.ErrQueryThrottled)
+		},
+	}
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.ListTodos(context.Background(), connect.NewRequest(&todov1.ListTodosRequest{}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("ListTodos() code = %v, want %v", connect.CodeOf(err), connect.CodeResourceExhausted)
+	}
+}
+
 func TestTodoHandler_ValidationError_ReturnsInvalidArgument(t *testing.T) {
 	mockService := &MockTodoService{
 		CreateTodoFunc: func(ctx context.Context, req application.CreateTodoRequest) (*application.TodoResponse, error) {
@@ -447,6 +778,86 @@ func TestTodoHandler_ValidationError_ReturnsInvalidArgument(t *testing.T) {
 	}
 }
 
+func TestMapDomainError_Details(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   connect.Code
+		detail map[string]any
+	}{
+		{
+			name:   "validation error",
+			err:    fmt.Errorf("listing: %w", domain.NewValidationError("sort_by", "is unknown")),
+			code:   connect.CodeInvalidArgument,
+			detail: map[string]any{"rule": "request.invalid_field", "field": "sort_by", "message": "is unknown"},
+		},
+		{
+			name:   "invalid title",
+			err:    domain.ErrInvalidTitle,
+			code:   connect.CodeInvalidArgument,
+			detail: map[string]any{"rule": "todo.invalid_title", "field": "title", "message": domain.ErrInvalidTitle.Error()},
+		},
+		{
+			name:   "throttled",
+			err:    fmt.Errorf("%w: retry in 30s", application.ErrQueryThrottled),
+			code:   connect.CodeResourceExhausted,
+			detail: map[string]any{"rule": "quota.expensive_queries", "quota": "expensive_queries"},
+		},
+		{
+			name:   "bulkhead full",
+			err:    bulkhead.ErrFull,
+			code:   connect.CodeResourceExhausted,
+			detail: map[string]any{"rule": "quota.concurrent_queries", "quota": "concurrent_queries"},
+		},
+		{
+			name:   "not found",
+			err:    fmt.Errorf("getting: %w", domain.ErrTodoNotFound),
+			code:   connect.CodeNotFound,
+			detail: map[string]any{"rule": "todo.not_found"},
+		},
+		{
+			name:   "completed",
+			err:    domain.ErrCannotModifyCompleted,
+			code:   connect.CodeFailedPrecondition,
+			detail: map[string]any{"rule": "todo.completed"},
+		},
+		{
+			name:   "unexpected",
+			err:    errors.New("boom"),
+			code:   connect.CodeInternal,
+			detail: map[string]any{"rule": application.RuleInternal},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connectErr *connect.Error
+			if !errors.As(mapDomainError(tt.err), &connectErr) {
+				t.Fatalf("mapDomainError() is not a connect.Error")
+			}
+			if connectErr.Code() != tt.code {
+				t.Errorf("Code() = %v, want %v", connectErr.Code(), tt.code)
+			}
+			if len(connectErr.Details()) != 1 {
+				t.Fatalf("Details() = %d, want 1", len(connectErr.Details()))
+			}
+			detail, err := connectErr.Details()[0].Value()
+			if err != nil {
+				t.Fatalf("Value() unexpected error: %v", err)
+			}
+			details, ok := detail.(*structpb.Struct)
+			if !ok {
+				t.Fatalf("detail = %T, want *structpb.Struct", detail)
+			}
+			for key, want := range tt.detail {
+				if got := details.AsMap()[key]; got != want {
+					t.Errorf("detail %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestTodoHandler_BusinessRuleError_ReturnsFailedPrecondition(t *testing.T) {
 	mockService := &MockTodoService{
 		CompleteTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
@@ -478,3 +889,76 @@ func TestTodoHandler_BusinessRuleError_ReturnsFailedPrecondition(t *testing.T) {
 		t.Errorf("Error code = %v, want %v", connectErr.Code(), connect.CodeFailedPrecondition)
 	}
 }
+
+func TestTodoHandler_ConcurrentModification_ReturnsAborted(t *testing.T) {
+	mockService := &MockTodoService{
+		UpdateTodoFunc: func(ctx context.Context, id string, req application.UpdateTodoRequest) (*application.TodoResponse, error) {
+			return nil, fmt.Errorf("saving todo: %w", domain.ErrConcurrentModification)
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.UpdateTodo(context.Background(), connect.NewRequest(&todov1.UpdateTodoRequest{Id: "123"}))
+
+	if connect.CodeOf(err) != connect.CodeAborted {
+		t.Errorf("Error code = %v, want %v", connect.CodeOf(err), connect.CodeAborted)
+	}
+}
+
+func TestTodoHandler_CircuitOpen_ReturnsUnavailable(t *testing.T) {
+	mockService := &MockTodoService{
+		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return nil, fmt.Errorf("finding todo: %w", circuitbreaker.ErrOpen)
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Errorf("Error code = %v, want %v", connect.CodeOf(err), connect.CodeUnavailable)
+	}
+}
+
+func TestTodoHandler_OwnerBulkheadFull_ReturnsResourceExhausted(t *testing.T) {
+	mockService := &MockTodoService{
+		GetTodoFunc: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return nil, fmt.Errorf("waiting for a query slot: %w", bulkhead.ErrFull)
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("Error code = %v, want %v", connect.CodeOf(err), connect.CodeResourceExhausted)
+	}
+}
+
+func TestTodoHandler_MaintenanceMode_ReturnsUnavailable(t *testing.T) {
+	mockService := &MockTodoService{
+		DeleteTodoFunc: func(ctx context.Context, id string) error {
+			return fmt.Errorf("%w: migrating", application.ErrMaintenanceMode)
+		},
+	}
+
+	handler := NewTodoHandler(mockService)
+
+	_, err := handler.DeleteTodo(context.Background(), connect.NewRequest(&todov1.DeleteTodoRequest{Id: "123"}))
+
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Errorf("Error code = %v, want %v", connect.CodeOf(err), connect.CodeUnavailable)
+	}
+}
+
+func TestErrorCatalog_ConnectCodes(t *testing.T) {
+	for _, rule := range application.ErrorCatalog() {
+		var code connect.Code
+		if err := code.UnmarshalText([]byte(rule.Code)); err != nil {
+			t.Errorf("rule %s: code %q is not a Connect code", rule.ID, rule.Code)
+		}
+	}
+}
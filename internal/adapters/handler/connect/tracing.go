@@ -0,0 +1,21 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+)
+
+// NewTracingInterceptor creates an interceptor timing the procedures of
+// traced requests as their service stage, for slow request dumps
+// It must come last, so the time of the other interceptors is left out
+func NewTracingInterceptor() connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			defer reqtrace.Start(ctx, reqtrace.StageService, req.Spec().Procedure)()
+			return next(ctx, req)
+		}
+	})
+}
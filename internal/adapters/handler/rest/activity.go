@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// activityEntry is the JSON representation of a change to a todo
+type activityEntry struct {
+	TodoID     string    `json:"todo_id"`
+	Title      string    `json:"title"`
+	Kind       string    `json:"kind"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// activityFeedResponse is the JSON representation of an activity feed page
+type activityFeedResponse struct {
+	Entries    []activityEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// listActivity answers GET /api/activity?cursor=<next_cursor>&limit=<n>
+func (h *Handler) listActivity(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListActivity(r.Context(), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapActivityFeed(page))
+}
+
+// mapActivityFeed converts an application ActivityFeedPage to its JSON representation
+func mapActivityFeed(page *application.ActivityFeedPage) activityFeedResponse {
+	entries := make([]activityEntry, len(page.Entries))
+	for i, entry := range page.Entries {
+		entries[i] = activityEntry{
+			TodoID:     entry.TodoID,
+			Title:      entry.Title,
+			Kind:       entry.Kind,
+			OccurredAt: entry.OccurredAt,
+		}
+	}
+
+	return activityFeedResponse{Entries: entries, NextCursor: page.NextCursor}
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_ListActivity_Success(t *testing.T) {
+	var gotCursor string
+	var gotLimit int
+	service := &fakeService{
+		listActivity: func(ctx context.Context, cursor string, limit int) (*application.ActivityFeedPage, error) {
+			gotCursor, gotLimit = cursor, limit
+			return &application.ActivityFeedPage{
+				Entries:    []*application.ActivityEntry{{TodoID: "123", Title: "Buy milk", Kind: "completed"}},
+				NextCursor: "41",
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/activity?cursor=42&limit=1")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if gotCursor != "42" || gotLimit != 1 {
+		t.Errorf("ListActivity() cursor, limit = %q, %d, want %q, %d", gotCursor, gotLimit, "42", 1)
+	}
+
+	var body activityFeedResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Entries) != 1 || body.Entries[0].Kind != "completed" || body.NextCursor != "41" {
+		t.Errorf("body = %+v, want the completed entry and next cursor 41", body)
+	}
+}
+
+func TestHandler_ListActivity_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid cursor", domain.NewValidationError("cursor", "is invalid"), http.StatusBadRequest},
+		{"not supported", application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				listActivity: func(ctx context.Context, cursor string, limit int) (*application.ActivityFeedPage, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serve(t, service, "/api/activity")
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
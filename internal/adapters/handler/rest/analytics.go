@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// analyticsPoint is the JSON representation of the activity over one bucket
+type analyticsPoint struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+	Overdue   int       `json:"overdue"`
+}
+
+// analyticsResponse is the JSON representation of an analytics report
+type analyticsResponse struct {
+	Interval                string           `json:"interval"`
+	From                    time.Time        `json:"from"`
+	To                      time.Time        `json:"to"`
+	Points                  []analyticsPoint `json:"points"`
+	Completed               int              `json:"completed"`
+	AverageCycleTimeSeconds float64          `json:"average_cycle_time_seconds"`
+}
+
+// getAnalytics answers GET /api/analytics?from=<date>&to=<date>&interval=day|week
+func (h *Handler) getAnalytics(w http.ResponseWriter, r *http.Request) {
+	req := application.AnalyticsRequest{Interval: r.URL.Query().Get("interval")}
+
+	var err error
+	if req.From, err = queryTime(r, "from"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.To, err = queryTime(r, "to"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.service.GetAnalytics(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	points := make([]analyticsPoint, len(report.Points))
+	for i, point := range report.Points {
+		points[i] = analyticsPoint{
+			Start:     point.Start,
+			Created:   point.Created,
+			Completed: point.Completed,
+			Overdue:   point.Overdue,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, analyticsResponse{
+		Interval:                report.Interval,
+		From:                    report.From,
+		To:                      report.To,
+		Points:                  points,
+		Completed:               report.Completed,
+		AverageCycleTimeSeconds: report.AverageCycleTime.Seconds(),
+	})
+}
+
+// queryTime parses an optional query parameter holding a date (YYYY-MM-DD,
+// midnight UTC) or an RFC 3339 timestamp, zero when absent
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid %s: must be a date or an RFC 3339 timestamp", name)
+}
+
+// heatmapDay is the JSON representation of the completions of one day
+type heatmapDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// heatmapResponse is the JSON representation of a completion heatmap
+type heatmapResponse struct {
+	Days  []heatmapDay `json:"days"`
+	Total int          `json:"total"`
+	Max   int          `json:"max"`
+}
+
+// getCompletionHeatmap answers GET /api/heatmap
+func (h *Handler) getCompletionHeatmap(w http.ResponseWriter, r *http.Request) {
+	heatmap, err := h.service.GetCompletionHeatmap(r.Context())
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	days := make([]heatmapDay, len(heatmap.Days))
+	for i, day := range heatmap.Days {
+		days[i] = heatmapDay{Date: day.Date.Format(time.DateOnly), Count: day.Count}
+	}
+
+	// Per-user content
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, heatmapResponse{Days: days, Total: heatmap.Total, Max: heatmap.Max})
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_GetAnalytics_Success(t *testing.T) {
+	var got application.AnalyticsRequest
+	service := &fakeService{
+		getAnalytics: func(ctx context.Context, req application.AnalyticsRequest) (*application.AnalyticsReport, error) {
+			got = req
+			return &application.AnalyticsReport{
+				Interval:         "week",
+				Points:           []*application.AnalyticsPoint{{Created: 3, Completed: 2, Overdue: 1}},
+				Completed:        2,
+				AverageCycleTime: 90 * time.Minute,
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/analytics?from=2026-03-02&to=2026-03-23T00:00:00Z&interval=week")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	wantFrom := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC)
+	if !got.From.Equal(wantFrom) || !got.To.Equal(wantTo) || got.Interval != "week" {
+		t.Errorf("GetAnalytics() request = %+v, want %v to %v weekly", got, wantFrom, wantTo)
+	}
+
+	var body analyticsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Points) != 1 || body.Points[0].Overdue != 1 {
+		t.Errorf("Points = %+v, want one point with one overdue todo", body.Points)
+	}
+	if body.AverageCycleTimeSeconds != 5400 {
+		t.Errorf("AverageCycleTimeSeconds = %v, want 5400", body.AverageCycleTimeSeconds)
+	}
+}
+
+func TestHandler_GetAnalytics_Errors(t *testing.T) {
+	service := &fakeService{
+		getAnalytics: func(ctx context.Context, req application.AnalyticsRequest) (*application.AnalyticsReport, error) {
+			return nil, domain.NewValidationError("interval", "must be day or week")
+		},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"invalid from", "/api/analytics?from=yesterday", http.StatusBadRequest},
+		{"invalid to", "/api/analytics?to=2026-13-01", http.StatusBadRequest},
+		{"invalid interval", "/api/analytics?interval=month", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, service, tt.target)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_GetCompletionHeatmap_Success(t *testing.T) {
+	service := &fakeService{
+		getHeatmap: func(ctx context.Context) (*application.CompletionHeatmap, error) {
+			return &application.CompletionHeatmap{
+				Days:  []*application.HeatmapDay{{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Count: 4}},
+				Total: 4,
+				Max:   4,
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/heatmap")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var body heatmapResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Days) != 1 || body.Days[0].Date != "2026-03-01" || body.Days[0].Count != 4 {
+		t.Errorf("Days = %+v, want 4 completions on 2026-03-01", body.Days)
+	}
+}
+
+func TestHandler_GetCompletionHeatmap_Unauthenticated(t *testing.T) {
+	service := &fakeService{
+		getHeatmap: func(ctx context.Context) (*application.CompletionHeatmap, error) {
+			return nil, application.ErrUnauthenticated
+		},
+	}
+
+	rec := serve(t, service, "/api/heatmap")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
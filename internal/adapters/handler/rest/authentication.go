@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// WithJWTs requires a Bearer credential on the /api routes and accepts
+// Bearer JWTs checked by verifier, as the Connect API does
+func WithJWTs(verifier auth.TokenVerifier) Option {
+	return func(h *Handler) {
+		h.authenticator.JWTs = verifier
+	}
+}
+
+// WithAPIKeys requires a Bearer credential on the /api routes and accepts
+// Bearer API keys resolved by keys, as the Connect API does
+func WithAPIKeys(keys auth.APIKeyAuthenticator) Option {
+	return func(h *Handler) {
+		h.authenticator.APIKeys = keys
+	}
+}
+
+// authenticate wraps the /api routes of next: once a credential is
+// accepted, every request needs a valid one, answering 401 otherwise, and
+// the scopes it grants are checked by requireScope
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	next = requireScope(next)
+	if !h.authenticator.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := h.authenticator.Authenticate(r.Context(), r.Header.Get("Authorization"))
+		var credentialErr *auth.CredentialError
+		if errors.As(err, &credentialErr) && credentialErr.Unavailable {
+			h.logger.Error("credential check failed", "path", r.URL.Path, "error", err)
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requiredScope returns the scope a request needs: read for GET and HEAD,
+// write for the other methods
+func requiredScope(r *http.Request) string {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return "read"
+	}
+	return "write"
+}
+
+// requireScope answers 403 to the requests whose credential lacks their
+// required scope, or admin which grants them all
+// Requests carrying no scopes (see application.ScopesFromContext) are not
+// checked: their authentication mechanism does not grant scopes
+func requireScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		granted, ok := application.ScopesFromContext(r.Context())
+		required := requiredScope(r)
+		if ok && !slices.Contains(granted, required) && !slices.Contains(granted, "admin") {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("%s %s requires the %s scope", r.Method, r.URL.Path, required))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// stubVerifier accepts the tokens it maps to an identity
+type stubVerifier map[string]auth.Identity
+
+func (v stubVerifier) Verify(_ context.Context, token string) (auth.Identity, error) {
+	if token == "unreachable" {
+		return auth.Identity{}, fmt.Errorf("%w: connection refused", auth.ErrKeySetUnavailable)
+	}
+	identity, ok := v[token]
+	if !ok {
+		return auth.Identity{}, fmt.Errorf("%w: bad signature", auth.ErrInvalidToken)
+	}
+	return identity, nil
+}
+
+func TestHandler_Authenticate(t *testing.T) {
+	verifier := stubVerifier{
+		"alice-token": {Subject: "alice"},
+		"bob-token":   {Subject: "bob", Scopes: []string{"read"}, HasScopes: true},
+		"root-token":  {Subject: "root", Scopes: []string{"admin"}, HasScopes: true},
+	}
+	keys := fakeAPIKeys{keys: map[string]*application.APIKeyResponse{
+		"tdk_cron": {ID: "key-cron", UserID: "cron", Scopes: []string{"read"}},
+		"tdk_none": {ID: "key-none", UserID: "audit", Scopes: []string{}},
+	}}
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		method        string
+		target        string
+		authorization string
+		wantStatus    int
+	}{
+		{"no authentication configured", nil, http.MethodGet, "/api/todos/export", "", http.StatusOK},
+		{"missing credential", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "", http.StatusUnauthorized},
+		{"invalid token", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer forged", http.StatusUnauthorized},
+		{"valid token", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer alice-token", http.StatusOK},
+		{"read scope reads", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer bob-token", http.StatusOK},
+		{"read scope cannot write", []Option{WithJWTs(verifier)}, http.MethodPost, "/api/todos/batch-delete", "Bearer bob-token", http.StatusForbidden},
+		{"admin scope reads", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer root-token", http.StatusOK},
+		{"key set unavailable", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer unreachable", http.StatusServiceUnavailable},
+		{"API key", []Option{WithAPIKeys(keys)}, http.MethodGet, "/api/todos/export", "Bearer tdk_cron", http.StatusOK},
+		{"invalid API key", []Option{WithAPIKeys(keys)}, http.MethodGet, "/api/todos/export", "Bearer tdk_forged", http.StatusUnauthorized},
+		{"API key without scopes", []Option{WithAPIKeys(keys)}, http.MethodGet, "/api/todos/export", "Bearer tdk_none", http.StatusForbidden},
+		{"API key cannot write with read scope", []Option{WithAPIKeys(keys)}, http.MethodPost, "/api/todos/batch-delete", "Bearer tdk_cron", http.StatusForbidden},
+		{"API keys not accepted", []Option{WithJWTs(verifier)}, http.MethodGet, "/api/todos/export", "Bearer tdk_cron", http.StatusUnauthorized},
+		{"JWT not accepted", []Option{WithAPIKeys(keys)}, http.MethodGet, "/api/todos/export", "Bearer alice-token", http.StatusUnauthorized},
+		{"API key store unavailable", []Option{WithAPIKeys(fakeAPIKeys{err: errors.New("connection refused")})}, http.MethodGet, "/api/todos/export", "Bearer tdk_cron", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(exportService(nil, exportedTodos()...), slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
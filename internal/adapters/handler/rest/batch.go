@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// batchTodoUpdate is the JSON representation of the update of one todo of
+// a batch; fields follow the v1 UpdateTodo RPC
+type batchTodoUpdate struct {
+	TodoID           string     `json:"todo_id"`
+	Title            *string    `json:"title,omitempty"`
+	Description      *string    `json:"description,omitempty"`
+	Priority         *string    `json:"priority,omitempty"`
+	Status           *string    `json:"status,omitempty"`
+	DueDate          *time.Time `json:"due_date,omitempty"`
+	BaseUpdatedAt    *time.Time `json:"base_updated_at,omitempty"`
+	ConflictStrategy string     `json:"conflict_strategy,omitempty"`
+}
+
+// batchUpdateRequest is the JSON body of a batch update
+type batchUpdateRequest struct {
+	Updates []batchTodoUpdate `json:"updates"`
+}
+
+// batchDeleteRequest is the JSON body of a batch deletion
+type batchDeleteRequest struct {
+	TodoIDs []string `json:"todo_ids"`
+}
+
+// batchFailureResponse is the JSON representation of a todo left out of a
+// batch, with the status code the same change alone would have answered
+type batchFailureResponse struct {
+	TodoID string `json:"todo_id"`
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// batchUpdateResponse is the JSON response of a batch update
+type batchUpdateResponse struct {
+	Updated  []todoResponse         `json:"updated"`
+	Failures []batchFailureResponse `json:"failures"`
+}
+
+// batchDeleteResponse is the JSON response of a batch deletion
+type batchDeleteResponse struct {
+	Deleted  []string               `json:"deleted"`
+	Failures []batchFailureResponse `json:"failures"`
+}
+
+// batchUpdateTodos answers POST /api/todos/batch-update, saving the updates
+// of the body at once and listing those refused
+func (h *Handler) batchUpdateTodos(w http.ResponseWriter, r *http.Request) {
+	var body batchUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	req := application.BatchUpdateRequest{Updates: make([]application.BatchTodoUpdate, len(body.Updates))}
+	for i, update := range body.Updates {
+		req.Updates[i] = application.BatchTodoUpdate{
+			TodoID: update.TodoID,
+			Fields: application.UpdateTodoRequest{
+				Title:            update.Title,
+				Description:      update.Description,
+				Priority:         update.Priority,
+				DueDate:          update.DueDate,
+				Status:           update.Status,
+				BaseUpdatedAt:    update.BaseUpdatedAt,
+				ConflictStrategy: update.ConflictStrategy,
+			},
+		}
+	}
+
+	batch, err := h.service.BatchUpdateTodos(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batchUpdateResponse{Updated: mapTodos(batch.Updated), Failures: mapBatchFailures(batch.Failures)})
+}
+
+// batchDeleteTodos answers POST /api/todos/batch-delete, deleting the todos
+// of the body at once and listing those refused
+func (h *Handler) batchDeleteTodos(w http.ResponseWriter, r *http.Request) {
+	var body batchDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	batch, err := h.service.BatchDeleteTodos(r.Context(), application.BatchDeleteRequest{TodoIDs: body.TodoIDs})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batchDeleteResponse{Deleted: batch.Deleted, Failures: mapBatchFailures(batch.Failures)})
+}
+
+// mapBatchFailures converts application batch failures to their JSON
+// representation
+func mapBatchFailures(failures []application.BatchFailure) []batchFailureResponse {
+	response := make([]batchFailureResponse, len(failures))
+	for i, failure := range failures {
+		response[i] = batchFailureResponse{
+			TodoID: failure.TodoID,
+			Status: statusForError(failure.Err),
+			Error:  failure.Err.Error(),
+		}
+	}
+	return response
+}
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_BatchUpdateTodos(t *testing.T) {
+	var got application.BatchUpdateRequest
+	service := &fakeService{
+		batchUpdate: func(ctx context.Context, req application.BatchUpdateRequest) (*application.BatchUpdateResponse, error) {
+			got = req
+			return &application.BatchUpdateResponse{
+				Updated: []*application.TodoResponse{{ID: "aaa", Title: "Renamed"}},
+				Failures: []application.BatchFailure{
+					{TodoID: "bbb", Err: domain.ErrInvalidStatusTransition},
+					{TodoID: "ccc", Err: domain.ErrTodoNotFound},
+				},
+			}, nil
+		},
+	}
+
+	body := `{"updates":[{"todo_id":"aaa","title":"Renamed"},{"todo_id":"bbb","status":"pending"},{"todo_id":"ccc","priority":"high"}]}`
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/batch-update", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if len(got.Updates) != 3 || got.Updates[0].TodoID != "aaa" || *got.Updates[0].Fields.Title != "Renamed" || *got.Updates[1].Fields.Status != "pending" {
+		t.Errorf("BatchUpdateTodos() called with %+v, want the updates of the body", got)
+	}
+
+	var batch batchUpdateResponse
+	if err := json.NewDecoder(rec.Body).Decode(&batch); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(batch.Updated) != 1 || batch.Updated[0].ID != "aaa" || len(batch.Failures) != 2 {
+		t.Fatalf("response = %+v, want aaa updated and two failures", batch)
+	}
+	if batch.Failures[0].Status != http.StatusConflict || batch.Failures[1].Status != http.StatusNotFound {
+		t.Errorf("failures = %+v, want 409 then 404", batch.Failures)
+	}
+}
+
+func TestHandler_BatchDeleteTodos(t *testing.T) {
+	var got application.BatchDeleteRequest
+	service := &fakeService{
+		batchDelete: func(ctx context.Context, req application.BatchDeleteRequest) (*application.BatchDeleteResponse, error) {
+			got = req
+			return &application.BatchDeleteResponse{
+				Deleted:  []string{"aaa"},
+				Failures: []application.BatchFailure{{TodoID: "bbb", Err: application.ErrLegalHold}},
+			}, nil
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/batch-delete", strings.NewReader(`{"todo_ids":["aaa","bbb"]}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if len(got.TodoIDs) != 2 || got.TodoIDs[1] != "bbb" {
+		t.Errorf("BatchDeleteTodos() called with %+v, want the IDs of the body", got)
+	}
+
+	var batch batchDeleteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&batch); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(batch.Deleted) != 1 || len(batch.Failures) != 1 || batch.Failures[0].TodoID != "bbb" || batch.Failures[0].Status != http.StatusConflict {
+		t.Errorf("response = %+v, want aaa deleted and bbb held", batch)
+	}
+}
+
+func TestHandler_BatchTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid update body", "/api/todos/batch-update", `{"updates":`, nil, http.StatusBadRequest},
+		{"no update", "/api/todos/batch-update", `{"updates":[]}`, domain.NewValidationError("updates", "at least one todo is required"), http.StatusBadRequest},
+		{"updates not supported", "/api/todos/batch-update", `{"updates":[{"todo_id":"aaa"}]}`, application.ErrNotSupported, http.StatusNotImplemented},
+		{"invalid delete body", "/api/todos/batch-delete", `{"todo_ids":`, nil, http.StatusBadRequest},
+		{"deletion forbidden", "/api/todos/batch-delete", `{"todo_ids":["aaa"]}`, application.ErrForbidden, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				batchUpdate: func(ctx context.Context, req application.BatchUpdateRequest) (*application.BatchUpdateResponse, error) {
+					return nil, tt.err
+				},
+				batchDelete: func(ctx context.Context, req application.BatchDeleteRequest) (*application.BatchDeleteResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// bulkCompleteRequest is the JSON body of a bulk completion: the filter of
+// the open todos to complete
+type bulkCompleteRequest struct {
+	Status   *string `json:"status,omitempty"`
+	Priority *string `json:"priority,omitempty"`
+	Overdue  bool    `json:"overdue,omitempty"`
+}
+
+// bulkCompleteResponse is the JSON response of a bulk completion
+type bulkCompleteResponse struct {
+	Completed []todoResponse `json:"completed"`
+	More      bool           `json:"more"`
+}
+
+// bulkCompleteTodos answers POST /api/todos/bulk-complete, completing every
+// open todo matching the filter of the body
+// With Prefer: respond-async, an operation completes them all, call after
+// call, instead of the todos a single call completes
+func (h *Handler) bulkCompleteTodos(w http.ResponseWriter, r *http.Request) {
+	var body bulkCompleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if h.respondAsync(r) {
+		h.startOperation(w, r, application.OperationBulkComplete, h.bulkCompleteAll(application.BulkCompleteRequest(body)))
+		return
+	}
+
+	resp, err := h.service.BulkCompleteTodos(r.Context(), application.BulkCompleteRequest(body))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkCompleteResponse{Completed: mapTodos(resp.Completed), More: resp.More})
+}
+
+// bulkCompleteAll returns the operation completing every todo matching req,
+// calling BulkCompleteTodos until nothing more matches; its result lists
+// the completed todos
+func (h *Handler) bulkCompleteAll(req application.BulkCompleteRequest) application.OperationFunc {
+	return func(ctx context.Context, progress func(done, total int)) (any, error) {
+		response := bulkCompleteResponse{Completed: []todoResponse{}}
+		for {
+			if err := ctx.Err(); err != nil {
+				response.More = true
+				return response, err
+			}
+
+			resp, err := h.service.BulkCompleteTodos(ctx, req)
+			if err != nil {
+				response.More = true
+				return response, err
+			}
+			response.Completed = append(response.Completed, mapTodos(resp.Completed)...)
+			progress(len(response.Completed), 0)
+
+			if !resp.More {
+				return response, nil
+			}
+		}
+	}
+}
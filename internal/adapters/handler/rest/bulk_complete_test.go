@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_BulkCompleteTodos(t *testing.T) {
+	var got application.BulkCompleteRequest
+	service := &fakeService{
+		bulkComplete: func(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error) {
+			got = req
+			return &application.BulkCompleteResponse{
+				Completed: []*application.TodoResponse{{ID: "aaa", Status: "completed"}},
+				More:      true,
+			}, nil
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/bulk-complete", strings.NewReader(`{"priority":"low","overdue":true}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got.Priority == nil || *got.Priority != "low" || !got.Overdue || got.Status != nil {
+		t.Errorf("BulkCompleteTodos() called with %+v, want overdue low priority todos", got)
+	}
+
+	var resp bulkCompleteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Completed) != 1 || resp.Completed[0].ID != "aaa" || !resp.More {
+		t.Errorf("response = %+v, want aaa completed and more to come", resp)
+	}
+}
+
+func TestHandler_BulkCompleteTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid body", `{"overdue":`, nil, http.StatusBadRequest},
+		{"no condition", `{}`, domain.NewValidationError("filter", "at least one condition is required"), http.StatusBadRequest},
+		{"not supported", `{"overdue":true}`, application.ErrNotSupported, http.StatusNotImplemented},
+		{"denied", `{"overdue":true}`, application.ErrForbidden, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				bulkComplete: func(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/bulk-complete", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
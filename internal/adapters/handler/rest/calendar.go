@@ -0,0 +1,184 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/ical"
+)
+
+// calendarRefresh is how often calendar clients are asked to poll the feed
+const calendarRefresh = "PT1H"
+
+// WithCalendarKeys requires an API key granting the read scope in the
+// "token" query parameter of the calendar feed, since calendar clients
+// cannot send headers; the feed is then the one of the user of the key
+func WithCalendarKeys(keys auth.APIKeyAuthenticator) Option {
+	return func(h *Handler) {
+		h.calendarKeys = keys
+	}
+}
+
+// calendarFeed answers GET /calendar.ics?type=event|todo&token=, rendering
+// the todos with a due date as an iCalendar feed calendar apps subscribe to
+// Todos are events at their due date by default, which every client shows;
+// type=todo renders them as tasks instead, for clients that support them
+func (h *Handler) calendarFeed(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("type")
+	switch kind {
+	case "":
+		kind = "event"
+	case "event", "todo":
+	default:
+		writeError(w, http.StatusBadRequest, "type must be event or todo")
+		return
+	}
+
+	ctx := r.Context()
+	if h.calendarKeys != nil {
+		var ok bool
+		if ctx, ok = h.authenticateCalendar(w, r); !ok {
+			return
+		}
+	}
+
+	todos, err := h.service.CalendarFeed(ctx)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	// Buffered so that a rendering failure still yields a clean error
+	var buf bytes.Buffer
+	if err := writeCalendar(&buf, kind, todos, time.Now()); err != nil {
+		h.writeServiceError(w, r, fmt.Errorf("rendering calendar: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="todos.ics"`)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// authenticateCalendar returns the context of the user of the API key in
+// the "token" query parameter, or writes the error response
+func (h *Handler) authenticateCalendar(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "missing token")
+		return nil, false
+	}
+
+	key, err := h.calendarKeys.Authenticate(r.Context(), token)
+	if errors.Is(err, application.ErrInvalidAPIKey) {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("calendar token check failed", "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusServiceUnavailable, "cannot verify token")
+		return nil, false
+	}
+	if !slices.Contains(key.Scopes, "read") && !slices.Contains(key.Scopes, "admin") {
+		writeError(w, http.StatusForbidden, "the token does not grant the read scope")
+		return nil, false
+	}
+
+	ctx := application.ContextWithUserID(r.Context(), key.UserID)
+	return application.ContextWithScopes(ctx, key.Scopes), true
+}
+
+// writeCalendar writes todos as a calendar of VEVENT or VTODO components,
+// per kind, stamped at now
+func writeCalendar(buf *bytes.Buffer, kind string, todos []*application.TodoResponse, now time.Time) error {
+	out := ical.NewWriter(buf)
+	out.Begin("VCALENDAR")
+	out.Property("VERSION", "2.0")
+	out.Property("PRODID", "-//mmw//todo//EN")
+	out.Property("CALSCALE", "GREGORIAN")
+	out.Property("METHOD", "PUBLISH")
+	out.Text("X-WR-CALNAME", "Todos")
+	out.Property("REFRESH-INTERVAL;VALUE=DURATION", calendarRefresh)
+	out.Property("X-PUBLISHED-TTL", calendarRefresh)
+
+	for _, todo := range todos {
+		if todo.DueDate == nil {
+			continue
+		}
+
+		component := "VEVENT"
+		if kind == "todo" {
+			component = "VTODO"
+		}
+		out.Begin(component)
+		out.Text("UID", todo.ID)
+		out.Time("DTSTAMP", now)
+		out.Time("CREATED", todo.CreatedAt)
+		out.Time("LAST-MODIFIED", todo.UpdatedAt)
+		if kind == "todo" {
+			out.Time("DUE", *todo.DueDate)
+		} else {
+			// Without DTEND, the event is an instant at its due date
+			out.Time("DTSTART", *todo.DueDate)
+		}
+		out.Text("SUMMARY", todo.Title)
+		if todo.Description != "" {
+			out.Text("DESCRIPTION", todo.Description)
+		}
+		out.Property("PRIORITY", calendarPriority(todo.Priority))
+		out.Property("STATUS", calendarStatus(kind, todo.Status))
+		out.End(component)
+	}
+
+	out.End("VCALENDAR")
+	return out.Flush()
+}
+
+// calendarPriority maps a todo priority onto the 1 (highest) to 9 (lowest)
+// scale of iCalendar, 0 leaving it undefined
+func calendarPriority(priority string) string {
+	switch domain.Priority(priority) {
+	case domain.PriorityUrgent:
+		return "1"
+	case domain.PriorityHigh:
+		return "3"
+	case domain.PriorityMedium:
+		return "5"
+	case domain.PriorityLow:
+		return "9"
+	default:
+		return "0"
+	}
+}
+
+// calendarStatus maps a todo status onto the statuses of the component of
+// kind; events can only be confirmed or cancelled
+func calendarStatus(kind, status string) string {
+	if kind != "todo" {
+		if domain.TaskStatus(status) == domain.StatusCancelled {
+			return "CANCELLED"
+		}
+		return "CONFIRMED"
+	}
+
+	switch domain.TaskStatus(status) {
+	case domain.StatusInProgress:
+		return "IN-PROCESS"
+	case domain.StatusCompleted:
+		return "COMPLETED"
+	case domain.StatusCancelled:
+		return "CANCELLED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
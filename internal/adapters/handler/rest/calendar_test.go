@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeAPIKeys authenticates the secrets of its keys
+type fakeAPIKeys struct {
+	keys map[string]*application.APIKeyResponse
+	err  error
+}
+
+func (f fakeAPIKeys) Authenticate(ctx context.Context, secret string) (*application.APIKeyResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	key, ok := f.keys[secret]
+	if !ok {
+		return nil, application.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+// calendarTodos returns a todo due on January 2nd, 2030, with a
+// description needing escaping, and an undated one
+func calendarTodos() []*application.TodoResponse {
+	due := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	return []*application.TodoResponse{
+		{ID: "aaa", Title: "Renew the domain", Description: "Registrar: gandi, account 42", Status: "completed", Priority: "urgent", DueDate: &due},
+		{ID: "bbb", Title: "Someday", Status: "pending", Priority: "low"},
+	}
+}
+
+func TestHandler_CalendarFeed(t *testing.T) {
+	service := &fakeService{
+		calendarFeed: func(ctx context.Context) ([]*application.TodoResponse, error) {
+			return calendarTodos(), nil
+		},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"events by default", "", []string{"BEGIN:VEVENT\r\n", "DTSTART:20300102T150405Z\r\n", "STATUS:CONFIRMED\r\n", "END:VEVENT\r\n"}},
+		{"todos", "?type=todo", []string{"BEGIN:VTODO\r\n", "DUE:20300102T150405Z\r\n", "STATUS:COMPLETED\r\n", "END:VTODO\r\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/calendar.ics"+tt.query, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != "text/calendar; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want text/calendar", contentType)
+			}
+
+			body := rec.Body.String()
+			if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+				t.Errorf("body = %q, want a VCALENDAR", body)
+			}
+			want := append(tt.want, "UID:aaa\r\n", "SUMMARY:Renew the domain\r\n", "DESCRIPTION:Registrar: gandi\\, account 42\r\n", "PRIORITY:1\r\n")
+			for _, line := range want {
+				if !strings.Contains(body, line) {
+					t.Errorf("body = %q, want %q", body, line)
+				}
+			}
+			if strings.Contains(body, "UID:bbb") {
+				t.Error("the undated todo is in the feed")
+			}
+		})
+	}
+}
+
+func TestHandler_CalendarFeed_Token(t *testing.T) {
+	keys := fakeAPIKeys{keys: map[string]*application.APIKeyResponse{
+		"tdk_reader": {UserID: "alice", Scopes: []string{"read"}},
+		"tdk_writer": {UserID: "alice", Scopes: []string{"write"}},
+	}}
+
+	tests := []struct {
+		name  string
+		keys  fakeAPIKeys
+		query string
+		want  int
+	}{
+		{"valid token", keys, "?token=tdk_reader", http.StatusOK},
+		{"missing token", keys, "", http.StatusUnauthorized},
+		{"unknown token", keys, "?token=tdk_unknown", http.StatusUnauthorized},
+		{"no read scope", keys, "?token=tdk_writer", http.StatusForbidden},
+		{"key store down", fakeAPIKeys{err: errors.New("connection reset")}, "?token=tdk_reader", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser string
+			service := &fakeService{
+				calendarFeed: func(ctx context.Context) ([]*application.TodoResponse, error) {
+					gotUser, _ = application.UserIDFromContext(ctx)
+					return calendarTodos(), nil
+				},
+			}
+			mux := http.NewServeMux()
+			NewHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)), WithCalendarKeys(tt.keys)).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics"+tt.query, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && gotUser != "alice" {
+				t.Errorf("CalendarFeed() called as %q, want alice", gotUser)
+			}
+		})
+	}
+}
+
+func TestHandler_CalendarFeed_Errors(t *testing.T) {
+	service := &fakeService{
+		calendarFeed: func(ctx context.Context) ([]*application.TodoResponse, error) {
+			return nil, application.ErrNotSupported
+		},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"unknown type", "?type=journal", http.StatusBadRequest},
+		{"not supported", "", http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/calendar.ics"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// dependencyNodeResponse is the JSON representation of a todo of a
+// dependency graph
+type dependencyNodeResponse struct {
+	Todo  todoResponse `json:"todo"`
+	Level int          `json:"level"`
+}
+
+// dependencyEdgeResponse is the JSON representation of a todo blocked by
+// another one
+type dependencyEdgeResponse struct {
+	TodoID    string `json:"todo_id"`
+	BlockedBy string `json:"blocked_by"`
+}
+
+// dependencyGraphResponse is the JSON response of a dependency graph
+type dependencyGraphResponse struct {
+	RootID       string                   `json:"root_id"`
+	Nodes        []dependencyNodeResponse `json:"nodes"`
+	Edges        []dependencyEdgeResponse `json:"edges"`
+	CriticalPath []string                 `json:"critical_path"`
+	Truncated    bool                     `json:"truncated"`
+}
+
+// getDependencyGraph answers GET /api/todos/{id}/dependencies with the
+// blocked-by graph around the todo, blockers first
+func (h *Handler) getDependencyGraph(w http.ResponseWriter, r *http.Request) {
+	graph, err := h.service.GetDependencyGraph(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := dependencyGraphResponse{
+		RootID:       graph.RootID,
+		Nodes:        make([]dependencyNodeResponse, len(graph.Nodes)),
+		Edges:        make([]dependencyEdgeResponse, len(graph.Edges)),
+		CriticalPath: graph.CriticalPath,
+		Truncated:    graph.Truncated,
+	}
+	for i, node := range graph.Nodes {
+		response.Nodes[i] = dependencyNodeResponse{Todo: mapTodo(node.Todo), Level: node.Level}
+	}
+	for i, edge := range graph.Edges {
+		response.Edges[i] = dependencyEdgeResponse(edge)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// addDependency answers PUT /api/todos/{id}/blocked-by/{blocker}
+func (h *Handler) addDependency(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.AddDependency(r.Context(), r.PathValue("id"), r.PathValue("blocker")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeDependency answers DELETE /api/todos/{id}/blocked-by/{blocker}
+func (h *Handler) removeDependency(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveDependency(r.Context(), r.PathValue("id"), r.PathValue("blocker")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
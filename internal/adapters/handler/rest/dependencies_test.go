@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_GetDependencyGraph(t *testing.T) {
+	var gotID string
+	service := &fakeService{
+		dependencyGraph: func(ctx context.Context, id string) (*application.DependencyGraph, error) {
+			gotID = id
+			return &application.DependencyGraph{
+				RootID: "bbb",
+				Nodes: []*application.DependencyNode{
+					{Todo: &application.TodoResponse{ID: "aaa"}, Level: 0},
+					{Todo: &application.TodoResponse{ID: "bbb"}, Level: 1},
+				},
+				Edges:        []application.DependencyEdge{{TodoID: "bbb", BlockedBy: "aaa"}},
+				CriticalPath: []string{"aaa", "bbb"},
+			}, nil
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/api/todos/TD-7/dependencies", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if gotID != "TD-7" {
+		t.Errorf("GetDependencyGraph() called with %q, want TD-7", gotID)
+	}
+
+	var graph dependencyGraphResponse
+	if err := json.NewDecoder(rec.Body).Decode(&graph); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(graph.Nodes) != 2 || graph.Nodes[1].Level != 1 || len(graph.Edges) != 1 || graph.Edges[0].BlockedBy != "aaa" || len(graph.CriticalPath) != 2 {
+		t.Errorf("response = %+v, want bbb blocked by aaa", graph)
+	}
+}
+
+func TestHandler_Dependencies(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		want   int
+	}{
+		{"added", http.MethodPut, nil, http.StatusNoContent},
+		{"cycle", http.MethodPut, application.ErrDependencyCycle, http.StatusConflict},
+		{"unknown blocker", http.MethodPut, domain.ErrTodoNotFound, http.StatusNotFound},
+		{"removed", http.MethodDelete, nil, http.StatusNoContent},
+		{"not blocked", http.MethodDelete, application.ErrDependencyNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [2]string
+			record := func(ctx context.Context, id, blockerID string) error {
+				got = [2]string{id, blockerID}
+				return tt.err
+			}
+			service := &fakeService{addDependency: record, removeDependency: record}
+
+			rec := serveRequest(t, service, httptest.NewRequest(tt.method, "/api/todos/TD-7/blocked-by/TD-3", nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+			if got != [2]string{"TD-7", "TD-3"} {
+				t.Errorf("called with %v, want TD-7 blocked by TD-3", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// deviceRequest is the JSON body registering a device
+type deviceRequest struct {
+	Provider string `json:"provider"`
+	Token    string `json:"token"`
+}
+
+// device is the JSON representation of a device push notifications are
+// sent to
+type device struct {
+	Token        string    `json:"token"`
+	Provider     string    `json:"provider"`
+	RegisteredAt time.Time `json:"registered_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+}
+
+// mapDevice converts a DeviceResponse to its JSON representation
+func mapDevice(d *application.DeviceResponse) device {
+	return device{
+		Token:        d.Token,
+		Provider:     d.Provider,
+		RegisteredAt: d.RegisteredAt,
+		LastSeenAt:   d.LastSeenAt,
+	}
+}
+
+// registerDevice answers POST /api/devices, which apps call with their push
+// token on every start; registering a token again refreshes it
+func (h *Handler) registerDevice(w http.ResponseWriter, r *http.Request) {
+	var body deviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	registered, err := h.service.RegisterDevice(r.Context(), application.RegisterDeviceRequest{
+		Provider: body.Provider,
+		Token:    body.Token,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapDevice(registered))
+}
+
+// listDevices answers GET /api/devices
+func (h *Handler) listDevices(w http.ResponseWriter, r *http.Request) {
+	devices, err := h.service.ListDevices(r.Context())
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := make([]device, len(devices))
+	for i, d := range devices {
+		response[i] = mapDevice(d)
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// unregisterDevice answers DELETE /api/devices/{token}
+func (h *Handler) unregisterDevice(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.UnregisterDevice(r.Context(), r.PathValue("token")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_RegisterDevice(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"registered", `{"provider":"fcm","token":"fcm-token"}`, nil, http.StatusOK},
+		{"invalid JSON", `{`, nil, http.StatusBadRequest},
+		{"unknown provider", `{"provider":"wns","token":"t"}`, domain.NewValidationError("provider", "must be one of fcm, apns"), http.StatusBadRequest},
+		{"unauthenticated", `{"provider":"fcm","token":"t"}`, application.ErrUnauthenticated, http.StatusUnauthorized},
+		{"not supported", `{"provider":"fcm","token":"t"}`, application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got application.RegisterDeviceRequest
+			service := &fakeService{
+				registerDevice: func(ctx context.Context, req application.RegisterDeviceRequest) (*application.DeviceResponse, error) {
+					got = req
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &application.DeviceResponse{Token: req.Token, Provider: req.Provider}, nil
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/devices", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if got.Provider != "fcm" || got.Token != "fcm-token" {
+				t.Errorf("RegisterDevice() request = %+v, want the fcm token", got)
+			}
+			var body device
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Token != "fcm-token" {
+				t.Errorf("Response = %+v, %v, want the device", body, err)
+			}
+		})
+	}
+}
+
+func TestHandler_ListDevices(t *testing.T) {
+	service := &fakeService{
+		listDevices: func(ctx context.Context) ([]*application.DeviceResponse, error) {
+			return []*application.DeviceResponse{{Token: "a", Provider: "apns"}, {Token: "b", Provider: "fcm"}}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/devices")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var body []device
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body) != 2 || body[0].Provider != "apns" {
+		t.Errorf("Response = %+v, want both devices in order", body)
+	}
+}
+
+func TestHandler_UnregisterDevice(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unregistered", nil, http.StatusNoContent},
+		{"unknown token", application.ErrDeviceNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			service := &fakeService{
+				unregisterDevice: func(ctx context.Context, token string) error {
+					got = token
+					return tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodDelete, "/api/devices/fcm:token-1", nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+			if got != "fcm:token-1" {
+				t.Errorf("UnregisterDevice() token = %q, want fcm:token-1", got)
+			}
+		})
+	}
+}
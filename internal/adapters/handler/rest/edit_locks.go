@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// editLockRequest is the optional JSON body acquiring an edit lock
+// TTLSeconds defaults to application.DefaultEditLockTTL
+type editLockRequest struct {
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+// editLock is the JSON representation of the lock of a user editing a todo
+type editLock struct {
+	TodoID     string    `json:"todo_id"`
+	HolderID   string    `json:"holder_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// editLockedResponse is the JSON body of an edit lock held by another user
+type editLockedResponse struct {
+	Error string   `json:"error"`
+	Lock  editLock `json:"lock"`
+}
+
+// mapEditLock converts an EditLockResponse to its JSON representation
+func mapEditLock(lock *application.EditLockResponse) editLock {
+	return editLock{
+		TodoID:     lock.TodoID,
+		HolderID:   lock.HolderID,
+		AcquiredAt: lock.AcquiredAt,
+		ExpiresAt:  lock.ExpiresAt,
+	}
+}
+
+// acquireEditLock answers PUT /api/todos/{id}/edit-lock, which editors call
+// when they start editing a todo, then again to keep the lock before it
+// expires
+// A lock held by another user is answered with 409 Conflict and that lock
+func (h *Handler) acquireEditLock(w http.ResponseWriter, r *http.Request) {
+	var body editLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	lock, err := h.service.AcquireEditLock(r.Context(), r.PathValue("id"), time.Duration(body.TTLSeconds)*time.Second)
+	var locked *application.EditLockedError
+	if errors.As(err, &locked) {
+		writeJSON(w, http.StatusConflict, editLockedResponse{Error: err.Error(), Lock: mapEditLock(locked.Lock)})
+		return
+	}
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapEditLock(lock))
+}
+
+// releaseEditLock answers DELETE /api/todos/{id}/edit-lock, which editors
+// call when they stop editing a todo
+func (h *Handler) releaseEditLock(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ReleaseEditLock(r.Context(), r.PathValue("id")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
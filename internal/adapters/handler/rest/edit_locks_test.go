@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_AcquireEditLock(t *testing.T) {
+	aliceLock := &application.EditLockResponse{TodoID: "todo-1", HolderID: "alice", ExpiresAt: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name    string
+		body    string
+		err     error
+		want    int
+		wantTTL time.Duration
+	}{
+		{"acquired", `{"ttl_seconds":300}`, nil, http.StatusOK, 5 * time.Minute},
+		{"default TTL", ``, nil, http.StatusOK, 0},
+		{"invalid JSON", `{`, nil, http.StatusBadRequest, 0},
+		{"held by another user", ``, &application.EditLockedError{Lock: aliceLock}, http.StatusConflict, 0},
+		{"not found", ``, domain.ErrTodoNotFound, http.StatusNotFound, 0},
+		{"unauthenticated", ``, application.ErrUnauthenticated, http.StatusUnauthorized, 0},
+		{"not supported", ``, application.ErrNotSupported, http.StatusNotImplemented, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTTL time.Duration
+			service := &fakeService{
+				acquireEditLock: func(ctx context.Context, id string, ttl time.Duration) (*application.EditLockResponse, error) {
+					gotTTL = ttl
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &application.EditLockResponse{TodoID: id, HolderID: "bob"}, nil
+				},
+			}
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPut, "/api/todos/todo-1/edit-lock", body))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			switch tt.want {
+			case http.StatusOK:
+				if gotTTL != tt.wantTTL {
+					t.Errorf("AcquireEditLock() TTL = %v, want %v", gotTTL, tt.wantTTL)
+				}
+				var lock editLock
+				if err := json.NewDecoder(rec.Body).Decode(&lock); err != nil || lock.HolderID != "bob" || lock.TodoID != "todo-1" {
+					t.Errorf("Response = %+v, %v, want bob's lock", lock, err)
+				}
+			case http.StatusConflict:
+				var locked editLockedResponse
+				if err := json.NewDecoder(rec.Body).Decode(&locked); err != nil || locked.Lock.HolderID != "alice" || locked.Error == "" {
+					t.Errorf("Response = %+v, %v, want alice's lock", locked, err)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ReleaseEditLock(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"released", nil, http.StatusNoContent},
+		{"not held", application.ErrEditLockNotFound, http.StatusNotFound},
+		{"unauthenticated", application.ErrUnauthenticated, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			service := &fakeService{
+				releaseEditLock: func(ctx context.Context, id string) error {
+					gotID = id
+					return tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodDelete, "/api/todos/todo-1/edit-lock", nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if gotID != "todo-1" {
+				t.Errorf("ReleaseEditLock() id = %q, want todo-1", gotID)
+			}
+		})
+	}
+}
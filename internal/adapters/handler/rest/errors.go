@@ -0,0 +1,18 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// errorCatalogResponse is the JSON representation of the error catalog
+type errorCatalogResponse struct {
+	Rules []application.ErrorRule `json:"rules"`
+}
+
+// listErrorRules answers GET /api/errors with the rules of the errors the
+// Connect API returns, their ID being the "rule" of the error details
+func (h *Handler) listErrorRules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, errorCatalogResponse{Rules: application.ErrorCatalog()})
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+func TestHandler_ListErrorRules(t *testing.T) {
+	rec := serve(t, &fakeService{}, "/api/errors")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var body struct {
+		Rules []struct {
+			Rule      string `json:"rule"`
+			Code      string `json:"code"`
+			Message   string `json:"message"`
+			Retryable bool   `json:"retryable"`
+		} `json:"rules"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Rules) != len(application.ErrorCatalog()) {
+		t.Fatalf("rules = %d, want %d", len(body.Rules), len(application.ErrorCatalog()))
+	}
+	first := body.Rules[0]
+	if first.Rule != "todo.not_found" || first.Code != "not_found" || first.Message == "" || first.Retryable {
+		t.Errorf("rules[0] = %+v, want the not found rule", first)
+	}
+}
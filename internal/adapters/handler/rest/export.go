@@ -0,0 +1,174 @@
+package rest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// exportColumns is the header row of CSV exports
+var exportColumns = []string{
+	"id", "short_code", "title", "description", "status", "priority",
+	"due_date", "created_at", "updated_at", "archived_at", "version",
+}
+
+// todoWriter writes the todos of an export in one format
+type todoWriter interface {
+	start() error
+	write(todo *application.TodoResponse) error
+	flush() error
+}
+
+// exportTodos answers
+// GET /api/todos/export?format=ndjson|csv&status=&priority=&archived=,
+// streaming every matching todo, oldest first
+// Archived todos are exported unless archived says otherwise. Like watch
+// streams, the export outlives the server write timeout as long as the
+// client takes each todo within the watch WriteTimeout. A failure after the
+// first todo was sent can only cut the stream short, and a client going
+// away stops the export at the next todo
+func (h *Handler) exportTodos(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "ndjson"
+	}
+
+	var out todoWriter
+	var contentType string
+	switch format {
+	case "ndjson":
+		out, contentType = &ndjsonTodoWriter{encoder: json.NewEncoder(w)}, "application/x-ndjson"
+	case "csv":
+		out, contentType = &csvTodoWriter{out: csv.NewWriter(w)}, "text/csv; charset=utf-8"
+	default:
+		writeError(w, http.StatusBadRequest, "format must be ndjson or csv")
+		return
+	}
+
+	var filters application.ExportFilters
+	if status := query.Get("status"); status != "" {
+		filters.Status = &status
+	}
+	if priority := query.Get("priority"); priority != "" {
+		filters.Priority = &priority
+	}
+	if archived := query.Get("archived"); archived != "" {
+		filters.Archived = &archived
+	}
+
+	rc := http.NewResponseController(w)
+	started := false
+	sent := 0
+	begin := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="todos.`+format+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		return out.start()
+	}
+
+	err := h.service.ExportTodos(r.Context(), filters, func(todo *application.TodoResponse) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		h.extendWriteDeadline(rc)
+		if err := out.write(todo); err != nil {
+			return err
+		}
+		sent++
+		return nil
+	})
+	if err == nil && !started {
+		err = begin()
+	}
+	if err != nil {
+		if r.Context().Err() != nil {
+			h.logger.Info("export cancelled by the client", "path", r.URL.Path, "sent", sent)
+			return
+		}
+		if !started {
+			h.writeServiceError(w, r, err)
+			return
+		}
+		h.logger.Error("export failed", "path", r.URL.Path, "error", err)
+		return
+	}
+
+	if err := out.flush(); err != nil {
+		h.logger.Error("export failed", "path", r.URL.Path, "error", err)
+	}
+}
+
+// ndjsonTodoWriter writes one JSON todo per line
+type ndjsonTodoWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonTodoWriter) start() error {
+	return nil
+}
+
+func (n *ndjsonTodoWriter) write(todo *application.TodoResponse) error {
+	return n.encoder.Encode(mapTodo(todo))
+}
+
+func (n *ndjsonTodoWriter) flush() error {
+	return nil
+}
+
+// csvTodoWriter writes a header row then one row per todo, timestamps in
+// RFC 3339 and UTC
+type csvTodoWriter struct {
+	out *csv.Writer
+}
+
+func (c *csvTodoWriter) start() error {
+	return c.out.Write(exportColumns)
+}
+
+func (c *csvTodoWriter) write(todo *application.TodoResponse) error {
+	return c.out.Write([]string{
+		todo.ID,
+		todo.ShortCode,
+		spreadsheetSafe(todo.Title),
+		spreadsheetSafe(todo.Description),
+		todo.Status,
+		todo.Priority,
+		formatExportTime(todo.DueDate),
+		formatExportTime(&todo.CreatedAt),
+		formatExportTime(&todo.UpdatedAt),
+		formatExportTime(todo.ArchivedAt),
+		strconv.FormatInt(todo.Version, 10),
+	})
+}
+
+func (c *csvTodoWriter) flush() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// formatExportTime formats t in RFC 3339 and UTC, empty when t is nil
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// spreadsheetSafe prefixes text a spreadsheet would run as a formula with a
+// quote, so that opening an export never evaluates what users typed
+func spreadsheetSafe(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
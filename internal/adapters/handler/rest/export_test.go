@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// exportService exports todos, then fails with err
+func exportService(err error, todos ...*application.TodoResponse) *fakeService {
+	return &fakeService{
+		exportTodos: func(ctx context.Context, filters application.ExportFilters, emit func(*application.TodoResponse) error) error {
+			for _, todo := range todos {
+				if err := emit(todo); err != nil {
+					return err
+				}
+			}
+			return err
+		},
+	}
+}
+
+func exportedTodos() []*application.TodoResponse {
+	created := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	due := created.AddDate(0, 0, 7)
+	return []*application.TodoResponse{
+		{ID: "aaa", Title: "=SUM(A1:A9)", Status: "pending", Priority: "high", DueDate: &due, CreatedAt: created, UpdatedAt: created},
+		{ID: "bbb", ShortCode: "TD-2", Title: "Plain", Description: "two\nlines", Status: "completed", Priority: "low", CreatedAt: created, UpdatedAt: created, Version: 3},
+	}
+}
+
+func TestHandler_ExportTodos_NDJSON(t *testing.T) {
+	var got application.ExportFilters
+	service := exportService(nil, exportedTodos()...)
+	export := service.exportTodos
+	service.exportTodos = func(ctx context.Context, filters application.ExportFilters, emit func(*application.TodoResponse) error) error {
+		got = filters
+		return export(ctx, filters, emit)
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/api/todos/export?status=pending&archived=exclude", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Status = %d, Content-Type = %q, want 200 and NDJSON", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if got.Status == nil || *got.Status != "pending" || got.Archived == nil || *got.Archived != "exclude" || got.Priority != nil {
+		t.Errorf("ExportTodos() called with %+v, want the query filters", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("exported %d lines, want 2: %s", len(lines), rec.Body.String())
+	}
+	var first todoResponse
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decoding first line: %v", err)
+	}
+	if first.ID != "aaa" || first.DueDate == nil || !first.DueDate.Equal(*exportedTodos()[0].DueDate) {
+		t.Errorf("first todo = %+v, want aaa with its due date", first)
+	}
+}
+
+func TestHandler_ExportTodos_CSV(t *testing.T) {
+	rec := serveRequest(t, exportService(nil, exportedTodos()...), httptest.NewRequest(http.MethodGet, "/api/todos/export?format=csv", nil))
+
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("Status = %d, Content-Type = %q, want 200 and CSV", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	want := [][]string{
+		exportColumns,
+		{"aaa", "", "'=SUM(A1:A9)", "", "pending", "high", "2026-03-09T08:30:00Z", "2026-03-02T08:30:00Z", "2026-03-02T08:30:00Z", "", "0"},
+		{"bbb", "TD-2", "Plain", "two\nlines", "completed", "low", "", "2026-03-02T08:30:00Z", "2026-03-02T08:30:00Z", "", "3"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %d", rows, len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestHandler_ExportTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		todos    []*application.TodoResponse
+		want     int
+		wantBody string
+	}{
+		{name: "unknown format", query: "?format=xlsx", want: http.StatusBadRequest},
+		{name: "invalid filter", query: "?status=someday", err: domain.ErrInvalidStatus, want: http.StatusBadRequest},
+		{name: "empty CSV", query: "?format=csv", want: http.StatusOK, wantBody: strings.Join(exportColumns, ",") + "\n"},
+		{name: "failure after the first todo", err: errors.New("connection lost"), todos: exportedTodos()[:1], want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveRequest(t, exportService(tt.err, tt.todos...), httptest.NewRequest(http.MethodGet, "/api/todos/export"+tt.query, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// todoFields are the JSON fields of a todo a sparse fieldset may select
+var todoFields = jsonFields(reflect.TypeFor[todoResponse]())
+
+// fieldset is a sparse fieldset: the fields of the todos a client asked
+// for, every field when nil
+// The id is always returned, as it identifies the todo
+type fieldset map[string]bool
+
+// parseFieldset reads the sparse fieldset of ?fields=id,title,status, as
+// in JSON:API
+// Todos have no relationship to expand, so ?include= is rejected, as
+// JSON:API requires for unsupported inclusion paths
+func parseFieldset(r *http.Request) (fieldset, error) {
+	query := r.URL.Query()
+	if include := query.Get("include"); include != "" {
+		return nil, fmt.Errorf("include is not supported: todos have no relationship to include, got %s", include)
+	}
+	if !query.Has("fields") {
+		return nil, nil
+	}
+
+	fields := fieldset{}
+	for _, name := range strings.Split(query.Get("fields"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !todoFields[name] {
+			return nil, fmt.Errorf("unknown field %q, want some of %s", name, strings.Join(slices.Sorted(maps.Keys(todoFields)), ", "))
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// todo renders a todo with the fieldset
+func (f fieldset) todo(todo todoResponse) todoResponse {
+	todo.fields = f
+	return todo
+}
+
+// todos renders todos with the fieldset
+func (f fieldset) todos(todos []todoResponse) []todoResponse {
+	for i := range todos {
+		todos[i].fields = f
+	}
+	return todos
+}
+
+// MarshalJSON encodes the fields of the fieldset of the todo, all of them
+// without one
+func (t todoResponse) MarshalJSON() ([]byte, error) {
+	type plain todoResponse
+	data, err := json.Marshal(plain(t))
+	if err != nil || t.fields == nil {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for name := range all {
+		if name != "id" && !t.fields[name] {
+			delete(all, name)
+		}
+	}
+	return json.Marshal(all)
+}
+
+// jsonFields returns the names of the JSON fields of a struct type
+func jsonFields(typ reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+func TestHandler_SparseFieldsets(t *testing.T) {
+	todo := &application.TodoResponse{ID: "123", Title: "Buy milk", Description: "Semi-skimmed", Status: "pending", Priority: "low"}
+	service := &fakeService{
+		listRecentTodos: func(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error) {
+			return []*application.TodoResponse{todo}, nil
+		},
+		searchTodos: func(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error) {
+			return &application.SearchTodosResponse{Todos: []*application.TodoResponse{todo}}, nil
+		},
+		getTodoAsOf: func(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error) {
+			return todo, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"recent", "/api/todos/recent?fields=title,status", []string{"id", "status", "title"}},
+		{"search", "/api/todos/search?q=milk&fields=title", []string{"id", "title"}},
+		{"as of", "/api/todos/123/as-of?at=2030-01-02T15:04:05Z&fields=status,%20priority", []string{"id", "priority", "status"}},
+		{"only the id", "/api/todos/recent?fields=", []string{"id"}},
+		{"every field", "/api/todos/recent", []string{"created_at", "description", "id", "priority", "status", "title", "updated_at"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, service, tt.target)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			got := slices.Sorted(maps.Keys(firstTodo(t, rec.Body.Bytes())))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// firstTodo decodes the todo of a response, or the first of its todos
+func firstTodo(t *testing.T, body []byte) map[string]json.RawMessage {
+	t.Helper()
+	var todo map[string]json.RawMessage
+	if err := json.Unmarshal(body, &todo); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if list, ok := todo["todos"]; ok {
+		var todos []map[string]json.RawMessage
+		if err := json.Unmarshal(list, &todos); err != nil || len(todos) == 0 {
+			t.Fatalf("decoding todos %s: %v", list, err)
+		}
+		return todos[0]
+	}
+	return todo
+}
+
+func TestHandler_SparseFieldsets_Errors(t *testing.T) {
+	service := &fakeService{
+		listRecentTodos: func(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error) {
+			t.Error("ListRecentTodos() called despite an invalid fieldset")
+			return nil, nil
+		},
+	}
+
+	for _, target := range []string{
+		"/api/todos/recent?fields=title,owner",
+		"/api/todos/recent?fields=fields",
+		"/api/todos/recent?include=tags,subtasks",
+	} {
+		if rec := serve(t, service, target); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
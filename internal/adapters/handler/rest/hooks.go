@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// MaxHookPayloadSize bounds the JSON payload of an inbound webhook
+const MaxHookPayloadSize = 1 << 20
+
+// receiveHook answers POST /hooks/{token}, creating a todo from any JSON
+// payload through the mapping of the hook; the token is the credential
+func (h *Handler) receiveHook(w http.ResponseWriter, r *http.Request) {
+	var payload any
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxHookPayloadSize)).Decode(&payload); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "payload too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	todo, err := h.service.ReceiveHook(r.Context(), r.PathValue("token"), payload)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, mapTodo(todo))
+}
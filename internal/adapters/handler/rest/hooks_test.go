@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_ReceiveHook_CreatesTodo(t *testing.T) {
+	var gotToken string
+	var gotPayload any
+	service := &fakeService{
+		receiveHook: func(ctx context.Context, token string, payload any) (*application.TodoResponse, error) {
+			gotToken, gotPayload = token, payload
+			return &application.TodoResponse{ID: "123", Title: "DiskFull", Status: "pending"}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/tok", strings.NewReader(`{"alert":{"name":"DiskFull"}}`))
+	rec := serveRequest(t, service, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	if gotToken != "tok" {
+		t.Errorf("ReceiveHook() token = %q, want %q", gotToken, "tok")
+	}
+	alert, _ := gotPayload.(map[string]any)["alert"].(map[string]any)
+	if alert["name"] != "DiskFull" {
+		t.Errorf("ReceiveHook() payload = %v, want the decoded JSON", gotPayload)
+	}
+
+	var body todoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.ID != "123" {
+		t.Errorf("ID = %q, want %q", body.ID, "123")
+	}
+}
+
+func TestHandler_ReceiveHook_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid JSON", `{"alert":`, nil, http.StatusBadRequest},
+		{"too large", `"` + strings.Repeat("x", MaxHookPayloadSize) + `"`, nil, http.StatusRequestEntityTooLarge},
+		{"unknown token", `{}`, application.ErrInboundHookNotFound, http.StatusNotFound},
+		{"unmappable payload", `{}`, domain.NewValidationError("title", "cannot be mapped"), http.StatusBadRequest},
+		{"invalid title", `{}`, domain.ErrInvalidTitle, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				receiveHook: func(ctx context.Context, token string, payload any) (*application.TodoResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/hooks/tok", strings.NewReader(tt.body))
+			rec := serveRequest(t, service, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_ReceiveHook_DoesNotLogToken(t *testing.T) {
+	service := &fakeService{
+		receiveHook: func(ctx context.Context, token string, payload any) (*application.TodoResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	NewHandler(service, slog.New(slog.NewTextHandler(&logs, nil))).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hooks/s3cr3t-token", strings.NewReader(`{}`)))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if logs.Len() == 0 || strings.Contains(logs.String(), "s3cr3t") {
+		t.Errorf("logs = %q, want the failure logged without the token", logs.String())
+	}
+}
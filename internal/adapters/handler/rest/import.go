@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MaxImportBodySize bounds the body of a CSV import
+const MaxImportBodySize = 8 << 20
+
+// importedTodoResponse is the JSON representation of the todo created from
+// a line of an import file
+type importedTodoResponse struct {
+	Line int          `json:"line"`
+	Todo todoResponse `json:"todo"`
+}
+
+// importFailureResponse is the JSON representation of a line of an import
+// file that created no todo, with the status code creating the todo alone
+// would have answered
+type importFailureResponse struct {
+	Line   int    `json:"line"`
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// importResponse is the JSON response of an import
+type importResponse struct {
+	Imported []importedTodoResponse  `json:"imported"`
+	Failures []importFailureResponse `json:"failures"`
+	Skipped  []int                   `json:"skipped,omitempty"`
+}
+
+// importTodos answers POST /api/todos/import, creating a todo from each row
+// of a CSV file sent as a text/csv body or as the "file" field of a
+// multipart form, and listing the rows refused
+// The header row names the columns: title is required, description,
+// priority, status and due_date, kept when already past, are read when
+// present and any other column, such as the IDs and timestamps of a CSV
+// export, is ignored
+// With Prefer: respond-async, the file is read then imported by an
+// operation, whose result is this response, partial when it is cancelled
+func (h *Handler) importTodos(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, MaxImportBodySize)
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, "content type must be text/csv or multipart/form-data")
+		return
+	}
+
+	var file io.Reader
+	switch mediaType {
+	case "text/csv":
+		file = body
+	case "multipart/form-data":
+		r.Body = body
+		file, err = multipartFile(r, "file")
+		if err != nil {
+			writeImportReadError(w, err)
+			return
+		}
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, "content type must be text/csv or multipart/form-data")
+		return
+	}
+
+	rows, err := readImportRows(file)
+	if err != nil {
+		writeImportReadError(w, err)
+		return
+	}
+
+	if h.respondAsync(r) {
+		h.startOperation(w, r, application.OperationImport, func(ctx context.Context, progress func(done, total int)) (any, error) {
+			result, err := h.service.ImportTodos(ctx, application.ImportTodosRequest{
+				Rows: rows,
+				Progress: func(p application.ImportProgress) {
+					progress(p.Saved+p.Failed, p.Total)
+				},
+			})
+			if result == nil {
+				return nil, err
+			}
+			return mapImport(result), err
+		})
+		return
+	}
+
+	result, err := h.service.ImportTodos(r.Context(), application.ImportTodosRequest{Rows: rows})
+	if err != nil {
+		if result != nil && r.Context().Err() != nil {
+			// Nobody is left to answer
+			h.logger.Info("import cancelled by the client", "path", r.URL.Path,
+				"imported", len(result.Imported), "skipped", len(result.Skipped))
+			return
+		}
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapImport(result))
+}
+
+// mapImport converts an application ImportTodosResponse to its JSON
+// representation
+func mapImport(result *application.ImportTodosResponse) importResponse {
+	response := importResponse{
+		Imported: make([]importedTodoResponse, len(result.Imported)),
+		Failures: make([]importFailureResponse, len(result.Failures)),
+		Skipped:  result.Skipped,
+	}
+	for i, imported := range result.Imported {
+		response.Imported[i] = importedTodoResponse{Line: imported.Line, Todo: mapTodo(imported.Todo)}
+	}
+	for i, failure := range result.Failures {
+		response.Failures[i] = importFailureResponse{
+			Line:   failure.Line,
+			Status: statusForError(failure.Err),
+			Error:  failure.Err.Error(),
+		}
+	}
+	return response
+}
+
+// writeImportReadError answers an import whose file could not be read
+func writeImportReadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "file too large")
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// multipartFile returns the content of the field name of the multipart
+// form of r
+func multipartFile(r *http.Request, name string) (io.Reader, error) {
+	form, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("invalid multipart body: %w", err)
+	}
+
+	for {
+		part, err := form.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the form has no %q field", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+		if part.FormName() == name {
+			return part, nil
+		}
+	}
+}
+
+// readImportRows reads the header then the rows of a CSV file, numbered by
+// the line they start on
+// A row whose due date cannot be read is returned with its error; reading
+// stops one row past application.MaxImportRows, which the service refuses
+func readImportRows(file io.Reader) ([]application.ImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets may save a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("the header row has no title column")
+	}
+
+	var rows []application.ImportRow
+	for len(rows) <= application.MaxImportRows {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return unquoteSpreadsheetSafe(strings.TrimSpace(record[i]))
+		}
+
+		// Exported todos may have become overdue since
+		row := application.ImportRow{
+			Line: line,
+			Request: application.CreateTodoRequest{
+				Title:       cell("title"),
+				Description: cell("description"),
+				Priority:    cell("priority"),
+			},
+			Status:          cell("status"),
+			KeepPastDueDate: true,
+		}
+		if row.Request.Priority == "" {
+			row.Request.Priority = domain.PriorityMedium.String()
+		}
+		if raw := cell("due_date"); raw != "" {
+			dueDate, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				row.Err = domain.NewValidationError("due_date", "must be an RFC 3339 timestamp")
+			}
+			row.Request.DueDate = &dueDate
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// unquoteSpreadsheetSafe removes the quote spreadsheetSafe prefixes, so
+// that an export imports back as written
+func unquoteSpreadsheetSafe(text string) string {
+	if len(text) > 1 && text[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(text[1])) {
+		return text[1:]
+	}
+	return text
+}
@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// importCSV is an export file with a multiline description, a
+// spreadsheet-safe title, a bad due date and no priority
+const importCSV = "id,title,description,status,priority,due_date\r\n" +
+	"aaa,Write the report,\"Two\nlines\",completed,high,2030-01-02T15:04:05Z\r\n" +
+	"bbb,'=SUM(A1),,pending,,tomorrow\r\n" +
+	"ccc,Plan the launch,,pending,low,\r\n"
+
+func TestHandler_ImportTodos(t *testing.T) {
+	var got application.ImportTodosRequest
+	service := &fakeService{
+		importTodos: func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+			got = req
+			return &application.ImportTodosResponse{
+				Imported: []application.ImportedTodo{{Line: 2, Todo: &application.TodoResponse{ID: "ddd", Title: "Write the report"}}},
+				Failures: []application.ImportFailure{{Line: 4, Err: req.Rows[1].Err}, {Line: 5, Err: errors.New("saving batch: connection reset")}},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todos/import", strings.NewReader(importCSV))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	rec := serveRequest(t, service, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(got.Rows) != 3 {
+		t.Fatalf("ImportTodos() called with %d rows, want 3", len(got.Rows))
+	}
+	first, second, third := got.Rows[0], got.Rows[1], got.Rows[2]
+	if first.Line != 2 || first.Request.Title != "Write the report" || first.Request.Description != "Two\nlines" || first.Request.Priority != "high" || first.Request.DueDate == nil || first.Err != nil {
+		t.Errorf("row 1 = %+v, want the first todo from line 2", first)
+	}
+	if first.Status != "completed" || !first.KeepPastDueDate {
+		t.Errorf("row 1 = %+v, want completed, keeping a past due date", first)
+	}
+	var validationErr domain.ValidationError
+	if second.Line != 4 || second.Request.Title != "=SUM(A1)" || second.Request.Priority != "medium" || !errors.As(second.Err, &validationErr) {
+		t.Errorf("row 2 = %+v, want line 4 unquoted, medium and a due date error", second)
+	}
+	if third.Line != 5 || third.Request.DueDate != nil {
+		t.Errorf("row 3 = %+v, want line 5 without due date", third)
+	}
+
+	var response importResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(response.Imported) != 1 || response.Imported[0].Line != 2 || response.Imported[0].Todo.ID != "ddd" {
+		t.Errorf("imported = %+v, want ddd from line 2", response.Imported)
+	}
+	if len(response.Failures) != 2 || response.Failures[0].Status != http.StatusBadRequest || response.Failures[1].Status != http.StatusInternalServerError {
+		t.Errorf("failures = %+v, want 400 then 500", response.Failures)
+	}
+}
+
+func TestReadImportRows_ExportRoundTrip(t *testing.T) {
+	dueDate := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	archivedAt := dueDate.Add(time.Hour)
+	exported := &application.TodoResponse{
+		ID:          "aaa",
+		ShortCode:   "abc123",
+		Title:       "=Pay the invoice",
+		Description: "Overdue since January",
+		Status:      "completed",
+		Priority:    "urgent",
+		DueDate:     &dueDate,
+		CreatedAt:   dueDate.Add(-time.Hour),
+		UpdatedAt:   archivedAt,
+		ArchivedAt:  &archivedAt,
+		Version:     3,
+	}
+
+	var file bytes.Buffer
+	out := &csvTodoWriter{out: csv.NewWriter(&file)}
+	if err := out.start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.write(exported); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := readImportRows(&file)
+	if err != nil {
+		t.Fatalf("readImportRows() unexpected error: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("readImportRows() = %+v, want 1 row", rows)
+	}
+	row := rows[0]
+	if row.Err != nil || row.Request.Title != exported.Title || row.Request.Description != exported.Description || row.Request.Priority != exported.Priority {
+		t.Errorf("row = %+v, want the title, description and priority of the export", row)
+	}
+	if row.Status != exported.Status || !row.KeepPastDueDate || row.Request.DueDate == nil || !row.Request.DueDate.Equal(dueDate) {
+		t.Errorf("row = %+v, want completed and due %v", row, dueDate)
+	}
+}
+
+func TestHandler_ImportTodos_Multipart(t *testing.T) {
+	var got application.ImportTodosRequest
+	service := &fakeService{
+		importTodos: func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+			got = req
+			return &application.ImportTodosResponse{}, nil
+		},
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("comment", "weekly"); err != nil {
+		t.Fatal(err)
+	}
+	file, err := form.CreateFormFile("file", "todos.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte("\ufeffTitle\nWrite the report\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todos/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := serveRequest(t, service, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(got.Rows) != 1 || got.Rows[0].Request.Title != "Write the report" {
+		t.Errorf("ImportTodos() called with %+v, want the row of the file", got.Rows)
+	}
+}
+
+func TestHandler_ImportTodos_Errors(t *testing.T) {
+	service := &fakeService{
+		importTodos: func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+			return nil, application.ErrNotSupported
+		},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"no content type", "", "title\nWrite\n", http.StatusUnsupportedMediaType},
+		{"JSON", "application/json", `{"title":"Write"}`, http.StatusUnsupportedMediaType},
+		{"empty file", "text/csv", "", http.StatusBadRequest},
+		{"no title column", "text/csv", "name\nWrite\n", http.StatusBadRequest},
+		{"malformed CSV", "text/csv", "title\n\"Write\"the\n", http.StatusBadRequest},
+		{"no file field", "multipart/form-data; boundary=x", "--x--\r\n", http.StatusBadRequest},
+		{"too large", "text/csv", "title\n" + strings.Repeat("x", MaxImportBodySize), http.StatusRequestEntityTooLarge},
+		{"not supported", "text/csv", "title\nWrite\n", http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/todos/import", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			if rec := serveRequest(t, service, req); rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// createMilestoneRequest is the JSON body of POST /api/milestones
+type createMilestoneRequest struct {
+	Name       string `json:"name"`
+	TargetDate string `json:"target_date"`
+}
+
+// attachTodoRequest is the JSON body of
+// PUT /api/milestones/{id}/todos/{todoID}, which may be empty
+type attachTodoRequest struct {
+	EstimateMinutes int `json:"estimate_minutes"`
+}
+
+// milestoneResponse is the JSON representation of a milestone
+type milestoneResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	TargetDate  string     `json:"target_date"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// milestoneTodoResponse is the JSON representation of a todo counted in the
+// progress of a milestone
+type milestoneTodoResponse struct {
+	TodoID          string `json:"todo_id"`
+	Status          string `json:"status"`
+	EstimateMinutes int    `json:"estimate_minutes"`
+}
+
+// milestoneProgressResponse is the JSON response of the progress of a
+// milestone
+type milestoneProgressResponse struct {
+	Milestone                milestoneResponse       `json:"milestone"`
+	Total                    int                     `json:"total"`
+	Completed                int                     `json:"completed"`
+	TotalEstimateMinutes     int                     `json:"total_estimate_minutes"`
+	CompletedEstimateMinutes int                     `json:"completed_estimate_minutes"`
+	Percent                  float64                 `json:"percent"`
+	Todos                    []milestoneTodoResponse `json:"todos"`
+}
+
+// createMilestone answers POST /api/milestones with the created milestone
+func (h *Handler) createMilestone(w http.ResponseWriter, r *http.Request) {
+	var body createMilestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	milestone, err := h.service.CreateMilestone(r.Context(), application.CreateMilestoneRequest(body))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, milestoneResponse(*milestone))
+}
+
+// listMilestones answers GET /api/milestones?archived=, earliest target
+// first, archived milestones left out unless archived says otherwise
+func (h *Handler) listMilestones(w http.ResponseWriter, r *http.Request) {
+	var archived *string
+	if value := r.URL.Query().Get("archived"); value != "" {
+		archived = &value
+	}
+
+	milestones, err := h.service.ListMilestones(r.Context(), archived)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := make([]milestoneResponse, len(milestones))
+	for i, milestone := range milestones {
+		response[i] = milestoneResponse(*milestone)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// deleteMilestone answers DELETE /api/milestones/{id}
+func (h *Handler) deleteMilestone(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteMilestone(r.Context(), r.PathValue("id")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// archiveMilestone answers POST /api/milestones/{id}/archive with the
+// archived milestone
+func (h *Handler) archiveMilestone(w http.ResponseWriter, r *http.Request) {
+	milestone, err := h.service.ArchiveMilestone(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, milestoneResponse(*milestone))
+}
+
+// unarchiveMilestone answers POST /api/milestones/{id}/unarchive with the
+// unarchived milestone
+func (h *Handler) unarchiveMilestone(w http.ResponseWriter, r *http.Request) {
+	milestone, err := h.service.UnarchiveMilestone(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, milestoneResponse(*milestone))
+}
+
+// getMilestoneProgress answers GET /api/milestones/{id}/progress
+func (h *Handler) getMilestoneProgress(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.service.GetMilestoneProgress(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := milestoneProgressResponse{
+		Milestone:                milestoneResponse(*progress.Milestone),
+		Total:                    progress.Total,
+		Completed:                progress.Completed,
+		TotalEstimateMinutes:     progress.TotalEstimateMinutes,
+		CompletedEstimateMinutes: progress.CompletedEstimateMinutes,
+		Percent:                  progress.Percent,
+		Todos:                    make([]milestoneTodoResponse, len(progress.Todos)),
+	}
+	for i, todo := range progress.Todos {
+		response.Todos[i] = milestoneTodoResponse(todo)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// attachTodo answers PUT /api/milestones/{id}/todos/{todoID}
+func (h *Handler) attachTodo(w http.ResponseWriter, r *http.Request) {
+	var body attachTodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	err := h.service.AttachTodo(r.Context(), r.PathValue("id"), r.PathValue("todoID"), body.EstimateMinutes)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// detachTodo answers DELETE /api/milestones/{id}/todos/{todoID}
+func (h *Handler) detachTodo(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DetachTodo(r.Context(), r.PathValue("id"), r.PathValue("todoID")); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_CreateMilestone(t *testing.T) {
+	var got application.CreateMilestoneRequest
+	service := &fakeService{
+		createMilestone: func(ctx context.Context, req application.CreateMilestoneRequest) (*application.MilestoneResponse, error) {
+			got = req
+			return &application.MilestoneResponse{ID: "m-1", Name: req.Name, TargetDate: req.TargetDate}, nil
+		},
+	}
+
+	body := strings.NewReader(`{"name": "Beta", "target_date": "2026-11-30"}`)
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/milestones", body))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if got != (application.CreateMilestoneRequest{Name: "Beta", TargetDate: "2026-11-30"}) {
+		t.Errorf("CreateMilestone() called with %+v", got)
+	}
+
+	var milestone milestoneResponse
+	if err := json.NewDecoder(rec.Body).Decode(&milestone); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if milestone.ID != "m-1" || milestone.TargetDate != "2026-11-30" || milestone.CompletedAt != nil {
+		t.Errorf("response = %+v, want the open milestone m-1", milestone)
+	}
+}
+
+func TestHandler_GetMilestoneProgress(t *testing.T) {
+	service := &fakeService{
+		milestoneProgress: func(ctx context.Context, id string) (*application.MilestoneProgress, error) {
+			if id != "m-1" {
+				return nil, application.ErrMilestoneNotFound
+			}
+			return &application.MilestoneProgress{
+				Milestone:                &application.MilestoneResponse{ID: "m-1", Name: "Beta"},
+				Total:                    2,
+				Completed:                1,
+				TotalEstimateMinutes:     120,
+				CompletedEstimateMinutes: 90,
+				Percent:                  75,
+				Todos: []application.MilestoneTodoProgress{
+					{TodoID: "aaa", Status: "completed", EstimateMinutes: 90},
+					{TodoID: "bbb", Status: "pending", EstimateMinutes: 30},
+				},
+			}, nil
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/api/milestones/m-1/progress", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var progress milestoneProgressResponse
+	if err := json.NewDecoder(rec.Body).Decode(&progress); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if progress.Milestone.ID != "m-1" || progress.Percent != 75 || len(progress.Todos) != 2 || progress.Todos[0].EstimateMinutes != 90 {
+		t.Errorf("response = %+v, want m-1 75%% done", progress)
+	}
+
+	rec = serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/api/milestones/m-2/progress", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown milestone status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandler_MilestoneTodos(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		err          error
+		want         int
+		wantEstimate int
+	}{
+		{name: "attached", method: http.MethodPut, body: `{"estimate_minutes": 45}`, want: http.StatusNoContent, wantEstimate: 45},
+		{name: "attached without body", method: http.MethodPut, want: http.StatusNoContent},
+		{name: "invalid body", method: http.MethodPut, body: `{`, want: http.StatusBadRequest},
+		{name: "unknown todo", method: http.MethodPut, err: domain.ErrTodoNotFound, want: http.StatusNotFound},
+		{name: "detached", method: http.MethodDelete, want: http.StatusNoContent},
+		{name: "not attached", method: http.MethodDelete, err: application.ErrMilestoneTodoNotFound, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [2]string
+			gotEstimate := -1
+			service := &fakeService{
+				attachTodo: func(ctx context.Context, milestoneID, todoID string, estimateMinutes int) error {
+					got, gotEstimate = [2]string{milestoneID, todoID}, estimateMinutes
+					return tt.err
+				},
+				detachTodo: func(ctx context.Context, milestoneID, todoID string) error {
+					got = [2]string{milestoneID, todoID}
+					return tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(tt.method, "/api/milestones/m-1/todos/TD-7", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusBadRequest {
+				return
+			}
+			if got != [2]string{"m-1", "TD-7"} {
+				t.Errorf("called with %v, want TD-7 in m-1", got)
+			}
+			if tt.method == http.MethodPut && gotEstimate != tt.wantEstimate {
+				t.Errorf("estimate = %d, want %d", gotEstimate, tt.wantEstimate)
+			}
+		})
+	}
+}
+
+func TestHandler_MilestoneArchival(t *testing.T) {
+	archivedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	var listed *string
+	service := &fakeService{
+		listMilestones: func(ctx context.Context, archived *string) ([]*application.MilestoneResponse, error) {
+			listed = archived
+			return []*application.MilestoneResponse{{ID: "m-1", ArchivedAt: &archivedAt}}, nil
+		},
+		archiveMilestone: func(ctx context.Context, id string, archive bool) (*application.MilestoneResponse, error) {
+			switch {
+			case id != "m-1":
+				return nil, application.ErrMilestoneNotFound
+			case !archive:
+				return &application.MilestoneResponse{ID: id}, nil
+			}
+			return &application.MilestoneResponse{ID: id, ArchivedAt: &archivedAt}, nil
+		},
+		deleteMilestone: func(ctx context.Context, id string) error {
+			return application.ErrMilestoneArchived
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodGet, "/api/milestones?archived=only", nil))
+	if rec.Code != http.StatusOK || listed == nil || *listed != "only" {
+		t.Errorf("GET /api/milestones?archived=only = %d, ListMilestones() called with %v", rec.Code, listed)
+	}
+
+	tests := []struct {
+		path     string
+		want     int
+		archived bool
+	}{
+		{"/api/milestones/m-1/archive", http.StatusOK, true},
+		{"/api/milestones/m-1/unarchive", http.StatusOK, false},
+		{"/api/milestones/m-2/archive", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("POST %s status = %d, want %d", tt.path, rec.Code, tt.want)
+			continue
+		}
+		if tt.want != http.StatusOK {
+			continue
+		}
+		var milestone milestoneResponse
+		if err := json.NewDecoder(rec.Body).Decode(&milestone); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if (milestone.ArchivedAt != nil) != tt.archived {
+			t.Errorf("POST %s archived_at = %v, want archived %v", tt.path, milestone.ArchivedAt, tt.archived)
+		}
+	}
+
+	// An archived milestone is kept until unarchived
+	rec = serveRequest(t, service, httptest.NewRequest(http.MethodDelete, "/api/milestones/m-1", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("DELETE of an archived milestone status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
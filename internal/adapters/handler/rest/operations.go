@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// Operations runs and reports on the long-running operations of the caller
+type Operations interface {
+	Start(ctx context.Context, kind string, run application.OperationFunc) (*application.OperationResponse, error)
+	GetOperation(ctx context.Context, id string) (*application.OperationResponse, error)
+	ListOperations(ctx context.Context, req application.OperationsRequest) (*application.OperationPage, error)
+	CancelOperation(ctx context.Context, id string) (*application.OperationResponse, error)
+}
+
+// WithOperations runs imports and bulk completions as long-running
+// operations when the client sends Prefer: respond-async, and serves the
+// operations under /api/operations
+func WithOperations(operations Operations) Option {
+	return func(h *Handler) {
+		h.operations = operations
+	}
+}
+
+// operationResponse is the JSON representation of a long-running operation
+type operationResponse struct {
+	ID              string          `json:"id"`
+	Kind            string          `json:"kind"`
+	Status          string          `json:"status"`
+	Done            int             `json:"done"`
+	Total           int             `json:"total,omitempty"`
+	Progress        *int            `json:"progress"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+// operationPageResponse is the JSON representation of a page of operations
+type operationPageResponse struct {
+	Operations []operationResponse `json:"operations"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// mapOperation converts an application OperationResponse to its JSON
+// representation
+func mapOperation(operation *application.OperationResponse) operationResponse {
+	return operationResponse{
+		ID:              operation.ID,
+		Kind:            operation.Kind,
+		Status:          operation.Status,
+		Done:            operation.Done,
+		Total:           operation.Total,
+		Progress:        operation.Progress,
+		Result:          operation.Result,
+		Error:           operation.Error,
+		CancelRequested: operation.CancelRequested,
+		CreatedAt:       operation.CreatedAt,
+		UpdatedAt:       operation.UpdatedAt,
+		FinishedAt:      operation.FinishedAt,
+	}
+}
+
+// respondAsync reports whether r prefers an asynchronous response
+// (RFC 7240) that the operations can give
+func (h *Handler) respondAsync(r *http.Request) bool {
+	if h.operations == nil {
+		return false
+	}
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			name, _, _ := strings.Cut(preference, "=")
+			name, _, _ = strings.Cut(name, ";")
+			if strings.EqualFold(strings.TrimSpace(name), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// startOperation answers 202 Accepted with the operation of kind running
+// run, to be polled at its Location
+func (h *Handler) startOperation(w http.ResponseWriter, r *http.Request, kind string, run application.OperationFunc) {
+	operation, err := h.operations.Start(r.Context(), kind, run)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/operations/"+operation.ID)
+	w.Header().Set("Preference-Applied", "respond-async")
+	writeJSON(w, http.StatusAccepted, mapOperation(operation))
+}
+
+// getOperation answers GET /api/operations/{id} with the operation and,
+// once finished, its result
+func (h *Handler) getOperation(w http.ResponseWriter, r *http.Request) {
+	if h.operations == nil {
+		h.writeServiceError(w, r, application.ErrNotSupported)
+		return
+	}
+
+	operation, err := h.operations.GetOperation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, mapOperation(operation))
+}
+
+// listOperations answers GET /api/operations?cursor=<next_cursor>&limit=<n>
+// with the operations of the caller, newest first
+func (h *Handler) listOperations(w http.ResponseWriter, r *http.Request) {
+	if h.operations == nil {
+		h.writeServiceError(w, r, application.ErrNotSupported)
+		return
+	}
+
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.operations.ListOperations(r.Context(), application.OperationsRequest{
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := operationPageResponse{Operations: make([]operationResponse, len(page.Operations)), NextCursor: page.NextCursor}
+	for i, operation := range page.Operations {
+		response.Operations[i] = mapOperation(operation)
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// cancelOperation answers POST /api/operations/{id}/cancel, asking a
+// running operation to stop
+func (h *Handler) cancelOperation(w http.ResponseWriter, r *http.Request) {
+	if h.operations == nil {
+		h.writeServiceError(w, r, application.ErrNotSupported)
+		return
+	}
+
+	operation, err := h.operations.CancelOperation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, mapOperation(operation))
+}
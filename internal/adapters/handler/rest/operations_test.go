@@ -0,0 +1,195 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeOperations runs operations synchronously, keeping their results
+// and errors by kind
+type fakeOperations struct {
+	results map[string]any
+	errs    map[string]error
+	err     error
+}
+
+func (f *fakeOperations) Start(ctx context.Context, kind string, run application.OperationFunc) (*application.OperationResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result, err := run(ctx, func(done, total int) {})
+	if f.results == nil {
+		f.results, f.errs = map[string]any{}, map[string]error{}
+	}
+	f.results[kind], f.errs[kind] = result, err
+	return &application.OperationResponse{ID: "0190f0c4-7b6a-7000-8000-000000000001", Kind: kind, Status: "running"}, nil
+}
+
+func (f *fakeOperations) GetOperation(ctx context.Context, id string) (*application.OperationResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	progress := 50
+	return &application.OperationResponse{ID: id, Kind: application.OperationImport, Status: "running", Done: 1, Total: 2, Progress: &progress}, nil
+}
+
+func (f *fakeOperations) ListOperations(ctx context.Context, req application.OperationsRequest) (*application.OperationPage, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.OperationPage{Operations: []*application.OperationResponse{{ID: "op-1", Status: "succeeded"}}, NextCursor: "op-1"}, nil
+}
+
+func (f *fakeOperations) CancelOperation(ctx context.Context, id string) (*application.OperationResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.OperationResponse{ID: id, Status: "running", CancelRequested: true}, nil
+}
+
+func serveOperations(t *testing.T, service TodoService, operations Operations, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	NewHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)), WithOperations(operations)).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_BulkCompleteTodos_Async(t *testing.T) {
+	calls := 0
+	service := &fakeService{
+		bulkComplete: func(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error) {
+			calls++
+			return &application.BulkCompleteResponse{
+				Completed: []*application.TodoResponse{{ID: "todo-" + string(rune('0'+calls))}},
+				More:      calls < 3,
+			}, nil
+		},
+	}
+	operations := &fakeOperations{}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todos/bulk-complete", strings.NewReader(`{"overdue":true}`))
+	req.Header.Set("Prefer", "wait=10, respond-async")
+	rec := serveOperations(t, service, operations, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	if location := rec.Header().Get("Location"); location != "/api/operations/0190f0c4-7b6a-7000-8000-000000000001" {
+		t.Errorf("Location = %q, want the operation", location)
+	}
+	if applied := rec.Header().Get("Preference-Applied"); applied != "respond-async" {
+		t.Errorf("Preference-Applied = %q, want respond-async", applied)
+	}
+
+	result, ok := operations.results[application.OperationBulkComplete].(bulkCompleteResponse)
+	if !ok || len(result.Completed) != 3 || result.More {
+		t.Errorf("operation result = %+v, want the 3 todos of every call", operations.results)
+	}
+}
+
+func TestHandler_ImportTodos_Async(t *testing.T) {
+	service := &fakeService{
+		importTodos: func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+			return &application.ImportTodosResponse{}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		operations Operations
+		prefer     string
+		want       int
+	}{
+		{"async", &fakeOperations{}, "respond-async", http.StatusAccepted},
+		{"sync without the preference", &fakeOperations{}, "", http.StatusOK},
+		{"sync without operations", nil, "respond-async", http.StatusOK},
+		{"busy", &fakeOperations{err: application.ErrOperationsBusy}, "respond-async", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/todos/import", strings.NewReader("title\nWater the plants\n"))
+			req.Header.Set("Content-Type", "text/csv")
+			if tt.prefer != "" {
+				req.Header.Set("Prefer", tt.prefer)
+			}
+
+			rec := serveOperations(t, service, tt.operations, req)
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestHandler_Operations(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations Operations
+		method     string
+		target     string
+		want       int
+		wantBody   string
+	}{
+		{"get", &fakeOperations{}, http.MethodGet, "/api/operations/op-1", http.StatusOK, `"progress":50`},
+		{"list", &fakeOperations{}, http.MethodGet, "/api/operations?limit=1", http.StatusOK, `"next_cursor":"op-1"`},
+		{"invalid limit", &fakeOperations{}, http.MethodGet, "/api/operations?limit=many", http.StatusBadRequest, ""},
+		{"cancel", &fakeOperations{}, http.MethodPost, "/api/operations/op-1/cancel", http.StatusAccepted, `"cancel_requested":true`},
+		{"not found", &fakeOperations{err: application.ErrOperationNotFound}, http.MethodGet, "/api/operations/op-2", http.StatusNotFound, ""},
+		{"already finished", &fakeOperations{err: application.ErrOperationFinished}, http.MethodPost, "/api/operations/op-1/cancel", http.StatusConflict, ""},
+		{"not configured", nil, http.MethodGet, "/api/operations", http.StatusNotImplemented, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveOperations(t, &fakeService{}, tt.operations, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if !json.Valid(rec.Body.Bytes()) || !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandler_ImportTodos_AsyncCancelled(t *testing.T) {
+	service := &fakeService{
+		importTodos: func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+			return &application.ImportTodosResponse{
+				Imported: []application.ImportedTodo{{Line: 2, Todo: &application.TodoResponse{ID: "ddd"}}},
+				Failures: []application.ImportFailure{},
+				Skipped:  []int{3},
+			}, context.Canceled
+		},
+	}
+	operations := &fakeOperations{}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todos/import", strings.NewReader("title\nWater the plants\nFeed the cat\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Prefer", "respond-async")
+	rec := serveOperations(t, service, operations, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	// The operation keeps the partial result with the cancellation
+	body, _ := json.Marshal(operations.results[application.OperationImport])
+	if err := operations.errs[application.OperationImport]; !errors.Is(err, context.Canceled) || !strings.Contains(string(body), `"skipped":[3]`) {
+		t.Errorf("operation result = %s, %v, want the partial import and context.Canceled", body, operations.errs)
+	}
+}
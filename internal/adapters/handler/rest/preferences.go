@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// preferences is the JSON representation of a client's ListTodos defaults
+type preferences struct {
+	DefaultPageSize  *int       `json:"default_page_size"`
+	DefaultStatus    *string    `json:"default_status"`
+	DefaultPriority  *string    `json:"default_priority"`
+	DefaultSort      *string    `json:"default_sort"`
+	DefaultSortOrder *string    `json:"default_sort_order"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// mapPreferences converts application Preferences to their JSON representation
+func mapPreferences(p *application.Preferences) preferences {
+	return preferences{
+		DefaultPageSize:  p.DefaultPageSize,
+		DefaultStatus:    p.DefaultStatus,
+		DefaultPriority:  p.DefaultPriority,
+		DefaultSort:      p.DefaultSort,
+		DefaultSortOrder: p.DefaultSortOrder,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+// getPreferences answers GET /api/preferences
+func (h *Handler) getPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.service.GetPreferences(r.Context())
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	// Per-client content
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, mapPreferences(prefs))
+}
+
+// putPreferences answers PUT /api/preferences, replacing every default;
+// omitted or null fields clear the matching default
+func (h *Handler) putPreferences(w http.ResponseWriter, r *http.Request) {
+	var body preferences
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), application.Preferences{
+		DefaultPageSize:  body.DefaultPageSize,
+		DefaultStatus:    body.DefaultStatus,
+		DefaultPriority:  body.DefaultPriority,
+		DefaultSort:      body.DefaultSort,
+		DefaultSortOrder: body.DefaultSortOrder,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapPreferences(prefs))
+}
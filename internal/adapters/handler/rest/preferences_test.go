@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_GetPreferences_Success(t *testing.T) {
+	pageSize := 20
+	service := &fakeService{
+		getPreferences: func(ctx context.Context) (*application.Preferences, error) {
+			return &application.Preferences{DefaultPageSize: &pageSize}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/preferences")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var body preferences
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if body.DefaultPageSize == nil || *body.DefaultPageSize != 20 || body.DefaultStatus != nil {
+		t.Errorf("Response = %+v, want only a page size of 20", body)
+	}
+}
+
+func TestHandler_PutPreferences_Success(t *testing.T) {
+	var got application.Preferences
+	service := &fakeService{
+		updatePreferences: func(ctx context.Context, req application.Preferences) (*application.Preferences, error) {
+			got = req
+			return &req, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/preferences", strings.NewReader(`{"default_status":"pending"}`))
+	rec := serveRequest(t, service, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got.DefaultStatus == nil || *got.DefaultStatus != "pending" || got.DefaultPageSize != nil {
+		t.Errorf("UpdatePreferences() request = %+v, want only a pending status", got)
+	}
+}
+
+func TestHandler_PutPreferences_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid JSON", `{`, nil, http.StatusBadRequest},
+		{"invalid page size", `{"default_page_size":0}`, domain.NewValidationError("default_page_size", "must be between 1 and 100"), http.StatusBadRequest},
+		{"invalid status", `{"default_status":"someday"}`, domain.ErrInvalidStatus, http.StatusBadRequest},
+		{"unauthenticated", `{}`, application.ErrUnauthenticated, http.StatusUnauthorized},
+		{"not configured", `{}`, application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				updatePreferences: func(ctx context.Context, req application.Preferences) (*application.Preferences, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/preferences", strings.NewReader(tt.body))
+			rec := serveRequest(t, service, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
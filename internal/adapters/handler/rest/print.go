@@ -0,0 +1,238 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/pdf"
+)
+
+// printDocument is a todo or a list of todos rendered for printing
+type printDocument struct {
+	Title     string
+	Filters   string
+	Generated time.Time
+	Todos     []*application.TodoResponse
+}
+
+// printTemplate renders a standalone HTML document, styled for paper
+var printTemplate = template.Must(template.New("print").Funcs(template.FuncMap{
+	"done":  isDone,
+	"stamp": formatStamp,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Georgia, serif; max-width: 48rem; margin: 2rem auto; color: #000; }
+h1 { font-size: 1.5rem; border-bottom: 1px solid #000; padding-bottom: .25rem; }
+.meta { font-size: .8rem; color: #444; }
+.todo { margin: 1rem 0; page-break-inside: avoid; }
+.todo h2 { font-size: 1.1rem; margin: 0; }
+.box { display: inline-block; width: 1.5rem; }
+.details { font-size: .85rem; color: #444; margin-left: 1.5rem; }
+.description { white-space: pre-wrap; margin: .25rem 0 0 1.5rem; }
+@media print { body { margin: 0; max-width: none; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{if .Filters}}{{.Filters}} · {{end}}Generated {{stamp .Generated}}</p>
+{{range .Todos}}<div class="todo">
+<h2><span class="box">{{if done .Status}}&#9745;{{else}}&#9744;{{end}}</span>{{.Title}}</h2>
+<div class="details">{{.Status}} · {{.Priority}} priority{{if .DueDate}} · due {{stamp .DueDate}}{{end}}{{if .ShortCode}} · {{.ShortCode}}{{end}}</div>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}</div>
+{{else}}<p>No todos.</p>
+{{end}}</body>
+</html>
+`))
+
+// printTodo answers GET /api/todos/{id}/print?format=pdf|html
+func (h *Handler) printTodo(w http.ResponseWriter, r *http.Request) {
+	format, ok := printFormat(w, r)
+	if !ok {
+		return
+	}
+
+	todo, err := h.service.GetTodo(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	h.writePrint(w, r, format, "todo-"+todo.ID, printDocument{
+		Title:     todo.Title,
+		Generated: time.Now().UTC(),
+		Todos:     []*application.TodoResponse{todo},
+	})
+}
+
+// printTodos answers
+// GET /api/todos/print?status=&priority=&sort_by=&sort_order=&limit=&format=,
+// rendering the todos matching the ListTodos filters as a checklist
+func (h *Handler) printTodos(w http.ResponseWriter, r *http.Request) {
+	format, ok := printFormat(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var filters application.ListFilters
+	var described []string
+	query := r.URL.Query()
+	if status := query.Get("status"); status != "" {
+		filters.Status = &status
+		described = append(described, "status "+status)
+	}
+	if priority := query.Get("priority"); priority != "" {
+		filters.Priority = &priority
+		described = append(described, "priority "+priority)
+	}
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		filters.SortBy = &sortBy
+		described = append(described, "sorted by "+sortBy)
+	}
+	if sortOrder := query.Get("sort_order"); sortOrder != "" {
+		filters.SortOrder = &sortOrder
+	}
+	if archived := query.Get("archived"); archived != "" {
+		filters.Archived = &archived
+		described = append(described, "archived "+archived)
+	}
+	if limit > 0 {
+		filters.Limit = &limit
+	}
+
+	list, err := h.service.ListTodos(r.Context(), filters)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+	// Rewrites of the query are reported outside the printout
+	for _, warning := range list.Warnings {
+		w.Header().Add("Todo-Query-Warning", warning)
+	}
+
+	h.writePrint(w, r, format, "todos", printDocument{
+		Title:     "Todos",
+		Filters:   strings.Join(described, ", "),
+		Generated: time.Now().UTC(),
+		Todos:     list.Todos,
+	})
+}
+
+// printFormat parses the "format" query parameter, html when absent, and
+// writes a 400 response when it is unknown
+func printFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		return "html", true
+	case "html", "pdf":
+		return format, true
+	default:
+		writeError(w, http.StatusBadRequest, "format must be html or pdf")
+		return "", false
+	}
+}
+
+// writePrint renders doc in format, buffered so that a rendering failure
+// still yields a clean error response
+func (h *Handler) writePrint(w http.ResponseWriter, r *http.Request, format, filename string, doc printDocument) {
+	var buf bytes.Buffer
+	var contentType string
+
+	switch format {
+	case "pdf":
+		contentType = "application/pdf"
+		_, err := renderPDF(doc).WriteTo(&buf)
+		if err != nil {
+			h.writeServiceError(w, r, fmt.Errorf("rendering PDF: %w", err))
+			return
+		}
+	default:
+		contentType = "text/html; charset=utf-8"
+		if err := printTemplate.Execute(&buf, doc); err != nil {
+			h.writeServiceError(w, r, fmt.Errorf("rendering HTML: %w", err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename+"."+format))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// renderPDF lays doc out as a paper checklist
+func renderPDF(doc printDocument) *pdf.Document {
+	out := pdf.New(doc.Title)
+	out.Text(doc.Title, 16, 0, true)
+
+	meta := "Generated " + formatStamp(doc.Generated)
+	if doc.Filters != "" {
+		meta = doc.Filters + " - " + meta
+	}
+	out.Text(meta, 9, 0, false)
+	out.Space(12)
+
+	if len(doc.Todos) == 0 {
+		out.Text("No todos.", 11, 0, false)
+	}
+
+	for _, todo := range doc.Todos {
+		box := "[ ] "
+		if isDone(todo.Status) {
+			box = "[x] "
+		}
+		out.Text(box+todo.Title, 12, 0, true)
+
+		details := todo.Status + " - " + todo.Priority + " priority"
+		if todo.DueDate != nil {
+			details += " - due " + formatStamp(todo.DueDate)
+		}
+		if todo.ShortCode != "" {
+			details += " - " + todo.ShortCode
+		}
+		out.Text(details, 9, 29, false)
+
+		if todo.Description != "" {
+			out.Text(todo.Description, 10, 29, false)
+		}
+		out.Space(8)
+	}
+
+	return out
+}
+
+// isDone reports whether a todo status should be printed as checked
+func isDone(status string) bool {
+	return domain.TaskStatus(status).IsCompleted()
+}
+
+// formatStamp formats a time for print, accepting a *time.Time for due dates
+func formatStamp(v any) string {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	case *time.Time:
+		if t != nil {
+			return t.UTC().Format("2006-01-02 15:04 MST")
+		}
+	}
+	return ""
+}
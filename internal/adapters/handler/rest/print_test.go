@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_PrintTodo_HTML(t *testing.T) {
+	service := &fakeService{
+		getTodo: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return &application.TodoResponse{
+				ID:          id,
+				Title:       "Buy <milk>",
+				Description: "Semi-skimmed",
+				Status:      "completed",
+				Priority:    "high",
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/123/print")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want HTML", got)
+	}
+
+	if got := rec.Header().Get("Content-Disposition"); got != `inline; filename="todo-123.html"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "Buy &lt;milk&gt;") {
+		t.Error("title is not HTML-escaped")
+	}
+	if !strings.Contains(body, "&#9745;") {
+		t.Error("completed todo is not printed as checked")
+	}
+	if !strings.Contains(body, "Semi-skimmed") {
+		t.Error("description is missing")
+	}
+}
+
+func TestHandler_PrintTodo_PDF(t *testing.T) {
+	service := &fakeService{
+		getTodo: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return &application.TodoResponse{ID: id, Title: "Pay rent", Status: "pending", Priority: "medium"}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/123/print?format=pdf")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "%PDF-") {
+		t.Error("body is not a PDF document")
+	}
+	if !strings.Contains(body, "([ ] Pay rent)") {
+		t.Error("pending todo is not printed as an unchecked item")
+	}
+}
+
+func TestHandler_PrintTodo_InvalidFormat(t *testing.T) {
+	rec := serve(t, &fakeService{}, "/api/todos/123/print?format=docx")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_PrintTodo_NotFound(t *testing.T) {
+	service := &fakeService{
+		getTodo: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/123/print")
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandler_PrintTodos_PassesFilters(t *testing.T) {
+	var got application.ListFilters
+	service := &fakeService{
+		listTodos: func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+			got = filters
+			return &application.ListTodosResponse{
+				Todos: []*application.TodoResponse{
+					{ID: "1", Title: "First", Status: "pending", Priority: "high"},
+					{ID: "2", Title: "Second", Status: "pending", Priority: "high"},
+				},
+				TotalCount: 2,
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/print?status=pending&priority=high&sort_by=due_date&sort_order=desc&limit=20")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got.Status == nil || *got.Status != "pending" {
+		t.Errorf("ListTodos() status = %v, want pending", got.Status)
+	}
+	if got.Priority == nil || *got.Priority != "high" {
+		t.Errorf("ListTodos() priority = %v, want high", got.Priority)
+	}
+	if got.Limit == nil || *got.Limit != 20 {
+		t.Errorf("ListTodos() limit = %v, want 20", got.Limit)
+	}
+	if got.SortBy == nil || *got.SortBy != "due_date" || got.SortOrder == nil || *got.SortOrder != "desc" {
+		t.Errorf("ListTodos() sort = %v %v, want due_date desc", got.SortBy, got.SortOrder)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "First") || !strings.Contains(body, "Second") {
+		t.Error("listed todos are missing from the document")
+	}
+	if !strings.Contains(body, "status pending, priority high, sorted by due_date") {
+		t.Error("filters are not described in the document")
+	}
+}
+
+func TestHandler_PrintTodos_InvalidLimit(t *testing.T) {
+	rec := serve(t, &fakeService{}, "/api/todos/print?limit=ten")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,241 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/auth"
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+)
+
+// TodoService is the part of the application service served over REST
+type TodoService interface {
+	GetTodo(ctx context.Context, id string) (*application.TodoResponse, error)
+	ListTodos(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error)
+	SuggestTodos(ctx context.Context, query string, limit int) ([]*application.TodoSuggestion, error)
+	SearchTodos(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error)
+	ListRecentTodos(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error)
+	GetPreferences(ctx context.Context) (*application.Preferences, error)
+	UpdatePreferences(ctx context.Context, req application.Preferences) (*application.Preferences, error)
+	GetTodoAsOf(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error)
+	MergeTodos(ctx context.Context, canonicalID, duplicateID string) (*application.MergeTodosResponse, error)
+	ArchiveTodo(ctx context.Context, id string) (*application.TodoResponse, error)
+	UnarchiveTodo(ctx context.Context, id string) (*application.TodoResponse, error)
+	MoveTodo(ctx context.Context, id, ownerID string) (*application.TodoResponse, error)
+	GetTodoAuditLog(ctx context.Context, id string) ([]application.TodoAuditLogEntry, error)
+	TriageTodos(ctx context.Context, req application.TriageRequest) (*application.TriageReport, error)
+	PlanWeek(ctx context.Context, req application.PlanWeekRequest) (*application.PlanWeekResponse, error)
+	ExportTodos(ctx context.Context, filters application.ExportFilters, emit func(*application.TodoResponse) error) error
+	ImportTodos(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error)
+	CalendarFeed(ctx context.Context) ([]*application.TodoResponse, error)
+	SuggestSchedule(ctx context.Context, req application.SuggestScheduleRequest) (*application.SuggestScheduleResponse, error)
+	BatchUpdateTodos(ctx context.Context, req application.BatchUpdateRequest) (*application.BatchUpdateResponse, error)
+	BatchDeleteTodos(ctx context.Context, req application.BatchDeleteRequest) (*application.BatchDeleteResponse, error)
+	BulkCompleteTodos(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error)
+	GetDependencyGraph(ctx context.Context, id string) (*application.DependencyGraph, error)
+	AddDependency(ctx context.Context, id, blockerID string) error
+	RemoveDependency(ctx context.Context, id, blockerID string) error
+	CreateMilestone(ctx context.Context, req application.CreateMilestoneRequest) (*application.MilestoneResponse, error)
+	ListMilestones(ctx context.Context, archived *string) ([]*application.MilestoneResponse, error)
+	ArchiveMilestone(ctx context.Context, id string) (*application.MilestoneResponse, error)
+	UnarchiveMilestone(ctx context.Context, id string) (*application.MilestoneResponse, error)
+	DeleteMilestone(ctx context.Context, id string) error
+	GetMilestoneProgress(ctx context.Context, id string) (*application.MilestoneProgress, error)
+	AttachTodo(ctx context.Context, milestoneID, todoID string, estimateMinutes int) error
+	DetachTodo(ctx context.Context, milestoneID, todoID string) error
+	ListActivity(ctx context.Context, cursor string, limit int) (*application.ActivityFeedPage, error)
+	GetAnalytics(ctx context.Context, req application.AnalyticsRequest) (*application.AnalyticsReport, error)
+	GetCompletionHeatmap(ctx context.Context) (*application.CompletionHeatmap, error)
+	ReceiveHook(ctx context.Context, token string, payload any) (*application.TodoResponse, error)
+	WatchTodos(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error)
+	Sync(ctx context.Context, req application.SyncRequest) (*application.SyncResponse, error)
+	RegisterDevice(ctx context.Context, req application.RegisterDeviceRequest) (*application.DeviceResponse, error)
+	ListDevices(ctx context.Context) ([]*application.DeviceResponse, error)
+	UnregisterDevice(ctx context.Context, token string) error
+	AcquireEditLock(ctx context.Context, id string, ttl time.Duration) (*application.EditLockResponse, error)
+	ReleaseEditLock(ctx context.Context, id string) error
+}
+
+// Handler serves plain HTTP/JSON endpoints for operations that are not part
+// of the v1 Connect API, mounted under /api, the inbound webhooks under
+// /hooks and the calendar feed at /calendar.ics
+type Handler struct {
+	service       TodoService
+	logger        *slog.Logger
+	watch         WatchOptions
+	calendarKeys  auth.APIKeyAuthenticator
+	operations    Operations
+	authenticator auth.Authenticator
+}
+
+// Option configures optional Handler behavior
+type Option func(*Handler)
+
+// NewHandler creates a new REST Handler
+func NewHandler(service TodoService, logger *slog.Logger, opts ...Option) *Handler {
+	h := &Handler{
+		service: service,
+		logger:  logger,
+		watch:   DefaultWatchOptions(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RegisterRoutes registers the REST routes on mux
+// The /api routes go through authenticate; the inbound webhooks and the
+// calendar feed authenticate with the token in their URL
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /api/todos/suggestions", h.suggestTodos)
+	api.HandleFunc("GET /api/todos/search", h.searchTodos)
+	api.HandleFunc("GET /api/todos/recent", h.listRecentTodos)
+	api.HandleFunc("GET /api/todos/print", h.printTodos)
+	api.HandleFunc("GET /api/todos/export", h.exportTodos)
+	api.HandleFunc("POST /api/todos/import", h.importTodos)
+	api.HandleFunc("GET /api/todos/watch", h.watchTodos)
+	api.HandleFunc("POST /api/todos/triage", h.triageTodos)
+	api.HandleFunc("POST /api/todos/plan", h.planWeek)
+	api.HandleFunc("POST /api/todos/schedule-suggestions", h.suggestSchedule)
+	api.HandleFunc("POST /api/todos/batch-update", h.batchUpdateTodos)
+	api.HandleFunc("POST /api/todos/batch-delete", h.batchDeleteTodos)
+	api.HandleFunc("POST /api/todos/bulk-complete", h.bulkCompleteTodos)
+	api.HandleFunc("GET /api/todos/{id}/print", h.printTodo)
+	api.HandleFunc("GET /api/todos/{id}/as-of", h.getTodoAsOf)
+	api.HandleFunc("POST /api/todos/{id}/merge", h.mergeTodos)
+	api.HandleFunc("POST /api/todos/{id}/archive", h.archiveTodo)
+	api.HandleFunc("POST /api/todos/{id}/unarchive", h.unarchiveTodo)
+	api.HandleFunc("POST /api/todos/{id}/move", h.moveTodo)
+	api.HandleFunc("GET /api/todos/{id}/audit", h.getTodoAuditLog)
+	api.HandleFunc("GET /api/todos/{id}/dependencies", h.getDependencyGraph)
+	api.HandleFunc("PUT /api/todos/{id}/blocked-by/{blocker}", h.addDependency)
+	api.HandleFunc("DELETE /api/todos/{id}/blocked-by/{blocker}", h.removeDependency)
+	api.HandleFunc("PUT /api/todos/{id}/edit-lock", h.acquireEditLock)
+	api.HandleFunc("DELETE /api/todos/{id}/edit-lock", h.releaseEditLock)
+	api.HandleFunc("POST /api/milestones", h.createMilestone)
+	api.HandleFunc("GET /api/milestones", h.listMilestones)
+	api.HandleFunc("DELETE /api/milestones/{id}", h.deleteMilestone)
+	api.HandleFunc("POST /api/milestones/{id}/archive", h.archiveMilestone)
+	api.HandleFunc("POST /api/milestones/{id}/unarchive", h.unarchiveMilestone)
+	api.HandleFunc("GET /api/milestones/{id}/progress", h.getMilestoneProgress)
+	api.HandleFunc("PUT /api/milestones/{id}/todos/{todoID}", h.attachTodo)
+	api.HandleFunc("DELETE /api/milestones/{id}/todos/{todoID}", h.detachTodo)
+	api.HandleFunc("GET /api/activity", h.listActivity)
+	api.HandleFunc("GET /api/analytics", h.getAnalytics)
+	api.HandleFunc("GET /api/heatmap", h.getCompletionHeatmap)
+	api.HandleFunc("POST /api/sync", h.syncTodos)
+	api.HandleFunc("GET /api/preferences", h.getPreferences)
+	api.HandleFunc("PUT /api/preferences", h.putPreferences)
+	api.HandleFunc("POST /api/devices", h.registerDevice)
+	api.HandleFunc("GET /api/devices", h.listDevices)
+	api.HandleFunc("DELETE /api/devices/{token}", h.unregisterDevice)
+	api.HandleFunc("GET /api/errors", h.listErrorRules)
+	api.HandleFunc("GET /api/operations", h.listOperations)
+	api.HandleFunc("GET /api/operations/{id}", h.getOperation)
+	api.HandleFunc("POST /api/operations/{id}/cancel", h.cancelOperation)
+	mux.Handle("/api/", h.authenticate(api))
+	mux.HandleFunc("POST /hooks/{token}", h.receiveHook)
+	mux.HandleFunc("GET /calendar.ics", h.calendarFeed)
+}
+
+// queryLimit parses the optional "limit" query parameter, zero when absent
+func queryLimit(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid limit: %s", raw)
+	}
+
+	return limit, nil
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeServiceError maps an application error to an HTTP error response
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusForError(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error("request failed", "path", reqtrace.RedactPath(r.URL.Path), "error", err)
+	}
+	writeError(w, status, err.Error())
+}
+
+// statusForError maps application and domain errors to HTTP status codes
+func statusForError(err error) int {
+	var validationErr domain.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, domain.ErrInvalidID),
+		errors.Is(err, domain.ErrInvalidShortCode),
+		errors.Is(err, domain.ErrInvalidTitle),
+		errors.Is(err, domain.ErrInvalidDueDate),
+		errors.Is(err, domain.ErrCannotMergeIntoSelf),
+		errors.Is(err, domain.ErrAlreadyOwned),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidStatus):
+		return http.StatusBadRequest
+	case errors.Is(err, application.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, application.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTodoNotFound),
+		errors.Is(err, application.ErrInboundHookNotFound),
+		errors.Is(err, application.ErrDependencyNotFound),
+		errors.Is(err, application.ErrMilestoneNotFound),
+		errors.Is(err, application.ErrMilestoneTodoNotFound),
+		errors.Is(err, application.ErrDeviceNotFound),
+		errors.Is(err, application.ErrOperationNotFound),
+		errors.Is(err, application.ErrEditLockNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAlreadyMerged),
+		errors.Is(err, domain.ErrCannotModifyCompleted),
+		errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrCannotCompleteCancelled),
+		errors.Is(err, application.ErrLegalHold),
+		errors.Is(err, application.ErrTodoFrozen),
+		errors.Is(err, application.ErrMilestoneArchived),
+		errors.Is(err, application.ErrEditConflict),
+		errors.Is(err, application.ErrDependencyCycle),
+		errors.Is(err, application.ErrOperationFinished),
+		errors.Is(err, application.ErrEditLocked),
+		errors.Is(err, domain.ErrConcurrentModification):
+		return http.StatusConflict
+	case errors.Is(err, application.ErrNotSupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, application.ErrQueryThrottled), errors.Is(err, bulkhead.ErrFull):
+		return http.StatusTooManyRequests
+	case errors.Is(err, application.ErrMaintenanceMode),
+		errors.Is(err, application.ErrOperationsBusy),
+		errors.Is(err, circuitbreaker.ErrOpen):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// fakeService is a configurable TodoService
+type fakeService struct {
+	getTodo           func(ctx context.Context, id string) (*application.TodoResponse, error)
+	listTodos         func(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error)
+	suggestTodos      func(ctx context.Context, query string, limit int) ([]*application.TodoSuggestion, error)
+	searchTodos       func(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error)
+	listRecentTodos   func(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error)
+	getPreferences    func(ctx context.Context) (*application.Preferences, error)
+	updatePreferences func(ctx context.Context, req application.Preferences) (*application.Preferences, error)
+	getTodoAsOf       func(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error)
+	mergeTodos        func(ctx context.Context, canonicalID, duplicateID string) (*application.MergeTodosResponse, error)
+	archiveTodo       func(ctx context.Context, id string) (*application.TodoResponse, error)
+	unarchiveTodo     func(ctx context.Context, id string) (*application.TodoResponse, error)
+	moveTodo          func(ctx context.Context, id, ownerID string) (*application.TodoResponse, error)
+	getTodoAuditLog   func(ctx context.Context, id string) ([]application.TodoAuditLogEntry, error)
+	triageTodos       func(ctx context.Context, req application.TriageRequest) (*application.TriageReport, error)
+	planWeek          func(ctx context.Context, req application.PlanWeekRequest) (*application.PlanWeekResponse, error)
+	exportTodos       func(ctx context.Context, filters application.ExportFilters, emit func(*application.TodoResponse) error) error
+	importTodos       func(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error)
+	calendarFeed      func(ctx context.Context) ([]*application.TodoResponse, error)
+	suggestSchedule   func(ctx context.Context, req application.SuggestScheduleRequest) (*application.SuggestScheduleResponse, error)
+	batchUpdate       func(ctx context.Context, req application.BatchUpdateRequest) (*application.BatchUpdateResponse, error)
+	batchDelete       func(ctx context.Context, req application.BatchDeleteRequest) (*application.BatchDeleteResponse, error)
+	bulkComplete      func(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error)
+	dependencyGraph   func(ctx context.Context, id string) (*application.DependencyGraph, error)
+	addDependency     func(ctx context.Context, id, blockerID string) error
+	removeDependency  func(ctx context.Context, id, blockerID string) error
+	createMilestone   func(ctx context.Context, req application.CreateMilestoneRequest) (*application.MilestoneResponse, error)
+	listMilestones    func(ctx context.Context, archived *string) ([]*application.MilestoneResponse, error)
+	archiveMilestone  func(ctx context.Context, id string, archive bool) (*application.MilestoneResponse, error)
+	deleteMilestone   func(ctx context.Context, id string) error
+	milestoneProgress func(ctx context.Context, id string) (*application.MilestoneProgress, error)
+	attachTodo        func(ctx context.Context, milestoneID, todoID string, estimateMinutes int) error
+	detachTodo        func(ctx context.Context, milestoneID, todoID string) error
+	listActivity      func(ctx context.Context, cursor string, limit int) (*application.ActivityFeedPage, error)
+	getAnalytics      func(ctx context.Context, req application.AnalyticsRequest) (*application.AnalyticsReport, error)
+	getHeatmap        func(ctx context.Context) (*application.CompletionHeatmap, error)
+	receiveHook       func(ctx context.Context, token string, payload any) (*application.TodoResponse, error)
+	watchTodos        func(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error)
+	sync              func(ctx context.Context, req application.SyncRequest) (*application.SyncResponse, error)
+	registerDevice    func(ctx context.Context, req application.RegisterDeviceRequest) (*application.DeviceResponse, error)
+	listDevices       func(ctx context.Context) ([]*application.DeviceResponse, error)
+	unregisterDevice  func(ctx context.Context, token string) error
+	acquireEditLock   func(ctx context.Context, id string, ttl time.Duration) (*application.EditLockResponse, error)
+	releaseEditLock   func(ctx context.Context, id string) error
+}
+
+func (f *fakeService) GetTodo(ctx context.Context, id string) (*application.TodoResponse, error) {
+	return f.getTodo(ctx, id)
+}
+
+func (f *fakeService) ListTodos(ctx context.Context, filters application.ListFilters) (*application.ListTodosResponse, error) {
+	return f.listTodos(ctx, filters)
+}
+
+func (f *fakeService) SuggestTodos(ctx context.Context, query string, limit int) ([]*application.TodoSuggestion, error) {
+	return f.suggestTodos(ctx, query, limit)
+}
+
+func (f *fakeService) SearchTodos(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error) {
+	return f.searchTodos(ctx, req)
+}
+
+func (f *fakeService) ListRecentTodos(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error) {
+	return f.listRecentTodos(ctx, kind, limit)
+}
+
+func (f *fakeService) GetPreferences(ctx context.Context) (*application.Preferences, error) {
+	return f.getPreferences(ctx)
+}
+
+func (f *fakeService) UpdatePreferences(ctx context.Context, req application.Preferences) (*application.Preferences, error) {
+	return f.updatePreferences(ctx, req)
+}
+
+func (f *fakeService) GetTodoAsOf(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error) {
+	return f.getTodoAsOf(ctx, id, at)
+}
+
+func (f *fakeService) MergeTodos(ctx context.Context, canonicalID, duplicateID string) (*application.MergeTodosResponse, error) {
+	return f.mergeTodos(ctx, canonicalID, duplicateID)
+}
+
+func (f *fakeService) ArchiveTodo(ctx context.Context, id string) (*application.TodoResponse, error) {
+	return f.archiveTodo(ctx, id)
+}
+
+func (f *fakeService) UnarchiveTodo(ctx context.Context, id string) (*application.TodoResponse, error) {
+	return f.unarchiveTodo(ctx, id)
+}
+
+func (f *fakeService) MoveTodo(ctx context.Context, id, ownerID string) (*application.TodoResponse, error) {
+	return f.moveTodo(ctx, id, ownerID)
+}
+
+func (f *fakeService) GetTodoAuditLog(ctx context.Context, id string) ([]application.TodoAuditLogEntry, error) {
+	return f.getTodoAuditLog(ctx, id)
+}
+
+func (f *fakeService) TriageTodos(ctx context.Context, req application.TriageRequest) (*application.TriageReport, error) {
+	return f.triageTodos(ctx, req)
+}
+
+func (f *fakeService) PlanWeek(ctx context.Context, req application.PlanWeekRequest) (*application.PlanWeekResponse, error) {
+	return f.planWeek(ctx, req)
+}
+
+func (f *fakeService) ExportTodos(ctx context.Context, filters application.ExportFilters, emit func(*application.TodoResponse) error) error {
+	return f.exportTodos(ctx, filters, emit)
+}
+
+func (f *fakeService) ImportTodos(ctx context.Context, req application.ImportTodosRequest) (*application.ImportTodosResponse, error) {
+	return f.importTodos(ctx, req)
+}
+
+func (f *fakeService) CalendarFeed(ctx context.Context) ([]*application.TodoResponse, error) {
+	return f.calendarFeed(ctx)
+}
+
+func (f *fakeService) SuggestSchedule(ctx context.Context, req application.SuggestScheduleRequest) (*application.SuggestScheduleResponse, error) {
+	return f.suggestSchedule(ctx, req)
+}
+
+func (f *fakeService) BatchUpdateTodos(ctx context.Context, req application.BatchUpdateRequest) (*application.BatchUpdateResponse, error) {
+	return f.batchUpdate(ctx, req)
+}
+
+func (f *fakeService) BatchDeleteTodos(ctx context.Context, req application.BatchDeleteRequest) (*application.BatchDeleteResponse, error) {
+	return f.batchDelete(ctx, req)
+}
+
+func (f *fakeService) BulkCompleteTodos(ctx context.Context, req application.BulkCompleteRequest) (*application.BulkCompleteResponse, error) {
+	return f.bulkComplete(ctx, req)
+}
+
+func (f *fakeService) GetDependencyGraph(ctx context.Context, id string) (*application.DependencyGraph, error) {
+	return f.dependencyGraph(ctx, id)
+}
+
+func (f *fakeService) AddDependency(ctx context.Context, id, blockerID string) error {
+	return f.addDependency(ctx, id, blockerID)
+}
+
+func (f *fakeService) RemoveDependency(ctx context.Context, id, blockerID string) error {
+	return f.removeDependency(ctx, id, blockerID)
+}
+
+func (f *fakeService) CreateMilestone(ctx context.Context, req application.CreateMilestoneRequest) (*application.MilestoneResponse, error) {
+	return f.createMilestone(ctx, req)
+}
+
+func (f *fakeService) ListMilestones(ctx context.Context, archived *string) ([]*application.MilestoneResponse, error) {
+	return f.listMilestones(ctx, archived)
+}
+
+func (f *fakeService) ArchiveMilestone(ctx context.Context, id string) (*application.MilestoneResponse, error) {
+	return f.archiveMilestone(ctx, id, true)
+}
+
+func (f *fakeService) UnarchiveMilestone(ctx context.Context, id string) (*application.MilestoneResponse, error) {
+	return f.archiveMilestone(ctx, id, false)
+}
+
+func (f *fakeService) DeleteMilestone(ctx context.Context, id string) error {
+	return f.deleteMilestone(ctx, id)
+}
+
+func (f *fakeService) GetMilestoneProgress(ctx context.Context, id string) (*application.MilestoneProgress, error) {
+	return f.milestoneProgress(ctx, id)
+}
+
+func (f *fakeService) AttachTodo(ctx context.Context, milestoneID, todoID string, estimateMinutes int) error {
+	return f.attachTodo(ctx, milestoneID, todoID, estimateMinutes)
+}
+
+func (f *fakeService) DetachTodo(ctx context.Context, milestoneID, todoID string) error {
+	return f.detachTodo(ctx, milestoneID, todoID)
+}
+
+func (f *fakeService) ListActivity(ctx context.Context, cursor string, limit int) (*application.ActivityFeedPage, error) {
+	return f.listActivity(ctx, cursor, limit)
+}
+
+func (f *fakeService) GetAnalytics(ctx context.Context, req application.AnalyticsRequest) (*application.AnalyticsReport, error) {
+	return f.getAnalytics(ctx, req)
+}
+
+func (f *fakeService) GetCompletionHeatmap(ctx context.Context) (*application.CompletionHeatmap, error) {
+	return f.getHeatmap(ctx)
+}
+
+func (f *fakeService) ReceiveHook(ctx context.Context, token string, payload any) (*application.TodoResponse, error) {
+	return f.receiveHook(ctx, token, payload)
+}
+
+func (f *fakeService) WatchTodos(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error) {
+	return f.watchTodos(ctx, filters)
+}
+
+func (f *fakeService) Sync(ctx context.Context, req application.SyncRequest) (*application.SyncResponse, error) {
+	return f.sync(ctx, req)
+}
+
+func (f *fakeService) RegisterDevice(ctx context.Context, req application.RegisterDeviceRequest) (*application.DeviceResponse, error) {
+	return f.registerDevice(ctx, req)
+}
+
+func (f *fakeService) ListDevices(ctx context.Context) ([]*application.DeviceResponse, error) {
+	return f.listDevices(ctx)
+}
+
+func (f *fakeService) UnregisterDevice(ctx context.Context, token string) error {
+	return f.unregisterDevice(ctx, token)
+}
+
+func (f *fakeService) AcquireEditLock(ctx context.Context, id string, ttl time.Duration) (*application.EditLockResponse, error) {
+	return f.acquireEditLock(ctx, id, ttl)
+}
+
+func (f *fakeService) ReleaseEditLock(ctx context.Context, id string) error {
+	return f.releaseEditLock(ctx, id)
+}
+
+func serve(t *testing.T, service TodoService, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveRequest(t, service, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func serveRequest(t *testing.T, service TodoService, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	NewHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil))).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
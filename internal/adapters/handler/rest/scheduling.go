@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// suggestScheduleRequest is the JSON body of a schedule suggestion; every
+// field is optional
+type suggestScheduleRequest struct {
+	Start                  string         `json:"start,omitempty"`
+	HorizonDays            int            `json:"horizon_days,omitempty"`
+	DailyCapacityMinutes   int            `json:"daily_capacity_minutes,omitempty"`
+	DefaultEstimateMinutes int            `json:"default_estimate_minutes,omitempty"`
+	Estimates              map[string]int `json:"estimates,omitempty"`
+}
+
+// scheduleSuggestionResponse is the JSON representation of the due date
+// suggested for a todo
+type scheduleSuggestionResponse struct {
+	TodoID          string    `json:"todo_id"`
+	Title           string    `json:"title"`
+	Priority        string    `json:"priority"`
+	EstimateMinutes int       `json:"estimate_minutes"`
+	DueDate         time.Time `json:"due_date"`
+}
+
+// scheduleDayResponse is the JSON representation of the load of a working
+// day, in minutes
+type scheduleDayResponse struct {
+	Day       string `json:"day"`
+	Committed int    `json:"committed_minutes"`
+	Suggested int    `json:"suggested_minutes"`
+}
+
+// suggestScheduleResponse is the JSON response of a schedule suggestion
+type suggestScheduleResponse struct {
+	Suggestions []scheduleSuggestionResponse `json:"suggestions"`
+	Unscheduled []string                     `json:"unscheduled"`
+	Days        []scheduleDayResponse        `json:"days"`
+}
+
+// suggestSchedule answers POST /api/todos/schedule-suggestions with due
+// dates for the undated open todos, changing none
+func (h *Handler) suggestSchedule(w http.ResponseWriter, r *http.Request) {
+	var body suggestScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	schedule, err := h.service.SuggestSchedule(r.Context(), application.SuggestScheduleRequest(body))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response := suggestScheduleResponse{
+		Suggestions: make([]scheduleSuggestionResponse, len(schedule.Suggestions)),
+		Unscheduled: schedule.Unscheduled,
+		Days:        make([]scheduleDayResponse, len(schedule.Days)),
+	}
+	for i, suggestion := range schedule.Suggestions {
+		response.Suggestions[i] = scheduleSuggestionResponse(suggestion)
+	}
+	for i, day := range schedule.Days {
+		response.Days[i] = scheduleDayResponse(day)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_SuggestSchedule(t *testing.T) {
+	dueDate := time.Date(2026, time.October, 20, 23, 59, 59, 0, time.UTC)
+	var got application.SuggestScheduleRequest
+	service := &fakeService{
+		suggestSchedule: func(ctx context.Context, req application.SuggestScheduleRequest) (*application.SuggestScheduleResponse, error) {
+			got = req
+			return &application.SuggestScheduleResponse{
+				Suggestions: []application.ScheduleSuggestion{{TodoID: "aaa", Priority: "high", EstimateMinutes: 90, DueDate: dueDate}},
+				Unscheduled: []string{"bbb"},
+				Days:        []application.ScheduleDay{{Day: "2026-10-20", Committed: 30, Suggested: 90}},
+			}, nil
+		},
+	}
+
+	body := `{"start":"2026-10-20","daily_capacity_minutes":120,"estimates":{"TD-7":90}}`
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/schedule-suggestions", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got.Start != "2026-10-20" || got.DailyCapacityMinutes != 120 || got.Estimates["TD-7"] != 90 {
+		t.Errorf("SuggestSchedule() called with %+v, want the fields of the body", got)
+	}
+
+	var schedule suggestScheduleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&schedule); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(schedule.Suggestions) != 1 || !schedule.Suggestions[0].DueDate.Equal(dueDate) || len(schedule.Unscheduled) != 1 || schedule.Days[0].Suggested != 90 {
+		t.Errorf("response = %+v, want aaa due on the 20th and bbb unscheduled", schedule)
+	}
+}
+
+func TestHandler_SuggestSchedule_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid body", `{"start":`, nil, http.StatusBadRequest},
+		{"past start", `{"start":"2020-01-06"}`, domain.NewValidationError("start", "must not be in the past"), http.StatusBadRequest},
+		{"unknown todo", `{"estimates":{"TD-404":30}}`, domain.ErrTodoNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				suggestSchedule: func(ctx context.Context, req application.SuggestScheduleRequest) (*application.SuggestScheduleResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/schedule-suggestions", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
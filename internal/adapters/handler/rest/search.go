@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// searchResponse is the JSON representation of a page of search results
+type searchResponse struct {
+	Todos      []todoResponse `json:"todos"`
+	NextOffset int            `json:"next_offset,omitempty"`
+}
+
+// searchTodos answers GET /api/todos/search?q=<keywords>&limit=<n>&offset=<next_offset>&fields=
+func (h *Handler) searchTodos(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req := application.SearchTodosRequest{Query: r.URL.Query().Get("q"), Limit: limit}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		req.Offset, err = strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid offset: "+raw)
+			return
+		}
+	}
+
+	page, err := h.service.SearchTodos(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, searchResponse{Todos: fields.todos(mapTodos(page.Todos)), NextOffset: page.NextOffset})
+}
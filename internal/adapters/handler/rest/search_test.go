@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_SearchTodos_Success(t *testing.T) {
+	var gotReq application.SearchTodosRequest
+	service := &fakeService{
+		searchTodos: func(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error) {
+			gotReq = req
+			return &application.SearchTodosResponse{
+				Todos:      []*application.TodoResponse{{ID: "123", Title: "Pay invoices"}},
+				NextOffset: 20,
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/search?q=invoice+-draft&limit=10&offset=10")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if gotReq.Query != "invoice -draft" || gotReq.Limit != 10 || gotReq.Offset != 10 {
+		t.Errorf("SearchTodos() called with %+v", gotReq)
+	}
+
+	var body searchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Todos) != 1 || body.Todos[0].Title != "Pay invoices" || body.NextOffset != 20 {
+		t.Errorf("body = %+v, want one todo and next offset 20", body)
+	}
+}
+
+func TestHandler_SearchTodos_Errors(t *testing.T) {
+	service := &fakeService{
+		searchTodos: func(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error) {
+			return nil, domain.NewValidationError("query", "cannot be empty")
+		},
+	}
+
+	for _, target := range []string{
+		"/api/todos/search?q=invoice&offset=next",
+		"/api/todos/search?q=invoice&limit=many",
+		"/api/todos/search",
+	} {
+		if rec := serve(t, service, target); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandler_SearchTodos_Throttled(t *testing.T) {
+	service := &fakeService{
+		searchTodos: func(ctx context.Context, req application.SearchTodosRequest) (*application.SearchTodosResponse, error) {
+			return nil, fmt.Errorf("%w: retry in 30s", application.ErrQueryThrottled)
+		},
+	}
+
+	if rec := serve(t, service, "/api/todos/search?q=invoice&offset=5000"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// suggestion is the JSON representation of an autocomplete match
+type suggestion struct {
+	ID        string `json:"id"`
+	ShortCode string `json:"short_code,omitempty"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+}
+
+// suggestTodos answers GET /api/todos/suggestions?q=<text>&limit=<n>
+func (h *Handler) suggestTodos(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.SuggestTodos(r.Context(), r.URL.Query().Get("q"), limit)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	suggestions := make([]suggestion, len(results))
+	for i, s := range results {
+		suggestions[i] = suggestion{
+			ID:        s.ID,
+			ShortCode: s.ShortCode,
+			Title:     s.Title,
+			Status:    s.Status,
+		}
+	}
+
+	// Suggestions go stale quickly but a short cache smooths bursts of keystrokes
+	w.Header().Set("Cache-Control", "private, max-age=5")
+	writeJSON(w, http.StatusOK, map[string][]suggestion{"suggestions": suggestions})
+}
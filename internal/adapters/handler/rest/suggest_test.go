@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+func TestHandler_SuggestTodos_Success(t *testing.T) {
+	var gotQuery string
+	var gotLimit int
+	service := &fakeService{
+		suggestTodos: func(ctx context.Context, query string, limit int) ([]*application.TodoSuggestion, error) {
+			gotQuery, gotLimit = query, limit
+			return []*application.TodoSuggestion{
+				{ID: "123", ShortCode: "TD-7", Title: "Buy milk", Status: "pending"},
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/suggestions?q=buy&limit=3")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if gotQuery != "buy" || gotLimit != 3 {
+		t.Errorf("SuggestTodos() called with %q, %d, want %q, 3", gotQuery, gotLimit, "buy")
+	}
+
+	var body struct {
+		Suggestions []suggestion `json:"suggestions"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Suggestions) != 1 || body.Suggestions[0].ShortCode != "TD-7" {
+		t.Errorf("Suggestions = %+v, want the TD-7 match", body.Suggestions)
+	}
+}
+
+func TestHandler_SuggestTodos_InvalidLimit(t *testing.T) {
+	rec := serve(t, &fakeService{}, "/api/todos/suggestions?q=buy&limit=many")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_SuggestTodos_NotSupported(t *testing.T) {
+	service := &fakeService{
+		suggestTodos: func(ctx context.Context, query string, limit int) ([]*application.TodoSuggestion, error) {
+			return nil, application.ErrNotSupported
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/suggestions?q=buy")
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
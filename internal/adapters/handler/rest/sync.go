@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// MaxSyncBodySize bounds the JSON body of a sync exchange
+const MaxSyncBodySize = 4 << 20
+
+// syncOperation is the JSON representation of a change made offline
+type syncOperation struct {
+	Ref           string     `json:"ref"`
+	Op            string     `json:"op"`
+	TodoID        string     `json:"todo_id,omitempty"`
+	BaseUpdatedAt time.Time  `json:"base_updated_at"`
+	Title         *string    `json:"title,omitempty"`
+	Description   *string    `json:"description,omitempty"`
+	Priority      *string    `json:"priority,omitempty"`
+	Status        *string    `json:"status,omitempty"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+}
+
+// syncRequest is the JSON body of a sync exchange
+type syncRequest struct {
+	Cursor     string          `json:"cursor"`
+	Limit      int             `json:"limit"`
+	Operations []syncOperation `json:"operations"`
+}
+
+// syncOperationResult is the JSON representation of the outcome of an
+// operation
+type syncOperationResult struct {
+	Ref        string        `json:"ref"`
+	Status     string        `json:"status"`
+	TodoID     string        `json:"todo_id,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	Resolution string        `json:"resolution,omitempty"`
+	Todo       *todoResponse `json:"todo,omitempty"`
+}
+
+// syncedTodo is the JSON representation of a todo changed on the server
+type syncedTodo struct {
+	TodoID  string        `json:"todo_id"`
+	Deleted bool          `json:"deleted,omitempty"`
+	Todo    *todoResponse `json:"todo,omitempty"`
+}
+
+// syncResponse is the JSON response of a sync exchange
+type syncResponse struct {
+	Results []syncOperationResult `json:"results"`
+	Changes []syncedTodo          `json:"changes"`
+	Cursor  string                `json:"cursor"`
+	HasMore bool                  `json:"has_more"`
+}
+
+// syncTodos answers POST /api/sync, applying the operations of the body then
+// returning the todos changed since its cursor
+// Clients keep the returned cursor for their next sync, and sync again right
+// away while has_more is set
+func (h *Handler) syncTodos(w http.ResponseWriter, r *http.Request) {
+	var body syncRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxSyncBodySize)).Decode(&body); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "payload too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	req := application.SyncRequest{Cursor: body.Cursor, Limit: body.Limit}
+	req.Operations = make([]application.SyncOperation, len(body.Operations))
+	for i, op := range body.Operations {
+		req.Operations[i] = application.SyncOperation{
+			Ref:           op.Ref,
+			Op:            op.Op,
+			TodoID:        op.TodoID,
+			BaseUpdatedAt: op.BaseUpdatedAt,
+			Fields: application.UpdateTodoRequest{
+				Title:       op.Title,
+				Description: op.Description,
+				Priority:    op.Priority,
+				Status:      op.Status,
+				DueDate:     op.DueDate,
+			},
+		}
+	}
+
+	synced, err := h.service.Sync(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, mapSync(synced))
+}
+
+// mapSync converts an application SyncResponse to its JSON representation
+func mapSync(synced *application.SyncResponse) syncResponse {
+	response := syncResponse{
+		Results: make([]syncOperationResult, len(synced.Results)),
+		Changes: make([]syncedTodo, len(synced.Changes)),
+		Cursor:  synced.Cursor,
+		HasMore: synced.HasMore,
+	}
+
+	for i, result := range synced.Results {
+		response.Results[i] = syncOperationResult{
+			Ref:        result.Ref,
+			Status:     result.Status,
+			TodoID:     result.TodoID,
+			Reason:     result.Reason,
+			Resolution: result.Resolution,
+		}
+		if result.Todo != nil {
+			todo := mapTodo(result.Todo)
+			response.Results[i].Todo = &todo
+		}
+	}
+
+	for i, change := range synced.Changes {
+		response.Changes[i] = syncedTodo{TodoID: change.TodoID, Deleted: change.Deleted}
+		if change.Todo != nil {
+			todo := mapTodo(change.Todo)
+			response.Changes[i].Todo = &todo
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_Sync_Success(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	var got application.SyncRequest
+	service := &fakeService{
+		sync: func(ctx context.Context, req application.SyncRequest) (*application.SyncResponse, error) {
+			got = req
+			return &application.SyncResponse{
+				Results: []*application.SyncOperationResult{
+					{Ref: "local-1", Status: application.SyncApplied, TodoID: "123", Todo: &application.TodoResponse{ID: "123", Title: "Buy milk"}},
+					{Ref: "local-2", Status: application.SyncConflict, TodoID: "456", Reason: application.SyncReasonDeleted, Resolution: application.SyncServerWins},
+				},
+				Changes: []*application.SyncedTodo{
+					{TodoID: "123", Todo: &application.TodoResponse{ID: "123", Title: "Buy milk"}},
+					{TodoID: "456", Deleted: true},
+				},
+				Cursor:  "57",
+				HasMore: true,
+			}, nil
+		},
+	}
+
+	body := `{"cursor":"42","limit":10,"operations":[
+		{"ref":"local-1","op":"create","title":"Buy milk","priority":"low"},
+		{"ref":"local-2","op":"update","todo_id":"456","base_updated_at":"2026-03-01T09:30:00Z","title":"Buy bread"}
+	]}`
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	if got.Cursor != "42" || got.Limit != 10 || len(got.Operations) != 2 {
+		t.Fatalf("Sync() request = %+v, want cursor 42, limit 10 and two operations", got)
+	}
+	update := got.Operations[1]
+	if update.Op != "update" || update.TodoID != "456" || !update.BaseUpdatedAt.Equal(base) ||
+		update.Fields.Title == nil || *update.Fields.Title != "Buy bread" {
+		t.Errorf("second operation = %+v, want the update of 456", update)
+	}
+
+	var response syncResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if response.Cursor != "57" || !response.HasMore {
+		t.Errorf("cursor, has_more = %q, %v, want 57, true", response.Cursor, response.HasMore)
+	}
+	if len(response.Results) != 2 || response.Results[0].Todo == nil || response.Results[1].Resolution != "server_wins" {
+		t.Errorf("results = %+v, want the created todo and a server_wins conflict", response.Results)
+	}
+	if len(response.Changes) != 2 || !response.Changes[1].Deleted || response.Changes[1].Todo != nil {
+		t.Errorf("changes = %+v, want the todo then the deletion", response.Changes)
+	}
+}
+
+func TestHandler_Sync_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid JSON", `{"operations":`, nil, http.StatusBadRequest},
+		{"invalid cursor", `{"cursor":"x"}`, domain.NewValidationError("cursor", "is invalid"), http.StatusBadRequest},
+		{"maintenance", `{"operations":[{"op":"delete","todo_id":"1"}]}`, application.ErrMaintenanceMode, http.StatusServiceUnavailable},
+		{"not supported", `{}`, application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				sync: func(ctx context.Context, req application.SyncRequest) (*application.SyncResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// todoResponse is the JSON representation of a todo
+type todoResponse struct {
+	ID          string     `json:"id"`
+	ShortCode   string     `json:"short_code,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	MergedInto  string     `json:"merged_into,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Version     int64      `json:"version,omitempty"`
+
+	// fields is the sparse fieldset the todo is rendered with
+	fields fieldset
+}
+
+// mapTodo converts an application TodoResponse to its JSON representation
+func mapTodo(todo *application.TodoResponse) todoResponse {
+	return todoResponse{
+		ID:          todo.ID,
+		ShortCode:   todo.ShortCode,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      todo.Status,
+		Priority:    todo.Priority,
+		DueDate:     todo.DueDate,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		MergedInto:  todo.MergedInto,
+		ArchivedAt:  todo.ArchivedAt,
+		Version:     todo.Version,
+	}
+}
+
+// mapTodos converts application TodoResponses to their JSON representation
+func mapTodos(todos []*application.TodoResponse) []todoResponse {
+	mapped := make([]todoResponse, len(todos))
+	for i, todo := range todos {
+		mapped[i] = mapTodo(todo)
+	}
+	return mapped
+}
+
+// listRecentTodos answers GET /api/todos/recent?kind=viewed|modified&limit=<n>&fields=
+func (h *Handler) listRecentTodos(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = "viewed"
+	}
+
+	todos, err := h.service.ListRecentTodos(r.Context(), kind, limit)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	// Per-user content
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSON(w, http.StatusOK, map[string][]todoResponse{"todos": fields.todos(mapTodos(todos))})
+}
+
+// getTodoAsOf answers GET /api/todos/{id}/as-of?at=<RFC 3339 timestamp>&fields=
+func (h *Handler) getTodoAsOf(w http.ResponseWriter, r *http.Request) {
+	at, err := time.Parse(time.RFC3339, r.URL.Query().Get("at"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "at must be an RFC 3339 timestamp")
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	todo, err := h.service.GetTodoAsOf(r.Context(), r.PathValue("id"), at)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fields.todo(mapTodo(todo)))
+}
+
+// mergeTodosRequest is the JSON body of a merge
+type mergeTodosRequest struct {
+	DuplicateID string `json:"duplicate_id"`
+}
+
+// mergeTodosResponse is the JSON response of a merge
+type mergeTodosResponse struct {
+	Canonical todoResponse `json:"canonical"`
+	Duplicate todoResponse `json:"duplicate"`
+}
+
+// mergeTodos answers POST /api/todos/{id}/merge, merging the duplicate of
+// the body into the todo of the path
+func (h *Handler) mergeTodos(w http.ResponseWriter, r *http.Request) {
+	var body mergeTodosRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if body.DuplicateID == "" {
+		writeError(w, http.StatusBadRequest, "duplicate_id is required")
+		return
+	}
+
+	merged, err := h.service.MergeTodos(r.Context(), r.PathValue("id"), body.DuplicateID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mergeTodosResponse{
+		Canonical: mapTodo(merged.Canonical),
+		Duplicate: mapTodo(merged.Duplicate),
+	})
+}
+
+// moveTodoRequest is the JSON body of a move
+type moveTodoRequest struct {
+	OwnerID string `json:"owner_id"`
+}
+
+// moveTodo answers POST /api/todos/{id}/move with the todo moved to
+// another owner
+func (h *Handler) moveTodo(w http.ResponseWriter, r *http.Request) {
+	var body moveTodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if body.OwnerID == "" {
+		writeError(w, http.StatusBadRequest, "owner_id is required")
+		return
+	}
+
+	todo, err := h.service.MoveTodo(r.Context(), r.PathValue("id"), body.OwnerID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapTodo(todo))
+}
+
+// archiveTodo answers POST /api/todos/{id}/archive
+func (h *Handler) archiveTodo(w http.ResponseWriter, r *http.Request) {
+	todo, err := h.service.ArchiveTodo(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapTodo(todo))
+}
+
+// unarchiveTodo answers POST /api/todos/{id}/unarchive
+func (h *Handler) unarchiveTodo(w http.ResponseWriter, r *http.Request) {
+	todo, err := h.service.UnarchiveTodo(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapTodo(todo))
+}
+
+// todoAuditEntryResponse is the JSON representation of an entry of the
+// audit log of a todo
+type todoAuditEntryResponse struct {
+	OccurredAt time.Time         `json:"occurred_at"`
+	Actor      string            `json:"actor"`
+	EventType  string            `json:"event_type"`
+	Changes    map[string]string `json:"changes"`
+}
+
+// getTodoAuditLog answers GET /api/todos/{id}/audit with the history of the
+// todo, oldest first
+func (h *Handler) getTodoAuditLog(w http.ResponseWriter, r *http.Request) {
+	log, err := h.service.GetTodoAuditLog(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	entries := make([]todoAuditEntryResponse, len(log))
+	for i, entry := range log {
+		entries[i] = todoAuditEntryResponse{
+			OccurredAt: entry.OccurredAt,
+			Actor:      entry.Actor,
+			EventType:  entry.EventType,
+			Changes:    entry.Changes,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]todoAuditEntryResponse{"entries": entries})
+}
+
+// triageRuleRequest is the JSON representation of a triage rule
+type triageRuleRequest struct {
+	Overdue       bool    `json:"overdue"`
+	NoDueDate     bool    `json:"no_due_date"`
+	OlderThanDays int     `json:"older_than_days"`
+	Priority      *string `json:"priority"`
+	SetPriority   string  `json:"set_priority"`
+}
+
+// triageTodosRequest is the JSON body of a triage pass
+type triageTodosRequest struct {
+	Rules  []triageRuleRequest `json:"rules"`
+	DryRun bool                `json:"dry_run"`
+}
+
+// triageChangeResponse is the JSON representation of a reprioritization
+type triageChangeResponse struct {
+	TodoID string `json:"todo_id"`
+	Title  string `json:"title"`
+	Rule   int    `json:"rule"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// triageTodosResponse is the JSON response of a triage pass
+type triageTodosResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Changes []triageChangeResponse `json:"changes"`
+}
+
+// triageTodos answers POST /api/todos/triage, reprioritizing the open todos
+// by the rules of the body, or previewing it with dry_run
+func (h *Handler) triageTodos(w http.ResponseWriter, r *http.Request) {
+	var body triageTodosRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	req := application.TriageRequest{DryRun: body.DryRun, Rules: make([]application.TriageRule, len(body.Rules))}
+	for i, rule := range body.Rules {
+		req.Rules[i] = application.TriageRule(rule)
+	}
+
+	report, err := h.service.TriageTodos(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	changes := make([]triageChangeResponse, len(report.Changes))
+	for i, change := range report.Changes {
+		changes[i] = triageChangeResponse(change)
+	}
+
+	writeJSON(w, http.StatusOK, triageTodosResponse{DryRun: report.DryRun, Changes: changes})
+}
+
+// planWeekRequest is the JSON body of a weekly plan
+type planWeekRequest struct {
+	Days map[string]string `json:"days"`
+}
+
+// planSkipResponse is the JSON representation of a todo left out of a plan
+type planSkipResponse struct {
+	TodoID string `json:"todo_id"`
+	Reason string `json:"reason"`
+}
+
+// planWeekResponse is the JSON response of a weekly plan
+type planWeekResponse struct {
+	Planned []todoResponse     `json:"planned"`
+	Skipped []planSkipResponse `json:"skipped"`
+}
+
+// planWeek answers POST /api/todos/plan, setting the due dates of the todos
+// of the body to the end of their planned day
+func (h *Handler) planWeek(w http.ResponseWriter, r *http.Request) {
+	var body planWeekRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	plan, err := h.service.PlanWeek(r.Context(), application.PlanWeekRequest{Days: body.Days})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	skipped := make([]planSkipResponse, len(plan.Skipped))
+	for i, skip := range plan.Skipped {
+		skipped[i] = planSkipResponse(skip)
+	}
+
+	writeJSON(w, http.StatusOK, planWeekResponse{Planned: mapTodos(plan.Planned), Skipped: skipped})
+}
@@ -0,0 +1,423 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestHandler_ListRecentTodos_Success(t *testing.T) {
+	var gotKind string
+	service := &fakeService{
+		listRecentTodos: func(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error) {
+			gotKind = kind
+			return []*application.TodoResponse{{ID: "123", Title: "Buy milk", Status: "pending"}}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/recent?kind=modified")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if gotKind != "modified" {
+		t.Errorf("ListRecentTodos() kind = %q, want %q", gotKind, "modified")
+	}
+
+	var body struct {
+		Todos []todoResponse `json:"todos"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(body.Todos) != 1 || body.Todos[0].ID != "123" {
+		t.Errorf("Todos = %+v, want the todo 123", body.Todos)
+	}
+}
+
+func TestHandler_ListRecentTodos_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unauthenticated", application.ErrUnauthenticated, http.StatusUnauthorized},
+		{"forbidden", fmt.Errorf("list: %w", application.ErrForbidden), http.StatusForbidden},
+		{"invalid kind", domain.NewValidationError("kind", "must be viewed or modified"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				listRecentTodos: func(ctx context.Context, kind string, limit int) ([]*application.TodoResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serve(t, service, "/api/todos/recent")
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_GetTodoAsOf_Success(t *testing.T) {
+	var gotID string
+	var gotAt time.Time
+	service := &fakeService{
+		getTodoAsOf: func(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error) {
+			gotID, gotAt = id, at
+			return &application.TodoResponse{ID: id, Title: "Old title", Status: "pending"}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/TD-7/as-of?at=2026-01-02T15:04:05Z")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	want := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	if gotID != "TD-7" || !gotAt.Equal(want) {
+		t.Errorf("GetTodoAsOf() called with %q, %v, want %q, %v", gotID, gotAt, "TD-7", want)
+	}
+
+	var body todoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Title != "Old title" {
+		t.Errorf("Title = %q, want %q", body.Title, "Old title")
+	}
+}
+
+func TestHandler_GetTodoAsOf_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		err    error
+		want   int
+	}{
+		{"missing timestamp", "/api/todos/abc/as-of", nil, http.StatusBadRequest},
+		{"invalid timestamp", "/api/todos/abc/as-of?at=yesterday", nil, http.StatusBadRequest},
+		{"not found", "/api/todos/abc/as-of?at=2026-01-02T15:04:05Z", domain.ErrTodoNotFound, http.StatusNotFound},
+		{"not configured", "/api/todos/abc/as-of?at=2026-01-02T15:04:05Z", application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				getTodoAsOf: func(ctx context.Context, id string, at time.Time) (*application.TodoResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serve(t, service, tt.target)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_MergeTodos_Success(t *testing.T) {
+	var gotCanonical, gotDuplicate string
+	service := &fakeService{
+		mergeTodos: func(ctx context.Context, canonicalID, duplicateID string) (*application.MergeTodosResponse, error) {
+			gotCanonical, gotDuplicate = canonicalID, duplicateID
+			return &application.MergeTodosResponse{
+				Canonical: &application.TodoResponse{ID: "aaa", Status: "pending"},
+				Duplicate: &application.TodoResponse{ID: "bbb", Status: "cancelled", MergedInto: "aaa"},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todos/aaa/merge", strings.NewReader(`{"duplicate_id":"bbb"}`))
+	rec := serveRequest(t, service, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if gotCanonical != "aaa" || gotDuplicate != "bbb" {
+		t.Errorf("MergeTodos() called with %q, %q, want aaa, bbb", gotCanonical, gotDuplicate)
+	}
+
+	var body mergeTodosResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Duplicate.MergedInto != "aaa" {
+		t.Errorf("Duplicate merged_into = %q, want %q", body.Duplicate.MergedInto, "aaa")
+	}
+}
+
+func TestHandler_MergeTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"missing duplicate", `{}`, nil, http.StatusBadRequest},
+		{"into itself", `{"duplicate_id":"aaa"}`, domain.ErrCannotMergeIntoSelf, http.StatusBadRequest},
+		{"already merged", `{"duplicate_id":"bbb"}`, domain.ErrAlreadyMerged, http.StatusConflict},
+		{"completed", `{"duplicate_id":"bbb"}`, domain.ErrCannotModifyCompleted, http.StatusConflict},
+		{"modified concurrently", `{"duplicate_id":"bbb"}`, domain.ErrConcurrentModification, http.StatusConflict},
+		{"not found", `{"duplicate_id":"bbb"}`, domain.ErrTodoNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				mergeTodos: func(ctx context.Context, canonicalID, duplicateID string) (*application.MergeTodosResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/todos/aaa/merge", strings.NewReader(tt.body))
+			rec := serveRequest(t, service, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_MoveTodo(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		err       error
+		want      int
+		wantOwner string
+	}{
+		{name: "moved", body: `{"owner_id":"bob"}`, want: http.StatusOK, wantOwner: "bob"},
+		{name: "missing owner", body: `{}`, want: http.StatusBadRequest},
+		{name: "already owned", body: `{"owner_id":"alice"}`, err: domain.ErrAlreadyOwned, want: http.StatusBadRequest, wantOwner: "alice"},
+		{name: "denied", body: `{"owner_id":"bob"}`, err: application.ErrForbidden, want: http.StatusForbidden, wantOwner: "bob"},
+		{name: "not found", body: `{"owner_id":"bob"}`, err: domain.ErrTodoNotFound, want: http.StatusNotFound, wantOwner: "bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID, gotOwner string
+			service := &fakeService{
+				moveTodo: func(ctx context.Context, id, ownerID string) (*application.TodoResponse, error) {
+					gotID, gotOwner = id, ownerID
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &application.TodoResponse{ID: id, OwnerID: ownerID}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/todos/TD-7/move", strings.NewReader(tt.body))
+			rec := serveRequest(t, service, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.wantOwner != "" && (gotID != "TD-7" || gotOwner != tt.wantOwner) {
+				t.Errorf("MoveTodo() called with %q, %q, want TD-7, %s", gotID, gotOwner, tt.wantOwner)
+			}
+		})
+	}
+}
+
+func TestHandler_ArchiveTodo(t *testing.T) {
+	archivedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	var archived, unarchived string
+	service := &fakeService{
+		archiveTodo: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			archived = id
+			return &application.TodoResponse{ID: id, Status: "completed", ArchivedAt: &archivedAt}, nil
+		},
+		unarchiveTodo: func(ctx context.Context, id string) (*application.TodoResponse, error) {
+			unarchived = id
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/aaa/archive", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("archive status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var body todoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if archived != "aaa" || body.ArchivedAt == nil || !body.ArchivedAt.Equal(archivedAt) {
+		t.Errorf("archived %q, archived_at = %v, want aaa at %v", archived, body.ArchivedAt, archivedAt)
+	}
+
+	rec = serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/bbb/unarchive", nil))
+	if unarchived != "bbb" || rec.Code != http.StatusNotFound {
+		t.Errorf("unarchived %q with status %d, want bbb and %d", unarchived, rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandler_GetTodoAuditLog(t *testing.T) {
+	occurredAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	service := &fakeService{
+		getTodoAuditLog: func(ctx context.Context, id string) ([]application.TodoAuditLogEntry, error) {
+			if id != "aaa" {
+				return nil, domain.ErrTodoNotFound
+			}
+			return []application.TodoAuditLogEntry{
+				{OccurredAt: occurredAt, Actor: "alice", EventType: "TodoUpdated", Changes: map[string]string{"title": "Renamed"}},
+			}, nil
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/aaa/audit")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var body struct {
+		Entries []todoAuditEntryResponse `json:"entries"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Entries) != 1 || body.Entries[0].Actor != "alice" || body.Entries[0].Changes["title"] != "Renamed" {
+		t.Errorf("entries = %+v, want the rename by alice", body.Entries)
+	}
+
+	if rec := serve(t, service, "/api/todos/bbb/audit"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown todo status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandler_TriageTodos(t *testing.T) {
+	var got application.TriageRequest
+	service := &fakeService{
+		triageTodos: func(ctx context.Context, req application.TriageRequest) (*application.TriageReport, error) {
+			got = req
+			return &application.TriageReport{DryRun: req.DryRun, Changes: []application.TriageChange{
+				{TodoID: "aaa", Title: "Pay rent", Rule: 0, From: "medium", To: "urgent"},
+			}}, nil
+		},
+	}
+
+	body := `{"rules":[{"overdue":true,"set_priority":"urgent"},{"no_due_date":true,"older_than_days":30,"priority":"medium","set_priority":"low"}],"dry_run":true}`
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/triage", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !got.DryRun || len(got.Rules) != 2 || !got.Rules[0].Overdue || got.Rules[1].OlderThanDays != 30 || got.Rules[1].Priority == nil || *got.Rules[1].Priority != "medium" || got.Rules[1].SetPriority != "low" {
+		t.Errorf("TriageTodos() called with %+v, want the rules of the body", got)
+	}
+
+	var report triageTodosResponse
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if !report.DryRun || len(report.Changes) != 1 || report.Changes[0].TodoID != "aaa" || report.Changes[0].To != "urgent" {
+		t.Errorf("response = %+v, want the previewed change", report)
+	}
+}
+
+func TestHandler_TriageTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid body", `{"rules":`, nil, http.StatusBadRequest},
+		{"invalid rule", `{"rules":[]}`, domain.NewValidationError("rules", "at least one rule is required"), http.StatusBadRequest},
+		{"not supported", `{"rules":[{"overdue":true,"set_priority":"urgent"}]}`, application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				triageTodos: func(ctx context.Context, req application.TriageRequest) (*application.TriageReport, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/triage", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_PlanWeek(t *testing.T) {
+	var got application.PlanWeekRequest
+	service := &fakeService{
+		planWeek: func(ctx context.Context, req application.PlanWeekRequest) (*application.PlanWeekResponse, error) {
+			got = req
+			return &application.PlanWeekResponse{
+				Planned: []*application.TodoResponse{{ID: "aaa", Status: "pending"}},
+				Skipped: []application.PlanSkip{{TodoID: "bbb", Reason: application.PlanSkipCompleted}},
+			}, nil
+		},
+	}
+
+	body := `{"days":{"aaa":"2026-10-19","bbb":"2026-10-20"}}`
+	rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/plan", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if len(got.Days) != 2 || got.Days["aaa"] != "2026-10-19" {
+		t.Errorf("PlanWeek() called with %+v, want the days of the body", got)
+	}
+
+	var plan planWeekResponse
+	if err := json.NewDecoder(rec.Body).Decode(&plan); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(plan.Planned) != 1 || plan.Planned[0].ID != "aaa" || len(plan.Skipped) != 1 || plan.Skipped[0].Reason != "completed" {
+		t.Errorf("response = %+v, want aaa planned and bbb skipped", plan)
+	}
+}
+
+func TestHandler_PlanWeek_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"invalid body", `{"days":`, nil, http.StatusBadRequest},
+		{"not a working day", `{"days":{"aaa":"2026-10-18"}}`, domain.NewValidationError("days[aaa]", "Sunday is not a working day"), http.StatusBadRequest},
+		{"not found", `{"days":{"aaa":"2026-10-19"}}`, domain.ErrTodoNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				planWeek: func(ctx context.Context, req application.PlanWeekRequest) (*application.PlanWeekResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serveRequest(t, service, httptest.NewRequest(http.MethodPost, "/api/todos/plan", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+)
+
+// WatchOptions tunes the keepalive of watch streams
+type WatchOptions struct {
+	// Heartbeat is the delay after which a quiet stream gets a comment, so
+	// proxies keep it open and clients detect dead connections
+	Heartbeat time.Duration
+	// IdleTimeout ends a stream without changes for that long; clients
+	// reconnect and resume. Zero keeps quiet streams open
+	IdleTimeout time.Duration
+	// WriteTimeout drops clients that do not read a message within it
+	WriteTimeout time.Duration
+	// Retry is the reconnection delay advised to EventSource clients
+	Retry time.Duration
+}
+
+// DefaultWatchOptions returns the default keepalive of watch streams
+func DefaultWatchOptions() WatchOptions {
+	return WatchOptions{
+		Heartbeat:    15 * time.Second,
+		IdleTimeout:  30 * time.Minute,
+		WriteTimeout: 10 * time.Second,
+		Retry:        3 * time.Second,
+	}
+}
+
+// WithWatchOptions sets the keepalive of watch streams
+func WithWatchOptions(options WatchOptions) Option {
+	return func(h *Handler) {
+		h.watch = options
+	}
+}
+
+// todoChange is the JSON representation of a change pushed to watchers
+type todoChange struct {
+	Kind       string        `json:"kind"`
+	TodoID     string        `json:"todo_id"`
+	Todo       *todoResponse `json:"todo,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// watchTodos answers GET /api/todos/watch?status=&priority= with a stream
+// of Server-Sent Events, one per change, named after the kind of change
+// Each event carries a resume token as its ID; reconnecting with it in
+// Last-Event-ID (or the last_event_id parameter) replays the missed changes.
+// When they are no longer available, the stream starts with a "reset" event
+// and the client should reload what it displays
+// Quiet streams get heartbeat comments, and end after the idle timeout
+// The stream ends with an "error" event when the watcher falls behind
+func (h *Handler) watchTodos(w http.ResponseWriter, r *http.Request) {
+	var filters application.WatchFilters
+	query := r.URL.Query()
+	if status := query.Get("status"); status != "" {
+		filters.Status = &status
+	}
+	if priority := query.Get("priority"); priority != "" {
+		filters.Priority = &priority
+	}
+	filters.After = r.Header.Get("Last-Event-ID")
+	if after := query.Get("last_event_id"); after != "" {
+		filters.After = after
+	}
+
+	watch, err := h.service.WatchTodos(r.Context(), filters)
+	reset := errors.Is(err, application.ErrWatchResumeExpired)
+	if reset {
+		filters.After = ""
+		watch, err = h.service.WatchTodos(r.Context(), filters)
+	}
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	h.extendWriteDeadline(rc)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Clients that never receive a change still learn where to resume
+	fmt.Fprintf(w, "retry: %d\n", h.watch.Retry.Milliseconds())
+	writeResumeToken(w, watch.ResumeToken())
+	if reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n")
+	}
+	fmt.Fprint(w, "\n")
+	if err := rc.Flush(); err != nil {
+		h.logger.Error("streaming not supported", "path", r.URL.Path, "error", err)
+		return
+	}
+
+	lastChange := time.Now()
+	for {
+		change, err := h.nextChange(r.Context(), watch)
+		if r.Context().Err() != nil {
+			return
+		}
+		h.extendWriteDeadline(rc)
+		if errors.Is(err, context.DeadlineExceeded) {
+			if h.watch.IdleTimeout > 0 && time.Since(lastChange) >= h.watch.IdleTimeout {
+				return
+			}
+			fmt.Fprint(w, ": heartbeat\n")
+			writeResumeToken(w, watch.ResumeToken())
+			fmt.Fprint(w, "\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, application.ErrWatchLagged) {
+				h.logger.Error("watch failed", "path", r.URL.Path, "error", err)
+			}
+			data, _ := json.Marshal(map[string]string{"error": err.Error()})
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			_ = rc.Flush()
+			return
+		}
+
+		lastChange = time.Now()
+		if err := writeChange(w, change); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// nextChange waits for the next change at most a heartbeat interval
+func (h *Handler) nextChange(ctx context.Context, watch *application.TodoWatch) (*application.TodoChange, error) {
+	if h.watch.Heartbeat <= 0 {
+		return watch.Next(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.watch.Heartbeat)
+	defer cancel()
+	return watch.Next(ctx)
+}
+
+// extendWriteDeadline gives the client WriteTimeout to take the next message
+// The stream itself outlives the server write timeout
+func (h *Handler) extendWriteDeadline(rc *http.ResponseController) {
+	deadline := time.Time{}
+	if h.watch.WriteTimeout > 0 {
+		deadline = time.Now().Add(h.watch.WriteTimeout)
+	}
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+// writeResumeToken writes the id field of a message, when there is a token
+func writeResumeToken(w http.ResponseWriter, token string) {
+	if token != "" {
+		fmt.Fprintf(w, "id: %s\n", token)
+	}
+}
+
+// writeChange writes change as a Server-Sent Event
+func writeChange(w http.ResponseWriter, change *application.TodoChange) error {
+	event := todoChange{
+		Kind:       change.Kind,
+		TodoID:     change.TodoID,
+		OccurredAt: change.OccurredAt,
+	}
+	if change.Todo != nil {
+		todo := mapTodo(change.Todo)
+		event.Todo = &todo
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	writeResumeToken(w, change.ResumeToken)
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", change.Kind, data)
+	return err
+}
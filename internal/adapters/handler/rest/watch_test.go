@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// chanSubscriber delivers the events sent on the channel to its subscriber
+type chanSubscriber chan domain.DomainEvent
+
+func (c chanSubscriber) Subscribe(ctx context.Context) <-chan domain.DomainEvent {
+	return c
+}
+
+func TestHandler_WatchTodos_StreamsChanges(t *testing.T) {
+	todoID := domain.NewTodoID()
+	events := make(chanSubscriber, 1)
+	events <- domain.NewTodoDeletedEvent(todoID, "alice")
+	close(events)
+
+	var got application.WatchFilters
+	service := &fakeService{
+		watchTodos: func(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error) {
+			got = filters
+			watcher := application.NewTodoApplicationService(nil, nil, application.WithEventSubscriber(events))
+			return watcher.WatchTodos(application.ContextWithUserID(ctx, "alice"), filters)
+		},
+	}
+
+	rec := serve(t, service, "/api/todos/watch?status=pending")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got.Status == nil || *got.Status != "pending" {
+		t.Errorf("WatchTodos() status = %v, want pending", got.Status)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: deleted\ndata: ") || !strings.Contains(body, `"todo_id":"`+todoID.String()+`"`) {
+		t.Errorf("body = %q, want a deleted event for %v", body, todoID)
+	}
+	// The closed subscription reports a lagging watcher
+	if !strings.HasSuffix(body, "event: error\ndata: {\"error\":\""+application.ErrWatchLagged.Error()+"\"}\n\n") {
+		t.Errorf("body = %q, want it to end with an error event", body)
+	}
+}
+
+func TestHandler_WatchTodos_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid filter", domain.NewValidationError("status", "is invalid"), http.StatusBadRequest},
+		{"not supported", application.ErrNotSupported, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{
+				watchTodos: func(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serve(t, service, "/api/todos/watch")
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// resumableSubscriber starts every subscription at position p7, and only
+// resumes from there
+type resumableSubscriber struct {
+	chanSubscriber
+}
+
+func (r resumableSubscriber) SubscribeFrom(ctx context.Context, position string) (<-chan ports.PositionedEvent, string, error) {
+	if position != "" && position != "p7" {
+		return nil, "", ports.ErrEventPositionExpired
+	}
+	return make(chan ports.PositionedEvent), "p7", nil
+}
+
+func TestHandler_WatchTodos_KeepsAliveAndResumes(t *testing.T) {
+	service := &fakeService{
+		watchTodos: func(ctx context.Context, filters application.WatchFilters) (*application.TodoWatch, error) {
+			watcher := application.NewTodoApplicationService(nil, nil, application.WithEventSubscriber(resumableSubscriber{}))
+			return watcher.WatchTodos(ctx, filters)
+		},
+	}
+	handler := NewHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)), WithWatchOptions(WatchOptions{
+		Heartbeat:   10 * time.Millisecond,
+		IdleTimeout: 35 * time.Millisecond,
+		Retry:       time.Second,
+	}))
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	tests := []struct {
+		name      string
+		lastID    string
+		wantReset bool
+	}{
+		{"fresh", "", false},
+		{"resumed", "p7", false},
+		{"expired", "p1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/todos/watch", nil)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			body := rec.Body.String()
+			if !strings.HasPrefix(body, "retry: 1000\nid: p7\n") {
+				t.Errorf("body = %q, want it to start with the retry delay and resume token", body)
+			}
+			if got := strings.Contains(body, "event: reset\n"); got != tt.wantReset {
+				t.Errorf("reset event sent = %v, want %v", got, tt.wantReset)
+			}
+			// The idle timeout ends the stream after a few heartbeats
+			if !strings.Contains(body, ": heartbeat\nid: p7\n\n") {
+				t.Errorf("body = %q, want heartbeats carrying the resume token", body)
+			}
+		})
+	}
+}
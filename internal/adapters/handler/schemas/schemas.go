@@ -0,0 +1,126 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	"github.com/pivaldi/mmw/todo/internal/adapters/webhook"
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// version is the version of the event payloads, in the URL of their
+// schemas; it changes with incompatible payload changes only
+const version = "v1"
+
+// todoEvents are samples of the events raised on a todo, which webhook
+// digests consolidate
+var todoEvents = []domain.DomainEvent{
+	domain.TodoCreated{},
+	domain.TodoUpdated{},
+	domain.TodoCompleted{},
+	domain.TodoReopened{},
+	domain.TodoDeleted{},
+	domain.TodoForceUpdated{},
+	domain.TodoMerged{},
+	domain.TodoMoved{},
+	domain.TodoArchived{},
+	domain.TodoUnarchived{},
+	domain.TodoDueSoon{},
+	domain.TodoOverdue{},
+}
+
+// serviceEvents are samples of the other events the service dispatches
+var serviceEvents = []domain.DomainEvent{
+	application.MilestoneCompleted{},
+	application.SecurityAnomalyDetected{},
+	application.WeekPlanned{},
+}
+
+// schemaEntry is the JSON representation of a schema in the index
+type schemaEntry struct {
+	EventType string `json:"event_type"`
+	URL       string `json:"url"`
+}
+
+// indexResponse is the JSON representation of the schema index
+type indexResponse struct {
+	Version string        `json:"version"`
+	Schemas []schemaEntry `json:"schemas"`
+}
+
+// Handler serves the JSON Schemas of the event payloads published to the
+// brokers and posted to webhooks, for consumers to validate them and
+// generate their types
+type Handler struct {
+	index   indexResponse
+	schemas map[string][]byte
+}
+
+// NewHandler creates a Handler of the schemas of every event, encoded once
+func NewHandler() (*Handler, error) {
+	h := &Handler{
+		index:   indexResponse{Version: version},
+		schemas: make(map[string][]byte),
+	}
+
+	var refs []string
+	for _, event := range todoEvents {
+		refs = append(refs, event.EventType()+".json")
+	}
+	for _, event := range slices.Concat(todoEvents, serviceEvents) {
+		if err := h.add(event.EventType(), events.EventSchema(event)); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.add(ports.EventDigestType, webhook.DigestSchema(refs)); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// add encodes the schema of eventType under its URL
+func (h *Handler) add(eventType string, schema map[string]any) error {
+	url := "/schemas/" + version + "/" + eventType + ".json"
+	schema["$id"] = url
+
+	body, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding schema of %s: %w", eventType, err)
+	}
+	h.schemas[eventType+".json"] = body
+	h.index.Schemas = append(h.index.Schemas, schemaEntry{EventType: eventType, URL: url})
+
+	return nil
+}
+
+// RegisterRoutes registers the schema routes under /schemas
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /schemas", h.listSchemas)
+	mux.HandleFunc("GET /schemas/"+version+"/{file}", h.getSchema)
+}
+
+// listSchemas answers GET /schemas with the URL of the schema of every
+// event type
+func (h *Handler) listSchemas(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.index)
+}
+
+// getSchema answers GET /schemas/v1/{event_type}.json with the schema of
+// the payloads of the event type
+func (h *Handler) getSchema(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.schemas[r.PathValue("file")]
+	if !ok {
+		http.Error(w, "unknown event type", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,98 @@
+package schemas
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// serve serves a GET of target with the routes of a Handler
+func serve(t *testing.T, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler, err := NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler() failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestHandler_Schemas(t *testing.T) {
+	rec := serve(t, "/schemas")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var index indexResponse
+	if err := json.NewDecoder(rec.Body).Decode(&index); err != nil {
+		t.Fatalf("decoding index: %v", err)
+	}
+	if want := len(todoEvents) + len(serviceEvents) + 1; len(index.Schemas) != want {
+		t.Errorf("index lists %d schemas, want %d", len(index.Schemas), want)
+	}
+
+	for _, entry := range index.Schemas {
+		rec := serve(t, entry.URL)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/schema+json" {
+			t.Errorf("GET %s = %d %s, want a schema", entry.URL, rec.Code, rec.Header().Get("Content-Type"))
+			continue
+		}
+
+		var schema map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&schema); err != nil {
+			t.Fatalf("decoding %s: %v", entry.URL, err)
+		}
+		if schema["$id"] != entry.URL || schema["title"] != entry.EventType {
+			t.Errorf("schema of %s = %v %v, want its URL and event type", entry.EventType, schema["$id"], schema["title"])
+		}
+	}
+}
+
+func TestHandler_DigestReferences(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Data struct {
+				Properties struct {
+					Events struct {
+						Items struct {
+							OneOf []struct {
+								Ref string `json:"$ref"`
+							} `json:"oneOf"`
+						} `json:"items"`
+					} `json:"events"`
+				} `json:"properties"`
+			} `json:"data"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(serve(t, "/schemas/v1/"+ports.EventDigestType+".json").Body).Decode(&schema); err != nil {
+		t.Fatalf("decoding digest schema: %v", err)
+	}
+
+	refs := schema.Properties.Data.Properties.Events.Items.OneOf
+	if len(refs) != len(todoEvents) {
+		t.Fatalf("digest references %d schemas, want %d", len(refs), len(todoEvents))
+	}
+	for _, ref := range refs {
+		// References are relative to the URL of the digest schema
+		if rec := serve(t, path.Join("/schemas/v1", ref.Ref)); rec.Code != http.StatusOK {
+			t.Errorf("%s resolves to %d, want a schema", ref.Ref, rec.Code)
+		}
+	}
+}
+
+func TestHandler_UnknownSchema(t *testing.T) {
+	for _, target := range []string{"/schemas/v1/Unknown.json", "/schemas/v2/TodoCreated.json"} {
+		if rec := serve(t, target); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want %d", target, rec.Code, http.StatusNotFound)
+		}
+	}
+}
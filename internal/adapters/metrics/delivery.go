@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// deliveryCollector reads the delivery of the events to the broker at each
+// scrape
+type deliveryCollector struct {
+	delivery ports.EventDeliveryMonitor
+
+	degraded *prometheus.Desc
+	buffered *prometheus.Desc
+	relayed  *prometheus.Desc
+}
+
+// RegisterEventDelivery registers the degraded mode and the outbox of the
+// event relay with registerer
+func RegisterEventDelivery(registerer prometheus.Registerer, delivery ports.EventDeliveryMonitor) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "event_delivery", name), help, nil, nil)
+	}
+	registerer.MustRegister(&deliveryCollector{
+		delivery: delivery,
+		degraded: desc("degraded", "1 while the event broker is unavailable and the events wait in the outbox."),
+		buffered: desc("buffered_events", "Domain events waiting in the outbox for the broker."),
+		relayed:  desc("relayed_events_total", "Domain events relayed from the outbox to the broker."),
+	})
+}
+
+// Describe sends the descriptions of the delivery metrics
+func (c *deliveryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.degraded
+	ch <- c.buffered
+	ch <- c.relayed
+}
+
+// Collect sends the current state of the delivery
+func (c *deliveryCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.delivery.EventDeliveryStats()
+	degraded := 0.0
+	if stats.Degraded {
+		degraded = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.degraded, prometheus.GaugeValue, degraded)
+	ch <- prometheus.MustNewConstMetric(c.buffered, prometheus.GaugeValue, float64(stats.Buffered))
+	ch <- prometheus.MustNewConstMetric(c.relayed, prometheus.CounterValue, float64(stats.Relayed))
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// CountingDispatcher decorates an EventDispatcher, counting the domain
+// events it dispatches by type and outcome
+type CountingDispatcher struct {
+	next   ports.EventDispatcher
+	events *prometheus.CounterVec
+}
+
+// NewCountingDispatcher creates a CountingDispatcher registered with
+// registerer, dispatching to next
+func NewCountingDispatcher(next ports.EventDispatcher, registerer prometheus.Registerer) *CountingDispatcher {
+	d := &CountingDispatcher{
+		next: next,
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "domain_events_dispatched_total",
+			Help:      "Domain events dispatched, by event type and result (ok or error).",
+		}, []string{"event_type", "result"}),
+	}
+	registerer.MustRegister(d.events)
+	return d
+}
+
+// Dispatch dispatches events to the next dispatcher and counts them, all
+// failed when it fails
+func (d *CountingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	err := d.next.Dispatch(ctx, events)
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	for _, event := range events {
+		d.events.WithLabelValues(event.EventType(), result).Inc()
+	}
+	return err
+}
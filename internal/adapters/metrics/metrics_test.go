@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// scrape returns the metrics of registry as served on GET /metrics
+func scrape(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+// wantLines fails unless every line is in the scraped metrics
+func wantLines(t *testing.T, metrics string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("metrics miss %q:\n%s", line, metrics)
+		}
+	}
+}
+
+func TestNewRegistry_RuntimeMetrics(t *testing.T) {
+	metrics := scrape(t, NewRegistry())
+	if !strings.Contains(metrics, "go_goroutines ") {
+		t.Errorf("metrics miss the Go runtime ones:\n%s", metrics)
+	}
+}
+
+// fakeRequest is a unary request of a procedure
+type fakeRequest struct {
+	connect.AnyRequest
+	spec connect.Spec
+}
+
+func (r fakeRequest) Spec() connect.Spec {
+	return r.spec
+}
+
+func TestRPCInterceptor_WrapUnary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"success", nil, "ok"},
+		{"connect error", connect.NewError(connect.CodeNotFound, errors.New("todo not found")), "not_found"},
+		{"plain error", errors.New("boom"), "unknown"},
+	}
+
+	registry := prometheus.NewRegistry()
+	interceptor := NewRPCInterceptor(registry)
+	for _, tt := range tests {
+		call := interceptor.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, tt.err
+		})
+		req := fakeRequest{spec: connect.Spec{Procedure: "/todo.v1.TodoService/GetTodo"}}
+		if _, err := call(context.Background(), req); err != tt.err {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	wantLines(t, scrape(t, registry),
+		`todo_rpc_requests_total{code="ok",procedure="/todo.v1.TodoService/GetTodo"} 1`,
+		`todo_rpc_requests_total{code="not_found",procedure="/todo.v1.TodoService/GetTodo"} 1`,
+		`todo_rpc_requests_total{code="unknown",procedure="/todo.v1.TodoService/GetTodo"} 1`,
+		`todo_rpc_request_duration_seconds_count{procedure="/todo.v1.TodoService/GetTodo"} 3`,
+	)
+}
+
+func TestRPCInterceptor_IgnoresClientCalls(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	call := NewRPCInterceptor(registry).WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, nil
+	})
+	_, _ = call(context.Background(), fakeRequest{spec: connect.Spec{Procedure: "/todo.v1.TodoService/GetTodo", IsClient: true}})
+
+	if metrics := scrape(t, registry); strings.Contains(metrics, "todo_rpc_requests_total{") {
+		t.Errorf("client call counted:\n%s", metrics)
+	}
+}
+
+// fakePool reports fixed statistics
+type fakePool ports.PoolStats
+
+func (p fakePool) PoolStats() ports.PoolStats {
+	return ports.PoolStats(p)
+}
+
+func TestRegisterPool(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	RegisterPool(registry, fakePool{
+		MaxConns:         10,
+		TotalConns:       4,
+		AcquiredConns:    3,
+		IdleConns:        1,
+		Acquires:         120,
+		EmptyAcquires:    7,
+		CanceledAcquires: 2,
+		AcquireDuration:  1500 * time.Millisecond,
+	})
+
+	wantLines(t, scrape(t, registry),
+		"todo_db_pool_max_conns 10",
+		"todo_db_pool_total_conns 4",
+		"todo_db_pool_acquired_conns 3",
+		"todo_db_pool_idle_conns 1",
+		"todo_db_pool_acquires_total 120",
+		"todo_db_pool_empty_acquires_total 7",
+		"todo_db_pool_canceled_acquires_total 2",
+		"todo_db_pool_acquire_duration_seconds_total 1.5",
+	)
+}
+
+// fakeDelivery reports fixed delivery stats
+type fakeDelivery ports.EventDeliveryStats
+
+func (d fakeDelivery) EventDeliveryStats() ports.EventDeliveryStats {
+	return ports.EventDeliveryStats(d)
+}
+
+func TestRegisterEventDelivery(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats fakeDelivery
+		want  []string
+	}{
+		{
+			name:  "broker available",
+			stats: fakeDelivery{Relayed: 12},
+			want: []string{
+				"todo_event_delivery_degraded 0",
+				"todo_event_delivery_buffered_events 0",
+				"todo_event_delivery_relayed_events_total 12",
+			},
+		},
+		{
+			name:  "broker unavailable",
+			stats: fakeDelivery{Degraded: true, Buffered: 100},
+			want: []string{
+				"todo_event_delivery_degraded 1",
+				"todo_event_delivery_buffered_events 100",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			RegisterEventDelivery(registry, tt.stats)
+			wantLines(t, scrape(t, registry), tt.want...)
+		})
+	}
+}
+
+// stubDispatcher returns err from every dispatch
+type stubDispatcher struct {
+	err error
+}
+
+func (d stubDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	return d.err
+}
+
+func TestCountingDispatcher(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	deleted := domain.NewTodoDeletedEvent(domain.NewTodoID(), "")
+
+	ok := NewCountingDispatcher(stubDispatcher{}, registry)
+	if err := ok.Dispatch(context.Background(), []domain.DomainEvent{deleted, deleted}); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	failing := &CountingDispatcher{next: stubDispatcher{err: errors.New("broker unavailable")}, events: ok.events}
+	if err := failing.Dispatch(context.Background(), []domain.DomainEvent{deleted}); err == nil {
+		t.Fatal("Dispatch() error = nil, want the dispatcher error")
+	}
+
+	wantLines(t, scrape(t, registry),
+		`todo_domain_events_dispatched_total{event_type="`+deleted.EventType()+`",result="ok"} 2`,
+		`todo_domain_events_dispatched_total{event_type="`+deleted.EventType()+`",result="error"} 1`,
+	)
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// poolCollector reads the statistics of a database pool at each scrape
+type poolCollector struct {
+	pool ports.PoolMonitor
+
+	maxConns         *prometheus.Desc
+	totalConns       *prometheus.Desc
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+}
+
+// RegisterPool registers the statistics of the database pool with
+// registerer
+func RegisterPool(registerer prometheus.Registerer, pool ports.PoolMonitor) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	registerer.MustRegister(&poolCollector{
+		pool:             pool,
+		maxConns:         desc("max_conns", "Maximum size of the database pool."),
+		totalConns:       desc("total_conns", "Connections open in the database pool."),
+		acquiredConns:    desc("acquired_conns", "Connections of the database pool in use."),
+		idleConns:        desc("idle_conns", "Idle connections of the database pool."),
+		acquires:         desc("acquires_total", "Connections acquired from the database pool."),
+		emptyAcquires:    desc("empty_acquires_total", "Acquisitions that waited for a connection, the pool being empty."),
+		canceledAcquires: desc("canceled_acquires_total", "Acquisitions canceled before getting a connection."),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+	})
+}
+
+// Describe sends the descriptions of the pool metrics
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxConns
+	ch <- c.totalConns
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+}
+
+// Collect sends the current statistics of the pool
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.pool.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxConns))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stats.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stats.Acquires))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stats.EmptyAcquires))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stats.CanceledAcquires))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stats.AcquireDuration.Seconds())
+}
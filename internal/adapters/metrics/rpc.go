@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RPCInterceptor counts and times the RPCs served by a Connect handler, per
+// procedure and status code
+type RPCInterceptor struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewRPCInterceptor creates an RPCInterceptor registered with registerer
+// It must come first, so that the calls rejected by the other interceptors
+// are counted too
+func NewRPCInterceptor(registerer prometheus.Registerer) *RPCInterceptor {
+	i := &RPCInterceptor{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "requests_total",
+			Help:      "RPCs served, by procedure and status code (ok or a Connect error code).",
+		}, []string{"procedure", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent serving RPCs, by procedure.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"procedure"}),
+	}
+	registerer.MustRegister(i.requests, i.duration)
+	return i
+}
+
+// WrapUnary observes a unary RPC
+func (i *RPCInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		start := time.Now()
+		resp, err := next(ctx, req)
+		i.observe(req.Spec().Procedure, start, err)
+		return resp, err
+	}
+}
+
+// WrapStreamingClient leaves the client streams alone
+func (i *RPCInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler observes a streaming RPC, once it ends
+func (i *RPCInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		i.observe(conn.Spec().Procedure, start, err)
+		return err
+	}
+}
+
+// observe records an RPC of procedure started at start and ending with err
+func (i *RPCInterceptor) observe(procedure string, start time.Time, err error) {
+	i.duration.WithLabelValues(procedure).Observe(time.Since(start).Seconds())
+	i.requests.WithLabelValues(procedure, statusCode(err)).Inc()
+}
+
+// statusCode returns the Connect code of err, "ok" when nil
+// Errors that are not Connect errors are served as unknown
+func statusCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code().String()
+	}
+	return connect.CodeUnknown.String()
+}
@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// DecisionQuery is the rule deciding whether a request is allowed: the
+// allow rule of the policy's todo package
+const DecisionQuery = "data.todo.allow"
+
+// OPAAuthorizer evaluates a Rego policy in-process with the Open Policy
+// Agent engine
+// The AuthorizationRequest is the policy input, and the policy decision
+// must be a boolean; an undefined decision denies the request
+type OPAAuthorizer struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewOPAAuthorizer compiles the Rego module source, read from filename, and
+// creates an authorizer evaluating its DecisionQuery
+func NewOPAAuthorizer(ctx context.Context, filename, source string) (*OPAAuthorizer, error) {
+	query, err := rego.New(
+		rego.Query(DecisionQuery),
+		rego.Module(filename, source),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("compiling policy: %w", err)
+	}
+	return &OPAAuthorizer{query: query}, nil
+}
+
+// opaInput is the policy input, the JSON form of an AuthorizationRequest
+type opaInput struct {
+	UserID string    `json:"user_id"`
+	Roles  []string  `json:"roles,omitempty"`
+	Action string    `json:"action"`
+	Todo   *opaTodo  `json:"todo,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// opaTodo is the JSON form of TodoAttributes
+type opaTodo struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	Priority  string     `json:"priority"`
+	DueDate   *time.Time `json:"due_date,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	OwnerID   string     `json:"owner_id,omitempty"`
+}
+
+// Authorize evaluates the policy with req as its input
+func (a *OPAAuthorizer) Authorize(ctx context.Context, req ports.AuthorizationRequest) (bool, error) {
+	input := opaInput{UserID: req.UserID, Roles: req.Roles, Action: req.Action, Time: req.Time}
+	if req.Todo != nil {
+		todo := opaTodo(*req.Todo)
+		input.Todo = &todo
+	}
+
+	results, err := a.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return false, fmt.Errorf("evaluating policy: %w", err)
+	}
+
+	// Allowed is false for undefined and non-boolean decisions
+	return results.Allowed(), nil
+}
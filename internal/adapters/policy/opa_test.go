@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+const testPolicy = `package todo
+
+default allow := false
+
+allow if input.action in {"read", "list"}
+
+allow if {
+	input.user_id != ""
+	input.todo.priority != "urgent"
+}
+`
+
+func TestOPAAuthorizer_Authorize(t *testing.T) {
+	authorizer, err := NewOPAAuthorizer(context.Background(), "policy.rego", testPolicy)
+	if err != nil {
+		t.Fatalf("NewOPAAuthorizer() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  ports.AuthorizationRequest
+		want bool
+	}{
+		{"list", ports.AuthorizationRequest{Action: "list"}, true},
+		{
+			"signed-in update",
+			ports.AuthorizationRequest{UserID: "alice", Action: "update", Todo: &ports.TodoAttributes{ID: "123", Priority: "low"}},
+			true,
+		},
+		{
+			"urgent update",
+			ports.AuthorizationRequest{UserID: "alice", Action: "update", Todo: &ports.TodoAttributes{ID: "123", Priority: "urgent"}},
+			false,
+		},
+		{
+			"anonymous update",
+			ports.AuthorizationRequest{Action: "update", Todo: &ports.TodoAttributes{ID: "123", Priority: "low"}},
+			false,
+		},
+		{"delete without todo", ports.AuthorizationRequest{UserID: "alice", Action: "delete"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := authorizer.Authorize(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Authorize() unexpected error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Authorize() = %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
+func TestOPAAuthorizer_UndefinedDecision(t *testing.T) {
+	authorizer, err := NewOPAAuthorizer(context.Background(), "policy.rego", "package todo\n\nallow if input.action == \"read\"\n")
+	if err != nil {
+		t.Fatalf("NewOPAAuthorizer() unexpected error: %v", err)
+	}
+
+	allowed, err := authorizer.Authorize(context.Background(), ports.AuthorizationRequest{Action: "delete"})
+	if err != nil || allowed {
+		t.Errorf("Authorize() = %v, %v, want a denial", allowed, err)
+	}
+}
+
+func TestOPAAuthorizer_NonBooleanDecision(t *testing.T) {
+	authorizer, err := NewOPAAuthorizer(context.Background(), "policy.rego", "package todo\n\nallow := \"yes\"\n")
+	if err != nil {
+		t.Fatalf("NewOPAAuthorizer() unexpected error: %v", err)
+	}
+
+	allowed, err := authorizer.Authorize(context.Background(), ports.AuthorizationRequest{Action: "read"})
+	if err != nil || allowed {
+		t.Errorf("Authorize() = %v, %v, want a denial", allowed, err)
+	}
+}
+
+func TestNewOPAAuthorizer_InvalidPolicy(t *testing.T) {
+	if _, err := NewOPAAuthorizer(context.Background(), "policy.rego", "package todo\n\nallow if {"); err == nil {
+		t.Error("NewOPAAuthorizer() expected an error for an invalid policy")
+	}
+}
@@ -0,0 +1,179 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// APNs hosts
+const (
+	apnsProductionURL  = "https://api.push.apple.com"
+	apnsDevelopmentURL = "https://api.sandbox.push.apple.com"
+)
+
+// apnsTokenLifetime is how long a provider token is used; APNs rejects
+// tokens older than an hour and refreshed more often than every 20 minutes
+const apnsTokenLifetime = 50 * time.Minute
+
+// apnsUnregistered are the reasons APNs gives for tokens that will never be
+// valid again
+var apnsUnregistered = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+}
+
+// APNsConfig identifies the app notifications are sent to
+type APNsConfig struct {
+	// KeyID and TeamID identify the .p8 authentication key, Key its PEM
+	KeyID  string
+	TeamID string
+	Key    []byte
+	// Topic is the bundle ID of the app
+	Topic string
+	// Production sends to the production environment instead of the
+	// sandbox development builds register with
+	Production bool
+}
+
+// APNsSender sends alert notifications through the APNs HTTP/2 API,
+// authenticated by provider tokens signed with the key of the team
+type APNsSender struct {
+	client  *http.Client
+	config  APNsConfig
+	signer  any
+	baseURL string
+	now     func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender creates an APNsSender giving up on a request after timeout
+func NewAPNsSender(config APNsConfig, timeout time.Duration) (*APNsSender, error) {
+	if config.KeyID == "" || config.TeamID == "" || config.Topic == "" {
+		return nil, errors.New("APNs needs a key ID, a team ID and a topic")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(config.Key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing APNs key: %w", err)
+	}
+
+	baseURL := apnsDevelopmentURL
+	if config.Production {
+		baseURL = apnsProductionURL
+	}
+	return &APNsSender{
+		// The default transport negotiates the HTTP/2 APNs requires
+		client:  &http.Client{Timeout: timeout},
+		config:  config,
+		signer:  key,
+		baseURL: baseURL,
+		now:     time.Now,
+	}, nil
+}
+
+// Send implements ports.PushSender
+func (s *APNsSender) Send(ctx context.Context, device ports.Device, message ports.PushMessage) error {
+	body, err := fit(message, MaxPayloadBytes, apnsPayload)
+	if err != nil {
+		return fmt.Errorf("encoding APNs payload: %w", err)
+	}
+
+	token, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+url.PathEscape(device.Token), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating APNs request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", s.config.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if message.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", message.CollapseKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending APNs notification: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(respBody, &failure)
+	if resp.StatusCode == http.StatusGone || apnsUnregistered[failure.Reason] {
+		return ports.ErrDeviceUnregistered
+	}
+	if failure.Reason == "ExpiredProviderToken" {
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("APNs answered %s: %s", resp.Status, failure.Reason)
+}
+
+// apnsPayload returns the payload of message: an alert in the aps
+// dictionary, the data as custom keys beside it
+func apnsPayload(message ports.PushMessage) ([]byte, error) {
+	alert := map[string]string{"title": message.Title}
+	if message.Body != "" {
+		alert["body"] = message.Body
+	}
+
+	payload := make(map[string]any, len(message.Data)+1)
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+	payload["aps"] = map[string]any{"alert": alert, "sound": "default"}
+	return json.Marshal(payload)
+}
+
+// providerToken returns the provider token, signing a new one when the last
+// one is apnsTokenLifetime old
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.config.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.config.KeyID
+	signed, err := token.SignedString(s.signer)
+	if err != nil {
+		return "", fmt.Errorf("signing APNs provider token: %w", err)
+	}
+
+	s.token, s.issuedAt = signed, now
+	return s.token, nil
+}
@@ -0,0 +1,152 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// newTestAPNsSender returns an APNsSender posting to server, and the public
+// key checking its provider tokens
+func newTestAPNsSender(t *testing.T, server *httptest.Server) (*APNsSender, *ecdsa.PublicKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("encoding key: %v", err)
+	}
+
+	sender, err := NewAPNsSender(APNsConfig{
+		KeyID:  "ABC123DEFG",
+		TeamID: "DEF123GHIJ",
+		Key:    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		Topic:  "com.example.todo",
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("NewAPNsSender() unexpected error: %v", err)
+	}
+	sender.baseURL = server.URL
+	return sender, &key.PublicKey
+}
+
+func TestAPNsSender_Send(t *testing.T) {
+	var (
+		path   string
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, header = r.URL.Path, r.Header
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sender, publicKey := newTestAPNsSender(t, server)
+	message := ports.PushMessage{Title: "Due soon", Body: "Renew the domain", Data: map[string]string{"todo_id": "abc"}, CollapseKey: "abc"}
+	if err := sender.Send(context.Background(), ports.Device{Token: "apns-token"}, message); err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+
+	if path != "/3/device/apns-token" {
+		t.Errorf("path = %q, want the device token", path)
+	}
+	if header.Get("apns-topic") != "com.example.todo" || header.Get("apns-push-type") != "alert" || header.Get("apns-collapse-id") != "abc" {
+		t.Errorf("headers = %v, want the topic, push type and collapse ID", header)
+	}
+
+	bearer := strings.TrimPrefix(header.Get("Authorization"), "bearer ")
+	token, err := jwt.Parse(bearer, func(token *jwt.Token) (any, error) { return publicKey, nil }, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("provider token %q: %v", bearer, err)
+	}
+	if token.Header["kid"] != "ABC123DEFG" {
+		t.Errorf("kid = %v, want the key ID", token.Header["kid"])
+	}
+	if iss, _ := token.Claims.GetIssuer(); iss != "DEF123GHIJ" {
+		t.Errorf("iss = %q, want the team ID", iss)
+	}
+
+	var payload struct {
+		APS struct {
+			Alert map[string]string `json:"alert"`
+		} `json:"aps"`
+		TodoID string `json:"todo_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("body %s: %v", body, err)
+	}
+	if payload.APS.Alert["title"] != "Due soon" || payload.APS.Alert["body"] != "Renew the domain" || payload.TodoID != "abc" {
+		t.Errorf("payload = %s, want the alert and the data", body)
+	}
+}
+
+func TestAPNsSender_ProviderTokenReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sender, _ := newTestAPNsSender(t, server)
+	now := time.Unix(1772355600, 0)
+	sender.now = func() time.Time { return now }
+
+	first, _ := sender.providerToken()
+	now = now.Add(apnsTokenLifetime - time.Second)
+	if second, _ := sender.providerToken(); second != first {
+		t.Error("provider token renewed before its lifetime")
+	}
+	now = now.Add(time.Second)
+	if third, _ := sender.providerToken(); third == first {
+		t.Error("provider token not renewed after its lifetime")
+	}
+}
+
+func TestAPNsSender_Send_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		reason     string
+		unregister bool
+	}{
+		{"unregistered", http.StatusGone, "Unregistered", true},
+		{"bad token", http.StatusBadRequest, "BadDeviceToken", true},
+		{"wrong topic", http.StatusBadRequest, "DeviceTokenNotForTopic", true},
+		{"too many requests", http.StatusTooManyRequests, "TooManyRequests", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"reason":"` + tt.reason + `"}`))
+			}))
+			defer server.Close()
+
+			sender, _ := newTestAPNsSender(t, server)
+			err := sender.Send(context.Background(), ports.Device{Token: "t"}, ports.PushMessage{Title: "x"})
+			if err == nil {
+				t.Fatal("Send() error = nil, want an error")
+			}
+			if errors.Is(err, ports.ErrDeviceUnregistered) != tt.unregister {
+				t.Errorf("Send() error = %v, want unregistered %v", err, tt.unregister)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// fcmScope is the OAuth2 scope granting the sending of FCM messages
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// fcmBaseURL is the FCM HTTP v1 API
+const fcmBaseURL = "https://fcm.googleapis.com"
+
+// tokenMargin renews access tokens this long before they expire
+const tokenMargin = time.Minute
+
+// serviceAccount is the part of a Google service account key file used to
+// obtain access tokens
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMSender sends notifications through the FCM HTTP v1 API, authenticated
+// as a service account of the Firebase project
+// Access tokens are obtained with a JWT signed by the account key and kept
+// until they expire
+type FCMSender struct {
+	client  *http.Client
+	account serviceAccount
+	signer  any
+	baseURL string
+	now     func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender creates an FCMSender from the JSON key file of a service
+// account, giving up on a request after timeout
+func NewFCMSender(credentials []byte, timeout time.Duration) (*FCMSender, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("parsing FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("FCM credentials need a project_id, a client_email and a private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parsing FCM private key: %w", err)
+	}
+
+	return &FCMSender{
+		client:  &http.Client{Timeout: timeout},
+		account: account,
+		signer:  key,
+		baseURL: fcmBaseURL,
+		now:     time.Now,
+	}, nil
+}
+
+// fcmRequest is the body of a messages:send request
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *fcmAndroid       `json:"android,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmAndroid struct {
+	CollapseKey string `json:"collapse_key"`
+}
+
+// fcmError is the body of a failed request
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send implements ports.PushSender
+func (s *FCMSender) Send(ctx context.Context, device ports.Device, message ports.PushMessage) error {
+	body, err := fit(message, MaxPayloadBytes, func(message ports.PushMessage) ([]byte, error) {
+		return json.Marshal(fcmRequestOf(device.Token, message))
+	})
+	if err != nil {
+		return fmt.Errorf("encoding FCM message: %w", err)
+	}
+
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := s.baseURL + "/v1/projects/" + url.PathEscape(s.account.ProjectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending FCM message: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	var failure fcmError
+	_ = json.Unmarshal(respBody, &failure)
+	if resp.StatusCode == http.StatusNotFound {
+		return ports.ErrDeviceUnregistered
+	}
+	for _, detail := range failure.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ports.ErrDeviceUnregistered
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The access token may have been revoked; get a new one next time
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("FCM answered %s: %s %s", resp.Status, failure.Error.Status, failure.Error.Message)
+}
+
+// fcmRequestOf returns the request sending message to the device of token
+func fcmRequestOf(token string, message ports.PushMessage) fcmRequest {
+	request := fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: message.Title, Body: message.Body},
+		Data:         message.Data,
+	}}
+	if message.CollapseKey != "" {
+		request.Message.Android = &fcmAndroid{CollapseKey: message.CollapseKey}
+	}
+	return request
+}
+
+// token returns a valid access token, obtaining a new one when the last one
+// is about to expire
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-tokenMargin)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.signer)
+	if err != nil {
+		return "", fmt.Errorf("signing FCM token request: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating FCM token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting FCM access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+		return "", fmt.Errorf("requesting FCM access token: %s", resp.Status)
+	}
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&grant); err != nil {
+		return "", fmt.Errorf("decoding FCM access token: %w", err)
+	}
+	if grant.AccessToken == "" {
+		return "", errors.New("FCM token response has no access_token")
+	}
+
+	s.accessToken = grant.AccessToken
+	s.expiresAt = now.Add(time.Duration(grant.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
@@ -0,0 +1,137 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// newTestFCMSender returns an FCMSender whose token and send requests go to
+// server
+func newTestFCMSender(t *testing.T, server *httptest.Server) *FCMSender {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	credentials, _ := json.Marshal(map[string]string{
+		"project_id":   "todo-app",
+		"client_email": "push@todo-app.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+
+	sender, err := NewFCMSender(credentials, time.Second)
+	if err != nil {
+		t.Fatalf("NewFCMSender() unexpected error: %v", err)
+	}
+	sender.baseURL = server.URL
+	return sender
+}
+
+func TestFCMSender_Send(t *testing.T) {
+	var (
+		tokenRequests int
+		auth          string
+		body          []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.FormValue("assertion") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3600,"token_type":"Bearer"}`))
+		case "/v1/projects/todo-app/messages:send":
+			auth = r.Header.Get("Authorization")
+			body, _ = io.ReadAll(r.Body)
+			_, _ = w.Write([]byte(`{"name":"projects/todo-app/messages/1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sender := newTestFCMSender(t, server)
+	device := ports.Device{Token: "fcm-token", Provider: ports.PushProviderFCM}
+	message := ports.PushMessage{Title: "Due soon", Body: "Renew the domain", Data: map[string]string{"todo_id": "abc"}, CollapseKey: "abc"}
+
+	for range 2 {
+		if err := sender.Send(context.Background(), device, message); err != nil {
+			t.Fatalf("Send() unexpected error: %v", err)
+		}
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("token requested %d times, want the cached token reused", tokenRequests)
+	}
+	if auth != "Bearer ya29.token" {
+		t.Errorf("Authorization = %q, want the access token", auth)
+	}
+	var got fcmRequest
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("body %s: %v", body, err)
+	}
+	if got.Message.Token != "fcm-token" || got.Message.Notification.Title != "Due soon" ||
+		got.Message.Data["todo_id"] != "abc" || got.Message.Android == nil || got.Message.Android.CollapseKey != "abc" {
+		t.Errorf("message = %+v, want the device token, notification, data and collapse key", got.Message)
+	}
+}
+
+func TestFCMSender_Send_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		unregister bool
+	}{
+		{"unregistered", http.StatusNotFound, `{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`, true},
+		{"unregistered detail", http.StatusBadRequest, `{"error":{"status":"INVALID_ARGUMENT","details":[{"errorCode":"UNREGISTERED"}]}}`, true},
+		{"unavailable", http.StatusServiceUnavailable, `{"error":{"status":"UNAVAILABLE"}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3600}`))
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := newTestFCMSender(t, server).Send(context.Background(), ports.Device{Token: "t"}, ports.PushMessage{Title: "x"})
+			if err == nil {
+				t.Fatal("Send() error = nil, want an error")
+			}
+			if errors.Is(err, ports.ErrDeviceUnregistered) != tt.unregister {
+				t.Errorf("Send() error = %v, want unregistered %v", err, tt.unregister)
+			}
+		})
+	}
+}
+
+func TestNewFCMSender_InvalidCredentials(t *testing.T) {
+	for _, credentials := range []string{"not json", `{"project_id":"p"}`, `{"project_id":"p","client_email":"e","private_key":"nope"}`} {
+		if _, err := NewFCMSender([]byte(credentials), time.Second); err == nil {
+			t.Errorf("NewFCMSender(%s) error = nil, want an error", credentials)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package push sends push notifications to mobile devices through Firebase
+// Cloud Messaging and the Apple Push Notification service
+package push
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxPayloadBytes is the largest payload both FCM and APNs accept for a
+// notification
+const MaxPayloadBytes = 4096
+
+// maxResponseBytes bounds the response body read from a provider
+const maxResponseBytes = 64 << 10
+
+// ellipsis ends a body shortened to fit the payload
+const ellipsis = "…"
+
+// Router sends each notification through the sender of the provider of its
+// device
+type Router struct {
+	senders map[ports.PushProvider]ports.PushSender
+}
+
+// NewRouter creates a Router; a nil sender leaves its provider unsupported
+func NewRouter(fcm, apns ports.PushSender) *Router {
+	senders := map[ports.PushProvider]ports.PushSender{}
+	if fcm != nil {
+		senders[ports.PushProviderFCM] = fcm
+	}
+	if apns != nil {
+		senders[ports.PushProviderAPNs] = apns
+	}
+	return &Router{senders: senders}
+}
+
+// Supports reports whether notifications can be sent through provider
+func (r *Router) Supports(provider ports.PushProvider) bool {
+	_, ok := r.senders[provider]
+	return ok
+}
+
+// Send implements ports.PushSender
+func (r *Router) Send(ctx context.Context, device ports.Device, message ports.PushMessage) error {
+	sender, ok := r.senders[device.Provider]
+	if !ok {
+		return fmt.Errorf("push provider %q is not configured", device.Provider)
+	}
+	return sender.Send(ctx, device, message)
+}
+
+// fit returns the payload encode makes of message, shortening its body until
+// the payload is at most limit bytes long
+// The body is cut on rune boundaries and ends with an ellipsis once cut;
+// a payload too long even without a body is an error
+func fit(message ports.PushMessage, limit int, encode func(ports.PushMessage) ([]byte, error)) ([]byte, error) {
+	payload, err := encode(message)
+	if err != nil || len(payload) <= limit {
+		return payload, err
+	}
+
+	body := message.Body
+	message.Body = ""
+	bare, err := encode(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(bare) > limit {
+		return nil, fmt.Errorf("push payload of %d bytes exceeds %d bytes without a body", len(bare), limit)
+	}
+
+	for {
+		// Escaping can make a body byte take several payload bytes: remove
+		// the excess scaled by the average, at least a byte per round
+		encoded := len(payload) - len(bare)
+		excess := len(payload) - limit + len(ellipsis)
+		remove := max(1, (excess*len(body)+encoded-1)/encoded)
+		cut := max(0, len(body)-remove)
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut]
+		if body == "" {
+			return bare, nil
+		}
+
+		message.Body = body + ellipsis
+		if payload, err = encode(message); err != nil || len(payload) <= limit {
+			return payload, err
+		}
+	}
+}
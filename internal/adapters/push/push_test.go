@@ -0,0 +1,100 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestFit(t *testing.T) {
+	encode := func(message ports.PushMessage) ([]byte, error) {
+		return json.Marshal(message)
+	}
+	overhead := func(message ports.PushMessage) int {
+		message.Body = ""
+		payload, _ := encode(message)
+		return len(payload)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		limit   int
+		wantCut bool
+		wantErr bool
+	}{
+		{"fits", "Buy milk", 200, false, false},
+		{"long", strings.Repeat("x", 500), 200, true, false},
+		{"multibyte", strings.Repeat("é", 300), 200, true, false},
+		{"escaped", strings.Repeat(`"`, 300), 200, true, false},
+		{"no room", "Buy milk", 10, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := ports.PushMessage{Title: "Due soon", Body: tt.body, Data: map[string]string{"todo_id": "abc"}}
+			payload, err := fit(message, tt.limit, encode)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("fit() error = nil, want an error for %d bytes of overhead", overhead(message))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fit() unexpected error: %v", err)
+			}
+			if len(payload) > tt.limit {
+				t.Errorf("payload is %d bytes, want at most %d", len(payload), tt.limit)
+			}
+
+			var got ports.PushMessage
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("payload %s is not the encoded message: %v", payload, err)
+			}
+			if !utf8.ValidString(got.Body) {
+				t.Errorf("body %q was cut inside a rune", got.Body)
+			}
+			if cut := got.Body != tt.body; cut != tt.wantCut {
+				t.Errorf("body = %q, cut %v, want cut %v", got.Body, cut, tt.wantCut)
+			}
+			if tt.wantCut && !strings.HasSuffix(got.Body, ellipsis) {
+				t.Errorf("body = %q, want it to end with an ellipsis", got.Body)
+			}
+		})
+	}
+}
+
+// recordingSender records the devices it sends to
+type recordingSender struct {
+	devices []ports.Device
+}
+
+func (s *recordingSender) Send(ctx context.Context, device ports.Device, message ports.PushMessage) error {
+	s.devices = append(s.devices, device)
+	return nil
+}
+
+func TestRouter_Send(t *testing.T) {
+	fcm := &recordingSender{}
+	router := NewRouter(fcm, nil)
+
+	if err := router.Send(context.Background(), ports.Device{Token: "a", Provider: ports.PushProviderFCM}, ports.PushMessage{}); err != nil {
+		t.Fatalf("Send(fcm) unexpected error: %v", err)
+	}
+	if len(fcm.devices) != 1 {
+		t.Errorf("FCM sender got %d devices, want 1", len(fcm.devices))
+	}
+
+	err := router.Send(context.Background(), ports.Device{Token: "b", Provider: ports.PushProviderAPNs}, ports.PushMessage{})
+	if err == nil || errors.Is(err, ports.ErrDeviceUnregistered) {
+		t.Errorf("Send(apns) error = %v, want an unconfigured provider error", err)
+	}
+	if router.Supports(ports.PushProviderAPNs) || !router.Supports(ports.PushProviderFCM) {
+		t.Error("Supports() does not match the configured senders")
+	}
+}
@@ -0,0 +1,285 @@
+// Package memory implements the todo repository in memory, to wire the
+// application service without a database: in tests, examples and demos
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// TodoRepository implements the TodoRepository port with a map
+// Like the Postgres repository, it scopes queries to the owner of the
+// context, leaves canaries out of listings and refuses stale updates; todos
+// are copied in and out, so callers never share them with the repository
+// It is safe for concurrent use
+type TodoRepository struct {
+	mu    sync.RWMutex
+	todos map[domain.TodoID]*domain.Todo
+}
+
+// NewTodoRepository creates a new, empty in-memory repository
+func NewTodoRepository() *TodoRepository {
+	return &TodoRepository{
+		todos: make(map[domain.TodoID]*domain.Todo),
+	}
+}
+
+// Save stores a new todo
+func (r *TodoRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.todos[todo.ID()]; ok {
+		return domain.ErrTodoAlreadyExists
+	}
+
+	todo.AssignVersion(1)
+	r.todos[todo.ID()] = clone(todo)
+
+	return nil
+}
+
+// FindByID retrieves a todo by its ID
+func (r *TodoRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todo, ok := r.todos[id]
+	if !ok || !ownedBy(ctx, todo) {
+		return nil, domain.ErrTodoNotFound
+	}
+
+	return clone(todo), nil
+}
+
+// FindAll retrieves todos matching the given filters
+func (r *TodoRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todos := r.matching(ctx, filters)
+	slices.SortFunc(todos, compareBy(filters))
+
+	if filters.Offset != nil {
+		todos = todos[min(max(*filters.Offset, 0), len(todos)):]
+	}
+	if filters.Limit != nil {
+		todos = todos[:min(max(*filters.Limit, 0), len(todos))]
+	}
+
+	found := make([]*domain.Todo, len(todos))
+	for i, todo := range todos {
+		found[i] = clone(todo)
+	}
+
+	return found, nil
+}
+
+// Count returns the number of todos matching the given filters, ignoring
+// Limit and Offset
+func (r *TodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.matching(ctx, filters)), nil
+}
+
+// Update updates an existing todo
+// It returns ErrConcurrentModification when the todo was saved since it was
+// read, and assigns the todo its new version; todos read without a version
+// (zero) are written whatever their stored version
+func (r *TodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.todos[todo.ID()]
+	if !ok || !ownedBy(ctx, stored) {
+		return domain.ErrTodoNotFound
+	}
+	if todo.Version() > 0 && todo.Version() != stored.Version() {
+		return domain.ErrConcurrentModification
+	}
+
+	todo.AssignVersion(stored.Version() + 1)
+	r.todos[todo.ID()] = clone(todo)
+
+	return nil
+}
+
+// Delete removes a todo
+func (r *TodoRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	todo, ok := r.todos[id]
+	if !ok || !ownedBy(ctx, todo) {
+		return domain.ErrTodoNotFound
+	}
+	delete(r.todos, id)
+
+	return nil
+}
+
+// FindDueBetween returns at most limit todos neither completed nor cancelled
+// whose due date is after from and at or before to, earliest due first
+func (r *TodoRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var due []*domain.Todo
+	for _, todo := range r.todos {
+		if todo.DueDate() == nil || todo.Status() == domain.StatusCompleted || todo.Status() == domain.StatusCancelled {
+			continue
+		}
+		if at := todo.DueDate().Time(); at.After(from) && !at.After(to) {
+			due = append(due, todo)
+		}
+	}
+	slices.SortFunc(due, func(a, b *domain.Todo) int {
+		return cmp.Or(a.DueDate().Time().Compare(b.DueDate().Time()), strings.Compare(a.ID().String(), b.ID().String()))
+	})
+
+	due = due[:min(max(limit, 0), len(due))]
+	found := make([]*domain.Todo, len(due))
+	for i, todo := range due {
+		found[i] = clone(todo)
+	}
+
+	return found, nil
+}
+
+// matching returns the stored todos of the owner of ctx matching the
+// status, priority and archival filters, canaries left out
+func (r *TodoRepository) matching(ctx context.Context, filters ports.Filters) []*domain.Todo {
+	var todos []*domain.Todo
+	for _, todo := range r.todos {
+		switch {
+		case todo.IsCanary(), !ownedBy(ctx, todo):
+		case filters.Status != nil && todo.Status() != *filters.Status:
+		case filters.Priority != nil && todo.Priority() != *filters.Priority:
+		case filters.Archived != nil && todo.IsArchived() != *filters.Archived:
+		default:
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+// ownedBy reports whether todo belongs to the owner of ctx; without an
+// owner, only a ctx with system access reaches it
+func ownedBy(ctx context.Context, todo *domain.Todo) bool {
+	if ownerID, ok := ports.OwnerFromContext(ctx); ok {
+		return todo.OwnerID() == ownerID
+	}
+	return ports.HasSystemAccess(ctx)
+}
+
+// priorityRanks ranks priorities by urgency rather than alphabetically
+var priorityRanks = map[domain.Priority]int{
+	domain.PriorityLow:    1,
+	domain.PriorityMedium: 2,
+	domain.PriorityHigh:   3,
+	domain.PriorityUrgent: 4,
+}
+
+// compareBy returns the order of the requested sort, newest first by
+// default, as the Postgres repository orders them: todos without due date
+// come last, and ties are newest first
+func compareBy(filters ports.Filters) func(a, b *domain.Todo) int {
+	newestFirst := func(a, b *domain.Todo) int {
+		return cmp.Or(b.CreatedAt().Compare(a.CreatedAt()), strings.Compare(a.ID().String(), b.ID().String()))
+	}
+
+	var compare func(a, b *domain.Todo) int
+	switch filters.SortBy {
+	case ports.SortByCreatedAt:
+		compare = func(a, b *domain.Todo) int { return a.CreatedAt().Compare(b.CreatedAt()) }
+	case ports.SortByUpdatedAt:
+		compare = func(a, b *domain.Todo) int { return a.UpdatedAt().Compare(b.UpdatedAt()) }
+	case ports.SortByPriority:
+		compare = func(a, b *domain.Todo) int {
+			return cmp.Compare(priorityRanks[a.Priority()], priorityRanks[b.Priority()])
+		}
+	case ports.SortByTitle:
+		compare = func(a, b *domain.Todo) int {
+			return strings.Compare(strings.ToLower(a.Title().String()), strings.ToLower(b.Title().String()))
+		}
+	case ports.SortByDueDate:
+		return func(a, b *domain.Todo) int {
+			switch {
+			case a.DueDate() == nil && b.DueDate() == nil:
+				return newestFirst(a, b)
+			case a.DueDate() == nil:
+				return 1
+			case b.DueDate() == nil:
+				return -1
+			}
+			order := a.DueDate().Time().Compare(b.DueDate().Time())
+			if filters.SortOrder == ports.SortDescending {
+				order = -order
+			}
+			return cmp.Or(order, newestFirst(a, b))
+		}
+	default:
+		return newestFirst
+	}
+
+	return func(a, b *domain.Todo) int {
+		order := compare(a, b)
+		if filters.SortOrder == ports.SortDescending {
+			order = -order
+		}
+		return cmp.Or(order, newestFirst(a, b))
+	}
+}
+
+// clone copies the stored state of todo, without its pending events
+func clone(todo *domain.Todo) *domain.Todo {
+	var dueDate *domain.DueDate
+	if todo.DueDate() != nil {
+		due := *todo.DueDate()
+		dueDate = &due
+	}
+	var completedAt *time.Time
+	if todo.CompletedAt() != nil {
+		at := *todo.CompletedAt()
+		completedAt = &at
+	}
+
+	copied := domain.ReconstituteTodo(
+		todo.ID(),
+		todo.Title(),
+		todo.Description(),
+		todo.Status(),
+		todo.Priority(),
+		dueDate,
+		todo.CreatedAt(),
+		todo.UpdatedAt(),
+		completedAt,
+	)
+	if todo.ShortCode() != 0 {
+		copied.AssignShortCode(todo.ShortCode())
+	}
+	if todo.OwnerID() != "" {
+		copied.AssignOwner(todo.OwnerID())
+	}
+	if todo.IsCanary() {
+		copied.RestoreCanary()
+	}
+	if todo.MergedInto() != nil {
+		copied.RestoreMergedInto(*todo.MergedInto())
+	}
+	if todo.ArchivedAt() != nil {
+		copied.RestoreArchived(*todo.ArchivedAt())
+	}
+	copied.AssignVersion(todo.Version())
+
+	return copied
+}
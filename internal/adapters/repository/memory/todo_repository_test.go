@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	"github.com/pivaldi/mmw/todo/internal/application"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func createTestTodo() *domain.Todo {
+	title, _ := domain.NewTaskTitle("Test Todo")
+	return domain.NewTodo(title, "Test description", domain.PriorityMedium, nil)
+}
+
+func TestTodoRepository_SaveAndFind(t *testing.T) {
+	repo := NewTodoRepository()
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if err := repo.Save(ctx, todo); !errors.Is(err, domain.ErrTodoAlreadyExists) {
+		t.Errorf("Save() again error = %v, want %v", err, domain.ErrTodoAlreadyExists)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found == todo || found.Title() != todo.Title() || found.OwnerID() != "alice" || found.Version() != 1 {
+		t.Errorf("FindByID() = %+v, want a copy of %+v", found, todo)
+	}
+
+	// Changing the todo found does not change the stored one
+	_ = found.Complete()
+	if stored, _ := repo.FindByID(ctx, todo.ID()); stored.Status() != domain.StatusPending {
+		t.Errorf("stored status = %s after changing a todo found, want pending", stored.Status())
+	}
+
+	if _, err := repo.FindByID(ports.ContextWithOwner(ctx, "bob"), todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() by another owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestTodoRepository_AnonymousSeesNothing(t *testing.T) {
+	repo := NewTodoRepository()
+	system := ports.ContextWithSystemAccess(context.Background())
+	anonymous := context.Background()
+
+	owned := createTestTodo()
+	owned.AssignOwner("alice")
+	unowned := createTestTodo()
+	for _, todo := range []*domain.Todo{owned, unowned} {
+		if err := repo.Save(system, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	for _, todo := range []*domain.Todo{owned, unowned} {
+		if _, err := repo.FindByID(anonymous, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("FindByID() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Update(anonymous, todo); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("Update() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Delete(anonymous, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("Delete() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+	}
+	if todos, err := repo.FindAll(anonymous, ports.Filters{}); err != nil || len(todos) != 0 {
+		t.Errorf("FindAll() anonymous = %d todos, %v, want none", len(todos), err)
+	}
+	if count, err := repo.Count(anonymous, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() anonymous = %d, %v, want 0", count, err)
+	}
+	if todos, err := repo.FindAll(system, ports.Filters{}); err != nil || len(todos) != 2 {
+		t.Errorf("FindAll() with system access = %d todos, %v, want 2", len(todos), err)
+	}
+}
+
+func TestTodoRepository_Update(t *testing.T) {
+	repo := NewTodoRepository()
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	stale, _ := repo.FindByID(ctx, todo.ID())
+
+	_ = todo.Complete()
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if todo.Version() != 2 {
+		t.Errorf("Version() = %d, want 2", todo.Version())
+	}
+	if err := repo.Update(ctx, stale); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("Update() of a stale todo error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if err := repo.Update(ctx, todo); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Update() of a deleted todo error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Delete(ctx, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Delete() again error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestTodoRepository_FindAll(t *testing.T) {
+	repo := NewTodoRepository()
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	// A medium todo without due date, then an urgent one due later, a
+	// completed low one due sooner, an archived one and a canary
+	undated := createTestTodo()
+	later, _ := domain.NewDueDate(time.Now().Add(48 * time.Hour))
+	sooner, _ := domain.NewDueDate(time.Now().Add(24 * time.Hour))
+	title, _ := domain.NewTaskTitle("Test Todo")
+	urgent := domain.NewTodo(title, "", domain.PriorityUrgent, &later)
+	low := domain.NewTodo(title, "", domain.PriorityLow, &sooner)
+	_ = low.Complete()
+	archived := createTestTodo()
+	archived.Archive()
+	canary := createTestTodo()
+	canary.RestoreCanary()
+	for _, todo := range []*domain.Todo{undated, urgent, low, archived, canary} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	completed := domain.StatusCompleted
+	urgentPriority := domain.PriorityUrgent
+	notArchived, onlyArchived := false, true
+	one, two := 1, 2
+
+	tests := []struct {
+		name    string
+		filters ports.Filters
+		want    []domain.TodoID
+	}{
+		{"status", ports.Filters{Status: &completed}, []domain.TodoID{low.ID()}},
+		{"priority", ports.Filters{Priority: &urgentPriority}, []domain.TodoID{urgent.ID()}},
+		{"archived", ports.Filters{Archived: &onlyArchived}, []domain.TodoID{archived.ID()}},
+		{"limit", ports.Filters{Archived: &notArchived, Limit: &one, SortBy: ports.SortByDueDate}, []domain.TodoID{low.ID()}},
+		{"offset", ports.Filters{Archived: &notArchived, Offset: &two, SortBy: ports.SortByDueDate}, []domain.TodoID{undated.ID()}},
+		{"due date ascending", ports.Filters{Archived: &notArchived, SortBy: ports.SortByDueDate}, []domain.TodoID{low.ID(), urgent.ID(), undated.ID()}},
+		{"due date descending", ports.Filters{Archived: &notArchived, SortBy: ports.SortByDueDate, SortOrder: ports.SortDescending}, []domain.TodoID{urgent.ID(), low.ID(), undated.ID()}},
+		{"priority descending", ports.Filters{Archived: &notArchived, SortBy: ports.SortByPriority, SortOrder: ports.SortDescending}, []domain.TodoID{urgent.ID(), undated.ID(), low.ID()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, err := repo.FindAll(ctx, tt.filters)
+			if err != nil {
+				t.Fatalf("FindAll() unexpected error: %v", err)
+			}
+			if len(todos) != len(tt.want) {
+				t.Fatalf("FindAll() returned %d todos, want %d", len(todos), len(tt.want))
+			}
+			for i, todo := range todos {
+				if todo.ID() != tt.want[i] {
+					t.Errorf("FindAll() todo %d = %s, want %s", i, todo.ID(), tt.want[i])
+				}
+			}
+		})
+	}
+
+	count, err := repo.Count(ctx, ports.Filters{Limit: &one})
+	if err != nil || count != 4 {
+		t.Errorf("Count() = %d, %v, want 4 ignoring the limit and the canary", count, err)
+	}
+}
+
+func TestTodoRepository_FindDueBetween(t *testing.T) {
+	repo := NewTodoRepository()
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	now := time.Now()
+
+	title, _ := domain.NewTaskTitle("Test Todo")
+	soon, _ := domain.NewDueDate(now.Add(time.Hour))
+	later, _ := domain.NewDueDate(now.Add(48 * time.Hour))
+	due := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	done := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	_ = done.Complete()
+	notYet := domain.NewTodo(title, "", domain.PriorityMedium, &later)
+	for _, todo := range []*domain.Todo{due, done, notYet, createTestTodo()} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	todos, err := repo.FindDueBetween(ctx, now, now.Add(24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDueBetween() unexpected error: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID() != due.ID() {
+		t.Errorf("FindDueBetween() = %v, want only the open todo due within the window", todos)
+	}
+}
+
+// The repository and the recording dispatcher wire the application service
+// without a database
+func TestTodoRepository_WithApplicationService(t *testing.T) {
+	dispatcher := events.NewRecordingDispatcher()
+	service := application.NewTodoApplicationService(NewTodoRepository(), dispatcher)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	created, err := service.CreateTodo(ctx, application.CreateTodoRequest{Title: "Write the docs", Priority: "high"})
+	if err != nil {
+		t.Fatalf("CreateTodo() failed: %v", err)
+	}
+	if _, err := service.CompleteTodo(ctx, created.ID); err != nil {
+		t.Fatalf("CompleteTodo() failed: %v", err)
+	}
+
+	list, err := service.ListTodos(ctx, application.ListFilters{})
+	if err != nil {
+		t.Fatalf("ListTodos() failed: %v", err)
+	}
+	if list.TotalCount != 1 || list.Todos[0].Status != "completed" {
+		t.Errorf("ListTodos() = %+v, want the completed todo", list)
+	}
+
+	want := []string{"TodoCreated", "TodoCompleted"}
+	if got := dispatcher.EventTypes(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("EventTypes() = %v, want %v", got, want)
+	}
+}
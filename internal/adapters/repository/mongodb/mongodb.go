@@ -0,0 +1,86 @@
+// Package mongodb stores todos in MongoDB, one document per todo, for
+// deployments that cannot run Postgres
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// defaultDatabase is the database of the URIs naming none
+const defaultDatabase = "todo"
+
+// todosCollection is the collection of the todo documents
+const todosCollection = "todos"
+
+// indexes are the indexes of the todos collection, serving the owner scope,
+// the status and priority filters ordered by creation, and the due date
+// reminders
+var indexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("todos_created_at"),
+	},
+	{
+		Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("todos_owner_created_at"),
+	},
+	{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("todos_status_created_at"),
+	},
+	{
+		Keys:    bson.D{{Key: "priority", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("todos_priority_created_at"),
+	},
+	{
+		Keys:    bson.D{{Key: "due_date", Value: 1}},
+		Options: options.Index().SetName("todos_due_date"),
+	},
+}
+
+// Connect connects to the deployment at uri, a mongodb:// or mongodb+srv://
+// URI, and creates the indexes of the todos collection in the database the
+// URI names, "todo" when it names none
+// Disconnect the client of the returned database when done
+func Connect(ctx context.Context, uri string) (*mongo.Database, error) {
+	parsed, err := connstring.ParseAndValidate(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing MongoDB URI: %w", err)
+	}
+	name := parsed.Database
+	if name == "" {
+		name = defaultDatabase
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("connecting to MongoDB: %w", err)
+	}
+
+	db := client.Database(name)
+	if err := EnsureIndexes(ctx, db); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// EnsureIndexes creates the missing indexes of the todos collection of db
+// Existing indexes of the same name and keys are left as they are
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	if _, err := db.Collection(todosCollection).Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("creating indexes: %w", err)
+	}
+	return nil
+}
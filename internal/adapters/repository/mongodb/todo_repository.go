@@ -0,0 +1,420 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MongoTodoRepository implements the TodoRepository port using MongoDB
+// Like the Postgres repository, it scopes queries to the owner of the
+// context, leaves canaries out of listings and refuses stale updates; it has
+// no short codes nor merges
+// Times are kept to the millisecond, the precision of BSON dates
+type MongoTodoRepository struct {
+	todos *mongo.Collection
+}
+
+// NewMongoTodoRepository creates a new MongoDB repository on a database
+// returned by Connect
+func NewMongoTodoRepository(db *mongo.Database) *MongoTodoRepository {
+	return &MongoTodoRepository{
+		todos: db.Collection(todosCollection),
+	}
+}
+
+// todoDocument is the stored document of a todo
+// PriorityRank ranks priorities by urgency, for sorts, rather than
+// alphabetically
+type todoDocument struct {
+	ID           string     `bson:"_id"`
+	Title        string     `bson:"title"`
+	Description  string     `bson:"description"`
+	Status       string     `bson:"status"`
+	Priority     string     `bson:"priority"`
+	PriorityRank int        `bson:"priority_rank"`
+	DueDate      *time.Time `bson:"due_date"`
+	CreatedAt    time.Time  `bson:"created_at"`
+	UpdatedAt    time.Time  `bson:"updated_at"`
+	CompletedAt  *time.Time `bson:"completed_at"`
+	OwnerID      string     `bson:"owner_id,omitempty"`
+	Canary       bool       `bson:"canary,omitempty"`
+	ArchivedAt   *time.Time `bson:"archived_at"`
+	Version      int64      `bson:"version"`
+}
+
+// priorityRanks ranks priorities by urgency
+var priorityRanks = map[domain.Priority]int{
+	domain.PriorityLow:    1,
+	domain.PriorityMedium: 2,
+	domain.PriorityHigh:   3,
+	domain.PriorityUrgent: 4,
+}
+
+// owned appends to filter the condition scoping a query to the owner of ctx
+// A ctx without an owner matches no document, unless it has system access
+func owned(ctx context.Context, filter bson.D) bson.D {
+	ownerID, ok := ports.OwnerFromContext(ctx)
+	if !ok {
+		if ports.HasSystemAccess(ctx) {
+			return filter
+		}
+		return append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: bson.A{}}}})
+	}
+
+	return append(filter, bson.E{Key: "owner_id", Value: ownerID})
+}
+
+// Save persists a new todo
+func (r *MongoTodoRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	document := toDocument(todo)
+	document.Version = 1
+
+	if _, err := r.todos.InsertOne(ctx, document); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTodoAlreadyExists
+		}
+		return fmt.Errorf("saving todo: %w", err)
+	}
+
+	todo.AssignVersion(1)
+
+	return nil
+}
+
+// FindByID retrieves a todo by its ID
+func (r *MongoTodoRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	var document todoDocument
+	err := r.todos.FindOne(ctx, owned(ctx, bson.D{{Key: "_id", Value: id.String()}})).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	return fromDocument(document)
+}
+
+// FindAll retrieves todos matching the given filters
+func (r *MongoTodoRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filterDocument(ctx, filters)}}}
+	pipeline = append(pipeline, sortStages(filters)...)
+	if filters.Offset != nil && *filters.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: int64(*filters.Offset)}})
+	}
+	if filters.Limit != nil {
+		if *filters.Limit <= 0 {
+			return nil, nil
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: int64(*filters.Limit)}})
+	}
+
+	aggregateOptions := options.Aggregate()
+	if filters.SortBy == ports.SortByTitle {
+		// Titles sort case-insensitively
+		aggregateOptions.SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	}
+
+	cursor, err := r.todos.Aggregate(ctx, pipeline, aggregateOptions)
+	if err != nil {
+		return nil, fmt.Errorf("querying todos: %w", err)
+	}
+
+	return decodeTodos(ctx, cursor)
+}
+
+// Count returns the number of todos matching the given filters, ignoring
+// Limit and Offset
+func (r *MongoTodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	count, err := r.todos.CountDocuments(ctx, filterDocument(ctx, filters))
+	if err != nil {
+		return 0, fmt.Errorf("counting todos: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// sortFields maps sort fields to the document field they order by
+var sortFields = map[ports.SortField]string{
+	ports.SortByCreatedAt: "created_at",
+	ports.SortByUpdatedAt: "updated_at",
+	ports.SortByDueDate:   "due_date",
+	ports.SortByPriority:  "priority_rank",
+	ports.SortByTitle:     "title",
+}
+
+// sortStages returns the stages ordering the requested sort, newest first by
+// default, as the Postgres repository orders them: todos without due date
+// come last, and ties are newest first
+func sortStages(filters ports.Filters) []bson.D {
+	ties := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+
+	field, ok := sortFields[filters.SortBy]
+	if !ok {
+		return []bson.D{{{Key: "$sort", Value: ties}}}
+	}
+
+	direction := 1
+	if filters.SortOrder == ports.SortDescending {
+		direction = -1
+	}
+	order := append(bson.D{{Key: field, Value: direction}}, ties...)
+	if filters.SortBy != ports.SortByDueDate {
+		return []bson.D{{{Key: "$sort", Value: order}}}
+	}
+
+	// MongoDB sorts missing values first in ascending order
+	return []bson.D{
+		{{Key: "$addFields", Value: bson.D{{Key: "no_due_date", Value: bson.D{{Key: "$eq", Value: bson.A{"$due_date", nil}}}}}}},
+		{{Key: "$sort", Value: append(bson.D{{Key: "no_due_date", Value: 1}}, order...)}},
+	}
+}
+
+// filterDocument returns the query of the status, priority and archival
+// filters and of the owner scope, canaries left out
+func filterDocument(ctx context.Context, filters ports.Filters) bson.D {
+	filter := owned(ctx, bson.D{{Key: "canary", Value: bson.D{{Key: "$ne", Value: true}}}})
+
+	if filters.Status != nil {
+		filter = append(filter, bson.E{Key: "status", Value: filters.Status.String()})
+	}
+
+	if filters.Priority != nil {
+		filter = append(filter, bson.E{Key: "priority", Value: filters.Priority.String()})
+	}
+
+	if filters.Archived != nil {
+		if *filters.Archived {
+			filter = append(filter, bson.E{Key: "archived_at", Value: bson.D{{Key: "$ne", Value: nil}}})
+		} else {
+			filter = append(filter, bson.E{Key: "archived_at", Value: nil})
+		}
+	}
+
+	return filter
+}
+
+// Update updates an existing todo
+// It returns ErrConcurrentModification when the todo was saved since it was
+// read, and assigns the todo its new version; todos read without a version
+// (zero) are written whatever their stored version
+func (r *MongoTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	document := toDocument(todo)
+	filter := owned(ctx, bson.D{{Key: "_id", Value: document.ID}})
+	if todo.Version() > 0 {
+		filter = append(filter, bson.E{Key: "version", Value: todo.Version()})
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "title", Value: document.Title},
+			{Key: "description", Value: document.Description},
+			{Key: "status", Value: document.Status},
+			{Key: "priority", Value: document.Priority},
+			{Key: "priority_rank", Value: document.PriorityRank},
+			{Key: "due_date", Value: document.DueDate},
+			{Key: "updated_at", Value: document.UpdatedAt},
+			{Key: "completed_at", Value: document.CompletedAt},
+			{Key: "archived_at", Value: document.ArchivedAt},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+	updateOptions := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.D{{Key: "version", Value: 1}})
+
+	var updated struct {
+		Version int64 `bson:"version"`
+	}
+	err := r.todos.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.missingOrStale(ctx, todo)
+	}
+	if err != nil {
+		return fmt.Errorf("updating todo: %w", err)
+	}
+	todo.AssignVersion(updated.Version)
+
+	return nil
+}
+
+// missingOrStale tells why an update of todo matched no document:
+// ErrConcurrentModification if the todo still exists, ErrTodoNotFound
+// otherwise
+func (r *MongoTodoRepository) missingOrStale(ctx context.Context, todo *domain.Todo) error {
+	if todo.Version() == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	count, err := r.todos.CountDocuments(ctx, owned(ctx, bson.D{{Key: "_id", Value: todo.ID().String()}}))
+	if err != nil {
+		return fmt.Errorf("checking todo version: %w", err)
+	}
+	if count == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	return domain.ErrConcurrentModification
+}
+
+// Delete removes a todo
+func (r *MongoTodoRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	result, err := r.todos.DeleteOne(ctx, owned(ctx, bson.D{{Key: "_id", Value: id.String()}}))
+	if err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	return nil
+}
+
+// FindDueBetween returns at most limit todos neither completed nor cancelled
+// whose due date is after from and at or before to, earliest due first
+func (r *MongoTodoRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	filter := bson.D{
+		{Key: "due_date", Value: bson.D{{Key: "$gt", Value: from}, {Key: "$lte", Value: to}}},
+		{Key: "status", Value: bson.D{{Key: "$nin", Value: bson.A{
+			domain.StatusCompleted.String(),
+			domain.StatusCancelled.String(),
+		}}}},
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.todos.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("querying due todos: %w", err)
+	}
+
+	return decodeTodos(ctx, cursor)
+}
+
+// decodeTodos reconstitutes the todos of the documents of cursor, closing it
+func decodeTodos(ctx context.Context, cursor *mongo.Cursor) ([]*domain.Todo, error) {
+	defer cursor.Close(ctx)
+
+	var todos []*domain.Todo
+	for cursor.Next(ctx) {
+		var document todoDocument
+		if err := cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("decoding todo: %w", err)
+		}
+		todo, err := fromDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("querying todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// toDocument returns the document storing todo
+func toDocument(todo *domain.Todo) todoDocument {
+	document := todoDocument{
+		ID:           todo.ID().String(),
+		Title:        todo.Title().String(),
+		Description:  todo.Description(),
+		Status:       todo.Status().String(),
+		Priority:     todo.Priority().String(),
+		PriorityRank: priorityRanks[todo.Priority()],
+		CreatedAt:    todo.CreatedAt().UTC(),
+		UpdatedAt:    todo.UpdatedAt().UTC(),
+		CompletedAt:  utc(todo.CompletedAt()),
+		OwnerID:      todo.OwnerID(),
+		Canary:       todo.IsCanary(),
+		ArchivedAt:   utc(todo.ArchivedAt()),
+		Version:      todo.Version(),
+	}
+	if todo.DueDate() != nil {
+		due := todo.DueDate().Time().UTC()
+		document.DueDate = &due
+	}
+
+	return document
+}
+
+// fromDocument reconstitutes the todo stored in document
+func fromDocument(document todoDocument) (*domain.Todo, error) {
+	todoID, err := domain.ParseTodoID(document.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	taskTitle, err := domain.NewTaskTitle(document.Title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title: %w", err)
+	}
+
+	taskStatus, err := domain.NewTaskStatus(document.Status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status: %w", err)
+	}
+
+	taskPriority, err := domain.NewPriority(document.Priority)
+	if err != nil {
+		return nil, fmt.Errorf("invalid priority: %w", err)
+	}
+
+	// Stored due dates may have passed since they were set
+	var dueDate *domain.DueDate
+	if document.DueDate != nil {
+		due := domain.ReconstituteDueDate(*document.DueDate)
+		dueDate = &due
+	}
+
+	todo := domain.ReconstituteTodo(
+		todoID,
+		taskTitle,
+		document.Description,
+		taskStatus,
+		taskPriority,
+		dueDate,
+		document.CreatedAt,
+		document.UpdatedAt,
+		document.CompletedAt,
+	)
+
+	if document.OwnerID != "" {
+		todo.AssignOwner(document.OwnerID)
+	}
+
+	if document.Canary {
+		todo.RestoreCanary()
+	}
+
+	if document.ArchivedAt != nil {
+		todo.RestoreArchived(*document.ArchivedAt)
+	}
+
+	todo.AssignVersion(document.Version)
+
+	return todo, nil
+}
+
+// utc returns an optional time in UTC, as BSON dates are decoded
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	at := t.UTC()
+	return &at
+}
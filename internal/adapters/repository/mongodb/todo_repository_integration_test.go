@@ -0,0 +1,167 @@
+//go:build integration
+// +build integration
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// setupTestDB starts a MongoDB container and connects to its database
+func setupTestDB(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	mongoContainer, err := testcontainers.Run(ctx, "mongo:7",
+		testcontainers.WithExposedPorts("27017/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("27017/tcp").WithStartupTimeout(60*time.Second)),
+	)
+	testcontainers.CleanupContainer(t, mongoContainer)
+	if err != nil {
+		t.Fatalf("failed to start mongo container: %v", err)
+	}
+
+	endpoint, err := mongoContainer.PortEndpoint(ctx, "27017/tcp", "mongodb")
+	if err != nil {
+		t.Fatalf("failed to get endpoint: %v", err)
+	}
+
+	db, err := Connect(ctx, endpoint+"/testdb")
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Client().Disconnect(ctx) })
+
+	return db
+}
+
+func TestMongoTodoRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMongoTodoRepository(db)
+	ctx := ports.ContextWithOwner(context.Background(), "alice")
+
+	// Connecting again keeps the existing indexes
+	if err := EnsureIndexes(ctx, db); err != nil {
+		t.Fatalf("EnsureIndexes() failed: %v", err)
+	}
+
+	t.Run("save and find", func(t *testing.T) {
+		todo := createTestTodo()
+		todo.AssignOwner("alice")
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		if err := repo.Save(ctx, todo); !errors.Is(err, domain.ErrTodoAlreadyExists) {
+			t.Errorf("Save() twice = %v, want ErrTodoAlreadyExists", err)
+		}
+
+		found, err := repo.FindByID(ctx, todo.ID())
+		if err != nil {
+			t.Fatalf("FindByID() failed: %v", err)
+		}
+		if found.Title() != todo.Title() || found.Version() != 1 {
+			t.Errorf("FindByID() = %q version %d, want %q version 1", found.Title(), found.Version(), todo.Title())
+		}
+
+		bob := ports.ContextWithOwner(context.Background(), "bob")
+		if _, err := repo.FindByID(bob, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("FindByID() of another owner = %v, want ErrTodoNotFound", err)
+		}
+	})
+
+	t.Run("update refuses stale versions", func(t *testing.T) {
+		todo := createTestTodo()
+		todo.AssignOwner("alice")
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		stale, _ := repo.FindByID(ctx, todo.ID())
+		if err := todo.UpdatePriority(domain.PriorityHigh); err != nil {
+			t.Fatalf("UpdatePriority() failed: %v", err)
+		}
+		if err := repo.Update(ctx, todo); err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+		if todo.Version() != 2 {
+			t.Errorf("Version() = %d, want 2", todo.Version())
+		}
+		if err := repo.Update(ctx, stale); !errors.Is(err, domain.ErrConcurrentModification) {
+			t.Errorf("Update() of a stale todo = %v, want ErrConcurrentModification", err)
+		}
+	})
+
+	t.Run("find all and count", func(t *testing.T) {
+		urgent := domain.PriorityUrgent
+		todo := createTestTodo()
+		todo.AssignOwner("alice")
+		if err := todo.UpdatePriority(urgent); err != nil {
+			t.Fatalf("UpdatePriority() failed: %v", err)
+		}
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		filters := ports.Filters{Priority: &urgent}
+		todos, err := repo.FindAll(ctx, filters)
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		count, err := repo.Count(ctx, filters)
+		if err != nil {
+			t.Fatalf("Count() failed: %v", err)
+		}
+		if len(todos) != 1 || count != 1 || todos[0].ID() != todo.ID() {
+			t.Errorf("FindAll() = %d todos, Count() = %d, want the urgent todo", len(todos), count)
+		}
+
+		sorted, err := repo.FindAll(ctx, ports.Filters{SortBy: ports.SortByPriority, SortOrder: ports.SortDescending})
+		if err != nil {
+			t.Fatalf("FindAll() sorted failed: %v", err)
+		}
+		if len(sorted) == 0 || sorted[0].Priority() != urgent {
+			t.Errorf("FindAll() by priority = %v, want the urgent todo first", sorted)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		todo := createTestTodo()
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		if err := repo.Delete(ports.ContextWithSystemAccess(context.Background()), todo.ID()); err != nil {
+			t.Fatalf("Delete() failed: %v", err)
+		}
+		if err := repo.Delete(ports.ContextWithSystemAccess(context.Background()), todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("Delete() twice = %v, want ErrTodoNotFound", err)
+		}
+	})
+
+	t.Run("find due between", func(t *testing.T) {
+		title, _ := domain.NewTaskTitle("Due soon")
+		dueDate, _ := domain.NewDueDate(time.Now().Add(time.Hour))
+		todo := domain.NewTodo(title, "", domain.PriorityLow, &dueDate)
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		due, err := repo.FindDueBetween(ctx, time.Now(), time.Now().Add(2*time.Hour), 10)
+		if err != nil {
+			t.Fatalf("FindDueBetween() failed: %v", err)
+		}
+		if len(due) != 1 || due[0].ID() != todo.ID() {
+			t.Errorf("FindDueBetween() = %v, want the todo due soon", due)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func createTestTodo() *domain.Todo {
+	title, _ := domain.NewTaskTitle("Test Todo")
+	return domain.NewTodo(title, "Test description", domain.PriorityMedium, nil)
+}
+
+func TestDocument_RoundTrip(t *testing.T) {
+	title, _ := domain.NewTaskTitle("Test Todo")
+	dueDate, _ := domain.NewDueDate(time.Now().Add(24 * time.Hour))
+	todo := domain.NewTodo(title, "Test description", domain.PriorityUrgent, &dueDate)
+	todo.AssignOwner("alice")
+	todo.RestoreArchived(time.Now())
+	todo.AssignVersion(3)
+
+	document := toDocument(todo)
+	if document.PriorityRank != 4 || document.OwnerID != "alice" || document.Version != 3 {
+		t.Errorf("toDocument() = %+v, want rank 4, owner alice and version 3", document)
+	}
+
+	restored, err := fromDocument(document)
+	if err != nil {
+		t.Fatalf("fromDocument() failed: %v", err)
+	}
+	if restored.ID() != todo.ID() || restored.Title() != todo.Title() || restored.Priority() != todo.Priority() {
+		t.Errorf("fromDocument() = %s %q %s, want %s %q %s",
+			restored.ID(), restored.Title(), restored.Priority(), todo.ID(), todo.Title(), todo.Priority())
+	}
+	if restored.OwnerID() != "alice" || !restored.IsArchived() || restored.Version() != 3 {
+		t.Errorf("fromDocument() lost the owner, archival or version: %s %v %d",
+			restored.OwnerID(), restored.IsArchived(), restored.Version())
+	}
+	if restored.DueDate() == nil || !restored.DueDate().Time().Equal(todo.DueDate().Time()) {
+		t.Errorf("DueDate() = %v, want %v", restored.DueDate(), todo.DueDate())
+	}
+}
+
+func TestFromDocument_Invalid(t *testing.T) {
+	document := toDocument(createTestTodo())
+	document.Status = "unknown"
+
+	if _, err := fromDocument(document); err == nil {
+		t.Error("fromDocument() of an unknown status succeeded")
+	}
+}
+
+func TestFilterDocument(t *testing.T) {
+	status := domain.StatusPending
+	archived := false
+	ctx := ports.ContextWithOwner(context.Background(), "alice")
+
+	filter := filterDocument(ctx, ports.Filters{Status: &status, Archived: &archived}).Map()
+
+	if filter["owner_id"] != "alice" || filter["status"] != status.String() {
+		t.Errorf("filter = %v, want the owner alice and status %s", filter, status)
+	}
+	if value, ok := filter["archived_at"]; !ok || value != nil {
+		t.Errorf("filter archived_at = %v, want null", value)
+	}
+	if _, ok := filter["canary"]; !ok {
+		t.Errorf("filter = %v, want canaries left out", filter)
+	}
+}
+
+func TestSortStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters ports.Filters
+		want    string
+	}{
+		{name: "default", filters: ports.Filters{}, want: "created_at"},
+		{name: "priority", filters: ports.Filters{SortBy: ports.SortByPriority}, want: "priority_rank"},
+		{name: "due date", filters: ports.Filters{SortBy: ports.SortByDueDate}, want: "no_due_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages := sortStages(tt.filters)
+			sort, ok := stages[len(stages)-1].Map()["$sort"].(bson.D)
+			if !ok {
+				t.Fatalf("last stage = %v, want a $sort", stages[len(stages)-1])
+			}
+			if sort[0].Key != tt.want {
+				t.Errorf("sort = %v, want it first by %s", sort, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// activityColumns maps activity kinds to their todo_activity column
+var activityColumns = map[ports.ActivityKind]string{
+	ports.ActivityViewed:   "last_viewed_at",
+	ports.ActivityModified: "last_modified_at",
+}
+
+// PostgresRecentActivityStore implements the RecentActivityStore port using PostgreSQL
+type PostgresRecentActivityStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRecentActivityStore creates a new PostgreSQL recent activity store
+func NewPostgresRecentActivityStore(pool *pgxpool.Pool) *PostgresRecentActivityStore {
+	return &PostgresRecentActivityStore{
+		pool: pool,
+	}
+}
+
+// Touch records that userID had activity of the given kind on a todo
+func (s *PostgresRecentActivityStore) Touch(
+	ctx context.Context,
+	userID string,
+	todoID domain.TodoID,
+	kind ports.ActivityKind,
+	at time.Time,
+) error {
+	column, ok := activityColumns[kind]
+	if !ok {
+		return fmt.Errorf("unknown activity kind %q", kind)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO todo_activity (user_id, todo_id, %[1]s)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, todo_id) DO UPDATE SET %[1]s = EXCLUDED.%[1]s
+	`, column)
+
+	if _, err := s.pool.Exec(ctx, query, userID, todoID.String(), at); err != nil {
+		return fmt.Errorf("recording %s activity: %w", kind, err)
+	}
+
+	return nil
+}
+
+// ListViewsBetween returns the last views of every user that happened
+// within the period, oldest first
+func (s *PostgresRecentActivityStore) ListViewsBetween(ctx context.Context, from, to time.Time, limit int) ([]ports.TodoView, error) {
+	query := `
+		SELECT user_id, todo_id::text, last_viewed_at
+		FROM todo_activity
+		WHERE last_viewed_at >= $1 AND last_viewed_at < $2
+		ORDER BY last_viewed_at, user_id, todo_id
+		LIMIT $3
+	`
+
+	rows, err := s.pool.Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying views: %w", err)
+	}
+	defer rows.Close()
+
+	views, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.TodoView, error) {
+		var view ports.TodoView
+		var id string
+		if err := row.Scan(&view.UserID, &id, &view.ViewedAt); err != nil {
+			return view, err
+		}
+		todoID, err := domain.ParseTodoID(id)
+		view.TodoID = todoID
+		return view, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting views: %w", err)
+	}
+
+	return views, nil
+}
+
+// ListRecent returns the IDs of the todos with the most recent activity of
+// the given kind by userID
+func (s *PostgresRecentActivityStore) ListRecent(
+	ctx context.Context,
+	userID string,
+	kind ports.ActivityKind,
+	limit int,
+) ([]domain.TodoID, error) {
+	column, ok := activityColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown activity kind %q", kind)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT todo_id::text
+		FROM todo_activity
+		WHERE user_id = $1 AND %[1]s IS NOT NULL
+		ORDER BY %[1]s DESC
+		LIMIT $2
+	`, column)
+
+	rows, err := s.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying recent todos: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.TodoID, error) {
+		var id string
+		if err := row.Scan(&id); err != nil {
+			return "", err
+		}
+		return domain.ParseTodoID(id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting recent todos: %w", err)
+	}
+
+	return ids, nil
+}
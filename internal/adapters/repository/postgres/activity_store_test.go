@@ -0,0 +1,113 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresRecentActivityStore_TouchAndList(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresRecentActivityStore(pool)
+	ctx := context.Background()
+
+	older, newer := createTestTodo(), createTestTodo()
+	if err := repo.Save(ctx, older); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if err := repo.Save(ctx, newer); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	now := time.Now()
+	if err := store.Touch(ctx, "alice", older.ID(), ports.ActivityViewed, now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+	if err := store.Touch(ctx, "alice", newer.ID(), ports.ActivityViewed, now); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+	if err := store.Touch(ctx, "alice", older.ID(), ports.ActivityModified, now); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+	if err := store.Touch(ctx, "bob", older.ID(), ports.ActivityViewed, now); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+
+	viewed, err := store.ListRecent(ctx, "alice", ports.ActivityViewed, 10)
+	if err != nil {
+		t.Fatalf("ListRecent() unexpected error: %v", err)
+	}
+	if len(viewed) != 2 || viewed[0] != newer.ID() || viewed[1] != older.ID() {
+		t.Errorf("ListRecent(viewed) = %v, want [%v %v]", viewed, newer.ID(), older.ID())
+	}
+
+	modified, err := store.ListRecent(ctx, "alice", ports.ActivityModified, 10)
+	if err != nil {
+		t.Fatalf("ListRecent() unexpected error: %v", err)
+	}
+	if len(modified) != 1 || modified[0] != older.ID() {
+		t.Errorf("ListRecent(modified) = %v, want [%v]", modified, older.ID())
+	}
+
+	// Deleting a todo removes its activity
+	if err := repo.Delete(ctx, newer.ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	viewed, err = store.ListRecent(ctx, "alice", ports.ActivityViewed, 10)
+	if err != nil {
+		t.Fatalf("ListRecent() unexpected error: %v", err)
+	}
+	if len(viewed) != 1 {
+		t.Errorf("ListRecent(viewed) after delete = %v, want 1 todo", viewed)
+	}
+}
+
+func TestPostgresRecentActivityStore_ListViewsBetween(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresRecentActivityStore(pool)
+	ctx := context.Background()
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	touches := []struct {
+		userID string
+		kind   ports.ActivityKind
+		at     time.Time
+	}{
+		{"alice", ports.ActivityViewed, from.Add(2 * time.Hour)},
+		{"bob", ports.ActivityViewed, from.Add(time.Hour)},
+		{"carol", ports.ActivityViewed, from.Add(-time.Hour)},
+		{"dave", ports.ActivityModified, from.Add(time.Hour)},
+	}
+	for _, touch := range touches {
+		if err := store.Touch(ctx, touch.userID, todo.ID(), touch.kind, touch.at); err != nil {
+			t.Fatalf("Touch() failed: %v", err)
+		}
+	}
+
+	views, err := store.ListViewsBetween(ctx, from, from.Add(24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListViewsBetween() unexpected error: %v", err)
+	}
+	if len(views) != 2 || views[0].UserID != "bob" || views[1].UserID != "alice" {
+		t.Fatalf("ListViewsBetween() = %+v, want the views of bob then alice", views)
+	}
+	if views[0].TodoID != todo.ID() || !views[0].ViewedAt.Equal(from.Add(time.Hour)) {
+		t.Errorf("views[0] = %+v, want %v viewed at %v", views[0], todo.ID(), from.Add(time.Hour))
+	}
+
+	if views, err := store.ListViewsBetween(ctx, from, from.Add(24*time.Hour), 1); err != nil || len(views) != 1 {
+		t.Errorf("ListViewsBetween(limit 1) = %+v, %v, want 1 view", views, err)
+	}
+}
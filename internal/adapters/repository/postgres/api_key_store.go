@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresAPIKeyStore implements the APIKeyStore port using PostgreSQL
+type PostgresAPIKeyStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAPIKeyStore creates a new PostgreSQL API key store
+func NewPostgresAPIKeyStore(pool *pgxpool.Pool) *PostgresAPIKeyStore {
+	return &PostgresAPIKeyStore{
+		pool: pool,
+	}
+}
+
+// apiKeyColumns are the columns read by apiKeyScanner
+const apiKeyColumns = `id::text, name, user_id, scopes, created_at`
+
+// apiKeyScanner scans a row of apiKeyColumns
+func apiKeyScanner(row pgx.CollectableRow) (ports.APIKey, error) {
+	var key ports.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.UserID,
+		&key.Scopes,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+// Create stores a new key
+func (s *PostgresAPIKeyStore) Create(ctx context.Context, key ports.APIKey, keyHash []byte) error {
+	query := `
+		INSERT INTO api_keys (id, name, key_hash, user_id, scopes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		key.ID,
+		key.Name,
+		keyHash,
+		key.UserID,
+		key.Scopes,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting API key: %w", err)
+	}
+
+	return nil
+}
+
+// FindByKeyHash returns the key of a secret hash, or nil if there is none
+func (s *PostgresAPIKeyStore) FindByKeyHash(ctx context.Context, keyHash []byte) (*ports.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	rows, err := s.pool.Query(ctx, query, keyHash)
+	if err != nil {
+		return nil, fmt.Errorf("querying API key: %w", err)
+	}
+	defer rows.Close()
+
+	key, err := pgx.CollectOneRow(rows, apiKeyScanner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("collecting API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// List returns every key, oldest first
+func (s *PostgresAPIKeyStore) List(ctx context.Context) ([]ports.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at, id`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys, err := pgx.CollectRows(rows, apiKeyScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Delete removes a key, reporting whether it existed
+func (s *PostgresAPIKeyStore) Delete(ctx context.Context, id string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM api_keys WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting API key: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresAPIKeyStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresAPIKeyStore(pool)
+	ctx := context.Background()
+
+	key := ports.APIKey{
+		ID:        uuid.New().String(),
+		Name:      "nightly-cleanup",
+		UserID:    "ci-bot",
+		Scopes:    []string{"read", "write"},
+		CreatedAt: time.Now(),
+	}
+	if err := store.Create(ctx, key, []byte("hash")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	found, err := store.FindByKeyHash(ctx, []byte("hash"))
+	if err != nil {
+		t.Fatalf("FindByKeyHash() unexpected error: %v", err)
+	}
+	if found == nil || found.ID != key.ID || found.UserID != "ci-bot" || !slices.Equal(found.Scopes, key.Scopes) {
+		t.Errorf("FindByKeyHash() = %+v, want %+v", found, key)
+	}
+
+	missing, err := store.FindByKeyHash(ctx, []byte("other"))
+	if err != nil || missing != nil {
+		t.Errorf("FindByKeyHash(unknown) = %v, %v, want nil, nil", missing, err)
+	}
+
+	keys, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Name != "nightly-cleanup" {
+		t.Errorf("List() = %+v, want the nightly-cleanup key", keys)
+	}
+
+	deleted, err := store.Delete(ctx, key.ID)
+	if err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v, want true", deleted, err)
+	}
+	deleted, err = store.Delete(ctx, key.ID)
+	if err != nil || deleted {
+		t.Errorf("Delete() again = %v, %v, want false", deleted, err)
+	}
+}
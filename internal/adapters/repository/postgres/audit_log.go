@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresAuditLog implements the AuditLog port using PostgreSQL
+type PostgresAuditLog struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAuditLog creates a new PostgreSQL audit log
+func NewPostgresAuditLog(pool *pgxpool.Pool) *PostgresAuditLog {
+	return &PostgresAuditLog{
+		pool: pool,
+	}
+}
+
+// Record appends an entry to the audit log; an entry without TodoID is
+// service-wide (migration 000021)
+func (l *PostgresAuditLog) Record(ctx context.Context, entry ports.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (action, todo_id, actor, reason, changes, occurred_at)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6)
+	`
+
+	changes := entry.Changes
+	if changes == nil {
+		changes = map[string]string{}
+	}
+
+	_, err := l.pool.Exec(ctx, query,
+		entry.Action,
+		entry.TodoID,
+		entry.Actor,
+		entry.Reason,
+		changes,
+		entry.OccurredAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListEntries returns at most limit entries that occurred at or after from
+// and before to, oldest first
+func (l *PostgresAuditLog) ListEntries(ctx context.Context, from, to time.Time, limit int) ([]ports.AuditEntry, error) {
+	query := `
+		SELECT action, COALESCE(todo_id::text, ''), actor, reason, changes, occurred_at
+		FROM audit_log
+		WHERE occurred_at >= $1 AND occurred_at < $2
+		ORDER BY occurred_at, id
+		LIMIT $3
+	`
+
+	rows, err := l.pool.Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.AuditEntry, error) {
+		var entry ports.AuditEntry
+		err := row.Scan(&entry.Action, &entry.TodoID, &entry.Actor, &entry.Reason, &entry.Changes, &entry.OccurredAt)
+		return entry, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting audit entries: %w", err)
+	}
+
+	return entries, nil
+}
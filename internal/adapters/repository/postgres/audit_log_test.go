@@ -0,0 +1,119 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresAuditLog_Record(t *testing.T) {
+	pool := setupTestDB(t)
+	auditLog := NewPostgresAuditLog(pool)
+	ctx := context.Background()
+
+	todo := createTestTodo()
+	entry := ports.AuditEntry{
+		Action:     "force_update",
+		TodoID:     todo.ID().String(),
+		Actor:      "ops@example.com",
+		Reason:     "Completed by mistake",
+		Changes:    map[string]string{"status": "pending"},
+		OccurredAt: time.Now(),
+	}
+
+	if err := auditLog.Record(ctx, entry); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+
+	var reason, status string
+	err := pool.QueryRow(ctx,
+		`SELECT reason, changes->>'status' FROM audit_log WHERE todo_id = $1`,
+		todo.ID().String(),
+	).Scan(&reason, &status)
+	if err != nil {
+		t.Fatalf("querying audit_log failed: %v", err)
+	}
+
+	if reason != entry.Reason || status != "pending" {
+		t.Errorf("Recorded reason=%q status=%q, want %q and %q", reason, status, entry.Reason, "pending")
+	}
+}
+
+func TestPostgresAuditLog_Record_RejectsEmptyReason(t *testing.T) {
+	pool := setupTestDB(t)
+	auditLog := NewPostgresAuditLog(pool)
+
+	err := auditLog.Record(context.Background(), ports.AuditEntry{
+		Action:     "force_update",
+		TodoID:     createTestTodo().ID().String(),
+		Actor:      "ops@example.com",
+		OccurredAt: time.Now(),
+	})
+
+	if err == nil {
+		t.Error("Record() expected error for an empty reason")
+	}
+}
+
+func TestPostgresAuditLog_ListEntries(t *testing.T) {
+	pool := setupTestDB(t)
+	auditLog := NewPostgresAuditLog(pool)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, action := range []string{"force_update", "purge", "legal_hold"} {
+		err := auditLog.Record(ctx, ports.AuditEntry{
+			Action:     action,
+			TodoID:     createTestTodo().ID().String(),
+			Actor:      "ops@example.com",
+			Reason:     "Reason",
+			Changes:    map[string]string{"status": "pending"},
+			OccurredAt: start.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("Record() unexpected error: %v", err)
+		}
+	}
+
+	entries, err := auditLog.ListEntries(ctx, start.Add(time.Minute), start.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListEntries() unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "purge" || entries[1].Action != "legal_hold" {
+		t.Fatalf("ListEntries() = %+v, want the purge then the legal hold", entries)
+	}
+	if entries[0].Changes["status"] != "pending" {
+		t.Errorf("Changes = %v, want status pending", entries[0].Changes)
+	}
+}
+
+func TestPostgresAuditLog_ServiceWideEntry(t *testing.T) {
+	pool := setupTestDB(t)
+	auditLog := NewPostgresAuditLog(pool)
+	ctx := context.Background()
+
+	occurredAt := time.Now().Truncate(time.Second)
+	err := auditLog.Record(ctx, ports.AuditEntry{
+		Action:     "config_reload",
+		Actor:      "SIGHUP",
+		Reason:     "Configuration reloaded",
+		Changes:    map[string]string{"LOG_LEVEL": "debug"},
+		OccurredAt: occurredAt,
+	})
+	if err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+
+	entries, err := auditLog.ListEntries(ctx, occurredAt, occurredAt.Add(time.Second), 10)
+	if err != nil {
+		t.Fatalf("ListEntries() unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].TodoID != "" || entries[0].Changes["LOG_LEVEL"] != "debug" {
+		t.Errorf("ListEntries() = %+v, want the entry without todo", entries)
+	}
+}
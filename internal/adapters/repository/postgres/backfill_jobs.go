@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// CompletedAtBackfill populates todos.completed_at for completed todos
+// It uses the TodoCompleted event from the outbox when available and falls
+// back to updated_at, which is when the todo last changed status
+type CompletedAtBackfill struct {
+	pool *pgxpool.Pool
+}
+
+// NewCompletedAtBackfill creates a new CompletedAtBackfill job
+func NewCompletedAtBackfill(pool *pgxpool.Pool) *CompletedAtBackfill {
+	return &CompletedAtBackfill{
+		pool: pool,
+	}
+}
+
+// Name returns the job name
+func (j *CompletedAtBackfill) Name() string {
+	return "completed_at"
+}
+
+// Description explains what the job does
+func (j *CompletedAtBackfill) Description() string {
+	return "Populate todos.completed_at from TodoCompleted events (or updated_at) for completed todos"
+}
+
+// RunBatch updates the next batch of completed todos, walking them in ID order
+func (j *CompletedAtBackfill) RunBatch(ctx context.Context, cursor string, batchSize int) (ports.BackfillBatch, error) {
+	if cursor == "" {
+		cursor = "00000000-0000-0000-0000-000000000000"
+	}
+
+	query := `
+		WITH batch AS (
+			SELECT id
+			FROM todos
+			WHERE status = 'completed' AND completed_at IS NULL AND id > $1
+			ORDER BY id
+			LIMIT $2
+		)
+		UPDATE todos t
+		SET completed_at = COALESCE(
+			(SELECT MAX(e.occurred_at) FROM domain_events e
+			 WHERE e.aggregate_id = t.id AND e.event_type = 'TodoCompleted'),
+			t.updated_at
+		)
+		FROM batch
+		WHERE t.id = batch.id
+		RETURNING t.id::text
+	`
+
+	rows, err := j.pool.Query(ctx, query, cursor, batchSize)
+	if err != nil {
+		return ports.BackfillBatch{}, fmt.Errorf("updating completed_at: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return ports.BackfillBatch{}, fmt.Errorf("collecting updated ids: %w", err)
+	}
+
+	batch := ports.BackfillBatch{
+		Cursor:    cursor,
+		Processed: len(ids),
+		Done:      len(ids) < batchSize,
+	}
+
+	// RETURNING does not preserve order, so keep the highest ID as the cursor
+	for _, id := range ids {
+		if id > batch.Cursor {
+			batch.Cursor = id
+		}
+	}
+
+	return batch, nil
+}
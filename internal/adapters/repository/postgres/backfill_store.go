@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresBackfillStore implements the BackfillProgressStore port using PostgreSQL
+type PostgresBackfillStore struct {
+	pool *pgxpool.Pool
+}
+
+// backfillRow represents a backfill_progress row from the database
+type backfillRow struct {
+	Name        string     `db:"name"`
+	Status      string     `db:"status"`
+	Cursor      string     `db:"cursor"`
+	Processed   int64      `db:"processed"`
+	LastError   string     `db:"last_error"`
+	StartedAt   time.Time  `db:"started_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
+
+// NewPostgresBackfillStore creates a new PostgreSQL backfill progress store
+func NewPostgresBackfillStore(pool *pgxpool.Pool) *PostgresBackfillStore {
+	return &PostgresBackfillStore{
+		pool: pool,
+	}
+}
+
+// Get returns the progress of a job, or nil if it never ran
+func (s *PostgresBackfillStore) Get(ctx context.Context, name string) (*ports.BackfillProgress, error) {
+	query := `
+		SELECT name, status, cursor, processed, last_error, started_at, updated_at, completed_at
+		FROM backfill_progress
+		WHERE name = $1
+	`
+
+	rows, err := s.pool.Query(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("querying backfill progress: %w", err)
+	}
+	defer rows.Close()
+
+	progress, err := pgx.CollectOneRow(rows, backfillRowScanner)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("collecting backfill progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// Save creates or replaces the progress of a job
+func (s *PostgresBackfillStore) Save(ctx context.Context, progress *ports.BackfillProgress) error {
+	query := `
+		INSERT INTO backfill_progress (name, status, cursor, processed, last_error, started_at, updated_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (name) DO UPDATE SET
+			status = EXCLUDED.status,
+			cursor = EXCLUDED.cursor,
+			processed = EXCLUDED.processed,
+			last_error = EXCLUDED.last_error,
+			started_at = EXCLUDED.started_at,
+			updated_at = EXCLUDED.updated_at,
+			completed_at = EXCLUDED.completed_at
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		progress.Name,
+		progress.Status,
+		progress.Cursor,
+		progress.Processed,
+		progress.LastError,
+		progress.StartedAt,
+		progress.UpdatedAt,
+		progress.CompletedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saving backfill progress: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the progress of every job that ever ran
+func (s *PostgresBackfillStore) List(ctx context.Context) ([]*ports.BackfillProgress, error) {
+	query := `
+		SELECT name, status, cursor, processed, last_error, started_at, updated_at, completed_at
+		FROM backfill_progress
+		ORDER BY name
+	`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying backfill progress: %w", err)
+	}
+	defer rows.Close()
+
+	progress, err := pgx.CollectRows(rows, backfillRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting backfill progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// backfillRowScanner is a pgx.RowToFunc that scans a backfill_progress row
+func backfillRowScanner(row pgx.CollectableRow) (*ports.BackfillProgress, error) {
+	dbRow, err := pgx.RowToStructByName[backfillRow](row)
+	if err != nil {
+		return nil, fmt.Errorf("scanning row: %w", err)
+	}
+
+	return &ports.BackfillProgress{
+		Name:        dbRow.Name,
+		Status:      dbRow.Status,
+		Cursor:      dbRow.Cursor,
+		Processed:   dbRow.Processed,
+		LastError:   dbRow.LastError,
+		StartedAt:   dbRow.StartedAt,
+		UpdatedAt:   dbRow.UpdatedAt,
+		CompletedAt: dbRow.CompletedAt,
+	}, nil
+}
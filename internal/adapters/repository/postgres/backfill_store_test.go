@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresBackfillStore_SaveAndGet(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresBackfillStore(pool)
+	ctx := context.Background()
+
+	missing, err := store.Get(ctx, "completed_at")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if missing != nil {
+		t.Fatalf("Get() = %+v, want nil for a job that never ran", missing)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	progress := &ports.BackfillProgress{
+		Name:      "completed_at",
+		Status:    "running",
+		Cursor:    "abc",
+		Processed: 42,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := store.Save(ctx, progress); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	progress.Status = "completed"
+	progress.CompletedAt = &now
+	if err := store.Save(ctx, progress); err != nil {
+		t.Fatalf("Save() on conflict unexpected error: %v", err)
+	}
+
+	saved, err := store.Get(ctx, "completed_at")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	if saved.Status != "completed" || saved.Processed != 42 || saved.CompletedAt == nil {
+		t.Errorf("Get() = %+v, want completed progress with 42 processed", saved)
+	}
+}
+
+func TestCompletedAtBackfill_RunBatch(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	job := NewCompletedAtBackfill(pool)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		todo := createTestTodo()
+		if err := todo.Complete(); err != nil {
+			t.Fatalf("Complete() unexpected error: %v", err)
+		}
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() unexpected error: %v", err)
+		}
+	}
+
+	first, err := job.RunBatch(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("RunBatch() unexpected error: %v", err)
+	}
+	if first.Processed != 2 || first.Done {
+		t.Fatalf("First batch = %+v, want 2 processed and not done", first)
+	}
+
+	second, err := job.RunBatch(ctx, first.Cursor, 2)
+	if err != nil {
+		t.Fatalf("RunBatch() unexpected error: %v", err)
+	}
+	if second.Processed != 1 || !second.Done {
+		t.Errorf("Second batch = %+v, want 1 processed and done", second)
+	}
+
+	var remaining int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM todos WHERE completed_at IS NULL`).Scan(&remaining); err != nil {
+		t.Fatalf("counting remaining todos: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("Todos without completed_at = %d, want 0", remaining)
+	}
+}
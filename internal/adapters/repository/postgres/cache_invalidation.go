@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// cacheInvalidationChannel is the channel invalidations are notified on
+const cacheInvalidationChannel = "cache_invalidation"
+
+// DefaultCacheReconnectWait is the delay between two attempts to listen
+// again after the listening connection was lost
+const DefaultCacheReconnectWait = 5 * time.Second
+
+// cacheInvalidationPayload is the JSON payload of a notification
+type cacheInvalidationPayload struct {
+	Cache string `json:"cache"`
+	Key   string `json:"key"`
+}
+
+// PostgresCacheInvalidationBus implements the CacheInvalidationBus port with
+// PostgreSQL LISTEN/NOTIFY, which every instance is already connected to
+// Notifications reach the listening instances as soon as they are sent. An
+// instance that lost its listening connection may have missed some, so it
+// evicts every entry once it listens again
+type PostgresCacheInvalidationBus struct {
+	pool          *pgxpool.Pool
+	logger        *slog.Logger
+	reconnectWait time.Duration
+}
+
+// NewPostgresCacheInvalidationBus creates a new PostgresCacheInvalidationBus
+// listening again every reconnectWait after a failure
+func NewPostgresCacheInvalidationBus(pool *pgxpool.Pool, logger *slog.Logger, reconnectWait time.Duration) *PostgresCacheInvalidationBus {
+	return &PostgresCacheInvalidationBus{pool: pool, logger: logger, reconnectWait: reconnectWait}
+}
+
+// Publish notifies every listening instance of invalidation
+func (b *PostgresCacheInvalidationBus) Publish(ctx context.Context, invalidation ports.CacheInvalidation) error {
+	payload, err := json.Marshal(cacheInvalidationPayload{Cache: invalidation.Cache, Key: invalidation.Key})
+	if err != nil {
+		return fmt.Errorf("encoding cache invalidation: %w", err)
+	}
+
+	if _, err := b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, cacheInvalidationChannel, string(payload)); err != nil {
+		return fmt.Errorf("publishing cache invalidation: %w", err)
+	}
+
+	return nil
+}
+
+// Listen passes the invalidations published by any instance to handle until
+// ctx is done
+// The listening connection is dedicated, so it does not hold a slot of the
+// pool. After it is lost, handle receives an invalidation of every cache
+// once listening again
+func (b *PostgresCacheInvalidationBus) Listen(ctx context.Context, handle func(ports.CacheInvalidation)) {
+	for reconnecting := false; ; reconnecting = true {
+		err := b.listen(ctx, func() {
+			if reconnecting {
+				handle(ports.CacheInvalidation{})
+			}
+		}, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.Warn("cache invalidation listener lost, evicting every entry once reconnected",
+			"error", err, "retry_in", b.reconnectWait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.reconnectWait):
+		}
+	}
+}
+
+// listen listens on a new connection, calling listening once notifications
+// are received, until the connection fails or ctx is done
+func (b *PostgresCacheInvalidationBus) listen(ctx context.Context, listening func(), handle func(ports.CacheInvalidation)) error {
+	conn, err := pgx.ConnectConfig(ctx, b.pool.Config().ConnConfig.Copy())
+	if err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+cacheInvalidationChannel); err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for notifications: %w", err)
+		}
+
+		// An unreadable invalidation may have been for any entry
+		var payload cacheInvalidationPayload
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			b.logger.Error("invalid cache invalidation, evicting every entry", "payload", notification.Payload, "error", err)
+			payload = cacheInvalidationPayload{}
+		}
+		handle(ports.CacheInvalidation{Cache: payload.Cache, Key: payload.Key})
+	}
+}
@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresCacheInvalidationBus_PublishReachesListeners(t *testing.T) {
+	pool := setupTestDB(t)
+	bus := NewPostgresCacheInvalidationBus(pool, slog.New(slog.NewTextHandler(io.Discard, nil)), time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan ports.CacheInvalidation, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bus.Listen(ctx, func(invalidation ports.CacheInvalidation) {
+			received <- invalidation
+		})
+	}()
+
+	// Notifications sent before the listener is ready are lost, so publish
+	// until one arrives
+	want := ports.CacheInvalidation{Cache: "heatmaps", Key: "alice"}
+	deadline := time.After(10 * time.Second)
+	for delivered := false; !delivered; {
+		if err := bus.Publish(ctx, want); err != nil {
+			t.Fatalf("Publish() unexpected error: %v", err)
+		}
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("received %+v, want %+v", got, want)
+			}
+			delivered = true
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no invalidation received")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen() did not return after cancellation")
+	}
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresClientProfileStore implements the ClientProfileStore port using PostgreSQL
+type PostgresClientProfileStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresClientProfileStore creates a new PostgreSQL client profile store
+func NewPostgresClientProfileStore(pool *pgxpool.Pool) *PostgresClientProfileStore {
+	return &PostgresClientProfileStore{
+		pool: pool,
+	}
+}
+
+// Get returns the profile of clientID, or nil if it has none
+func (s *PostgresClientProfileStore) Get(ctx context.Context, clientID string) (*ports.ClientProfile, error) {
+	query := `
+		SELECT page_size, status, priority, sort_by, sort_order, updated_at
+		FROM client_profiles
+		WHERE client_id = $1
+	`
+
+	var (
+		pageSize  *int
+		status    *string
+		priority  *string
+		sortBy    *string
+		sortOrder *string
+		updatedAt time.Time
+	)
+	err := s.pool.QueryRow(ctx, query, clientID).Scan(&pageSize, &status, &priority, &sortBy, &sortOrder, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying client profile: %w", err)
+	}
+
+	profile := &ports.ClientProfile{
+		PageSize:  pageSize,
+		UpdatedAt: updatedAt,
+	}
+
+	if status != nil {
+		taskStatus, err := domain.NewTaskStatus(*status)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status in client profile: %w", err)
+		}
+		profile.Status = &taskStatus
+	}
+
+	if priority != nil {
+		taskPriority, err := domain.NewPriority(*priority)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority in client profile: %w", err)
+		}
+		profile.Priority = &taskPriority
+	}
+
+	// The table constraints keep both set or both NULL
+	if sortBy != nil && sortOrder != nil {
+		field, order := ports.SortField(*sortBy), ports.SortOrder(*sortOrder)
+		profile.SortBy = &field
+		profile.SortOrder = &order
+	}
+
+	return profile, nil
+}
+
+// Save creates or replaces the profile of clientID
+func (s *PostgresClientProfileStore) Save(ctx context.Context, clientID string, profile ports.ClientProfile) error {
+	query := `
+		INSERT INTO client_profiles (client_id, page_size, status, priority, sort_by, sort_order, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (client_id) DO UPDATE SET
+			page_size = EXCLUDED.page_size,
+			status = EXCLUDED.status,
+			priority = EXCLUDED.priority,
+			sort_by = EXCLUDED.sort_by,
+			sort_order = EXCLUDED.sort_order,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	var status, priority, sortBy, sortOrder *string
+	if profile.Status != nil {
+		value := profile.Status.String()
+		status = &value
+	}
+	if profile.Priority != nil {
+		value := profile.Priority.String()
+		priority = &value
+	}
+	if profile.SortBy != nil && profile.SortOrder != nil {
+		field, order := string(*profile.SortBy), string(*profile.SortOrder)
+		sortBy, sortOrder = &field, &order
+	}
+
+	if _, err := s.pool.Exec(ctx, query, clientID, profile.PageSize, status, priority, sortBy, sortOrder, profile.UpdatedAt); err != nil {
+		return fmt.Errorf("saving client profile: %w", err)
+	}
+
+	return nil
+}
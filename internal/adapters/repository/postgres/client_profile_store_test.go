@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresClientProfileStore_SaveAndGet(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresClientProfileStore(pool)
+	ctx := context.Background()
+
+	profile, err := store.Get(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if profile != nil {
+		t.Fatalf("Get() = %+v, want nil before any save", profile)
+	}
+
+	pageSize := 25
+	status := domain.StatusPending
+	sortBy, sortOrder := ports.SortByDueDate, ports.SortAscending
+	if err := store.Save(ctx, "alice", ports.ClientProfile{
+		PageSize:  &pageSize,
+		Status:    &status,
+		SortBy:    &sortBy,
+		SortOrder: &sortOrder,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	profile, err = store.Get(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+
+	if profile.PageSize == nil || *profile.PageSize != 25 {
+		t.Errorf("PageSize = %v, want 25", profile.PageSize)
+	}
+	if profile.Status == nil || *profile.Status != domain.StatusPending {
+		t.Errorf("Status = %v, want %v", profile.Status, domain.StatusPending)
+	}
+	if profile.Priority != nil {
+		t.Errorf("Priority = %v, want nil", profile.Priority)
+	}
+	if profile.SortBy == nil || *profile.SortBy != sortBy || profile.SortOrder == nil || *profile.SortOrder != sortOrder {
+		t.Errorf("SortBy, SortOrder = %v, %v, want %v %v", profile.SortBy, profile.SortOrder, sortBy, sortOrder)
+	}
+
+	// Saving again replaces the whole profile
+	if err := store.Save(ctx, "alice", ports.ClientProfile{UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	profile, err = store.Get(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if profile.PageSize != nil || profile.Status != nil || profile.SortBy != nil {
+		t.Errorf("Get() = %+v, want cleared defaults", profile)
+	}
+}
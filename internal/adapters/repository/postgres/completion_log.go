@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresCompletionLog implements the CompletionLog port using PostgreSQL
+type PostgresCompletionLog struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresCompletionLog creates a new PostgreSQL completion log
+func NewPostgresCompletionLog(pool *pgxpool.Pool) *PostgresCompletionLog {
+	return &PostgresCompletionLog{
+		pool: pool,
+	}
+}
+
+// RecordCompletion records that userID completed a todo at the given time
+func (l *PostgresCompletionLog) RecordCompletion(
+	ctx context.Context,
+	userID string,
+	todoID domain.TodoID,
+	at time.Time,
+) error {
+	query := `
+		INSERT INTO todo_completions (user_id, todo_id, completed_at)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := l.pool.Exec(ctx, query, userID, todoID.String(), at); err != nil {
+		return fmt.Errorf("recording completion: %w", err)
+	}
+
+	return nil
+}
+
+// DailyCompletions counts the completions by userID per UTC day since the
+// given time, filling days without completions with zero
+func (l *PostgresCompletionLog) DailyCompletions(
+	ctx context.Context,
+	userID string,
+	since time.Time,
+) ([]ports.DailyCount, error) {
+	query := `
+		WITH days AS (
+			SELECT generate_series(
+				($2::timestamptz AT TIME ZONE 'UTC')::date,
+				(NOW() AT TIME ZONE 'UTC')::date,
+				interval '1 day'
+			)::date AS day
+		), completions AS (
+			SELECT (completed_at AT TIME ZONE 'UTC')::date AS day, count(*) AS count
+			FROM todo_completions
+			WHERE user_id = $1 AND completed_at >= ($2::timestamptz AT TIME ZONE 'UTC')::date AT TIME ZONE 'UTC'
+			GROUP BY 1
+		)
+		SELECT days.day, COALESCE(completions.count, 0)
+		FROM days
+		LEFT JOIN completions ON completions.day = days.day
+		ORDER BY days.day
+	`
+
+	rows, err := l.pool.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying daily completions: %w", err)
+	}
+	defer rows.Close()
+
+	counts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.DailyCount, error) {
+		var count ports.DailyCount
+		err := row.Scan(&count.Day, &count.Count)
+		return count, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting daily completions: %w", err)
+	}
+
+	return counts, nil
+}
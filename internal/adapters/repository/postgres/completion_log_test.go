@@ -0,0 +1,50 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestPostgresCompletionLog_DailyCompletions(t *testing.T) {
+	pool := setupTestDB(t)
+	log := NewPostgresCompletionLog(pool)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	yesterday := today.Add(-time.Hour)
+
+	for _, at := range []time.Time{now, now, yesterday} {
+		if err := log.RecordCompletion(ctx, "alice", domain.NewTodoID(), at); err != nil {
+			t.Fatalf("RecordCompletion() failed: %v", err)
+		}
+	}
+	if err := log.RecordCompletion(ctx, "bob", domain.NewTodoID(), now); err != nil {
+		t.Fatalf("RecordCompletion() failed: %v", err)
+	}
+
+	counts, err := log.DailyCompletions(ctx, "alice", today.AddDate(0, 0, -2))
+	if err != nil {
+		t.Fatalf("DailyCompletions() unexpected error: %v", err)
+	}
+
+	if len(counts) != 3 {
+		t.Fatalf("DailyCompletions() = %d days, want 3", len(counts))
+	}
+
+	want := []int{0, 1, 2}
+	for i, count := range counts {
+		if count.Count != want[i] {
+			t.Errorf("day %d count = %d, want %d", i, count.Count, want[i])
+		}
+	}
+	if !counts[2].Day.Equal(today) {
+		t.Errorf("last day = %v, want %v", counts[2].Day, today)
+	}
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresDependencyStore implements the DependencyStore port using PostgreSQL
+type PostgresDependencyStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDependencyStore creates a new PostgreSQL dependency store
+func NewPostgresDependencyStore(pool *pgxpool.Pool) *PostgresDependencyStore {
+	return &PostgresDependencyStore{
+		pool: pool,
+	}
+}
+
+// Add stores a dependency; adding an existing one does nothing
+func (s *PostgresDependencyStore) Add(ctx context.Context, dependency ports.Dependency) error {
+	query := `
+		INSERT INTO todo_dependencies (todo_id, blocked_by)
+		VALUES ($1, $2)
+		ON CONFLICT (todo_id, blocked_by) DO NOTHING
+	`
+
+	if _, err := s.pool.Exec(ctx, query, dependency.TodoID.String(), dependency.BlockedBy.String()); err != nil {
+		return fmt.Errorf("adding dependency: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes a dependency, reporting whether there was one
+func (s *PostgresDependencyStore) Remove(ctx context.Context, dependency ports.Dependency) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM todo_dependencies WHERE todo_id = $1 AND blocked_by = $2`,
+		dependency.TodoID.String(), dependency.BlockedBy.String())
+	if err != nil {
+		return false, fmt.Errorf("removing dependency: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Touching returns the dependencies with either end in ids
+func (s *PostgresDependencyStore) Touching(ctx context.Context, ids []domain.TodoID) ([]ports.Dependency, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	query := `
+		SELECT todo_id::text, blocked_by::text
+		FROM todo_dependencies
+		WHERE todo_id = ANY($1::uuid[]) OR blocked_by = ANY($1::uuid[])
+		ORDER BY todo_id, blocked_by
+	`
+
+	rows, err := s.pool.Query(ctx, query, values)
+	if err != nil {
+		return nil, fmt.Errorf("querying dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	dependencies, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.Dependency, error) {
+		var dependency ports.Dependency
+		var todoID, blockedBy string
+		if err := row.Scan(&todoID, &blockedBy); err != nil {
+			return dependency, err
+		}
+
+		var err error
+		if dependency.TodoID, err = domain.ParseTodoID(todoID); err != nil {
+			return dependency, fmt.Errorf("invalid todo ID: %w", err)
+		}
+		if dependency.BlockedBy, err = domain.ParseTodoID(blockedBy); err != nil {
+			return dependency, fmt.Errorf("invalid blocking todo ID: %w", err)
+		}
+		return dependency, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting dependencies: %w", err)
+	}
+
+	return dependencies, nil
+}
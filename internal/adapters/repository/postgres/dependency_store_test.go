@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresDependencyStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresDependencyStore(pool)
+	ctx := context.Background()
+
+	design, build, ship, other := createTestTodo(), createTestTodo(), createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{design, build, ship, other} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	for _, dependency := range []ports.Dependency{
+		{TodoID: build.ID(), BlockedBy: design.ID()},
+		{TodoID: ship.ID(), BlockedBy: build.ID()},
+		// Adding again does nothing
+		{TodoID: ship.ID(), BlockedBy: build.ID()},
+	} {
+		if err := store.Add(ctx, dependency); err != nil {
+			t.Fatalf("Add(%v) failed: %v", dependency, err)
+		}
+	}
+	// The database refuses a todo blocking itself
+	if err := store.Add(ctx, ports.Dependency{TodoID: other.ID(), BlockedBy: other.ID()}); err == nil {
+		t.Error("Add() of a self dependency succeeded")
+	}
+
+	touching, err := store.Touching(ctx, []domain.TodoID{build.ID()})
+	if err != nil {
+		t.Fatalf("Touching() unexpected error: %v", err)
+	}
+	if len(touching) != 2 {
+		t.Errorf("Touching(build) = %+v, want both dependencies", touching)
+	}
+
+	// Deleting a todo deletes its dependencies
+	if err := repo.Delete(ctx, design.ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	touching, err = store.Touching(ctx, []domain.TodoID{build.ID()})
+	if err != nil || len(touching) != 1 || touching[0].BlockedBy != build.ID() {
+		t.Errorf("Touching(build) after delete = %+v, %v, want ship blocked by build", touching, err)
+	}
+
+	removed, err := store.Remove(ctx, ports.Dependency{TodoID: ship.ID(), BlockedBy: build.ID()})
+	if err != nil || !removed {
+		t.Fatalf("Remove() = %v, %v, want true", removed, err)
+	}
+	removed, err = store.Remove(ctx, ports.Dependency{TodoID: ship.ID(), BlockedBy: build.ID()})
+	if err != nil || removed {
+		t.Errorf("Remove() again = %v, %v, want false", removed, err)
+	}
+}
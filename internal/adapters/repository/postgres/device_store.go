@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresDeviceStore implements the DeviceStore port using PostgreSQL
+type PostgresDeviceStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDeviceStore creates a new PostgreSQL device store
+func NewPostgresDeviceStore(pool *pgxpool.Pool) *PostgresDeviceStore {
+	return &PostgresDeviceStore{
+		pool: pool,
+	}
+}
+
+// Save stores a device, or refreshes the user, provider and last_seen_at of
+// the device already holding its token
+// A token registered again by another user moves to this user: the app was
+// signed in with another account
+func (s *PostgresDeviceStore) Save(ctx context.Context, device ports.Device) error {
+	query := `
+		INSERT INTO devices (token, user_id, provider, registered_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (token) DO UPDATE
+		SET user_id = EXCLUDED.user_id, provider = EXCLUDED.provider, last_seen_at = EXCLUDED.last_seen_at
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		device.Token,
+		device.UserID,
+		string(device.Provider),
+		device.RegisteredAt,
+		device.LastSeenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("saving device: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns the devices of a user, most recently seen first
+func (s *PostgresDeviceStore) ListByUser(ctx context.Context, userID string) ([]ports.Device, error) {
+	query := `
+		SELECT token, user_id, provider, registered_at, last_seen_at
+		FROM devices
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC, token
+	`
+
+	rows, err := s.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("querying devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.Device, error) {
+		var device ports.Device
+		var provider string
+		err := row.Scan(&device.Token, &device.UserID, &provider, &device.RegisteredAt, &device.LastSeenAt)
+		device.Provider = ports.PushProvider(provider)
+		return device, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// Delete removes the device of a user holding token, reporting whether it
+// existed
+func (s *PostgresDeviceStore) Delete(ctx context.Context, userID, token string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM devices WHERE user_id = $1 AND token = $2`, userID, token)
+	if err != nil {
+		return false, fmt.Errorf("deleting device: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteSeenBefore removes the devices last seen before a time and returns
+// how many there were
+func (s *PostgresDeviceStore) DeleteSeenBefore(ctx context.Context, before time.Time) (int, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM devices WHERE last_seen_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("deleting stale devices: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresDeviceStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresDeviceStore(pool)
+	ctx := context.Background()
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+
+	for _, device := range []ports.Device{
+		{Token: "phone", UserID: "alice", Provider: ports.PushProviderAPNs, RegisteredAt: lastWeek, LastSeenAt: lastWeek},
+		{Token: "tablet", UserID: "alice", Provider: ports.PushProviderFCM, RegisteredAt: time.Now(), LastSeenAt: time.Now()},
+		{Token: "laptop", UserID: "bob", Provider: ports.PushProviderFCM, RegisteredAt: time.Now(), LastSeenAt: time.Now()},
+	} {
+		if err := store.Save(ctx, device); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	devices, err := store.ListByUser(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListByUser() unexpected error: %v", err)
+	}
+	if len(devices) != 2 || devices[0].Token != "tablet" || devices[1].Provider != ports.PushProviderAPNs {
+		t.Errorf("ListByUser() = %+v, want the tablet then the phone", devices)
+	}
+
+	// Registering a token again moves it to its new user
+	if err := store.Save(ctx, ports.Device{Token: "laptop", UserID: "alice", Provider: ports.PushProviderFCM, RegisteredAt: time.Now(), LastSeenAt: time.Now()}); err != nil {
+		t.Fatalf("Save() again failed: %v", err)
+	}
+	if devices, err := store.ListByUser(ctx, "bob"); err != nil || len(devices) != 0 {
+		t.Errorf("ListByUser(bob) = %+v, %v, want no device left", devices, err)
+	}
+
+	if deleted, err := store.Delete(ctx, "bob", "tablet"); err != nil || deleted {
+		t.Errorf("Delete() of another user's device = %v, %v, want false", deleted, err)
+	}
+	if deleted, err := store.Delete(ctx, "alice", "tablet"); err != nil || !deleted {
+		t.Errorf("Delete() = %v, %v, want true", deleted, err)
+	}
+
+	pruned, err := store.DeleteSeenBefore(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil || pruned != 1 {
+		t.Errorf("DeleteSeenBefore() = %d, %v, want the phone", pruned, err)
+	}
+	if devices, err := store.ListByUser(ctx, "alice"); err != nil || len(devices) != 1 || devices[0].Token != "laptop" {
+		t.Errorf("ListByUser() = %+v, %v, want the laptop left", devices, err)
+	}
+}
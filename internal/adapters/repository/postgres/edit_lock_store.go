@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresEditLockStore implements the EditLockStore port using PostgreSQL
+// Expiry is computed by the database clock, shared by every instance
+type PostgresEditLockStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresEditLockStore creates a new PostgreSQL edit lock store
+func NewPostgresEditLockStore(pool *pgxpool.Pool) *PostgresEditLockStore {
+	return &PostgresEditLockStore{
+		pool: pool,
+	}
+}
+
+// Acquire locks a todo for holderID during ttl, unless another holder has an
+// unexpired lock on it; acquiring a lock already held extends it
+func (s *PostgresEditLockStore) Acquire(ctx context.Context, todoID domain.TodoID, holderID string, ttl time.Duration) (ports.EditLock, error) {
+	query := `
+		INSERT INTO edit_locks (todo_id, holder_id, acquired_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (todo_id) DO UPDATE
+		SET holder_id = EXCLUDED.holder_id,
+			acquired_at = CASE WHEN edit_locks.holder_id = EXCLUDED.holder_id AND edit_locks.expires_at > NOW()
+				THEN edit_locks.acquired_at ELSE EXCLUDED.acquired_at END,
+			expires_at = EXCLUDED.expires_at
+		WHERE edit_locks.holder_id = EXCLUDED.holder_id OR edit_locks.expires_at <= NOW()
+		RETURNING holder_id, acquired_at, expires_at
+	`
+
+	// The lock of another holder may be released or expire between the
+	// two statements, hence a second attempt
+	for attempt := 0; ; attempt++ {
+		lock := ports.EditLock{TodoID: todoID}
+		err := s.pool.QueryRow(ctx, query, todoID.String(), holderID, ttl.Seconds()).
+			Scan(&lock.HolderID, &lock.AcquiredAt, &lock.ExpiresAt)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return ports.EditLock{}, fmt.Errorf("acquiring edit lock: %w", err)
+		}
+
+		held, err := s.Find(ctx, todoID)
+		if err != nil {
+			return ports.EditLock{}, err
+		}
+		switch {
+		case held != nil:
+			return *held, nil
+		case attempt > 0:
+			return ports.EditLock{}, errors.New("acquiring edit lock: lock changed concurrently")
+		}
+	}
+}
+
+// Release removes the lock of holderID on a todo, reporting whether it held
+// one
+func (s *PostgresEditLockStore) Release(ctx context.Context, todoID domain.TodoID, holderID string) (bool, error) {
+	query := `DELETE FROM edit_locks WHERE todo_id = $1 AND holder_id = $2 AND expires_at > NOW()`
+
+	tag, err := s.pool.Exec(ctx, query, todoID.String(), holderID)
+	if err != nil {
+		return false, fmt.Errorf("releasing edit lock: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Find returns the unexpired lock on a todo, nil when there is none
+func (s *PostgresEditLockStore) Find(ctx context.Context, todoID domain.TodoID) (*ports.EditLock, error) {
+	query := `SELECT holder_id, acquired_at, expires_at FROM edit_locks WHERE todo_id = $1 AND expires_at > NOW()`
+
+	lock := ports.EditLock{TodoID: todoID}
+	err := s.pool.QueryRow(ctx, query, todoID.String()).Scan(&lock.HolderID, &lock.AcquiredAt, &lock.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding edit lock: %w", err)
+	}
+
+	return &lock, nil
+}
@@ -0,0 +1,85 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPostgresEditLockStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresEditLockStore(pool)
+	ctx := context.Background()
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if lock, err := store.Find(ctx, todo.ID()); err != nil || lock != nil {
+		t.Fatalf("Find() = %+v, %v, want no lock", lock, err)
+	}
+
+	lock, err := store.Acquire(ctx, todo.ID(), "alice", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() unexpected error: %v", err)
+	}
+	if lock.HolderID != "alice" || !lock.ExpiresAt.After(lock.AcquiredAt) {
+		t.Errorf("Acquire() = %+v, want alice's lock", lock)
+	}
+
+	// Acquiring again extends the lock and keeps when it was acquired
+	refreshed, err := store.Acquire(ctx, todo.ID(), "alice", 2*time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() again unexpected error: %v", err)
+	}
+	if !refreshed.AcquiredAt.Equal(lock.AcquiredAt) || !refreshed.ExpiresAt.After(lock.ExpiresAt) {
+		t.Errorf("Acquire() again = %+v, want %+v extended", refreshed, lock)
+	}
+
+	// Another holder gets alice's lock back
+	other, err := store.Acquire(ctx, todo.ID(), "bob", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() by bob unexpected error: %v", err)
+	}
+	if other.HolderID != "alice" {
+		t.Errorf("Acquire() by bob = %+v, want alice's lock", other)
+	}
+
+	found, err := store.Find(ctx, todo.ID())
+	if err != nil || found == nil || found.HolderID != "alice" {
+		t.Fatalf("Find() = %+v, %v, want alice's lock", found, err)
+	}
+
+	if released, err := store.Release(ctx, todo.ID(), "bob"); err != nil || released {
+		t.Errorf("Release() by bob = %v, %v, want false", released, err)
+	}
+	if released, err := store.Release(ctx, todo.ID(), "alice"); err != nil || !released {
+		t.Fatalf("Release() = %v, %v, want true", released, err)
+	}
+
+	// An expired lock is taken over
+	if _, err := store.Acquire(ctx, todo.ID(), "alice", time.Millisecond); err != nil {
+		t.Fatalf("Acquire() short lock unexpected error: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if lock, err := store.Find(ctx, todo.ID()); err != nil || lock != nil {
+		t.Errorf("Find() after expiry = %+v, %v, want no lock", lock, err)
+	}
+	taken, err := store.Acquire(ctx, todo.ID(), "bob", time.Minute)
+	if err != nil || taken.HolderID != "bob" {
+		t.Errorf("Acquire() after expiry = %+v, %v, want bob's lock", taken, err)
+	}
+
+	// Deleting the todo drops its lock
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if lock, err := store.Find(ctx, todo.ID()); err != nil || lock != nil {
+		t.Errorf("Find() after delete = %+v, %v, want no lock", lock, err)
+	}
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// OutboxMigration is the migration letting the domain_events outbox hold
+// every event type
+const OutboxMigration = 35
+
+// PostgresEventOutbox implements the EventOutbox port with the domain_events
+// table of migration 000002, widened by migration 000035
+// Events are recorded with their fields as snake_case keys, as published,
+// and within the transaction of the context (see PostgresTransactor)
+type PostgresEventOutbox struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresEventOutbox creates a new PostgresEventOutbox
+func NewPostgresEventOutbox(pool *pgxpool.Pool) *PostgresEventOutbox {
+	return &PostgresEventOutbox{pool: pool}
+}
+
+// Record appends events to the outbox, unpublished
+func (o *PostgresEventOutbox) Record(ctx context.Context, domainEvents []domain.DomainEvent) error {
+	db := connOf(ctx, o.pool)
+	for _, event := range domainEvents {
+		data, err := eventData(event)
+		if err != nil {
+			return err
+		}
+
+		var aggregateID *string
+		if id := event.AggregateID(); id != "" {
+			aggregateID = &id
+		}
+
+		query := `INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at) VALUES ($1, $2, $3, $4)`
+		if _, err := db.Exec(ctx, query, aggregateID, event.EventType(), data, event.OccurredAt()); err != nil {
+			return fmt.Errorf("recording %s: %w", event.EventType(), err)
+		}
+	}
+
+	return nil
+}
+
+// eventData returns the fields of event, by their snake_case name
+func eventData(event domain.DomainEvent) ([]byte, error) {
+	fields, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %s: %w", event.EventType(), err)
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(fields, &data); err != nil {
+		return nil, fmt.Errorf("reading %s fields: %w", event.EventType(), err)
+	}
+
+	snakeCased := make(map[string]json.RawMessage, len(data))
+	for name, value := range data {
+		snakeCased[events.SnakeCase(name)] = value
+	}
+	return json.Marshal(snakeCased)
+}
+
+// Relay locks at most limit unpublished events, oldest first, skipping the
+// ones locked by another relay, passes them to publish as
+// ports.RecordedEvent and marks them published once it succeeds
+func (o *PostgresEventOutbox) Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []domain.DomainEvent) error) (int, error) {
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		SELECT id, COALESCE(aggregate_id::text, ''), event_type, event_data, occurred_at
+		FROM domain_events
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("querying unpublished events: %w", err)
+	}
+	recorded, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (outboxRow, error) {
+		var r outboxRow
+		err := row.Scan(&r.id, &r.event.Aggregate, &r.event.Type, &r.event.Data, &r.event.At)
+		return r, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("collecting unpublished events: %w", err)
+	}
+	if len(recorded) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(recorded))
+	pending := make([]domain.DomainEvent, len(recorded))
+	for i, r := range recorded {
+		ids[i] = r.id
+		pending[i] = r.event
+	}
+	if err := publish(ctx, pending); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE domain_events SET published_at = now() WHERE id = ANY($1)`, ids); err != nil {
+		return 0, fmt.Errorf("marking events published: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing relay: %w", err)
+	}
+
+	return len(recorded), nil
+}
+
+// outboxRow is an unpublished event of the domain_events table
+type outboxRow struct {
+	id    int64
+	event ports.RecordedEvent
+}
+
+// Unpublished returns the number of events waiting in the outbox
+func (o *PostgresEventOutbox) Unpublished(ctx context.Context) (int, error) {
+	var count int
+	if err := o.pool.QueryRow(ctx, `SELECT count(*) FROM domain_events WHERE published_at IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting unpublished events: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,117 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestPostgresEventOutbox_RecordWithTheWrite(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresTodoRepository(pool)
+	outbox := NewPostgresEventOutbox(pool)
+	transactor := NewPostgresTransactor(pool)
+	errAbort := errors.New("aborted")
+
+	tests := []struct {
+		name    string
+		abort   bool
+		wantErr error
+		want    int
+	}{
+		{"committed write", false, nil, 1},
+		{"rolled back write", true, errAbort, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := createTestTodo()
+			err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+				if err := repo.Save(ctx, todo); err != nil {
+					return err
+				}
+				if err := outbox.Record(ctx, todo.Events()); err != nil {
+					return err
+				}
+				if tt.abort {
+					return errAbort
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InTransaction() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var todos, events int
+			if err := pool.QueryRow(ctx, `SELECT count(*) FROM todos WHERE id = $1`, todo.ID().String()).Scan(&todos); err != nil {
+				t.Fatalf("counting todos: %v", err)
+			}
+			if err := pool.QueryRow(ctx, `SELECT count(*) FROM domain_events WHERE aggregate_id = $1`, todo.ID().String()).Scan(&events); err != nil {
+				t.Fatalf("counting events: %v", err)
+			}
+			if todos != tt.want || events != tt.want {
+				t.Errorf("%d todos and %d events saved, want %d of each", todos, events, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresEventOutbox_Relay(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := context.Background()
+	outbox := NewPostgresEventOutbox(pool)
+
+	todo := createTestTodo()
+	events := append(todo.Events(), domain.NewTodoUpdatedEvent(todo.ID()))
+	if err := outbox.Record(ctx, events); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+
+	// A refused batch stays in the outbox
+	errBroker := errors.New("broker unavailable")
+	relayed, err := outbox.Relay(ctx, 10, func(ctx context.Context, events []domain.DomainEvent) error {
+		return errBroker
+	})
+	if !errors.Is(err, errBroker) || relayed != 0 {
+		t.Errorf("Relay() = %d, %v, want 0, the broker error", relayed, err)
+	}
+	if unpublished, err := outbox.Unpublished(ctx); err != nil || unpublished != 2 {
+		t.Errorf("Unpublished() = %d, %v, want 2", unpublished, err)
+	}
+
+	var published []domain.DomainEvent
+	publish := func(ctx context.Context, events []domain.DomainEvent) error {
+		published = append(published, events...)
+		return nil
+	}
+	for _, want := range []int{1, 1, 0} {
+		if relayed, err := outbox.Relay(ctx, 1, publish); err != nil || relayed != want {
+			t.Fatalf("Relay() = %d, %v, want %d", relayed, err, want)
+		}
+	}
+
+	if len(published) != 2 || published[0].EventType() != "TodoCreated" || published[1].EventType() != "TodoUpdated" {
+		t.Fatalf("published %v, want TodoCreated then TodoUpdated", published)
+	}
+	if published[0].AggregateID() != todo.ID().String() {
+		t.Errorf("AggregateID() = %q, want %q", published[0].AggregateID(), todo.ID())
+	}
+	data, err := json.Marshal(published[0])
+	if err != nil {
+		t.Fatalf("marshaling the relayed event: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("unmarshaling the relayed event: %v", err)
+	}
+	if fields["title"] != todo.Title().String() {
+		t.Errorf("relayed fields = %v, want the snake_case fields of the event", fields)
+	}
+}
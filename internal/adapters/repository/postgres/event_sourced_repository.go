@@ -0,0 +1,541 @@
+package postgres
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Types of the events recorded besides the ones emitted by the aggregate
+const (
+	eventTodoCreated = "TodoCreated"
+	eventTodoUpdated = "TodoUpdated"
+	eventTodoDeleted = "TodoDeleted"
+)
+
+// uniqueViolation is the SQLSTATE of a duplicate key
+const uniqueViolation = "23505"
+
+// priorityRanks ranks priorities by urgency, as sortColumns does
+var priorityRanks = map[domain.Priority]int{
+	domain.PriorityLow:    1,
+	domain.PriorityMedium: 2,
+	domain.PriorityHigh:   3,
+	domain.PriorityUrgent: 4,
+}
+
+// PostgresEventSourcedTodoRepository implements the TodoRepository port with
+// an event store: every change of a todo is appended to its stream in the
+// todo_events table, and todos are rebuilt by replaying their stream
+// Nothing is overwritten, so the full history of each todo is kept and
+// served by FindAsOf and FindVersion. Listings replay every stream, which
+// suits modest volumes; short codes, merges, search and analytics need the
+// todos table of PostgresTodoRepository
+type PostgresEventSourcedTodoRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresEventSourcedTodoRepository creates a new event-sourced repository
+func NewPostgresEventSourcedTodoRepository(pool *pgxpool.Pool) *PostgresEventSourcedTodoRepository {
+	return &PostgresEventSourcedTodoRepository{
+		pool: pool,
+	}
+}
+
+// storedEvent is an event of the todo_events table
+// Its payload holds the fields the event set, by their todoRow JSON name
+type storedEvent struct {
+	TodoID    string `db:"todo_id"`
+	Version   int    `db:"version"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+}
+
+// todoStream is a todo rebuilt from the events of its stream
+type todoStream struct {
+	id      string
+	version int
+	deleted bool
+	fields  map[string]json.RawMessage
+}
+
+// apply folds event into the stream
+func (s *todoStream) apply(event storedEvent) error {
+	s.version = event.Version
+	if event.EventType == eventTodoDeleted {
+		s.deleted = true
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(event.Payload, &fields); err != nil {
+		return fmt.Errorf("decoding event %d of todo %s: %w", event.Version, event.TodoID, err)
+	}
+	for name, value := range fields {
+		s.fields[name] = value
+	}
+	return nil
+}
+
+// todo reconstitutes the todo as of the events applied
+func (s *todoStream) todo() (*domain.Todo, error) {
+	encoded, err := json.Marshal(s.fields)
+	if err != nil {
+		return nil, fmt.Errorf("encoding todo %s: %w", s.id, err)
+	}
+
+	row := todoRow{ID: s.id}
+	if err := json.Unmarshal(encoded, &row); err != nil {
+		return nil, fmt.Errorf("decoding todo %s: %w", s.id, err)
+	}
+	todo, err := row.todo()
+	if err != nil {
+		return nil, err
+	}
+	todo.AssignVersion(int64(s.version))
+	return todo, nil
+}
+
+// replay rebuilds the streams of events, ordered by todo and version
+func replay(events []storedEvent) ([]*todoStream, error) {
+	var streams []*todoStream
+	for _, event := range events {
+		if len(streams) == 0 || streams[len(streams)-1].id != event.TodoID {
+			streams = append(streams, &todoStream{id: event.TodoID, fields: map[string]json.RawMessage{}})
+		}
+		if err := streams[len(streams)-1].apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return streams, nil
+}
+
+// rowOf returns the fields of todo recorded by the event store
+func rowOf(todo *domain.Todo) todoRow {
+	row := todoRow{
+		Title:       todo.Title().String(),
+		Description: todo.Description(),
+		Status:      todo.Status().String(),
+		Priority:    todo.Priority().String(),
+		CreatedAt:   todo.CreatedAt(),
+		UpdatedAt:   todo.UpdatedAt(),
+		CompletedAt: todo.CompletedAt(),
+		ArchivedAt:  todo.ArchivedAt(),
+	}
+	if todo.DueDate() != nil {
+		t := todo.DueDate().Time()
+		row.DueDate = &t
+	}
+	if todo.MergedInto() != nil {
+		canonicalID := todo.MergedInto().String()
+		row.MergedInto = &canonicalID
+	}
+	if todo.IsCanary() {
+		canary := true
+		row.Canary = &canary
+	}
+	if todo.OwnerID() != "" {
+		ownerID := todo.OwnerID()
+		row.UserID = &ownerID
+	}
+	return row
+}
+
+// fieldsOf returns the recorded fields of todo, by their JSON name
+func fieldsOf(todo *domain.Todo) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(rowOf(todo))
+	if err != nil {
+		return nil, fmt.Errorf("encoding todo: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("encoding todo: %w", err)
+	}
+	return fields, nil
+}
+
+// changedFields returns the fields of todo that differ from the ones of
+// stream
+func changedFields(stream *todoStream, todo *domain.Todo) (map[string]json.RawMessage, error) {
+	fields, err := fieldsOf(todo)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]json.RawMessage{}
+	for name, value := range fields {
+		if previous, ok := stream.fields[name]; !ok || !bytes.Equal(previous, value) {
+			changed[name] = value
+		}
+	}
+	return changed, nil
+}
+
+// eventTypeOf names the change of todo after the events it emitted, or
+// TodoUpdated when it emitted none or several kinds
+func eventTypeOf(todo *domain.Todo) string {
+	eventType := ""
+	for _, event := range todo.Events() {
+		if eventType != "" && event.EventType() != eventType {
+			return eventTodoUpdated
+		}
+		eventType = event.EventType()
+	}
+	if eventType == "" {
+		return eventTodoUpdated
+	}
+	return eventType
+}
+
+// visible reports whether todo belongs to the owner of ctx; without an
+// owner, only a ctx with system access sees it
+func visible(ctx context.Context, todo *domain.Todo) bool {
+	if ownerID, ok := ports.OwnerFromContext(ctx); ok {
+		return todo.OwnerID() == ownerID
+	}
+	return ports.HasSystemAccess(ctx)
+}
+
+// append adds the event of the given version to the stream of id
+// Appending a version twice fails: another writer changed the todo since it
+// was read
+func (r *PostgresEventSourcedTodoRepository) append(ctx context.Context, id domain.TodoID, version int, eventType string, fields map[string]json.RawMessage) error {
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	query := `INSERT INTO todo_events (todo_id, version, event_type, payload) VALUES ($1, $2, $3, $4)`
+	if _, err := connOf(ctx, r.pool).Exec(ctx, query, id.String(), version, eventType, payload); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			if version == 1 {
+				return domain.ErrTodoAlreadyExists
+			}
+			return domain.ErrConcurrentModification
+		}
+		return fmt.Errorf("appending event: %w", err)
+	}
+
+	return nil
+}
+
+// events reads the events matching where, ordered by todo and version
+func (r *PostgresEventSourcedTodoRepository) events(ctx context.Context, where string, args ...interface{}) ([]storedEvent, error) {
+	query := `
+		SELECT todo_id, version, event_type, payload
+		FROM todo_events
+		WHERE ` + where + `
+		ORDER BY todo_id, version
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying todo events: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[storedEvent])
+	if err != nil {
+		return nil, fmt.Errorf("collecting todo events: %w", err)
+	}
+
+	return events, nil
+}
+
+// stream rebuilds the todo id from its events matching where, which
+// receives the ID as $1
+// Returns ErrTodoNotFound when it has no such event, was deleted, or belongs
+// to another owner than the one of ctx
+func (r *PostgresEventSourcedTodoRepository) stream(ctx context.Context, id domain.TodoID, where string, args ...interface{}) (*todoStream, *domain.Todo, error) {
+	events, err := r.events(ctx, "todo_id = $1"+where, append([]interface{}{id.String()}, args...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	streams, err := replay(events)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(streams) == 0 || streams[0].deleted {
+		return nil, nil, domain.ErrTodoNotFound
+	}
+
+	todo, err := streams[0].todo()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !visible(ctx, todo) {
+		return nil, nil, domain.ErrTodoNotFound
+	}
+
+	return streams[0], todo, nil
+}
+
+// live rebuilds every todo not deleted
+func (r *PostgresEventSourcedTodoRepository) live(ctx context.Context) ([]*domain.Todo, error) {
+	events, err := r.events(ctx, "TRUE")
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := replay(events)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]*domain.Todo, 0, len(streams))
+	for _, stream := range streams {
+		if stream.deleted {
+			continue
+		}
+		todo, err := stream.todo()
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, nil
+}
+
+// Save starts the stream of a new todo with a TodoCreated event holding all
+// its fields
+func (r *PostgresEventSourcedTodoRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	fields, err := fieldsOf(todo)
+	if err != nil {
+		return err
+	}
+
+	if err := r.append(ctx, todo.ID(), 1, eventTodoCreated, fields); err != nil {
+		return fmt.Errorf("saving todo: %w", err)
+	}
+	todo.AssignVersion(1)
+
+	return nil
+}
+
+// FindByID rebuilds a todo from its stream
+func (r *PostgresEventSourcedTodoRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	_, todo, err := r.stream(ctx, id, "")
+	return todo, err
+}
+
+// FindAll retrieves todos matching the given filters
+func (r *PostgresEventSourcedTodoRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	todos, err := r.listed(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(todos, todoOrder(filters))
+
+	if filters.Offset != nil {
+		todos = todos[min(max(*filters.Offset, 0), len(todos)):]
+	}
+	if filters.Limit != nil {
+		todos = todos[:min(max(*filters.Limit, 0), len(todos))]
+	}
+
+	return todos, nil
+}
+
+// Count returns the number of todos matching the given filters, ignoring
+// Limit and Offset
+func (r *PostgresEventSourcedTodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	todos, err := r.listed(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(todos), nil
+}
+
+// listed returns the todos of the owner of ctx matching the status, priority
+// and archival filters, leaving canaries out
+func (r *PostgresEventSourcedTodoRepository) listed(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	todos, err := r.live(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(todos, func(todo *domain.Todo) bool {
+		return todo.IsCanary() || !visible(ctx, todo) ||
+			(filters.Status != nil && todo.Status() != *filters.Status) ||
+			(filters.Priority != nil && todo.Priority() != *filters.Priority) ||
+			(filters.Archived != nil && todo.IsArchived() != *filters.Archived)
+	}), nil
+}
+
+// todoOrder returns the comparison of the requested sort, ordering as
+// orderBy does: newest first by default, todos without a due date last in
+// either direction, and ties broken newest first
+func todoOrder(filters ports.Filters) func(a, b *domain.Todo) int {
+	newestFirst := func(a, b *domain.Todo) int {
+		if c := b.CreatedAt().Compare(a.CreatedAt()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID().String(), b.ID().String())
+	}
+
+	var by func(a, b *domain.Todo) int
+	switch filters.SortBy {
+	case ports.SortByCreatedAt:
+		by = func(a, b *domain.Todo) int { return a.CreatedAt().Compare(b.CreatedAt()) }
+	case ports.SortByUpdatedAt:
+		by = func(a, b *domain.Todo) int { return a.UpdatedAt().Compare(b.UpdatedAt()) }
+	case ports.SortByDueDate:
+		by = func(a, b *domain.Todo) int {
+			if a.DueDate() == nil || b.DueDate() == nil {
+				return 0
+			}
+			return a.DueDate().Time().Compare(b.DueDate().Time())
+		}
+	case ports.SortByPriority:
+		by = func(a, b *domain.Todo) int {
+			return cmp.Compare(priorityRanks[a.Priority()], priorityRanks[b.Priority()])
+		}
+	case ports.SortByTitle:
+		by = func(a, b *domain.Todo) int {
+			return strings.Compare(strings.ToLower(a.Title().String()), strings.ToLower(b.Title().String()))
+		}
+	default:
+		return newestFirst
+	}
+
+	return func(a, b *domain.Todo) int {
+		if filters.SortBy == ports.SortByDueDate && (a.DueDate() == nil) != (b.DueDate() == nil) {
+			if a.DueDate() == nil {
+				return 1
+			}
+			return -1
+		}
+
+		c := by(a, b)
+		if filters.SortOrder == ports.SortDescending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return newestFirst(a, b)
+	}
+}
+
+// Update appends the fields of todo changed since its last event, named
+// after the events todo emitted
+// The version of a todo is the length of its stream: a todo read before the
+// last event fails with ErrConcurrentModification
+func (r *PostgresEventSourcedTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	stream, _, err := r.stream(ctx, todo.ID(), "")
+	if err != nil {
+		return err
+	}
+	if todo.Version() > 0 && todo.Version() != int64(stream.version) {
+		return domain.ErrConcurrentModification
+	}
+
+	changed, err := changedFields(stream, todo)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := r.append(ctx, todo.ID(), stream.version+1, eventTypeOf(todo), changed); err != nil {
+		return fmt.Errorf("updating todo: %w", err)
+	}
+	todo.AssignVersion(int64(stream.version + 1))
+
+	return nil
+}
+
+// Delete ends the stream of a todo with a TodoDeleted event; its past events
+// are kept
+func (r *PostgresEventSourcedTodoRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	stream, _, err := r.stream(ctx, id, "")
+	if err != nil {
+		return err
+	}
+
+	if err := r.append(ctx, id, stream.version+1, eventTodoDeleted, map[string]json.RawMessage{}); err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+
+	return nil
+}
+
+// FindAsOf replays the events of a todo recorded up to at
+func (r *PostgresEventSourcedTodoRepository) FindAsOf(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+	_, todo, err := r.stream(ctx, id, " AND occurred_at <= $2", at)
+	return todo, err
+}
+
+// FindVersion replays the events of a todo up to the one saving it with the
+// given UpdatedAt
+func (r *PostgresEventSourcedTodoRepository) FindVersion(ctx context.Context, id domain.TodoID, updatedAt time.Time) (*domain.Todo, error) {
+	events, err := r.events(ctx, "todo_id = $1", id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &todoStream{id: id.String(), fields: map[string]json.RawMessage{}}
+	for _, event := range events {
+		if err := stream.apply(event); err != nil {
+			return nil, err
+		}
+		if stream.deleted {
+			break
+		}
+
+		todo, err := stream.todo()
+		if err != nil {
+			return nil, err
+		}
+		if todo.UpdatedAt().Equal(updatedAt) {
+			if !visible(ctx, todo) {
+				break
+			}
+			return todo, nil
+		}
+	}
+
+	return nil, domain.ErrTodoNotFound
+}
+
+// FindDueBetween returns at most limit todos neither completed nor cancelled
+// whose due date is after from and at or before to, earliest due first
+func (r *PostgresEventSourcedTodoRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	todos, err := r.live(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todos = slices.DeleteFunc(todos, func(todo *domain.Todo) bool {
+		return todo.DueDate() == nil || !todo.DueDate().Time().After(from) || todo.DueDate().Time().After(to) ||
+			todo.Status() == domain.StatusCompleted || todo.Status() == domain.StatusCancelled
+	})
+	slices.SortFunc(todos, func(a, b *domain.Todo) int {
+		if c := a.DueDate().Time().Compare(b.DueDate().Time()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID().String(), b.ID().String())
+	})
+
+	return todos[:min(max(limit, 0), len(todos))], nil
+}
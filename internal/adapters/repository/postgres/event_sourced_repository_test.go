@@ -0,0 +1,340 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresEventSourcedTodoRepository_SaveAndFindByID(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodoWithDueDate()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	if err := repo.Save(ctx, todo); !errors.Is(err, domain.ErrTodoAlreadyExists) {
+		t.Errorf("second Save() error = %v, want %v", err, domain.ErrTodoAlreadyExists)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Title() != todo.Title() || found.Description() != todo.Description() ||
+		found.Status() != todo.Status() || found.Priority() != todo.Priority() {
+		t.Errorf("FindByID() = %+v, want the saved fields of %+v", found, todo)
+	}
+	if found.DueDate() == nil || !found.DueDate().Time().Equal(todo.DueDate().Time()) {
+		t.Errorf("DueDate = %v, want %v", found.DueDate(), todo.DueDate())
+	}
+	if !found.CreatedAt().Equal(todo.CreatedAt()) || !found.UpdatedAt().Equal(todo.UpdatedAt()) {
+		t.Errorf("timestamps = %v, %v, want %v, %v", found.CreatedAt(), found.UpdatedAt(), todo.CreatedAt(), todo.UpdatedAt())
+	}
+
+	if _, err := repo.FindByID(ctx, domain.NewTodoID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() of unknown todo error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_UpdateAppendsChanges(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodoWithDueDate()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	todo.ClearEvents()
+
+	if err := todo.UpdateDueDate(nil); err != nil {
+		t.Fatalf("UpdateDueDate() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	todo.ClearEvents()
+
+	if err := todo.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+
+	// An update without changes appends nothing
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() without changes unexpected error: %v", err)
+	}
+
+	rows, err := pool.Query(ctx, `SELECT event_type FROM todo_events WHERE todo_id = $1 ORDER BY version`, todo.ID().String())
+	if err != nil {
+		t.Fatalf("querying events: %v", err)
+	}
+	var types []string
+	for rows.Next() {
+		var eventType string
+		if err := rows.Scan(&eventType); err != nil {
+			t.Fatalf("scanning event: %v", err)
+		}
+		types = append(types, eventType)
+	}
+	rows.Close()
+	want := []string{"TodoCreated", "TodoUpdated", "TodoCompleted"}
+	if len(types) != len(want) || types[0] != want[0] || types[1] != want[1] || types[2] != want[2] {
+		t.Errorf("event types = %v, want %v", types, want)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Status() != domain.StatusCompleted || found.CompletedAt() == nil || found.DueDate() != nil {
+		t.Errorf("replayed todo status %s, completed at %v, due %v, want completed without due date",
+			found.Status(), found.CompletedAt(), found.DueDate())
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_ConcurrentUpdateFails(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Both writers read version 1; the second one appending version 2 fails
+	first, _, err := repo.stream(ctx, todo.ID(), "")
+	if err != nil {
+		t.Fatalf("stream() failed: %v", err)
+	}
+	title, _ := domain.NewTaskTitle("First writer")
+	if err := todo.UpdateTitle(title); err != nil {
+		t.Fatalf("UpdateTitle() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	changed, err := changedFields(first, todo)
+	if err != nil {
+		t.Fatalf("changedFields() failed: %v", err)
+	}
+	if err := repo.append(ctx, todo.ID(), first.version+1, "TodoUpdated", changed); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("append() of a stale version error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+
+	// A todo read before the last event is refused outright
+	stale, err := first.todo()
+	if err != nil {
+		t.Fatalf("todo() failed: %v", err)
+	}
+	if err := stale.UpdatePriority(domain.PriorityLow); err != nil {
+		t.Fatalf("UpdatePriority() failed: %v", err)
+	}
+	if err := repo.Update(ctx, stale); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("Update() of a stale todo error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+	if todo.Version() != 2 {
+		t.Errorf("Version = %d, want 2", todo.Version())
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_DeleteKeepsHistory(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	saved := todo.UpdatedAt()
+	time.Sleep(10 * time.Millisecond)
+	afterCreate := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	title, _ := domain.NewTaskTitle("Renamed")
+	if err := todo.UpdateTitle(title); err != nil {
+		t.Fatalf("UpdateTitle() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+
+	if _, err := repo.FindByID(ctx, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() after deletion error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Delete(ctx, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("second Delete() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Update(ctx, todo); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Update() after deletion error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	original, err := repo.FindAsOf(ctx, todo.ID(), afterCreate)
+	if err != nil {
+		t.Fatalf("FindAsOf() unexpected error: %v", err)
+	}
+	if original.Title().String() != "Test Todo" {
+		t.Errorf("Title as of creation = %q, want %q", original.Title(), "Test Todo")
+	}
+
+	version, err := repo.FindVersion(ctx, todo.ID(), saved)
+	if err != nil {
+		t.Fatalf("FindVersion() unexpected error: %v", err)
+	}
+	if version.Title().String() != "Test Todo" {
+		t.Errorf("Title of the saved version = %q, want %q", version.Title(), "Test Todo")
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_FindAllAndCount(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	var todos []*domain.Todo
+	for _, spec := range []struct {
+		title    string
+		priority domain.Priority
+	}{
+		{"Bravo", domain.PriorityLow},
+		{"alpha", domain.PriorityUrgent},
+		{"Charlie", domain.PriorityMedium},
+	} {
+		title, _ := domain.NewTaskTitle(spec.title)
+		todo := domain.NewTodo(title, "", spec.priority, nil)
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		todos = append(todos, todo)
+		time.Sleep(5 * time.Millisecond)
+	}
+	canaryTitle, _ := domain.NewTaskTitle("Canary")
+	if err := repo.Save(ctx, domain.NewCanaryTodo(canaryTitle, "")); err != nil {
+		t.Fatalf("Save() of canary failed: %v", err)
+	}
+	if err := repo.Delete(ctx, todos[2].ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	low := domain.PriorityLow
+	one := 1
+	tests := []struct {
+		name    string
+		filters ports.Filters
+		want    []string
+	}{
+		{"newest first by default", ports.Filters{}, []string{"alpha", "Bravo"}},
+		{"by title", ports.Filters{SortBy: ports.SortByTitle}, []string{"alpha", "Bravo"}},
+		{"by priority descending", ports.Filters{SortBy: ports.SortByPriority, SortOrder: ports.SortDescending}, []string{"alpha", "Bravo"}},
+		{"by priority filter", ports.Filters{Priority: &low}, []string{"Bravo"}},
+		{"paginated", ports.Filters{SortBy: ports.SortByTitle, Limit: &one, Offset: &one}, []string{"Bravo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.FindAll(ctx, tt.filters)
+			if err != nil {
+				t.Fatalf("FindAll() unexpected error: %v", err)
+			}
+			var titles []string
+			for _, todo := range found {
+				titles = append(titles, todo.Title().String())
+			}
+			if len(titles) != len(tt.want) {
+				t.Fatalf("FindAll() = %v, want %v", titles, tt.want)
+			}
+			for i := range titles {
+				if titles[i] != tt.want[i] {
+					t.Errorf("FindAll() = %v, want %v", titles, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	count, err := repo.Count(ctx, ports.Filters{Limit: &one})
+	if err != nil {
+		t.Fatalf("Count() unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count() = %d, want 2", count)
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_Owner_ScopesQueries(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	alice := ports.ContextWithOwner(ctx, "alice")
+	bob := ports.ContextWithOwner(ctx, "bob")
+	if found, err := repo.FindByID(alice, todo.ID()); err != nil || found.OwnerID() != "alice" {
+		t.Errorf("FindByID() as owner = %v, %v, want the todo of alice", found, err)
+	}
+	if _, err := repo.FindByID(bob, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() as another owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if _, err := repo.FindByID(context.Background(), todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Delete(bob, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Delete() as another owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if count, err := repo.Count(bob, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() as another owner = %d, %v, want 0", count, err)
+	}
+}
+
+func TestPostgresEventSourcedTodoRepository_FindDueBetween(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresEventSourcedTodoRepository(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	due := createTestTodoWithDueDate()
+	if err := repo.Save(ctx, due); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if err := repo.Save(ctx, createTestTodo()); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	found, err := repo.FindDueBetween(ctx, time.Now(), due.DueDate().Time(), 10)
+	if err != nil {
+		t.Fatalf("FindDueBetween() unexpected error: %v", err)
+	}
+	if len(found) != 1 || found[0].ID() != due.ID() {
+		t.Errorf("FindDueBetween() = %v, want the todo due", found)
+	}
+
+	found, err = repo.FindDueBetween(ctx, due.DueDate().Time(), due.DueDate().Time().Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDueBetween() unexpected error: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("FindDueBetween() after the due date = %v, want none", found)
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresHolidayStore implements the HolidayStore port using PostgreSQL
+type PostgresHolidayStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresHolidayStore creates a new PostgreSQL holiday store
+func NewPostgresHolidayStore(pool *pgxpool.Pool) *PostgresHolidayStore {
+	return &PostgresHolidayStore{
+		pool: pool,
+	}
+}
+
+// Save stores a holiday, replacing the holiday already on its day
+func (s *PostgresHolidayStore) Save(ctx context.Context, holiday ports.Holiday) error {
+	query := `
+		INSERT INTO holidays (day, name)
+		VALUES ($1, $2)
+		ON CONFLICT (day) DO UPDATE SET name = EXCLUDED.name
+	`
+
+	if _, err := s.pool.Exec(ctx, query, holiday.Day, holiday.Name); err != nil {
+		return fmt.Errorf("saving holiday: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the holiday on day, reporting whether there was one
+func (s *PostgresHolidayStore) Delete(ctx context.Context, day time.Time) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM holidays WHERE day = $1`, day)
+	if err != nil {
+		return false, fmt.Errorf("deleting holiday: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// List returns every holiday, earliest first
+func (s *PostgresHolidayStore) List(ctx context.Context) ([]ports.Holiday, error) {
+	rows, err := s.pool.Query(ctx, `SELECT day, name FROM holidays ORDER BY day`)
+	if err != nil {
+		return nil, fmt.Errorf("querying holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.Holiday, error) {
+		var holiday ports.Holiday
+		err := row.Scan(&holiday.Day, &holiday.Name)
+		return holiday, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting holidays: %w", err)
+	}
+
+	return holidays, nil
+}
@@ -0,0 +1,48 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresHolidayStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresHolidayStore(pool)
+	ctx := context.Background()
+
+	christmas := time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC)
+	newYear := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, holiday := range []ports.Holiday{
+		{Day: newYear, Name: "New Year"},
+		{Day: christmas, Name: "Xmas"},
+		// Saving a day again renames its holiday
+		{Day: christmas, Name: "Christmas"},
+	} {
+		if err := store.Save(ctx, holiday); err != nil {
+			t.Fatalf("Save(%v) failed: %v", holiday, err)
+		}
+	}
+
+	holidays, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(holidays) != 2 || !holidays[0].Day.Equal(christmas) || holidays[0].Name != "Christmas" || !holidays[1].Day.Equal(newYear) {
+		t.Errorf("List() = %+v, want Christmas then New Year", holidays)
+	}
+
+	deleted, err := store.Delete(ctx, christmas)
+	if err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v, want true", deleted, err)
+	}
+	deleted, err = store.Delete(ctx, christmas)
+	if err != nil || deleted {
+		t.Errorf("Delete() again = %v, %v, want false", deleted, err)
+	}
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresInboundHookStore implements the InboundHookStore port using PostgreSQL
+type PostgresInboundHookStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresInboundHookStore creates a new PostgreSQL inbound hook store
+func NewPostgresInboundHookStore(pool *pgxpool.Pool) *PostgresInboundHookStore {
+	return &PostgresInboundHookStore{
+		pool: pool,
+	}
+}
+
+// inboundHookColumns are the columns read by inboundHookScanner
+const inboundHookColumns = `id::text, name, title_template, description_template,
+	priority_template, due_date_template, created_at`
+
+// inboundHookScanner scans a row of inboundHookColumns
+func inboundHookScanner(row pgx.CollectableRow) (ports.InboundHook, error) {
+	var hook ports.InboundHook
+	err := row.Scan(
+		&hook.ID,
+		&hook.Name,
+		&hook.Mapping.Title,
+		&hook.Mapping.Description,
+		&hook.Mapping.Priority,
+		&hook.Mapping.DueDate,
+		&hook.CreatedAt,
+	)
+	return hook, err
+}
+
+// Create stores a new hook
+func (s *PostgresInboundHookStore) Create(ctx context.Context, hook ports.InboundHook, tokenHash []byte) error {
+	query := `
+		INSERT INTO inbound_hooks (id, name, token_hash, title_template, description_template,
+			priority_template, due_date_template, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		hook.ID,
+		hook.Name,
+		tokenHash,
+		hook.Mapping.Title,
+		hook.Mapping.Description,
+		hook.Mapping.Priority,
+		hook.Mapping.DueDate,
+		hook.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting inbound hook: %w", err)
+	}
+
+	return nil
+}
+
+// FindByTokenHash returns the hook of a token hash, or nil if there is none
+func (s *PostgresInboundHookStore) FindByTokenHash(ctx context.Context, tokenHash []byte) (*ports.InboundHook, error) {
+	query := `SELECT ` + inboundHookColumns + ` FROM inbound_hooks WHERE token_hash = $1`
+
+	rows, err := s.pool.Query(ctx, query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("querying inbound hook: %w", err)
+	}
+	defer rows.Close()
+
+	hook, err := pgx.CollectOneRow(rows, inboundHookScanner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("collecting inbound hook: %w", err)
+	}
+
+	return &hook, nil
+}
+
+// List returns every hook, oldest first
+func (s *PostgresInboundHookStore) List(ctx context.Context) ([]ports.InboundHook, error) {
+	query := `SELECT ` + inboundHookColumns + ` FROM inbound_hooks ORDER BY created_at, id`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying inbound hooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks, err := pgx.CollectRows(rows, inboundHookScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting inbound hooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// Delete removes a hook, reporting whether it existed
+func (s *PostgresInboundHookStore) Delete(ctx context.Context, id string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM inbound_hooks WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting inbound hook: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresInboundHookStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresInboundHookStore(pool)
+	ctx := context.Background()
+
+	hook := ports.InboundHook{
+		ID:        uuid.New().String(),
+		Name:      "Alertmanager",
+		Mapping:   ports.HookMapping{Title: "{{.alert}}", Priority: "high"},
+		CreatedAt: time.Now(),
+	}
+	if err := store.Create(ctx, hook, []byte("hash")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	found, err := store.FindByTokenHash(ctx, []byte("hash"))
+	if err != nil {
+		t.Fatalf("FindByTokenHash() unexpected error: %v", err)
+	}
+	if found == nil || found.ID != hook.ID || found.Mapping != hook.Mapping {
+		t.Errorf("FindByTokenHash() = %+v, want %+v", found, hook)
+	}
+
+	missing, err := store.FindByTokenHash(ctx, []byte("other"))
+	if err != nil || missing != nil {
+		t.Errorf("FindByTokenHash(unknown) = %v, %v, want nil, nil", missing, err)
+	}
+
+	hooks, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(hooks) != 1 || hooks[0].Name != "Alertmanager" {
+		t.Errorf("List() = %+v, want the Alertmanager hook", hooks)
+	}
+
+	deleted, err := store.Delete(ctx, hook.ID)
+	if err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v, want true", deleted, err)
+	}
+	deleted, err = store.Delete(ctx, hook.ID)
+	if err != nil || deleted {
+		t.Errorf("Delete() again = %v, %v, want false", deleted, err)
+	}
+}
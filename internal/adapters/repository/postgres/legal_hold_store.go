@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresLegalHoldStore implements the LegalHoldStore port using PostgreSQL
+type PostgresLegalHoldStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLegalHoldStore creates a new PostgreSQL legal hold store
+func NewPostgresLegalHoldStore(pool *pgxpool.Pool) *PostgresLegalHoldStore {
+	return &PostgresLegalHoldStore{
+		pool: pool,
+	}
+}
+
+// Place stores a hold, replacing the hold already placed on its todo
+func (s *PostgresLegalHoldStore) Place(ctx context.Context, hold ports.LegalHold) error {
+	query := `
+		INSERT INTO legal_holds (todo_id, reason, placed_by, placed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (todo_id) DO UPDATE
+		SET reason = EXCLUDED.reason, placed_by = EXCLUDED.placed_by, placed_at = EXCLUDED.placed_at
+	`
+
+	_, err := s.pool.Exec(ctx, query, hold.TodoID.String(), hold.Reason, hold.PlacedBy, hold.PlacedAt)
+	if err != nil {
+		return fmt.Errorf("placing legal hold: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the hold on a todo, reporting whether there was one
+func (s *PostgresLegalHoldStore) Release(ctx context.Context, todoID domain.TodoID) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM legal_holds WHERE todo_id = $1`, todoID.String())
+	if err != nil {
+		return false, fmt.Errorf("releasing legal hold: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Held returns which of ids are on hold
+func (s *PostgresLegalHoldStore) Held(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error) {
+	held := make(map[domain.TodoID]bool)
+	if len(ids) == 0 {
+		return held, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT todo_id::text FROM legal_holds WHERE todo_id = ANY($1::uuid[])`, values)
+	if err != nil {
+		return nil, fmt.Errorf("querying legal holds: %w", err)
+	}
+	defer rows.Close()
+
+	heldIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collecting legal holds: %w", err)
+	}
+
+	for _, raw := range heldIDs {
+		id, err := domain.ParseTodoID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid todo ID: %w", err)
+		}
+		held[id] = true
+	}
+
+	return held, nil
+}
+
+// List returns every hold, oldest first
+func (s *PostgresLegalHoldStore) List(ctx context.Context) ([]ports.LegalHold, error) {
+	query := `SELECT todo_id::text, reason, placed_by, placed_at FROM legal_holds ORDER BY placed_at, todo_id`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying legal holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.LegalHold, error) {
+		var hold ports.LegalHold
+		var todoID string
+		if err := row.Scan(&todoID, &hold.Reason, &hold.PlacedBy, &hold.PlacedAt); err != nil {
+			return hold, err
+		}
+
+		id, err := domain.ParseTodoID(todoID)
+		if err != nil {
+			return hold, fmt.Errorf("invalid todo ID: %w", err)
+		}
+		hold.TodoID = id
+		return hold, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting legal holds: %w", err)
+	}
+
+	return holds, nil
+}
@@ -0,0 +1,72 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresLegalHoldStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresLegalHoldStore(pool)
+	ctx := context.Background()
+
+	held := createTestTodo()
+	free := createTestTodo()
+	for _, todo := range []*domain.Todo{held, free} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	hold := ports.LegalHold{TodoID: held.ID(), Reason: "Case 42", PlacedBy: "legal", PlacedAt: time.Now()}
+	if err := store.Place(ctx, hold); err != nil {
+		t.Fatalf("Place() failed: %v", err)
+	}
+	// Placing again replaces the hold
+	hold.Reason = "Case 43"
+	if err := store.Place(ctx, hold); err != nil {
+		t.Fatalf("Place() again failed: %v", err)
+	}
+
+	heldIDs, err := store.Held(ctx, []domain.TodoID{held.ID(), free.ID()})
+	if err != nil {
+		t.Fatalf("Held() unexpected error: %v", err)
+	}
+	if !heldIDs[held.ID()] || heldIDs[free.ID()] {
+		t.Errorf("Held() = %v, want only %v", heldIDs, held.ID())
+	}
+
+	holds, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(holds) != 1 || holds[0].TodoID != held.ID() || holds[0].Reason != "Case 43" {
+		t.Errorf("List() = %+v, want the replaced hold", holds)
+	}
+
+	// The database refuses deleting a held todo
+	if err := repo.Delete(ctx, held.ID()); err == nil {
+		t.Error("Delete() of a held todo succeeded")
+	}
+
+	released, err := store.Release(ctx, held.ID())
+	if err != nil || !released {
+		t.Fatalf("Release() = %v, %v, want true", released, err)
+	}
+	released, err = store.Release(ctx, held.ID())
+	if err != nil || released {
+		t.Errorf("Release() again = %v, %v, want false", released, err)
+	}
+
+	if err := repo.Delete(ctx, held.ID()); err != nil {
+		t.Errorf("Delete() after release unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresMilestoneStore implements the MilestoneStore port using PostgreSQL
+type PostgresMilestoneStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresMilestoneStore creates a new PostgreSQL milestone store
+func NewPostgresMilestoneStore(pool *pgxpool.Pool) *PostgresMilestoneStore {
+	return &PostgresMilestoneStore{
+		pool: pool,
+	}
+}
+
+// milestoneColumns are the columns scanned by scanMilestone
+const milestoneColumns = `id::text, name, target_date, COALESCE(user_id, ''), created_at, completed_at, archived_at`
+
+// owned appends the condition restricting a query to the milestones of the
+// owner of ctx; a ctx without an owner matches none, unless it has system
+// access
+func (s *PostgresMilestoneStore) owned(ctx context.Context, args []interface{}) (string, []interface{}) {
+	ownerID, ok := ports.OwnerFromContext(ctx)
+	if !ok {
+		if ports.HasSystemAccess(ctx) {
+			return "", args
+		}
+		return " AND FALSE", args
+	}
+
+	args = append(args, ownerID)
+	return fmt.Sprintf(" AND user_id = $%d", len(args)), args
+}
+
+// Create stores a new milestone
+func (s *PostgresMilestoneStore) Create(ctx context.Context, milestone ports.Milestone) error {
+	query := `
+		INSERT INTO milestones (id, name, target_date, user_id, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+	`
+
+	_, err := s.pool.Exec(ctx, query, milestone.ID, milestone.Name, milestone.TargetDate, milestone.OwnerID, milestone.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("creating milestone: %w", err)
+	}
+
+	return nil
+}
+
+// Find returns the milestone id, nil if there is none
+func (s *PostgresMilestoneStore) Find(ctx context.Context, id string) (*ports.Milestone, error) {
+	condition, args := s.owned(ctx, []interface{}{id})
+	query := `SELECT ` + milestoneColumns + ` FROM milestones WHERE id = $1` + condition
+
+	milestone, err := scanMilestone(s.pool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying milestone: %w", err)
+	}
+
+	return &milestone, nil
+}
+
+// List returns the milestones, earliest target first
+func (s *PostgresMilestoneStore) List(ctx context.Context) ([]ports.Milestone, error) {
+	condition, args := s.owned(ctx, nil)
+	query := `SELECT ` + milestoneColumns + ` FROM milestones WHERE TRUE` + condition + ` ORDER BY target_date, name, id`
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying milestones: %w", err)
+	}
+	defer rows.Close()
+
+	milestones, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.Milestone, error) {
+		return scanMilestone(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+// Delete removes a milestone, detaching its todos, and reports whether
+// there was one
+func (s *PostgresMilestoneStore) Delete(ctx context.Context, id string) (bool, error) {
+	condition, args := s.owned(ctx, []interface{}{id})
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM milestones WHERE id = $1`+condition, args...)
+	if err != nil {
+		return false, fmt.Errorf("deleting milestone: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Attach attaches a todo to a milestone, moving it from the milestone it
+// was attached to, whose ID is returned, empty if none
+func (s *PostgresMilestoneStore) Attach(ctx context.Context, milestoneID string, todo ports.MilestoneTodo) (string, error) {
+	// The CTE reads the attachment as it was before the upsert
+	query := `
+		WITH previous AS (
+			SELECT milestone_id FROM milestone_todos WHERE todo_id = $2
+		)
+		INSERT INTO milestone_todos (todo_id, milestone_id, estimate_minutes)
+		VALUES ($2, $1, $3)
+		ON CONFLICT (todo_id) DO UPDATE SET
+			milestone_id = EXCLUDED.milestone_id,
+			estimate_minutes = EXCLUDED.estimate_minutes,
+			attached_at = CASE
+				WHEN milestone_todos.milestone_id = EXCLUDED.milestone_id THEN milestone_todos.attached_at
+				ELSE NOW()
+			END
+		RETURNING COALESCE((SELECT milestone_id::text FROM previous), '')
+	`
+
+	var previous string
+	if err := s.pool.QueryRow(ctx, query, milestoneID, todo.TodoID.String(), todo.EstimateMinutes).Scan(&previous); err != nil {
+		return "", fmt.Errorf("attaching todo: %w", err)
+	}
+	if previous == milestoneID {
+		previous = ""
+	}
+
+	return previous, nil
+}
+
+// Detach detaches a todo from a milestone, reporting whether it was
+// attached to it
+func (s *PostgresMilestoneStore) Detach(ctx context.Context, milestoneID string, todoID domain.TodoID) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM milestone_todos WHERE milestone_id = $1 AND todo_id = $2`,
+		milestoneID, todoID.String())
+	if err != nil {
+		return false, fmt.Errorf("detaching todo: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Todos returns the todos attached to a milestone, oldest attachment first
+func (s *PostgresMilestoneStore) Todos(ctx context.Context, milestoneID string) ([]ports.MilestoneTodo, error) {
+	query := `
+		SELECT m.todo_id::text, t.status, m.estimate_minutes
+		FROM milestone_todos m
+		JOIN todos t ON t.id = m.todo_id
+		WHERE m.milestone_id = $1
+		ORDER BY m.attached_at, m.todo_id
+	`
+
+	rows, err := s.pool.Query(ctx, query, milestoneID)
+	if err != nil {
+		return nil, fmt.Errorf("querying milestone todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.MilestoneTodo, error) {
+		var todo ports.MilestoneTodo
+		var todoID, status string
+		if err := row.Scan(&todoID, &status, &todo.EstimateMinutes); err != nil {
+			return todo, err
+		}
+
+		var err error
+		if todo.TodoID, err = domain.ParseTodoID(todoID); err != nil {
+			return todo, fmt.Errorf("invalid todo ID: %w", err)
+		}
+		if todo.Status, err = domain.NewTaskStatus(status); err != nil {
+			return todo, fmt.Errorf("invalid status: %w", err)
+		}
+		return todo, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting milestone todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// Containing returns the IDs of the milestones todos in ids are attached to
+func (s *PostgresMilestoneStore) Containing(ctx context.Context, ids []domain.TodoID) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	query := `
+		SELECT DISTINCT milestone_id::text
+		FROM milestone_todos
+		WHERE todo_id = ANY($1::uuid[])
+		ORDER BY 1
+	`
+
+	rows, err := s.pool.Query(ctx, query, values)
+	if err != nil {
+		return nil, fmt.Errorf("querying milestones of todos: %w", err)
+	}
+	defer rows.Close()
+
+	milestoneIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collecting milestones of todos: %w", err)
+	}
+
+	return milestoneIDs, nil
+}
+
+// SetCompleted sets when a milestone was completed, nil to reopen it, and
+// reports whether this changed it
+func (s *PostgresMilestoneStore) SetCompleted(ctx context.Context, id string, at *time.Time) (bool, error) {
+	// The condition makes concurrent completions of a milestone report a
+	// single change
+	query := `UPDATE milestones SET completed_at = $2 WHERE id = $1 AND completed_at IS NOT NULL`
+	if at != nil {
+		query = `UPDATE milestones SET completed_at = $2 WHERE id = $1 AND completed_at IS NULL`
+	}
+
+	tag, err := s.pool.Exec(ctx, query, id, at)
+	if err != nil {
+		return false, fmt.Errorf("setting milestone completion: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetArchived sets when a milestone was archived, nil to unarchive it, and
+// reports whether there was one
+func (s *PostgresMilestoneStore) SetArchived(ctx context.Context, id string, at *time.Time) (bool, error) {
+	condition, args := s.owned(ctx, []interface{}{id, at})
+
+	tag, err := s.pool.Exec(ctx, `UPDATE milestones SET archived_at = $2 WHERE id = $1`+condition, args...)
+	if err != nil {
+		return false, fmt.Errorf("setting milestone archival: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Frozen returns which of ids are attached to an archived milestone
+func (s *PostgresMilestoneStore) Frozen(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error) {
+	frozen := make(map[domain.TodoID]bool)
+	if len(ids) == 0 {
+		return frozen, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	todoIDs, err := s.frozenTodos(ctx, ` AND t.todo_id = ANY($1::uuid[])`, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range todoIDs {
+		frozen[id] = true
+	}
+
+	return frozen, nil
+}
+
+// FrozenTodos returns every todo attached to an archived milestone
+func (s *PostgresMilestoneStore) FrozenTodos(ctx context.Context) ([]domain.TodoID, error) {
+	return s.frozenTodos(ctx, "")
+}
+
+// frozenTodos returns the todos attached to an archived milestone matching
+// condition
+func (s *PostgresMilestoneStore) frozenTodos(ctx context.Context, condition string, args ...interface{}) ([]domain.TodoID, error) {
+	query := `
+		SELECT t.todo_id::text
+		FROM milestone_todos t
+		JOIN milestones m ON m.id = t.milestone_id
+		WHERE m.archived_at IS NOT NULL` + condition + `
+		ORDER BY 1
+	`
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying frozen todos: %w", err)
+	}
+	defer rows.Close()
+
+	todoIDs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.TodoID, error) {
+		var id string
+		if err := row.Scan(&id); err != nil {
+			return "", err
+		}
+		return domain.ParseTodoID(id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting frozen todos: %w", err)
+	}
+
+	return todoIDs, nil
+}
+
+// scanMilestone scans the milestoneColumns of row
+func scanMilestone(row pgx.Row) (ports.Milestone, error) {
+	var milestone ports.Milestone
+	err := row.Scan(&milestone.ID, &milestone.Name, &milestone.TargetDate, &milestone.OwnerID,
+		&milestone.CreatedAt, &milestone.CompletedAt, &milestone.ArchivedAt)
+	return milestone, err
+}
@@ -0,0 +1,158 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresMilestoneStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresMilestoneStore(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	alice := ports.ContextWithOwner(ctx, "alice")
+
+	design, build := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{design, build} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	beta := ports.Milestone{ID: uuid.New().String(), Name: "Beta", TargetDate: time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), OwnerID: "alice", CreatedAt: time.Now()}
+	launch := ports.Milestone{ID: uuid.New().String(), Name: "Launch", TargetDate: time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), CreatedAt: time.Now()}
+	for _, milestone := range []ports.Milestone{launch, beta} {
+		if err := store.Create(ctx, milestone); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	// Owners only see their milestones
+	milestones, err := store.List(alice)
+	if err != nil || len(milestones) != 1 || milestones[0].ID != beta.ID || !milestones[0].TargetDate.Equal(beta.TargetDate) {
+		t.Errorf("List(alice) = %+v, %v, want Beta", milestones, err)
+	}
+	if found, err := store.Find(alice, launch.ID); err != nil || found != nil {
+		t.Errorf("Find(alice, launch) = %+v, %v, want nil", found, err)
+	}
+
+	if previous, err := store.Attach(ctx, launch.ID, ports.MilestoneTodo{TodoID: design.ID(), EstimateMinutes: 30}); err != nil || previous != "" {
+		t.Fatalf("Attach() = %q, %v, want no previous milestone", previous, err)
+	}
+	// Attaching to another milestone moves the todo
+	if previous, err := store.Attach(ctx, beta.ID, ports.MilestoneTodo{TodoID: design.ID(), EstimateMinutes: 90}); err != nil || previous != launch.ID {
+		t.Fatalf("Attach() = %q, %v, want moved from Launch", previous, err)
+	}
+	if _, err := store.Attach(ctx, beta.ID, ports.MilestoneTodo{TodoID: build.ID()}); err != nil {
+		t.Fatalf("Attach() failed: %v", err)
+	}
+
+	todos, err := store.Todos(ctx, beta.ID)
+	if err != nil || len(todos) != 2 || todos[0].TodoID != design.ID() || todos[0].EstimateMinutes != 90 || todos[0].Status != domain.StatusPending {
+		t.Errorf("Todos(beta) = %+v, %v, want design then build", todos, err)
+	}
+	containing, err := store.Containing(ctx, []domain.TodoID{design.ID(), build.ID()})
+	if err != nil || len(containing) != 1 || containing[0] != beta.ID {
+		t.Errorf("Containing() = %v, %v, want Beta", containing, err)
+	}
+
+	// Completing twice reports a single change
+	now := time.Now()
+	if changed, err := store.SetCompleted(ctx, beta.ID, &now); err != nil || !changed {
+		t.Errorf("SetCompleted() = %v, %v, want true", changed, err)
+	}
+	if changed, err := store.SetCompleted(ctx, beta.ID, &now); err != nil || changed {
+		t.Errorf("SetCompleted() again = %v, %v, want false", changed, err)
+	}
+	if found, err := store.Find(ctx, beta.ID); err != nil || found == nil || found.CompletedAt == nil {
+		t.Errorf("Find(beta) = %+v, %v, want completed", found, err)
+	}
+	if changed, err := store.SetCompleted(ctx, beta.ID, nil); err != nil || !changed {
+		t.Errorf("SetCompleted(nil) = %v, %v, want true", changed, err)
+	}
+
+	if detached, err := store.Detach(ctx, launch.ID, build.ID()); err != nil || detached {
+		t.Errorf("Detach() from another milestone = %v, %v, want false", detached, err)
+	}
+	if detached, err := store.Detach(ctx, beta.ID, build.ID()); err != nil || !detached {
+		t.Errorf("Detach() = %v, %v, want true", detached, err)
+	}
+
+	// Deleting a milestone detaches its todos
+	if deleted, err := store.Delete(alice, launch.ID); err != nil || deleted {
+		t.Errorf("Delete(alice, launch) = %v, %v, want false", deleted, err)
+	}
+	if deleted, err := store.Delete(alice, beta.ID); err != nil || !deleted {
+		t.Errorf("Delete(alice, beta) = %v, %v, want true", deleted, err)
+	}
+	containing, err = store.Containing(ctx, []domain.TodoID{design.ID()})
+	if err != nil || len(containing) != 0 {
+		t.Errorf("Containing() after delete = %v, %v, want none", containing, err)
+	}
+}
+
+func TestPostgresMilestoneStore_Archival(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+	store := NewPostgresMilestoneStore(pool)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	alice := ports.ContextWithOwner(ctx, "alice")
+
+	frozen, free := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{frozen, free} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	beta := ports.Milestone{ID: uuid.New().String(), Name: "Beta", TargetDate: time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), OwnerID: "bob", CreatedAt: time.Now()}
+	if err := store.Create(ctx, beta); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if _, err := store.Attach(ctx, beta.ID, ports.MilestoneTodo{TodoID: frozen.ID()}); err != nil {
+		t.Fatalf("Attach() failed: %v", err)
+	}
+
+	now := time.Now()
+	// Owners only archive their milestones
+	if changed, err := store.SetArchived(alice, beta.ID, &now); err != nil || changed {
+		t.Errorf("SetArchived(alice) = %v, %v, want false", changed, err)
+	}
+	if changed, err := store.SetArchived(ctx, beta.ID, &now); err != nil || !changed {
+		t.Fatalf("SetArchived() = %v, %v, want true", changed, err)
+	}
+	if found, err := store.Find(ctx, beta.ID); err != nil || found == nil || found.ArchivedAt == nil {
+		t.Errorf("Find() = %+v, %v, want archived", found, err)
+	}
+
+	held, err := store.Frozen(ctx, []domain.TodoID{frozen.ID(), free.ID()})
+	if err != nil || !held[frozen.ID()] || held[free.ID()] {
+		t.Errorf("Frozen() = %v, %v, want the attached todo only", held, err)
+	}
+	all, err := store.FrozenTodos(ctx)
+	if err != nil || len(all) != 1 || all[0] != frozen.ID() {
+		t.Errorf("FrozenTodos() = %v, %v, want the attached todo", all, err)
+	}
+
+	// Bulk completion leaves excluded todos open
+	completed, err := repo.CompleteMatching(ctx, ports.CompletionFilter{Exclude: all}, now, 10)
+	if err != nil || len(completed) != 1 || completed[0].ID() != free.ID() {
+		t.Errorf("CompleteMatching() = %v, %v, want the free todo only", completed, err)
+	}
+
+	if changed, err := store.SetArchived(ctx, beta.ID, nil); err != nil || !changed {
+		t.Errorf("SetArchived(nil) = %v, %v, want true", changed, err)
+	}
+	if all, err := store.FrozenTodos(ctx); err != nil || len(all) != 0 {
+		t.Errorf("FrozenTodos() after unarchiving = %v, %v, want none", all, err)
+	}
+}
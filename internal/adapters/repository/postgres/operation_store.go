@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// operationColumns are the columns scanned by scanOperation, the result
+// aside
+const operationColumns = `id::text, kind, owner_id, status, done, total, error, cancel_requested, created_at, updated_at, finished_at`
+
+// PostgresOperationStore implements the OperationStore port using PostgreSQL
+type PostgresOperationStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresOperationStore creates a new PostgreSQL operation store
+func NewPostgresOperationStore(pool *pgxpool.Pool) *PostgresOperationStore {
+	return &PostgresOperationStore{
+		pool: pool,
+	}
+}
+
+// Create stores a new operation
+func (s *PostgresOperationStore) Create(ctx context.Context, operation *ports.Operation) error {
+	query := `
+		INSERT INTO operations (id, kind, owner_id, status, done, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		operation.ID,
+		operation.Kind,
+		operation.OwnerID,
+		operation.Status,
+		operation.Done,
+		operation.Total,
+		operation.CreatedAt,
+		operation.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting operation: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves the progress, status, result and error of an operation and
+// returns whether its cancellation was requested meanwhile
+func (s *PostgresOperationStore) Update(ctx context.Context, operation *ports.Operation) (bool, error) {
+	query := `
+		UPDATE operations
+		SET status = $2, done = $3, total = $4, result = $5, error = $6, updated_at = $7, finished_at = $8
+		WHERE id = $1
+		RETURNING cancel_requested
+	`
+
+	var cancelRequested bool
+	err := s.pool.QueryRow(ctx, query,
+		operation.ID,
+		operation.Status,
+		operation.Done,
+		operation.Total,
+		operation.Result,
+		operation.Error,
+		operation.UpdatedAt,
+		operation.FinishedAt,
+	).Scan(&cancelRequested)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("operation %s not found", operation.ID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("updating operation: %w", err)
+	}
+
+	return cancelRequested, nil
+}
+
+// Get returns an operation with its result, or nil if there is none with
+// this ID
+func (s *PostgresOperationStore) Get(ctx context.Context, id string) (*ports.Operation, error) {
+	query := `SELECT ` + operationColumns + `, result FROM operations WHERE id = $1`
+
+	var result []byte
+	operation, err := scanOperation(s.pool.QueryRow(ctx, query, id), &result)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying operation: %w", err)
+	}
+	operation.Result = result
+
+	return operation, nil
+}
+
+// List returns a page of the operations of an owner, newest first, without
+// their results
+func (s *PostgresOperationStore) List(ctx context.Context, query ports.OperationQuery) ([]*ports.Operation, error) {
+	sql := `
+		SELECT ` + operationColumns + `
+		FROM operations
+		WHERE owner_id = $1
+		  AND ($2 = '' OR (created_at, id) < (SELECT created_at, id FROM operations WHERE id::text = $2))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := s.pool.Query(ctx, sql, query.OwnerID, query.Before, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying operations: %w", err)
+	}
+	defer rows.Close()
+
+	operations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*ports.Operation, error) {
+		return scanOperation(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting operations: %w", err)
+	}
+
+	return operations, nil
+}
+
+// RequestCancel flags a running operation for cancellation, reporting
+// whether it was still running
+func (s *PostgresOperationStore) RequestCancel(ctx context.Context, id string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `UPDATE operations SET cancel_requested = TRUE WHERE id = $1 AND status = 'running'`, id)
+	if err != nil {
+		return false, fmt.Errorf("requesting operation cancellation: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteFinishedBefore removes the operations finished before a time and
+// returns how many there were
+func (s *PostgresOperationStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM operations WHERE finished_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("deleting finished operations: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// scanOperation scans the operationColumns of a row, then extra
+func scanOperation(row pgx.Row, extra ...any) (*ports.Operation, error) {
+	var operation ports.Operation
+	dest := append([]any{
+		&operation.ID,
+		&operation.Kind,
+		&operation.OwnerID,
+		&operation.Status,
+		&operation.Done,
+		&operation.Total,
+		&operation.Error,
+		&operation.CancelRequested,
+		&operation.CreatedAt,
+		&operation.UpdatedAt,
+		&operation.FinishedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &operation, nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresOperationStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresOperationStore(pool)
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	var operations []*ports.Operation
+	for i, owner := range []string{"alice", "alice", "alice", "bob"} {
+		operation := &ports.Operation{
+			ID:        uuid.NewString(),
+			Kind:      "import",
+			OwnerID:   owner,
+			Status:    ports.OperationRunning,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+			UpdatedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := store.Create(ctx, operation); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		operations = append(operations, operation)
+	}
+
+	first := operations[0]
+	if ok, err := store.RequestCancel(ctx, first.ID); err != nil || !ok {
+		t.Errorf("RequestCancel() = %v, %v, want true", ok, err)
+	}
+	first.Done, first.Total = 40, 100
+	first.UpdatedAt = time.Now()
+	cancelRequested, err := store.Update(ctx, first)
+	if err != nil || !cancelRequested {
+		t.Errorf("Update() = %v, %v, want the cancellation requested", cancelRequested, err)
+	}
+
+	finished := start
+	first.Status = ports.OperationCancelled
+	first.Result = []byte(`{"imported":[]}`)
+	first.FinishedAt = &finished
+	if _, err := store.Update(ctx, first); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if ok, err := store.RequestCancel(ctx, first.ID); err != nil || ok {
+		t.Errorf("RequestCancel() of a finished operation = %v, %v, want false", ok, err)
+	}
+
+	got, err := store.Get(ctx, first.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	if got.Status != ports.OperationCancelled || got.Done != 40 || got.Total != 100 || !got.CancelRequested ||
+		string(got.Result) != `{"imported": []}` || got.FinishedAt == nil {
+		t.Errorf("Get() = %+v, result %s", got, got.Result)
+	}
+	if got, err := store.Get(ctx, uuid.NewString()); err != nil || got != nil {
+		t.Errorf("Get() of an unknown ID = %+v, %v, want nil", got, err)
+	}
+
+	// Newest first, two at a time
+	page, err := store.List(ctx, ports.OperationQuery{OwnerID: "alice", Limit: 2})
+	if err != nil || len(page) != 2 || page[0].ID != operations[2].ID || page[1].ID != operations[1].ID {
+		t.Fatalf("List() = %+v, %v, want the last two operations of alice", page, err)
+	}
+	if page[0].Result != nil {
+		t.Errorf("List() returned a result: %s", page[0].Result)
+	}
+	page, err = store.List(ctx, ports.OperationQuery{OwnerID: "alice", Before: page[1].ID, Limit: 2})
+	if err != nil || len(page) != 1 || page[0].ID != first.ID {
+		t.Errorf("List() after the cursor = %+v, %v, want the first operation", page, err)
+	}
+
+	pruned, err := store.DeleteFinishedBefore(ctx, time.Now())
+	if err != nil || pruned != 1 {
+		t.Errorf("DeleteFinishedBefore() = %d, %v, want the finished operation", pruned, err)
+	}
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresOutboxReconciler compares the domain_events outbox of migration
+// 000002 with the todos table
+// Canary todos are never announced, so they are not reported unrecorded
+type PostgresOutboxReconciler struct {
+	pool     *pgxpool.Pool
+	features SchemaFeatures
+}
+
+// NewPostgresOutboxReconciler creates a new PostgresOutboxReconciler
+func NewPostgresOutboxReconciler(pool *pgxpool.Pool, features SchemaFeatures) *PostgresOutboxReconciler {
+	return &PostgresOutboxReconciler{pool: pool, features: features}
+}
+
+// unrecordedCondition selects the todos t without a TodoCreated event
+func (r *PostgresOutboxReconciler) unrecordedCondition() string {
+	condition := `NOT EXISTS (
+		SELECT 1 FROM domain_events e
+		WHERE e.aggregate_id = t.id AND e.event_type = 'TodoCreated'
+	)`
+	if r.features.Canary {
+		condition += " AND NOT t.canary"
+	}
+	return condition
+}
+
+// orphanedCondition selects the aggregates e of todo events whose todo no
+// longer exists and has no TodoDeleted event; the other events of the
+// outbox, such as MilestoneCompleted, have no todo
+const orphanedCondition = `e.event_type LIKE 'Todo%'
+	AND NOT EXISTS (SELECT 1 FROM todos t WHERE t.id = e.aggregate_id)
+	AND NOT EXISTS (
+		SELECT 1 FROM domain_events d
+		WHERE d.aggregate_id = e.aggregate_id AND d.event_type = 'TodoDeleted'
+	)`
+
+// FindDrift returns at most limit unrecorded and limit orphaned todos,
+// oldest first
+func (r *PostgresOutboxReconciler) FindDrift(ctx context.Context, limit int) (ports.OutboxDrift, error) {
+	var drift ports.OutboxDrift
+
+	unrecorded := `
+		SELECT t.id::text
+		FROM todos t
+		WHERE ` + r.unrecordedCondition() + `
+		ORDER BY t.created_at, t.id
+		LIMIT $1
+	`
+	ids, err := r.collectIDs(ctx, unrecorded, limit)
+	if err != nil {
+		return drift, fmt.Errorf("finding unrecorded todos: %w", err)
+	}
+	drift.Unrecorded = ids
+
+	orphaned := `
+		SELECT e.aggregate_id::text
+		FROM domain_events e
+		WHERE ` + orphanedCondition + `
+		GROUP BY e.aggregate_id
+		ORDER BY min(e.occurred_at), e.aggregate_id
+		LIMIT $1
+	`
+	ids, err = r.collectIDs(ctx, orphaned, limit)
+	if err != nil {
+		return drift, fmt.Errorf("finding orphaned events: %w", err)
+	}
+	drift.Orphaned = ids
+
+	return drift, nil
+}
+
+// collectIDs runs query, which selects todo IDs
+func (r *PostgresOutboxReconciler) collectIDs(ctx context.Context, query string, args ...any) ([]domain.TodoID, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.TodoID, error) {
+		var id string
+		if err := row.Scan(&id); err != nil {
+			return "", err
+		}
+		return domain.ParseTodoID(id)
+	})
+}
+
+// RepairDrift records, in one transaction, a TodoCreated event dated from
+// the creation of each unrecorded todo and a TodoDeleted event for each
+// orphaned one, both marked as reconciled and left unpublished
+// The drift conditions are checked again, so concurrent repairs record
+// each event once
+func (r *PostgresOutboxReconciler) RepairDrift(ctx context.Context, drift ports.OutboxDrift) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Serializes repairs, so two instances do not both record an event
+	if _, err := tx.Exec(ctx, "LOCK TABLE domain_events IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return 0, fmt.Errorf("locking outbox: %w", err)
+	}
+
+	created := `
+		INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at)
+		SELECT t.id, 'TodoCreated',
+			jsonb_build_object('title', t.title, 'description', t.description,
+				'priority', t.priority, 'due_date', t.due_date, 'reconciled', true),
+			t.created_at
+		FROM todos t
+		WHERE t.id = ANY($1::uuid[]) AND ` + r.unrecordedCondition()
+	createdTag, err := tx.Exec(ctx, created, idStrings(drift.Unrecorded))
+	if err != nil {
+		return 0, fmt.Errorf("recording created events: %w", err)
+	}
+
+	deleted := `
+		INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at)
+		SELECT DISTINCT e.aggregate_id, 'TodoDeleted', '{"reconciled": true}'::jsonb, now()
+		FROM domain_events e
+		WHERE e.aggregate_id = ANY($1::uuid[]) AND ` + orphanedCondition
+	deletedTag, err := tx.Exec(ctx, deleted, idStrings(drift.Orphaned))
+	if err != nil {
+		return 0, fmt.Errorf("recording deleted events: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing repair: %w", err)
+	}
+
+	return int(createdTag.RowsAffected() + deletedTag.RowsAffected()), nil
+}
+
+// idStrings formats ids for a uuid[] parameter
+func idStrings(ids []domain.TodoID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestPostgresOutboxReconciler_RepairDrift(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Canary: true}))
+	reconciler := NewPostgresOutboxReconciler(pool, SchemaFeatures{Canary: true})
+
+	recorded := createTestTodo()
+	unrecorded := createTestTodo()
+	title, _ := domain.NewTaskTitle("Payroll credentials")
+	canary := domain.NewCanaryTodo(title, "Decoy")
+	for _, todo := range []*domain.Todo{recorded, unrecorded, canary} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	orphaned := domain.NewTodoID()
+	deleted := domain.NewTodoID()
+	milestone := domain.NewTodoID()
+	insert := `
+		INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at)
+		VALUES ($1, $2, '{}', $3)
+	`
+	for _, event := range []struct {
+		id        domain.TodoID
+		eventType string
+	}{
+		{recorded.ID(), "TodoCreated"},
+		{orphaned, "TodoCreated"},
+		{orphaned, "TodoUpdated"},
+		{deleted, "TodoCreated"},
+		{deleted, "TodoDeleted"},
+		{milestone, "MilestoneCompleted"},
+	} {
+		if _, err := pool.Exec(ctx, insert, event.id.String(), event.eventType, time.Now()); err != nil {
+			t.Fatalf("inserting event: %v", err)
+		}
+	}
+
+	drift, err := reconciler.FindDrift(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindDrift() unexpected error: %v", err)
+	}
+	if len(drift.Unrecorded) != 1 || drift.Unrecorded[0] != unrecorded.ID() {
+		t.Errorf("FindDrift() unrecorded = %v, want [%s]", drift.Unrecorded, unrecorded.ID())
+	}
+	if len(drift.Orphaned) != 1 || drift.Orphaned[0] != orphaned {
+		t.Errorf("FindDrift() orphaned = %v, want [%s]", drift.Orphaned, orphaned)
+	}
+
+	repaired, err := reconciler.RepairDrift(ctx, drift)
+	if err != nil {
+		t.Fatalf("RepairDrift() unexpected error: %v", err)
+	}
+	if repaired != 2 {
+		t.Errorf("RepairDrift() = %d, want 2 recorded events", repaired)
+	}
+
+	// A second repair of the same drift records nothing
+	repaired, err = reconciler.RepairDrift(ctx, drift)
+	if err != nil {
+		t.Fatalf("RepairDrift() unexpected error: %v", err)
+	}
+	if repaired != 0 {
+		t.Errorf("RepairDrift() again = %d, want 0", repaired)
+	}
+
+	drift, err = reconciler.FindDrift(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindDrift() unexpected error: %v", err)
+	}
+	if len(drift.Unrecorded) != 0 || len(drift.Orphaned) != 0 {
+		t.Errorf("FindDrift() after repair = %+v, want no drift", drift)
+	}
+
+	var reconciled bool
+	query := `
+		SELECT (event_data->>'reconciled')::boolean
+		FROM domain_events
+		WHERE aggregate_id = $1 AND event_type = 'TodoCreated'
+	`
+	if err := pool.QueryRow(ctx, query, unrecorded.ID().String()).Scan(&reconciled); err != nil {
+		t.Fatalf("querying recorded event: %v", err)
+	}
+	if !reconciled {
+		t.Error("RepairDrift() did not mark the recorded event as reconciled")
+	}
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LatestMigration is the version of the last migration in scripts/migrations
+// this binary knows about
+const LatestMigration = 35
+
+// requiredIndexes maps the indexes the queries rely on to the migration
+// creating them
+var requiredIndexes = map[string]int{
+	"idx_todos_status":                   1,
+	"idx_todos_due_date":                 1,
+	"idx_todos_created_at":               1,
+	"idx_todos_priority":                 1,
+	"idx_unpublished_events":             2,
+	"idx_events_by_aggregate":            2,
+	"idx_events_by_type":                 2,
+	"idx_audit_log_by_todo":              5,
+	"idx_todos_short_code":               6,
+	"idx_todos_title_trgm":               7,
+	"idx_todo_activity_viewed":           8,
+	"idx_todo_activity_modified":         8,
+	"idx_todo_history_as_of":             10,
+	"idx_todos_merged_into":              11,
+	"idx_todo_completions_user":          12,
+	"idx_todos_search":                   15,
+	"idx_todos_canary":                   16,
+	"idx_todos_user_id":                  17,
+	"idx_todos_unarchived":               19,
+	"idx_webhook_deliveries_endpoint":    20,
+	"idx_todo_audit_by_todo":             23,
+	"idx_todo_dependencies_blocked_by":   26,
+	"idx_milestone_todos_milestone":      27,
+	"idx_devices_user_id":                30,
+	"idx_webhook_deliveries_endpoint_id": 31,
+	"idx_operations_owner_id":            32,
+	"idx_operations_finished_at":         32,
+}
+
+// MigrationStatus is the state of the schema_migrations table maintained by
+// golang-migrate
+type MigrationStatus struct {
+	// Applied is false when no migration ever ran
+	Applied bool
+	Version int
+	// Dirty is set when the migration Version failed halfway
+	Dirty bool
+}
+
+// MissingIndex is a required index absent from the database
+type MissingIndex struct {
+	Name      string
+	Migration int
+}
+
+// ReadMigrationStatus returns the version of the schema
+func ReadMigrationStatus(ctx context.Context, pool *pgxpool.Pool) (MigrationStatus, error) {
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return MigrationStatus{}, fmt.Errorf("looking up schema_migrations: %w", err)
+	}
+	if !exists {
+		return MigrationStatus{}, nil
+	}
+
+	var status MigrationStatus
+	err := pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&status.Version, &status.Dirty)
+	if err == pgx.ErrNoRows {
+		return MigrationStatus{}, nil
+	}
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	status.Applied = true
+
+	return status, nil
+}
+
+// MissingIndexes returns the required indexes of the migrations up to
+// version that do not exist, ordered by migration
+// Indexes of later migrations are not expected yet
+func MissingIndexes(ctx context.Context, pool *pgxpool.Pool, version int) ([]MissingIndex, error) {
+	rows, err := pool.Query(ctx, `SELECT indexname FROM pg_indexes WHERE schemaname = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("querying indexes: %w", err)
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collecting indexes: %w", err)
+	}
+
+	return missingIndexes(existing, version), nil
+}
+
+// missingIndexes returns the required indexes of the migrations up to
+// version absent from existing
+func missingIndexes(existing []string, version int) []MissingIndex {
+	present := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		present[name] = true
+	}
+
+	var missing []MissingIndex
+	for name, migration := range requiredIndexes {
+		if migration <= version && !present[name] {
+			missing = append(missing, MissingIndex{Name: name, Migration: migration})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Migration != missing[j].Migration {
+			return missing[i].Migration < missing[j].Migration
+		}
+		return missing[i].Name < missing[j].Name
+	})
+	return missing
+}
+
+// DatabaseClockSkew returns how far the database clock is ahead of the local
+// one, compensating for the round trip
+func DatabaseClockSkew(ctx context.Context, pool *pgxpool.Pool) (time.Duration, error) {
+	before := time.Now()
+	var dbNow time.Time
+	if err := pool.QueryRow(ctx, `SELECT clock_timestamp()`).Scan(&dbNow); err != nil {
+		return 0, fmt.Errorf("reading database clock: %w", err)
+	}
+	after := time.Now()
+
+	local := before.Add(after.Sub(before) / 2)
+	return dbNow.Sub(local), nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMissingIndexes(t *testing.T) {
+	all := make([]string, 0, len(requiredIndexes))
+	for name := range requiredIndexes {
+		all = append(all, name)
+	}
+
+	tests := []struct {
+		name     string
+		existing []string
+		version  int
+		want     []MissingIndex
+	}{
+		{"fully migrated", all, LatestMigration, nil},
+		{"later indexes not expected yet", []string{"idx_todos_status", "idx_todos_due_date", "idx_todos_created_at", "idx_todos_priority"}, 1, nil},
+		{"dropped indexes", []string{"idx_todos_status", "idx_todos_due_date", "idx_todos_created_at"}, 2, []MissingIndex{
+			{Name: "idx_todos_priority", Migration: 1},
+			{Name: "idx_events_by_aggregate", Migration: 2},
+			{Name: "idx_events_by_type", Migration: 2},
+			{Name: "idx_unpublished_events", Migration: 2},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingIndexes(tt.existing, tt.version)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("missingIndexes() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("missingIndexes() = %+v, want %+v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestRequiredIndexes_MatchMigrations keeps LatestMigration and
+// requiredIndexes in step with scripts/migrations
+func TestRequiredIndexes_MatchMigrations(t *testing.T) {
+	files, err := filepath.Glob("../../../../scripts/migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("listing migrations: %v", err)
+	}
+
+	created := regexp.MustCompile(`CREATE (?:UNIQUE )?INDEX (?:IF NOT EXISTS )?(\w+)`)
+	latest := 0
+	indexes := map[string]int{}
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.SplitN(filepath.Base(file), "_", 2)[0])
+		if err != nil {
+			t.Fatalf("parsing version of %s: %v", file, err)
+		}
+		latest = max(latest, version)
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("reading %s: %v", file, err)
+		}
+		for _, match := range created.FindAllStringSubmatch(string(content), -1) {
+			indexes[match[1]] = version
+		}
+	}
+
+	if latest != LatestMigration {
+		t.Errorf("LatestMigration = %d, want %d", LatestMigration, latest)
+	}
+	for name, version := range indexes {
+		if requiredIndexes[name] != version {
+			t.Errorf("requiredIndexes[%q] = %d, want %d", name, requiredIndexes[name], version)
+		}
+	}
+	for name := range requiredIndexes {
+		if _, ok := indexes[name]; !ok {
+			t.Errorf("required index %q is created by no migration", name)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/pivaldi/mmw/todo/internal/pkg/reqtrace"
+)
+
+// maxTracedSQL bounds the statement kept to describe the slowest query
+const maxTracedSQL = 200
+
+// QueryTracer times the queries of traced requests, for slow request dumps
+// Set it as the Tracer of the pool's ConnConfig; queries outside a traced
+// request are not timed
+type QueryTracer struct{}
+
+// tracedQuery is the query in progress, carried by the context of the call
+type tracedQuery struct {
+	sql   string
+	start time.Time
+}
+
+// tracedQueryKey is the context key of the tracedQuery
+type tracedQueryKey struct{}
+
+// TraceQueryStart records the statement and when it started
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if reqtrace.FromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracedQueryKey{}, tracedQuery{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd adds the query to the SQL stage of the request
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(tracedQueryKey{}).(tracedQuery)
+	if !ok {
+		return
+	}
+	reqtrace.Record(ctx, reqtrace.StageSQL, time.Since(query.start), compactSQL(query.sql))
+}
+
+// compactSQL folds the whitespace of a statement and truncates it; the
+// arguments are sent apart, so no value of the request is kept
+func compactSQL(sql string) string {
+	compact := strings.Join(strings.Fields(sql), " ")
+	if len(compact) > maxTracedSQL {
+		compact = compact[:maxTracedSQL] + "…"
+	}
+	return compact
+}
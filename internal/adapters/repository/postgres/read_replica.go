@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithReadReplica sends the lists, counts, searches, activity reads and
+// analytics to replica, a read-only copy lagging at most maxLag behind pool
+// Callers who wrote less than maxLag ago (see ports.WrittenAtFromContext)
+// read from pool instead, so that they see their writes. The reads of
+// single todos stay on pool, as they precede every change
+func WithReadReplica(replica *pgxpool.Pool, maxLag time.Duration) RepositoryOption {
+	return func(r *PostgresTodoRepository) {
+		r.replica = replica
+		r.replicaLag = maxLag
+	}
+}
+
+// querier runs read queries, on the primary or a replica
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// reader returns where the heavy reads of ctx run: the replica, unless ctx
+// carries a transaction or the caller wrote recently enough for it not to
+// have replayed the write yet
+func (r *PostgresTodoRepository) reader(ctx context.Context) querier {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok || r.replica == nil {
+		return connOf(ctx, r.pool)
+	}
+	if writtenAt, ok := ports.WrittenAtFromContext(ctx); ok && time.Since(writtenAt) < r.replicaLag {
+		return r.pool
+	}
+	return r.replica
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresTodoRepository_Reader(t *testing.T) {
+	// Pools connect lazily, so none of these is dialed
+	newPool := func(url string) *pgxpool.Pool {
+		pool, err := pgxpool.New(context.Background(), url)
+		if err != nil {
+			t.Fatalf("pgxpool.New() unexpected error: %v", err)
+		}
+		t.Cleanup(pool.Close)
+		return pool
+	}
+	primary := newPool("postgres://primary.invalid/todoapp")
+	replica := newPool("postgres://replica.invalid/todoapp")
+
+	tests := []struct {
+		name      string
+		replica   *pgxpool.Pool
+		writtenAt time.Time
+		want      *pgxpool.Pool
+	}{
+		{"no replica", nil, time.Time{}, primary},
+		{"no recent write", replica, time.Time{}, replica},
+		{"write within the lag", replica, time.Now().Add(-time.Second), primary},
+		{"write older than the lag", replica, time.Now().Add(-time.Minute), replica},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []RepositoryOption{}
+			if tt.replica != nil {
+				opts = append(opts, WithReadReplica(tt.replica, 10*time.Second))
+			}
+			r := NewPostgresTodoRepository(primary, opts...)
+
+			ctx := ports.ContextWithSystemAccess(context.Background())
+			if !tt.writtenAt.IsZero() {
+				ctx = ports.ContextWithWrittenAt(ctx, tt.writtenAt)
+			}
+
+			if got := r.reader(ctx); got != tt.want {
+				t.Errorf("reader() = %v, want %v", got.(*pgxpool.Pool).Config().ConnConfig.Host, tt.want.Config().ConnConfig.Host)
+			}
+		})
+	}
+}
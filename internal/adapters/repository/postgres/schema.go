@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaFeatures lists the optional todos columns the repository may use
+// During a blue/green or rolling deploy the same binary can run against the
+// schema before and after a migration: columns are only read and written
+// once their feature is enabled
+type SchemaFeatures struct {
+	// CompletedAt enables the todos.completed_at column (migration 000004)
+	CompletedAt bool
+	// ShortCode enables the todos.short_code column (migration 000006)
+	ShortCode bool
+	// MergedInto enables the todos.merged_into column (migration 000011)
+	MergedInto bool
+	// Canary enables the todos.canary column (migration 000016)
+	Canary bool
+	// Owner enables the todos.user_id and todo_history.user_id columns
+	// (migration 000017)
+	Owner bool
+	// Archived enables the todos.archived_at column (migration 000019)
+	Archived bool
+	// Version enables the todos.version column and the refusal of stale
+	// updates (migration 000024)
+	Version bool
+}
+
+// Schema feature specs accepted by ResolveSchemaFeatures
+const (
+	// SchemaFeaturesAuto enables every feature whose columns exist
+	SchemaFeaturesAuto = "auto"
+	// SchemaFeaturesNone disables every optional column (legacy schema)
+	SchemaFeaturesNone = "none"
+)
+
+// schemaFeatureColumns maps feature names to the column they require
+var schemaFeatureColumns = map[string]string{
+	"completed_at": "completed_at",
+	"short_code":   "short_code",
+	"merged_into":  "merged_into",
+	"canary":       "canary",
+	"owner":        "user_id",
+	"archived":     "archived_at",
+	"version":      "version",
+}
+
+// ResolveSchemaFeatures decides which optional columns to use
+// spec is "auto", "none" or a comma-separated list of feature names; naming a
+// feature whose column is missing is an error, so a misconfigured deploy
+// fails at startup instead of on the first query
+func ResolveSchemaFeatures(ctx context.Context, pool *pgxpool.Pool, spec string) (SchemaFeatures, error) {
+	available, err := detectSchemaFeatures(ctx, pool)
+	if err != nil {
+		return SchemaFeatures{}, err
+	}
+
+	return parseSchemaFeatures(spec, available)
+}
+
+// detectSchemaFeatures reports the features whose columns exist in the database
+func detectSchemaFeatures(ctx context.Context, pool *pgxpool.Pool) (map[string]bool, error) {
+	query := `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'todos'
+	`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying todos columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collecting todos columns: %w", err)
+	}
+
+	existing := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		existing[column] = true
+	}
+
+	available := make(map[string]bool, len(schemaFeatureColumns))
+	for feature, column := range schemaFeatureColumns {
+		available[feature] = existing[column]
+	}
+
+	return available, nil
+}
+
+// parseSchemaFeatures applies a feature spec to the available features
+func parseSchemaFeatures(spec string, available map[string]bool) (SchemaFeatures, error) {
+	enabled := map[string]bool{}
+
+	switch spec = strings.TrimSpace(spec); spec {
+	case "", SchemaFeaturesAuto:
+		enabled = available
+	case SchemaFeaturesNone:
+	default:
+		for _, name := range strings.Split(spec, ",") {
+			name = strings.TrimSpace(name)
+			if _, known := schemaFeatureColumns[name]; !known {
+				return SchemaFeatures{}, fmt.Errorf("unknown schema feature %q", name)
+			}
+			if !available[name] {
+				return SchemaFeatures{}, fmt.Errorf("schema feature %q requires a pending migration", name)
+			}
+			enabled[name] = true
+		}
+	}
+
+	return SchemaFeatures{
+		CompletedAt: enabled["completed_at"],
+		ShortCode:   enabled["short_code"],
+		MergedInto:  enabled["merged_into"],
+		Canary:      enabled["canary"],
+		Owner:       enabled["owner"],
+		Archived:    enabled["archived"],
+		Version:     enabled["version"],
+	}, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import "testing"
+
+func TestParseSchemaFeatures(t *testing.T) {
+	migrated := map[string]bool{"completed_at": true, "short_code": true, "merged_into": true, "canary": true, "owner": true, "archived": true, "version": true}
+	legacy := map[string]bool{"completed_at": false, "short_code": false, "merged_into": false, "canary": false, "owner": false, "archived": false, "version": false}
+	all := SchemaFeatures{CompletedAt: true, ShortCode: true, MergedInto: true, Canary: true, Owner: true, Archived: true, Version: true}
+
+	tests := []struct {
+		name      string
+		spec      string
+		available map[string]bool
+		want      SchemaFeatures
+		wantErr   bool
+	}{
+		{"auto on migrated schema", "auto", migrated, all, false},
+		{"auto on legacy schema", "auto", legacy, SchemaFeatures{}, false},
+		{"empty means auto", "", migrated, all, false},
+		{"none on migrated schema", "none", migrated, SchemaFeatures{}, false},
+		{"explicit feature", "completed_at", migrated, SchemaFeatures{CompletedAt: true}, false},
+		{"explicit feature list", "completed_at, short_code,merged_into,canary,owner,archived,version", migrated, all, false},
+		{"explicit feature missing column", "completed_at", legacy, SchemaFeatures{}, true},
+		{"unknown feature", "tags", migrated, SchemaFeatures{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSchemaFeatures(tt.spec, tt.available)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseSchemaFeatures() expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseSchemaFeatures() unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseSchemaFeatures() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresStatusProbe reports the state of the database for on-call triage:
+// the outbox backlog of migration 000002 and the use of the pool
+type PostgresStatusProbe struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStatusProbe creates a new PostgresStatusProbe
+func NewPostgresStatusProbe(pool *pgxpool.Pool) *PostgresStatusProbe {
+	return &PostgresStatusProbe{pool: pool}
+}
+
+// OutboxBacklog counts the unpublished domain events, served by the partial
+// index on published_at
+func (p *PostgresStatusProbe) OutboxBacklog(ctx context.Context) (ports.OutboxBacklog, error) {
+	query := `
+		SELECT count(*), min(occurred_at)
+		FROM domain_events
+		WHERE published_at IS NULL
+	`
+
+	var backlog ports.OutboxBacklog
+	var oldest *time.Time
+	if err := p.pool.QueryRow(ctx, query).Scan(&backlog.Pending, &oldest); err != nil {
+		return ports.OutboxBacklog{}, fmt.Errorf("querying outbox backlog: %w", err)
+	}
+	backlog.OldestPendingAt = oldest
+
+	return backlog, nil
+}
+
+// PoolStats returns the current use of the connection pool
+func (p *PostgresStatusProbe) PoolStats() ports.PoolStats {
+	stat := p.pool.Stat()
+	return ports.PoolStats{
+		MaxConns:         stat.MaxConns(),
+		TotalConns:       stat.TotalConns(),
+		AcquiredConns:    stat.AcquiredConns(),
+		IdleConns:        stat.IdleConns(),
+		Acquires:         stat.AcquireCount(),
+		EmptyAcquires:    stat.EmptyAcquireCount(),
+		CanceledAcquires: stat.CanceledAcquireCount(),
+		AcquireDuration:  stat.AcquireDuration(),
+	}
+}
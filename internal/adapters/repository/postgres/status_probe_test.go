@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestPostgresStatusProbe_OutboxBacklog(t *testing.T) {
+	pool := setupTestDB(t)
+	probe := NewPostgresStatusProbe(pool)
+	ctx := context.Background()
+
+	empty, err := probe.OutboxBacklog(ctx)
+	if err != nil {
+		t.Fatalf("OutboxBacklog() unexpected error: %v", err)
+	}
+	if empty.Pending != 0 || empty.OldestPendingAt != nil {
+		t.Errorf("OutboxBacklog() = %+v, want an empty backlog", empty)
+	}
+
+	oldest := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	insert := `
+		INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at, published_at)
+		VALUES ($1, 'TodoCreated', '{}', $2, $3)
+	`
+	published := time.Now()
+	for _, event := range []struct {
+		at        time.Time
+		published *time.Time
+	}{
+		{oldest.Add(-time.Hour), &published},
+		{oldest, nil},
+		{oldest.Add(time.Minute), nil},
+	} {
+		if _, err := pool.Exec(ctx, insert, domain.NewTodoID().String(), event.at, event.published); err != nil {
+			t.Fatalf("inserting event: %v", err)
+		}
+	}
+
+	backlog, err := probe.OutboxBacklog(ctx)
+	if err != nil {
+		t.Fatalf("OutboxBacklog() unexpected error: %v", err)
+	}
+	if backlog.Pending != 2 || backlog.OldestPendingAt == nil || !backlog.OldestPendingAt.Equal(oldest) {
+		t.Errorf("OutboxBacklog() = %d pending since %v, want 2 since %v", backlog.Pending, backlog.OldestPendingAt, oldest)
+	}
+
+	if stats := probe.PoolStats(); stats.MaxConns == 0 {
+		t.Errorf("PoolStats() = %+v, want the pool limits", stats)
+	}
+}
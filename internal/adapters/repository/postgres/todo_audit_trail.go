@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresTodoAuditTrail implements the TodoAuditTrail port using PostgreSQL
+type PostgresTodoAuditTrail struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresTodoAuditTrail creates a new PostgreSQL todo audit trail
+func NewPostgresTodoAuditTrail(pool *pgxpool.Pool) *PostgresTodoAuditTrail {
+	return &PostgresTodoAuditTrail{
+		pool: pool,
+	}
+}
+
+// Append records entries in a single transaction, all or none
+func (a *PostgresTodoAuditTrail) Append(ctx context.Context, entries []ports.TodoAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO todo_audit (todo_id, event_type, actor, changes, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning todo audit transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, entry := range entries {
+		changes := entry.Changes
+		if changes == nil {
+			changes = map[string]string{}
+		}
+		if _, err := tx.Exec(ctx, query, entry.TodoID, entry.EventType, entry.Actor, changes, entry.OccurredAt); err != nil {
+			return fmt.Errorf("appending todo audit entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing todo audit entries: %w", err)
+	}
+
+	return nil
+}
+
+// ListByTodo returns the entries of a todo, oldest first
+func (a *PostgresTodoAuditTrail) ListByTodo(ctx context.Context, todoID string) ([]ports.TodoAuditEntry, error) {
+	query := `
+		SELECT id, todo_id::text, event_type, actor, changes, occurred_at
+		FROM todo_audit
+		WHERE todo_id = $1
+		ORDER BY occurred_at, id
+	`
+
+	return a.list(ctx, query, todoID)
+}
+
+// ListBetween returns at most limit entries that occurred at or after from
+// and before to, with an ID above after, in ID order
+func (a *PostgresTodoAuditTrail) ListBetween(ctx context.Context, from, to time.Time, after int64, limit int) ([]ports.TodoAuditEntry, error) {
+	query := `
+		SELECT id, todo_id::text, event_type, actor, changes, occurred_at
+		FROM todo_audit
+		WHERE id > $1 AND occurred_at >= $2 AND occurred_at < $3
+		ORDER BY id
+		LIMIT $4
+	`
+
+	return a.list(ctx, query, after, from, to, limit)
+}
+
+// list runs query, which selects entries
+func (a *PostgresTodoAuditTrail) list(ctx context.Context, query string, args ...any) ([]ports.TodoAuditEntry, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying todo audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.TodoAuditEntry, error) {
+		var entry ports.TodoAuditEntry
+		err := row.Scan(&entry.Seq, &entry.TodoID, &entry.EventType, &entry.Actor, &entry.Changes, &entry.OccurredAt)
+		return entry, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting todo audit entries: %w", err)
+	}
+
+	return entries, nil
+}
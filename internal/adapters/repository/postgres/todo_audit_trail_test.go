@@ -0,0 +1,95 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresTodoAuditTrail_ListByTodo(t *testing.T) {
+	pool := setupTestDB(t)
+	trail := NewPostgresTodoAuditTrail(pool)
+	ctx := context.Background()
+
+	todoID := createTestTodo().ID().String()
+	start := time.Now().Truncate(time.Second)
+	err := trail.Append(ctx, []ports.TodoAuditEntry{
+		{TodoID: todoID, EventType: "TodoUpdated", Actor: "alice", Changes: map[string]string{"title": "Renamed"}, OccurredAt: start.Add(time.Minute)},
+		{TodoID: todoID, EventType: "TodoCreated", Actor: "alice", OccurredAt: start},
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoCreated", OccurredAt: start},
+	})
+	if err != nil {
+		t.Fatalf("Append() unexpected error: %v", err)
+	}
+
+	entries, err := trail.ListByTodo(ctx, todoID)
+	if err != nil {
+		t.Fatalf("ListByTodo() unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].EventType != "TodoCreated" || entries[1].EventType != "TodoUpdated" {
+		t.Fatalf("ListByTodo() = %+v, want the creation then the update", entries)
+	}
+	if entries[1].Actor != "alice" || entries[1].Changes["title"] != "Renamed" {
+		t.Errorf("entries[1] = %+v, want alice renaming the todo", entries[1])
+	}
+}
+
+func TestPostgresTodoAuditTrail_ListBetween(t *testing.T) {
+	pool := setupTestDB(t)
+	trail := NewPostgresTodoAuditTrail(pool)
+	ctx := context.Background()
+
+	start := time.Now().Truncate(time.Second)
+	err := trail.Append(ctx, []ports.TodoAuditEntry{
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoCreated", OccurredAt: start.Add(-time.Hour)},
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoCreated", OccurredAt: start},
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoUpdated", Changes: map[string]string{"priority": "high"}, OccurredAt: start.Add(time.Minute)},
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoDeleted", OccurredAt: start.Add(2 * time.Minute)},
+		{TodoID: createTestTodo().ID().String(), EventType: "TodoCompleted", OccurredAt: start.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Append() unexpected error: %v", err)
+	}
+
+	from, to := start, start.Add(time.Hour)
+	page, err := trail.ListBetween(ctx, from, to, 0, 2)
+	if err != nil {
+		t.Fatalf("ListBetween() unexpected error: %v", err)
+	}
+	if len(page) != 2 || page[0].EventType != "TodoCreated" || page[1].Changes["priority"] != "high" || page[0].Seq >= page[1].Seq {
+		t.Fatalf("ListBetween() = %+v, want the creation then the update", page)
+	}
+
+	page, err = trail.ListBetween(ctx, from, to, page[1].Seq, 2)
+	if err != nil || len(page) != 1 || page[0].EventType != "TodoDeleted" {
+		t.Errorf("ListBetween() next page = %+v, %v, want the deletion only", page, err)
+	}
+}
+
+func TestPostgresTodoAuditTrail_Append_AllOrNone(t *testing.T) {
+	pool := setupTestDB(t)
+	trail := NewPostgresTodoAuditTrail(pool)
+	ctx := context.Background()
+
+	todoID := createTestTodo().ID().String()
+	err := trail.Append(ctx, []ports.TodoAuditEntry{
+		{TodoID: todoID, EventType: "TodoCreated", OccurredAt: time.Now()},
+		{TodoID: "not-a-uuid", EventType: "TodoCreated", OccurredAt: time.Now()},
+	})
+	if err == nil {
+		t.Fatal("Append() expected error for an invalid todo ID")
+	}
+
+	entries, err := trail.ListByTodo(ctx, todoID)
+	if err != nil {
+		t.Fatalf("ListByTodo() unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("ListByTodo() = %+v, want no entry", entries)
+	}
+}
@@ -4,53 +4,177 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
 	"github.com/pivaldi/mmw/todo/internal/ports"
 )
 
 // PostgresTodoRepository implements the TodoRepository port using PostgreSQL
 type PostgresTodoRepository struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	replica    *pgxpool.Pool
+	replicaLag time.Duration
+	features   SchemaFeatures
+	owners     *bulkhead.Bulkhead
 }
 
-// todoRow represents a todo row from the database
+// RepositoryOption configures a PostgresTodoRepository
+type RepositoryOption func(*PostgresTodoRepository)
+
+// WithSchemaFeatures enables optional columns (see ResolveSchemaFeatures)
+// Without it the repository only uses the baseline schema
+func WithSchemaFeatures(features SchemaFeatures) RepositoryOption {
+	return func(r *PostgresTodoRepository) {
+		r.features = features
+	}
+}
+
+// WithOwnerBulkhead caps the concurrent lists, counts, searches, activity
+// reads and analytics of each owner, so that one owner cannot use up the
+// connection pool
+// Calls over the cap fail with bulkhead.ErrFull; unscoped calls are not
+// capped
+func WithOwnerBulkhead(owners *bulkhead.Bulkhead) RepositoryOption {
+	return func(r *PostgresTodoRepository) {
+		r.owners = owners
+	}
+}
+
+// todoRow represents a todo row from the database, or the fields of a todo
+// recorded by the event store (see PostgresEventSourcedTodoRepository)
 type todoRow struct {
-	ID          string     `db:"id"`
-	Title       string     `db:"title"`
-	Description string     `db:"description"`
-	Status      string     `db:"status"`
-	Priority    string     `db:"priority"`
-	DueDate     *time.Time `db:"due_date"`
-	CreatedAt   time.Time  `db:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at"`
+	ID          string     `db:"id" json:"-"`
+	Title       string     `db:"title" json:"title"`
+	Description string     `db:"description" json:"description"`
+	Status      string     `db:"status" json:"status"`
+	Priority    string     `db:"priority" json:"priority"`
+	DueDate     *time.Time `db:"due_date" json:"due_date"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at"`
+	ShortCode   *int64     `db:"short_code" json:"-"`
+	MergedInto  *string    `db:"merged_into" json:"merged_into"`
+	Canary      *bool      `db:"canary" json:"canary"`
+	UserID      *string    `db:"user_id" json:"user_id"`
+	ArchivedAt  *time.Time `db:"archived_at" json:"archived_at"`
+	Version     *int64     `db:"version" json:"-"`
 }
 
 // NewPostgresTodoRepository creates a new PostgreSQL repository
-func NewPostgresTodoRepository(pool *pgxpool.Pool) *PostgresTodoRepository {
-	return &PostgresTodoRepository{
+func NewPostgresTodoRepository(pool *pgxpool.Pool, opts ...RepositoryOption) *PostgresTodoRepository {
+	r := &PostgresTodoRepository{
 		pool: pool,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// selectColumns returns the todos columns to read, honoring schema features
+func (r *PostgresTodoRepository) selectColumns() string {
+	columns := "id, title, description, status, priority, due_date, created_at, updated_at"
+	if r.features.CompletedAt {
+		columns += ", completed_at"
+	}
+	if r.features.ShortCode {
+		columns += ", short_code"
+	}
+	if r.features.MergedInto {
+		columns += ", merged_into"
+	}
+	if r.features.Canary {
+		columns += ", canary"
+	}
+	if r.features.Owner {
+		columns += ", user_id"
+	}
+	if r.features.Archived {
+		columns += ", archived_at"
+	}
+	if r.features.Version {
+		columns += ", version"
+	}
+	return columns
+}
+
+// listable returns the condition leaving canary todos out of listings
+func (r *PostgresTodoRepository) listable() string {
+	if !r.features.Canary {
+		return ""
+	}
+	return " AND NOT canary"
+}
+
+// owned returns the condition scoping a query to the owner of ctx on column,
+// appending its argument to args
+// A ctx without an owner matches no row unless it has system access, which
+// lifts the scope, as does disabled ownership; the operator queries (purges,
+// reminders, canaries) are never scoped
+func (r *PostgresTodoRepository) owned(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	ownerID, ok := ports.OwnerFromContext(ctx)
+	if !ok {
+		if ports.HasSystemAccess(ctx) {
+			return "", args
+		}
+		return " AND FALSE", args
+	}
+	if !r.features.Owner {
+		return "", args
+	}
+
+	args = append(args, ownerID)
+	return fmt.Sprintf(" AND %s = $%d", column, len(args)), args
+}
+
+// acquire takes a slot of the owner of ctx for a heavy read and returns the
+// function releasing it
+func (r *PostgresTodoRepository) acquire(ctx context.Context) (func(), error) {
+	ownerID, ok := ports.OwnerFromContext(ctx)
+	if !ok || r.owners == nil {
+		return func() {}, nil
+	}
+
+	release, err := r.owners.Acquire(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for a query slot: %w", err)
+	}
+	return release, nil
+}
+
+// hiddenFromActivity returns the condition leaving the history of canary
+// todos out of the activity feed
+func (r *PostgresTodoRepository) hiddenFromActivity() string {
+	if !r.features.Canary {
+		return ""
+	}
+	return " AND NOT EXISTS (SELECT 1 FROM todos c WHERE c.id = h.todo_id AND c.canary)"
 }
 
 // Save persists a new todo to the database
+// When short codes are enabled, the code allocated by the database is
+// assigned to the todo
 func (r *PostgresTodoRepository) Save(ctx context.Context, todo *domain.Todo) error {
-	query := `
-		INSERT INTO todos (id, title, description, status, priority, due_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
+	return r.insert(ctx, connOf(ctx, r.pool), todo)
+}
 
+// insert writes the new todo with db
+func (r *PostgresTodoRepository) insert(ctx context.Context, db execer, todo *domain.Todo) error {
 	var dueDate *time.Time
 	if todo.DueDate() != nil {
 		t := todo.DueDate().Time()
 		dueDate = &t
 	}
 
-	_, err := r.pool.Exec(ctx, query,
+	columns := []string{"id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at"}
+	args := []interface{}{
 		todo.ID().String(),
 		todo.Title().String(),
 		todo.Description(),
@@ -59,24 +183,91 @@ func (r *PostgresTodoRepository) Save(ctx context.Context, todo *domain.Todo) er
 		dueDate,
 		todo.CreatedAt(),
 		todo.UpdatedAt(),
+	}
+	if r.features.CompletedAt {
+		columns = append(columns, "completed_at")
+		args = append(args, todo.CompletedAt())
+	}
+	if todo.IsCanary() {
+		if !r.features.Canary {
+			return errors.New("saving canary todo: the canary column is not enabled")
+		}
+		columns = append(columns, "canary")
+		args = append(args, true)
+	}
+	if todo.OwnerID() != "" {
+		if !r.features.Owner {
+			return errors.New("saving owned todo: the user_id column is not enabled")
+		}
+		columns = append(columns, "user_id")
+		args = append(args, todo.OwnerID())
+	}
+	if r.features.Version {
+		columns = append(columns, "version")
+		args = append(args, 1)
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO todos (%s) VALUES (%s)",
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
 	)
 
-	if err != nil {
-		return fmt.Errorf("saving todo: %w", err)
+	if !r.features.ShortCode {
+		if _, err := db.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("saving todo: %w", err)
+		}
+	} else {
+		var shortCode int64
+		if err := db.QueryRow(ctx, query+" RETURNING short_code", args...).Scan(&shortCode); err != nil {
+			return fmt.Errorf("saving todo: %w", err)
+		}
+		todo.AssignShortCode(domain.ShortCode(shortCode))
+	}
+
+	if r.features.Version {
+		todo.AssignVersion(1)
 	}
 
 	return nil
 }
 
+// FindIDByShortCode resolves a short code to the ID of its todo
+// Returns ErrTodoNotFound if no todo has this code or short codes are disabled
+func (r *PostgresTodoRepository) FindIDByShortCode(ctx context.Context, code domain.ShortCode) (domain.TodoID, error) {
+	if !r.features.ShortCode {
+		return "", domain.ErrTodoNotFound
+	}
+
+	owned, args := r.owned(ctx, "user_id", []interface{}{int64(code)})
+	query := `SELECT id FROM todos WHERE short_code = $1` + owned
+
+	var id string
+	if err := connOf(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrTodoNotFound
+		}
+		return "", fmt.Errorf("resolving short code: %w", err)
+	}
+
+	return domain.ParseTodoID(id)
+}
+
 // FindByID retrieves a todo by its ID
 func (r *PostgresTodoRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{id.String()})
 	query := `
-		SELECT id, title, description, status, priority, due_date, created_at, updated_at
+		SELECT ` + r.selectColumns() + `
 		FROM todos
-		WHERE id = $1
+		WHERE id = $1` + owned + `
 	`
 
-	rows, err := r.pool.Query(ctx, query, id.String())
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying todo: %w", err)
 	}
@@ -95,30 +286,21 @@ func (r *PostgresTodoRepository) FindByID(ctx context.Context, id domain.TodoID)
 
 // FindAll retrieves todos matching the given filters
 func (r *PostgresTodoRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	where, args := r.filterConditions(ctx, filters)
 	query := `
-		SELECT id, title, description, status, priority, due_date, created_at, updated_at
+		SELECT ` + r.selectColumns() + `
 		FROM todos
-		WHERE 1=1
+		WHERE ` + where + `
 	`
-	args := []interface{}{}
-	argIndex := 1
+	argIndex := len(args) + 1
 
-	// Apply status filter
-	if filters.Status != nil {
-		query += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, filters.Status.String())
-		argIndex++
-	}
-
-	// Apply priority filter
-	if filters.Priority != nil {
-		query += fmt.Sprintf(" AND priority = $%d", argIndex)
-		args = append(args, filters.Priority.String())
-		argIndex++
-	}
-
-	// Order by created_at descending (newest first)
-	query += " ORDER BY created_at DESC"
+	query += " ORDER BY " + orderBy(filters)
 
 	// Apply limit
 	if filters.Limit != nil {
@@ -134,7 +316,7 @@ func (r *PostgresTodoRepository) FindAll(ctx context.Context, filters ports.Filt
 		argIndex++
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying todos: %w", err)
 	}
@@ -148,21 +330,416 @@ func (r *PostgresTodoRepository) FindAll(ctx context.Context, filters ports.Filt
 	return todos, nil
 }
 
-// Update updates an existing todo
-func (r *PostgresTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+// Count returns the number of todos matching the given filters, ignoring
+// Limit and Offset
+func (r *PostgresTodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	where, args := r.filterConditions(ctx, filters)
+	query := `SELECT count(*) FROM todos WHERE ` + where
+
+	var count int
+	if err := r.reader(ctx).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting todos: %w", err)
+	}
+
+	return count, nil
+}
+
+// sortColumns maps sort fields to the expression they order by
+// Priorities are ranked by urgency rather than alphabetically
+var sortColumns = map[ports.SortField]string{
+	ports.SortByCreatedAt: "created_at",
+	ports.SortByUpdatedAt: "updated_at",
+	ports.SortByDueDate:   "due_date",
+	ports.SortByPriority:  "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 END",
+	ports.SortByTitle:     "lower(title)",
+}
+
+// orderBy returns the ORDER BY expressions of the requested sort, newest
+// first by default
+// Todos without a due date come last in either direction, and ties are
+// broken newest first so pages are stable
+func orderBy(filters ports.Filters) string {
+	column, ok := sortColumns[filters.SortBy]
+	if !ok {
+		return "created_at DESC, id"
+	}
+
+	direction := "ASC"
+	if filters.SortOrder == ports.SortDescending {
+		direction = "DESC"
+	}
+
+	return column + " " + direction + " NULLS LAST, created_at DESC, id"
+}
+
+// filterConditions returns the WHERE conditions of the status, priority and
+// archival filters and of the owner scope, with their arguments numbered from $1
+func (r *PostgresTodoRepository) filterConditions(ctx context.Context, filters ports.Filters) (string, []interface{}) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{})
+	where := "1=1" + r.listable() + owned
+
+	// Apply status filter
+	if filters.Status != nil {
+		args = append(args, filters.Status.String())
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	// Apply priority filter
+	if filters.Priority != nil {
+		args = append(args, filters.Priority.String())
+		where += fmt.Sprintf(" AND priority = $%d", len(args))
+	}
+
+	// Apply archival filter; without the column no todo is archived
+	if filters.Archived != nil {
+		switch {
+		case r.features.Archived && *filters.Archived:
+			where += " AND archived_at IS NOT NULL"
+		case r.features.Archived:
+			where += " AND archived_at IS NULL"
+		case *filters.Archived:
+			where += " AND FALSE"
+		}
+	}
+
+	return where, args
+}
+
+// Suggest returns at most limit todos whose title contains query (case
+// insensitive), titles starting with query first, then shorter titles
+// Served by the trigram index of migration 000007
+func (r *PostgresTodoRepository) Suggest(ctx context.Context, query string, limit int) ([]*domain.Todo, error) {
+	pattern := escapeLike(query)
+	owned, args := r.owned(ctx, "user_id", []interface{}{"%" + pattern + "%", pattern + "%"})
+	args = append(args, limit)
+
+	sqlQuery := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos
+		WHERE title ILIKE $1 ESCAPE '\'` + r.listable() + owned + `
+		ORDER BY title ILIKE $2 ESCAPE '\' DESC, length(title), updated_at DESC
+		LIMIT $` + fmt.Sprint(len(args)) + `
+	`
+
+	rows, err := r.reader(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting suggestions: %w", err)
+	}
+
+	return todos, nil
+}
+
+// todoSearchDocument weighs title words above description words
+// It must match the expression of the full-text index of migration 000015
+const todoSearchDocument = `(
+	setweight(to_tsvector('english', title), 'A') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'B')
+)`
+
+// Search returns at most limit todos matching query in their title or
+// description, best ranked first, skipping the first offset
+// query uses web search syntax: quoted phrases, "or" and "-" exclusions
+func (r *PostgresTodoRepository) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Todo, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	owned, args := r.owned(ctx, "user_id", []interface{}{query})
+	args = append(args, limit, offset)
+
+	sqlQuery := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos, websearch_to_tsquery('english', $1) AS query
+		WHERE ` + todoSearchDocument + ` @@ query` + r.listable() + owned + `
+		ORDER BY ts_rank(` + todoSearchDocument + `, query) DESC, updated_at DESC, id
+		LIMIT $` + fmt.Sprint(len(args)-1) + ` OFFSET $` + fmt.Sprint(len(args)) + `
+	`
+
+	rows, err := r.reader(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting search results: %w", err)
+	}
+
+	return todos, nil
+}
+
+// FindAsOf returns the todo as it was at the given time, from the versions
+// recorded in todo_history
+func (r *PostgresTodoRepository) FindAsOf(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{id.String(), at})
+	query := `
+		SELECT id, title, description, status, priority, due_date, created_at, updated_at, completed_at, short_code
+		FROM (
+			SELECT todo_id AS id, title, description, status, priority, due_date,
+				created_at, updated_at, completed_at, short_code, deleted
+			FROM todo_history
+			WHERE todo_id = $1 AND recorded_at <= $2` + owned + `
+			ORDER BY recorded_at DESC, id DESC
+			LIMIT 1
+		) version
+		WHERE NOT deleted
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying todo history: %w", err)
+	}
+	defer rows.Close()
+
+	todo, err := pgx.CollectOneRow(rows, todoRowScanner)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("collecting todo version: %w", err)
+	}
+
+	return todo, nil
+}
+
+// FindVersion returns the version of the todo recorded in todo_history with
+// the given updated_at, to the microsecond
+func (r *PostgresTodoRepository) FindVersion(ctx context.Context, id domain.TodoID, updatedAt time.Time) (*domain.Todo, error) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{id.String(), updatedAt.Truncate(time.Microsecond)})
 	query := `
-		UPDATE todos
-		SET title = $2, description = $3, status = $4, priority = $5, due_date = $6, updated_at = $7
-		WHERE id = $1
+		SELECT todo_id AS id, title, description, status, priority, due_date,
+			created_at, updated_at, completed_at, short_code
+		FROM todo_history
+		WHERE todo_id = $1 AND updated_at = $2 AND NOT deleted` + owned + `
+		ORDER BY todo_history.id DESC
+		LIMIT 1
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying todo version: %w", err)
+	}
+	defer rows.Close()
+
+	todo, err := pgx.CollectOneRow(rows, todoRowScanner)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("collecting todo version: %w", err)
+	}
+
+	return todo, nil
+}
+
+// ListActivity lists changes to all todos from the versions recorded in
+// todo_history, each version being compared with the previous one
+func (r *PostgresTodoRepository) ListActivity(ctx context.Context, before int64, limit int) ([]ports.ActivityEvent, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	owned, args := r.owned(ctx, "h.user_id", []interface{}{before, limit})
+	query := `
+		SELECT h.id, h.todo_id::text, h.title, h.recorded_at,
+			CASE
+				WHEN h.deleted THEN 'deleted'
+				WHEN previous.status IS NULL THEN 'created'
+				WHEN h.status = 'completed' AND previous.status <> 'completed' THEN 'completed'
+				ELSE 'updated'
+			END
+		FROM todo_history h
+		LEFT JOIN LATERAL (
+			SELECT p.status
+			FROM todo_history p
+			WHERE p.todo_id = h.todo_id AND p.id < h.id
+			ORDER BY p.recorded_at DESC, p.id DESC
+			LIMIT 1
+		) previous ON TRUE
+		WHERE ($1 = 0 OR h.id < $1)` + r.hiddenFromActivity() + owned + `
+		ORDER BY h.id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying activity: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.ActivityEvent, error) {
+		var event ports.ActivityEvent
+		var todoID, kind string
+		if err := row.Scan(&event.Seq, &todoID, &event.Title, &event.OccurredAt, &kind); err != nil {
+			return event, err
+		}
+		event.Kind = ports.ActivityEventKind(kind)
+
+		id, err := domain.ParseTodoID(todoID)
+		event.TodoID = id
+		return event, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting activity: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListChangedSince lists the todos changed after the todo_history version
+// numbered after, from their latest version
+func (r *PostgresTodoRepository) ListChangedSince(ctx context.Context, after int64, limit int) ([]ports.TodoChangeMark, error) {
+	owned, args := r.owned(ctx, "h.user_id", []interface{}{after, limit})
+	query := `
+		SELECT id, todo_id, deleted
+		FROM (
+			SELECT DISTINCT ON (h.todo_id) h.id, h.todo_id::text, h.deleted
+			FROM todo_history h
+			WHERE h.id > $1` + r.hiddenFromActivity() + owned + `
+			ORDER BY h.todo_id, h.id DESC
+		) latest
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying changes: %w", err)
+	}
+	defer rows.Close()
+
+	marks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.TodoChangeMark, error) {
+		var mark ports.TodoChangeMark
+		var todoID string
+		if err := row.Scan(&mark.Seq, &todoID, &mark.Deleted); err != nil {
+			return mark, err
+		}
+
+		id, err := domain.ParseTodoID(todoID)
+		mark.TodoID = id
+		return mark, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting changes: %w", err)
+	}
+
+	return marks, nil
+}
+
+// Analytics counts created, completed and overdue todos per bucket
+// Completion times come from the completed_at column; deleted todos are not
+// counted, and overdue counts use the current status to leave out
+// cancelled todos
+func (r *PostgresTodoRepository) Analytics(ctx context.Context, query ports.AnalyticsQuery) (*ports.AnalyticsSeries, error) {
+	if !r.features.CompletedAt {
+		return nil, errors.New("analytics require the completed_at column")
+	}
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	db := r.reader(ctx)
+
+	owned, bucketsArgs := r.owned(ctx, "t.user_id", []interface{}{query.From, query.Buckets, query.BucketDays})
+	bucketsQuery := `
+		WITH buckets AS (
+			SELECT $1::timestamptz + make_interval(days => i * $3) AS start,
+				$1::timestamptz + make_interval(days => (i + 1) * $3) AS stop
+			FROM generate_series(0, $2 - 1) AS i
+		)
+		SELECT b.start,
+			(SELECT count(*) FROM todos t WHERE t.created_at >= b.start AND t.created_at < b.stop` + owned + `),
+			(SELECT count(*) FROM todos t WHERE t.completed_at >= b.start AND t.completed_at < b.stop` + owned + `),
+			(SELECT count(*) FROM todos t
+				WHERE t.due_date < b.stop AND t.created_at < b.stop AND t.status <> 'cancelled'
+					AND (t.completed_at IS NULL OR t.completed_at >= b.stop)` + owned + `)
+		FROM buckets b
+		ORDER BY b.start
+	`
+
+	rows, err := db.Query(ctx, bucketsQuery, bucketsArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("querying analytics: %w", err)
+	}
+	defer rows.Close()
+
+	buckets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.AnalyticsBucket, error) {
+		var bucket ports.AnalyticsBucket
+		err := row.Scan(&bucket.Start, &bucket.Created, &bucket.Completed, &bucket.Overdue)
+		return bucket, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting analytics: %w", err)
+	}
+
+	series := &ports.AnalyticsSeries{Buckets: buckets}
+
+	owned, summaryArgs := r.owned(ctx, "user_id", []interface{}{query.From, query.Buckets * query.BucketDays})
+	summaryQuery := `
+		SELECT count(*), COALESCE(avg(EXTRACT(EPOCH FROM completed_at - created_at)), 0)::float8
+		FROM todos
+		WHERE completed_at >= $1 AND completed_at < $1::timestamptz + make_interval(days => $2)` + owned + `
 	`
 
+	var seconds float64
+	err = db.QueryRow(ctx, summaryQuery, summaryArgs...).Scan(&series.Completed, &seconds)
+	if err != nil {
+		return nil, fmt.Errorf("querying cycle time: %w", err)
+	}
+	series.AverageCycleTime = time.Duration(seconds * float64(time.Second))
+
+	return series, nil
+}
+
+// escapeLike escapes the LIKE wildcards of s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Update updates an existing todo
+// With versions enabled, it returns ErrConcurrentModification when the todo
+// was saved since it was read, and assigns the todo its new version
+func (r *PostgresTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	return r.update(ctx, connOf(ctx, r.pool), todo)
+}
+
+// execer runs statements on a pool or within a transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// update writes todo with db
+// Todos read without a version (zero), e.g. from the history, are written
+// whatever their stored version
+func (r *PostgresTodoRepository) update(ctx context.Context, db execer, todo *domain.Todo) error {
 	var dueDate *time.Time
 	if todo.DueDate() != nil {
 		t := todo.DueDate().Time()
 		dueDate = &t
 	}
 
-	result, err := r.pool.Exec(ctx, query,
+	columns := []string{"title", "description", "status", "priority", "due_date", "updated_at"}
+	args := []interface{}{
 		todo.ID().String(),
 		todo.Title().String(),
 		todo.Description(),
@@ -170,24 +747,265 @@ func (r *PostgresTodoRepository) Update(ctx context.Context, todo *domain.Todo)
 		todo.Priority().String(),
 		dueDate,
 		todo.UpdatedAt(),
-	)
+	}
+	if r.features.CompletedAt {
+		columns = append(columns, "completed_at")
+		args = append(args, todo.CompletedAt())
+	}
+	if r.features.Archived {
+		columns = append(columns, "archived_at")
+		args = append(args, todo.ArchivedAt())
+	} else if todo.IsArchived() {
+		return errors.New("updating archived todo: the archived_at column is not enabled")
+	}
 
-	if err != nil {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+2)
+	}
+	if r.features.Version {
+		assignments = append(assignments, "version = version + 1")
+	}
+	owned, args := r.owned(ctx, "user_id", args)
+	query := "UPDATE todos SET " + strings.Join(assignments, ", ") + " WHERE id = $1" + owned
+
+	if !r.features.Version {
+		result, err := db.Exec(ctx, query, args...)
+
+		if err != nil {
+			return fmt.Errorf("updating todo: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrTodoNotFound
+		}
+
+		return nil
+	}
+
+	if todo.Version() > 0 {
+		args = append(args, todo.Version())
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	var version int64
+	if err := db.QueryRow(ctx, query+" RETURNING version", args...).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.missingOrStale(ctx, db, todo)
+		}
 		return fmt.Errorf("updating todo: %w", err)
 	}
+	todo.AssignVersion(version)
 
-	if result.RowsAffected() == 0 {
+	return nil
+}
+
+// missingOrStale tells why a versioned update of todo changed no row:
+// ErrConcurrentModification if the todo still exists, ErrTodoNotFound
+// otherwise
+func (r *PostgresTodoRepository) missingOrStale(ctx context.Context, db execer, todo *domain.Todo) error {
+	if todo.Version() == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	owned, args := r.owned(ctx, "user_id", []interface{}{todo.ID().String()})
+	query := `SELECT EXISTS (SELECT 1 FROM todos WHERE id = $1` + owned + `)`
+
+	var exists bool
+	if err := db.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
+		return fmt.Errorf("checking todo version: %w", err)
+	}
+	if !exists {
 		return domain.ErrTodoNotFound
 	}
 
+	return domain.ErrConcurrentModification
+}
+
+// SaveMerge persists both sides of a merge in a single transaction: the
+// canonical todo and the cancelled duplicate with its merged_into reference
+func (r *PostgresTodoRepository) SaveMerge(ctx context.Context, canonical, duplicate *domain.Todo) error {
+	if !r.features.MergedInto || duplicate.MergedInto() == nil {
+		return errors.New("saving merge: merged_into is not enabled or not set")
+	}
+
+	tx, err := connOf(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning merge transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := r.update(ctx, tx, canonical); err != nil {
+		return fmt.Errorf("saving canonical todo: %w", err)
+	}
+
+	if err := r.update(ctx, tx, duplicate); err != nil {
+		return fmt.Errorf("saving duplicate todo: %w", err)
+	}
+
+	query := `UPDATE todos SET merged_into = $2 WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, duplicate.ID().String(), duplicate.MergedInto().String()); err != nil {
+		return fmt.Errorf("saving merged_into: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing merge: %w", err)
+	}
+
 	return nil
 }
 
+// SaveMove persists a todo moved to another owner in a single transaction:
+// the todo, its new owner and the owner of its history, so the new owner
+// reads its earlier versions too
+// The update is scoped to the owner of ctx, the previous one
+func (r *PostgresTodoRepository) SaveMove(ctx context.Context, todo *domain.Todo) error {
+	if !r.features.Owner {
+		return errors.New("saving move: the user_id column is not enabled")
+	}
+
+	tx, err := connOf(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning move transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := r.update(ctx, tx, todo); err != nil {
+		return fmt.Errorf("saving moved todo: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE todos SET user_id = $2 WHERE id = $1`, todo.ID().String(), todo.OwnerID()); err != nil {
+		return fmt.Errorf("saving owner: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE todo_history SET user_id = $2 WHERE todo_id = $1`, todo.ID().String(), todo.OwnerID()); err != nil {
+		return fmt.Errorf("moving history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing move: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMany updates todos in a single transaction, all or none
+func (r *PostgresTodoRepository) UpdateMany(ctx context.Context, todos []*domain.Todo) error {
+	tx, err := connOf(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning batch update transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, todo := range todos {
+		if err := r.update(ctx, tx, todo); err != nil {
+			return fmt.Errorf("updating todo %s: %w", todo.ID(), err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing batch update: %w", err)
+	}
+
+	return nil
+}
+
+// SaveMany saves new todos in a single transaction, all or none
+func (r *PostgresTodoRepository) SaveMany(ctx context.Context, todos []*domain.Todo) error {
+	tx, err := connOf(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning batch insert transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, todo := range todos {
+		if err := r.insert(ctx, tx, todo); err != nil {
+			return fmt.Errorf("inserting todo %s: %w", todo.ID(), err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing batch insert: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteMatching completes at most limit pending or in progress,
+// unarchived todos matching filter, oldest first, in a single statement, and
+// returns them as completed at the given time
+// Like listings, it is scoped to the owner of ctx and leaves canaries out
+func (r *PostgresTodoRepository) CompleteMatching(ctx context.Context, filter ports.CompletionFilter, at time.Time, limit int) ([]*domain.Todo, error) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{at})
+	where := "status IN ('pending', 'in_progress')" + r.listable() + owned
+	if r.features.Archived {
+		where += " AND archived_at IS NULL"
+	}
+
+	if filter.Status != nil {
+		args = append(args, filter.Status.String())
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	if filter.Priority != nil {
+		args = append(args, filter.Priority.String())
+		where += fmt.Sprintf(" AND priority = $%d", len(args))
+	}
+
+	if filter.DueBefore != nil {
+		args = append(args, *filter.DueBefore)
+		where += fmt.Sprintf(" AND due_date < $%d", len(args))
+	}
+
+	if len(filter.Exclude) > 0 {
+		excluded := make([]string, len(filter.Exclude))
+		for i, id := range filter.Exclude {
+			excluded[i] = id.String()
+		}
+		args = append(args, excluded)
+		where += fmt.Sprintf(" AND id <> ALL($%d::uuid[])", len(args))
+	}
+
+	assignments := "status = 'completed', updated_at = $1"
+	if r.features.CompletedAt {
+		assignments += ", completed_at = $1"
+	}
+	if r.features.Version {
+		assignments += ", version = version + 1"
+	}
+
+	args = append(args, limit)
+	query := `
+		UPDATE todos SET ` + assignments + `
+		WHERE id IN (
+			SELECT id FROM todos
+			WHERE ` + where + `
+			ORDER BY created_at, id
+			LIMIT ` + fmt.Sprintf("$%d", len(args)) + `
+			FOR UPDATE
+		)
+		RETURNING ` + r.selectColumns()
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("completing todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting completed todos: %w", err)
+	}
+
+	return todos, nil
+}
+
 // Delete removes a todo from the database
 func (r *PostgresTodoRepository) Delete(ctx context.Context, id domain.TodoID) error {
-	query := `DELETE FROM todos WHERE id = $1`
+	owned, args := r.owned(ctx, "user_id", []interface{}{id.String()})
+	query := `DELETE FROM todos WHERE id = $1` + owned
 
-	result, err := r.pool.Exec(ctx, query, id.String())
+	result, err := connOf(ctx, r.pool).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("deleting todo: %w", err)
 	}
@@ -199,14 +1017,172 @@ func (r *PostgresTodoRepository) Delete(ctx context.Context, id domain.TodoID) e
 	return nil
 }
 
+// FindPurgeable returns at most limit todos matching filter, least recently
+// updated first
+func (r *PostgresTodoRepository) FindPurgeable(ctx context.Context, filter ports.PurgeFilter, limit int) ([]*domain.Todo, error) {
+	query := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos
+		WHERE 1=1
+	`
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Status.String())
+		argIndex++
+	}
+
+	if filter.Priority != nil {
+		query += fmt.Sprintf(" AND priority = $%d", argIndex)
+		args = append(args, filter.Priority.String())
+		argIndex++
+	}
+
+	if filter.UpdatedBefore != nil {
+		query += fmt.Sprintf(" AND updated_at < $%d", argIndex)
+		args = append(args, *filter.UpdatedBefore)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY updated_at, id LIMIT $%d", argIndex)
+	args = append(args, limit)
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying purgeable todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting purgeable todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// FindDueBetween returns at most limit todos neither completed nor cancelled
+// whose due date is after from and at or before to, earliest due first
+func (r *PostgresTodoRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	query := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos
+		WHERE due_date > $1 AND due_date <= $2
+			AND status NOT IN ('completed', 'cancelled')
+		ORDER BY due_date, id
+		LIMIT $3
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying due todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting due todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// FindDatedBetween returns at most limit unarchived todos, whatever their
+// status, whose due date is at or after from and before to, earliest due
+// first
+// Like listings, it is scoped to the owner of ctx and leaves canaries out
+func (r *PostgresTodoRepository) FindDatedBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	owned, args := r.owned(ctx, "user_id", []interface{}{from, to})
+	where := "due_date >= $1 AND due_date < $2" + r.listable() + owned
+	if r.features.Archived {
+		where += " AND archived_at IS NULL"
+	}
+
+	args = append(args, limit)
+	query := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos
+		WHERE ` + where + `
+		ORDER BY due_date, id
+		LIMIT $` + fmt.Sprint(len(args)) + `
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying dated todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting dated todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// FindCanaries returns every canary todo, newest first
+func (r *PostgresTodoRepository) FindCanaries(ctx context.Context) ([]*domain.Todo, error) {
+	if !r.features.Canary {
+		return nil, errors.New("finding canaries: the canary column is not enabled")
+	}
+
+	query := `
+		SELECT ` + r.selectColumns() + `
+		FROM todos
+		WHERE canary
+		ORDER BY created_at DESC
+	`
+
+	rows, err := connOf(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying canaries: %w", err)
+	}
+	defer rows.Close()
+
+	todos, err := pgx.CollectRows(rows, todoRowScanner)
+	if err != nil {
+		return nil, fmt.Errorf("collecting canaries: %w", err)
+	}
+
+	return todos, nil
+}
+
+// DeleteMany deletes the todos with the given IDs in a single statement
+func (r *PostgresTodoRepository) DeleteMany(ctx context.Context, ids []domain.TodoID) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	result, err := connOf(ctx, r.pool).Exec(ctx, `DELETE FROM todos WHERE id = ANY($1::uuid[])`, values)
+	if err != nil {
+		return 0, fmt.Errorf("deleting todos: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
 // todoRowScanner is a pgx.RowToFunc that scans a row and reconstitutes a domain Todo
 func todoRowScanner(row pgx.CollectableRow) (*domain.Todo, error) {
-	// Use pgx.RowToStructByName to automatically map columns to struct fields
-	dbRow, err := pgx.RowToStructByName[todoRow](row)
+	// Use pgx.RowToStructByNameLax to map columns to struct fields; optional
+	// columns disabled by schema features are simply left empty
+	dbRow, err := pgx.RowToStructByNameLax[todoRow](row)
 	if err != nil {
 		return nil, fmt.Errorf("scanning row: %w", err)
 	}
 
+	return dbRow.todo()
+}
+
+// todo reconstitutes the domain Todo of the row
+func (dbRow todoRow) todo() (*domain.Todo, error) {
 	// Parse domain ID
 	todoID, err := domain.ParseTodoID(dbRow.ID)
 	if err != nil {
@@ -229,22 +1205,11 @@ func todoRowScanner(row pgx.CollectableRow) (*domain.Todo, error) {
 		return nil, fmt.Errorf("invalid priority: %w", err)
 	}
 
+	// Stored due dates may have passed since they were set
 	var domainDueDate *domain.DueDate
 	if dbRow.DueDate != nil {
-		// For reconstitution, we don't validate that due date is in the future
-		// since it may have passed since creation
-		dd := domain.DueDate{}
-		// We need to use reflection or create a helper method
-		// For now, we'll just store the time directly if it's past
-		// In production, you might want to add a reconstitution method to DueDate
-		if dbRow.DueDate.After(time.Now()) {
-			dd, err = domain.NewDueDate(*dbRow.DueDate)
-			if err == nil {
-				domainDueDate = &dd
-			}
-		}
-		// If due date is in the past, we'll set it to nil for now
-		// A better approach would be to have a separate reconstitution method
+		dd := domain.ReconstituteDueDate(*dbRow.DueDate)
+		domainDueDate = &dd
 	}
 
 	// Reconstitute the aggregate
@@ -257,8 +1222,36 @@ func todoRowScanner(row pgx.CollectableRow) (*domain.Todo, error) {
 		domainDueDate,
 		dbRow.CreatedAt,
 		dbRow.UpdatedAt,
-		nil, // completedAt - we don't track this in current schema
+		dbRow.CompletedAt,
 	)
 
+	if dbRow.ShortCode != nil {
+		todo.AssignShortCode(domain.ShortCode(*dbRow.ShortCode))
+	}
+
+	if dbRow.MergedInto != nil {
+		canonicalID, err := domain.ParseTodoID(*dbRow.MergedInto)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merged_into: %w", err)
+		}
+		todo.RestoreMergedInto(canonicalID)
+	}
+
+	if dbRow.Canary != nil && *dbRow.Canary {
+		todo.RestoreCanary()
+	}
+
+	if dbRow.UserID != nil {
+		todo.AssignOwner(*dbRow.UserID)
+	}
+
+	if dbRow.ArchivedAt != nil {
+		todo.RestoreArchived(*dbRow.ArchivedAt)
+	}
+
+	if dbRow.Version != nil {
+		todo.AssignVersion(*dbRow.Version)
+	}
+
 	return todo, nil
 }
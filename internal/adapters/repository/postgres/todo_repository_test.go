@@ -5,6 +5,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
 	"github.com/pivaldi/mmw/todo/internal/ports"
 )
 
@@ -26,7 +28,7 @@ var testDB *pgxpool.Pool
 func setupTestDB(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 
-	ctx := context.Background()
+	ctx := ports.ContextWithSystemAccess(context.Background())
 
 	// Create PostgreSQL container
 	postgresContainer, err := postgres.Run(ctx,
@@ -123,17 +125,17 @@ func createTestTodoWithDueDate() *domain.Todo {
 
 func TestPostgresTodRepository_Save_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	todo := createTestTodo()
-	err := repo.Save(context.Background(), todo)
+	err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo)
 
 	if err != nil {
 		t.Fatalf("Save() unexpected error: %v", err)
 	}
 
 	// Verify todo was saved
-	saved, err := repo.FindByID(context.Background(), todo.ID())
+	saved, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != nil {
 		t.Fatalf("FindByID() unexpected error: %v", err)
 	}
@@ -149,17 +151,17 @@ func TestPostgresTodRepository_Save_Success(t *testing.T) {
 
 func TestPostgresTodRepository_Save_WithDueDate_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	todo := createTestTodoWithDueDate()
-	err := repo.Save(context.Background(), todo)
+	err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo)
 
 	if err != nil {
 		t.Fatalf("Save() unexpected error: %v", err)
 	}
 
 	// Verify todo was saved with due date
-	saved, err := repo.FindByID(context.Background(), todo.ID())
+	saved, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != nil {
 		t.Fatalf("FindByID() unexpected error: %v", err)
 	}
@@ -182,10 +184,10 @@ func TestPostgresTodRepository_Save_WithDueDate_Success(t *testing.T) {
 
 func TestPostgresTodRepository_FindByID_NotFound_ReturnsError(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	nonExistentID := domain.NewTodoID()
-	_, err := repo.FindByID(context.Background(), nonExistentID)
+	_, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), nonExistentID)
 
 	if err == nil {
 		t.Error("FindByID() expected error for non-existent todo, got nil")
@@ -198,25 +200,25 @@ func TestPostgresTodRepository_FindByID_NotFound_ReturnsError(t *testing.T) {
 
 func TestPostgresTodRepository_FindAll_NoFilters_ReturnsAll(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create and save multiple todos
 	todo1 := createTestTodo()
 	todo2 := createTestTodo()
 	todo3 := createTestTodo()
 
-	if err := repo.Save(context.Background(), todo1); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo1); err != nil {
 		t.Fatalf("Save() todo1 failed: %v", err)
 	}
-	if err := repo.Save(context.Background(), todo2); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo2); err != nil {
 		t.Fatalf("Save() todo2 failed: %v", err)
 	}
-	if err := repo.Save(context.Background(), todo3); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo3); err != nil {
 		t.Fatalf("Save() todo3 failed: %v", err)
 	}
 
 	// Find all
-	todos, err := repo.FindAll(context.Background(), ports.Filters{})
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{})
 
 	if err != nil {
 		t.Fatalf("FindAll() unexpected error: %v", err)
@@ -229,23 +231,23 @@ func TestPostgresTodRepository_FindAll_NoFilters_ReturnsAll(t *testing.T) {
 
 func TestPostgresTodRepository_FindAll_WithStatusFilter_FiltersCorrectly(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create todos with different statuses
 	todo1 := createTestTodo()
 	todo2 := createTestTodo()
 	todo2.Complete()
 
-	if err := repo.Save(context.Background(), todo1); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo1); err != nil {
 		t.Fatalf("Save() todo1 failed: %v", err)
 	}
-	if err := repo.Save(context.Background(), todo2); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo2); err != nil {
 		t.Fatalf("Save() todo2 failed: %v", err)
 	}
 
 	// Filter by pending status
 	pendingStatus := domain.StatusPending
-	todos, err := repo.FindAll(context.Background(), ports.Filters{
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{
 		Status: &pendingStatus,
 	})
 
@@ -264,7 +266,7 @@ func TestPostgresTodRepository_FindAll_WithStatusFilter_FiltersCorrectly(t *test
 
 func TestPostgresTodRepository_FindAll_WithPriorityFilter_FiltersCorrectly(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create todos with different priorities
 	title1, _ := domain.NewTaskTitle("Low Priority Todo")
@@ -273,16 +275,16 @@ func TestPostgresTodRepository_FindAll_WithPriorityFilter_FiltersCorrectly(t *te
 	title2, _ := domain.NewTaskTitle("High Priority Todo")
 	todo2 := domain.NewTodo(title2, "Description", domain.PriorityHigh, nil)
 
-	if err := repo.Save(context.Background(), todo1); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo1); err != nil {
 		t.Fatalf("Save() todo1 failed: %v", err)
 	}
-	if err := repo.Save(context.Background(), todo2); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo2); err != nil {
 		t.Fatalf("Save() todo2 failed: %v", err)
 	}
 
 	// Filter by high priority
 	highPriority := domain.PriorityHigh
-	todos, err := repo.FindAll(context.Background(), ports.Filters{
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{
 		Priority: &highPriority,
 	})
 
@@ -301,19 +303,19 @@ func TestPostgresTodRepository_FindAll_WithPriorityFilter_FiltersCorrectly(t *te
 
 func TestPostgresTodRepository_FindAll_WithLimit_LimitsResults(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create multiple todos
 	for i := 0; i < 5; i++ {
 		todo := createTestTodo()
-		if err := repo.Save(context.Background(), todo); err != nil {
+		if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 			t.Fatalf("Save() failed: %v", err)
 		}
 	}
 
 	// Query with limit
 	limit := 2
-	todos, err := repo.FindAll(context.Background(), ports.Filters{
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{
 		Limit: &limit,
 	})
 
@@ -328,14 +330,14 @@ func TestPostgresTodRepository_FindAll_WithLimit_LimitsResults(t *testing.T) {
 
 func TestPostgresTodRepository_FindAll_WithOffset_OffsetsResults(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create multiple todos
 	var createdIDs []domain.TodoID
 	for i := 0; i < 3; i++ {
 		todo := createTestTodo()
 		createdIDs = append(createdIDs, todo.ID())
-		if err := repo.Save(context.Background(), todo); err != nil {
+		if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 			t.Fatalf("Save() failed: %v", err)
 		}
 		// Small delay to ensure different created_at times
@@ -344,7 +346,7 @@ func TestPostgresTodRepository_FindAll_WithOffset_OffsetsResults(t *testing.T) {
 
 	// Query with offset
 	offset := 1
-	todos, err := repo.FindAll(context.Background(), ports.Filters{
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{
 		Offset: &offset,
 	})
 
@@ -357,13 +359,95 @@ func TestPostgresTodRepository_FindAll_WithOffset_OffsetsResults(t *testing.T) {
 	}
 }
 
+func TestPostgresTodRepository_FindAll_Sorted(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+
+	// A medium todo without due date, then an urgent one due later and a
+	// low one due sooner
+	undated := createTestTodo()
+	later, _ := domain.NewDueDate(time.Now().Add(48 * time.Hour))
+	sooner, _ := domain.NewDueDate(time.Now().Add(24 * time.Hour))
+	title, _ := domain.NewTaskTitle("Test Todo")
+	urgent := domain.NewTodo(title, "", domain.PriorityUrgent, &later)
+	low := domain.NewTodo(title, "", domain.PriorityLow, &sooner)
+	for _, todo := range []*domain.Todo{undated, urgent, low} {
+		if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		sortBy ports.SortField
+		order  ports.SortOrder
+		want   []domain.TodoID
+	}{
+		{ports.SortByDueDate, ports.SortAscending, []domain.TodoID{low.ID(), urgent.ID(), undated.ID()}},
+		{ports.SortByDueDate, ports.SortDescending, []domain.TodoID{urgent.ID(), low.ID(), undated.ID()}},
+		{ports.SortByPriority, ports.SortDescending, []domain.TodoID{urgent.ID(), undated.ID(), low.ID()}},
+	}
+
+	for _, tt := range tests {
+		todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{SortBy: tt.sortBy, SortOrder: tt.order})
+		if err != nil {
+			t.Fatalf("FindAll() unexpected error: %v", err)
+		}
+
+		if len(todos) != len(tt.want) {
+			t.Fatalf("FindAll() returned %d todos, want %d", len(todos), len(tt.want))
+		}
+		for i, todo := range todos {
+			if todo.ID() != tt.want[i] {
+				t.Errorf("FindAll() sorted by %s %s: todo %d = %s, want %s", tt.sortBy, tt.order, i, todo.ID(), tt.want[i])
+			}
+		}
+	}
+}
+
+func TestPostgresTodRepository_Count_IgnoresPaging(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool)
+
+	// Create pending todos and one completed todo
+	for i := 0; i < 3; i++ {
+		todo := createTestTodo()
+		if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	completed := createTestTodo()
+	if err := completed.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), completed); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Count with a status filter and paging
+	status := domain.StatusPending
+	limit, offset := 1, 1
+	count, err := repo.Count(ports.ContextWithSystemAccess(context.Background()), ports.Filters{
+		Status: &status,
+		Limit:  &limit,
+		Offset: &offset,
+	})
+
+	if err != nil {
+		t.Fatalf("Count() unexpected error: %v", err)
+	}
+
+	if count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+}
+
 func TestPostgresTodRepository_Update_ExistingTodo_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Save initial todo
 	todo := createTestTodo()
-	if err := repo.Save(context.Background(), todo); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 		t.Fatalf("Save() failed: %v", err)
 	}
 
@@ -371,14 +455,14 @@ func TestPostgresTodRepository_Update_ExistingTodo_Success(t *testing.T) {
 	newTitle, _ := domain.NewTaskTitle("Updated Title")
 	todo.UpdateTitle(newTitle)
 
-	err := repo.Update(context.Background(), todo)
+	err := repo.Update(ports.ContextWithSystemAccess(context.Background()), todo)
 
 	if err != nil {
 		t.Fatalf("Update() unexpected error: %v", err)
 	}
 
 	// Verify update
-	updated, err := repo.FindByID(context.Background(), todo.ID())
+	updated, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != nil {
 		t.Fatalf("FindByID() unexpected error: %v", err)
 	}
@@ -390,11 +474,11 @@ func TestPostgresTodRepository_Update_ExistingTodo_Success(t *testing.T) {
 
 func TestPostgresTodRepository_Update_NonExistentTodo_ReturnsError(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Try to update non-existent todo
 	todo := createTestTodo()
-	err := repo.Update(context.Background(), todo)
+	err := repo.Update(ports.ContextWithSystemAccess(context.Background()), todo)
 
 	if err == nil {
 		t.Error("Update() expected error for non-existent todo, got nil")
@@ -407,25 +491,25 @@ func TestPostgresTodRepository_Update_NonExistentTodo_ReturnsError(t *testing.T)
 
 func TestPostgresTodRepository_Update_CompleteTodo_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Save initial todo
 	todo := createTestTodo()
-	if err := repo.Save(context.Background(), todo); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 		t.Fatalf("Save() failed: %v", err)
 	}
 
 	// Complete the todo
 	todo.Complete()
 
-	err := repo.Update(context.Background(), todo)
+	err := repo.Update(ports.ContextWithSystemAccess(context.Background()), todo)
 
 	if err != nil {
 		t.Fatalf("Update() unexpected error: %v", err)
 	}
 
 	// Verify status changed
-	updated, err := repo.FindByID(context.Background(), todo.ID())
+	updated, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != nil {
 		t.Fatalf("FindByID() unexpected error: %v", err)
 	}
@@ -437,23 +521,23 @@ func TestPostgresTodRepository_Update_CompleteTodo_Success(t *testing.T) {
 
 func TestPostgresTodRepository_Delete_ExistingTodo_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Save todo
 	todo := createTestTodo()
-	if err := repo.Save(context.Background(), todo); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 		t.Fatalf("Save() failed: %v", err)
 	}
 
 	// Delete todo
-	err := repo.Delete(context.Background(), todo.ID())
+	err := repo.Delete(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 
 	if err != nil {
 		t.Fatalf("Delete() unexpected error: %v", err)
 	}
 
 	// Verify todo is deleted
-	_, err = repo.FindByID(context.Background(), todo.ID())
+	_, err = repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != domain.ErrTodoNotFound {
 		t.Errorf("FindByID() after delete error = %v, want %v", err, domain.ErrTodoNotFound)
 	}
@@ -461,11 +545,11 @@ func TestPostgresTodRepository_Delete_ExistingTodo_Success(t *testing.T) {
 
 func TestPostgresTodRepository_Delete_NonExistentTodo_ReturnsError(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Try to delete non-existent todo
 	nonExistentID := domain.NewTodoID()
-	err := repo.Delete(context.Background(), nonExistentID)
+	err := repo.Delete(ports.ContextWithSystemAccess(context.Background()), nonExistentID)
 
 	if err == nil {
 		t.Error("Delete() expected error for non-existent todo, got nil")
@@ -478,7 +562,7 @@ func TestPostgresTodRepository_Delete_NonExistentTodo_ReturnsError(t *testing.T)
 
 func TestPostgresTodRepository_Reconstitution_PreservesAllFields(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create todo with all fields set
 	title, _ := domain.NewTaskTitle("Complete Todo")
@@ -488,12 +572,12 @@ func TestPostgresTodRepository_Reconstitution_PreservesAllFields(t *testing.T) {
 	todo.Complete()
 
 	// Save
-	if err := repo.Save(context.Background(), todo); err != nil {
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), todo); err != nil {
 		t.Fatalf("Save() failed: %v", err)
 	}
 
 	// Retrieve
-	retrieved, err := repo.FindByID(context.Background(), todo.ID())
+	retrieved, err := repo.FindByID(ports.ContextWithSystemAccess(context.Background()), todo.ID())
 	if err != nil {
 		t.Fatalf("FindByID() unexpected error: %v", err)
 	}
@@ -518,7 +602,7 @@ func TestPostgresTodRepository_Reconstitution_PreservesAllFields(t *testing.T) {
 
 func TestPostgresTodRepository_ConcurrentSaves_Success(t *testing.T) {
 	pool := setupTestDB(t)
-	repo := NewPostgresTodRepository(pool)
+	repo := NewPostgresTodoRepository(pool)
 
 	// Create multiple todos concurrently
 	const numTodos = 10
@@ -527,7 +611,7 @@ func TestPostgresTodRepository_ConcurrentSaves_Success(t *testing.T) {
 	for i := 0; i < numTodos; i++ {
 		go func() {
 			todo := createTestTodo()
-			errChan <- repo.Save(context.Background(), todo)
+			errChan <- repo.Save(ports.ContextWithSystemAccess(context.Background()), todo)
 		}()
 	}
 
@@ -539,7 +623,7 @@ func TestPostgresTodRepository_ConcurrentSaves_Success(t *testing.T) {
 	}
 
 	// Verify count
-	todos, err := repo.FindAll(context.Background(), ports.Filters{})
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{})
 	if err != nil {
 		t.Fatalf("FindAll() unexpected error: %v", err)
 	}
@@ -548,3 +632,1080 @@ func TestPostgresTodRepository_ConcurrentSaves_Success(t *testing.T) {
 		t.Errorf("Expected %d todos, got %d", numTodos, len(todos))
 	}
 }
+
+func TestPostgresTodoRepository_SchemaFeatures_PersistsCompletedAt(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	features, err := ResolveSchemaFeatures(ctx, pool, SchemaFeaturesAuto)
+	if err != nil {
+		t.Fatalf("ResolveSchemaFeatures() unexpected error: %v", err)
+	}
+	if !features.CompletedAt {
+		t.Fatal("ResolveSchemaFeatures() expected completed_at to be detected")
+	}
+
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(features))
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if err := todo.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+
+	if found.CompletedAt() == nil {
+		t.Error("FindByID() expected completed_at to be persisted")
+	}
+}
+
+func TestPostgresTodoRepository_SchemaFeatures_LegacySchema(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	// Simulate the schema before migration 000004
+	if _, err := pool.Exec(ctx, `ALTER TABLE todos DROP COLUMN completed_at`); err != nil {
+		t.Fatalf("dropping completed_at failed: %v", err)
+	}
+
+	if _, err := ResolveSchemaFeatures(ctx, pool, "completed_at"); err == nil {
+		t.Error("ResolveSchemaFeatures() expected error for a missing column")
+	}
+
+	features, err := ResolveSchemaFeatures(ctx, pool, SchemaFeaturesAuto)
+	if err != nil {
+		t.Fatalf("ResolveSchemaFeatures() unexpected error: %v", err)
+	}
+
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(features))
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, todo.ID()); err != nil {
+		t.Errorf("FindByID() unexpected error: %v", err)
+	}
+}
+
+func TestPreflight_MigrationStatusAndIndexes(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	// The test schema is applied without golang-migrate
+	status, err := ReadMigrationStatus(ctx, pool)
+	if err != nil || status.Applied {
+		t.Fatalf("ReadMigrationStatus() = %+v, %v, want no migration applied", status, err)
+	}
+
+	if _, err := pool.Exec(ctx, `CREATE TABLE schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		t.Fatalf("creating schema_migrations failed: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO schema_migrations VALUES ($1, true)`, LatestMigration); err != nil {
+		t.Fatalf("recording migration failed: %v", err)
+	}
+	status, err = ReadMigrationStatus(ctx, pool)
+	if err != nil || status != (MigrationStatus{Applied: true, Version: LatestMigration, Dirty: true}) {
+		t.Errorf("ReadMigrationStatus() = %+v, %v, want dirty version %d", status, err, LatestMigration)
+	}
+
+	missing, err := MissingIndexes(ctx, pool, LatestMigration)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("MissingIndexes() = %+v, %v, want none", missing, err)
+	}
+	if _, err := pool.Exec(ctx, `DROP INDEX idx_todos_search`); err != nil {
+		t.Fatalf("dropping idx_todos_search failed: %v", err)
+	}
+	missing, err = MissingIndexes(ctx, pool, LatestMigration)
+	if err != nil || len(missing) != 1 || missing[0] != (MissingIndex{Name: "idx_todos_search", Migration: 15}) {
+		t.Errorf("MissingIndexes() = %+v, %v, want idx_todos_search of migration 15", missing, err)
+	}
+
+	skew, err := DatabaseClockSkew(ctx, pool)
+	if err != nil || skew.Abs() > time.Minute {
+		t.Errorf("DatabaseClockSkew() = %v, %v, want the clocks of the same host in step", skew, err)
+	}
+}
+
+func TestPostgresTodoRepository_ShortCode_AssignedAndResolved(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{ShortCode: true}))
+
+	first := createTestTodo()
+	second := createTestTodo()
+	for _, todo := range []*domain.Todo{first, second} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	if first.ShortCode().IsZero() || second.ShortCode() <= first.ShortCode() {
+		t.Fatalf("Short codes = %v, %v, want increasing codes", first.ShortCode(), second.ShortCode())
+	}
+
+	id, err := repo.FindIDByShortCode(ctx, second.ShortCode())
+	if err != nil {
+		t.Fatalf("FindIDByShortCode() unexpected error: %v", err)
+	}
+	if id != second.ID() {
+		t.Errorf("FindIDByShortCode() = %v, want %v", id, second.ID())
+	}
+
+	found, err := repo.FindByID(ctx, first.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.ShortCode() != first.ShortCode() {
+		t.Errorf("ShortCode = %v, want %v", found.ShortCode(), first.ShortCode())
+	}
+
+	if _, err := repo.FindIDByShortCode(ctx, 999999); err != domain.ErrTodoNotFound {
+		t.Errorf("FindIDByShortCode() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestPostgresTodoRepository_Suggest_PrefixFirst(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	for _, title := range []string{"Call the plumber", "Plumbing invoice", "Buy groceries", "50% off plumbing"} {
+		taskTitle, _ := domain.NewTaskTitle(title)
+		if err := repo.Save(ctx, domain.NewTodo(taskTitle, "", domain.PriorityLow, nil)); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	todos, err := repo.Suggest(ctx, "plumb", 5)
+	if err != nil {
+		t.Fatalf("Suggest() unexpected error: %v", err)
+	}
+
+	if len(todos) != 3 {
+		t.Fatalf("Suggest() returned %d todos, want 3", len(todos))
+	}
+
+	if todos[0].Title().String() != "Plumbing invoice" {
+		t.Errorf("First suggestion = %q, want the prefix match", todos[0].Title())
+	}
+
+	wildcard, err := repo.Suggest(ctx, "50%", 5)
+	if err != nil {
+		t.Fatalf("Suggest() unexpected error: %v", err)
+	}
+	if len(wildcard) != 1 {
+		t.Errorf("Suggest(%q) returned %d todos, want 1 (wildcards escaped)", "50%", len(wildcard))
+	}
+}
+
+func TestPostgresTodoRepository_Search_RanksTitlesFirst(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	todos := []struct{ title, description string }{
+		{"Call the plumber", "About the leaking invoices"},
+		{"Pay invoices", "Before the end of the month"},
+		{"Buy groceries", ""},
+	}
+	for _, todo := range todos {
+		taskTitle, _ := domain.NewTaskTitle(todo.title)
+		if err := repo.Save(ctx, domain.NewTodo(taskTitle, todo.description, domain.PriorityLow, nil)); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	results, err := repo.Search(ctx, "invoice", 10, 0)
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search() returned %d todos, want 2", len(results))
+	}
+	if results[0].Title().String() != "Pay invoices" {
+		t.Errorf("First result = %q, want the title match", results[0].Title())
+	}
+
+	page, err := repo.Search(ctx, "invoice", 10, 1)
+	if err != nil {
+		t.Fatalf("Search() with offset unexpected error: %v", err)
+	}
+	if len(page) != 1 || page[0].Title().String() != "Call the plumber" {
+		t.Errorf("Search() second page = %v, want the description match", page)
+	}
+
+	excluded, err := repo.Search(ctx, "invoice -plumber", 10, 0)
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if len(excluded) != 1 {
+		t.Errorf("Search() with exclusion returned %d todos, want 1", len(excluded))
+	}
+}
+
+func TestPostgresTodoRepository_Archived_Filter(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Archived: true}))
+
+	archived, kept := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{archived, kept} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	archived.Archive()
+	if err := repo.Update(ctx, archived); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, archived.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if !found.IsArchived() {
+		t.Error("FindByID() lost the archival")
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		archived *bool
+		want     int
+	}{
+		{"any", nil, 2},
+		{"archived", &yes, 1},
+		{"unarchived", &no, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := repo.Count(ctx, ports.Filters{Archived: tt.archived})
+			if err != nil {
+				t.Fatalf("Count() unexpected error: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("Count() = %d, want %d", count, tt.want)
+			}
+		})
+	}
+
+	legacy := NewPostgresTodoRepository(pool)
+	if err := legacy.Update(ctx, archived); err == nil {
+		t.Error("Update() of an archived todo without the archived_at column succeeded")
+	}
+}
+
+func TestPostgresTodoRepository_Canary_HiddenFromListings(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Canary: true}))
+
+	title, _ := domain.NewTaskTitle("Payroll credentials")
+	canary := domain.NewCanaryTodo(title, "Decoy")
+	regular := createTestTodo()
+	for _, todo := range []*domain.Todo{canary, regular} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	found, err := repo.FindByID(ctx, canary.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if !found.IsCanary() {
+		t.Error("FindByID() lost the canary flag")
+	}
+
+	listed, err := repo.FindAll(ctx, ports.Filters{})
+	if err != nil {
+		t.Fatalf("FindAll() unexpected error: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID() != regular.ID() {
+		t.Errorf("FindAll() = %d todos, want only the regular todo", len(listed))
+	}
+
+	suggested, err := repo.Suggest(ctx, "payroll", 5)
+	if err != nil {
+		t.Fatalf("Suggest() unexpected error: %v", err)
+	}
+	if len(suggested) != 0 {
+		t.Errorf("Suggest() returned %d todos, want no canary", len(suggested))
+	}
+
+	canaries, err := repo.FindCanaries(ctx)
+	if err != nil {
+		t.Fatalf("FindCanaries() unexpected error: %v", err)
+	}
+	if len(canaries) != 1 || canaries[0].ID() != canary.ID() {
+		t.Errorf("FindCanaries() = %v, want only %v", canaries, canary.ID())
+	}
+
+	legacy := NewPostgresTodoRepository(pool)
+	if err := legacy.Save(ctx, domain.NewCanaryTodo(title, "")); err == nil {
+		t.Error("Save() of a canary without the canary column succeeded")
+	}
+}
+
+func TestPostgresTodoRepository_Owner_ScopesQueries(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Owner: true}))
+	alice := ports.ContextWithOwner(context.Background(), "alice")
+	bob := ports.ContextWithOwner(context.Background(), "bob")
+
+	owned := createTestTodo()
+	owned.AssignOwner("alice")
+	if err := repo.Save(alice, owned); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	found, err := repo.FindByID(alice, owned.ID())
+	if err != nil {
+		t.Fatalf("FindByID() by the owner unexpected error: %v", err)
+	}
+	if found.OwnerID() != "alice" {
+		t.Errorf("OwnerID() = %q, want alice", found.OwnerID())
+	}
+
+	// Other users neither see nor change the todo
+	if _, err := repo.FindByID(bob, owned.ID()); err != domain.ErrTodoNotFound {
+		t.Errorf("FindByID() by another user error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if count, err := repo.Count(bob, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() by another user = %d, %v, want 0", count, err)
+	}
+	if err := repo.Update(bob, owned); err != domain.ErrTodoNotFound {
+		t.Errorf("Update() by another user error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Delete(bob, owned.ID()); err != domain.ErrTodoNotFound {
+		t.Errorf("Delete() by another user error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	// Requests without a user see nothing, not even the unowned todos
+	unowned := createTestTodo()
+	if err := repo.Save(ports.ContextWithSystemAccess(context.Background()), unowned); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	anonymous := context.Background()
+	for _, todo := range []*domain.Todo{owned, unowned} {
+		if _, err := repo.FindByID(anonymous, todo.ID()); err != domain.ErrTodoNotFound {
+			t.Errorf("FindByID() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Update(anonymous, todo); err != domain.ErrTodoNotFound {
+			t.Errorf("Update() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Delete(anonymous, todo.ID()); err != domain.ErrTodoNotFound {
+			t.Errorf("Delete() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+	}
+	if todos, err := repo.FindAll(anonymous, ports.Filters{}); err != nil || len(todos) != 0 {
+		t.Errorf("FindAll() anonymous = %d todos, %v, want none", len(todos), err)
+	}
+	if count, err := repo.Count(anonymous, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() anonymous = %d, %v, want 0", count, err)
+	}
+
+	// System access sees every todo
+	todos, err := repo.FindAll(ports.ContextWithSystemAccess(context.Background()), ports.Filters{})
+	if err != nil {
+		t.Fatalf("FindAll() unexpected error: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Errorf("FindAll() with system access returned %d todos, want 2", len(todos))
+	}
+
+	legacy := NewPostgresTodoRepository(pool)
+	if err := legacy.Save(ports.ContextWithSystemAccess(context.Background()), owned); err == nil {
+		t.Error("Save() of an owned todo without the user_id column succeeded")
+	}
+}
+
+func TestPostgresTodoRepository_FindAsOf_ReadsPastVersions(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	todo := createTestTodo()
+	beforeCreate := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	afterCreate := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	newTitle, _ := domain.NewTaskTitle("Renamed")
+	if err := todo.UpdateTitle(newTitle); err != nil {
+		t.Fatalf("UpdateTitle() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	afterUpdate := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	if _, err := repo.FindAsOf(ctx, todo.ID(), beforeCreate); err != domain.ErrTodoNotFound {
+		t.Errorf("FindAsOf() before creation error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	original, err := repo.FindAsOf(ctx, todo.ID(), afterCreate)
+	if err != nil {
+		t.Fatalf("FindAsOf() unexpected error: %v", err)
+	}
+	if original.Title().String() != "Test Todo" {
+		t.Errorf("Title as of creation = %q, want %q", original.Title(), "Test Todo")
+	}
+
+	renamed, err := repo.FindAsOf(ctx, todo.ID(), afterUpdate)
+	if err != nil {
+		t.Fatalf("FindAsOf() unexpected error: %v", err)
+	}
+	if renamed.Title().String() != "Renamed" {
+		t.Errorf("Title as of update = %q, want %q", renamed.Title(), "Renamed")
+	}
+
+	if _, err := repo.FindAsOf(ctx, todo.ID(), time.Now()); err != domain.ErrTodoNotFound {
+		t.Errorf("FindAsOf() after deletion error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestPostgresTodoRepository_FindVersion(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	original := todo.UpdatedAt()
+	time.Sleep(10 * time.Millisecond)
+
+	newTitle, _ := domain.NewTaskTitle("Renamed")
+	if err := todo.UpdateTitle(newTitle); err != nil {
+		t.Fatalf("UpdateTitle() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	// The in-memory timestamp is more precise than the stored one
+	version, err := repo.FindVersion(ctx, todo.ID(), original)
+	if err != nil {
+		t.Fatalf("FindVersion() unexpected error: %v", err)
+	}
+	if version.Title().String() != "Test Todo" {
+		t.Errorf("Title of the first version = %q, want %q", version.Title(), "Test Todo")
+	}
+
+	if _, err := repo.FindVersion(ctx, todo.ID(), original.Add(-time.Hour)); err != domain.ErrTodoNotFound {
+		t.Errorf("FindVersion() of an unknown version error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestPostgresTodoRepository_ListActivity(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	newTitle, _ := domain.NewTaskTitle("Renamed")
+	if err := todo.UpdateTitle(newTitle); err != nil {
+		t.Fatalf("UpdateTitle() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if err := todo.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	events, err := repo.ListActivity(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+
+	want := []ports.ActivityEventKind{ports.ActivityDeleted, ports.ActivityCompleted, ports.ActivityUpdated, ports.ActivityCreated}
+	if len(events) != len(want) {
+		t.Fatalf("ListActivity() = %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Kind != want[i] || event.TodoID != todo.ID() {
+			t.Errorf("event %d = %s of %v, want %s of %v", i, event.Kind, event.TodoID, want[i], todo.ID())
+		}
+	}
+
+	// Pages continue below the last sequence number
+	older, err := repo.ListActivity(ctx, events[1].Seq, 10)
+	if err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+	if len(older) != 2 || older[0].Kind != ports.ActivityUpdated || older[0].Title != "Renamed" {
+		t.Errorf("ListActivity(before) = %+v, want the update and creation", older)
+	}
+}
+
+func TestPostgresTodoRepository_ListChangedSince(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	kept, deleted := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{kept, deleted} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	if err := kept.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Update(ctx, kept); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if err := repo.Delete(ctx, deleted.ID()); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	marks, err := repo.ListChangedSince(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("ListChangedSince() unexpected error: %v", err)
+	}
+	if len(marks) != 2 {
+		t.Fatalf("ListChangedSince() = %+v, want each todo once", marks)
+	}
+	if marks[0].TodoID != kept.ID() || marks[0].Deleted {
+		t.Errorf("first mark = %+v, want the completion of %v", marks[0], kept.ID())
+	}
+	if marks[1].TodoID != deleted.ID() || !marks[1].Deleted || marks[1].Seq <= marks[0].Seq {
+		t.Errorf("second mark = %+v, want the later deletion of %v", marks[1], deleted.ID())
+	}
+
+	// Later syncs only see later changes
+	later, err := repo.ListChangedSince(ctx, marks[0].Seq, 10)
+	if err != nil {
+		t.Fatalf("ListChangedSince() unexpected error: %v", err)
+	}
+	if len(later) != 1 || later[0].TodoID != deleted.ID() {
+		t.Errorf("ListChangedSince(after) = %+v, want the deletion", later)
+	}
+}
+
+func TestPostgresTodoRepository_Analytics(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{CompletedAt: true}))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	done := createTestTodo()
+	if err := done.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if err := repo.Save(ctx, done); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	overdue := createTestTodo()
+	if err := repo.Save(ctx, overdue); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE todos SET due_date = $1 WHERE id = $2", today.Add(-time.Hour), overdue.ID().String()); err != nil {
+		t.Fatalf("setting due date: %v", err)
+	}
+
+	series, err := repo.Analytics(ctx, ports.AnalyticsQuery{From: today.AddDate(0, 0, -1), Buckets: 2, BucketDays: 1})
+	if err != nil {
+		t.Fatalf("Analytics() unexpected error: %v", err)
+	}
+
+	if len(series.Buckets) != 2 {
+		t.Fatalf("Analytics() = %d buckets, want 2", len(series.Buckets))
+	}
+
+	yesterday, now := series.Buckets[0], series.Buckets[1]
+	if yesterday.Created != 0 || yesterday.Overdue != 0 {
+		t.Errorf("yesterday = %+v, want no activity", yesterday)
+	}
+	if now.Created != 2 || now.Completed != 1 || now.Overdue != 1 {
+		t.Errorf("today = %+v, want 2 created, 1 completed, 1 overdue", now)
+	}
+	if series.Completed != 1 {
+		t.Errorf("Completed = %d, want 1", series.Completed)
+	}
+
+	// Without the completed_at column, analytics are unavailable
+	legacy := NewPostgresTodoRepository(pool)
+	if _, err := legacy.Analytics(ctx, ports.AnalyticsQuery{From: today, Buckets: 1, BucketDays: 1}); err == nil {
+		t.Error("Analytics() without completed_at succeeded, want an error")
+	}
+}
+
+func TestPostgresTodoRepository_FindPurgeableAndDeleteMany(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	cancelled, pending := createTestTodo(), createTestTodo()
+	if err := cancelled.Cancel(); err != nil {
+		t.Fatalf("Cancel() failed: %v", err)
+	}
+	for _, todo := range []*domain.Todo{cancelled, pending} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	status := domain.StatusCancelled
+	future := time.Now().Add(time.Hour)
+	found, err := repo.FindPurgeable(ctx, ports.PurgeFilter{Status: &status, UpdatedBefore: &future}, 10)
+	if err != nil {
+		t.Fatalf("FindPurgeable() unexpected error: %v", err)
+	}
+	if len(found) != 1 || found[0].ID() != cancelled.ID() {
+		t.Fatalf("FindPurgeable() = %v, want only the cancelled todo", found)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if found, _ := repo.FindPurgeable(ctx, ports.PurgeFilter{UpdatedBefore: &past}, 10); len(found) != 0 {
+		t.Errorf("FindPurgeable() updated before an hour ago = %d todos, want 0", len(found))
+	}
+
+	deleted, err := repo.DeleteMany(ctx, []domain.TodoID{cancelled.ID(), domain.NewTodoID()})
+	if err != nil {
+		t.Fatalf("DeleteMany() unexpected error: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteMany() = %d, want 1", deleted)
+	}
+
+	if _, err := repo.FindByID(ctx, pending.ID()); err != nil {
+		t.Errorf("FindByID() of the unmatched todo error = %v, want nil", err)
+	}
+}
+
+func TestPostgresTodoRepository_FindDueBetween(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	title, _ := domain.NewTaskTitle("Due todo")
+	soon, _ := domain.NewDueDate(time.Now().Add(time.Hour))
+	later, _ := domain.NewDueDate(time.Now().Add(48 * time.Hour))
+	dueSoon := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	completed := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	if err := completed.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	dueLater := domain.NewTodo(title, "", domain.PriorityMedium, &later)
+	for _, todo := range []*domain.Todo{dueSoon, completed, dueLater, createTestTodo()} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	found, err := repo.FindDueBetween(ctx, time.Now(), time.Now().Add(2*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDueBetween() unexpected error: %v", err)
+	}
+	if len(found) != 1 || found[0].ID() != dueSoon.ID() {
+		t.Errorf("FindDueBetween() = %v, want only the open todo due soon", found)
+	}
+}
+
+func TestPostgresTodoRepository_FindDatedBetween(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Owner: true, Archived: true}))
+	alice := ports.ContextWithOwner(context.Background(), "alice")
+
+	title, _ := domain.NewTaskTitle("Dated todo")
+	soon, _ := domain.NewDueDate(time.Now().Add(time.Hour))
+	later, _ := domain.NewDueDate(time.Now().Add(48 * time.Hour))
+	completed := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	if err := completed.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	dueLater := domain.NewTodo(title, "", domain.PriorityMedium, &later)
+	archived := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	othersTodo := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	undated := createTestTodo()
+	for _, todo := range []*domain.Todo{completed, dueLater, archived, undated} {
+		todo.AssignOwner("alice")
+	}
+	othersTodo.AssignOwner("bob")
+	for _, todo := range []*domain.Todo{completed, dueLater, archived, othersTodo, undated} {
+		if err := repo.Save(alice, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	archived.Archive()
+	if err := repo.Update(alice, archived); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	found, err := repo.FindDatedBetween(alice, time.Now(), time.Now().Add(72*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDatedBetween() unexpected error: %v", err)
+	}
+	if len(found) != 2 || found[0].ID() != completed.ID() || found[1].ID() != dueLater.ID() {
+		t.Errorf("FindDatedBetween() = %v, want the completed todo then the one due later", found)
+	}
+
+	found, err = repo.FindDatedBetween(alice, time.Now(), time.Now().Add(72*time.Hour), 1)
+	if err != nil || len(found) != 1 {
+		t.Errorf("FindDatedBetween() with a limit of 1 = %v, %v, want one todo", found, err)
+	}
+}
+
+func TestPostgresTodoRepository_SaveMerge(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{CompletedAt: true, MergedInto: true}))
+
+	canonical, duplicate := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{canonical, duplicate} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	if err := duplicate.MergeInto(canonical); err != nil {
+		t.Fatalf("MergeInto() failed: %v", err)
+	}
+	if err := repo.SaveMerge(ctx, canonical, duplicate); err != nil {
+		t.Fatalf("SaveMerge() unexpected error: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, duplicate.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Status() != domain.StatusCancelled || found.MergedInto() == nil || *found.MergedInto() != canonical.ID() {
+		t.Errorf("Duplicate = %v merged into %v, want cancelled and merged into %v", found.Status(), found.MergedInto(), canonical.ID())
+	}
+
+	found, err = repo.FindByID(ctx, canonical.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Description() != canonical.Description() {
+		t.Errorf("Canonical description = %q, want %q", found.Description(), canonical.Description())
+	}
+}
+
+func TestPostgresTodoRepository_SaveMerge_RollsBackOnFailure(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{MergedInto: true}))
+
+	// The duplicate is never saved, so its update fails after the canonical one
+	canonical, duplicate := createTestTodo(), createTestTodo()
+	if err := repo.Save(ctx, canonical); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := duplicate.MergeInto(canonical); err != nil {
+		t.Fatalf("MergeInto() failed: %v", err)
+	}
+	if err := repo.SaveMerge(ctx, canonical, duplicate); err == nil {
+		t.Fatal("SaveMerge() expected an error")
+	}
+
+	found, err := repo.FindByID(ctx, canonical.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Description() != "Test description" {
+		t.Errorf("Canonical description = %q, want the pre-merge description", found.Description())
+	}
+}
+
+func TestPostgresTodoRepository_SaveMove(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Owner: true, Version: true}))
+	alice := ports.ContextWithOwner(context.Background(), "alice")
+	bob := ports.ContextWithOwner(context.Background(), "bob")
+
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	if err := repo.Save(alice, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	saved := time.Now()
+
+	if err := todo.MoveTo("bob"); err != nil {
+		t.Fatalf("MoveTo() failed: %v", err)
+	}
+	if err := repo.SaveMove(alice, todo); err != nil {
+		t.Fatalf("SaveMove() unexpected error: %v", err)
+	}
+
+	found, err := repo.FindByID(bob, todo.ID())
+	if err != nil || found.OwnerID() != "bob" {
+		t.Fatalf("FindByID() by the new owner = %v, %v, want the todo", found, err)
+	}
+	if _, err := repo.FindByID(alice, todo.ID()); err != domain.ErrTodoNotFound {
+		t.Errorf("FindByID() by the previous owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	// The new owner reads the versions saved before the move
+	if _, err := repo.FindAsOf(bob, todo.ID(), saved); err != nil {
+		t.Errorf("FindAsOf() by the new owner unexpected error: %v", err)
+	}
+
+	// A stale todo is not moved
+	stale := domain.ReconstituteTodo(todo.ID(), todo.Title(), "", domain.StatusPending, domain.PriorityLow, nil, todo.CreatedAt(), time.Now(), nil)
+	stale.AssignOwner("bob")
+	stale.AssignVersion(1)
+	if err := stale.MoveTo("carol"); err != nil {
+		t.Fatalf("MoveTo() failed: %v", err)
+	}
+	if err := repo.SaveMove(bob, stale); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("SaveMove() of a stale todo error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+	if found, err := repo.FindByID(bob, todo.ID()); err != nil || found.OwnerID() != "bob" {
+		t.Errorf("FindByID() after a refused move = %v, %v, want bob's todo", found, err)
+	}
+
+	legacy := NewPostgresTodoRepository(pool)
+	if err := legacy.SaveMove(ports.ContextWithSystemAccess(context.Background()), todo); err == nil {
+		t.Error("SaveMove() without the user_id column succeeded")
+	}
+}
+
+func TestPostgresTodoRepository_UpdateMany(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	saved, unsaved := createTestTodo(), createTestTodo()
+	if err := repo.Save(ctx, saved); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	for _, todo := range []*domain.Todo{saved, unsaved} {
+		if err := todo.UpdatePriority(domain.PriorityUrgent); err != nil {
+			t.Fatalf("UpdatePriority() failed: %v", err)
+		}
+	}
+
+	// The unsaved todo fails the batch, after the saved one was updated
+	if err := repo.UpdateMany(ctx, []*domain.Todo{saved, unsaved}); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Fatalf("UpdateMany() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	found, err := repo.FindByID(ctx, saved.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Priority() == domain.PriorityUrgent {
+		t.Error("Priority = urgent, want the batch rolled back")
+	}
+
+	if err := repo.UpdateMany(ctx, []*domain.Todo{saved}); err != nil {
+		t.Fatalf("UpdateMany() unexpected error: %v", err)
+	}
+	found, err = repo.FindByID(ctx, saved.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Priority() != domain.PriorityUrgent {
+		t.Errorf("Priority = %v, want urgent", found.Priority())
+	}
+}
+
+func TestPostgresTodoRepository_SaveMany(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{ShortCode: true}))
+
+	existing, first, second := createTestTodo(), createTestTodo(), createTestTodo()
+	if err := repo.Save(ctx, existing); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// A todo saved already fails the batch, after the first one was inserted
+	if err := repo.SaveMany(ctx, []*domain.Todo{first, existing}); err == nil {
+		t.Fatal("SaveMany() expected an error for a todo saved already")
+	}
+	if _, err := repo.FindByID(ctx, first.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() error = %v, want the batch rolled back", err)
+	}
+
+	if err := repo.SaveMany(ctx, []*domain.Todo{first, second}); err != nil {
+		t.Fatalf("SaveMany() unexpected error: %v", err)
+	}
+	for _, todo := range []*domain.Todo{first, second} {
+		if _, err := repo.FindByID(ctx, todo.ID()); err != nil {
+			t.Errorf("FindByID() unexpected error: %v", err)
+		}
+	}
+	if first.ShortCode().IsZero() || second.ShortCode() <= first.ShortCode() {
+		t.Errorf("Short codes = %v, %v, want increasing codes", first.ShortCode(), second.ShortCode())
+	}
+}
+
+func TestPostgresTodoRepository_CompleteMatching(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{CompletedAt: true, Version: true}))
+
+	title, _ := domain.NewTaskTitle("Overdue todo")
+	now := time.Now()
+	yesterday := domain.ReconstituteDueDate(now.Add(-24 * time.Hour))
+	overdue := domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusPending, domain.PriorityLow, &yesterday, now.Add(-time.Hour), now, nil)
+	urgent := domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusInProgress, domain.PriorityUrgent, &yesterday, now.Add(-time.Hour), now, nil)
+	cancelled := domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusCancelled, domain.PriorityLow, &yesterday, now.Add(-time.Hour), now, nil)
+	undated := createTestTodo()
+	for _, todo := range []*domain.Todo{overdue, urgent, cancelled, undated} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	low := domain.PriorityLow
+	completedAt := now.Truncate(time.Microsecond)
+	completed, err := repo.CompleteMatching(ctx, ports.CompletionFilter{Priority: &low, DueBefore: &now}, completedAt, 10)
+	if err != nil {
+		t.Fatalf("CompleteMatching() unexpected error: %v", err)
+	}
+	if len(completed) != 1 || completed[0].ID() != overdue.ID() {
+		t.Fatalf("CompleteMatching() = %v, want only the overdue low priority todo", completed)
+	}
+	if !completed[0].Status().IsCompleted() || completed[0].CompletedAt() == nil || !completed[0].CompletedAt().Equal(completedAt) || completed[0].Version() != 2 {
+		t.Errorf("completed todo = %+v, want completed at %v in version 2", completed[0], completedAt)
+	}
+
+	// Completed todos no longer match
+	if completed, _ := repo.CompleteMatching(ctx, ports.CompletionFilter{Priority: &low, DueBefore: &now}, completedAt, 10); len(completed) != 0 {
+		t.Errorf("CompleteMatching() again = %d todos, want 0", len(completed))
+	}
+
+	completed, err = repo.CompleteMatching(ctx, ports.CompletionFilter{}, completedAt, 1)
+	if err != nil {
+		t.Fatalf("CompleteMatching() unexpected error: %v", err)
+	}
+	if len(completed) != 1 || completed[0].ID() != urgent.ID() {
+		t.Errorf("CompleteMatching() limited to 1 = %v, want the oldest open todo", completed)
+	}
+}
+
+func TestPostgresTodoRepository_Update_StaleVersion(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool, WithSchemaFeatures(SchemaFeatures{Version: true}))
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Both writers read version 1; the first save wins
+	first, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() failed: %v", err)
+	}
+	second, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() failed: %v", err)
+	}
+	if err := first.UpdatePriority(domain.PriorityUrgent); err != nil {
+		t.Fatalf("UpdatePriority() failed: %v", err)
+	}
+	if err := second.UpdatePriority(domain.PriorityLow); err != nil {
+		t.Fatalf("UpdatePriority() failed: %v", err)
+	}
+
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if first.Version() != 2 {
+		t.Errorf("Version = %d, want 2", first.Version())
+	}
+	if err := repo.Update(ctx, second); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("Update() of a stale todo error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() failed: %v", err)
+	}
+	if found.Priority() != domain.PriorityUrgent || found.Version() != 2 {
+		t.Errorf("stored priority %v version %d, want urgent version 2", found.Priority(), found.Version())
+	}
+
+	// A todo that was never saved is missing rather than stale
+	if err := repo.Update(ctx, createTestTodo()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Update() of an unsaved todo error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestPostgresTodoRepository_FindByID_KeepsPastDueDates(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	repo := NewPostgresTodoRepository(pool)
+
+	todo := createTestTodoWithDueDate()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	overdue := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	if _, err := pool.Exec(ctx, `UPDATE todos SET due_date = $2 WHERE id = $1`, todo.ID().String(), overdue); err != nil {
+		t.Fatalf("backdating due date: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() failed: %v", err)
+	}
+	if found.DueDate() == nil || !found.DueDate().Time().Equal(overdue) {
+		t.Errorf("DueDate() = %v, want the passed due date %v", found.DueDate(), overdue)
+	}
+}
+
+func TestPostgresTodoRepository_OwnerBulkhead_CapsHeavyReads(t *testing.T) {
+	pool := setupTestDB(t)
+	owners := bulkhead.New(1, 10*time.Millisecond)
+	repo := NewPostgresTodoRepository(pool, WithOwnerBulkhead(owners))
+	alice := ports.ContextWithOwner(context.Background(), "alice")
+	bob := ports.ContextWithOwner(context.Background(), "bob")
+
+	// alice already runs a heavy query
+	release, err := owners.Acquire(alice, "alice")
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	defer release()
+
+	if _, err := repo.FindAll(alice, ports.Filters{}); !errors.Is(err, bulkhead.ErrFull) {
+		t.Errorf("FindAll() by a capped owner error = %v, want %v", err, bulkhead.ErrFull)
+	}
+	if _, err := repo.FindAll(bob, ports.Filters{}); err != nil {
+		t.Errorf("FindAll() by another owner unexpected error: %v", err)
+	}
+	if _, err := repo.Count(ports.ContextWithSystemAccess(context.Background()), ports.Filters{}); err != nil {
+		t.Errorf("Count() unscoped unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey is the context key of the transaction of a PostgresTransactor
+type txKey struct{}
+
+// PostgresTransactor implements the Transactor port with the transactions
+// of a pool
+// The todo repositories and the event outbox on the same pool join the
+// transaction of their context (see connOf)
+type PostgresTransactor struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresTransactor creates a new PostgresTransactor
+func NewPostgresTransactor(pool *pgxpool.Pool) *PostgresTransactor {
+	return &PostgresTransactor{pool: pool}
+}
+
+// InTransaction runs fn with a context carrying a new transaction, committed
+// when fn succeeds and rolled back otherwise; within a transaction, fn joins
+// it
+func (t *PostgresTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// conn runs statements on a pool or within a transaction, and begins
+// transactions, nested ones as savepoints
+type conn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// connOf returns the transaction ctx carries (see PostgresTransactor), or
+// pool
+func connOf(ctx context.Context, pool *pgxpool.Pool) conn {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// PostgresWebhookStore implements the WebhookStore port using PostgreSQL
+type PostgresWebhookStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresWebhookStore creates a new PostgreSQL webhook store
+func NewPostgresWebhookStore(pool *pgxpool.Pool) *PostgresWebhookStore {
+	return &PostgresWebhookStore{
+		pool: pool,
+	}
+}
+
+// Create stores a new endpoint
+func (s *PostgresWebhookStore) Create(ctx context.Context, endpoint ports.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, url, event_types, secret, digest_window_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	eventTypes := endpoint.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	_, err := s.pool.Exec(ctx, query, endpoint.ID, endpoint.URL, eventTypes, endpoint.Secret,
+		endpoint.DigestWindow.Milliseconds(), endpoint.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every endpoint, oldest first
+func (s *PostgresWebhookStore) List(ctx context.Context) ([]ports.WebhookEndpoint, error) {
+	query := `
+		SELECT id::text, url, event_types, secret, digest_window_ms, created_at
+		FROM webhook_endpoints
+		ORDER BY created_at, id
+	`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.WebhookEndpoint, error) {
+		var endpoint ports.WebhookEndpoint
+		var digestWindowMs int64
+		err := row.Scan(&endpoint.ID, &endpoint.URL, &endpoint.EventTypes, &endpoint.Secret, &digestWindowMs, &endpoint.CreatedAt)
+		endpoint.DigestWindow = time.Duration(digestWindowMs) * time.Millisecond
+		return endpoint, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// Delete removes an endpoint, reporting whether it existed
+// Its delivery attempts are kept
+func (s *PostgresWebhookStore) Delete(ctx context.Context, id string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting webhook endpoint: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecordDelivery logs a delivery attempt and returns its ID
+func (s *PostgresWebhookStore) RecordDelivery(ctx context.Context, delivery ports.WebhookDelivery) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, attempt, status_code,
+			error, response_snippet, payload, redelivery_of, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	var statusCode *int
+	if delivery.StatusCode != 0 {
+		statusCode = &delivery.StatusCode
+	}
+	var redeliveryOf *int64
+	if delivery.RedeliveryOf != 0 {
+		redeliveryOf = &delivery.RedeliveryOf
+	}
+
+	var id int64
+	err := s.pool.QueryRow(ctx, query,
+		delivery.EndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		statusCode,
+		delivery.Error,
+		delivery.ResponseSnippet,
+		delivery.Payload,
+		redeliveryOf,
+		delivery.Duration.Milliseconds(),
+		delivery.AttemptedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting webhook delivery: %w", err)
+	}
+
+	return id, nil
+}
+
+// deliveryColumns are the columns scanned by scanDelivery, payload excepted
+const deliveryColumns = `id, endpoint_id::text, event_id, event_type, attempt, status_code, error,
+	response_snippet, redelivery_of, duration_ms, attempted_at`
+
+// ListDeliveries returns the attempts matching query, most recent first,
+// without their payload
+func (s *PostgresWebhookStore) ListDeliveries(ctx context.Context, query ports.WebhookDeliveryQuery) ([]ports.WebhookDelivery, error) {
+	sql := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		  AND ($2 = 0 OR id < $2)
+		  AND (NOT $3 OR error <> '')
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, err := s.pool.Query(ctx, sql, query.EndpointID, query.Before, query.FailedOnly, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ports.WebhookDelivery, error) {
+		return scanDelivery(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collecting webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// FindDelivery returns an attempt with its payload, nil when there is no
+// attempt with this ID
+func (s *PostgresWebhookStore) FindDelivery(ctx context.Context, id int64) (*ports.WebhookDelivery, error) {
+	sql := `SELECT ` + deliveryColumns + `, payload FROM webhook_deliveries WHERE id = $1`
+
+	var payload []byte
+	delivery, err := scanDelivery(s.pool.QueryRow(ctx, sql, id), &payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying webhook delivery: %w", err)
+	}
+	delivery.Payload = payload
+
+	return &delivery, nil
+}
+
+// scanDelivery scans the deliveryColumns of a row, then extra
+func scanDelivery(row pgx.Row, extra ...any) (ports.WebhookDelivery, error) {
+	var delivery ports.WebhookDelivery
+	var statusCode *int
+	var redeliveryOf *int64
+	var durationMs int64
+
+	dest := append([]any{
+		&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.EventType, &delivery.Attempt,
+		&statusCode, &delivery.Error, &delivery.ResponseSnippet, &redeliveryOf, &durationMs, &delivery.AttemptedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return ports.WebhookDelivery{}, err
+	}
+
+	if statusCode != nil {
+		delivery.StatusCode = *statusCode
+	}
+	if redeliveryOf != nil {
+		delivery.RedeliveryOf = *redeliveryOf
+	}
+	delivery.Duration = time.Duration(durationMs) * time.Millisecond
+	return delivery, nil
+}
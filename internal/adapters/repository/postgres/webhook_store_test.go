@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestPostgresWebhookStore_Lifecycle(t *testing.T) {
+	pool := setupTestDB(t)
+	store := NewPostgresWebhookStore(pool)
+	ctx := context.Background()
+
+	endpoint := ports.WebhookEndpoint{
+		ID:           uuid.New().String(),
+		URL:          "https://example.com/hooks/todo",
+		EventTypes:   []string{"TodoCreated", "TodoCompleted"},
+		Secret:       "secret",
+		DigestWindow: 30 * time.Second,
+		CreatedAt:    time.Now(),
+	}
+	if err := store.Create(ctx, endpoint); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	endpoints, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0].URL != endpoint.URL || len(endpoints[0].EventTypes) != 2 || endpoints[0].Secret != "secret" || endpoints[0].DigestWindow != 30*time.Second {
+		t.Errorf("List() = %+v, want %+v", endpoints, endpoint)
+	}
+
+	var ids []int64
+	for _, delivery := range []ports.WebhookDelivery{
+		{EndpointID: endpoint.ID, EventID: "e1", EventType: "TodoCreated", Attempt: 1, Error: "connection refused", Payload: []byte(`{"a":1}`), AttemptedAt: time.Now()},
+		{EndpointID: endpoint.ID, EventID: "e1", EventType: "TodoCreated", Attempt: 2, StatusCode: http.StatusOK, ResponseSnippet: "ok", Payload: []byte(`{"a":1}`), Duration: 30 * time.Millisecond, AttemptedAt: time.Now()},
+	} {
+		id, err := store.RecordDelivery(ctx, delivery)
+		if err != nil {
+			t.Fatalf("RecordDelivery() failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	redelivery := ports.WebhookDelivery{EndpointID: endpoint.ID, EventID: "e1", EventType: "TodoCreated", Attempt: 1,
+		StatusCode: http.StatusBadGateway, Error: "endpoint answered 502 Bad Gateway", ResponseSnippet: "upstream down",
+		RedeliveryOf: ids[0], AttemptedAt: time.Now()}
+	redeliveryID, err := store.RecordDelivery(ctx, redelivery)
+	if err != nil {
+		t.Fatalf("RecordDelivery() failed: %v", err)
+	}
+
+	deliveries, err := store.ListDeliveries(ctx, ports.WebhookDeliveryQuery{EndpointID: endpoint.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListDeliveries() unexpected error: %v", err)
+	}
+	if len(deliveries) != 3 || deliveries[0].ID != redeliveryID || deliveries[0].RedeliveryOf != ids[0] ||
+		deliveries[0].ResponseSnippet != "upstream down" || deliveries[1].Duration != 30*time.Millisecond || deliveries[1].Payload != nil {
+		t.Errorf("ListDeliveries() = %+v, want the 3 attempts newest first, without payloads", deliveries)
+	}
+
+	deliveries, err = store.ListDeliveries(ctx, ports.WebhookDeliveryQuery{EndpointID: endpoint.ID, FailedOnly: true, Before: redeliveryID, Limit: 10})
+	if err != nil || len(deliveries) != 1 || deliveries[0].ID != ids[0] || deliveries[0].StatusCode != 0 {
+		t.Errorf("ListDeliveries(failed, before) = %+v, %v, want the first attempt", deliveries, err)
+	}
+
+	found, err := store.FindDelivery(ctx, ids[1])
+	if err != nil || found == nil || string(found.Payload) != `{"a":1}` || found.StatusCode != http.StatusOK {
+		t.Errorf("FindDelivery() = %+v, %v, want the second attempt with its payload", found, err)
+	}
+	found, err = store.FindDelivery(ctx, redeliveryID+1000)
+	if err != nil || found != nil {
+		t.Errorf("FindDelivery(unknown) = %+v, %v, want nil", found, err)
+	}
+
+	deleted, err := store.Delete(ctx, endpoint.ID)
+	if err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v, want true", deleted, err)
+	}
+	deleted, err = store.Delete(ctx, endpoint.ID)
+	if err != nil || deleted {
+		t.Errorf("Delete() again = %v, %v, want false", deleted, err)
+	}
+
+	// Attempts outlive their endpoint
+	var attempts int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM webhook_deliveries WHERE endpoint_id = $1`, endpoint.ID).Scan(&attempts); err != nil {
+		t.Fatalf("counting deliveries: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("deliveries = %d, want 3", attempts)
+	}
+}
@@ -0,0 +1,21 @@
+-- Todos, with the columns of the Postgres schema the repository supports
+-- Times are stored as Unix microseconds, which sort and compare as numbers
+CREATE TABLE IF NOT EXISTS todos (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL CHECK (length(title) <= 200),
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('pending', 'in_progress', 'completed', 'cancelled')),
+    priority TEXT NOT NULL CHECK (priority IN ('low', 'medium', 'high', 'urgent')),
+    due_date INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    completed_at INTEGER,
+    user_id TEXT,
+    archived_at INTEGER,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+-- Indexes for the listings
+CREATE INDEX idx_todos_created_at ON todos(created_at DESC);
+CREATE INDEX idx_todos_status ON todos(status);
+CREATE INDEX idx_todos_user_id ON todos(user_id, created_at DESC);
@@ -0,0 +1,105 @@
+// Package sqlite stores todos in a SQLite database file, for single-binary
+// deployments and local development without Postgres
+// It requires a cgo build
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	// Registers the sqlite3 database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are applied in the order of their file names, which start with
+// their version
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the database at path, a file path or a "file:" URI, creating
+// it if needed, and applies the pending migrations
+// Writers wait up to 5 seconds for each other instead of failing at once
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := path
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	dsn += separator + "_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening SQLite database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("opening SQLite database: %w", err)
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Migrate applies the migrations newer than the schema version of db, each
+// in its own transaction
+// The version is kept in PRAGMA user_version: the number of migrations
+// applied
+func Migrate(ctx context.Context, db *sql.DB) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("listing migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if version > len(names) {
+		return fmt.Errorf("schema version %d is newer than this binary, which knows %d migrations", version, len(names))
+	}
+
+	for i, name := range names[version:] {
+		script, err := migrations.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("reading migration %s: %w", name, err)
+		}
+		if err := migrate(ctx, db, string(script), version+i+1); err != nil {
+			return fmt.Errorf("applying migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// migrate runs script and sets the schema version in a single transaction
+func migrate(ctx context.Context, db *sql.DB, script string, version int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
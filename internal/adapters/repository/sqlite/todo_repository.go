@@ -0,0 +1,426 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// SQLiteTodoRepository implements the TodoRepository port using SQLite
+// Like the Postgres repository, it scopes queries to the owner of the
+// context and refuses stale updates; it has no short codes, canaries nor
+// merges
+type SQLiteTodoRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTodoRepository creates a new SQLite repository on a database
+// opened with Open
+func NewSQLiteTodoRepository(db *sql.DB) *SQLiteTodoRepository {
+	return &SQLiteTodoRepository{
+		db: db,
+	}
+}
+
+// selectColumns are the columns scanned by scanTodo
+const selectColumns = "id, title, description, status, priority, due_date, created_at, updated_at, completed_at, user_id, archived_at, version"
+
+// owned returns the condition scoping a query to the owner of ctx, appending
+// its argument to args
+// A ctx without an owner matches no row, unless it has system access
+func owned(ctx context.Context, args []any) (string, []any) {
+	ownerID, ok := ports.OwnerFromContext(ctx)
+	if !ok {
+		if ports.HasSystemAccess(ctx) {
+			return "", args
+		}
+		return " AND FALSE", args
+	}
+
+	return " AND user_id = ?", append(args, ownerID)
+}
+
+// Save persists a new todo to the database
+func (r *SQLiteTodoRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	if todo.IsCanary() {
+		return errors.New("saving canary todo: canaries are not supported")
+	}
+
+	var ownerID *string
+	if todo.OwnerID() != "" {
+		owner := todo.OwnerID()
+		ownerID = &owner
+	}
+
+	query := `
+		INSERT INTO todos (id, title, description, status, priority, due_date, created_at, updated_at, completed_at, user_id, archived_at, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		todo.ID().String(),
+		todo.Title().String(),
+		todo.Description(),
+		todo.Status().String(),
+		todo.Priority().String(),
+		dueDateMicros(todo),
+		toMicros(todo.CreatedAt()),
+		toMicros(todo.UpdatedAt()),
+		nullableMicros(todo.CompletedAt()),
+		ownerID,
+		nullableMicros(todo.ArchivedAt()),
+	)
+	if err != nil {
+		return fmt.Errorf("saving todo: %w", err)
+	}
+
+	todo.AssignVersion(1)
+
+	return nil
+}
+
+// FindByID retrieves a todo by its ID
+func (r *SQLiteTodoRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	condition, args := owned(ctx, []any{id.String()})
+	query := `SELECT ` + selectColumns + ` FROM todos WHERE id = ?` + condition
+
+	todo, err := scanTodo(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	return todo, nil
+}
+
+// FindAll retrieves todos matching the given filters
+func (r *SQLiteTodoRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	where, args := filterConditions(ctx, filters)
+	query := `SELECT ` + selectColumns + ` FROM todos WHERE ` + where + ` ORDER BY ` + orderBy(filters)
+
+	// SQLite only accepts an OFFSET after a LIMIT, -1 meaning none
+	if filters.Limit != nil || filters.Offset != nil {
+		limit, offset := -1, 0
+		if filters.Limit != nil {
+			limit = *filters.Limit
+		}
+		if filters.Offset != nil {
+			offset = *filters.Offset
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying todos: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []*domain.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning todo: %w", err)
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// Count returns the number of todos matching the given filters, ignoring
+// Limit and Offset
+func (r *SQLiteTodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	where, args := filterConditions(ctx, filters)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT count(*) FROM todos WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting todos: %w", err)
+	}
+
+	return count, nil
+}
+
+// sortColumns maps sort fields to the expression they order by
+// Priorities are ranked by urgency rather than alphabetically
+var sortColumns = map[ports.SortField]string{
+	ports.SortByCreatedAt: "created_at",
+	ports.SortByUpdatedAt: "updated_at",
+	ports.SortByDueDate:   "due_date",
+	ports.SortByPriority:  "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 END",
+	ports.SortByTitle:     "lower(title)",
+}
+
+// orderBy returns the ORDER BY expressions of the requested sort, newest
+// first by default, as the Postgres repository orders them
+func orderBy(filters ports.Filters) string {
+	column, ok := sortColumns[filters.SortBy]
+	if !ok {
+		return "created_at DESC, id"
+	}
+
+	direction := "ASC"
+	if filters.SortOrder == ports.SortDescending {
+		direction = "DESC"
+	}
+
+	return column + " " + direction + " NULLS LAST, created_at DESC, id"
+}
+
+// filterConditions returns the WHERE conditions of the status, priority and
+// archival filters and of the owner scope, with their arguments
+func filterConditions(ctx context.Context, filters ports.Filters) (string, []any) {
+	condition, args := owned(ctx, nil)
+	where := "1=1" + condition
+
+	if filters.Status != nil {
+		where += " AND status = ?"
+		args = append(args, filters.Status.String())
+	}
+
+	if filters.Priority != nil {
+		where += " AND priority = ?"
+		args = append(args, filters.Priority.String())
+	}
+
+	if filters.Archived != nil {
+		if *filters.Archived {
+			where += " AND archived_at IS NOT NULL"
+		} else {
+			where += " AND archived_at IS NULL"
+		}
+	}
+
+	return where, args
+}
+
+// Update updates an existing todo
+// It returns ErrConcurrentModification when the todo was saved since it was
+// read, and assigns the todo its new version; todos read without a version
+// (zero) are written whatever their stored version
+func (r *SQLiteTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	args := []any{
+		todo.Title().String(),
+		todo.Description(),
+		todo.Status().String(),
+		todo.Priority().String(),
+		dueDateMicros(todo),
+		toMicros(todo.UpdatedAt()),
+		nullableMicros(todo.CompletedAt()),
+		nullableMicros(todo.ArchivedAt()),
+		todo.ID().String(),
+	}
+	query := `
+		UPDATE todos
+		SET title = ?, description = ?, status = ?, priority = ?, due_date = ?, updated_at = ?,
+			completed_at = ?, archived_at = ?, version = version + 1
+		WHERE id = ?`
+	condition, args := owned(ctx, args)
+	query += condition
+	if todo.Version() > 0 {
+		query += " AND version = ?"
+		args = append(args, todo.Version())
+	}
+
+	var version int64
+	err := r.db.QueryRowContext(ctx, query+" RETURNING version", args...).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.missingOrStale(ctx, todo)
+	}
+	if err != nil {
+		return fmt.Errorf("updating todo: %w", err)
+	}
+	todo.AssignVersion(version)
+
+	return nil
+}
+
+// missingOrStale tells why an update of todo changed no row:
+// ErrConcurrentModification if the todo still exists, ErrTodoNotFound
+// otherwise
+func (r *SQLiteTodoRepository) missingOrStale(ctx context.Context, todo *domain.Todo) error {
+	if todo.Version() == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	condition, args := owned(ctx, []any{todo.ID().String()})
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM todos WHERE id = ?`+condition+`)`, args...).Scan(&exists); err != nil {
+		return fmt.Errorf("checking todo version: %w", err)
+	}
+	if !exists {
+		return domain.ErrTodoNotFound
+	}
+
+	return domain.ErrConcurrentModification
+}
+
+// Delete removes a todo from the database
+func (r *SQLiteTodoRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	condition, args := owned(ctx, []any{id.String()})
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM todos WHERE id = ?`+condition, args...)
+	if err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+	if deleted == 0 {
+		return domain.ErrTodoNotFound
+	}
+
+	return nil
+}
+
+// FindDueBetween returns at most limit todos neither completed nor cancelled
+// whose due date is after from and at or before to, earliest due first
+func (r *SQLiteTodoRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	query := `
+		SELECT ` + selectColumns + `
+		FROM todos
+		WHERE due_date > ? AND due_date <= ?
+			AND status NOT IN ('completed', 'cancelled')
+		ORDER BY due_date, id
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, toMicros(from), toMicros(to), limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying due todos: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []*domain.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning due todo: %w", err)
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying due todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// scanner is a row of *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTodo reconstitutes the todo of a row of selectColumns
+func scanTodo(row scanner) (*domain.Todo, error) {
+	var (
+		id, title, description, status, priority string
+		dueDate, completedAt, archivedAt         sql.NullInt64
+		createdAt, updatedAt, version            int64
+		ownerID                                  sql.NullString
+	)
+	if err := row.Scan(&id, &title, &description, &status, &priority, &dueDate,
+		&createdAt, &updatedAt, &completedAt, &ownerID, &archivedAt, &version); err != nil {
+		return nil, err
+	}
+
+	todoID, err := domain.ParseTodoID(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	taskTitle, err := domain.NewTaskTitle(title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title: %w", err)
+	}
+
+	taskStatus, err := domain.NewTaskStatus(status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status: %w", err)
+	}
+
+	taskPriority, err := domain.NewPriority(priority)
+	if err != nil {
+		return nil, fmt.Errorf("invalid priority: %w", err)
+	}
+
+	// Stored due dates may have passed since they were set
+	var domainDueDate *domain.DueDate
+	if dueDate.Valid {
+		dd := domain.ReconstituteDueDate(fromMicros(dueDate.Int64))
+		domainDueDate = &dd
+	}
+
+	var completed *time.Time
+	if completedAt.Valid {
+		t := fromMicros(completedAt.Int64)
+		completed = &t
+	}
+
+	todo := domain.ReconstituteTodo(
+		todoID,
+		taskTitle,
+		description,
+		taskStatus,
+		taskPriority,
+		domainDueDate,
+		fromMicros(createdAt),
+		fromMicros(updatedAt),
+		completed,
+	)
+
+	if ownerID.Valid {
+		todo.AssignOwner(ownerID.String)
+	}
+
+	if archivedAt.Valid {
+		todo.RestoreArchived(fromMicros(archivedAt.Int64))
+	}
+
+	todo.AssignVersion(version)
+
+	return todo, nil
+}
+
+// toMicros converts t to the Unix microseconds it is stored as, the
+// precision of Postgres timestamps
+func toMicros(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+// fromMicros converts stored Unix microseconds to a time
+func fromMicros(micros int64) time.Time {
+	return time.UnixMicro(micros)
+}
+
+// nullableMicros converts an optional time to its stored value
+func nullableMicros(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	micros := toMicros(*t)
+	return &micros
+}
+
+// dueDateMicros returns the stored value of the due date of todo
+func dueDateMicros(todo *domain.Todo) *int64 {
+	if todo.DueDate() == nil {
+		return nil
+	}
+	due := todo.DueDate().Time()
+	return nullableMicros(&due)
+}
@@ -0,0 +1,288 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// setupTestDB opens a migrated database in a temporary file
+func setupTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "todo.db"))
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func createTestTodo() *domain.Todo {
+	title, _ := domain.NewTaskTitle("Test Todo")
+	return domain.NewTodo(title, "Test description", domain.PriorityMedium, nil)
+}
+
+func TestSQLiteTodoRepository_SaveAndFind(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	title, _ := domain.NewTaskTitle("Test Todo with Due Date")
+	dueDate, _ := domain.NewDueDate(time.Now().Add(24 * time.Hour))
+	todo := domain.NewTodo(title, "Test description", domain.PriorityHigh, &dueDate)
+	todo.AssignOwner("alice")
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Title() != todo.Title() || found.Description() != todo.Description() ||
+		found.Priority() != todo.Priority() || found.Status() != todo.Status() ||
+		found.OwnerID() != "alice" || found.Version() != 1 {
+		t.Errorf("FindByID() = %+v, want %+v", found, todo)
+	}
+	if !found.DueDate().Time().Equal(dueDate.Time().Truncate(time.Microsecond)) {
+		t.Errorf("DueDate() = %v, want %v to the microsecond", found.DueDate().Time(), dueDate.Time())
+	}
+	if !found.CreatedAt().Equal(todo.CreatedAt().Truncate(time.Microsecond)) {
+		t.Errorf("CreatedAt() = %v, want %v to the microsecond", found.CreatedAt(), todo.CreatedAt())
+	}
+
+	if _, err := repo.FindByID(ctx, domain.NewTodoID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() of a missing todo error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestSQLiteTodoRepository_Update(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	todo := createTestTodo()
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	stale, _ := repo.FindByID(ctx, todo.ID())
+
+	if err := todo.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	todo.Archive()
+	if err := repo.Update(ctx, todo); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if todo.Version() != 2 {
+		t.Errorf("Version() = %d, want 2", todo.Version())
+	}
+
+	found, err := repo.FindByID(ctx, todo.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	if found.Status() != domain.StatusCompleted || found.CompletedAt() == nil || !found.IsArchived() {
+		t.Errorf("FindByID() after update = %+v, want a completed, archived todo", found)
+	}
+
+	// An update of the version read before is refused
+	if err := repo.Update(ctx, stale); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("Update() of a stale todo error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+
+	if err := repo.Delete(ctx, todo.ID()); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if err := repo.Update(ctx, todo); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Update() of a deleted todo error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if err := repo.Delete(ctx, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Delete() again error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestSQLiteTodoRepository_FindAll(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := ports.ContextWithSystemAccess(context.Background())
+
+	// A medium todo without due date, then an urgent one due later and a
+	// completed low one due sooner
+	undated := createTestTodo()
+	later, _ := domain.NewDueDate(time.Now().Add(48 * time.Hour))
+	sooner, _ := domain.NewDueDate(time.Now().Add(24 * time.Hour))
+	title, _ := domain.NewTaskTitle("Test Todo")
+	urgent := domain.NewTodo(title, "", domain.PriorityUrgent, &later)
+	low := domain.NewTodo(title, "", domain.PriorityLow, &sooner)
+	_ = low.Complete()
+	for _, todo := range []*domain.Todo{undated, urgent, low} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	completed := domain.StatusCompleted
+	urgentPriority := domain.PriorityUrgent
+	one, two := 1, 2
+
+	tests := []struct {
+		name    string
+		filters ports.Filters
+		want    []domain.TodoID
+	}{
+		{"status", ports.Filters{Status: &completed}, []domain.TodoID{low.ID()}},
+		{"priority", ports.Filters{Priority: &urgentPriority}, []domain.TodoID{urgent.ID()}},
+		{"limit", ports.Filters{Limit: &one, SortBy: ports.SortByDueDate}, []domain.TodoID{low.ID()}},
+		{"offset", ports.Filters{Offset: &two, SortBy: ports.SortByDueDate}, []domain.TodoID{undated.ID()}},
+		{"due date ascending", ports.Filters{SortBy: ports.SortByDueDate, SortOrder: ports.SortAscending}, []domain.TodoID{low.ID(), urgent.ID(), undated.ID()}},
+		{"due date descending", ports.Filters{SortBy: ports.SortByDueDate, SortOrder: ports.SortDescending}, []domain.TodoID{urgent.ID(), low.ID(), undated.ID()}},
+		{"priority descending", ports.Filters{SortBy: ports.SortByPriority, SortOrder: ports.SortDescending}, []domain.TodoID{urgent.ID(), undated.ID(), low.ID()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, err := repo.FindAll(ctx, tt.filters)
+			if err != nil {
+				t.Fatalf("FindAll() unexpected error: %v", err)
+			}
+			if len(todos) != len(tt.want) {
+				t.Fatalf("FindAll() returned %d todos, want %d", len(todos), len(tt.want))
+			}
+			for i, todo := range todos {
+				if todo.ID() != tt.want[i] {
+					t.Errorf("FindAll() todo %d = %s, want %s", i, todo.ID(), tt.want[i])
+				}
+			}
+		})
+	}
+
+	count, err := repo.Count(ctx, ports.Filters{Limit: &one})
+	if err != nil || count != 3 {
+		t.Errorf("Count() = %d, %v, want 3 ignoring the limit", count, err)
+	}
+}
+
+func TestSQLiteTodoRepository_ScopedToOwner(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	alice := ports.ContextWithOwner(ctx, "alice")
+	bob := ports.ContextWithOwner(ctx, "bob")
+
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	if err := repo.Save(ctx, todo); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if _, err := repo.FindByID(bob, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindByID() by another owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+	if count, err := repo.Count(bob, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() by another owner = %d, %v, want 0", count, err)
+	}
+	if err := repo.Delete(bob, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("Delete() by another owner error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	todos, err := repo.FindAll(alice, ports.Filters{})
+	if err != nil || len(todos) != 1 {
+		t.Errorf("FindAll() by the owner = %d todos, %v, want 1", len(todos), err)
+	}
+}
+
+func TestSQLiteTodoRepository_AnonymousSeesNothing(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	system := ports.ContextWithSystemAccess(context.Background())
+	anonymous := context.Background()
+
+	owned := createTestTodo()
+	owned.AssignOwner("alice")
+	unowned := createTestTodo()
+	for _, todo := range []*domain.Todo{owned, unowned} {
+		if err := repo.Save(system, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	for _, todo := range []*domain.Todo{owned, unowned} {
+		if _, err := repo.FindByID(anonymous, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("FindByID() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Update(anonymous, todo); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("Update() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+		if err := repo.Delete(anonymous, todo.ID()); !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Errorf("Delete() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+	}
+	if todos, err := repo.FindAll(anonymous, ports.Filters{}); err != nil || len(todos) != 0 {
+		t.Errorf("FindAll() anonymous = %d todos, %v, want none", len(todos), err)
+	}
+	if count, err := repo.Count(anonymous, ports.Filters{}); err != nil || count != 0 {
+		t.Errorf("Count() anonymous = %d, %v, want 0", count, err)
+	}
+
+	// An empty owner is anonymous too
+	if todos, err := repo.FindAll(ports.ContextWithOwner(anonymous, ""), ports.Filters{}); err != nil || len(todos) != 0 {
+		t.Errorf("FindAll() with an empty owner = %d todos, %v, want none", len(todos), err)
+	}
+	if todos, err := repo.FindAll(system, ports.Filters{}); err != nil || len(todos) != 2 {
+		t.Errorf("FindAll() with system access = %d todos, %v, want 2", len(todos), err)
+	}
+}
+
+func TestSQLiteTodoRepository_FindDueBetween(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := ports.ContextWithSystemAccess(context.Background())
+	now := time.Now()
+
+	title, _ := domain.NewTaskTitle("Test Todo")
+	soon, _ := domain.NewDueDate(now.Add(time.Hour))
+	later, _ := domain.NewDueDate(now.Add(48 * time.Hour))
+	due := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	done := domain.NewTodo(title, "", domain.PriorityMedium, &soon)
+	_ = done.Complete()
+	notYet := domain.NewTodo(title, "", domain.PriorityMedium, &later)
+	for _, todo := range []*domain.Todo{due, done, notYet, createTestTodo()} {
+		if err := repo.Save(ctx, todo); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	todos, err := repo.FindDueBetween(ctx, now, now.Add(24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FindDueBetween() unexpected error: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID() != due.ID() {
+		t.Errorf("FindDueBetween() = %v, want only the open todo due within the window", todos)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	db, err := Open(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer db.Close()
+
+	// Opening a migrated database applies nothing
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate() again unexpected error: %v", err)
+	}
+
+	// A database migrated by a newer binary is refused
+	if _, err := db.Exec("PRAGMA user_version = 1000"); err != nil {
+		t.Fatalf("setting user_version failed: %v", err)
+	}
+	if err := Migrate(context.Background(), db); err == nil {
+		t.Error("Migrate() of a newer schema succeeded")
+	}
+}
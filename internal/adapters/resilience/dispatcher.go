@@ -0,0 +1,39 @@
+package resilience
+
+import (
+	"context"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// CircuitBreakingDispatcher decorates an EventDispatcher with a circuit breaker
+// While the downstream broker or endpoint is failing, deliveries are paused
+// and fail fast with circuitbreaker.ErrOpen
+type CircuitBreakingDispatcher struct {
+	next    ports.EventDispatcher
+	breaker *circuitbreaker.Breaker
+}
+
+// NewCircuitBreakingDispatcher creates a new CircuitBreakingDispatcher
+func NewCircuitBreakingDispatcher(
+	next ports.EventDispatcher,
+	breaker *circuitbreaker.Breaker,
+) *CircuitBreakingDispatcher {
+	return &CircuitBreakingDispatcher{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+// Dispatch publishes domain events through the breaker
+func (d *CircuitBreakingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return d.breaker.Execute(func() error {
+		return d.next.Dispatch(ctx, events)
+	})
+}
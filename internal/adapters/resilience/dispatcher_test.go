@@ -0,0 +1,49 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+)
+
+type failingDispatcher struct {
+	calls int
+}
+
+func (d *failingDispatcher) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	d.calls++
+	return errors.New("broker unavailable")
+}
+
+func TestCircuitBreakingDispatcher_PausesDeliveries(t *testing.T) {
+	inner := &failingDispatcher{}
+	dispatcher := NewCircuitBreakingDispatcher(inner, newTestBreaker())
+	events := []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")}
+
+	for i := 0; i < 3; i++ {
+		_ = dispatcher.Dispatch(context.Background(), events)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("Inner calls = %d, want 2", inner.calls)
+	}
+
+	if err := dispatcher.Dispatch(context.Background(), nil); err != nil {
+		t.Errorf("Dispatch() with no events unexpected error: %v", err)
+	}
+}
+
+func TestCircuitBreakingDispatcher_OpenReturnsErrOpen(t *testing.T) {
+	dispatcher := NewCircuitBreakingDispatcher(&failingDispatcher{}, newTestBreaker())
+	events := []domain.DomainEvent{domain.NewTodoDeletedEvent(domain.NewTodoID(), "")}
+
+	_ = dispatcher.Dispatch(context.Background(), events)
+	_ = dispatcher.Dispatch(context.Background(), events)
+
+	if err := dispatcher.Dispatch(context.Background(), events); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("Dispatch() error = %v, want %v", err, circuitbreaker.ErrOpen)
+	}
+}
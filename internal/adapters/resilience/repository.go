@@ -0,0 +1,437 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// errPurgeNotSupported is returned by the bulk deletion methods when the
+// decorated repository does not implement ports.TodoPurger
+var errPurgeNotSupported = errors.New("repository does not support bulk deletion")
+
+// errMergeNotSupported is returned by SaveMerge when the decorated repository
+// does not implement ports.TodoMerger
+var errMergeNotSupported = errors.New("repository does not support merges")
+
+// errMoveNotSupported is returned by SaveMove when the decorated repository
+// does not implement ports.TodoMover
+var errMoveNotSupported = errors.New("repository does not support moves")
+
+// errBatchUpdateNotSupported is returned by UpdateMany when the decorated
+// repository does not implement ports.TodoBatchUpdater
+var errBatchUpdateNotSupported = errors.New("repository does not support batch updates")
+
+// errBatchSaveNotSupported is returned by SaveMany when the decorated
+// repository does not implement ports.TodoBatchSaver
+var errBatchSaveNotSupported = errors.New("repository does not support batch inserts")
+
+// errBulkCompleteNotSupported is returned by CompleteMatching when the
+// decorated repository does not implement ports.TodoBulkCompleter
+var errBulkCompleteNotSupported = errors.New("repository does not support bulk completion")
+
+// errAnalyticsNotSupported is returned by Analytics when the decorated
+// repository does not implement ports.TodoAnalytics
+var errAnalyticsNotSupported = errors.New("repository does not support analytics")
+
+// errDueNotSupported is returned by FindDueBetween when the decorated
+// repository does not implement ports.DueTodoFinder
+var errDueNotSupported = errors.New("repository does not support due date lookups")
+
+// errDatedNotSupported is returned by FindDatedBetween when the decorated
+// repository does not implement ports.DatedTodoFinder
+var errDatedNotSupported = errors.New("repository does not support calendar lookups")
+
+// errSearchNotSupported is returned by Search when the decorated repository
+// does not implement ports.TodoSearcher
+var errSearchNotSupported = errors.New("repository does not support full-text search")
+
+// errCanaryNotSupported is returned by FindCanaries when the decorated
+// repository does not implement ports.CanaryFinder
+var errCanaryNotSupported = errors.New("repository does not support canary todos")
+
+// errChangeLogNotSupported is returned by ListChangedSince when the decorated
+// repository does not implement ports.TodoChangeLog
+var errChangeLogNotSupported = errors.New("repository does not support change logs")
+
+// CircuitBreakingRepository decorates a TodoRepository with a circuit breaker
+// When the database keeps failing, calls fail fast with circuitbreaker.ErrOpen
+// instead of waiting on an exhausted connection pool
+type CircuitBreakingRepository struct {
+	next    ports.TodoRepository
+	breaker *circuitbreaker.Breaker
+}
+
+// NewCircuitBreakingRepository creates a new CircuitBreakingRepository
+func NewCircuitBreakingRepository(
+	next ports.TodoRepository,
+	breaker *circuitbreaker.Breaker,
+) *CircuitBreakingRepository {
+	return &CircuitBreakingRepository{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+// Save persists a new todo
+func (r *CircuitBreakingRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	return r.breaker.Execute(func() error {
+		return r.next.Save(ctx, todo)
+	})
+}
+
+// FindByID retrieves a todo by its ID
+func (r *CircuitBreakingRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	var todo *domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todo, err = r.next.FindByID(ctx, id)
+		return err
+	})
+	return todo, err
+}
+
+// FindAll retrieves todos matching the given filters
+func (r *CircuitBreakingRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = r.next.FindAll(ctx, filters)
+		return err
+	})
+	return todos, err
+}
+
+// Count returns the number of todos matching the given filters
+func (r *CircuitBreakingRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	var count int
+	err := r.breaker.Execute(func() error {
+		var err error
+		count, err = r.next.Count(ctx, filters)
+		return err
+	})
+	return count, err
+}
+
+// Update updates an existing todo
+func (r *CircuitBreakingRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	return r.breaker.Execute(func() error {
+		return r.next.Update(ctx, todo)
+	})
+}
+
+// Delete removes a todo
+func (r *CircuitBreakingRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	return r.breaker.Execute(func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+// FindIDByShortCode resolves a short code when the decorated repository
+// supports short codes, and reports ErrTodoNotFound otherwise
+func (r *CircuitBreakingRepository) FindIDByShortCode(ctx context.Context, code domain.ShortCode) (domain.TodoID, error) {
+	resolver, ok := r.next.(ports.ShortCodeResolver)
+	if !ok {
+		return "", domain.ErrTodoNotFound
+	}
+
+	var id domain.TodoID
+	err := r.breaker.Execute(func() error {
+		var err error
+		id, err = resolver.FindIDByShortCode(ctx, code)
+		return err
+	})
+	return id, err
+}
+
+// Suggest finds autocomplete matches when the decorated repository supports
+// them, and returns no matches otherwise
+func (r *CircuitBreakingRepository) Suggest(ctx context.Context, query string, limit int) ([]*domain.Todo, error) {
+	suggester, ok := r.next.(ports.TodoSuggester)
+	if !ok {
+		return []*domain.Todo{}, nil
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = suggester.Suggest(ctx, query, limit)
+		return err
+	})
+	return todos, err
+}
+
+// Search finds todos by keywords when the decorated repository supports it
+func (r *CircuitBreakingRepository) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Todo, error) {
+	searcher, ok := r.next.(ports.TodoSearcher)
+	if !ok {
+		return nil, errSearchNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = searcher.Search(ctx, query, limit, offset)
+		return err
+	})
+	return todos, err
+}
+
+// FindCanaries lists canary todos when the decorated repository supports them
+func (r *CircuitBreakingRepository) FindCanaries(ctx context.Context) ([]*domain.Todo, error) {
+	finder, ok := r.next.(ports.CanaryFinder)
+	if !ok {
+		return nil, errCanaryNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = finder.FindCanaries(ctx)
+		return err
+	})
+	return todos, err
+}
+
+// FindAsOf reads a past version of a todo when the decorated repository
+// keeps history, and reports ErrTodoNotFound otherwise
+func (r *CircuitBreakingRepository) FindAsOf(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+	history, ok := r.next.(ports.TodoHistory)
+	if !ok {
+		return nil, domain.ErrTodoNotFound
+	}
+
+	var todo *domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todo, err = history.FindAsOf(ctx, id, at)
+		return err
+	})
+	return todo, err
+}
+
+// FindVersion reads a saved version of a todo when the decorated repository
+// keeps history, and reports ErrTodoNotFound otherwise
+func (r *CircuitBreakingRepository) FindVersion(ctx context.Context, id domain.TodoID, updatedAt time.Time) (*domain.Todo, error) {
+	versions, ok := r.next.(ports.TodoVersionFinder)
+	if !ok {
+		return nil, domain.ErrTodoNotFound
+	}
+
+	var todo *domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todo, err = versions.FindVersion(ctx, id, updatedAt)
+		return err
+	})
+	return todo, err
+}
+
+// ListActivity lists changes to todos when the decorated repository keeps
+// history, and reports no activity otherwise
+func (r *CircuitBreakingRepository) ListActivity(ctx context.Context, before int64, limit int) ([]ports.ActivityEvent, error) {
+	feed, ok := r.next.(ports.TodoActivityFeed)
+	if !ok {
+		return []ports.ActivityEvent{}, nil
+	}
+
+	var events []ports.ActivityEvent
+	err := r.breaker.Execute(func() error {
+		var err error
+		events, err = feed.ListActivity(ctx, before, limit)
+		return err
+	})
+	return events, err
+}
+
+// ListChangedSince lists the todos changed since a sync cursor when the
+// decorated repository keeps history
+func (r *CircuitBreakingRepository) ListChangedSince(ctx context.Context, after int64, limit int) ([]ports.TodoChangeMark, error) {
+	changes, ok := r.next.(ports.TodoChangeLog)
+	if !ok {
+		return nil, errChangeLogNotSupported
+	}
+
+	var marks []ports.TodoChangeMark
+	err := r.breaker.Execute(func() error {
+		var err error
+		marks, err = changes.ListChangedSince(ctx, after, limit)
+		return err
+	})
+	return marks, err
+}
+
+// Analytics computes trends when the decorated repository supports them
+func (r *CircuitBreakingRepository) Analytics(ctx context.Context, query ports.AnalyticsQuery) (*ports.AnalyticsSeries, error) {
+	analytics, ok := r.next.(ports.TodoAnalytics)
+	if !ok {
+		return nil, errAnalyticsNotSupported
+	}
+
+	var series *ports.AnalyticsSeries
+	err := r.breaker.Execute(func() error {
+		var err error
+		series, err = analytics.Analytics(ctx, query)
+		return err
+	})
+	return series, err
+}
+
+// SaveMerge persists a merge when the decorated repository supports merges
+func (r *CircuitBreakingRepository) SaveMerge(ctx context.Context, canonical, duplicate *domain.Todo) error {
+	merger, ok := r.next.(ports.TodoMerger)
+	if !ok {
+		return errMergeNotSupported
+	}
+
+	return r.breaker.Execute(func() error {
+		return merger.SaveMerge(ctx, canonical, duplicate)
+	})
+}
+
+// SaveMove persists a move when the decorated repository supports moves
+func (r *CircuitBreakingRepository) SaveMove(ctx context.Context, todo *domain.Todo) error {
+	mover, ok := r.next.(ports.TodoMover)
+	if !ok {
+		return errMoveNotSupported
+	}
+
+	return r.breaker.Execute(func() error {
+		return mover.SaveMove(ctx, todo)
+	})
+}
+
+// UpdateMany persists a batch of changes when the decorated repository
+// supports batch updates
+func (r *CircuitBreakingRepository) UpdateMany(ctx context.Context, todos []*domain.Todo) error {
+	updater, ok := r.next.(ports.TodoBatchUpdater)
+	if !ok {
+		return errBatchUpdateNotSupported
+	}
+
+	return r.breaker.Execute(func() error {
+		return updater.UpdateMany(ctx, todos)
+	})
+}
+
+// SaveMany persists a batch of new todos when the decorated repository
+// supports batch inserts
+func (r *CircuitBreakingRepository) SaveMany(ctx context.Context, todos []*domain.Todo) error {
+	saver, ok := r.next.(ports.TodoBatchSaver)
+	if !ok {
+		return errBatchSaveNotSupported
+	}
+
+	return r.breaker.Execute(func() error {
+		return saver.SaveMany(ctx, todos)
+	})
+}
+
+// CompleteMatching completes todos in bulk when the decorated repository
+// supports it
+func (r *CircuitBreakingRepository) CompleteMatching(ctx context.Context, filter ports.CompletionFilter, at time.Time, limit int) ([]*domain.Todo, error) {
+	completer, ok := r.next.(ports.TodoBulkCompleter)
+	if !ok {
+		return nil, errBulkCompleteNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = completer.CompleteMatching(ctx, filter, at, limit)
+		return err
+	})
+	return todos, err
+}
+
+// FindPurgeable finds todos to purge when the decorated repository supports
+// bulk deletion, and reports ErrNotSupported otherwise
+func (r *CircuitBreakingRepository) FindPurgeable(ctx context.Context, filter ports.PurgeFilter, limit int) ([]*domain.Todo, error) {
+	purger, ok := r.next.(ports.TodoPurger)
+	if !ok {
+		return nil, errPurgeNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = purger.FindPurgeable(ctx, filter, limit)
+		return err
+	})
+	return todos, err
+}
+
+// DeleteMany deletes todos in bulk when the decorated repository supports it
+func (r *CircuitBreakingRepository) DeleteMany(ctx context.Context, ids []domain.TodoID) (int, error) {
+	deleter, ok := r.next.(ports.TodoBatchDeleter)
+	if !ok {
+		return 0, errPurgeNotSupported
+	}
+
+	var deleted int
+	err := r.breaker.Execute(func() error {
+		var err error
+		deleted, err = deleter.DeleteMany(ctx, ids)
+		return err
+	})
+	return deleted, err
+}
+
+// FindDueBetween finds open todos by due date when the decorated repository
+// supports it
+func (r *CircuitBreakingRepository) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	finder, ok := r.next.(ports.DueTodoFinder)
+	if !ok {
+		return nil, errDueNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = finder.FindDueBetween(ctx, from, to, limit)
+		return err
+	})
+	return todos, err
+}
+
+// FindDatedBetween finds the todos of a calendar feed when the decorated
+// repository supports it
+func (r *CircuitBreakingRepository) FindDatedBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	finder, ok := r.next.(ports.DatedTodoFinder)
+	if !ok {
+		return nil, errDatedNotSupported
+	}
+
+	var todos []*domain.Todo
+	err := r.breaker.Execute(func() error {
+		var err error
+		todos, err = finder.FindDatedBetween(ctx, from, to, limit)
+		return err
+	})
+	return todos, err
+}
+
+// IsDependencyFailure reports whether an error returned by an adapter means
+// the dependency itself is failing
+// Domain errors, caller cancellations and capped owners are expected
+// outcomes and must not trip the circuit
+func IsDependencyFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, domain.ErrTodoNotFound) ||
+		errors.Is(err, domain.ErrConcurrentModification) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, bulkhead.ErrFull) {
+		return false
+	}
+
+	var domainErr domain.DomainError
+	return !errors.As(err, &domainErr)
+}
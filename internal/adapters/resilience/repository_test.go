@@ -0,0 +1,143 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// failingRepository returns err from every method and counts calls
+type failingRepository struct {
+	err   error
+	calls int
+}
+
+func (r *failingRepository) Save(ctx context.Context, todo *domain.Todo) error {
+	r.calls++
+	return r.err
+}
+
+func (r *failingRepository) FindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	r.calls++
+	return nil, r.err
+}
+
+func (r *failingRepository) FindAll(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+	r.calls++
+	return nil, r.err
+}
+
+func (r *failingRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	r.calls++
+	return 0, r.err
+}
+
+func (r *failingRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	r.calls++
+	return r.err
+}
+
+func (r *failingRepository) Delete(ctx context.Context, id domain.TodoID) error {
+	r.calls++
+	return r.err
+}
+
+// shortCodeRepository is a failingRepository that also resolves short codes
+type shortCodeRepository struct {
+	failingRepository
+}
+
+func (r *shortCodeRepository) FindIDByShortCode(ctx context.Context, code domain.ShortCode) (domain.TodoID, error) {
+	r.calls++
+	return "", r.err
+}
+
+func newTestBreaker() *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Settings{
+		Name:             "test",
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		IsFailure:        IsDependencyFailure,
+	})
+}
+
+func TestCircuitBreakingRepository_OpensOnDatabaseFailures(t *testing.T) {
+	inner := &failingRepository{err: errors.New("connection refused")}
+	repo := NewCircuitBreakingRepository(inner, newTestBreaker())
+
+	for i := 0; i < 2; i++ {
+		_, _ = repo.FindByID(context.Background(), domain.NewTodoID())
+	}
+
+	_, err := repo.FindByID(context.Background(), domain.NewTodoID())
+
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("FindByID() error = %v, want %v", err, circuitbreaker.ErrOpen)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("Inner calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestCircuitBreakingRepository_NotFoundDoesNotTrip(t *testing.T) {
+	inner := &failingRepository{err: domain.ErrTodoNotFound}
+	repo := NewCircuitBreakingRepository(inner, newTestBreaker())
+
+	for i := 0; i < 5; i++ {
+		_, err := repo.FindByID(context.Background(), domain.NewTodoID())
+		if !errors.Is(err, domain.ErrTodoNotFound) {
+			t.Fatalf("FindByID() error = %v, want %v", err, domain.ErrTodoNotFound)
+		}
+	}
+}
+
+func TestCircuitBreakingRepository_FindIDByShortCode(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	plain := NewCircuitBreakingRepository(&failingRepository{err: errDown}, newTestBreaker())
+	if _, err := plain.FindIDByShortCode(context.Background(), 1); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("FindIDByShortCode() without support error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	inner := &shortCodeRepository{failingRepository{err: errDown}}
+	repo := NewCircuitBreakingRepository(inner, newTestBreaker())
+	for i := 0; i < 3; i++ {
+		_, _ = repo.FindIDByShortCode(context.Background(), 1)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("Inner calls = %d, want 2 before the circuit opens", inner.calls)
+	}
+}
+
+func TestIsDependencyFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", domain.ErrTodoNotFound, false},
+		{"concurrent modification", fmt.Errorf("updating todo: %w", domain.ErrConcurrentModification), false},
+		{"cancelled", context.Canceled, false},
+		{"owner capped", fmt.Errorf("waiting for a query slot: %w", bulkhead.ErrFull), false},
+		{"validation", domain.NewValidationError("title", "cannot be empty"), false},
+		{"infrastructure", errors.New("connection reset"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDependencyFailure(tt.err); got != tt.want {
+				t.Errorf("IsDependencyFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
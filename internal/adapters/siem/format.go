@@ -0,0 +1,131 @@
+// Package siem exports the audit entries and the security anomalies to a
+// SIEM, over syslog or HTTP, as CEF or JSON
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Format is the encoding of the exported records
+type Format string
+
+const (
+	// FormatJSON encodes a record as a JSON object
+	FormatJSON Format = "json"
+	// FormatCEF encodes a record as an ArcSight Common Event Format line
+	FormatCEF Format = "cef"
+)
+
+// ParseFormat parses a record encoding: json or cef
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(value); format {
+	case FormatJSON, FormatCEF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown SIEM format %q, want json or cef", value)
+	}
+}
+
+// Device identifying the service in the CEF header
+const (
+	cefVendor  = "pivaldi"
+	cefProduct = "mmw-todo"
+	cefVersion = "1"
+)
+
+// jsonRecord is a record encoded as JSON
+type jsonRecord struct {
+	Time     time.Time         `json:"time"`
+	Category string            `json:"category"`
+	Name     string            `json:"name"`
+	Severity int               `json:"severity"`
+	Actor    string            `json:"actor,omitempty"`
+	TodoID   string            `json:"todo_id,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Changes  map[string]string `json:"changes,omitempty"`
+}
+
+// Encode returns record encoded in format, on a single line
+func Encode(record ports.SecurityRecord, format Format) ([]byte, error) {
+	if format == FormatCEF {
+		return []byte(encodeCEF(record)), nil
+	}
+
+	body, err := json.Marshal(jsonRecord{
+		Time:     record.OccurredAt.UTC(),
+		Category: record.Category,
+		Name:     record.Name,
+		Severity: record.Severity,
+		Actor:    record.Actor,
+		TodoID:   record.TodoID,
+		Message:  record.Message,
+		Changes:  record.Changes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding security record: %w", err)
+	}
+	return body, nil
+}
+
+// encodeCEF returns record as a CEF line: its signature is the category and
+// the name, and the extension holds the other fields, changes sorted by
+// field in cs2
+func encodeCEF(record ports.SecurityRecord) string {
+	var line strings.Builder
+	line.WriteString("CEF:0")
+	for _, field := range []string{
+		cefVendor,
+		cefProduct,
+		cefVersion,
+		record.Category + ":" + record.Name,
+		record.Name,
+		strconv.Itoa(min(max(record.Severity, 0), 10)),
+	} {
+		line.WriteByte('|')
+		line.WriteString(cefHeaderEscaper.Replace(field))
+	}
+	line.WriteByte('|')
+
+	extension := []string{
+		"rt=" + strconv.FormatInt(record.OccurredAt.UnixMilli(), 10),
+		"cat=" + cefExtensionEscaper.Replace(record.Category),
+	}
+	add := func(key, value string) {
+		if value != "" {
+			extension = append(extension, key+"="+cefExtensionEscaper.Replace(value))
+		}
+	}
+	add("suser", record.Actor)
+	if record.TodoID != "" {
+		add("cs1Label", "todoId")
+		add("cs1", record.TodoID)
+	}
+	if len(record.Changes) > 0 {
+		changes := make([]string, 0, len(record.Changes))
+		for _, field := range slices.Sorted(maps.Keys(record.Changes)) {
+			changes = append(changes, field+"="+record.Changes[field])
+		}
+		add("cs2Label", "changes")
+		add("cs2", strings.Join(changes, "; "))
+	}
+	add("msg", record.Message)
+	line.WriteString(strings.Join(extension, " "))
+
+	return line.String()
+}
+
+// cefHeaderEscaper escapes the pipes and backslashes of a CEF header field;
+// line breaks are not allowed there and are replaced by spaces
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// cefExtensionEscaper escapes the equal signs, backslashes and line breaks
+// of a CEF extension value
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
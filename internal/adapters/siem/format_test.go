@@ -0,0 +1,119 @@
+package siem
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+var testRecord = ports.SecurityRecord{
+	Category:   ports.SecurityCategoryAudit,
+	Name:       "force_update",
+	Severity:   5,
+	Actor:      "ops",
+	TodoID:     "todo-1",
+	Message:    "Fix the title\nasked by support",
+	Changes:    map[string]string{"title": "a=b", "status": "pending"},
+	OccurredAt: time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC),
+}
+
+func TestEncode_CEF(t *testing.T) {
+	tests := []struct {
+		name   string
+		record ports.SecurityRecord
+		want   string
+	}{
+		{
+			name:   "audit entry",
+			record: testRecord,
+			want: `CEF:0|pivaldi|mmw-todo|1|audit:force_update|force_update|5|rt=1772402400000 cat=audit suser=ops ` +
+				`cs1Label=todoId cs1=todo-1 cs2Label=changes cs2=status\=pending; title\=a\=b msg=Fix the title\nasked by support`,
+		},
+		{
+			name: "header escaped and severity clamped",
+			record: ports.SecurityRecord{
+				Category:   ports.SecurityCategoryAnomaly,
+				Name:       `a|b\c`,
+				Severity:   12,
+				OccurredAt: time.UnixMilli(1000),
+			},
+			want: `CEF:0|pivaldi|mmw-todo|1|anomaly:a\|b\\c|a\|b\\c|10|rt=1000 cat=anomaly`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.record, FormatCEF)
+			if err != nil {
+				t.Fatalf("Encode() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Encode() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode_JSON(t *testing.T) {
+	got, err := Encode(testRecord, FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("Encode() wrote invalid JSON %s: %v", got, err)
+	}
+	for key, want := range map[string]any{
+		"time":     "2026-03-01T22:00:00Z",
+		"category": "audit",
+		"name":     "force_update",
+		"severity": float64(5),
+		"actor":    "ops",
+		"todo_id":  "todo-1",
+	} {
+		if decoded[key] != want {
+			t.Errorf("%s = %v, want %v", key, decoded[key], want)
+		}
+	}
+	if changes, _ := decoded["changes"].(map[string]any); changes["status"] != "pending" {
+		t.Errorf("changes = %v, want those of the record", decoded["changes"])
+	}
+
+	// Empty fields are left out
+	got, err = Encode(ports.SecurityRecord{Category: "anomaly", Name: "mass_deletion"}, FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	decoded = nil
+	_ = json.Unmarshal(got, &decoded)
+	for _, key := range []string{"actor", "todo_id", "message", "changes"} {
+		if _, ok := decoded[key]; ok {
+			t.Errorf("empty %s was encoded: %s", key, got)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Format
+		wantErr bool
+	}{
+		{value: "json", want: FormatJSON},
+		{value: "cef", want: FormatCEF},
+		{value: "leef", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFormat(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// maxResponseBytes bounds the response body read before closing it, so that
+// the connection can be reused
+const maxResponseBytes = 64 << 10
+
+// HTTPExporter posts the records by batch to a collector, such as a Splunk
+// HTTP Event Collector or a Logstash http input: a JSON array of the records
+// as JSON, or a CEF line per record
+type HTTPExporter struct {
+	client        *http.Client
+	url           string
+	format        Format
+	authorization string
+}
+
+// NewHTTPExporter creates an HTTPExporter posting to url, with the
+// Authorization header authorization when it is not empty; timeout bounds
+// each request
+func NewHTTPExporter(url string, format Format, authorization string, timeout time.Duration) *HTTPExporter {
+	return &HTTPExporter{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		url:           url,
+		format:        format,
+		authorization: authorization,
+	}
+}
+
+// Export posts records in a single request
+func (e *HTTPExporter) Export(ctx context.Context, records []ports.SecurityRecord) error {
+	body, contentType, err := e.encode(records)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "todo-service-siem")
+	if e.authorization != "" {
+		req.Header.Set("Authorization", e.authorization)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting security records: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("SIEM collector answered %s", resp.Status)
+	}
+	return nil
+}
+
+// encode returns the body posting records and its content type
+func (e *HTTPExporter) encode(records []ports.SecurityRecord) ([]byte, string, error) {
+	var body bytes.Buffer
+	separator, end, contentType := byte(','), "]", "application/json"
+	if e.format == FormatCEF {
+		separator, end, contentType = '\n', "\n", "text/plain; charset=utf-8"
+	} else {
+		body.WriteByte('[')
+	}
+
+	for i, record := range records {
+		if i > 0 {
+			body.WriteByte(separator)
+		}
+		line, err := Encode(record, e.format)
+		if err != nil {
+			return nil, "", err
+		}
+		body.Write(line)
+	}
+	body.WriteString(end)
+
+	return body.Bytes(), contentType, nil
+}
@@ -0,0 +1,91 @@
+package siem
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestHTTPExporter_Export(t *testing.T) {
+	anomaly := ports.SecurityRecord{Category: ports.SecurityCategoryAnomaly, Name: "mass_deletion", Severity: 8, OccurredAt: time.Now()}
+
+	tests := []struct {
+		name        string
+		format      Format
+		contentType string
+		check       func(t *testing.T, body []byte)
+	}{
+		{
+			name:        "json",
+			format:      FormatJSON,
+			contentType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var records []map[string]any
+				if err := json.Unmarshal(body, &records); err != nil {
+					t.Fatalf("body %s is not a JSON array: %v", body, err)
+				}
+				if len(records) != 2 || records[0]["name"] != "force_update" || records[1]["name"] != "mass_deletion" {
+					t.Errorf("posted %v, want the records in order", records)
+				}
+			},
+		},
+		{
+			name:        "cef",
+			format:      FormatCEF,
+			contentType: "text/plain; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+				if len(lines) != 2 || !strings.Contains(lines[0], "|audit:force_update|") || !strings.Contains(lines[1], "|anomaly:mass_deletion|") {
+					t.Errorf("posted %q, want a CEF line per record", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				body    []byte
+				headers http.Header
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = r.Header.Clone()
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			exporter := NewHTTPExporter(server.URL, tt.format, "Splunk token", time.Second)
+			if err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord, anomaly}); err != nil {
+				t.Fatalf("Export() unexpected error: %v", err)
+			}
+
+			if got := headers.Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := headers.Get("Authorization"); got != "Splunk token" {
+				t.Errorf("Authorization = %q, want the configured one", got)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestHTTPExporter_Export_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter := NewHTTPExporter(server.URL, FormatJSON, "", time.Second)
+	err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord})
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Export() error = %v, want the status of the collector", err)
+	}
+}
@@ -0,0 +1,43 @@
+package siem
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// NewExporter returns the exporter of the SIEM at rawURL, by its scheme:
+// udp://, tcp:// and tls:// for syslog, http:// and https:// for an HTTP
+// collector; authorization is only sent to an HTTP collector
+// Syslog defaults to port 514, and 6514 over TLS
+func NewExporter(rawURL string, format Format, authorization string, timeout time.Duration) (ports.SecurityExporter, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SIEM URL: %w", err)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("SIEM URL %q has no host", rawURL)
+	}
+
+	syslogAddress := func(port string) string {
+		if parsed.Port() != "" {
+			return parsed.Host
+		}
+		return net.JoinHostPort(parsed.Hostname(), port)
+	}
+	switch parsed.Scheme {
+	case "udp", "tcp":
+		return NewSyslogExporter(parsed.Scheme, syslogAddress("514"), nil, format, timeout), nil
+	case "tls":
+		config := &tls.Config{ServerName: parsed.Hostname(), MinVersion: tls.VersionTLS12}
+		return NewSyslogExporter("tcp", syslogAddress("6514"), config, format, timeout), nil
+	case "http", "https":
+		return NewHTTPExporter(rawURL, format, authorization, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported SIEM URL scheme %q, want udp, tcp, tls, http or https", parsed.Scheme)
+	}
+}
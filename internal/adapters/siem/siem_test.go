@@ -0,0 +1,47 @@
+package siem
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "udp://siem.internal", want: "siem.internal:514"},
+		{url: "tcp://siem.internal:1514", want: "siem.internal:1514"},
+		{url: "tls://siem.internal", want: "siem.internal:6514"},
+		{url: "https://siem.internal/services/collector/raw", want: "https://siem.internal/services/collector/raw"},
+		{url: "ftp://siem.internal", wantErr: true},
+		{url: "siem.internal:514", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			exporter, err := NewExporter(tt.url, FormatJSON, "", time.Second)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewExporter(%q) expected an error", tt.url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewExporter(%q) unexpected error: %v", tt.url, err)
+			}
+
+			var got string
+			switch exporter := exporter.(type) {
+			case *SyslogExporter:
+				got = exporter.address
+			case *HTTPExporter:
+				got = exporter.url
+			}
+			if got != tt.want {
+				t.Errorf("NewExporter(%q) sends to %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
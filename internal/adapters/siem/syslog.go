@@ -0,0 +1,178 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Syslog message header
+const (
+	// syslogFacility is the security/authorization facility, authpriv
+	syslogFacility = 10
+	// syslogAppName identifies the service in the messages
+	syslogAppName = "todo"
+	// maxUDPMessageBytes bounds the messages sent over UDP; longer ones are
+	// truncated, as most receivers would
+	maxUDPMessageBytes = 8192
+)
+
+// SyslogExporter sends every record as an RFC 5424 syslog message, over UDP,
+// TCP or TLS; over TCP and TLS, messages are framed by octet counting, as
+// RFC 6587 describes
+// The connection is kept open, and dialed again after a failed write
+type SyslogExporter struct {
+	network string
+	address string
+	tls     *tls.Config
+	format  Format
+	timeout time.Duration
+	host    string
+	procID  string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogExporter creates a SyslogExporter sending to address over network,
+// udp or tcp, through TLS when tlsConfig is not nil; timeout bounds the
+// dialing and the writing of a batch
+func NewSyslogExporter(network, address string, tlsConfig *tls.Config, format Format, timeout time.Duration) *SyslogExporter {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "-"
+	}
+	return &SyslogExporter{
+		network: network,
+		address: address,
+		tls:     tlsConfig,
+		format:  format,
+		timeout: timeout,
+		host:    host,
+		procID:  strconv.Itoa(os.Getpid()),
+	}
+}
+
+// Export sends a message per record
+// Over UDP, delivery is not acknowledged, so only local failures are
+// reported
+func (e *SyslogExporter) Export(ctx context.Context, records []ports.SecurityRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.connect(ctx); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(e.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := e.conn.SetWriteDeadline(deadline); err != nil {
+		return e.fail(fmt.Errorf("setting write deadline: %w", err))
+	}
+
+	for _, record := range records {
+		message, err := e.message(record)
+		if err != nil {
+			return err
+		}
+		if e.network == "udp" {
+			if len(message) > maxUDPMessageBytes {
+				message = message[:maxUDPMessageBytes]
+			}
+		} else {
+			message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+		}
+		if _, err := e.conn.Write(message); err != nil {
+			return e.fail(fmt.Errorf("writing to syslog %s: %w", e.address, err))
+		}
+	}
+
+	return nil
+}
+
+// Close closes the connection, if open
+func (e *SyslogExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// connect dials the receiver, unless connected
+func (e *SyslogExporter) connect(ctx context.Context) error {
+	if e.conn != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	var dialer interface {
+		DialContext(ctx context.Context, network, address string) (net.Conn, error)
+	} = &net.Dialer{}
+	if e.tls != nil {
+		dialer = &tls.Dialer{Config: e.tls}
+	}
+	conn, err := dialer.DialContext(ctx, e.network, e.address)
+	if err != nil {
+		return fmt.Errorf("connecting to syslog %s: %w", e.address, err)
+	}
+	e.conn = conn
+	return nil
+}
+
+// fail closes the connection, for the next export to dial again, and
+// returns err
+func (e *SyslogExporter) fail(err error) error {
+	_ = e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// message returns the syslog message of record, whose severity follows
+// that of the record
+func (e *SyslogExporter) message(record ports.SecurityRecord) ([]byte, error) {
+	body, err := Encode(record, e.format)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s - ",
+		syslogFacility*8+syslogSeverity(record.Severity),
+		record.OccurredAt.UTC().Format(time.RFC3339Nano),
+		e.host,
+		syslogAppName,
+		e.procID,
+		record.Category,
+	)
+	return append([]byte(header), body...), nil
+}
+
+// syslogSeverity maps a CEF severity to a syslog one: notice below 4,
+// warning below 7, error below 9 and critical above
+func syslogSeverity(severity int) int {
+	switch {
+	case severity < 4:
+		return 5
+	case severity < 7:
+		return 4
+	case severity < 9:
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,164 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// syslogHeader matches the header of the messages, up to the body
+var syslogHeader = regexp.MustCompile(`^<(\d+)>1 \S+ \S+ todo \d+ (\w+) - `)
+
+func TestSyslogExporter_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Messages are framed by their length, then a space
+		reader := bufio.NewReader(conn)
+		var messages []string
+		for range 2 {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			message := make([]byte, n)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			messages = append(messages, string(message))
+		}
+		received <- messages
+	}()
+
+	exporter := NewSyslogExporter("tcp", listener.Addr().String(), nil, FormatCEF, time.Second)
+	defer exporter.Close()
+	anomaly := ports.SecurityRecord{Category: ports.SecurityCategoryAnomaly, Name: "mass_deletion", Severity: 8, OccurredAt: time.Now()}
+	if err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord, anomaly}); err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+
+	var messages []string
+	select {
+	case messages = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no messages received")
+	}
+
+	tests := []struct {
+		priority string
+		msgID    string
+		body     string
+	}{
+		// authpriv (10) * 8 + warning (4), then error (3)
+		{priority: "84", msgID: "audit", body: "CEF:0|pivaldi|mmw-todo|1|audit:force_update|"},
+		{priority: "83", msgID: "anomaly", body: "CEF:0|pivaldi|mmw-todo|1|anomaly:mass_deletion|"},
+	}
+	for i, tt := range tests {
+		match := syslogHeader.FindStringSubmatch(messages[i])
+		if match == nil {
+			t.Fatalf("message %q has no syslog header", messages[i])
+		}
+		if match[1] != tt.priority || match[2] != tt.msgID {
+			t.Errorf("message %d has priority %s and MSGID %s, want %s and %s", i, match[1], match[2], tt.priority, tt.msgID)
+		}
+		if body := messages[i][len(match[0]):]; !strings.HasPrefix(body, tt.body) {
+			t.Errorf("message %d body = %q, want it to start with %q", i, body, tt.body)
+		}
+	}
+}
+
+func TestSyslogExporter_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer conn.Close()
+
+	exporter := NewSyslogExporter("udp", conn.LocalAddr().String(), nil, FormatJSON, time.Second)
+	defer exporter.Close()
+	if err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord}); err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+
+	// A datagram per message, without framing
+	buffer := make([]byte, maxUDPMessageBytes)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("reading datagram: %v", err)
+	}
+	message := string(buffer[:n])
+	match := syslogHeader.FindStringSubmatch(message)
+	if match == nil || !strings.HasPrefix(message[len(match[0]):], `{"time":"2026-03-01T22:00:00Z"`) {
+		t.Errorf("datagram = %q, want a syslog message of the JSON record", message)
+	}
+}
+
+func TestSyslogExporter_Redial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	exporter := NewSyslogExporter("tcp", address, nil, FormatJSON, time.Second)
+	defer exporter.Close()
+	if err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord}); err == nil {
+		t.Fatal("Export() expected an error while the receiver is down")
+	}
+
+	// The next export dials again
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("listening again on %s: %v", address, err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			defer conn.Close()
+			_, _ = bufio.NewReader(conn).ReadString('}')
+		}
+	}()
+	if err := exporter.Export(context.Background(), []ports.SecurityRecord{testRecord}); err != nil {
+		t.Errorf("Export() unexpected error once the receiver is up: %v", err)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := []struct {
+		severity int
+		want     int
+	}{
+		{severity: 0, want: 5},
+		{severity: 5, want: 4},
+		{severity: 8, want: 3},
+		{severity: 10, want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := syslogSeverity(tt.severity); got != tt.want {
+			t.Errorf("syslogSeverity(%d) = %d, want %d", tt.severity, got, tt.want)
+		}
+	}
+}
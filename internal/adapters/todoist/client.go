@@ -0,0 +1,200 @@
+// Package todoist reads the projects and tasks of a Todoist account through
+// the Todoist API
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// baseURL is the Todoist API
+const baseURL = "https://api.todoist.com/api/v1"
+
+// pageSize is the number of items requested per page, the most the API
+// returns
+const pageSize = 200
+
+// maxResponseBytes bounds the body of an error response read
+const maxResponseBytes = 64 << 10
+
+// Client reads a Todoist account with the API token of its user
+// It implements ports.TodoistClient
+type Client struct {
+	client  *http.Client
+	token   string
+	baseURL string
+}
+
+// NewClient creates a Client authenticated by token, giving up on a request
+// after timeout
+func NewClient(token string, timeout time.Duration) *Client {
+	return &Client{
+		client:  &http.Client{Timeout: timeout},
+		token:   token,
+		baseURL: baseURL,
+	}
+}
+
+// project is a project as the API returns it
+type project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// task is a task as the API returns it
+type task struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"project_id"`
+	Content     string `json:"content"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	Due         *due   `json:"due"`
+}
+
+// due is the due date of a task as the API returns it; Date is a day, a
+// floating time, or a UTC time for tasks due at a fixed time
+type due struct {
+	Date     string `json:"date"`
+	Timezone string `json:"timezone"`
+}
+
+// Projects implements ports.TodoistClient
+func (c *Client) Projects(ctx context.Context) ([]ports.TodoistProject, error) {
+	var projects []ports.TodoistProject
+	err := c.list(ctx, "/projects", func(raw json.RawMessage) error {
+		var page []project
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		for _, p := range page {
+			projects = append(projects, ports.TodoistProject{ID: p.ID, Name: p.Name})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing Todoist projects: %w", err)
+	}
+	return projects, nil
+}
+
+// Tasks implements ports.TodoistClient
+func (c *Client) Tasks(ctx context.Context) ([]ports.TodoistTask, error) {
+	var tasks []ports.TodoistTask
+	err := c.list(ctx, "/tasks", func(raw json.RawMessage) error {
+		var page []task
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		for _, t := range page {
+			converted := ports.TodoistTask{
+				ID:          t.ID,
+				ProjectID:   t.ProjectID,
+				Content:     t.Content,
+				Description: t.Description,
+				Priority:    t.Priority,
+			}
+			if t.Due != nil {
+				d, err := parseDue(*t.Due)
+				if err != nil {
+					return fmt.Errorf("task %s: %w", t.ID, err)
+				}
+				converted.Due = &d
+			}
+			tasks = append(tasks, converted)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing Todoist tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// parseDue splits the date of a due date into its day and time of day
+func parseDue(d due) (ports.TodoistDue, error) {
+	day, clock, hasClock := strings.Cut(d.Date, "T")
+	if !hasClock {
+		return ports.TodoistDue{Date: day, Timezone: d.Timezone}, nil
+	}
+
+	// Tasks due at a fixed time carry it in UTC, with the zone of the user
+	// who set it in timezone
+	if strings.HasSuffix(clock, "Z") {
+		at, err := time.Parse(time.RFC3339Nano, d.Date)
+		if err != nil {
+			return ports.TodoistDue{}, fmt.Errorf("invalid due date %q", d.Date)
+		}
+		return ports.TodoistDue{Date: at.Format(time.DateOnly), Clock: at.Format(time.TimeOnly), Timezone: "UTC"}, nil
+	}
+
+	// Floating times are local to the user, whatever their zone
+	if len(clock) > len(time.TimeOnly) {
+		clock = clock[:len(time.TimeOnly)]
+	}
+	return ports.TodoistDue{Date: day, Clock: clock}, nil
+}
+
+// list reads every page of the collection at path, passing the results of
+// each page to decode
+func (c *Client) list(ctx context.Context, path string, decode func(json.RawMessage) error) error {
+	cursor := ""
+	for {
+		query := url.Values{"limit": {fmt.Sprint(pageSize)}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		var page struct {
+			Results    json.RawMessage `json:"results"`
+			NextCursor *string         `json:"next_cursor"`
+		}
+		if err := c.get(ctx, path+"?"+query.Encode(), &page); err != nil {
+			return err
+		}
+		if err := decode(page.Results); err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			return nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// get requests path and decodes the JSON response into v
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("Todoist refused the token: %s", resp.Status)
+		}
+		return fmt.Errorf("Todoist answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
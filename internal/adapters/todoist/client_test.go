@@ -0,0 +1,113 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestClient_Tasks(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/tasks" || r.URL.Query().Get("limit") != "200" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": "1", "project_id": "p1", "content": "Renew the domain", "description": "gandi", "priority": 4,
+				 "due": {"date": "2030-01-02", "timezone": null, "is_recurring": false}},
+				{"id": "2", "project_id": "p1", "content": "Call the bank", "priority": 1,
+				 "due": {"date": "2030-01-02T09:30:00", "timezone": null}}
+			], "next_cursor": "page-2"}`))
+		case "page-2":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": "3", "project_id": "p2", "content": "Standup", "priority": 2,
+				 "due": {"date": "2030-01-02T08:00:00.000000Z", "timezone": "Europe/Paris"}},
+				{"id": "4", "project_id": "p2", "content": "Someday", "priority": 1, "due": null}
+			], "next_cursor": null}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("secret-token", time.Second)
+	client.baseURL = server.URL
+
+	tasks, err := client.Tasks(context.Background())
+	if err != nil {
+		t.Fatalf("Tasks() unexpected error: %v", err)
+	}
+	if auth != "Bearer secret-token" {
+		t.Errorf("Authorization = %q, want the token", auth)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("Tasks() = %d tasks, want both pages", len(tasks))
+	}
+
+	wantDue := []*ports.TodoistDue{
+		{Date: "2030-01-02"},
+		{Date: "2030-01-02", Clock: "09:30:00"},
+		{Date: "2030-01-02", Clock: "08:00:00", Timezone: "UTC"},
+		nil,
+	}
+	for i, task := range tasks {
+		if (task.Due == nil) != (wantDue[i] == nil) || task.Due != nil && *task.Due != *wantDue[i] {
+			t.Errorf("task %s due = %+v, want %+v", task.ID, task.Due, wantDue[i])
+		}
+	}
+	if tasks[0].Priority != 4 || tasks[0].Description != "gandi" || tasks[0].ProjectID != "p1" {
+		t.Errorf("task 1 = %+v, want its priority, description and project", tasks[0])
+	}
+}
+
+func TestClient_Projects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results": [{"id": "p1", "name": "Inbox"}, {"id": "p2", "name": "Work"}], "next_cursor": null}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("secret-token", time.Second)
+	client.baseURL = server.URL
+
+	projects, err := client.Projects(context.Background())
+	if err != nil {
+		t.Fatalf("Projects() unexpected error: %v", err)
+	}
+	if len(projects) != 2 || projects[1] != (ports.TodoistProject{ID: "p2", Name: "Work"}) {
+		t.Errorf("Projects() = %+v, want Inbox and Work", projects)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"bad token", http.StatusUnauthorized, `Unauthorized`},
+		{"rate limited", http.StatusTooManyRequests, `{"error": "Too many requests"}`},
+		{"invalid JSON", http.StatusOK, `{"results": [`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient("secret-token", time.Second)
+			client.baseURL = server.URL
+			if _, err := client.Tasks(context.Background()); err == nil {
+				t.Error("Tasks() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// DigestSchema returns the JSON Schema of the bodies posting an
+// EventDigest, whose events each match one of the schemas eventRefs point
+// to
+func DigestSchema(eventRefs []string) map[string]any {
+	oneOf := make([]any, len(eventRefs))
+	for i, ref := range eventRefs {
+		oneOf[i] = map[string]any{"$ref": ref}
+	}
+
+	return events.EnvelopeSchema(ports.EventDigestType, map[string]any{
+		"type":     "object",
+		"required": []string{"events"},
+		"properties": map[string]any{
+			"events": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"oneOf": oneOf},
+			},
+		},
+	})
+}
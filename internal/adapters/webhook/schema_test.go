@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestDigestSchema(t *testing.T) {
+	schema := DigestSchema([]string{"TodoCreated.json", "TodoUpdated.json"})
+	properties := schema["properties"].(map[string]any)
+
+	if eventType := properties["event_type"].(map[string]any)["const"]; eventType != ports.EventDigestType {
+		t.Errorf("event_type = %v, want %s", eventType, ports.EventDigestType)
+	}
+
+	data := properties["data"].(map[string]any)
+	events := data["properties"].(map[string]any)["events"].(map[string]any)
+	oneOf := events["items"].(map[string]any)["oneOf"].([]any)
+	if len(oneOf) != 2 || oneOf[1].(map[string]any)["$ref"] != "TodoUpdated.json" {
+		t.Errorf("events items = %v, want one of the event schemas", oneOf)
+	}
+}
@@ -0,0 +1,175 @@
+// Package webhook posts domain events to the webhook endpoints
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pivaldi/mmw/todo/internal/adapters/events"
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Headers of the requests posting an event
+const (
+	// IDHeader identifies the event, so that receivers can drop the copies
+	// of a retried delivery
+	IDHeader = "Webhook-Id"
+	// EventTypeHeader is the type of the event, e.g. TodoCreated
+	EventTypeHeader = "Webhook-Event-Type"
+	// TimestampHeader is the Unix time of the request, which is signed with
+	// the body
+	TimestampHeader = "Webhook-Timestamp"
+	// SignatureHeader is "sha256=" followed by the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed by the endpoint secret
+	SignatureHeader = "Webhook-Signature"
+)
+
+// maxResponseBytes bounds the response body read before closing it, so that
+// the connection can be reused
+const maxResponseBytes = 64 << 10
+
+// SnippetBytes bounds the start of the response body returned by Send
+const SnippetBytes = 1024
+
+// Sender posts events as JSON to webhook endpoints
+// Redirects are not followed, and count as failures
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender creates a Sender giving up on a request after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Encode returns the body posting event: the event envelope published to the
+// brokers, or a digest envelope for a ports.EventDigest
+func (s *Sender) Encode(event domain.DomainEvent) ([]byte, error) {
+	body, err := encode(event)
+	if err != nil {
+		return nil, fmt.Errorf("encoding event: %w", err)
+	}
+	return body, nil
+}
+
+// Send posts body, the encoding of an event of eventType identified by
+// eventID, to endpoint and returns the status code and the start of the body
+// of the response
+func (s *Sender) Send(ctx context.Context, endpoint ports.WebhookEndpoint, eventID, eventType string, body []byte) (ports.WebhookReply, error) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return ports.WebhookReply{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-service-webhooks")
+	req.Header.Set(IDHeader, eventID)
+	req.Header.Set(EventTypeHeader, eventType)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ports.WebhookReply{}, fmt.Errorf("posting %s: %w", eventType, err)
+	}
+	defer resp.Body.Close()
+
+	snippet := make([]byte, SnippetBytes)
+	n, _ := io.ReadFull(resp.Body, snippet)
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+	reply := ports.WebhookReply{StatusCode: resp.StatusCode, Body: snippetText(snippet[:n])}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return reply, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return reply, nil
+}
+
+// snippetText returns the start of a response body as text: a multi-byte
+// character cut at the end is dropped, and invalid sequences and NUL bytes,
+// which PostgreSQL rejects in text, are replaced
+func snippetText(body []byte) string {
+	for i := 1; i < utf8.UTFMax && i <= len(body); i++ {
+		start := len(body) - i
+		if utf8.RuneStart(body[start]) {
+			if !utf8.FullRune(body[start:]) {
+				body = body[:start]
+			}
+			break
+		}
+	}
+	text := strings.ToValidUTF8(string(body), "\uFFFD")
+	return strings.ReplaceAll(text, "\x00", "\uFFFD")
+}
+
+// digestEnvelope is the body posting a ports.EventDigest: an envelope like
+// those of the other events, whose data lists the envelopes of the events
+// of the digest
+type digestEnvelope struct {
+	EventType   string     `json:"event_type"`
+	AggregateID string     `json:"aggregate_id"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	Data        digestData `json:"data"`
+}
+
+// digestData is the data of a digestEnvelope
+type digestData struct {
+	Events []json.RawMessage `json:"events"`
+}
+
+// encode returns the body posting event
+func encode(event domain.DomainEvent) ([]byte, error) {
+	digest, ok := event.(ports.EventDigest)
+	if !ok {
+		return events.EncodeEvent(event, events.EncodingJSON)
+	}
+
+	envelope := digestEnvelope{
+		EventType:   digest.EventType(),
+		AggregateID: digest.AggregateID(),
+		OccurredAt:  digest.OccurredAt().UTC(),
+		Data:        digestData{Events: make([]json.RawMessage, len(digest.Events))},
+	}
+	for i, event := range digest.Events {
+		body, err := events.EncodeEvent(event, events.EncodingJSON)
+		if err != nil {
+			return nil, err
+		}
+		envelope.Data.Events[i] = body
+	}
+	return json.Marshal(envelope)
+}
+
+// Sign returns the Webhook-Signature of a request body sent at timestamp,
+// keyed by secret
+// Receivers compute it again to check that a request comes from this
+// service and was not replayed long after timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestSender_Send(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, `{"queued":true}`)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	sender.now = func() time.Time { return time.Unix(1772355600, 0) }
+	endpoint := ports.WebhookEndpoint{URL: server.URL, Secret: "secret"}
+	event := domain.NewTodoDeletedEvent(domain.NewTodoID(), "")
+
+	payload, err := sender.Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+
+	reply, err := sender.Send(context.Background(), endpoint, "event-1", event.EventType(), payload)
+	if err != nil || reply.StatusCode != http.StatusAccepted || reply.Body != `{"queued":true}` {
+		t.Fatalf("Send() = %+v, %v, want %d and the response body", reply, err, http.StatusAccepted)
+	}
+
+	if header.Get(IDHeader) != "event-1" || header.Get(EventTypeHeader) != "TodoDeleted" {
+		t.Errorf("headers = %v, want the event ID and type", header)
+	}
+	if header.Get(TimestampHeader) != "1772355600" {
+		t.Errorf("%s = %q, want the Unix time", TimestampHeader, header.Get(TimestampHeader))
+	}
+	if want := Sign("secret", "1772355600", body); header.Get(SignatureHeader) != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, header.Get(SignatureHeader), want)
+	}
+
+	var envelope map[string]any
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope["event_type"] != "TodoDeleted" {
+		t.Errorf("body = %s, want the event envelope", body)
+	}
+}
+
+func TestSender_Send_Digest(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	id := domain.NewTodoID()
+	digest := ports.EventDigest{TodoID: id.String(), Events: []domain.DomainEvent{
+		domain.NewTodoArchivedEvent(id, time.Now()),
+		domain.NewTodoDeletedEvent(id, ""),
+	}}
+
+	sender := NewSender(time.Second)
+	payload, err := sender.Encode(digest)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	if _, err := sender.Send(context.Background(), ports.WebhookEndpoint{URL: server.URL}, "digest-1", digest.EventType(), payload); err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+
+	var envelope struct {
+		EventType   string `json:"event_type"`
+		AggregateID string `json:"aggregate_id"`
+		Data        struct {
+			Events []struct {
+				EventType   string `json:"event_type"`
+				AggregateID string `json:"aggregate_id"`
+			} `json:"events"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if envelope.EventType != ports.EventDigestType || envelope.AggregateID != id.String() {
+		t.Errorf("envelope = %+v, want a digest of the todo", envelope)
+	}
+	events := envelope.Data.Events
+	if len(events) != 2 || events[0].EventType != "TodoArchived" || events[1].EventType != "TodoDeleted" || events[1].AggregateID != id.String() {
+		t.Errorf("events = %+v, want the envelopes of the archival then the deletion", events)
+	}
+}
+
+func TestSender_Encode_ReplayedEvent(t *testing.T) {
+	id := domain.NewTodoID()
+	occurredAt := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	event := ports.ReplayedEvent{Entry: ports.TodoAuditEntry{
+		TodoID:     id.String(),
+		EventType:  "TodoCreated",
+		Changes:    map[string]string{"title": "Pay rent", "due_date": "2030-01-31T00:00:00Z"},
+		OccurredAt: occurredAt,
+	}}
+
+	body, err := NewSender(time.Second).Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+
+	var envelope struct {
+		EventType   string         `json:"event_type"`
+		AggregateID string         `json:"aggregate_id"`
+		OccurredAt  time.Time      `json:"occurred_at"`
+		Data        map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if envelope.EventType != "TodoCreated" || envelope.AggregateID != id.String() || !envelope.OccurredAt.Equal(occurredAt) {
+		t.Errorf("envelope = %+v, want the recorded event", envelope)
+	}
+	if envelope.Data["title"] != "Pay rent" || envelope.Data["due_date"] != "2030-01-31T00:00:00Z" || envelope.Data["replayed"] != true {
+		t.Errorf("data = %v, want the recorded fields, replayed", envelope.Data)
+	}
+}
+
+func TestSender_Send_Failures(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+	}{
+		{name: "server error", status: http.StatusBadGateway, wantStatus: http.StatusBadGateway},
+		{name: "redirect not followed", status: http.StatusFound, wantStatus: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "/elsewhere")
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, "upstream down")
+			}))
+			defer server.Close()
+
+			reply, err := NewSender(time.Second).Send(context.Background(),
+				ports.WebhookEndpoint{URL: server.URL}, "event-1", "TodoDeleted", []byte(`{}`))
+			if err == nil || reply.StatusCode != tt.wantStatus || reply.Body != "upstream down" {
+				t.Errorf("Send() = %+v, %v, want %d, the response body and an error", reply, err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSender_Send_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	reply, err := NewSender(time.Second).Send(context.Background(),
+		ports.WebhookEndpoint{URL: server.URL}, "event-1", "TodoDeleted", []byte(`{}`))
+	if err == nil || reply.StatusCode != 0 {
+		t.Errorf("Send() = %+v, %v, want 0 and an error", reply, err)
+	}
+}
+
+func TestSender_Send_LongResponse(t *testing.T) {
+	// The multi-byte character straddles the end of the snippet
+	body := strings.Repeat("x", SnippetBytes-1) + "é" + strings.Repeat("y", 2*maxResponseBytes)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	reply, err := NewSender(time.Second).Send(context.Background(),
+		ports.WebhookEndpoint{URL: server.URL}, "event-1", "TodoDeleted", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+	if reply.Body != body[:SnippetBytes-1] {
+		t.Errorf("Body = %d bytes, want the %d bytes before the cut character", len(reply.Body), SnippetBytes-1)
+	}
+}
+
+func TestSnippetText(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"text", "ok", "ok"},
+		{"empty", "", ""},
+		{"cut character", "caf\xc3", "caf"},
+		{"whole character", "café", "café"},
+		{"invalid", "a\xffb", "a\uFFFDb"},
+		{"nul", "a\x00b", "a\uFFFDb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippetText([]byte(tt.body)); got != tt.want {
+				t.Errorf("snippetText(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Analytics intervals
+const (
+	AnalyticsDaily  = "day"
+	AnalyticsWeekly = "week"
+)
+
+// Analytics limits
+const (
+	// DefaultAnalyticsRange is the period covered when no start is given
+	DefaultAnalyticsRange = 30 * 24 * time.Hour
+	// MaxAnalyticsPoints bounds the length of a time series
+	MaxAnalyticsPoints = 366
+)
+
+// WithAnalytics enables GetAnalytics
+func WithAnalytics(analytics ports.TodoAnalytics) Option {
+	return func(s *TodoApplicationService) {
+		s.analytics = analytics
+	}
+}
+
+// GetAnalytics returns the todos created, completed and overdue per day or
+// week, with the average cycle time of the todos completed over the period
+// Buckets are aligned on UTC days, weeks starting on Monday; the period
+// defaults to the last DefaultAnalyticsRange up to now, daily
+func (s *TodoApplicationService) GetAnalytics(ctx context.Context, req AnalyticsRequest) (*AnalyticsReport, error) {
+	if s.analytics == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	interval := req.Interval
+	bucketDays := 1
+	switch interval {
+	case "", AnalyticsDaily:
+		interval = AnalyticsDaily
+	case AnalyticsWeekly:
+		bucketDays = 7
+	default:
+		return nil, domain.NewValidationError("interval", "must be day or week")
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-DefaultAnalyticsRange)
+	}
+	if !from.Before(to) {
+		return nil, domain.NewValidationError("from", "must be before to")
+	}
+
+	from = alignAnalytics(from, interval)
+	bucketLength := time.Duration(bucketDays) * 24 * time.Hour
+	buckets := int((to.Sub(from) + bucketLength - 1) / bucketLength)
+	if buckets > MaxAnalyticsPoints {
+		return nil, domain.NewValidationError("from", fmt.Sprintf("period must span at most %d %ss", MaxAnalyticsPoints, interval))
+	}
+
+	series, err := s.analytics.Analytics(ctx, ports.AnalyticsQuery{From: from, Buckets: buckets, BucketDays: bucketDays})
+	if err != nil {
+		return nil, fmt.Errorf("computing analytics: %w", err)
+	}
+
+	report := &AnalyticsReport{
+		Interval:         interval,
+		From:             from,
+		To:               from.Add(time.Duration(buckets) * bucketLength),
+		Points:           make([]*AnalyticsPoint, len(series.Buckets)),
+		Completed:        series.Completed,
+		AverageCycleTime: series.AverageCycleTime,
+	}
+	for i, bucket := range series.Buckets {
+		report.Points[i] = &AnalyticsPoint{
+			Start:     bucket.Start,
+			Created:   bucket.Created,
+			Completed: bucket.Completed,
+			Overdue:   bucket.Overdue,
+		}
+	}
+
+	return report, nil
+}
+
+// alignAnalytics truncates t to the start of its UTC day, or of its week
+// starting on Monday
+func alignAnalytics(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == AnalyticsWeekly {
+		day = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
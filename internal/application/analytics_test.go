@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockTodoAnalytics records its query and returns one bucket per requested bucket
+type MockTodoAnalytics struct {
+	GotQuery ports.AnalyticsQuery
+}
+
+func (m *MockTodoAnalytics) Analytics(ctx context.Context, query ports.AnalyticsQuery) (*ports.AnalyticsSeries, error) {
+	m.GotQuery = query
+
+	series := &ports.AnalyticsSeries{Completed: query.Buckets, AverageCycleTime: time.Hour}
+	for i := 0; i < query.Buckets; i++ {
+		start := query.From.AddDate(0, 0, i*query.BucketDays)
+		series.Buckets = append(series.Buckets, ports.AnalyticsBucket{Start: start, Created: 2, Completed: 1})
+	}
+	return series, nil
+}
+
+func TestTodoService_GetAnalytics_WeeklyAlignsOnMonday(t *testing.T) {
+	analytics := &MockTodoAnalytics{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithAnalytics(analytics))
+
+	// Wednesday 2026-03-04 to Wednesday 2026-03-18
+	from := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 18, 9, 0, 0, 0, time.UTC)
+
+	report, err := service.GetAnalytics(context.Background(), AnalyticsRequest{From: from, To: to, Interval: AnalyticsWeekly})
+	if err != nil {
+		t.Fatalf("GetAnalytics() unexpected error: %v", err)
+	}
+
+	wantFrom := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if !analytics.GotQuery.From.Equal(wantFrom) {
+		t.Errorf("From = %v, want Monday %v", analytics.GotQuery.From, wantFrom)
+	}
+	if analytics.GotQuery.Buckets != 3 || analytics.GotQuery.BucketDays != 7 {
+		t.Errorf("query = %+v, want 3 buckets of 7 days", analytics.GotQuery)
+	}
+
+	if len(report.Points) != 3 || report.Points[0].Created != 2 {
+		t.Errorf("Points = %+v, want 3 points", report.Points)
+	}
+	if !report.To.Equal(wantFrom.AddDate(0, 0, 21)) {
+		t.Errorf("To = %v, want the end of the last bucket", report.To)
+	}
+	if report.AverageCycleTime != time.Hour {
+		t.Errorf("AverageCycleTime = %v, want %v", report.AverageCycleTime, time.Hour)
+	}
+}
+
+func TestTodoService_GetAnalytics_Defaults(t *testing.T) {
+	analytics := &MockTodoAnalytics{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithAnalytics(analytics))
+
+	report, err := service.GetAnalytics(context.Background(), AnalyticsRequest{})
+	if err != nil {
+		t.Fatalf("GetAnalytics() unexpected error: %v", err)
+	}
+
+	if report.Interval != AnalyticsDaily || analytics.GotQuery.BucketDays != 1 {
+		t.Errorf("Interval = %q, want daily buckets", report.Interval)
+	}
+	// 30 days, plus the partial day the range starts in
+	if analytics.GotQuery.Buckets != 31 {
+		t.Errorf("Buckets = %d, want 31", analytics.GotQuery.Buckets)
+	}
+}
+
+func TestTodoService_GetAnalytics_Errors(t *testing.T) {
+	var validationErr domain.ValidationError
+	now := time.Now()
+
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithAnalytics(&MockTodoAnalytics{}))
+	tests := []struct {
+		name string
+		req  AnalyticsRequest
+	}{
+		{"unknown interval", AnalyticsRequest{Interval: "month"}},
+		{"reversed period", AnalyticsRequest{From: now, To: now.Add(-time.Hour)}},
+		{"too long", AnalyticsRequest{From: now.AddDate(-2, 0, 0), To: now}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GetAnalytics(context.Background(), tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("GetAnalytics() error = %v, want a ValidationError", err)
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.GetAnalytics(context.Background(), AnalyticsRequest{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("GetAnalytics() error = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,246 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Kinds of security anomalies
+const (
+	AnomalyMassDeletion        = "mass_deletion"
+	AnomalyOffHoursAdminAction = "off_hours_admin_action"
+	AnomalyCanaryAccess        = "canary_access"
+)
+
+// anomalyScanInterval is the delay between two scans of the audit log
+const anomalyScanInterval = time.Minute
+
+// SecurityAnomalyDetected is dispatched when the AnomalyDetector flags
+// unusual activity
+// AggregateID is the todo concerned, empty when the anomaly spans many todos
+type SecurityAnomalyDetected struct {
+	Kind       string
+	Detail     string
+	Actor      string
+	todoID     string
+	occurredAt time.Time
+}
+
+// EventType returns the event type
+func (e SecurityAnomalyDetected) EventType() string {
+	return "SecurityAnomalyDetected"
+}
+
+// AggregateID returns the todo concerned, if any
+func (e SecurityAnomalyDetected) AggregateID() string {
+	return e.todoID
+}
+
+// OccurredAt returns when the anomaly was detected
+func (e SecurityAnomalyDetected) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// AnomalyOptions controls what the AnomalyDetector flags
+type AnomalyOptions struct {
+	// MaxDeletions is the number of deletions tolerated within Window;
+	// zero disables mass deletion detection
+	MaxDeletions int
+	// Window is the sliding period deletions are counted over
+	Window time.Duration
+	// BusinessHours is the [start, end) hour range admin actions are expected
+	// in on weekdays; a zero range disables off-hours detection
+	BusinessHours [2]int
+	// Location is the time zone of BusinessHours
+	Location *time.Location
+}
+
+// DefaultAnomalyOptions flags more than 50 deletions within 5 minutes and
+// admin actions outside 08:00-19:00 UTC on weekdays
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{
+		MaxDeletions:  50,
+		Window:        5 * time.Minute,
+		BusinessHours: [2]int{8, 19},
+		Location:      time.UTC,
+	}
+}
+
+// AnomalyDetector watches activity for unusual patterns and dispatches a
+// SecurityAnomalyDetected event for each one
+// Deletions are counted from the events of this instance; admin actions are
+// read back from the shared audit log
+type AnomalyDetector struct {
+	subscriber ports.EventSubscriber
+	auditLog   ports.AuditLogReader
+	dispatcher ports.EventDispatcher
+	logger     *slog.Logger
+	options    AnomalyOptions
+	runs       *JobTracker
+
+	deletions []time.Time
+}
+
+// NewAnomalyDetector creates a new AnomalyDetector
+// auditLog may be nil, which disables off-hours detection
+func NewAnomalyDetector(
+	subscriber ports.EventSubscriber,
+	auditLog ports.AuditLogReader,
+	dispatcher ports.EventDispatcher,
+	logger *slog.Logger,
+	options AnomalyOptions,
+) *AnomalyDetector {
+	return &AnomalyDetector{
+		subscriber: subscriber,
+		auditLog:   auditLog,
+		dispatcher: dispatcher,
+		logger:     logger,
+		options:    options,
+		runs:       NewJobTracker("anomaly_audit_scan", anomalyScanInterval),
+	}
+}
+
+// Runs returns the tracker of the audit log scans made by Run
+func (d *AnomalyDetector) Runs() *JobTracker {
+	return d.runs
+}
+
+// Run analyzes activity until ctx is done
+// Admin actions are checked from the time Run is called; a failed audit log
+// scan is retried over the same period at the next interval
+func (d *AnomalyDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(anomalyScanInterval)
+	defer ticker.Stop()
+
+	events := d.subscriber.Subscribe(ctx)
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind: some deletions went uncounted
+				if ctx.Err() != nil {
+					return
+				}
+				d.logger.Warn("anomaly detector fell behind the events, resubscribing")
+				events = d.subscriber.Subscribe(ctx)
+				continue
+			}
+			if err := d.Observe(ctx, event); err != nil {
+				d.logger.Error("raising anomaly failed", "error", err)
+			}
+		case <-ticker.C:
+			current := time.Now()
+			err := d.ScanAuditLog(ctx, last, current)
+			d.runs.Record(time.Now(), err)
+			if err != nil {
+				d.logger.Error("anomaly audit scan failed", "error", err)
+				continue
+			}
+			last = current
+		}
+	}
+}
+
+// Observe counts deletions and raises a mass deletion anomaly when more
+// than MaxDeletions happen within Window
+// The count restarts after each anomaly, so a long purge raises one anomaly
+// per MaxDeletions deletions rather than one per deletion
+func (d *AnomalyDetector) Observe(ctx context.Context, event domain.DomainEvent) error {
+	if _, ok := event.(domain.TodoDeleted); !ok || d.options.MaxDeletions <= 0 {
+		return nil
+	}
+
+	at := event.OccurredAt()
+	cutoff := at.Add(-d.options.Window)
+	kept := d.deletions[:0]
+	for _, deletedAt := range d.deletions {
+		if deletedAt.After(cutoff) {
+			kept = append(kept, deletedAt)
+		}
+	}
+	d.deletions = append(kept, at)
+
+	if len(d.deletions) <= d.options.MaxDeletions {
+		return nil
+	}
+
+	count := len(d.deletions)
+	d.deletions = d.deletions[:0]
+
+	return d.raise(ctx, SecurityAnomalyDetected{
+		Kind:       AnomalyMassDeletion,
+		Detail:     fmt.Sprintf("%d todos deleted within %s", count, d.options.Window),
+		occurredAt: at,
+	})
+}
+
+// ScanAuditLog raises an anomaly for every admin action recorded at or
+// after from and before to outside business hours
+func (d *AnomalyDetector) ScanAuditLog(ctx context.Context, from, to time.Time) error {
+	if d.auditLog == nil || d.options.BusinessHours[0] == d.options.BusinessHours[1] {
+		return nil
+	}
+
+	entries, err := d.auditLog.ListEntries(ctx, from, to, MaxComplianceReportEntries)
+	if err != nil {
+		return fmt.Errorf("listing audit entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		if d.duringBusinessHours(entry.OccurredAt) {
+			continue
+		}
+
+		anomaly := SecurityAnomalyDetected{
+			Kind:       AnomalyOffHoursAdminAction,
+			Detail:     fmt.Sprintf("%s at %s", entry.Action, entry.OccurredAt.In(d.location()).Format(time.RFC1123)),
+			Actor:      entry.Actor,
+			todoID:     entry.TodoID,
+			occurredAt: entry.OccurredAt,
+		}
+		if err := d.raise(ctx, anomaly); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// duringBusinessHours reports whether at falls within business hours on a weekday
+func (d *AnomalyDetector) duringBusinessHours(at time.Time) bool {
+	local := at.In(d.location())
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+
+	hour := local.Hour()
+	return hour >= d.options.BusinessHours[0] && hour < d.options.BusinessHours[1]
+}
+
+// location returns the time zone of business hours, UTC by default
+func (d *AnomalyDetector) location() *time.Location {
+	if d.options.Location == nil {
+		return time.UTC
+	}
+	return d.options.Location
+}
+
+// raise logs and dispatches an anomaly
+func (d *AnomalyDetector) raise(ctx context.Context, anomaly SecurityAnomalyDetected) error {
+	d.logger.Warn("security anomaly detected",
+		"kind", anomaly.Kind, "detail", anomaly.Detail, "actor", anomaly.Actor, "todo_id", anomaly.todoID)
+
+	if err := d.dispatcher.Dispatch(ctx, []domain.DomainEvent{anomaly}); err != nil {
+		return fmt.Errorf("dispatching anomaly: %w", err)
+	}
+
+	return nil
+}
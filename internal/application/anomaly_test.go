@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func newTestAnomalyDetector(auditLog ports.AuditLogReader, dispatcher ports.EventDispatcher, options AnomalyOptions) *AnomalyDetector {
+	return NewAnomalyDetector(&MockEventSubscriber{}, auditLog, dispatcher,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), options)
+}
+
+func TestAnomalyDetector_Observe_MassDeletion(t *testing.T) {
+	dispatcher := &MockEventDispatcher{}
+	detector := newTestAnomalyDetector(nil, dispatcher, AnomalyOptions{MaxDeletions: 3, Window: time.Minute})
+	ctx := context.Background()
+
+	// Other events are not counted
+	if err := detector.Observe(ctx, domain.NewTodoCompletedEvent(domain.NewTodoID(), time.Now())); err != nil {
+		t.Fatalf("Observe() unexpected error: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		if err := detector.Observe(ctx, domain.NewTodoDeletedEvent(domain.NewTodoID(), "")); err != nil {
+			t.Fatalf("Observe() unexpected error: %v", err)
+		}
+	}
+
+	// The count restarts after each anomaly: deletions 4 and 8 raise one
+	if len(dispatcher.DispatchedEvents) != 2 {
+		t.Fatalf("dispatched %d anomalies, want 2", len(dispatcher.DispatchedEvents))
+	}
+	anomaly, ok := dispatcher.DispatchedEvents[0].(SecurityAnomalyDetected)
+	if !ok || anomaly.Kind != AnomalyMassDeletion {
+		t.Errorf("dispatched %+v, want a mass deletion anomaly", dispatcher.DispatchedEvents[0])
+	}
+}
+
+func TestAnomalyDetector_Observe_SlidingWindow(t *testing.T) {
+	dispatcher := &MockEventDispatcher{}
+	detector := newTestAnomalyDetector(nil, dispatcher, AnomalyOptions{MaxDeletions: 2, Window: time.Minute})
+	detector.deletions = []time.Time{time.Now().Add(-2 * time.Minute), time.Now().Add(-90 * time.Second)}
+
+	if err := detector.Observe(context.Background(), domain.NewTodoDeletedEvent(domain.NewTodoID(), "")); err != nil {
+		t.Fatalf("Observe() unexpected error: %v", err)
+	}
+	if len(dispatcher.DispatchedEvents) != 0 {
+		t.Errorf("deletions outside the window raised %d anomalies", len(dispatcher.DispatchedEvents))
+	}
+}
+
+func TestAnomalyDetector_ScanAuditLog_OffHours(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	// Monday 2 March 2026, Paris time
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, paris)
+	reader := &MockAuditLogReader{Entries: []ports.AuditEntry{
+		{Action: AuditActionForceUpdate, Actor: "day", TodoID: "todo-1", OccurredAt: monday.Add(10 * time.Hour)},
+		{Action: AuditActionPurge, Actor: "night", TodoID: "todo-2", OccurredAt: monday.Add(23 * time.Hour)},
+		{Action: AuditActionLegalHold, Actor: "weekend", TodoID: "todo-3", OccurredAt: monday.AddDate(0, 0, 5).Add(10 * time.Hour)},
+	}}
+	dispatcher := &MockEventDispatcher{}
+	detector := newTestAnomalyDetector(reader, dispatcher, AnomalyOptions{BusinessHours: [2]int{8, 19}, Location: paris})
+
+	if err := detector.ScanAuditLog(context.Background(), monday, monday.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("ScanAuditLog() unexpected error: %v", err)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 2 {
+		t.Fatalf("dispatched %d anomalies, want 2", len(dispatcher.DispatchedEvents))
+	}
+	for i, actor := range []string{"night", "weekend"} {
+		anomaly := dispatcher.DispatchedEvents[i].(SecurityAnomalyDetected)
+		if anomaly.Kind != AnomalyOffHoursAdminAction || anomaly.Actor != actor {
+			t.Errorf("anomaly %d = %+v, want an off-hours action by %s", i, anomaly, actor)
+		}
+	}
+	if got := dispatcher.DispatchedEvents[0].AggregateID(); got != "todo-2" {
+		t.Errorf("AggregateID() = %q, want todo-2", got)
+	}
+}
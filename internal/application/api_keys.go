@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrAPIKeyNotFound is returned for unknown API key IDs
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrInvalidAPIKey is returned for unknown or revoked API key secrets
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyPrefix starts every API key secret, telling them apart from JWTs
+const APIKeyPrefix = "tdk_"
+
+// MaxAPIKeyNameLength bounds the name of an API key
+const MaxAPIKeyNameLength = 100
+
+// apiKeyBytes is the entropy of an API key secret
+const apiKeyBytes = 32
+
+// apiKeyScopes are the token scopes an API key may be granted
+var apiKeyScopes = []string{"read", "write", "admin"}
+
+// APIKeyService manages the static API keys of machine clients, such as
+// cron jobs and CI scripts, and authenticates the calls made with them
+type APIKeyService struct {
+	store ports.APIKeyStore
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(store ports.APIKeyStore) *APIKeyService {
+	return &APIKeyService{store: store}
+}
+
+// Create registers an API key and returns it with its secret
+// Only a hash of the secret is stored: it cannot be shown again
+func (s *APIKeyService) Create(ctx context.Context, req APIKeyRequest) (*APIKeyCreated, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > MaxAPIKeyNameLength {
+		return nil, domain.NewValidationError("name", fmt.Sprintf("must be 1 to %d characters", MaxAPIKeyNameLength))
+	}
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, domain.NewValidationError("user_id", "is required")
+	}
+
+	scopes := []string{}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(apiKeyScopes, scope) {
+			return nil, domain.NewValidationError("scopes", fmt.Sprintf("must be among %s", strings.Join(apiKeyScopes, ", ")))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key := ports.APIKey{
+		ID:        uuid.New().String(),
+		Name:      name,
+		UserID:    userID,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+
+	random := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("generating API key: %w", err)
+	}
+	secret := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	if err := s.store.Create(ctx, key, hashAPIKey(secret)); err != nil {
+		return nil, fmt.Errorf("creating API key: %w", err)
+	}
+
+	return &APIKeyCreated{Key: mapAPIKey(key), Secret: secret}, nil
+}
+
+// List returns every API key, oldest first
+func (s *APIKeyService) List(ctx context.Context) ([]*APIKeyResponse, error) {
+	keys, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing API keys: %w", err)
+	}
+
+	responses := make([]*APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = mapAPIKey(key)
+	}
+	return responses, nil
+}
+
+// Revoke deletes an API key; calls made with its secret fail from then on
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	deleted, err := s.store.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("revoking API key: %w", err)
+	}
+	if !deleted {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate returns the API key of secret, or ErrInvalidAPIKey
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (*APIKeyResponse, error) {
+	if !strings.HasPrefix(secret, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.store.FindByKeyHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		return nil, fmt.Errorf("finding API key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return mapAPIKey(*key), nil
+}
+
+// hashAPIKey returns the stored form of an API key secret
+// Secrets are random, so a fast unsalted hash is enough
+func hashAPIKey(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// mapAPIKey converts a stored key to its response DTO
+func mapAPIKey(key ports.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		UserID:    key.UserID,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+	}
+}
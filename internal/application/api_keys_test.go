@@ -0,0 +1,120 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// storedAPIKey is a key kept by MockAPIKeyStore
+type storedAPIKey struct {
+	key     ports.APIKey
+	keyHash []byte
+}
+
+// MockAPIKeyStore keeps API keys in memory
+type MockAPIKeyStore struct {
+	Keys []storedAPIKey
+}
+
+func (m *MockAPIKeyStore) Create(ctx context.Context, key ports.APIKey, keyHash []byte) error {
+	m.Keys = append(m.Keys, storedAPIKey{key, keyHash})
+	return nil
+}
+
+func (m *MockAPIKeyStore) FindByKeyHash(ctx context.Context, keyHash []byte) (*ports.APIKey, error) {
+	for _, stored := range m.Keys {
+		if bytes.Equal(stored.keyHash, keyHash) {
+			return &stored.key, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyStore) List(ctx context.Context) ([]ports.APIKey, error) {
+	keys := make([]ports.APIKey, len(m.Keys))
+	for i, stored := range m.Keys {
+		keys[i] = stored.key
+	}
+	return keys, nil
+}
+
+func (m *MockAPIKeyStore) Delete(ctx context.Context, id string) (bool, error) {
+	for i, stored := range m.Keys {
+		if stored.key.ID == id {
+			m.Keys = append(m.Keys[:i], m.Keys[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestAPIKeyService_Lifecycle(t *testing.T) {
+	store := &MockAPIKeyStore{}
+	service := NewAPIKeyService(store)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, APIKeyRequest{Name: "nightly", UserID: "ci-bot", Scopes: []string{"read", "write", "read"}})
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, APIKeyPrefix) {
+		t.Errorf("Secret = %q, want the %s prefix", created.Secret, APIKeyPrefix)
+	}
+	if bytes.Contains(store.Keys[0].keyHash, []byte(created.Secret)) {
+		t.Error("secret is stored in clear")
+	}
+	if !slices.Equal(created.Key.Scopes, []string{"read", "write"}) {
+		t.Errorf("Scopes = %v, want [read write]", created.Key.Scopes)
+	}
+
+	key, err := service.Authenticate(ctx, created.Secret)
+	if err != nil {
+		t.Fatalf("Authenticate() unexpected error: %v", err)
+	}
+	if key.ID != created.Key.ID || key.UserID != "ci-bot" {
+		t.Errorf("Authenticate() = %+v, want key %s of ci-bot", key, created.Key.ID)
+	}
+
+	if _, err := service.Authenticate(ctx, APIKeyPrefix+"forged"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Authenticate(forged) error = %v, want ErrInvalidAPIKey", err)
+	}
+
+	if err := service.Revoke(ctx, created.Key.ID); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	if _, err := service.Authenticate(ctx, created.Secret); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrInvalidAPIKey", err)
+	}
+	if err := service.Revoke(ctx, created.Key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Revoke() again error = %v, want ErrAPIKeyNotFound", err)
+	}
+}
+
+func TestAPIKeyService_Create_Validation(t *testing.T) {
+	var validationErr domain.ValidationError
+	service := NewAPIKeyService(&MockAPIKeyStore{})
+
+	tests := []struct {
+		name string
+		req  APIKeyRequest
+	}{
+		{"missing name", APIKeyRequest{UserID: "ci-bot"}},
+		{"missing user", APIKeyRequest{Name: "nightly"}},
+		{"unknown scope", APIKeyRequest{Name: "nightly", UserID: "ci-bot", Scopes: []string{"superuser"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Create(context.Background(), tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("Create() error = %v, want a ValidationError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Archival filters of ListTodos
+const (
+	// ArchivedExclude leaves archived todos out, the default
+	ArchivedExclude = "exclude"
+	// ArchivedOnly lists archived todos only
+	ArchivedOnly = "only"
+	// ArchivedInclude lists archived and unarchived todos
+	ArchivedInclude = "include"
+)
+
+// applyArchived validates the requested archival filter and sets it on
+// filters, leaving archived todos out by default
+func applyArchived(filters *ports.Filters, archived *string) error {
+	filter := ArchivedExclude
+	if archived != nil {
+		filter = strings.ToLower(*archived)
+	}
+
+	archivedOnly, unarchivedOnly := true, false
+	switch filter {
+	case ArchivedExclude:
+		filters.Archived = &unarchivedOnly
+	case ArchivedOnly:
+		filters.Archived = &archivedOnly
+	case ArchivedInclude:
+		filters.Archived = nil
+	default:
+		return domain.NewValidationError("archived", "must be one of exclude, only or include")
+	}
+	return nil
+}
+
+// ArchiveTodo takes a todo out of default listings without deleting it
+// Archiving an archived todo changes nothing
+func (s *TodoApplicationService) ArchiveTodo(ctx context.Context, id string) (*TodoResponse, error) {
+	return s.changeArchival(ctx, id, true)
+}
+
+// UnarchiveTodo brings an archived todo back into default listings
+// Unarchiving a todo that is not archived changes nothing
+func (s *TodoApplicationService) UnarchiveTodo(ctx context.Context, id string) (*TodoResponse, error) {
+	return s.changeArchival(ctx, id, false)
+}
+
+// changeArchival archives or unarchives the todo with id
+func (s *TodoApplicationService) changeArchival(ctx context.Context, id string, archive bool) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.findTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionArchive, todo); err != nil {
+		return nil, err
+	}
+
+	if todo.IsArchived() == archive {
+		return MapTodoToResponse(todo), nil
+	}
+	if archive {
+		todo.Archive()
+	} else {
+		todo.Unarchive()
+	}
+
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Update(ctx, todo); err != nil {
+			return nil, fmt.Errorf("updating todo: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
+	}
+	todo.ClearEvents()
+
+	// Track per-user recent activity
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
+	return MapTodoToResponse(todo), nil
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestTodoService_ArchiveTodo(t *testing.T) {
+	testTodo := createTestTodo()
+	testTodo.ClearEvents()
+	updates := 0
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+			updates++
+			return nil
+		},
+	}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(repo, dispatcher)
+	id := testTodo.ID().String()
+
+	archived, err := service.ArchiveTodo(context.Background(), id)
+	if err != nil {
+		t.Fatalf("ArchiveTodo() unexpected error: %v", err)
+	}
+	if archived.ArchivedAt == nil {
+		t.Error("ArchiveTodo() response has no ArchivedAt")
+	}
+
+	// Archiving again is a no-op
+	if _, err := service.ArchiveTodo(context.Background(), id); err != nil {
+		t.Fatalf("second ArchiveTodo() unexpected error: %v", err)
+	}
+
+	unarchived, err := service.UnarchiveTodo(context.Background(), id)
+	if err != nil {
+		t.Fatalf("UnarchiveTodo() unexpected error: %v", err)
+	}
+	if unarchived.ArchivedAt != nil {
+		t.Errorf("UnarchiveTodo() ArchivedAt = %v, want nil", unarchived.ArchivedAt)
+	}
+
+	if updates != 2 || len(dispatcher.DispatchedEvents) != 2 {
+		t.Errorf("got %d updates and %d events, want 2 of each", updates, len(dispatcher.DispatchedEvents))
+	}
+
+	viewer := ContextWithRoles(context.Background(), []string{RoleViewer})
+	if _, err := service.ArchiveTodo(viewer, id); !errors.Is(err, ErrForbidden) {
+		t.Errorf("ArchiveTodo() as viewer error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestTodoService_ListTodos_ArchivedFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		archived *string
+		want     *bool
+		wantErr  bool
+	}{
+		{"excluded by default", nil, &no, false},
+		{"exclude", str("exclude"), &no, false},
+		{"only", str("ONLY"), &yes, false},
+		{"include", str("include"), nil, false},
+		{"unknown", str("hidden"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ports.Filters
+			repo := &MockTodoRepository{
+				FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+					got = filters
+					return nil, nil
+				},
+			}
+			service := NewTodoApplicationService(repo, &MockEventDispatcher{})
+
+			_, err := service.ListTodos(context.Background(), ListFilters{Archived: tt.archived})
+			if tt.wantErr {
+				var validationErr domain.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("ListTodos() error = %v, want a ValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTodos() unexpected error: %v", err)
+			}
+			if (got.Archived == nil) != (tt.want == nil) || (got.Archived != nil && *got.Archived != *tt.want) {
+				t.Errorf("Archived filter = %v, want %v", got.Archived, tt.want)
+			}
+		})
+	}
+}
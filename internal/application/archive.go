@@ -0,0 +1,262 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Archive format identifiers, stored in the manifest
+const (
+	ArchiveFormat  = "mmw-todo-archive"
+	ArchiveVersion = 1
+)
+
+// Entries of an archive
+const (
+	archiveManifestFile = "manifest.json"
+	archiveTodosFile    = "todos.json"
+)
+
+// ErrUnsupportedArchive is returned when importing a file that is not a
+// supported todo archive
+var ErrUnsupportedArchive = errors.New("unsupported todo archive")
+
+// ArchiveManifest describes the content of an archive
+type ArchiveManifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	TodoCount  int       `json:"todo_count"`
+}
+
+// ArchivedTodo is the portable representation of a todo in an archive
+type ArchivedTodo struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	OwnerID     string     `json:"owner_id,omitempty"`
+}
+
+// ImportResult summarizes an archive import
+type ImportResult struct {
+	// Imported is the number of todos created
+	Imported int
+	// Skipped is the number of todos left untouched because their ID already exists
+	Skipped int
+}
+
+// ArchiveService exports todos to, and imports them from, a portable zip
+// archive of JSON documents, to move data between instances
+// Todos keep their IDs and timestamps, so importing an archive twice is a no-op
+type ArchiveService struct {
+	repository  ports.TodoRepository
+	maintenance *MaintenanceMode
+}
+
+// NewArchiveService creates a new ArchiveService
+func NewArchiveService(repository ports.TodoRepository, maintenance *MaintenanceMode) *ArchiveService {
+	return &ArchiveService{
+		repository:  repository,
+		maintenance: maintenance,
+	}
+}
+
+// Export writes every todo to w as a zip archive
+func (s *ArchiveService) Export(ctx context.Context, w io.Writer) (*ArchiveManifest, error) {
+	todos, err := s.repository.FindAll(ctx, ports.Filters{})
+	if err != nil {
+		return nil, fmt.Errorf("finding todos: %w", err)
+	}
+
+	archived := make([]ArchivedTodo, 0, len(todos))
+	for _, todo := range todos {
+		archived = append(archived, archiveTodo(todo))
+	}
+
+	manifest := &ArchiveManifest{
+		Format:     ArchiveFormat,
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		TodoCount:  len(archived),
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeArchiveEntry(zw, archiveManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	if err := writeArchiveEntry(zw, archiveTodosFile, archived); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("closing archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Import creates the todos of the archive read from r
+// Todos whose ID already exists are skipped. No domain events are dispatched:
+// imported todos are restored, not created
+func (s *ArchiveService) Import(ctx context.Context, r io.ReaderAt, size int64) (*ImportResult, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+	}
+
+	var manifest ArchiveManifest
+	if err := readArchiveEntry(zr, archiveManifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Format != ArchiveFormat || manifest.Version != ArchiveVersion {
+		return nil, fmt.Errorf("%w: format %q version %d", ErrUnsupportedArchive, manifest.Format, manifest.Version)
+	}
+
+	var archived []ArchivedTodo
+	if err := readArchiveEntry(zr, archiveTodosFile, &archived); err != nil {
+		return nil, err
+	}
+
+	// Validate everything before writing anything
+	todos := make([]*domain.Todo, 0, len(archived))
+	for i, a := range archived {
+		todo, err := restoreTodo(a)
+		if err != nil {
+			return nil, fmt.Errorf("todo %d (%s): %w", i, a.ID, err)
+		}
+		todos = append(todos, todo)
+	}
+
+	result := &ImportResult{}
+	for _, todo := range todos {
+		_, err := s.repository.FindByID(ctx, todo.ID())
+		if err == nil {
+			result.Skipped++
+			continue
+		}
+		if !errors.Is(err, domain.ErrTodoNotFound) {
+			return result, fmt.Errorf("finding todo %s: %w", todo.ID(), err)
+		}
+
+		if err := s.repository.Save(ctx, todo); err != nil {
+			return result, fmt.Errorf("saving todo %s: %w", todo.ID(), err)
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+// archiveTodo converts a domain Todo to its archived representation
+func archiveTodo(todo *domain.Todo) ArchivedTodo {
+	a := ArchivedTodo{
+		ID:          todo.ID().String(),
+		Title:       todo.Title().String(),
+		Description: todo.Description(),
+		Status:      todo.Status().String(),
+		Priority:    todo.Priority().String(),
+		CreatedAt:   todo.CreatedAt(),
+		UpdatedAt:   todo.UpdatedAt(),
+		CompletedAt: todo.CompletedAt(),
+		OwnerID:     todo.OwnerID(),
+	}
+
+	if todo.DueDate() != nil {
+		dueDate := todo.DueDate().Time()
+		a.DueDate = &dueDate
+	}
+
+	return a
+}
+
+// restoreTodo validates an archived todo and reconstitutes the aggregate
+func restoreTodo(a ArchivedTodo) (*domain.Todo, error) {
+	id, err := domain.ParseTodoID(a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	title, err := domain.NewTaskTitle(a.Title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title: %w", err)
+	}
+
+	status, err := domain.NewTaskStatus(a.Status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status: %w", err)
+	}
+
+	priority, err := domain.NewPriority(a.Priority)
+	if err != nil {
+		return nil, fmt.Errorf("invalid priority: %w", err)
+	}
+
+	var dueDate *domain.DueDate
+	if a.DueDate != nil {
+		dd := domain.ReconstituteDueDate(*a.DueDate)
+		dueDate = &dd
+	}
+
+	todo := domain.ReconstituteTodo(
+		id,
+		title,
+		a.Description,
+		status,
+		priority,
+		dueDate,
+		a.CreatedAt,
+		a.UpdatedAt,
+		a.CompletedAt,
+	)
+	todo.AssignOwner(a.OwnerID)
+
+	return todo, nil
+}
+
+// writeArchiveEntry adds a JSON document to the archive
+func writeArchiveEntry(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// readArchiveEntry decodes a JSON document of the archive
+func readArchiveEntry(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: missing %s", ErrUnsupportedArchive, name)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid %s: %v", ErrUnsupportedArchive, name, err)
+	}
+
+	return nil
+}
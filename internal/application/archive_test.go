@@ -0,0 +1,129 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// memoryTodoRepository is a map-backed TodoRepository for archive tests
+func memoryTodoRepository(todos map[domain.TodoID]*domain.Todo) *MockTodoRepository {
+	return &MockTodoRepository{
+		SaveFunc: func(ctx context.Context, todo *domain.Todo) error {
+			todos[todo.ID()] = todo
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if todo, ok := todos[id]; ok {
+				return todo, nil
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			all := make([]*domain.Todo, 0, len(todos))
+			for _, todo := range todos {
+				all = append(all, todo)
+			}
+			return all, nil
+		},
+	}
+}
+
+func TestArchiveService_ExportImport_RoundTrip(t *testing.T) {
+	completed := createTestTodo()
+	_ = completed.Complete()
+	completed.AssignOwner("alice")
+	source := map[domain.TodoID]*domain.Todo{
+		completed.ID(): completed,
+	}
+	pending := createTestTodo()
+	source[pending.ID()] = pending
+
+	var buf bytes.Buffer
+	manifest, err := NewArchiveService(memoryTodoRepository(source), nil).Export(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+	if manifest.TodoCount != 2 {
+		t.Errorf("TodoCount = %d, want 2", manifest.TodoCount)
+	}
+
+	// The target already has one of the todos
+	target := map[domain.TodoID]*domain.Todo{pending.ID(): pending}
+	service := NewArchiveService(memoryTodoRepository(target), nil)
+
+	result, err := service.Import(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+
+	if result.Imported != 1 || result.Skipped != 1 {
+		t.Errorf("Import() = %+v, want 1 imported and 1 skipped", result)
+	}
+
+	restored := target[completed.ID()]
+	if restored == nil {
+		t.Fatal("Expected the completed todo to be imported")
+	}
+	if restored.Status() != domain.StatusCompleted || restored.CompletedAt() == nil {
+		t.Errorf("Restored todo status=%v completedAt=%v, want completed with a timestamp", restored.Status(), restored.CompletedAt())
+	}
+	if !restored.CreatedAt().Equal(completed.CreatedAt()) {
+		t.Errorf("CreatedAt = %v, want %v", restored.CreatedAt(), completed.CreatedAt())
+	}
+	if restored.OwnerID() != "alice" {
+		t.Errorf("OwnerID = %q, want alice", restored.OwnerID())
+	}
+}
+
+func TestArchiveService_Import_RejectsUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = writeArchiveEntry(zw, archiveManifestFile, ArchiveManifest{Format: "other", Version: 1, ExportedAt: time.Now()})
+	_ = zw.Close()
+
+	service := NewArchiveService(memoryTodoRepository(map[domain.TodoID]*domain.Todo{}), nil)
+	_, err := service.Import(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if !errors.Is(err, ErrUnsupportedArchive) {
+		t.Errorf("Import() error = %v, want %v", err, ErrUnsupportedArchive)
+	}
+}
+
+func TestArchiveService_Import_InvalidTodo_WritesNothing(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = writeArchiveEntry(zw, archiveManifestFile, ArchiveManifest{Format: ArchiveFormat, Version: ArchiveVersion})
+	_ = writeArchiveEntry(zw, archiveTodosFile, []ArchivedTodo{
+		{ID: domain.NewTodoID().String(), Title: "Valid", Status: "pending", Priority: "low"},
+		{ID: domain.NewTodoID().String(), Title: "", Status: "pending", Priority: "low"},
+	})
+	_ = zw.Close()
+
+	target := map[domain.TodoID]*domain.Todo{}
+	service := NewArchiveService(memoryTodoRepository(target), nil)
+	_, err := service.Import(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if err == nil {
+		t.Fatal("Import() expected error for an invalid todo")
+	}
+	if len(target) != 0 {
+		t.Errorf("Expected no todos imported, got %d", len(target))
+	}
+}
+
+func TestArchiveService_Import_MaintenanceMode(t *testing.T) {
+	service := NewArchiveService(&MockTodoRepository{}, NewMaintenanceMode(true, ""))
+
+	_, err := service.Import(context.Background(), bytes.NewReader(nil), 0)
+
+	if !errors.Is(err, ErrMaintenanceMode) {
+		t.Errorf("Import() error = %v, want %v", err, ErrMaintenanceMode)
+	}
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrForbidden is returned when the authorization policy denies an operation
+var ErrForbidden = errors.New("permission denied")
+
+// Actions submitted to the authorization policy
+const (
+	ActionCreate   = "create"
+	ActionRead     = "read"
+	ActionUpdate   = "update"
+	ActionComplete = "complete"
+	ActionReopen   = "reopen"
+	ActionDelete   = "delete"
+	ActionMerge    = "merge"
+	ActionArchive  = "archive"
+	ActionMove     = "move"
+	ActionList     = "list"
+)
+
+// WithAuthorizer submits user operations to an authorization policy
+// Without it every operation is allowed; administrative operations, which
+// require the admin token, are never submitted
+func WithAuthorizer(authorizer ports.Authorizer) Option {
+	return func(s *TodoApplicationService) {
+		s.authorizer = authorizer
+	}
+}
+
+// authorize checks the roles of the current user, then asks the policy
+// whether they may perform action on todo, nil for actions on no single todo
+// The policy failing denies the operation. Allowed changes to an existing
+// todo are then refused with ErrTodoFrozen while its milestone is archived
+func (s *TodoApplicationService) authorize(ctx context.Context, action string, todo *domain.Todo) error {
+	if err := s.checkPolicy(ctx, action, todo); err != nil {
+		return err
+	}
+
+	if todo != nil && action != ActionRead && action != ActionCreate {
+		return s.checkNotFrozen(ctx, todo.ID())
+	}
+	return nil
+}
+
+// checkPolicy checks the roles of the current user and the policy
+func (s *TodoApplicationService) checkPolicy(ctx context.Context, action string, todo *domain.Todo) error {
+	if err := checkRole(ctx, action); err != nil {
+		return err
+	}
+	if s.authorizer == nil {
+		return nil
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	roles, _ := RolesFromContext(ctx)
+	req := ports.AuthorizationRequest{
+		UserID: userID,
+		Roles:  roles,
+		Action: action,
+		Time:   time.Now(),
+	}
+
+	if todo != nil {
+		req.Todo = &ports.TodoAttributes{
+			ID:        todo.ID().String(),
+			Title:     todo.Title().String(),
+			Status:    todo.Status().String(),
+			Priority:  todo.Priority().String(),
+			CreatedAt: todo.CreatedAt(),
+			OwnerID:   todo.OwnerID(),
+		}
+		if todo.DueDate() != nil {
+			dueDate := todo.DueDate().Time()
+			req.Todo.DueDate = &dueDate
+		}
+	}
+
+	allowed, err := s.authorizer.Authorize(ctx, req)
+	if err != nil {
+		return fmt.Errorf("authorizing %s: %w", action, err)
+	}
+	if !allowed {
+		return fmt.Errorf("%s: %w", action, ErrForbidden)
+	}
+
+	return nil
+}
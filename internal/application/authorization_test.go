@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockAuthorizer records authorization requests and answers with Allow
+type MockAuthorizer struct {
+	Allow    bool
+	Err      error
+	Requests []ports.AuthorizationRequest
+}
+
+func (m *MockAuthorizer) Authorize(ctx context.Context, req ports.AuthorizationRequest) (bool, error) {
+	m.Requests = append(m.Requests, req)
+	return m.Allow, m.Err
+}
+
+func TestTodoService_CompleteTodo_SubmitsTodoToPolicy(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	authorizer := &MockAuthorizer{Allow: true}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithAuthorizer(authorizer))
+
+	ctx := ContextWithUserID(context.Background(), "alice")
+	if _, err := service.CompleteTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("CompleteTodo() unexpected error: %v", err)
+	}
+
+	if len(authorizer.Requests) != 1 {
+		t.Fatalf("Authorize() called %d times, want 1", len(authorizer.Requests))
+	}
+	req := authorizer.Requests[0]
+	if req.UserID != "alice" || req.Action != ActionComplete {
+		t.Errorf("request = %s %s, want alice %s", req.UserID, req.Action, ActionComplete)
+	}
+	if req.Todo == nil || req.Todo.ID != testTodo.ID().String() || req.Todo.Priority != testTodo.Priority().String() {
+		t.Errorf("request todo = %+v, want the attributes of %v", req.Todo, testTodo.ID())
+	}
+	if req.Time.IsZero() {
+		t.Error("request time is not set")
+	}
+}
+
+func TestTodoService_Authorization_Denied(t *testing.T) {
+	testTodo := createTestTodo()
+	updated := false
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+			updated = true
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, id domain.TodoID) error {
+			updated = true
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		authorizer *MockAuthorizer
+		call       func(service *TodoApplicationService) error
+		wantErr    error
+	}{
+		{
+			name:       "update denied",
+			authorizer: &MockAuthorizer{Allow: false},
+			call: func(service *TodoApplicationService) error {
+				title := "Renamed"
+				_, err := service.UpdateTodo(context.Background(), testTodo.ID().String(), UpdateTodoRequest{Title: &title})
+				return err
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:       "delete denied",
+			authorizer: &MockAuthorizer{Allow: false},
+			call: func(service *TodoApplicationService) error {
+				return service.DeleteTodo(context.Background(), testTodo.ID().String())
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:       "policy failure denies",
+			authorizer: &MockAuthorizer{Allow: true, Err: errors.New("connection refused")},
+			call: func(service *TodoApplicationService) error {
+				return service.DeleteTodo(context.Background(), testTodo.ID().String())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated = false
+			service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithAuthorizer(tt.authorizer))
+
+			err := tt.call(service)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if updated {
+				t.Error("a denied operation reached the repository")
+			}
+		})
+	}
+}
+
+func TestTodoService_ListTodos_SubmitsListToPolicy(t *testing.T) {
+	authorizer := &MockAuthorizer{Allow: false}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithAuthorizer(authorizer))
+
+	_, err := service.ListTodos(context.Background(), ListFilters{})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("ListTodos() error = %v, want %v", err, ErrForbidden)
+	}
+
+	if len(authorizer.Requests) != 1 || authorizer.Requests[0].Action != ActionList || authorizer.Requests[0].Todo != nil {
+		t.Errorf("requests = %+v, want one list request without a todo", authorizer.Requests)
+	}
+}
@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Backfill statuses stored in BackfillProgress.Status
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+// ErrUnknownBackfill is returned when running a job that is not registered
+var ErrUnknownBackfill = errors.New("unknown backfill job")
+
+// BackfillOptions controls how a backfill job is executed
+type BackfillOptions struct {
+	// BatchSize is the maximum number of items per batch
+	BatchSize int
+	// BatchInterval is the minimum delay between two batches, limiting load on the database
+	BatchInterval time.Duration
+	// Restart discards saved progress and starts over from the beginning
+	Restart bool
+}
+
+// DefaultBackfillOptions returns conservative options suitable for production
+func DefaultBackfillOptions() BackfillOptions {
+	return BackfillOptions{
+		BatchSize:     500,
+		BatchInterval: 200 * time.Millisecond,
+	}
+}
+
+// BackfillRunner executes registered backfill jobs batch by batch,
+// checkpointing progress after every batch so an interrupted run resumes
+// where it stopped
+type BackfillRunner struct {
+	store  ports.BackfillProgressStore
+	jobs   map[string]ports.BackfillJob
+	logger *slog.Logger
+}
+
+// NewBackfillRunner creates a new BackfillRunner for the given jobs
+func NewBackfillRunner(
+	store ports.BackfillProgressStore,
+	logger *slog.Logger,
+	jobs ...ports.BackfillJob,
+) *BackfillRunner {
+	registry := make(map[string]ports.BackfillJob, len(jobs))
+	for _, job := range jobs {
+		registry[job.Name()] = job
+	}
+
+	return &BackfillRunner{
+		store:  store,
+		jobs:   registry,
+		logger: logger,
+	}
+}
+
+// Jobs returns the registered jobs sorted by name
+func (r *BackfillRunner) Jobs() []ports.BackfillJob {
+	jobs := make([]ports.BackfillJob, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name() < jobs[j].Name()
+	})
+
+	return jobs
+}
+
+// Status returns the progress of every job that ever ran
+func (r *BackfillRunner) Status(ctx context.Context) ([]*ports.BackfillProgress, error) {
+	progress, err := r.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing backfill progress: %w", err)
+	}
+	return progress, nil
+}
+
+// Run executes a job until it is done, the context is cancelled or a batch fails
+func (r *BackfillRunner) Run(
+	ctx context.Context,
+	name string,
+	opts BackfillOptions,
+) (*ports.BackfillProgress, error) {
+	job, ok := r.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackfill, name)
+	}
+
+	if opts.BatchSize < 1 {
+		opts.BatchSize = DefaultBackfillOptions().BatchSize
+	}
+
+	progress, err := r.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("loading backfill progress: %w", err)
+	}
+
+	now := time.Now()
+	if progress == nil || opts.Restart {
+		progress = &ports.BackfillProgress{Name: name, StartedAt: now}
+	} else if progress.Status == BackfillStatusCompleted {
+		return progress, nil
+	}
+
+	progress.Status = BackfillStatusRunning
+	progress.LastError = ""
+	progress.UpdatedAt = now
+	if err := r.store.Save(ctx, progress); err != nil {
+		return nil, fmt.Errorf("saving backfill progress: %w", err)
+	}
+
+	r.logger.Info("backfill started",
+		"job", name,
+		"cursor", progress.Cursor,
+		"processed", progress.Processed,
+	)
+
+	for {
+		batch, err := job.RunBatch(ctx, progress.Cursor, opts.BatchSize)
+		if err != nil {
+			return progress, r.fail(progress, err)
+		}
+
+		progress.Cursor = batch.Cursor
+		progress.Processed += int64(batch.Processed)
+		progress.UpdatedAt = time.Now()
+
+		if batch.Done {
+			progress.Status = BackfillStatusCompleted
+			progress.CompletedAt = &progress.UpdatedAt
+		}
+
+		if err := r.store.Save(ctx, progress); err != nil {
+			return progress, fmt.Errorf("saving backfill progress: %w", err)
+		}
+
+		r.logger.Debug("backfill batch processed",
+			"job", name,
+			"batch", batch.Processed,
+			"processed", progress.Processed,
+		)
+
+		if batch.Done {
+			r.logger.Info("backfill completed", "job", name, "processed", progress.Processed)
+			return progress, nil
+		}
+
+		if err := sleep(ctx, opts.BatchInterval); err != nil {
+			r.logger.Info("backfill interrupted, progress saved",
+				"job", name,
+				"processed", progress.Processed,
+			)
+			return progress, err
+		}
+	}
+}
+
+// fail records a failed batch; the job resumes from the last checkpoint next time
+func (r *BackfillRunner) fail(progress *ports.BackfillProgress, cause error) error {
+	progress.Status = BackfillStatusFailed
+	progress.LastError = cause.Error()
+	progress.UpdatedAt = time.Now()
+
+	// Use a fresh context: the run context may be the reason the batch failed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.store.Save(ctx, progress); err != nil {
+		r.logger.Error("saving failed backfill progress", "job", progress.Name, "error", err)
+	}
+
+	return fmt.Errorf("running backfill %s: %w", progress.Name, cause)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// memoryBackfillStore is an in-memory BackfillProgressStore
+type memoryBackfillStore struct {
+	progress map[string]ports.BackfillProgress
+}
+
+func newMemoryBackfillStore() *memoryBackfillStore {
+	return &memoryBackfillStore{progress: map[string]ports.BackfillProgress{}}
+}
+
+func (s *memoryBackfillStore) Get(ctx context.Context, name string) (*ports.BackfillProgress, error) {
+	p, ok := s.progress[name]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (s *memoryBackfillStore) Save(ctx context.Context, progress *ports.BackfillProgress) error {
+	s.progress[progress.Name] = *progress
+	return nil
+}
+
+func (s *memoryBackfillStore) List(ctx context.Context) ([]*ports.BackfillProgress, error) {
+	var list []*ports.BackfillProgress
+	for _, p := range s.progress {
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// countingJob processes integers up to total, optionally failing at failAt
+type countingJob struct {
+	total  int
+	failAt int
+}
+
+func (j *countingJob) Name() string        { return "counting" }
+func (j *countingJob) Description() string { return "counts to total" }
+
+func (j *countingJob) RunBatch(ctx context.Context, cursor string, batchSize int) (ports.BackfillBatch, error) {
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+
+	end := min(start+batchSize, j.total)
+	if j.failAt > start && j.failAt <= end {
+		return ports.BackfillBatch{}, errors.New("batch failed")
+	}
+
+	return ports.BackfillBatch{
+		Cursor:    strconv.Itoa(end),
+		Processed: end - start,
+		Done:      end == j.total,
+	}, nil
+}
+
+func newTestRunner(store ports.BackfillProgressStore, job ports.BackfillJob) *BackfillRunner {
+	return NewBackfillRunner(store, slog.New(slog.NewTextHandler(io.Discard, nil)), job)
+}
+
+func TestBackfillRunner_Run_ProcessesAllBatches(t *testing.T) {
+	store := newMemoryBackfillStore()
+	runner := newTestRunner(store, &countingJob{total: 25})
+
+	progress, err := runner.Run(context.Background(), "counting", BackfillOptions{BatchSize: 10})
+
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if progress.Status != BackfillStatusCompleted {
+		t.Errorf("Status = %v, want %v", progress.Status, BackfillStatusCompleted)
+	}
+
+	if progress.Processed != 25 {
+		t.Errorf("Processed = %d, want 25", progress.Processed)
+	}
+}
+
+func TestBackfillRunner_Run_ResumesAfterFailure(t *testing.T) {
+	store := newMemoryBackfillStore()
+	job := &countingJob{total: 30, failAt: 25}
+	runner := newTestRunner(store, job)
+
+	progress, err := runner.Run(context.Background(), "counting", BackfillOptions{BatchSize: 10})
+	if err == nil {
+		t.Fatal("Run() expected error, got nil")
+	}
+
+	if progress.Status != BackfillStatusFailed || progress.Cursor != "20" {
+		t.Fatalf("Progress = %+v, want failed at cursor 20", progress)
+	}
+
+	job.failAt = 0
+	progress, err = runner.Run(context.Background(), "counting", BackfillOptions{BatchSize: 10})
+	if err != nil {
+		t.Fatalf("Run() unexpected error on resume: %v", err)
+	}
+
+	if progress.Processed != 30 {
+		t.Errorf("Processed = %d, want 30", progress.Processed)
+	}
+}
+
+func TestBackfillRunner_Run_CompletedJobIsNotRerun(t *testing.T) {
+	store := newMemoryBackfillStore()
+	runner := newTestRunner(store, &countingJob{total: 5})
+
+	if _, err := runner.Run(context.Background(), "counting", BackfillOptions{BatchSize: 10}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	progress, err := runner.Run(context.Background(), "counting", BackfillOptions{BatchSize: 10})
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if progress.Processed != 5 {
+		t.Errorf("Processed = %d, want 5", progress.Processed)
+	}
+}
+
+func TestBackfillRunner_Run_UnknownJob_ReturnsError(t *testing.T) {
+	runner := newTestRunner(newMemoryBackfillStore(), &countingJob{})
+
+	_, err := runner.Run(context.Background(), "missing", DefaultBackfillOptions())
+
+	if !errors.Is(err, ErrUnknownBackfill) {
+		t.Errorf("Run() error = %v, want %v", err, ErrUnknownBackfill)
+	}
+}
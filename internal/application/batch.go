@@ -0,0 +1,208 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxBatchSize is the largest number of todos a batch operation can change
+const MaxBatchSize = 100
+
+// WithBatchDeletes enables BatchDeleteTodos
+func WithBatchDeletes(deleter ports.TodoBatchDeleter) Option {
+	return func(s *TodoApplicationService) {
+		s.batchDeleter = deleter
+	}
+}
+
+// BatchUpdateTodos applies many updates at once, each as UpdateTodo would
+// An update refused for its todo alone, because the todo is missing,
+// forbidden, modified since it was read or the change is invalid, is
+// reported as a failure and left out. The other updates are saved in a
+// single transaction, all or none, and their events dispatched at once
+func (s *TodoApplicationService) BatchUpdateTodos(ctx context.Context, req BatchUpdateRequest) (*BatchUpdateResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.batchUpdater == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := checkBatchSize("updates", len(req.Updates)); err != nil {
+		return nil, err
+	}
+
+	response := &BatchUpdateResponse{Updated: []*TodoResponse{}, Failures: []BatchFailure{}}
+	var updated []*domain.Todo
+	seen := make(map[domain.TodoID]bool, len(req.Updates))
+	for _, update := range req.Updates {
+		todo, err := s.batchUpdate(ctx, update, seen)
+		if err != nil {
+			if !isBatchFailure(err) {
+				return nil, err
+			}
+			response.Failures = append(response.Failures, BatchFailure{TodoID: update.TodoID, Err: err})
+			continue
+		}
+		updated = append(updated, todo)
+	}
+
+	if len(updated) == 0 {
+		return response, nil
+	}
+
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.batchUpdater.UpdateMany(ctx, updated); err != nil {
+			return nil, fmt.Errorf("saving batch: %w", err)
+		}
+		var events []domain.DomainEvent
+		for _, todo := range updated {
+			events = append(events, todo.Events()...)
+		}
+		return events, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, todo := range updated {
+		todo.ClearEvents()
+		s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+		response.Updated = append(response.Updated, MapTodoToResponse(todo))
+	}
+
+	return response, nil
+}
+
+// batchUpdate loads the todo of update and applies its fields
+func (s *TodoApplicationService) batchUpdate(ctx context.Context, update BatchTodoUpdate, seen map[domain.TodoID]bool) (*domain.Todo, error) {
+	todo, err := s.findTodo(ctx, update.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	// An ID and a short code may name the same todo
+	if seen[todo.ID()] {
+		return nil, domain.NewValidationError("todo_id", "is changed twice in the batch")
+	}
+	seen[todo.ID()] = true
+
+	if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.resolveEditConflict(ctx, todo, update.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyUpdate(todo, fields); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// BatchDeleteTodos deletes many todos at once, each as DeleteTodo would
+// A todo that is missing, forbidden or on legal hold is reported as a
+// failure and kept. The other todos are deleted in a single statement, all
+// or none, and their events dispatched at once
+func (s *TodoApplicationService) BatchDeleteTodos(ctx context.Context, req BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.batchDeleter == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := checkBatchSize("todo_ids", len(req.TodoIDs)); err != nil {
+		return nil, err
+	}
+
+	// Roles do not depend on the todos, so they refuse the whole batch
+	if err := checkRole(ctx, ActionDelete); err != nil {
+		return nil, err
+	}
+
+	response := &BatchDeleteResponse{Deleted: []string{}, Failures: []BatchFailure{}}
+	var todos []*domain.Todo
+	seen := make(map[domain.TodoID]bool, len(req.TodoIDs))
+	for _, id := range req.TodoIDs {
+		todo, err := s.findTodo(ctx, id)
+		if err == nil && seen[todo.ID()] {
+			err = domain.NewValidationError("todo_ids", "is deleted twice in the batch")
+		}
+		if err == nil {
+			seen[todo.ID()] = true
+			err = s.authorize(ctx, ActionDelete, todo)
+		}
+		if err != nil {
+			if !isBatchFailure(err) {
+				return nil, err
+			}
+			response.Failures = append(response.Failures, BatchFailure{TodoID: id, Err: err})
+			continue
+		}
+		todos = append(todos, todo)
+	}
+
+	free, _, err := s.withoutHeld(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]domain.TodoID, len(free))
+	deletable := make(map[domain.TodoID]bool, len(free))
+	for i, todo := range free {
+		ids[i] = todo.ID()
+		deletable[todo.ID()] = true
+	}
+	for _, todo := range todos {
+		if !deletable[todo.ID()] {
+			response.Failures = append(response.Failures, BatchFailure{TodoID: todo.ID().String(), Err: ErrLegalHold})
+		}
+	}
+	if len(ids) == 0 {
+		return response, nil
+	}
+
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if _, err := s.batchDeleter.DeleteMany(ctx, ids); err != nil {
+			return nil, fmt.Errorf("deleting batch: %w", err)
+		}
+		events := make([]domain.DomainEvent, len(free))
+		for i, todo := range free {
+			events[i] = domain.NewTodoDeletedEvent(todo.ID(), todo.OwnerID())
+		}
+		return events, nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		response.Deleted = append(response.Deleted, id.String())
+	}
+
+	return response, nil
+}
+
+// checkBatchSize validates the number of todos of a batch
+func checkBatchSize(field string, size int) error {
+	if size == 0 {
+		return domain.NewValidationError(field, "at least one todo is required")
+	}
+	if size > MaxBatchSize {
+		return domain.NewValidationError(field, fmt.Sprintf("at most %d todos can be changed at once", MaxBatchSize))
+	}
+	return nil
+}
+
+// isBatchFailure reports whether err refuses the change of one todo of a
+// batch, rather than the whole batch
+func isBatchFailure(err error) bool {
+	return isRejection(err) ||
+		errors.Is(err, domain.ErrTodoNotFound) ||
+		errors.Is(err, ErrEditConflict)
+}
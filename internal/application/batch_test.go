@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func newBatchService(findErr error, opts []Option, todos ...*domain.Todo) *TodoApplicationService {
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if findErr != nil {
+				return nil, findErr
+			}
+			for _, todo := range todos {
+				if todo.ID() == id {
+					return todo, nil
+				}
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	return NewTodoApplicationService(repo, &MockEventDispatcher{}, opts...)
+}
+
+func TestTodoService_BatchUpdateTodos(t *testing.T) {
+	renamed, invalid := createTestTodo(), createTestTodo()
+	for _, todo := range []*domain.Todo{renamed, invalid} {
+		todo.ClearEvents()
+	}
+	unknown := domain.NewTodoID().String()
+	title, status := "Renamed", "someday"
+	updater := &MockBatchUpdater{}
+	dispatcher := &MockEventDispatcher{}
+	service := newBatchService(nil, []Option{WithBatchUpdates(updater)}, renamed, invalid)
+	service.dispatcher = dispatcher
+
+	resp, err := service.BatchUpdateTodos(context.Background(), BatchUpdateRequest{Updates: []BatchTodoUpdate{
+		{TodoID: renamed.ID().String(), Fields: UpdateTodoRequest{Title: &title}},
+		{TodoID: invalid.ID().String(), Fields: UpdateTodoRequest{Status: &status}},
+		{TodoID: unknown, Fields: UpdateTodoRequest{Title: &title}},
+		{TodoID: renamed.ID().String(), Fields: UpdateTodoRequest{Title: &title}},
+	}})
+	if err != nil {
+		t.Fatalf("BatchUpdateTodos() unexpected error: %v", err)
+	}
+
+	if len(resp.Updated) != 1 || resp.Updated[0].Title != title {
+		t.Errorf("Updated = %+v, want the renamed todo", resp.Updated)
+	}
+	wantFailures := []struct {
+		id  string
+		err error
+	}{
+		{invalid.ID().String(), domain.ErrInvalidStatus},
+		{unknown, domain.ErrTodoNotFound},
+		{renamed.ID().String(), nil},
+	}
+	if len(resp.Failures) != len(wantFailures) {
+		t.Fatalf("Failures = %+v, want %d", resp.Failures, len(wantFailures))
+	}
+	for i, want := range wantFailures {
+		failure := resp.Failures[i]
+		var validationErr domain.ValidationError
+		if failure.TodoID != want.id || (want.err != nil && !errors.Is(failure.Err, want.err)) || (want.err == nil && !errors.As(failure.Err, &validationErr)) {
+			t.Errorf("failure %d = %+v, want %s refused with %v", i, failure, want.id, want.err)
+		}
+	}
+
+	if len(updater.Batches) != 1 || len(updater.Batches[0]) != 1 {
+		t.Errorf("batches = %v, want the renamed todo saved", updater.Batches)
+	}
+	if len(dispatcher.DispatchedEvents) == 0 {
+		t.Fatal("dispatched no event, want the rename")
+	}
+	for _, event := range dispatcher.DispatchedEvents {
+		if event.AggregateID() != renamed.ID().String() {
+			t.Errorf("dispatched %s for %s, want only events of the renamed todo", event.EventType(), event.AggregateID())
+		}
+	}
+}
+
+func TestTodoService_BatchUpdateTodos_Errors(t *testing.T) {
+	todo := createTestTodo()
+	title := "Renamed"
+	one := []BatchTodoUpdate{{TodoID: todo.ID().String(), Fields: UpdateTodoRequest{Title: &title}}}
+	lostErr := errors.New("connection lost")
+
+	tests := []struct {
+		name    string
+		updates []BatchTodoUpdate
+		findErr error
+		updater *MockBatchUpdater
+		wantErr error
+	}{
+		{name: "no update", updates: nil, updater: &MockBatchUpdater{}},
+		{name: "too many updates", updates: make([]BatchTodoUpdate, MaxBatchSize+1), updater: &MockBatchUpdater{}},
+		{name: "lookup failure", updates: one, findErr: lostErr, updater: &MockBatchUpdater{}, wantErr: lostErr},
+		{name: "save failure", updates: one, updater: &MockBatchUpdater{Err: lostErr}, wantErr: lostErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newBatchService(tt.findErr, []Option{WithBatchUpdates(tt.updater)}, todo)
+
+			_, err := service.BatchUpdateTodos(context.Background(), BatchUpdateRequest{Updates: tt.updates})
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("BatchUpdateTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("BatchUpdateTodos() error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.BatchUpdateTodos(context.Background(), BatchUpdateRequest{Updates: one}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("BatchUpdateTodos() without batch updates error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_BatchDeleteTodos(t *testing.T) {
+	first, second, held := createTestTodo(), createTestTodo(), createTestTodo()
+	unknown := domain.NewTodoID().String()
+	deleter := &MockTodoPurger{}
+	holds := &MockLegalHoldStore{Holds: map[domain.TodoID]ports.LegalHold{held.ID(): {TodoID: held.ID()}}}
+	dispatcher := &MockEventDispatcher{}
+	service := newBatchService(nil, []Option{WithBatchDeletes(deleter), WithLegalHolds(holds)}, first, second, held)
+	service.dispatcher = dispatcher
+
+	resp, err := service.BatchDeleteTodos(context.Background(), BatchDeleteRequest{TodoIDs: []string{
+		first.ID().String(), held.ID().String(), unknown, second.ID().String(),
+	}})
+	if err != nil {
+		t.Fatalf("BatchDeleteTodos() unexpected error: %v", err)
+	}
+
+	if len(resp.Deleted) != 2 || resp.Deleted[0] != first.ID().String() || resp.Deleted[1] != second.ID().String() {
+		t.Errorf("Deleted = %v, want the first and second todos", resp.Deleted)
+	}
+	if len(resp.Failures) != 2 || !errors.Is(resp.Failures[0].Err, domain.ErrTodoNotFound) ||
+		resp.Failures[1].TodoID != held.ID().String() || !errors.Is(resp.Failures[1].Err, ErrLegalHold) {
+		t.Errorf("Failures = %+v, want the unknown then the held todo", resp.Failures)
+	}
+	if len(deleter.Deleted) != 2 {
+		t.Errorf("deleted %v, want both free todos at once", deleter.Deleted)
+	}
+	if len(dispatcher.DispatchedEvents) != 2 || dispatcher.DispatchedEvents[0].EventType() != "TodoDeleted" {
+		t.Errorf("dispatched %v, want a TodoDeleted per deleted todo", dispatcher.DispatchedEvents)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.BatchDeleteTodos(context.Background(), BatchDeleteRequest{TodoIDs: []string{unknown}}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("BatchDeleteTodos() without batch deletes error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxBulkComplete is the largest number of todos a BulkCompleteTodos call
+// completes
+const MaxBulkComplete = 500
+
+// WithBulkCompletion enables BulkCompleteTodos
+func WithBulkCompletion(completer ports.TodoBulkCompleter) Option {
+	return func(s *TodoApplicationService) {
+		s.bulkCompleter = completer
+	}
+}
+
+// BulkCompleteTodos completes the open todos of the caller matching req,
+// e.g. every overdue todo of low priority, oldest first
+// The todos are completed by the repository in a single statement rather
+// than read and saved one by one, and a TodoCompleted event is dispatched
+// for each. The authorization policy is therefore asked once, with no todo.
+// At most MaxBulkComplete todos are completed per call, setting More when
+// the limit is reached: calling again completes the others. Todos attached
+// to an archived milestone are left open
+func (s *TodoApplicationService) BulkCompleteTodos(ctx context.Context, req BulkCompleteRequest) (*BulkCompleteResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.bulkCompleter == nil {
+		return nil, ErrNotSupported
+	}
+
+	now := time.Now()
+	filter, err := completionFilter(req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionComplete, nil); err != nil {
+		return nil, err
+	}
+
+	// Todos of archived milestones are read-only
+	if s.milestones != nil {
+		filter.Exclude, err = s.milestones.FrozenTodos(ports.ContextWithSystemAccess(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("finding frozen todos: %w", err)
+		}
+	}
+
+	// Once the todos are completed, a cancelled ctx must not lose their
+	// events
+	var todos []*domain.Todo
+	err = s.commit(context.WithoutCancel(ctx), func(ctx context.Context) ([]domain.DomainEvent, error) {
+		var err error
+		todos, err = s.bulkCompleter.CompleteMatching(ctx, filter, now, MaxBulkComplete)
+		if err != nil {
+			return nil, fmt.Errorf("completing todos: %w", err)
+		}
+		sortOldestFirst(todos)
+
+		events := make([]domain.DomainEvent, len(todos))
+		for i, todo := range todos {
+			events[i] = domain.NewTodoCompletedEvent(todo.ID(), now)
+		}
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Whatever the statement completed must be announced, so the limit is
+	// not probed with an extra todo: More may be set with nothing left
+	response := &BulkCompleteResponse{Completed: []*TodoResponse{}, More: len(todos) == MaxBulkComplete}
+
+	for _, todo := range todos {
+		s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+		s.recordCompletion(ctx, todo.ID())
+		response.Completed = append(response.Completed, MapTodoToResponse(todo))
+	}
+
+	return response, nil
+}
+
+// completionFilter validates req and returns the filter of the todos to
+// complete at now
+func completionFilter(req BulkCompleteRequest, now time.Time) (ports.CompletionFilter, error) {
+	var filter ports.CompletionFilter
+	if req.Status == nil && req.Priority == nil && !req.Overdue {
+		return filter, domain.NewValidationError("filter", "at least one of status, priority or overdue is required")
+	}
+
+	if req.Status != nil {
+		status, err := domain.NewTaskStatus(*req.Status)
+		if err != nil {
+			return filter, fmt.Errorf("invalid status filter: %w", err)
+		}
+		if status != domain.StatusPending && status != domain.StatusInProgress {
+			return filter, domain.NewValidationError("status", "must be pending or in_progress")
+		}
+		filter.Status = &status
+	}
+
+	if req.Priority != nil {
+		priority, err := domain.NewPriority(*req.Priority)
+		if err != nil {
+			return filter, fmt.Errorf("invalid priority filter: %w", err)
+		}
+		filter.Priority = &priority
+	}
+
+	if req.Overdue {
+		filter.DueBefore = &now
+	}
+
+	return filter, nil
+}
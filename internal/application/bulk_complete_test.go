@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockBulkCompleter completes Todos and records the filter it was given
+type MockBulkCompleter struct {
+	Todos   []*domain.Todo
+	Err     error
+	Filters []ports.CompletionFilter
+}
+
+func (m *MockBulkCompleter) CompleteMatching(ctx context.Context, filter ports.CompletionFilter, at time.Time, limit int) ([]*domain.Todo, error) {
+	m.Filters = append(m.Filters, filter)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	for _, todo := range m.Todos {
+		_ = todo.Complete()
+		todo.ClearEvents()
+	}
+	return m.Todos, nil
+}
+
+func TestTodoService_BulkCompleteTodos(t *testing.T) {
+	older, newer := createTestTodo(), createTestTodo()
+	title, _ := domain.NewTaskTitle("Older")
+	older = domain.ReconstituteTodo(older.ID(), title, "", domain.StatusPending, domain.PriorityLow, nil, time.Now().AddDate(0, 0, -1), time.Now(), nil)
+	completer := &MockBulkCompleter{Todos: []*domain.Todo{newer, older}}
+	dispatcher := &MockEventDispatcher{}
+	completions := &MockCompletionLog{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, dispatcher, WithBulkCompletion(completer), WithCompletionLog(completions))
+
+	low := "low"
+	ctx := ContextWithUserID(context.Background(), "alice")
+	resp, err := service.BulkCompleteTodos(ctx, BulkCompleteRequest{Priority: &low, Overdue: true})
+	if err != nil {
+		t.Fatalf("BulkCompleteTodos() unexpected error: %v", err)
+	}
+
+	filter := completer.Filters[0]
+	if filter.Priority == nil || *filter.Priority != domain.PriorityLow || filter.DueBefore == nil || filter.Status != nil {
+		t.Errorf("filter = %+v, want overdue low priority todos", filter)
+	}
+	if len(resp.Completed) != 2 || resp.Completed[0].ID != older.ID().String() || resp.More {
+		t.Errorf("response = %+v, want both todos, oldest first", resp)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 2 {
+		t.Fatalf("dispatched %d events, want one per todo", len(dispatcher.DispatchedEvents))
+	}
+	for i, event := range dispatcher.DispatchedEvents {
+		if _, ok := event.(domain.TodoCompleted); !ok || event.AggregateID() != resp.Completed[i].ID {
+			t.Errorf("event %d = %+v, want the completion of %s", i, event, resp.Completed[i].ID)
+		}
+	}
+	if len(completions.Recorded) != 2 {
+		t.Errorf("recorded %d completions, want 2", len(completions.Recorded))
+	}
+}
+
+func TestTodoService_BulkCompleteTodos_Errors(t *testing.T) {
+	completed, unknown := "completed", "someday"
+	pending := "pending"
+	queryErr := errors.New("connection lost")
+
+	tests := []struct {
+		name      string
+		req       BulkCompleteRequest
+		completer *MockBulkCompleter
+		wantErr   error
+	}{
+		{name: "no condition", req: BulkCompleteRequest{}, completer: &MockBulkCompleter{}},
+		{name: "closed status", req: BulkCompleteRequest{Status: &completed}, completer: &MockBulkCompleter{}},
+		{name: "unknown status", req: BulkCompleteRequest{Status: &unknown}, completer: &MockBulkCompleter{}, wantErr: domain.ErrInvalidStatus},
+		{name: "unknown priority", req: BulkCompleteRequest{Priority: &unknown}, completer: &MockBulkCompleter{}, wantErr: domain.ErrInvalidPriority},
+		{name: "repository failure", req: BulkCompleteRequest{Status: &pending}, completer: &MockBulkCompleter{Err: queryErr}, wantErr: queryErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := &MockEventDispatcher{}
+			service := NewTodoApplicationService(&MockTodoRepository{}, dispatcher, WithBulkCompletion(tt.completer))
+
+			_, err := service.BulkCompleteTodos(context.Background(), tt.req)
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("BulkCompleteTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("BulkCompleteTodos() error = %v, want a validation error", err)
+			}
+			if len(dispatcher.DispatchedEvents) != 0 {
+				t.Errorf("dispatched %d events, want none", len(dispatcher.DispatchedEvents))
+			}
+		})
+	}
+
+	denied := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{},
+		WithBulkCompletion(&MockBulkCompleter{}), WithAuthorizer(&MockAuthorizer{Allow: false}))
+	if _, err := denied.BulkCompleteTodos(context.Background(), BulkCompleteRequest{Status: &pending}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("BulkCompleteTodos() denied error = %v, want %v", err, ErrForbidden)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.BulkCompleteTodos(context.Background(), BulkCompleteRequest{Status: &pending}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("BulkCompleteTodos() without bulk completion error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// CacheHeatmaps names the completion heatmap cache, keyed by user ID
+const CacheHeatmaps = "heatmaps"
+
+// WithCacheInvalidation evicts the entries invalidated through this instance
+// on every instance, through bus
+// Without it the other instances serve their entries until they expire
+func WithCacheInvalidation(bus ports.CacheInvalidationBus) Option {
+	return func(s *TodoApplicationService) {
+		s.invalidations = bus
+	}
+}
+
+// InvalidateCache evicts the entries of invalidation from the caches of this
+// instance, e.g. when another instance published it
+func (s *TodoApplicationService) InvalidateCache(invalidation ports.CacheInvalidation) {
+	if s.todos != nil && (invalidation.Cache == "" || invalidation.Cache == CacheTodos) {
+		s.todos.Invalidate(invalidation.Key)
+	}
+	if s.heatmaps == nil || (invalidation.Cache != "" && invalidation.Cache != CacheHeatmaps) {
+		return
+	}
+
+	if invalidation.Cache == "" || invalidation.Key == "" {
+		s.heatmaps.Purge()
+		return
+	}
+	s.heatmaps.Remove(invalidation.Key)
+}
+
+// invalidateCache evicts the entries of invalidation here at once, then on
+// every instance
+// Publishing is best effort: the cache TTLs bound the staleness of the other
+// instances when it fails
+func (s *TodoApplicationService) invalidateCache(ctx context.Context, invalidation ports.CacheInvalidation) {
+	s.InvalidateCache(invalidation)
+	if s.invalidations != nil {
+		_ = s.invalidations.Publish(ctx, invalidation)
+	}
+}
+
+// CacheInvalidator evicts the cached todos changed by the dispatched events,
+// on this instance and, through the cache invalidation bus of the service,
+// on every other
+type CacheInvalidator struct {
+	service    *TodoApplicationService
+	subscriber ports.EventSubscriber
+	logger     *slog.Logger
+}
+
+// NewCacheInvalidator creates a new CacheInvalidator
+func NewCacheInvalidator(service *TodoApplicationService, subscriber ports.EventSubscriber, logger *slog.Logger) *CacheInvalidator {
+	return &CacheInvalidator{
+		service:    service,
+		subscriber: subscriber,
+		logger:     logger,
+	}
+}
+
+// Run evicts the todos of the dispatched events until ctx is done
+// Falling behind the events evicts every todo, as some changes were missed
+func (i *CacheInvalidator) Run(ctx context.Context) {
+	events := i.subscriber.Subscribe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				i.logger.Warn("cache invalidator fell behind the events, evicting every cached todo and resubscribing")
+				i.service.invalidateCache(ctx, ports.CacheInvalidation{Cache: CacheTodos})
+				events = i.subscriber.Subscribe(ctx)
+				continue
+			}
+			i.Invalidate(ctx, event)
+		}
+	}
+}
+
+// Invalidate evicts the todos changed by event
+func (i *CacheInvalidator) Invalidate(ctx context.Context, event domain.DomainEvent) {
+	for _, id := range changedTodos(event) {
+		i.service.invalidateCache(ctx, ports.CacheInvalidation{Cache: CacheTodos, Key: id})
+	}
+}
+
+// changedTodos returns the IDs of the todos event changed; reminders and the
+// events of other aggregates change none
+func changedTodos(event domain.DomainEvent) []string {
+	switch e := event.(type) {
+	case domain.TodoMerged:
+		return []string{e.AggregateID(), e.CanonicalID}
+	case domain.TodoUpdated, domain.TodoCompleted, domain.TodoDeleted, domain.TodoReopened,
+		domain.TodoForceUpdated, domain.TodoMoved, domain.TodoArchived, domain.TodoUnarchived:
+		return []string{event.AggregateID()}
+	default:
+		return nil
+	}
+}
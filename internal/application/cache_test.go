@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockCacheInvalidationBus records the published invalidations
+type MockCacheInvalidationBus struct {
+	Published []ports.CacheInvalidation
+}
+
+func (m *MockCacheInvalidationBus) Publish(ctx context.Context, invalidation ports.CacheInvalidation) error {
+	m.Published = append(m.Published, invalidation)
+	return nil
+}
+
+func TestTodoService_CompleteTodo_PublishesHeatmapInvalidation(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	bus := &MockCacheInvalidationBus{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{},
+		WithCompletionLog(&MockCompletionLog{}), WithCacheInvalidation(bus))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	if _, err := service.CompleteTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("CompleteTodo() unexpected error: %v", err)
+	}
+
+	want := ports.CacheInvalidation{Cache: CacheHeatmaps, Key: "alice"}
+	if len(bus.Published) != 1 || bus.Published[0] != want {
+		t.Errorf("Published = %v, want [%v]", bus.Published, want)
+	}
+}
+
+func TestTodoService_InvalidateCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		invalidation ports.CacheInvalidation
+		wantQueries  int
+	}{
+		{"entry of the user", ports.CacheInvalidation{Cache: CacheHeatmaps, Key: "alice"}, 2},
+		{"entry of another user", ports.CacheInvalidation{Cache: CacheHeatmaps, Key: "bob"}, 1},
+		{"whole cache", ports.CacheInvalidation{Cache: CacheHeatmaps}, 2},
+		{"every cache", ports.CacheInvalidation{}, 2},
+		{"another cache", ports.CacheInvalidation{Cache: "profiles", Key: "alice"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &MockCompletionLog{Counts: []ports.DailyCount{
+				{Day: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+			}}
+			service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCompletionLog(log))
+			ctx := ContextWithUserID(context.Background(), "alice")
+
+			if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+				t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+			}
+			service.InvalidateCache(tt.invalidation)
+			if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+				t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+			}
+
+			if log.Queries != tt.wantQueries {
+				t.Errorf("DailyCompletions() called %d times, want %d", log.Queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidator_Invalidate(t *testing.T) {
+	id := domain.NewTodoID()
+	canonical := domain.NewTodoID()
+
+	tests := []struct {
+		name  string
+		event domain.DomainEvent
+		want  []string
+	}{
+		{"updated", domain.NewTodoUpdatedEvent(id), []string{id.String()}},
+		{"completed", domain.NewTodoCompletedEvent(id, time.Now()), []string{id.String()}},
+		{"deleted", domain.NewTodoDeletedEvent(id, ""), []string{id.String()}},
+		{"merged", domain.NewTodoMergedEvent(id, canonical), []string{id.String(), canonical.String()}},
+		{"anomaly", SecurityAnomalyDetected{Kind: AnomalyCanaryAccess, todoID: id.String()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &MockCacheInvalidationBus{}
+			service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{},
+				WithTodoCache(NewTodoCache(10, time.Minute)), WithCacheInvalidation(bus))
+			invalidator := NewCacheInvalidator(service, &MockEventSubscriber{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			invalidator.Invalidate(context.Background(), tt.event)
+
+			var got []string
+			for _, invalidation := range bus.Published {
+				if invalidation.Cache != CacheTodos {
+					t.Errorf("published %v, want an invalidation of %s", invalidation, CacheTodos)
+				}
+				got = append(got, invalidation.Key)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("evicted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidator_Run(t *testing.T) {
+	testTodo := createTestTodo()
+	queries := 0
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			queries++
+			return testTodo, nil
+		},
+	}
+	bus := &MockCacheInvalidationBus{}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{},
+		WithTodoCache(NewTodoCache(10, time.Minute)), WithCacheInvalidation(bus))
+	events := make(chan domain.DomainEvent)
+	invalidator := NewCacheInvalidator(service, &MockEventSubscriber{Events: events}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		invalidator.Run(ctx)
+	}()
+
+	id := testTodo.ID().String()
+	if _, err := service.GetTodo(ctx, id); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+	events <- domain.NewTodoCompletedEvent(testTodo.ID(), time.Now())
+	cancel()
+	<-done
+
+	if _, err := service.GetTodo(context.Background(), id); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("FindByID() called %d times, want the todo evicted", queries)
+	}
+	if len(bus.Published) != 1 || bus.Published[0] != (ports.CacheInvalidation{Cache: CacheTodos, Key: id}) {
+		t.Errorf("published %v, want the eviction of the completed todo", bus.Published)
+	}
+}
@@ -0,0 +1,257 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxHolidayNameLength is the longest name a holiday can have
+const MaxHolidayNameLength = 200
+
+// calendarDayLayout is the format of the days of the business calendar
+const calendarDayLayout = "2006-01-02"
+
+// maxSkippedDays bounds the non-working days addWorkingTime skips, so a
+// calendar made only of holidays cannot loop forever
+const maxSkippedDays = 3660
+
+// ErrHolidayNotFound is returned when deleting a day that is not a holiday
+var ErrHolidayNotFound = errors.New("holiday not found")
+
+// CalendarOptions describes the working week of the business calendar
+type CalendarOptions struct {
+	// WorkingDays are the days of the week people work on
+	WorkingDays []time.Weekday
+	// Location is the time zone of the calendar days
+	Location *time.Location
+}
+
+// DefaultCalendarOptions works from Monday to Friday, in UTC
+func DefaultCalendarOptions() CalendarOptions {
+	return CalendarOptions{
+		WorkingDays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Location:    time.UTC,
+	}
+}
+
+// BusinessCalendar tells the working days from the weekends and holidays
+// Holidays are read from the store each time the calendar is used, so a
+// holiday saved through any instance applies to all of them
+type BusinessCalendar struct {
+	options  CalendarOptions
+	holidays ports.HolidayStore
+}
+
+// NewBusinessCalendar creates a new BusinessCalendar; without a store, the
+// calendar has no holidays
+func NewBusinessCalendar(options CalendarOptions, holidays ports.HolidayStore) *BusinessCalendar {
+	return &BusinessCalendar{
+		options:  options,
+		holidays: holidays,
+	}
+}
+
+// load returns the working days in effect
+func (c *BusinessCalendar) load(ctx context.Context) (workingDays, error) {
+	days := workingDays{options: c.options, holidays: map[string]string{}}
+	if c.holidays == nil {
+		return days, nil
+	}
+
+	holidays, err := c.holidays.List(ctx)
+	if err != nil {
+		return days, fmt.Errorf("listing holidays: %w", err)
+	}
+	for _, holiday := range holidays {
+		days.holidays[holiday.Day.UTC().Format(calendarDayLayout)] = holiday.Name
+	}
+
+	return days, nil
+}
+
+// workingDays is the business calendar with its holidays loaded
+type workingDays struct {
+	options CalendarOptions
+	// holidays maps the days of the holidays to their name
+	holidays map[string]string
+}
+
+// holiday returns the name of the holiday on the calendar day of t, if any
+func (w workingDays) holiday(t time.Time) (string, bool) {
+	name, ok := w.holidays[t.In(w.options.Location).Format(calendarDayLayout)]
+	return name, ok
+}
+
+// isWorkingDay reports whether the calendar day of t is worked
+func (w workingDays) isWorkingDay(t time.Time) bool {
+	if _, ok := w.holiday(t); ok {
+		return false
+	}
+
+	weekday := t.In(w.options.Location).Weekday()
+	for _, working := range w.options.WorkingDays {
+		if weekday == working {
+			return true
+		}
+	}
+	return false
+}
+
+// addWorkingTime returns the earliest time d of working days after t:
+// weekends and holidays do not count
+// It never goes back when t does, which lets reminder scans cover
+// consecutive windows; without working days, every day counts
+func (w workingDays) addWorkingTime(t time.Time, d time.Duration) time.Time {
+	if len(w.options.WorkingDays) == 0 {
+		return t.Add(d)
+	}
+
+	t = t.In(w.options.Location)
+	for skipped := 0; d > 0; {
+		year, month, day := t.Date()
+		next := time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+
+		if !w.isWorkingDay(t) {
+			if skipped++; skipped > maxSkippedDays {
+				return t.Add(d)
+			}
+			t = next
+			continue
+		}
+
+		step := next.Sub(t)
+		if step >= d {
+			return t.Add(d)
+		}
+		t, d = next, d-step
+	}
+	return t
+}
+
+// WithBusinessCalendar sets the working days and holidays used by PlanWeek
+// and exposes the holidays for management; without it, DefaultCalendarOptions
+// apply and there is no holiday
+func WithBusinessCalendar(calendar *BusinessCalendar) Option {
+	return func(s *TodoApplicationService) {
+		s.calendar = calendar
+	}
+}
+
+// businessCalendar returns the configured calendar, or the default one
+func (s *TodoApplicationService) businessCalendar() *BusinessCalendar {
+	if s.calendar == nil {
+		return NewBusinessCalendar(DefaultCalendarOptions(), nil)
+	}
+	return s.calendar
+}
+
+// GetBusinessCalendar returns the working week and the holidays of the
+// business calendar
+func (s *TodoApplicationService) GetBusinessCalendar(ctx context.Context) (*BusinessCalendarResponse, error) {
+	calendar := s.businessCalendar()
+	days, err := calendar.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &BusinessCalendarResponse{
+		WorkingDays: make([]string, len(calendar.options.WorkingDays)),
+		TimeZone:    calendar.options.Location.String(),
+		Holidays:    make([]*HolidayResponse, 0, len(days.holidays)),
+	}
+	for i, weekday := range calendar.options.WorkingDays {
+		response.WorkingDays[i] = weekday.String()
+	}
+	for day, name := range days.holidays {
+		response.Holidays = append(response.Holidays, &HolidayResponse{Day: day, Name: name})
+	}
+	// Days as YYYY-MM-DD sort chronologically
+	sort.Slice(response.Holidays, func(i, j int) bool {
+		return response.Holidays[i].Day < response.Holidays[j].Day
+	})
+
+	return response, nil
+}
+
+// SetHoliday makes day, as YYYY-MM-DD, a holiday named after req, renaming
+// the holiday already on that day
+func (s *TodoApplicationService) SetHoliday(ctx context.Context, day string, req HolidayRequest) (*HolidayResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	store, err := s.holidayStore()
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseCalendarDay(day)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", "is required")
+	}
+	if len(name) > MaxHolidayNameLength {
+		return nil, domain.NewValidationError("name", fmt.Sprintf("must be at most %d characters", MaxHolidayNameLength))
+	}
+
+	if err := store.Save(ctx, ports.Holiday{Day: date, Name: name}); err != nil {
+		return nil, fmt.Errorf("saving holiday: %w", err)
+	}
+
+	return &HolidayResponse{Day: date.Format(calendarDayLayout), Name: name}, nil
+}
+
+// DeleteHoliday makes day, as YYYY-MM-DD, an ordinary day again
+func (s *TodoApplicationService) DeleteHoliday(ctx context.Context, day string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	store, err := s.holidayStore()
+	if err != nil {
+		return err
+	}
+
+	date, err := parseCalendarDay(day)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := store.Delete(ctx, date)
+	if err != nil {
+		return fmt.Errorf("deleting holiday: %w", err)
+	}
+	if !deleted {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+// holidayStore returns the store of the holidays, or ErrNotSupported
+func (s *TodoApplicationService) holidayStore() (ports.HolidayStore, error) {
+	if s.calendar == nil || s.calendar.holidays == nil {
+		return nil, ErrNotSupported
+	}
+	return s.calendar.holidays, nil
+}
+
+// parseCalendarDay parses a day as YYYY-MM-DD, at midnight UTC
+func parseCalendarDay(day string) (time.Time, error) {
+	date, err := time.Parse(calendarDayLayout, day)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError("day", "must be a day as YYYY-MM-DD")
+	}
+	return date, nil
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Calendar feed window and size
+const (
+	// CalendarFeedPast is how long todos stay in the feed after they were due
+	CalendarFeedPast = 90 * 24 * time.Hour
+	// CalendarFeedAhead is how far ahead of now the feed looks
+	CalendarFeedAhead = 365 * 24 * time.Hour
+	// MaxCalendarFeedTodos bounds the todos of a feed, the earliest due kept
+	MaxCalendarFeedTodos = 1000
+)
+
+// WithCalendarFeed enables CalendarFeed
+func WithCalendarFeed(finder ports.DatedTodoFinder) Option {
+	return func(s *TodoApplicationService) {
+		s.datedFinder = finder
+	}
+}
+
+// CalendarFeed returns the unarchived todos of the caller due from
+// CalendarFeedPast ago to CalendarFeedAhead from now, whatever their status,
+// earliest due first, for calendar subscriptions
+// Completed and cancelled todos stay in the feed, so that calendar clients
+// show them as done rather than dropping them
+func (s *TodoApplicationService) CalendarFeed(ctx context.Context) ([]*TodoResponse, error) {
+	if s.datedFinder == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	todos, err := s.datedFinder.FindDatedBetween(ctx, now.Add(-CalendarFeedPast), now.Add(CalendarFeedAhead), MaxCalendarFeedTodos)
+	if err != nil {
+		return nil, fmt.Errorf("finding dated todos: %w", err)
+	}
+
+	responses := make([]*TodoResponse, len(todos))
+	for i, todo := range todos {
+		responses[i] = MapTodoToResponse(todo)
+	}
+	return responses, nil
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockDatedTodoFinder records the window of FindDatedBetween
+type MockDatedTodoFinder struct {
+	Todos    []*domain.Todo
+	Err      error
+	GotFrom  time.Time
+	GotTo    time.Time
+	GotLimit int
+	Calls    int
+}
+
+func (m *MockDatedTodoFinder) FindDatedBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	m.Calls++
+	m.GotFrom, m.GotTo, m.GotLimit = from, to, limit
+	return m.Todos, m.Err
+}
+
+func TestTodoService_CalendarFeed(t *testing.T) {
+	testTodo := createTestTodo()
+	finder := &MockDatedTodoFinder{Todos: []*domain.Todo{testTodo}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCalendarFeed(finder))
+
+	before := time.Now()
+	todos, err := service.CalendarFeed(context.Background())
+
+	if err != nil {
+		t.Fatalf("CalendarFeed() unexpected error: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != testTodo.ID().String() {
+		t.Errorf("CalendarFeed() = %v, want the test todo", todos)
+	}
+	if finder.GotFrom.Before(before.Add(-CalendarFeedPast)) || finder.GotTo.Sub(finder.GotFrom) != CalendarFeedPast+CalendarFeedAhead || finder.GotLimit != MaxCalendarFeedTodos {
+		t.Errorf("FindDatedBetween() called with %v, %v, %d, want the feed window", finder.GotFrom, finder.GotTo, finder.GotLimit)
+	}
+}
+
+func TestTodoService_CalendarFeed_Errors(t *testing.T) {
+	errQuery := errors.New("connection reset")
+
+	tests := []struct {
+		name    string
+		service *TodoApplicationService
+		want    error
+	}{
+		{"not configured", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}), ErrNotSupported},
+		{"denied", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCalendarFeed(&MockDatedTodoFinder{}), WithAuthorizer(&MockAuthorizer{Allow: false})), ErrForbidden},
+		{"query fails", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCalendarFeed(&MockDatedTodoFinder{Err: errQuery})), errQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.service.CalendarFeed(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("CalendarFeed() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
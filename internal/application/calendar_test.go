@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockHolidayStore keeps holidays in memory
+type MockHolidayStore struct {
+	Holidays map[time.Time]string
+	Err      error
+}
+
+func (m *MockHolidayStore) Save(ctx context.Context, holiday ports.Holiday) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Holidays == nil {
+		m.Holidays = map[time.Time]string{}
+	}
+	m.Holidays[holiday.Day] = holiday.Name
+	return nil
+}
+
+func (m *MockHolidayStore) Delete(ctx context.Context, day time.Time) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	_, ok := m.Holidays[day]
+	delete(m.Holidays, day)
+	return ok, nil
+}
+
+func (m *MockHolidayStore) List(ctx context.Context) ([]ports.Holiday, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var holidays []ports.Holiday
+	for day, name := range m.Holidays {
+		holidays = append(holidays, ports.Holiday{Day: day, Name: name})
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Day.Before(holidays[j].Day) })
+	return holidays, nil
+}
+
+// calendarDay returns day of March 2026 at midnight UTC; the 2nd is a Monday
+func calendarDay(day int) time.Time {
+	return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestWorkingDays_AddWorkingTime(t *testing.T) {
+	holidays := &MockHolidayStore{Holidays: map[time.Time]string{calendarDay(9): "Founders' Day"}}
+	plusTen := time.FixedZone("UTC+10", 10*60*60)
+
+	tests := []struct {
+		name    string
+		options CalendarOptions
+		from    time.Time
+		lead    time.Duration
+		want    time.Time
+	}{
+		{"within the week", DefaultCalendarOptions(), calendarDay(2).Add(9 * time.Hour), 24 * time.Hour, calendarDay(3).Add(9 * time.Hour)},
+		{"over a weekend and a holiday", DefaultCalendarOptions(), calendarDay(6).Add(9 * time.Hour), 24 * time.Hour, calendarDay(10).Add(9 * time.Hour)},
+		{"from a weekend", DefaultCalendarOptions(), calendarDay(7).Add(10 * time.Hour), time.Hour, calendarDay(10).Add(time.Hour)},
+		{"no lead", DefaultCalendarOptions(), calendarDay(7).Add(10 * time.Hour), 0, calendarDay(7).Add(10 * time.Hour)},
+		// Friday 20:00 UTC is already Saturday in UTC+10
+		{"in the calendar time zone", CalendarOptions{WorkingDays: []time.Weekday{time.Sunday}, Location: plusTen}, calendarDay(6).Add(20 * time.Hour), time.Hour, calendarDay(7).Add(15 * time.Hour)},
+		{"without working day", CalendarOptions{Location: time.UTC}, calendarDay(6), time.Hour, calendarDay(6).Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := NewBusinessCalendar(tt.options, holidays).load(context.Background())
+			if err != nil {
+				t.Fatalf("load() unexpected error: %v", err)
+			}
+
+			if got := days.addWorkingTime(tt.from, tt.lead); !got.Equal(tt.want) {
+				t.Errorf("addWorkingTime(%v, %v) = %v, want %v", tt.from, tt.lead, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoService_Holidays(t *testing.T) {
+	store := &MockHolidayStore{}
+	calendar := NewBusinessCalendar(DefaultCalendarOptions(), store)
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithBusinessCalendar(calendar))
+	ctx := context.Background()
+
+	for _, day := range []string{"2026-12-25", "2026-01-01"} {
+		if _, err := service.SetHoliday(ctx, day, HolidayRequest{Name: "Holiday"}); err != nil {
+			t.Fatalf("SetHoliday(%s) unexpected error: %v", day, err)
+		}
+	}
+	holiday, err := service.SetHoliday(ctx, "2026-12-25", HolidayRequest{Name: " Christmas "})
+	if err != nil || *holiday != (HolidayResponse{Day: "2026-12-25", Name: "Christmas"}) {
+		t.Fatalf("SetHoliday() again = %+v, %v, want the renamed holiday", holiday, err)
+	}
+
+	got, err := service.GetBusinessCalendar(ctx)
+	if err != nil {
+		t.Fatalf("GetBusinessCalendar() unexpected error: %v", err)
+	}
+	if len(got.WorkingDays) != 5 || got.WorkingDays[0] != "Monday" || got.TimeZone != "UTC" {
+		t.Errorf("GetBusinessCalendar() = %+v, want the default working week", got)
+	}
+	if len(got.Holidays) != 2 || got.Holidays[0].Day != "2026-01-01" || got.Holidays[1].Name != "Christmas" {
+		t.Errorf("Holidays = %+v, want New Year then Christmas", got.Holidays)
+	}
+
+	if err := service.DeleteHoliday(ctx, "2026-12-25"); err != nil {
+		t.Errorf("DeleteHoliday() unexpected error: %v", err)
+	}
+	if err := service.DeleteHoliday(ctx, "2026-12-25"); !errors.Is(err, ErrHolidayNotFound) {
+		t.Errorf("DeleteHoliday() again error = %v, want %v", err, ErrHolidayNotFound)
+	}
+}
+
+func TestTodoService_SetHoliday_Errors(t *testing.T) {
+	storeErr := errors.New("connection lost")
+
+	tests := []struct {
+		name    string
+		day     string
+		holiday string
+		store   ports.HolidayStore
+		wantErr error
+	}{
+		{name: "not a day", day: "25/12/2026", holiday: "Christmas", store: &MockHolidayStore{}},
+		{name: "no name", day: "2026-12-25", holiday: " ", store: &MockHolidayStore{}},
+		{name: "name too long", day: "2026-12-25", holiday: strings.Repeat("x", MaxHolidayNameLength+1), store: &MockHolidayStore{}},
+		{name: "store failure", day: "2026-12-25", holiday: "Christmas", store: &MockHolidayStore{Err: storeErr}, wantErr: storeErr},
+		{name: "no store", day: "2026-12-25", holiday: "Christmas", wantErr: ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := NewBusinessCalendar(DefaultCalendarOptions(), tt.store)
+			service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithBusinessCalendar(calendar))
+
+			_, err := service.SetHoliday(context.Background(), tt.day, HolidayRequest{Name: tt.holiday})
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetHoliday() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("SetHoliday() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrCanaryNotFound is returned when deleting a todo that is not a canary
+var ErrCanaryNotFound = errors.New("canary todo not found")
+
+// WithCanaries enables canary todos, decoys hidden from listings whose access
+// raises a SecurityAnomalyDetected event
+func WithCanaries(finder ports.CanaryFinder) Option {
+	return func(s *TodoApplicationService) {
+		s.canaries = finder
+	}
+}
+
+// CreateCanaryTodo creates a canary todo
+// Canaries look like any other todo but are left out of listings, searches
+// and the activity feed, so only someone enumerating IDs or reading the
+// database finds them
+func (s *TodoApplicationService) CreateCanaryTodo(ctx context.Context, req CreateCanaryRequest) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.canaries == nil {
+		return nil, ErrNotSupported
+	}
+
+	title, err := domain.NewTaskTitle(req.Title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title: %w", err)
+	}
+
+	todo := domain.NewCanaryTodo(title, req.Description)
+	if err := s.repository.Save(ctx, todo); err != nil {
+		return nil, fmt.Errorf("saving canary todo: %w", err)
+	}
+
+	return MapTodoToResponse(todo), nil
+}
+
+// ListCanaryTodos returns every canary todo, newest first
+func (s *TodoApplicationService) ListCanaryTodos(ctx context.Context) ([]*TodoResponse, error) {
+	if s.canaries == nil {
+		return nil, ErrNotSupported
+	}
+
+	todos, err := s.canaries.FindCanaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding canaries: %w", err)
+	}
+
+	responses := make([]*TodoResponse, len(todos))
+	for i, todo := range todos {
+		responses[i] = MapTodoToResponse(todo)
+	}
+
+	return responses, nil
+}
+
+// DeleteCanaryTodo deletes a canary todo without raising an alert
+func (s *TodoApplicationService) DeleteCanaryTodo(ctx context.Context, id string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.canaries == nil {
+		return ErrNotSupported
+	}
+
+	todoID, err := domain.ParseTodoID(id)
+	if err != nil {
+		return fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if errors.Is(err, domain.ErrTodoNotFound) {
+		return ErrCanaryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("finding todo: %w", err)
+	}
+	if !todo.IsCanary() {
+		return ErrCanaryNotFound
+	}
+
+	if err := s.repository.Delete(ctx, todoID); err != nil {
+		return fmt.Errorf("deleting canary todo: %w", err)
+	}
+
+	return nil
+}
+
+// tripCanary raises a canary_access anomaly when todo is a canary
+// The operation itself goes on, so the intruder is not warned
+func (s *TodoApplicationService) tripCanary(ctx context.Context, todo *domain.Todo) error {
+	if !todo.IsCanary() {
+		return nil
+	}
+
+	actor, _ := UserIDFromContext(ctx)
+	alert := SecurityAnomalyDetected{
+		Kind:       AnomalyCanaryAccess,
+		Detail:     "canary todo accessed",
+		Actor:      actor,
+		todoID:     todo.ID().String(),
+		occurredAt: time.Now(),
+	}
+	if err := s.dispatcher.Dispatch(ctx, []domain.DomainEvent{alert}); err != nil {
+		return fmt.Errorf("dispatching events: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockCanaryFinder serves a fixed list of canaries
+type MockCanaryFinder struct {
+	Canaries []*domain.Todo
+}
+
+func (m *MockCanaryFinder) FindCanaries(ctx context.Context) ([]*domain.Todo, error) {
+	return m.Canaries, nil
+}
+
+func canaryTestTodo() *domain.Todo {
+	title, _ := domain.NewTaskTitle("Payroll credentials")
+	return domain.NewCanaryTodo(title, "Decoy")
+}
+
+func TestTodoService_CreateCanaryTodo(t *testing.T) {
+	var saved *domain.Todo
+	mockRepo := &MockTodoRepository{
+		SaveFunc: func(ctx context.Context, todo *domain.Todo) error {
+			saved = todo
+			return nil
+		},
+	}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(mockRepo, dispatcher, WithCanaries(&MockCanaryFinder{}))
+
+	response, err := service.CreateCanaryTodo(context.Background(), CreateCanaryRequest{Title: "Payroll credentials"})
+	if err != nil {
+		t.Fatalf("CreateCanaryTodo() unexpected error: %v", err)
+	}
+	if saved == nil || !saved.IsCanary() || response.ID != saved.ID().String() {
+		t.Errorf("saved %+v, want a canary", saved)
+	}
+	if len(dispatcher.DispatchedEvents) != 0 {
+		t.Errorf("dispatched %d events, want none for a canary", len(dispatcher.DispatchedEvents))
+	}
+
+	unsupported := NewTodoApplicationService(mockRepo, dispatcher)
+	if _, err := unsupported.CreateCanaryTodo(context.Background(), CreateCanaryRequest{Title: "Decoy"}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("without canaries error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_GetTodo_CanaryRaisesAlert(t *testing.T) {
+	canary := canaryTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return canary, nil
+		},
+	}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(mockRepo, dispatcher, WithCanaries(&MockCanaryFinder{}))
+	ctx := ContextWithUserID(context.Background(), "mallory")
+
+	// The access succeeds so the intruder is not warned
+	if _, err := service.GetTodo(ctx, canary.ID().String()); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 1 {
+		t.Fatalf("dispatched %d events, want 1 alert", len(dispatcher.DispatchedEvents))
+	}
+	alert, ok := dispatcher.DispatchedEvents[0].(SecurityAnomalyDetected)
+	if !ok || alert.Kind != AnomalyCanaryAccess || alert.Actor != "mallory" || alert.AggregateID() != canary.ID().String() {
+		t.Errorf("dispatched %+v, want a canary access alert by mallory", dispatcher.DispatchedEvents[0])
+	}
+}
+
+func TestTodoService_DeleteTodo_CanaryRaisesAlert(t *testing.T) {
+	canary := canaryTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return canary, nil
+		},
+	}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(mockRepo, dispatcher, WithCanaries(&MockCanaryFinder{}))
+
+	if err := service.DeleteTodo(context.Background(), canary.ID().String()); err != nil {
+		t.Fatalf("DeleteTodo() unexpected error: %v", err)
+	}
+	if len(dispatcher.DispatchedEvents) != 2 || dispatcher.DispatchedEvents[0].EventType() != "SecurityAnomalyDetected" {
+		t.Errorf("dispatched %v, want the alert then the deletion", dispatcher.DispatchedEvents)
+	}
+}
+
+func TestTodoService_DeleteCanaryTodo(t *testing.T) {
+	canary, regular := canaryTestTodo(), createTestTodo()
+	var deleted []domain.TodoID
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if id == canary.ID() {
+				return canary, nil
+			}
+			return regular, nil
+		},
+		DeleteFunc: func(ctx context.Context, id domain.TodoID) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(mockRepo, dispatcher, WithCanaries(&MockCanaryFinder{}))
+
+	if err := service.DeleteCanaryTodo(context.Background(), regular.ID().String()); !errors.Is(err, ErrCanaryNotFound) {
+		t.Errorf("DeleteCanaryTodo() of a regular todo error = %v, want %v", err, ErrCanaryNotFound)
+	}
+
+	if err := service.DeleteCanaryTodo(context.Background(), canary.ID().String()); err != nil {
+		t.Fatalf("DeleteCanaryTodo() unexpected error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != canary.ID() {
+		t.Errorf("deleted %v, want only %v", deleted, canary.ID())
+	}
+	if len(dispatcher.DispatchedEvents) != 0 {
+		t.Errorf("dispatched %d events, want none", len(dispatcher.DispatchedEvents))
+	}
+}
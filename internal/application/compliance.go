@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxComplianceReportEntries is the largest number of entries a single
+// compliance report can list
+const MaxComplianceReportEntries = 50000
+
+// ComplianceActionViewed is the action of the compliance entries reporting
+// the last view of a todo by a user
+const ComplianceActionViewed = "viewed"
+
+// WithComplianceReports enables GenerateComplianceReport from the audit log
+// of the privileged actions, the audit trail of every change to the todos
+// and the last views of each user; trail and views may be nil
+func WithComplianceReports(reader ports.AuditLogReader, trail ports.TodoAuditScanner, views ports.RecentActivityScanner) Option {
+	return func(s *TodoApplicationService) {
+		s.auditReader = reader
+		s.auditScanner = trail
+		s.viewScanner = views
+	}
+}
+
+// GenerateComplianceReport compiles who accessed or modified which todos
+// over a period: the privileged actions of the audit log, the changes of
+// the todo audit trail, by their event type, and the last views
+// Periods holding more than MaxComplianceReportEntries entries are refused
+// rather than truncated, so a report is always complete
+func (s *TodoApplicationService) GenerateComplianceReport(ctx context.Context, req ComplianceReportRequest) (*ComplianceReport, error) {
+	if s.auditReader == nil {
+		return nil, ErrNotSupported
+	}
+
+	if req.From.IsZero() || req.To.IsZero() {
+		return nil, domain.NewValidationError("period", "from and to are required")
+	}
+	if !req.From.Before(req.To) {
+		return nil, domain.NewValidationError("period", "from must be before to")
+	}
+
+	report := &ComplianceReport{
+		From:        req.From,
+		To:          req.To,
+		GeneratedAt: time.Now(),
+	}
+	tooMany := domain.NewValidationError("period", fmt.Sprintf("holds more than %d audit entries, narrow it", MaxComplianceReportEntries))
+
+	entries, err := s.auditReader.ListEntries(ctx, req.From, req.To, MaxComplianceReportEntries+1)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit entries: %w", err)
+	}
+	for _, entry := range entries {
+		report.Entries = append(report.Entries, ComplianceEntry{
+			OccurredAt: entry.OccurredAt,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			TodoID:     entry.TodoID,
+			Reason:     entry.Reason,
+			Changes:    entry.Changes,
+		})
+	}
+	if len(report.Entries) > MaxComplianceReportEntries {
+		return nil, tooMany
+	}
+
+	if s.auditScanner != nil {
+		changes, err := s.auditScanner.ListBetween(ctx, req.From, req.To, 0, MaxComplianceReportEntries+1-len(report.Entries))
+		if err != nil {
+			return nil, fmt.Errorf("listing todo changes: %w", err)
+		}
+		for _, change := range changes {
+			report.Entries = append(report.Entries, ComplianceEntry{
+				OccurredAt: change.OccurredAt,
+				Actor:      change.Actor,
+				Action:     change.EventType,
+				TodoID:     change.TodoID,
+				Changes:    change.Changes,
+			})
+		}
+		if len(report.Entries) > MaxComplianceReportEntries {
+			return nil, tooMany
+		}
+	}
+
+	if s.viewScanner != nil {
+		views, err := s.viewScanner.ListViewsBetween(ctx, req.From, req.To, MaxComplianceReportEntries+1-len(report.Entries))
+		if err != nil {
+			return nil, fmt.Errorf("listing todo views: %w", err)
+		}
+		for _, view := range views {
+			report.Entries = append(report.Entries, ComplianceEntry{
+				OccurredAt: view.ViewedAt,
+				Actor:      view.UserID,
+				Action:     ComplianceActionViewed,
+				TodoID:     view.TodoID.String(),
+			})
+		}
+		if len(report.Entries) > MaxComplianceReportEntries {
+			return nil, tooMany
+		}
+	}
+
+	// Each source is in order; the stable sort keeps their order on ties
+	slices.SortStableFunc(report.Entries, func(a, b ComplianceEntry) int {
+		return a.OccurredAt.Compare(b.OccurredAt)
+	})
+
+	return report, nil
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockAuditLogReader serves the audit entries of each queried period
+type MockAuditLogReader struct {
+	Entries []ports.AuditEntry
+}
+
+func (m *MockAuditLogReader) ListEntries(ctx context.Context, from, to time.Time, limit int) ([]ports.AuditEntry, error) {
+	var entries []ports.AuditEntry
+	for _, entry := range m.Entries {
+		if !entry.OccurredAt.Before(from) && entry.OccurredAt.Before(to) && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestTodoService_GenerateComplianceReport(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	reader := &MockAuditLogReader{Entries: []ports.AuditEntry{
+		{Action: AuditActionPurge, Actor: "before", OccurredAt: from.Add(-time.Second)},
+		{Action: AuditActionForceUpdate, Actor: "ops", Reason: "Fix", OccurredAt: from},
+		{Action: AuditActionLegalHold, Actor: "legal", Reason: "Case 42", OccurredAt: to.Add(-time.Second)},
+		{Action: AuditActionPurge, Actor: "after", OccurredAt: to},
+	}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithComplianceReports(reader, nil, nil))
+
+	report, err := service.GenerateComplianceReport(context.Background(), ComplianceReportRequest{From: from, To: to})
+	if err != nil {
+		t.Fatalf("GenerateComplianceReport() unexpected error: %v", err)
+	}
+	if len(report.Entries) != 2 || report.Entries[0].Actor != "ops" || report.Entries[1].Actor != "legal" {
+		t.Errorf("Entries = %+v, want the ops then legal entries", report.Entries)
+	}
+	if !report.From.Equal(from) || !report.To.Equal(to) {
+		t.Errorf("period = %v - %v, want %v - %v", report.From, report.To, from, to)
+	}
+}
+
+// MockRecentActivityScanner serves the views of each queried period
+type MockRecentActivityScanner struct {
+	Views []ports.TodoView
+}
+
+func (m *MockRecentActivityScanner) ListViewsBetween(ctx context.Context, from, to time.Time, limit int) ([]ports.TodoView, error) {
+	var views []ports.TodoView
+	for _, view := range m.Views {
+		if !view.ViewedAt.Before(from) && view.ViewedAt.Before(to) && len(views) < limit {
+			views = append(views, view)
+		}
+	}
+	return views, nil
+}
+
+func TestTodoService_GenerateComplianceReport_AllSources(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	todoID := domain.NewTodoID()
+	reader := &MockAuditLogReader{Entries: []ports.AuditEntry{
+		{Action: AuditActionLegalHold, Actor: "legal", Reason: "Case 42", TodoID: todoID.String(), OccurredAt: from.Add(3 * time.Hour)},
+	}}
+	trail := &MockTodoAuditScanner{Entries: []ports.TodoAuditEntry{
+		{TodoID: todoID.String(), EventType: "TodoCreated", Actor: "alice", OccurredAt: from.Add(time.Hour)},
+		{TodoID: todoID.String(), EventType: "TodoUpdated", Actor: "bob", Changes: map[string]string{"title": "Call mom"}, OccurredAt: from.Add(4 * time.Hour)},
+		{TodoID: todoID.String(), EventType: "TodoUpdated", Actor: "before", OccurredAt: from.Add(-time.Hour)},
+	}}
+	views := &MockRecentActivityScanner{Views: []ports.TodoView{
+		{UserID: "carol", TodoID: todoID, ViewedAt: from.Add(2 * time.Hour)},
+		{UserID: "after", TodoID: todoID, ViewedAt: to},
+	}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithComplianceReports(reader, trail, views))
+
+	report, err := service.GenerateComplianceReport(context.Background(), ComplianceReportRequest{From: from, To: to})
+	if err != nil {
+		t.Fatalf("GenerateComplianceReport() unexpected error: %v", err)
+	}
+
+	want := []struct{ actor, action string }{
+		{"alice", "TodoCreated"},
+		{"carol", ComplianceActionViewed},
+		{"legal", AuditActionLegalHold},
+		{"bob", "TodoUpdated"},
+	}
+	if len(report.Entries) != len(want) {
+		t.Fatalf("Entries = %+v, want %d entries", report.Entries, len(want))
+	}
+	for i, w := range want {
+		entry := report.Entries[i]
+		if entry.Actor != w.actor || entry.Action != w.action || entry.TodoID != todoID.String() {
+			t.Errorf("Entries[%d] = %+v, want %s by %s on %s", i, entry, w.action, w.actor, todoID)
+		}
+	}
+	if report.Entries[3].Changes["title"] != "Call mom" {
+		t.Errorf("Entries[3].Changes = %v, want the changes of the update", report.Entries[3].Changes)
+	}
+}
+
+func TestTodoService_GenerateComplianceReport_Errors(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.GenerateComplianceReport(ctx, ComplianceReportRequest{From: from, To: from.Add(time.Hour)}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("without reader error = %v, want %v", err, ErrNotSupported)
+	}
+
+	reader := &MockAuditLogReader{}
+	for i := 0; i <= MaxComplianceReportEntries; i++ {
+		reader.Entries = append(reader.Entries, ports.AuditEntry{Action: AuditActionPurge, OccurredAt: from})
+	}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithComplianceReports(reader, nil, nil))
+
+	tests := []struct {
+		name string
+		req  ComplianceReportRequest
+	}{
+		{"missing from", ComplianceReportRequest{To: from}},
+		{"reversed period", ComplianceReportRequest{From: from.Add(time.Hour), To: from}},
+		{"too many entries", ComplianceReportRequest{From: from, To: from.Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validationErr domain.ValidationError
+			if _, err := service.GenerateComplianceReport(ctx, tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+		})
+	}
+}
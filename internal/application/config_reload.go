@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// AuditActionConfigReload is the audit log action of a configuration reload
+const AuditActionConfigReload = "config_reload"
+
+// ErrInvalidConfig is returned by a reload whose settings do not validate;
+// nothing is applied then
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ReloadableSetting is a group of settings applied without restart
+type ReloadableSetting struct {
+	// Names are the settings of the group, validated and applied together
+	Names []string
+	// Prepare validates the values of the settings, by name, and returns
+	// the function applying them
+	Prepare func(values map[string]string) (apply func(), err error)
+}
+
+// ConfigReloader applies the changes of the safe settings to the running
+// service, e.g. the log level or the scheduler intervals
+// The other settings are read once at startup: their changes are reported
+// as requiring a restart. A reload is all or nothing, and is recorded in the
+// audit log before it is applied
+type ConfigReloader struct {
+	load     func() (map[string]string, error)
+	settings []ReloadableSetting
+	auditLog ports.AuditLog
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	current map[string]string
+}
+
+// NewConfigReloader creates a ConfigReloader of the settings whose values at
+// startup are current; load returns their values when reloading
+func NewConfigReloader(
+	current map[string]string,
+	load func() (map[string]string, error),
+	settings []ReloadableSetting,
+	auditLog ports.AuditLog,
+	logger *slog.Logger,
+) *ConfigReloader {
+	return &ConfigReloader{
+		load:     load,
+		settings: settings,
+		auditLog: auditLog,
+		logger:   logger,
+		now:      time.Now,
+		current:  current,
+	}
+}
+
+// Reload loads the settings and applies the changed ones
+func (r *ConfigReloader) Reload(ctx context.Context, req ConfigReloadRequest) (*ConfigReloadResponse, error) {
+	if strings.TrimSpace(req.Actor) == "" {
+		return nil, domain.NewValidationError("actor", "is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.NewValidationError("reason", "is required")
+	}
+	if r.auditLog == nil {
+		return nil, ErrAuditLogRequired
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values, err := r.load()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	changed := changedSettings(r.current, values)
+	response := &ConfigReloadResponse{Applied: map[string]string{}, RestartRequired: []string{}}
+
+	var applies []func()
+	var errs []error
+	for _, setting := range r.settings {
+		if !slices.ContainsFunc(setting.Names, func(name string) bool { return slices.Contains(changed, name) }) {
+			continue
+		}
+		apply, err := setting.Prepare(values)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applies = append(applies, apply)
+		for _, name := range setting.Names {
+			if slices.Contains(changed, name) {
+				response.Applied[name] = values[name]
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	for _, name := range changed {
+		if _, ok := response.Applied[name]; !ok {
+			response.RestartRequired = append(response.RestartRequired, name)
+		}
+	}
+
+	if len(applies) == 0 {
+		return response, nil
+	}
+
+	entry := ports.AuditEntry{
+		Action:     AuditActionConfigReload,
+		Actor:      req.Actor,
+		Reason:     req.Reason,
+		Changes:    response.Applied,
+		OccurredAt: r.now(),
+	}
+	if err := r.auditLog.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("recording audit entry: %w", err)
+	}
+
+	for _, apply := range applies {
+		apply()
+	}
+	for name, value := range response.Applied {
+		r.current[name] = value
+	}
+
+	r.logger.Warn("configuration reloaded", "actor", req.Actor, "applied", response.Applied,
+		"restart_required", response.RestartRequired)
+
+	return response, nil
+}
+
+// changedSettings returns the names of the settings whose value differs,
+// sorted
+func changedSettings(current, values map[string]string) []string {
+	var changed []string
+	for name, value := range values {
+		if current[name] != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range current {
+		if _, ok := values[name]; !ok && current[name] != "" {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// newTestConfigReloader creates a ConfigReloader loading *values, with a
+// LOG_LEVEL setting and a REMINDER_INTERVAL and REMINDER_LEAD group, both
+// recording what they apply in applied
+func newTestConfigReloader(values *map[string]string, auditLog *MockAuditLog, applied map[string]string) *ConfigReloader {
+	current := map[string]string{}
+	for name, value := range *values {
+		current[name] = value
+	}
+
+	settings := []ReloadableSetting{
+		{Names: []string{"LOG_LEVEL"}, Prepare: func(values map[string]string) (func(), error) {
+			level := values["LOG_LEVEL"]
+			if level != "debug" && level != "info" {
+				return nil, fmt.Errorf("invalid LOG_LEVEL: %q", level)
+			}
+			return func() { applied["LOG_LEVEL"] = level }, nil
+		}},
+		{Names: []string{"REMINDER_INTERVAL", "REMINDER_LEAD"}, Prepare: func(values map[string]string) (func(), error) {
+			if _, err := strconv.Atoi(values["REMINDER_INTERVAL"]); err != nil {
+				return nil, fmt.Errorf("invalid REMINDER_INTERVAL: %q", values["REMINDER_INTERVAL"])
+			}
+			return func() {
+				applied["REMINDER_INTERVAL"] = values["REMINDER_INTERVAL"]
+				applied["REMINDER_LEAD"] = values["REMINDER_LEAD"]
+			}, nil
+		}},
+	}
+
+	return NewConfigReloader(current, func() (map[string]string, error) {
+		return *values, nil
+	}, settings, auditLog, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	values := map[string]string{"LOG_LEVEL": "info", "REMINDER_INTERVAL": "60", "REMINDER_LEAD": "24", "PORT": "8090"}
+	auditLog := &MockAuditLog{}
+	applied := map[string]string{}
+	reloader := newTestConfigReloader(&values, auditLog, applied)
+	req := ConfigReloadRequest{Actor: "ops@example.com", Reason: "Investigate slow scans"}
+
+	values = map[string]string{"LOG_LEVEL": "debug", "REMINDER_INTERVAL": "60", "REMINDER_LEAD": "24", "PORT": "9000"}
+	response, err := reloader.Reload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+
+	if len(response.Applied) != 1 || response.Applied["LOG_LEVEL"] != "debug" {
+		t.Errorf("Applied = %v, want LOG_LEVEL only", response.Applied)
+	}
+	if len(response.RestartRequired) != 1 || response.RestartRequired[0] != "PORT" {
+		t.Errorf("RestartRequired = %v, want [PORT]", response.RestartRequired)
+	}
+	if len(applied) != 1 || applied["LOG_LEVEL"] != "debug" {
+		t.Errorf("applied %v, want the LOG_LEVEL setting only", applied)
+	}
+	if len(auditLog.Entries) != 1 {
+		t.Fatalf("recorded %d audit entries, want 1", len(auditLog.Entries))
+	}
+	entry := auditLog.Entries[0]
+	if entry.Action != AuditActionConfigReload || entry.TodoID != "" || entry.Actor != req.Actor || entry.Changes["LOG_LEVEL"] != "debug" {
+		t.Errorf("audit entry = %+v, want the service-wide reload of LOG_LEVEL", entry)
+	}
+
+	// Applied settings are not reported again; PORT still needs a restart
+	response, err = reloader.Reload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("second Reload() unexpected error: %v", err)
+	}
+	if len(response.Applied) != 0 || len(response.RestartRequired) != 1 || len(auditLog.Entries) != 1 {
+		t.Errorf("second Reload() = %+v with %d audit entries, want only PORT reported", response, len(auditLog.Entries))
+	}
+}
+
+func TestConfigReloader_Reload_InvalidAppliesNothing(t *testing.T) {
+	values := map[string]string{"LOG_LEVEL": "info", "REMINDER_INTERVAL": "60"}
+	auditLog := &MockAuditLog{}
+	applied := map[string]string{}
+	reloader := newTestConfigReloader(&values, auditLog, applied)
+
+	values = map[string]string{"LOG_LEVEL": "debug", "REMINDER_INTERVAL": "soon"}
+	_, err := reloader.Reload(context.Background(), ConfigReloadRequest{Actor: "SIGHUP", Reason: "Reload"})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Reload() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	if len(applied) != 0 || len(auditLog.Entries) != 0 {
+		t.Errorf("applied %v and recorded %d entries, want nothing", applied, len(auditLog.Entries))
+	}
+}
+
+func TestConfigReloader_Reload_AuditFailureAppliesNothing(t *testing.T) {
+	values := map[string]string{"LOG_LEVEL": "info"}
+	auditLog := &MockAuditLog{RecordFunc: func(ctx context.Context, entry ports.AuditEntry) error {
+		return errors.New("database down")
+	}}
+	applied := map[string]string{}
+	reloader := newTestConfigReloader(&values, auditLog, applied)
+
+	values = map[string]string{"LOG_LEVEL": "debug"}
+	if _, err := reloader.Reload(context.Background(), ConfigReloadRequest{Actor: "SIGHUP", Reason: "Reload"}); err == nil {
+		t.Fatal("Reload() succeeded, want the audit error")
+	}
+	if len(applied) != 0 {
+		t.Errorf("applied %v without an audit entry, want nothing", applied)
+	}
+}
+
+func TestConfigReloader_Reload_RequiresActorAndReason(t *testing.T) {
+	values := map[string]string{}
+	reloader := newTestConfigReloader(&values, &MockAuditLog{}, map[string]string{})
+
+	for _, req := range []ConfigReloadRequest{{Reason: "Reload"}, {Actor: "SIGHUP"}} {
+		if _, err := reloader.Reload(context.Background(), req); err == nil {
+			t.Errorf("Reload(%+v) succeeded, want a validation error", req)
+		}
+	}
+}
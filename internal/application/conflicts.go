@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Conflict strategies of UpdateTodo, applied when the todo changed since the
+// version the client edited
+const (
+	// ConflictReject fails the update, the default
+	ConflictReject = "reject"
+	// ConflictLastWriterWins applies the update over the changes made since
+	ConflictLastWriterWins = "last_writer_wins"
+	// ConflictMerge applies the update when it changes other fields than the
+	// changes made since, and keeps the fields only changed on the server
+	ConflictMerge = "merge"
+)
+
+// ErrEditConflict is wrapped by EditConflictError
+var ErrEditConflict = errors.New("todo was modified since it was read")
+
+// FieldConflict is a field of an update that conflicts with the server state
+// Base is nil when the version the client edited is unknown
+type FieldConflict struct {
+	Field     string
+	Base      *string
+	Current   string
+	Requested string
+}
+
+// EditConflictError reports an update refused because the todo changed since
+// the version the client edited
+// Fields lists the fields changed both by the update and on the server; with
+// ConflictReject it may be empty, when the changes do not overlap
+type EditConflictError struct {
+	Strategy string
+	Current  *TodoResponse
+	Fields   []FieldConflict
+}
+
+// Error describes the conflict
+func (e *EditConflictError) Error() string {
+	if len(e.Fields) == 0 {
+		return ErrEditConflict.Error()
+	}
+	names := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		names[i] = field.Field
+	}
+	return fmt.Sprintf("%s: conflicting %s", ErrEditConflict, strings.Join(names, ", "))
+}
+
+// Unwrap returns ErrEditConflict
+func (e *EditConflictError) Unwrap() error {
+	return ErrEditConflict
+}
+
+// WithVersions lets UpdateTodo merge concurrent edits field by field, from the
+// version the client edited
+func WithVersions(versions ports.TodoVersionFinder) Option {
+	return func(s *TodoApplicationService) {
+		s.versions = versions
+	}
+}
+
+// editableField reads a field of UpdateTodoRequest and of a todo, as strings
+// compared to detect conflicts
+type editableField struct {
+	name      string
+	current   func(todo *domain.Todo) string
+	requested func(req *UpdateTodoRequest) (string, bool)
+	drop      func(req *UpdateTodoRequest)
+}
+
+// editableFields are the fields UpdateTodo may change
+var editableFields = []editableField{
+	{
+		name:    "title",
+		current: func(todo *domain.Todo) string { return todo.Title().String() },
+		requested: func(req *UpdateTodoRequest) (string, bool) {
+			if req.Title == nil {
+				return "", false
+			}
+			return *req.Title, true
+		},
+		drop: func(req *UpdateTodoRequest) { req.Title = nil },
+	},
+	{
+		name:    "description",
+		current: func(todo *domain.Todo) string { return todo.Description() },
+		requested: func(req *UpdateTodoRequest) (string, bool) {
+			if req.Description == nil {
+				return "", false
+			}
+			return *req.Description, true
+		},
+		drop: func(req *UpdateTodoRequest) { req.Description = nil },
+	},
+	{
+		name:    "priority",
+		current: func(todo *domain.Todo) string { return todo.Priority().String() },
+		requested: func(req *UpdateTodoRequest) (string, bool) {
+			if req.Priority == nil {
+				return "", false
+			}
+			return strings.ToLower(*req.Priority), true
+		},
+		drop: func(req *UpdateTodoRequest) { req.Priority = nil },
+	},
+	{
+		name:    "status",
+		current: func(todo *domain.Todo) string { return todo.Status().String() },
+		requested: func(req *UpdateTodoRequest) (string, bool) {
+			if req.Status == nil {
+				return "", false
+			}
+			return strings.ToLower(*req.Status), true
+		},
+		drop: func(req *UpdateTodoRequest) { req.Status = nil },
+	},
+	{
+		name: "due_date",
+		current: func(todo *domain.Todo) string {
+			if todo.DueDate() == nil {
+				return ""
+			}
+			return formatConflictTime(todo.DueDate().Time())
+		},
+		requested: func(req *UpdateTodoRequest) (string, bool) {
+			if req.DueDate == nil {
+				return "", false
+			}
+			if req.DueDate.IsZero() {
+				return "", true
+			}
+			return formatConflictTime(*req.DueDate), true
+		},
+		drop: func(req *UpdateTodoRequest) { req.DueDate = nil },
+	},
+}
+
+// formatConflictTime formats t as stored, to the microsecond
+func formatConflictTime(t time.Time) string {
+	return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+}
+
+// resolveEditConflict applies the conflict strategy of req to todo, the
+// current server state, and returns the request to apply
+// Stored timestamps have microsecond precision, so versions are compared to
+// the microsecond
+func (s *TodoApplicationService) resolveEditConflict(ctx context.Context, todo *domain.Todo, req UpdateTodoRequest) (UpdateTodoRequest, error) {
+	strategy := req.ConflictStrategy
+	switch strategy {
+	case "":
+		strategy = ConflictReject
+	case ConflictReject, ConflictLastWriterWins, ConflictMerge:
+	default:
+		return req, domain.NewValidationError("conflict_strategy", "must be one of reject, last_writer_wins or merge")
+	}
+
+	if req.BaseUpdatedAt == nil || strategy == ConflictLastWriterWins ||
+		!todo.UpdatedAt().Truncate(time.Microsecond).After(req.BaseUpdatedAt.Truncate(time.Microsecond)) {
+		return req, nil
+	}
+
+	var base *domain.Todo
+	if s.versions != nil {
+		var err error
+		base, err = s.versions.FindVersion(ctx, todo.ID(), *req.BaseUpdatedAt)
+		if err != nil && !errors.Is(err, domain.ErrTodoNotFound) {
+			return req, fmt.Errorf("finding edited version: %w", err)
+		}
+	}
+
+	merged := req
+	var conflicts []FieldConflict
+	for _, field := range editableFields {
+		requested, ok := field.requested(&req)
+		if !ok {
+			continue
+		}
+		current := field.current(todo)
+
+		if base == nil {
+			// Without the edited version, any difference may undo a change
+			if requested != current {
+				conflicts = append(conflicts, FieldConflict{Field: field.name, Current: current, Requested: requested})
+			}
+			continue
+		}
+
+		baseValue := field.current(base)
+		switch {
+		case requested == current, current == baseValue:
+			// Nothing to merge
+		case requested == baseValue:
+			// Only changed on the server: the server value is kept
+			field.drop(&merged)
+		default:
+			conflicts = append(conflicts, FieldConflict{Field: field.name, Base: &baseValue, Current: current, Requested: requested})
+		}
+	}
+
+	if strategy == ConflictMerge && len(conflicts) == 0 {
+		return merged, nil
+	}
+	return req, &EditConflictError{Strategy: strategy, Current: MapTodoToResponse(todo), Fields: conflicts}
+}
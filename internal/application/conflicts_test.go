@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockVersions serves the versions of todos by UpdatedAt
+type MockVersions struct {
+	Versions []*domain.Todo
+}
+
+func (m *MockVersions) FindVersion(ctx context.Context, id domain.TodoID, updatedAt time.Time) (*domain.Todo, error) {
+	for _, version := range m.Versions {
+		if version.ID() == id && version.UpdatedAt().Equal(updatedAt) {
+			return version, nil
+		}
+	}
+	return nil, domain.ErrTodoNotFound
+}
+
+// conflictingVersions returns the version of a todo a client edited, and
+// the current version, whose description changed on the server since
+func conflictingVersions() (base, current *domain.Todo) {
+	id := domain.NewTodoID()
+	title, _ := domain.NewTaskTitle("Buy milk")
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	base = domain.ReconstituteTodo(id, title, "Whole", domain.StatusPending, domain.PriorityMedium, nil, edited, edited, nil)
+	current = domain.ReconstituteTodo(id, title, "Skimmed", domain.StatusPending, domain.PriorityMedium, nil, edited, edited.Add(time.Hour), nil)
+	return base, current
+}
+
+func TestTodoService_UpdateTodo_ConflictStrategies(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name            string
+		req             UpdateTodoRequest
+		versions        bool
+		staleBase       bool
+		wantConflict    []string
+		wantTitle       string
+		wantDescription string
+	}{
+		{"no base", UpdateTodoRequest{Title: str("Buy oat milk")}, true, false, nil, "Buy oat milk", "Skimmed"},
+		{"current base", UpdateTodoRequest{Title: str("Buy oat milk")}, true, false, nil, "Buy oat milk", "Skimmed"},
+		{"reject by default", UpdateTodoRequest{Title: str("Buy oat milk")}, true, true, []string{}, "", ""},
+		{"last writer wins", UpdateTodoRequest{Description: str("Oat"), ConflictStrategy: ConflictLastWriterWins}, true, true, nil, "Buy milk", "Oat"},
+		{"merge other fields", UpdateTodoRequest{Title: str("Buy oat milk"), ConflictStrategy: ConflictMerge}, true, true, nil, "Buy oat milk", "Skimmed"},
+		{"merge keeps server changes", UpdateTodoRequest{Title: str("Buy oat milk"), Description: str("Whole"), ConflictStrategy: ConflictMerge}, true, true, nil, "Buy oat milk", "Skimmed"},
+		{"merge same change", UpdateTodoRequest{Description: str("Skimmed"), ConflictStrategy: ConflictMerge}, true, true, nil, "Buy milk", "Skimmed"},
+		{"merge conflicting change", UpdateTodoRequest{Description: str("Oat"), ConflictStrategy: ConflictMerge}, true, true, []string{"description"}, "", ""},
+		{"merge without versions", UpdateTodoRequest{Title: str("Buy oat milk"), Description: str("Whole"), ConflictStrategy: ConflictMerge}, false, true, []string{"title", "description"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, current := conflictingVersions()
+			updated := false
+			repo := &MockTodoRepository{
+				FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+					return current, nil
+				},
+				UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+					updated = true
+					return nil
+				},
+			}
+			var opts []Option
+			if tt.versions {
+				opts = append(opts, WithVersions(&MockVersions{Versions: []*domain.Todo{base, current}}))
+			}
+			service := NewTodoApplicationService(repo, &MockEventDispatcher{}, opts...)
+
+			req := tt.req
+			switch {
+			case tt.staleBase:
+				edited := base.UpdatedAt()
+				req.BaseUpdatedAt = &edited
+			case tt.name == "current base":
+				edited := current.UpdatedAt()
+				req.BaseUpdatedAt = &edited
+			}
+
+			result, err := service.UpdateTodo(context.Background(), current.ID().String(), req)
+			if tt.wantConflict != nil {
+				var conflict *EditConflictError
+				if !errors.As(err, &conflict) || !errors.Is(err, ErrEditConflict) {
+					t.Fatalf("UpdateTodo() error = %v, want an EditConflictError", err)
+				}
+				if updated {
+					t.Error("a conflicting update was saved")
+				}
+				if conflict.Current == nil || conflict.Current.Description != "Skimmed" {
+					t.Errorf("conflict current = %+v, want the server state", conflict.Current)
+				}
+				if len(conflict.Fields) != len(tt.wantConflict) {
+					t.Fatalf("conflicting fields = %+v, want %v", conflict.Fields, tt.wantConflict)
+				}
+				for i, field := range conflict.Fields {
+					if field.Field != tt.wantConflict[i] {
+						t.Errorf("conflicting field %d = %s, want %s", i, field.Field, tt.wantConflict[i])
+					}
+					if (field.Base != nil) != tt.versions {
+						t.Errorf("field %s base = %v, want it known: %v", field.Field, field.Base, tt.versions)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateTodo() unexpected error: %v", err)
+			}
+			if result.Title != tt.wantTitle || result.Description != tt.wantDescription {
+				t.Errorf("UpdateTodo() = %q, %q, want %q, %q", result.Title, result.Description, tt.wantTitle, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestTodoService_UpdateTodo_UnknownConflictStrategy(t *testing.T) {
+	testTodo := createTestTodo()
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{})
+
+	var validationErr domain.ValidationError
+	_, err := service.UpdateTodo(context.Background(), testTodo.ID().String(), UpdateTodoRequest{ConflictStrategy: "ours"})
+	if !errors.As(err, &validationErr) {
+		t.Errorf("UpdateTodo() error = %v, want a ValidationError", err)
+	}
+}
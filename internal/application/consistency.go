@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// consistencyTokenPrefix versions the format of consistency tokens
+const consistencyTokenPrefix = "c1."
+
+// NewConsistencyToken returns the consistency token of a write made at
+// writtenAt
+// Clients echo it on their next reads to see the write, even when those are
+// served by a replica or a cache (see ContextWithConsistencyToken)
+func NewConsistencyToken(writtenAt time.Time) string {
+	return consistencyTokenPrefix + strconv.FormatInt(writtenAt.UnixNano(), 36)
+}
+
+// ContextWithConsistencyToken returns a copy of ctx reading data at least as
+// fresh as the write token was issued for
+// Tokens are not signed: a token from the future is taken as issued now, so
+// that a forged one cannot keep reads off the replicas for long
+func ContextWithConsistencyToken(ctx context.Context, token string) (context.Context, error) {
+	encoded, ok := strings.CutPrefix(token, consistencyTokenPrefix)
+	if !ok {
+		return nil, domain.NewValidationError("consistency_token", "is not a consistency token")
+	}
+	nanos, err := strconv.ParseInt(encoded, 36, 64)
+	if err != nil {
+		return nil, domain.NewValidationError("consistency_token", "is not a consistency token")
+	}
+
+	writtenAt := time.Unix(0, nanos)
+	if now := time.Now(); writtenAt.After(now) {
+		writtenAt = now
+	}
+
+	return ports.ContextWithWrittenAt(ctx, writtenAt), nil
+}
+
+// freshEnough reports whether data computed at computedAt can be served to
+// the caller of ctx, i.e. does not predate the caller's last write
+func freshEnough(ctx context.Context, computedAt time.Time) bool {
+	writtenAt, ok := ports.WrittenAtFromContext(ctx)
+	return !ok || computedAt.After(writtenAt)
+}
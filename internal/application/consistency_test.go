@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestContextWithConsistencyToken(t *testing.T) {
+	writtenAt := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "issued token", token: NewConsistencyToken(writtenAt), want: writtenAt},
+		{name: "unknown format", token: "c0.abc", wantErr: true},
+		{name: "malformed", token: "c1.!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := ContextWithConsistencyToken(context.Background(), tt.token)
+
+			var validationErr domain.ValidationError
+			if tt.wantErr {
+				if !errors.As(err, &validationErr) {
+					t.Errorf("ContextWithConsistencyToken() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ContextWithConsistencyToken() unexpected error: %v", err)
+			}
+			if got, ok := ports.WrittenAtFromContext(ctx); !ok || !got.Equal(tt.want) {
+				t.Errorf("written at = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextWithConsistencyToken_FutureTakenAsNow(t *testing.T) {
+	ctx, err := ContextWithConsistencyToken(context.Background(), NewConsistencyToken(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("ContextWithConsistencyToken() unexpected error: %v", err)
+	}
+
+	if got, _ := ports.WrittenAtFromContext(ctx); got.After(time.Now()) {
+		t.Errorf("written at = %v, want no later than now", got)
+	}
+}
+
+func TestTodoService_GetCompletionHeatmap_FreshForConsistencyToken(t *testing.T) {
+	log := &MockCompletionLog{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCompletionLog(log))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+
+	// A write made elsewhere after the heatmap was cached
+	ctx, err := ContextWithConsistencyToken(ctx, NewConsistencyToken(time.Now()))
+	if err != nil {
+		t.Fatalf("ContextWithConsistencyToken() unexpected error: %v", err)
+	}
+	if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+	if log.Queries != 2 {
+		t.Errorf("DailyCompletions() called %d times, want the cached heatmap skipped", log.Queries)
+	}
+
+	// The heatmap computed since is fresh enough
+	if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+	if log.Queries != 2 {
+		t.Errorf("DailyCompletions() called %d times, want the second read cached", log.Queries)
+	}
+}
@@ -0,0 +1,371 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxGraphNodes is the largest number of todos GetDependencyGraph returns
+const MaxGraphNodes = 500
+
+// ErrDependencyCycle is returned when a dependency would make a todo wait
+// for itself
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+// ErrDependencyNotFound is returned when removing a dependency that does not
+// exist
+var ErrDependencyNotFound = errors.New("dependency not found")
+
+// WithDependencies enables the blocked-by relations between todos
+func WithDependencies(store ports.DependencyStore) Option {
+	return func(s *TodoApplicationService) {
+		s.dependencies = store
+	}
+}
+
+// AddDependency makes the todo id blocked by the todo blockerID
+// A dependency making a todo wait, even indirectly, for itself is refused
+// with ErrDependencyCycle. Two concurrent additions closing a cycle are not
+// detected; GetDependencyGraph still lays such a graph out
+func (s *TodoApplicationService) AddDependency(ctx context.Context, id, blockerID string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.dependencies == nil {
+		return ErrNotSupported
+	}
+
+	dependency, err := s.findDependency(ctx, id, blockerID)
+	if err != nil {
+		return err
+	}
+	if dependency.TodoID == dependency.BlockedBy {
+		return domain.NewValidationError("blocked_by", "a todo cannot block itself")
+	}
+
+	cycle, err := s.waitsFor(ctx, dependency.BlockedBy, dependency.TodoID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	if err := s.dependencies.Add(ctx, dependency); err != nil {
+		return fmt.Errorf("adding dependency: %w", err)
+	}
+
+	s.trackActivity(ctx, dependency.TodoID, ports.ActivityModified)
+
+	return nil
+}
+
+// RemoveDependency makes the todo id no longer blocked by the todo blockerID
+func (s *TodoApplicationService) RemoveDependency(ctx context.Context, id, blockerID string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.dependencies == nil {
+		return ErrNotSupported
+	}
+
+	dependency, err := s.findDependency(ctx, id, blockerID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.dependencies.Remove(ctx, dependency)
+	if err != nil {
+		return fmt.Errorf("removing dependency: %w", err)
+	}
+	if !removed {
+		return ErrDependencyNotFound
+	}
+
+	s.trackActivity(ctx, dependency.TodoID, ports.ActivityModified)
+
+	return nil
+}
+
+// findDependency resolves both todos of a dependency: the blocked todo must
+// be updatable and the blocking one readable
+func (s *TodoApplicationService) findDependency(ctx context.Context, id, blockerID string) (ports.Dependency, error) {
+	todo, err := s.findTodo(ctx, id)
+	if err != nil {
+		return ports.Dependency{}, err
+	}
+	if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+		return ports.Dependency{}, err
+	}
+
+	blocker, err := s.findTodo(ctx, blockerID)
+	if err != nil {
+		return ports.Dependency{}, err
+	}
+	if err := s.authorize(ctx, ActionRead, blocker); err != nil {
+		return ports.Dependency{}, err
+	}
+
+	return ports.Dependency{TodoID: todo.ID(), BlockedBy: blocker.ID()}, nil
+}
+
+// waitsFor reports whether from is blocked, directly or not, by target
+func (s *TodoApplicationService) waitsFor(ctx context.Context, from, target domain.TodoID) (bool, error) {
+	visited := map[domain.TodoID]bool{from: true}
+	frontier := []domain.TodoID{from}
+	for len(frontier) > 0 {
+		dependencies, err := s.dependencies.Touching(ctx, frontier)
+		if err != nil {
+			return false, fmt.Errorf("finding dependencies: %w", err)
+		}
+
+		waiting := make(map[domain.TodoID]bool, len(frontier))
+		for _, id := range frontier {
+			waiting[id] = true
+		}
+		frontier = nil
+		for _, dependency := range dependencies {
+			if !waiting[dependency.TodoID] || visited[dependency.BlockedBy] {
+				continue
+			}
+			if dependency.BlockedBy == target {
+				return true, nil
+			}
+			visited[dependency.BlockedBy] = true
+			frontier = append(frontier, dependency.BlockedBy)
+		}
+	}
+
+	return false, nil
+}
+
+// GetDependencyGraph returns the todos connected to the todo id by
+// blocked-by relations, in either direction, laid out for Gantt and graph
+// views
+// Nodes come blockers first, each with its level: the number of open todos
+// on the longest chain blocking it. The critical path is the longest such
+// chain through the graph. Todos the caller cannot read are left out, and
+// the graph stops growing at MaxGraphNodes todos, setting Truncated
+func (s *TodoApplicationService) GetDependencyGraph(ctx context.Context, id string) (*DependencyGraph, error) {
+	if s.dependencies == nil {
+		return nil, ErrNotSupported
+	}
+
+	root, err := s.findTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, ActionRead, root); err != nil {
+		return nil, err
+	}
+
+	graph := &DependencyGraph{RootID: root.ID().String()}
+	nodes := map[domain.TodoID]*domain.Todo{root.ID(): root}
+	hidden := map[domain.TodoID]bool{}
+	linked := map[ports.Dependency]bool{}
+	frontier := []domain.TodoID{root.ID()}
+	for len(frontier) > 0 {
+		dependencies, err := s.dependencies.Touching(ctx, frontier)
+		if err != nil {
+			return nil, fmt.Errorf("finding dependencies: %w", err)
+		}
+
+		frontier = nil
+		for _, dependency := range dependencies {
+			for _, end := range []domain.TodoID{dependency.TodoID, dependency.BlockedBy} {
+				if nodes[end] != nil || hidden[end] {
+					continue
+				}
+				if len(nodes) >= MaxGraphNodes {
+					graph.Truncated = true
+					continue
+				}
+
+				todo, err := s.graphNode(ctx, end)
+				if err != nil {
+					return nil, err
+				}
+				if todo == nil {
+					hidden[end] = true
+					continue
+				}
+				nodes[end] = todo
+				frontier = append(frontier, end)
+			}
+
+			if nodes[dependency.TodoID] != nil && nodes[dependency.BlockedBy] != nil {
+				linked[dependency] = true
+			}
+		}
+	}
+
+	edges := make([]ports.Dependency, 0, len(linked))
+	for dependency := range linked {
+		edges = append(edges, dependency)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].TodoID != edges[j].TodoID {
+			return edges[i].TodoID < edges[j].TodoID
+		}
+		return edges[i].BlockedBy < edges[j].BlockedBy
+	})
+
+	layout := layoutGraph(nodes, edges)
+	graph.Nodes = make([]*DependencyNode, len(layout.order))
+	for i, todo := range layout.order {
+		graph.Nodes[i] = &DependencyNode{Todo: MapTodoToResponse(todo), Level: layout.levels[todo.ID()]}
+	}
+	graph.Edges = make([]DependencyEdge, len(edges))
+	for i, edge := range edges {
+		graph.Edges[i] = DependencyEdge{TodoID: edge.TodoID.String(), BlockedBy: edge.BlockedBy.String()}
+	}
+	graph.CriticalPath = make([]string, len(layout.critical))
+	for i, id := range layout.critical {
+		graph.CriticalPath[i] = id.String()
+	}
+
+	return graph, nil
+}
+
+// graphNode loads a todo reached through a dependency, or returns nil when
+// the caller is not to see it
+// Canaries are left out silently, as from listings
+func (s *TodoApplicationService) graphNode(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	todo, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrTodoNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+	if todo.IsCanary() {
+		return nil, nil
+	}
+
+	err = s.authorize(ctx, ActionRead, todo)
+	if errors.Is(err, ErrForbidden) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// graphLayout is the order, levels and critical path of a dependency graph
+type graphLayout struct {
+	order    []*domain.Todo
+	levels   map[domain.TodoID]int
+	critical []domain.TodoID
+}
+
+// layoutGraph sorts the nodes blockers first, oldest first among those
+// ready at the same time, and computes their levels and the critical path
+// Todos caught in a cycle, and those they block, come last
+func layoutGraph(nodes map[domain.TodoID]*domain.Todo, edges []ports.Dependency) graphLayout {
+	blockers := make(map[domain.TodoID][]domain.TodoID, len(nodes))
+	dependents := make(map[domain.TodoID][]domain.TodoID, len(nodes))
+	pending := make(map[domain.TodoID]int, len(nodes))
+	for _, edge := range edges {
+		blockers[edge.TodoID] = append(blockers[edge.TodoID], edge.BlockedBy)
+		dependents[edge.BlockedBy] = append(dependents[edge.BlockedBy], edge.TodoID)
+		pending[edge.TodoID]++
+	}
+
+	var ready []*domain.Todo
+	for id, todo := range nodes {
+		if pending[id] == 0 {
+			ready = append(ready, todo)
+		}
+	}
+
+	layout := graphLayout{levels: make(map[domain.TodoID]int, len(nodes))}
+	placed := make(map[domain.TodoID]bool, len(nodes))
+	for len(layout.order) < len(nodes) {
+		if len(ready) == 0 {
+			// Only cycles are left: place them as they come
+			for id, todo := range nodes {
+				if !placed[id] {
+					ready = append(ready, todo)
+				}
+			}
+		}
+		sortOldestFirst(ready)
+
+		var next []*domain.Todo
+		for _, todo := range ready {
+			placed[todo.ID()] = true
+			layout.order = append(layout.order, todo)
+			for _, dependent := range dependents[todo.ID()] {
+				if pending[dependent]--; pending[dependent] == 0 && !placed[dependent] {
+					next = append(next, nodes[dependent])
+				}
+			}
+		}
+		ready = next
+	}
+
+	// Done todos take no time: only open ones add to the levels
+	finish := func(id domain.TodoID) int {
+		if nodes[id].Status().IsCompleted() || nodes[id].Status().IsCancelled() {
+			return layout.levels[id]
+		}
+		return layout.levels[id] + 1
+	}
+
+	var end domain.TodoID
+	longest := 0
+	for _, todo := range layout.order {
+		level := 0
+		for _, blocker := range blockers[todo.ID()] {
+			level = max(level, finish(blocker))
+		}
+		layout.levels[todo.ID()] = level
+
+		if finish(todo.ID()) > longest {
+			longest, end = finish(todo.ID()), todo.ID()
+		}
+	}
+	if longest == 0 {
+		return layout
+	}
+
+	// Walk back from the last todo of the longest chain
+	onPath := map[domain.TodoID]bool{end: true}
+	layout.critical = []domain.TodoID{end}
+	for current := end; layout.levels[current] > 0; {
+		var previous domain.TodoID
+		for _, blocker := range blockers[current] {
+			if !onPath[blocker] && finish(blocker) == layout.levels[current] {
+				previous = blocker
+				break
+			}
+		}
+		if previous == "" {
+			break
+		}
+		onPath[previous] = true
+		layout.critical = append([]domain.TodoID{previous}, layout.critical...)
+		current = previous
+	}
+
+	return layout
+}
+
+// sortOldestFirst sorts todos by creation, then ID
+func sortOldestFirst(todos []*domain.Todo) {
+	sort.Slice(todos, func(i, j int) bool {
+		if !todos[i].CreatedAt().Equal(todos[j].CreatedAt()) {
+			return todos[i].CreatedAt().Before(todos[j].CreatedAt())
+		}
+		return todos[i].ID() < todos[j].ID()
+	})
+}
@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockDependencyStore keeps dependencies in memory
+type MockDependencyStore struct {
+	Dependencies []ports.Dependency
+}
+
+func (m *MockDependencyStore) Add(ctx context.Context, dependency ports.Dependency) error {
+	for _, existing := range m.Dependencies {
+		if existing == dependency {
+			return nil
+		}
+	}
+	m.Dependencies = append(m.Dependencies, dependency)
+	return nil
+}
+
+func (m *MockDependencyStore) Remove(ctx context.Context, dependency ports.Dependency) (bool, error) {
+	for i, existing := range m.Dependencies {
+		if existing == dependency {
+			m.Dependencies = append(m.Dependencies[:i], m.Dependencies[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockDependencyStore) Touching(ctx context.Context, ids []domain.TodoID) ([]ports.Dependency, error) {
+	var touching []ports.Dependency
+	for _, dependency := range m.Dependencies {
+		for _, id := range ids {
+			if dependency.TodoID == id || dependency.BlockedBy == id {
+				touching = append(touching, dependency)
+				break
+			}
+		}
+	}
+	return touching, nil
+}
+
+func newDependencyService(store ports.DependencyStore, todos ...*domain.Todo) *TodoApplicationService {
+	return newBatchService(nil, []Option{WithDependencies(store)}, todos...)
+}
+
+func TestTodoService_AddDependency(t *testing.T) {
+	design, build, ship := createTestTodo(), createTestTodo(), createTestTodo()
+	store := &MockDependencyStore{}
+	service := newDependencyService(store, design, build, ship)
+	ctx := context.Background()
+
+	for _, pair := range [][2]*domain.Todo{{build, design}, {ship, build}, {ship, build}} {
+		if err := service.AddDependency(ctx, pair[0].ID().String(), pair[1].ID().String()); err != nil {
+			t.Fatalf("AddDependency() unexpected error: %v", err)
+		}
+	}
+	if len(store.Dependencies) != 2 {
+		t.Errorf("dependencies = %v, want ship blocked by build blocked by design", store.Dependencies)
+	}
+
+	var validationErr domain.ValidationError
+	tests := []struct {
+		name       string
+		id, target string
+		check      func(error) bool
+	}{
+		{"cycle", design.ID().String(), ship.ID().String(), func(err error) bool { return errors.Is(err, ErrDependencyCycle) }},
+		{"itself", design.ID().String(), design.ID().String(), func(err error) bool { return errors.As(err, &validationErr) }},
+		{"unknown blocker", design.ID().String(), domain.NewTodoID().String(), func(err error) bool { return errors.Is(err, domain.ErrTodoNotFound) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.AddDependency(ctx, tt.id, tt.target); !tt.check(err) {
+				t.Errorf("AddDependency() error = %v", err)
+			}
+			if len(store.Dependencies) != 2 {
+				t.Errorf("dependencies = %v, want them unchanged", store.Dependencies)
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if err := unsupported.AddDependency(ctx, ship.ID().String(), build.ID().String()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("AddDependency() without dependencies error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_RemoveDependency(t *testing.T) {
+	build, ship := createTestTodo(), createTestTodo()
+	store := &MockDependencyStore{Dependencies: []ports.Dependency{{TodoID: ship.ID(), BlockedBy: build.ID()}}}
+	service := newDependencyService(store, build, ship)
+	ctx := context.Background()
+
+	if err := service.RemoveDependency(ctx, ship.ID().String(), build.ID().String()); err != nil {
+		t.Fatalf("RemoveDependency() unexpected error: %v", err)
+	}
+	if len(store.Dependencies) != 0 {
+		t.Errorf("dependencies = %v, want none", store.Dependencies)
+	}
+	if err := service.RemoveDependency(ctx, ship.ID().String(), build.ID().String()); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("RemoveDependency() again error = %v, want %v", err, ErrDependencyNotFound)
+	}
+}
+
+func TestTodoService_GetDependencyGraph(t *testing.T) {
+	design, docs, build, side, ship := createTestTodo(), createTestTodo(), createTestTodo(), createTestTodo(), createTestTodo()
+	_ = docs.Complete()
+	title, _ := domain.NewTaskTitle("Decoy")
+	canary := domain.NewCanaryTodo(title, "")
+	store := &MockDependencyStore{Dependencies: []ports.Dependency{
+		{TodoID: build.ID(), BlockedBy: design.ID()},
+		{TodoID: ship.ID(), BlockedBy: build.ID()},
+		{TodoID: ship.ID(), BlockedBy: docs.ID()},
+		{TodoID: side.ID(), BlockedBy: design.ID()},
+		{TodoID: canary.ID(), BlockedBy: ship.ID()},
+		{TodoID: side.ID(), BlockedBy: domain.NewTodoID()},
+	}}
+	service := newDependencyService(store, design, docs, build, side, ship, canary)
+
+	graph, err := service.GetDependencyGraph(context.Background(), ship.ID().String())
+	if err != nil {
+		t.Fatalf("GetDependencyGraph() unexpected error: %v", err)
+	}
+
+	wantLevels := map[string]int{
+		design.ID().String(): 0,
+		docs.ID().String():   0,
+		build.ID().String():  1,
+		side.ID().String():   1,
+		ship.ID().String():   2,
+	}
+	if len(graph.Nodes) != len(wantLevels) {
+		t.Fatalf("Nodes = %d, want %d without the canary and the missing todo", len(graph.Nodes), len(wantLevels))
+	}
+	position := map[string]int{}
+	for i, node := range graph.Nodes {
+		position[node.Todo.ID] = i
+		if node.Level != wantLevels[node.Todo.ID] {
+			t.Errorf("level of %s = %d, want %d", node.Todo.ID, node.Level, wantLevels[node.Todo.ID])
+		}
+	}
+
+	if len(graph.Edges) != 4 {
+		t.Errorf("Edges = %+v, want the 4 edges between visible todos", graph.Edges)
+	}
+	for _, edge := range graph.Edges {
+		if position[edge.BlockedBy] > position[edge.TodoID] {
+			t.Errorf("%s comes before its blocker %s", edge.TodoID, edge.BlockedBy)
+		}
+	}
+
+	wantPath := []string{design.ID().String(), build.ID().String(), ship.ID().String()}
+	if len(graph.CriticalPath) != len(wantPath) {
+		t.Fatalf("CriticalPath = %v, want %v", graph.CriticalPath, wantPath)
+	}
+	for i, id := range wantPath {
+		if graph.CriticalPath[i] != id {
+			t.Errorf("CriticalPath[%d] = %s, want %s", i, graph.CriticalPath[i], id)
+		}
+	}
+	if graph.RootID != ship.ID().String() || graph.Truncated {
+		t.Errorf("graph = %+v, want the whole graph of ship", graph)
+	}
+}
+
+func TestLayoutGraph_Cycle(t *testing.T) {
+	first, second, blocked := createTestTodo(), createTestTodo(), createTestTodo()
+	nodes := map[domain.TodoID]*domain.Todo{first.ID(): first, second.ID(): second, blocked.ID(): blocked}
+	edges := []ports.Dependency{
+		{TodoID: first.ID(), BlockedBy: second.ID()},
+		{TodoID: second.ID(), BlockedBy: first.ID()},
+		{TodoID: blocked.ID(), BlockedBy: first.ID()},
+	}
+
+	layout := layoutGraph(nodes, edges)
+
+	if len(layout.order) != 3 {
+		t.Errorf("order = %v, want every todo placed once", layout.order)
+	}
+	if len(layout.critical) == 0 {
+		t.Error("critical path is empty, want a path through the open todos")
+	}
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// Description modes of ListTodos; any other mode is the number of
+// characters descriptions are truncated to
+const (
+	// DescriptionFull lists the descriptions whole, the default
+	DescriptionFull = "full"
+	// DescriptionOmit lists the todos without their description
+	DescriptionOmit = "omit"
+)
+
+// parseDescriptionMode validates the requested description mode and returns
+// the number of characters descriptions are cut to: -1 to keep them whole,
+// 0 to omit them
+func parseDescriptionMode(mode *string) (int, error) {
+	if mode == nil {
+		return -1, nil
+	}
+	switch strings.ToLower(*mode) {
+	case DescriptionFull:
+		return -1, nil
+	case DescriptionOmit:
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(*mode)
+	if err != nil || limit < 1 {
+		return 0, domain.NewValidationError("description", "must be full, omit or a positive number of characters")
+	}
+	return limit, nil
+}
+
+// trimDescriptions cuts the descriptions of todos to limit characters,
+// flagging those that lost some with HasMoreDescription
+// A negative limit keeps them whole
+func trimDescriptions(todos []*TodoResponse, limit int) {
+	if limit < 0 {
+		return
+	}
+	for _, todo := range todos {
+		if utf8.RuneCountInString(todo.Description) <= limit {
+			continue
+		}
+		cut := 0
+		for i := 0; i < limit; i++ {
+			_, size := utf8.DecodeRuneInString(todo.Description[cut:])
+			cut += size
+		}
+		todo.Description = todo.Description[:cut]
+		todo.HasMoreDescription = true
+	}
+}
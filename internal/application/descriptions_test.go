@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestTodoService_ListTodos_Description(t *testing.T) {
+	str := func(s string) *string { return &s }
+	title, _ := domain.NewTaskTitle("Plan the trip")
+	long := domain.NewTodo(title, "Café, hotel and trains", domain.PriorityMedium, nil)
+	short := domain.NewTodo(title, "Café", domain.PriorityMedium, nil)
+
+	tests := []struct {
+		name     string
+		mode     *string
+		want     []string
+		wantMore []bool
+	}{
+		{"full by default", nil, []string{"Café, hotel and trains", "Café"}, []bool{false, false}},
+		{"full", str("FULL"), []string{"Café, hotel and trains", "Café"}, []bool{false, false}},
+		{"omit", str("omit"), []string{"", ""}, []bool{true, true}},
+		{"truncate", str("4"), []string{"Café", "Café"}, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockTodoRepository{
+				FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+					return []*domain.Todo{long, short}, nil
+				},
+			}
+			service := NewTodoApplicationService(repo, &MockEventDispatcher{})
+
+			result, err := service.ListTodos(context.Background(), ListFilters{Description: tt.mode})
+			if err != nil {
+				t.Fatalf("ListTodos() unexpected error: %v", err)
+			}
+			for i, todo := range result.Todos {
+				if todo.Description != tt.want[i] || todo.HasMoreDescription != tt.wantMore[i] {
+					t.Errorf("todo %d = %q, more %v, want %q, more %v", i, todo.Description, todo.HasMoreDescription, tt.want[i], tt.wantMore[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTodoService_ListTodos_InvalidDescription(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+
+	for _, mode := range []string{"short", "0", "-5"} {
+		_, err := service.ListTodos(context.Background(), ListFilters{Description: &mode})
+		var validationErr domain.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("ListTodos(%q) error = %v, want a ValidationError", mode, err)
+		}
+	}
+}
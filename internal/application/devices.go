@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrDeviceNotFound is returned when unregistering a token the user has not
+// registered
+var ErrDeviceNotFound = errors.New("device not found")
+
+// Device limits
+const (
+	// MaxDeviceTokenLength bounds the token of a device; FCM tokens are
+	// about 160 characters long and APNs ones 64
+	MaxDeviceTokenLength = 512
+	// MaxDevicesPerUser is the number of devices kept per user; registering
+	// another one forgets the least recently seen
+	MaxDevicesPerUser = 20
+)
+
+// WithDevices enables the registration of the devices push notifications
+// are sent to, through the given providers
+func WithDevices(store ports.DeviceStore, providers []ports.PushProvider) Option {
+	return func(s *TodoApplicationService) {
+		s.devices = store
+		s.pushProviders = providers
+	}
+}
+
+// RegisterDevice registers a device of the authenticated user
+// Apps register their token on every start: registering it again refreshes
+// its last seen time, which keeps it from being pruned, and moves it to the
+// user when another one registered it before
+func (s *TodoApplicationService) RegisterDevice(ctx context.Context, req RegisterDeviceRequest) (*DeviceResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.deviceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := ports.PushProvider(req.Provider)
+	if !slices.Contains(s.pushProviders, provider) {
+		return nil, domain.NewValidationError("provider", fmt.Sprintf("must be one of %s", joinProviders(s.pushProviders)))
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" || len(token) > MaxDeviceTokenLength {
+		return nil, domain.NewValidationError("token", fmt.Sprintf("must be between 1 and %d characters", MaxDeviceTokenLength))
+	}
+
+	now := time.Now()
+	device := ports.Device{Token: token, UserID: userID, Provider: provider, RegisteredAt: now, LastSeenAt: now}
+	if err := s.devices.Save(ctx, device); err != nil {
+		return nil, fmt.Errorf("saving device: %w", err)
+	}
+
+	devices, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+	for _, stale := range devices[min(len(devices), MaxDevicesPerUser):] {
+		if _, err := s.devices.Delete(ctx, userID, stale.Token); err != nil {
+			return nil, fmt.Errorf("deleting device: %w", err)
+		}
+	}
+	for _, saved := range devices {
+		if saved.Token == token {
+			device = saved
+		}
+	}
+
+	return mapDeviceToResponse(device), nil
+}
+
+// ListDevices returns the devices of the authenticated user, most recently
+// seen first
+func (s *TodoApplicationService) ListDevices(ctx context.Context) ([]*DeviceResponse, error) {
+	userID, err := s.deviceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+
+	responses := make([]*DeviceResponse, len(devices))
+	for i, device := range devices {
+		responses[i] = mapDeviceToResponse(device)
+	}
+	return responses, nil
+}
+
+// UnregisterDevice stops the push notifications to a device of the
+// authenticated user, as apps do on sign out
+func (s *TodoApplicationService) UnregisterDevice(ctx context.Context, token string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	userID, err := s.deviceUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.devices.Delete(ctx, userID, token)
+	if err != nil {
+		return fmt.Errorf("deleting device: %w", err)
+	}
+	if !deleted {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// deviceUserID returns the user whose devices device operations manage
+func (s *TodoApplicationService) deviceUserID(ctx context.Context) (string, error) {
+	if s.devices == nil {
+		return "", ErrNotSupported
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+	return userID, nil
+}
+
+// joinProviders lists providers for error messages
+func joinProviders(providers []ports.PushProvider) string {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = string(provider)
+	}
+	return strings.Join(names, ", ")
+}
+
+// mapDeviceToResponse converts a device to a DeviceResponse
+func mapDeviceToResponse(device ports.Device) *DeviceResponse {
+	return &DeviceResponse{
+		Token:        device.Token,
+		Provider:     string(device.Provider),
+		RegisteredAt: device.RegisteredAt,
+		LastSeenAt:   device.LastSeenAt,
+	}
+}
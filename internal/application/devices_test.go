@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockDeviceStore keeps devices in memory, by token
+type MockDeviceStore struct {
+	Devices map[string]ports.Device
+	Err     error
+}
+
+func (m *MockDeviceStore) Save(ctx context.Context, device ports.Device) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Devices == nil {
+		m.Devices = map[string]ports.Device{}
+	}
+	if existing, ok := m.Devices[device.Token]; ok {
+		device.RegisteredAt = existing.RegisteredAt
+	}
+	m.Devices[device.Token] = device
+	return nil
+}
+
+func (m *MockDeviceStore) ListByUser(ctx context.Context, userID string) ([]ports.Device, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var devices []ports.Device
+	for _, device := range m.Devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	slices.SortFunc(devices, func(a, b ports.Device) int { return b.LastSeenAt.Compare(a.LastSeenAt) })
+	return devices, nil
+}
+
+func (m *MockDeviceStore) Delete(ctx context.Context, userID, token string) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	device, ok := m.Devices[token]
+	if !ok || device.UserID != userID {
+		return false, nil
+	}
+	delete(m.Devices, token)
+	return true, nil
+}
+
+func (m *MockDeviceStore) DeleteSeenBefore(ctx context.Context, before time.Time) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	count := 0
+	for token, device := range m.Devices {
+		if device.LastSeenAt.Before(before) {
+			delete(m.Devices, token)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// allProviders enables both push providers
+var allProviders = []ports.PushProvider{ports.PushProviderFCM, ports.PushProviderAPNs}
+
+func TestTodoService_RegisterDevice(t *testing.T) {
+	store := &MockDeviceStore{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(store, allProviders))
+	alice := ContextWithUserID(context.Background(), "alice")
+	bob := ContextWithUserID(context.Background(), "bob")
+
+	first, err := service.RegisterDevice(alice, RegisterDeviceRequest{Provider: "fcm", Token: " token-1 "})
+	if err != nil {
+		t.Fatalf("RegisterDevice() unexpected error: %v", err)
+	}
+	if first.Token != "token-1" || first.Provider != "fcm" {
+		t.Errorf("RegisterDevice() = %+v, want the trimmed fcm token", first)
+	}
+
+	// The same install signed in as another user
+	again, err := service.RegisterDevice(bob, RegisterDeviceRequest{Provider: "fcm", Token: "token-1"})
+	if err != nil {
+		t.Fatalf("RegisterDevice() again unexpected error: %v", err)
+	}
+	if !again.RegisteredAt.Equal(first.RegisteredAt) || again.LastSeenAt.Before(first.LastSeenAt) {
+		t.Errorf("RegisterDevice() again = %+v, want the registration kept and last seen refreshed", again)
+	}
+
+	if devices, _ := service.ListDevices(alice); len(devices) != 0 {
+		t.Errorf("ListDevices(alice) = %d devices, want the token moved to bob", len(devices))
+	}
+	if devices, _ := service.ListDevices(bob); len(devices) != 1 {
+		t.Errorf("ListDevices(bob) = %d devices, want 1", len(devices))
+	}
+}
+
+func TestTodoService_RegisterDevice_KeepsRecentDevices(t *testing.T) {
+	store := &MockDeviceStore{Devices: map[string]ports.Device{}}
+	oldest := time.Now().Add(-time.Hour)
+	for i := range MaxDevicesPerUser {
+		token := "old-" + string(rune('a'+i))
+		seen := oldest.Add(time.Duration(i) * time.Minute)
+		store.Devices[token] = ports.Device{Token: token, UserID: "alice", Provider: ports.PushProviderAPNs, LastSeenAt: seen}
+	}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(store, allProviders))
+
+	if _, err := service.RegisterDevice(ContextWithUserID(context.Background(), "alice"), RegisterDeviceRequest{Provider: "apns", Token: "new"}); err != nil {
+		t.Fatalf("RegisterDevice() unexpected error: %v", err)
+	}
+
+	if len(store.Devices) != MaxDevicesPerUser {
+		t.Errorf("kept %d devices, want %d", len(store.Devices), MaxDevicesPerUser)
+	}
+	if _, ok := store.Devices["old-a"]; ok {
+		t.Error("the least recently seen device was kept")
+	}
+	if _, ok := store.Devices["new"]; !ok {
+		t.Error("the new device was not kept")
+	}
+}
+
+func TestTodoService_RegisterDevice_Errors(t *testing.T) {
+	alice := ContextWithUserID(context.Background(), "alice")
+	errStore := errors.New("connection reset")
+
+	tests := []struct {
+		name      string
+		service   *TodoApplicationService
+		ctx       context.Context
+		req       RegisterDeviceRequest
+		wantErr   error
+		wantValid bool
+	}{
+		{"not supported", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}), alice, RegisterDeviceRequest{Provider: "fcm", Token: "t"}, ErrNotSupported, false},
+		{"anonymous", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, allProviders)), context.Background(), RegisterDeviceRequest{Provider: "fcm", Token: "t"}, ErrUnauthenticated, false},
+		{"unknown provider", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, allProviders)), alice, RegisterDeviceRequest{Provider: "wns", Token: "t"}, nil, true},
+		{"provider not enabled", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, []ports.PushProvider{ports.PushProviderFCM})), alice, RegisterDeviceRequest{Provider: "apns", Token: "t"}, nil, true},
+		{"empty token", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, allProviders)), alice, RegisterDeviceRequest{Provider: "fcm", Token: "  "}, nil, true},
+		{"long token", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, allProviders)), alice, RegisterDeviceRequest{Provider: "fcm", Token: strings.Repeat("x", MaxDeviceTokenLength+1)}, nil, true},
+		{"store error", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{Err: errStore}, allProviders)), alice, RegisterDeviceRequest{Provider: "fcm", Token: "t"}, errStore, false},
+		{"maintenance", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(&MockDeviceStore{}, allProviders), WithMaintenanceMode(NewMaintenanceMode(true, ""))), alice, RegisterDeviceRequest{Provider: "fcm", Token: "t"}, ErrMaintenanceMode, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.service.RegisterDevice(tt.ctx, tt.req)
+			if tt.wantValid {
+				var validationErr domain.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("RegisterDevice() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RegisterDevice() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoService_UnregisterDevice(t *testing.T) {
+	store := &MockDeviceStore{Devices: map[string]ports.Device{
+		"token-1": {Token: "token-1", UserID: "alice", Provider: ports.PushProviderFCM},
+	}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithDevices(store, allProviders))
+
+	if err := service.UnregisterDevice(ContextWithUserID(context.Background(), "bob"), "token-1"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("UnregisterDevice() by another user error = %v, want %v", err, ErrDeviceNotFound)
+	}
+	if err := service.UnregisterDevice(ContextWithUserID(context.Background(), "alice"), "token-1"); err != nil {
+		t.Fatalf("UnregisterDevice() unexpected error: %v", err)
+	}
+	if len(store.Devices) != 0 {
+		t.Errorf("store has %d devices, want 0", len(store.Devices))
+	}
+	if err := service.UnregisterDevice(ContextWithUserID(context.Background(), "alice"), "token-1"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("UnregisterDevice() again error = %v, want %v", err, ErrDeviceNotFound)
+	}
+}
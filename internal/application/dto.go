@@ -16,17 +16,75 @@ type CreateTodoRequest struct {
 
 // UpdateTodoRequest represents the data for updating a todo
 // All fields are optional (pointers indicate which fields to update)
+// BaseUpdatedAt is the UpdatedAt of the version the client edited; when the
+// todo changed since, ConflictStrategy decides. Nil skips the check
 type UpdateTodoRequest struct {
-	Title       *string
-	Description *string
-	Priority    *string
-	DueDate     *time.Time
-	Status      *string
+	Title            *string
+	Description      *string
+	Priority         *string
+	DueDate          *time.Time
+	Status           *string
+	BaseUpdatedAt    *time.Time
+	ConflictStrategy string
+}
+
+// ForceUpdateTodoRequest represents an administrative override of a todo
+// Fields follow UpdateTodoRequest; Reason is mandatory and recorded in the audit log
+type ForceUpdateTodoRequest struct {
+	UpdateTodoRequest
+	Actor  string
+	Reason string
+}
+
+// PurgeTodosRequest selects todos to hard-delete in bulk
+// At least one criterion is required; ConfirmationToken comes from PreviewPurge
+type PurgeTodosRequest struct {
+	Status            *string
+	Priority          *string
+	UpdatedBefore     *time.Time
+	Actor             string
+	Reason            string
+	ConfirmationToken string
+}
+
+// PurgePreview describes what a purge would delete
+// HeldCount todos also match but are on legal hold, so they are kept
+type PurgePreview struct {
+	MatchCount        int
+	HeldCount         int
+	ConfirmationToken string
+	ExpiresAt         time.Time
+}
+
+// TodoTombstone records a purged todo
+type TodoTombstone struct {
+	ID        string
+	ShortCode string
+	Title     string
+	Status    string
+	Priority  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PurgeReport lists the todos deleted by a purge
+type PurgeReport struct {
+	Actor      string
+	Reason     string
+	PurgedAt   time.Time
+	Tombstones []TodoTombstone
+}
+
+// MergeTodosResponse holds both todos of a merge
+type MergeTodosResponse struct {
+	Canonical *TodoResponse
+	Duplicate *TodoResponse
 }
 
 // TodoResponse represents a todo for API responses
 type TodoResponse struct {
 	ID          string
+	ShortCode   string
 	Title       string
 	Description string
 	Status      string
@@ -34,6 +92,25 @@ type TodoResponse struct {
 	DueDate     *time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	MergedInto  string
+	OwnerID     string
+	ArchivedAt  *time.Time
+	// Version is the number of saves of the todo, zero if the repository
+	// does not keep versions
+	Version int64
+	// HasMoreDescription tells that Description was omitted or truncated
+	// from a list
+	HasMoreDescription bool
+	// EditLock is the lock of the user editing the todo, only set by GetTodo
+	EditLock *EditLockResponse
+}
+
+// ExportFilters selects the todos to export
+// Archived is one of exclude, only or include, the default
+type ExportFilters struct {
+	Status   *string
+	Priority *string
+	Archived *string
 }
 
 // ListFilters represents filtering options for listing todos
@@ -42,24 +119,703 @@ type ListFilters struct {
 	Priority *string
 	Limit    *int
 	Offset   *int
+	// SortBy is one of created_at (the default), updated_at, due_date,
+	// priority or title
+	SortBy *string
+	// SortOrder is asc or desc, defaulting to asc for due_date and title
+	// and desc otherwise
+	SortOrder *string
+	// Archived is one of exclude (the default), only or include
+	Archived *string
+	// Description is full (the default), omit, or the number of characters
+	// the descriptions are truncated to
+	Description *string
 }
 
 // ListTodosResponse represents the response for listing todos
+// Warnings tell the client how its query was rewritten, if it was
 type ListTodosResponse struct {
 	Todos      []*TodoResponse
 	TotalCount int
+	Warnings   []string
+}
+
+// SearchTodosRequest represents a keyword search
+// A Limit of zero selects DefaultSearchLimit
+type SearchTodosRequest struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// SearchTodosResponse represents a page of search results, best match first
+// NextOffset is zero on the last page
+type SearchTodosResponse struct {
+	Todos      []*TodoResponse
+	NextOffset int
+}
+
+// ActivityEntry represents a change to a todo in the activity feed
+type ActivityEntry struct {
+	TodoID     string
+	Title      string
+	Kind       string
+	OccurredAt time.Time
+}
+
+// ActivityFeedPage represents a page of the activity feed
+// NextCursor is empty on the last page
+type ActivityFeedPage struct {
+	Entries    []*ActivityEntry
+	NextCursor string
+}
+
+// AnalyticsRequest represents the request for todo analytics
+// Zero times select the defaults of GetAnalytics
+type AnalyticsRequest struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+}
+
+// AnalyticsPoint represents the todo activity over one day or week
+type AnalyticsPoint struct {
+	Start     time.Time
+	Created   int
+	Completed int
+	Overdue   int
+}
+
+// AnalyticsReport represents created vs completed and overdue trends
+type AnalyticsReport struct {
+	Interval         string
+	From             time.Time
+	To               time.Time
+	Points           []*AnalyticsPoint
+	Completed        int
+	AverageCycleTime time.Duration
+}
+
+// CompletionHeatmap represents the completions of a user per UTC day over
+// the past year, oldest day first
+type CompletionHeatmap struct {
+	Days  []*HeatmapDay
+	Total int
+	// Max is the highest daily count, to scale the heatmap colors
+	Max int
+}
+
+// HeatmapDay represents the completions of one UTC day
+type HeatmapDay struct {
+	Date  time.Time
+	Count int
+}
+
+// InboundHookRequest represents the creation of an inbound webhook
+// Templates are Go text/templates executed against the JSON payload
+type InboundHookRequest struct {
+	Name                string
+	TitleTemplate       string
+	DescriptionTemplate string
+	PriorityTemplate    string
+	DueDateTemplate     string
+}
+
+// InboundHookResponse represents an inbound webhook, without its token
+type InboundHookResponse struct {
+	ID                  string
+	Name                string
+	TitleTemplate       string
+	DescriptionTemplate string
+	PriorityTemplate    string
+	DueDateTemplate     string
+	CreatedAt           time.Time
+}
+
+// InboundHookCreated represents a new inbound webhook with its token
+// The token is only ever returned here
+type InboundHookCreated struct {
+	Hook  *InboundHookResponse
+	Token string
+}
+
+// APIKeyRequest represents the creation of an API key
+type APIKeyRequest struct {
+	Name   string
+	UserID string
+	Scopes []string
+}
+
+// APIKeyResponse represents an API key, without its secret
+type APIKeyResponse struct {
+	ID        string
+	Name      string
+	UserID    string
+	Scopes    []string
+	CreatedAt time.Time
+}
+
+// APIKeyCreated represents a new API key with its secret
+// The secret is only ever returned here
+type APIKeyCreated struct {
+	Key    *APIKeyResponse
+	Secret string
+}
+
+// WebhookRequest represents the registration of a webhook endpoint
+// An empty EventTypes receives every event, and a zero DigestWindow posts
+// each event on its own. A Backfill posts the past events of a period
+type WebhookRequest struct {
+	URL          string
+	EventTypes   []string
+	DigestWindow time.Duration
+	Backfill     *WebhookBackfillRequest
+}
+
+// WebhookBackfillRequest represents the replay of the events that occurred
+// from From to To to a new webhook endpoint, Rate events per second
+// A zero To ends the period at the registration, and a zero Rate selects
+// DefaultWebhookBackfillRate
+type WebhookBackfillRequest struct {
+	From time.Time
+	To   time.Time
+	Rate int
+}
+
+// WebhookResponse represents a webhook endpoint, without its secret
+type WebhookResponse struct {
+	ID           string
+	URL          string
+	EventTypes   []string
+	DigestWindow time.Duration
+	CreatedAt    time.Time
+}
+
+// WebhookCreated represents a new webhook endpoint with its signing secret
+// The secret is only ever returned here; Backfilling is set when the
+// requested backfill was queued
+type WebhookCreated struct {
+	Webhook     *WebhookResponse
+	Secret      string
+	Backfilling bool
+}
+
+// WebhookDeliveriesRequest represents the listing of the delivery attempts
+// of a webhook endpoint
+// Cursor is the NextCursor of the previous page, empty for the first one
+type WebhookDeliveriesRequest struct {
+	FailedOnly bool
+	Cursor     string
+	Limit      int
+}
+
+// WebhookDeliveryResponse represents an attempt to post an event to a
+// webhook endpoint
+// StatusCode is 0 when no response was received and Error is empty on
+// success; RedeliveryOf is the ID of the attempt a manual redelivery
+// repeated, 0 for automatic attempts
+type WebhookDeliveryResponse struct {
+	ID              int64
+	EventID         string
+	EventType       string
+	Attempt         int
+	StatusCode      int
+	Error           string
+	ResponseSnippet string
+	RedeliveryOf    int64
+	Duration        time.Duration
+	AttemptedAt     time.Time
+}
+
+// WebhookDeliveryPage represents a page of the delivery attempts of a
+// webhook endpoint
+// NextCursor is empty on the last page
+type WebhookDeliveryPage struct {
+	Deliveries []*WebhookDeliveryResponse
+	NextCursor string
+}
+
+// RegisterDeviceRequest represents the registration of a device for push
+// notifications: the token its app got from Provider, fcm or apns
+type RegisterDeviceRequest struct {
+	Provider string
+	Token    string
+}
+
+// DeviceResponse represents a device push notifications are sent to
+type DeviceResponse struct {
+	Token        string
+	Provider     string
+	RegisteredAt time.Time
+	LastSeenAt   time.Time
+}
+
+// ConfigReloadRequest identifies who reloads the configuration, and why, for
+// the audit log
+type ConfigReloadRequest struct {
+	Actor  string
+	Reason string
+}
+
+// ConfigReloadResponse represents the outcome of a configuration reload
+type ConfigReloadResponse struct {
+	// Applied maps the settings applied to their new value
+	Applied map[string]string
+	// RestartRequired lists the changed settings only read at startup
+	RestartRequired []string
+}
+
+// WatchFilters represents filtering options for watching todo changes
+// After is the resume token of the last change received, empty to watch
+// from now on
+type WatchFilters struct {
+	Status   *string
+	Priority *string
+	After    string
+}
+
+// TodoChange represents a change to a todo pushed to watchers
+// Todo is the state after the change, nil when the todo was deleted
+// ResumeToken resumes a watch after this change
+type TodoChange struct {
+	Kind        string
+	ResumeToken string
+	TodoID      string
+	Todo        *TodoResponse
+	OccurredAt  time.Time
+}
+
+// LegalHoldRequest represents placing or releasing a legal hold
+type LegalHoldRequest struct {
+	Actor  string
+	Reason string
+}
+
+// LegalHoldResponse represents a legal hold on a todo
+type LegalHoldResponse struct {
+	TodoID   string
+	Reason   string
+	PlacedBy string
+	PlacedAt time.Time
+}
+
+// EditLockResponse represents the lock of a user editing a todo
+type EditLockResponse struct {
+	TodoID     string
+	HolderID   string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// CreateCanaryRequest represents the creation of a canary todo
+type CreateCanaryRequest struct {
+	Title       string
+	Description string
+}
+
+// ComplianceReportRequest selects the period of a compliance report
+type ComplianceReportRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+// ComplianceReport lists the accesses and modifications of the todos over a
+// period, oldest first
+type ComplianceReport struct {
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	Entries     []ComplianceEntry
+}
+
+// ComplianceEntry represents one access or modification of a todo
+type ComplianceEntry struct {
+	OccurredAt time.Time
+	Actor      string
+	Action     string
+	TodoID     string
+	Reason     string
+	Changes    map[string]string
+}
+
+// SyncOperation represents a change made offline by a sync client
+// TodoID is unused by create; it may be the Ref of a create earlier in the
+// same request. BaseUpdatedAt is the UpdatedAt of the todo the client
+// changed, zero to skip the conflict check
+type SyncOperation struct {
+	Ref           string
+	Op            string
+	TodoID        string
+	BaseUpdatedAt time.Time
+	Fields        UpdateTodoRequest
+}
+
+// SyncRequest represents a sync exchange: the changes made offline since the
+// last sync, pushed before the changes of the server since Cursor are pulled
+type SyncRequest struct {
+	Cursor     string
+	Limit      int
+	Operations []SyncOperation
+}
+
+// SyncOperationResult represents the outcome of a pushed operation
+// Status is applied, conflict (Resolution tells how it was settled), rejected
+// (the operation can never apply) or failed (it may be pushed again)
+// Todo is the server state of the todo, nil once deleted
+type SyncOperationResult struct {
+	Ref        string
+	Status     string
+	TodoID     string
+	Reason     string
+	Resolution string
+	Todo       *TodoResponse
+}
+
+// SyncedTodo represents a todo changed on the server since the sync cursor
+type SyncedTodo struct {
+	TodoID  string
+	Deleted bool
+	Todo    *TodoResponse
+}
+
+// SyncResponse represents the outcome of a sync exchange
+// Cursor is sent with the next sync; HasMore tells to sync again right away
+type SyncResponse struct {
+	Results []*SyncOperationResult
+	Changes []*SyncedTodo
+	Cursor  string
+	HasMore bool
+}
+
+// TriageRule sets the priority of the open todos matching all its
+// conditions; at least one condition is required
+// OlderThanDays matches todos created more than that many days ago
+// Priority matches todos of that priority
+type TriageRule struct {
+	Overdue       bool
+	NoDueDate     bool
+	OlderThanDays int
+	Priority      *string
+	SetPriority   string
+}
+
+// TriageRequest represents a triage pass: each open todo gets the priority
+// of the first rule it matches
+// DryRun previews the changes without saving them
+type TriageRequest struct {
+	Rules  []TriageRule
+	DryRun bool
+}
+
+// TriageChange represents the reprioritization of a todo by a triage rule,
+// Rule being its index
+type TriageChange struct {
+	TodoID string
+	Title  string
+	Rule   int
+	From   string
+	To     string
+}
+
+// TriageReport lists the todos a triage pass reprioritized, or would have
+// with DryRun
+type TriageReport struct {
+	DryRun  bool
+	Changes []TriageChange
+}
+
+// PlanWeekRequest maps the todos to plan, by ID or short code, to the day
+// they become due, as YYYY-MM-DD
+type PlanWeekRequest struct {
+	Days map[string]string
+}
+
+// PlanSkip represents a todo PlanWeek left unchanged, with the reason
+type PlanSkip struct {
+	TodoID string
+	Reason string
+}
+
+// PlanWeekResponse lists the planned todos with their new due date and the
+// skipped ones
+type PlanWeekResponse struct {
+	Planned []*TodoResponse
+	Skipped []PlanSkip
+}
+
+// SuggestScheduleRequest tunes the due dates suggested for the undated open
+// todos; zero fields take their default
+// Start is the first day to schedule, as YYYY-MM-DD, and Estimates maps
+// todos, by ID or short code, to their estimated work in minutes
+type SuggestScheduleRequest struct {
+	Start                  string
+	HorizonDays            int
+	DailyCapacityMinutes   int
+	DefaultEstimateMinutes int
+	Estimates              map[string]int
+}
+
+// ScheduleSuggestion represents the due date suggested for an undated todo
+type ScheduleSuggestion struct {
+	TodoID          string
+	Title           string
+	Priority        string
+	EstimateMinutes int
+	DueDate         time.Time
+}
+
+// ScheduleDay represents the work planned on a working day of the horizon,
+// in minutes: due todos already committed to, and suggested ones
+type ScheduleDay struct {
+	Day       string
+	Committed int
+	Suggested int
+}
+
+// SuggestScheduleResponse lists the suggested due dates, in scheduling
+// order, the IDs of the undated todos that did not fit in the horizon and
+// the resulting load of each working day
+type SuggestScheduleResponse struct {
+	Suggestions []ScheduleSuggestion
+	Unscheduled []string
+	Days        []ScheduleDay
+}
+
+// BatchTodoUpdate represents the update of one todo of a batch; Fields
+// follow UpdateTodoRequest
+type BatchTodoUpdate struct {
+	TodoID string
+	Fields UpdateTodoRequest
+}
+
+// BatchUpdateRequest represents updating many todos at once
+type BatchUpdateRequest struct {
+	Updates []BatchTodoUpdate
+}
+
+// BatchDeleteRequest represents deleting many todos at once
+type BatchDeleteRequest struct {
+	TodoIDs []string
+}
+
+// BatchFailure reports why the change of a todo was left out of a batch;
+// TodoID is as given in the request
+type BatchFailure struct {
+	TodoID string
+	Err    error
+}
+
+// BatchUpdateResponse lists the updated todos and the failed updates
+type BatchUpdateResponse struct {
+	Updated  []*TodoResponse
+	Failures []BatchFailure
+}
+
+// BatchDeleteResponse lists the IDs of the deleted todos and the failed
+// deletions
+type BatchDeleteResponse struct {
+	Deleted  []string
+	Failures []BatchFailure
+}
+
+// ImportRow is a todo read from line Line of an import file; Err is set
+// instead when the line could not be read into a request
+// KeepPastDueDate restores a due date already past rather than refusing
+// the row, for the todos of another app that may be overdue. Status is the
+// status the todo is imported in, pending when empty
+type ImportRow struct {
+	Line            int
+	Request         CreateTodoRequest
+	Status          string
+	KeepPastDueDate bool
+	Err             error
+}
+
+// ImportTodosRequest represents creating the todos read from a file
+// Progress, when set, is called after each batch is saved
+type ImportTodosRequest struct {
+	Rows     []ImportRow
+	Progress func(ImportProgress)
+}
+
+// ImportProgress reports how far an import went: Saved of its Total rows
+// were imported and Failed were refused so far
+type ImportProgress struct {
+	Saved  int
+	Failed int
+	Total  int
+}
+
+// ImportedTodo is the todo created from line Line of an import file
+type ImportedTodo struct {
+	Line int
+	Todo *TodoResponse
+}
+
+// ImportFailure reports why line Line of an import file created no todo
+type ImportFailure struct {
+	Line int
+	Err  error
+}
+
+// ImportTodosResponse lists the created todos and the failed lines, both
+// in line order
+// Skipped lists the lines of the valid rows a cancelled import left unsaved
+type ImportTodosResponse struct {
+	Imported []ImportedTodo
+	Failures []ImportFailure
+	Skipped  []int
+}
+
+// TodoistImportRequest selects the Todoist tasks to import
+// Projects names the projects imported, all of them when empty; Location
+// is the zone of the due dates that float in the zone of the user, UTC when
+// nil
+type TodoistImportRequest struct {
+	Projects []string
+	Location *time.Location
+	Progress func(ImportProgress)
+}
+
+// TodoistImportFailure reports why a Todoist task created no todo
+type TodoistImportFailure struct {
+	TaskID  string
+	Content string
+	Err     error
+}
+
+// TodoistImportResponse counts the todos created from Todoist tasks and
+// lists the tasks that failed
+type TodoistImportResponse struct {
+	Imported int
+	Failures []TodoistImportFailure
+}
+
+// BulkCompleteRequest selects the open todos to complete: nil fields and a
+// false Overdue match any open todo, but at least one condition is required
+type BulkCompleteRequest struct {
+	Status   *string
+	Priority *string
+	Overdue  bool
+}
+
+// BulkCompleteResponse lists the completed todos, oldest first
+// More is set when a single call completed as many todos as it can, others
+// possibly matching
+type BulkCompleteResponse struct {
+	Completed []*TodoResponse
+	More      bool
+}
+
+// DependencyNode represents a todo of a dependency graph
+// Level is the number of open todos on the longest chain blocking it, so a
+// Gantt view can start it after them
+type DependencyNode struct {
+	Todo  *TodoResponse
+	Level int
+}
+
+// DependencyEdge represents a todo blocked by another one
+type DependencyEdge struct {
+	TodoID    string
+	BlockedBy string
+}
+
+// DependencyGraph represents the todos connected to a root todo by
+// blocked-by relations
+// Nodes come blockers first; CriticalPath lists the IDs of the longest chain
+// of open todos, blockers first. Truncated is set when the graph was cut at
+// MaxGraphNodes todos
+type DependencyGraph struct {
+	RootID       string
+	Nodes        []*DependencyNode
+	Edges        []DependencyEdge
+	CriticalPath []string
+	Truncated    bool
+}
+
+// HolidayRequest represents naming a holiday of the business calendar
+type HolidayRequest struct {
+	Name string
+}
+
+// HolidayResponse represents a holiday of the business calendar; Day is
+// formatted as YYYY-MM-DD
+type HolidayResponse struct {
+	Day  string
+	Name string
+}
+
+// BusinessCalendarResponse represents the business calendar: the names of
+// its working days, its time zone and its holidays, earliest first
+type BusinessCalendarResponse struct {
+	WorkingDays []string
+	TimeZone    string
+	Holidays    []*HolidayResponse
+}
+
+// CreateMilestoneRequest represents the input for creating a milestone;
+// TargetDate is formatted as YYYY-MM-DD
+type CreateMilestoneRequest struct {
+	Name       string
+	TargetDate string
+}
+
+// MilestoneResponse represents a milestone; TargetDate is formatted as
+// YYYY-MM-DD and CompletedAt is nil while some of its todos are open
+type MilestoneResponse struct {
+	ID          string
+	Name        string
+	TargetDate  string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	ArchivedAt  *time.Time
+}
+
+// MilestoneTodoProgress represents a todo counted in the progress of a
+// milestone
+type MilestoneTodoProgress struct {
+	TodoID          string
+	Status          string
+	EstimateMinutes int
+}
+
+// MilestoneProgress represents the progress of a milestone
+// Cancelled todos are not counted. Percent is weighted by estimate when the
+// todos have any, and by count otherwise
+type MilestoneProgress struct {
+	Milestone                *MilestoneResponse
+	Total                    int
+	Completed                int
+	TotalEstimateMinutes     int
+	CompletedEstimateMinutes int
+	Percent                  float64
+	Todos                    []MilestoneTodoProgress
+}
+
+// TodoAuditLogEntry represents one domain event in the history of a todo
+// Changes maps the fields set by the event to their new value; Actor is
+// empty for changes made by the service itself, e.g. reminders
+type TodoAuditLogEntry struct {
+	OccurredAt time.Time
+	Actor      string
+	EventType  string
+	Changes    map[string]string
 }
 
 // MapTodoToResponse converts a domain Todo to a TodoResponse DTO
 func MapTodoToResponse(todo *domain.Todo) *TodoResponse {
 	response := &TodoResponse{
 		ID:          todo.ID().String(),
+		ShortCode:   todo.ShortCode().String(),
 		Title:       todo.Title().String(),
 		Description: todo.Description(),
 		Status:      todo.Status().String(),
 		Priority:    todo.Priority().String(),
 		CreatedAt:   todo.CreatedAt(),
 		UpdatedAt:   todo.UpdatedAt(),
+		OwnerID:     todo.OwnerID(),
+		Version:     todo.Version(),
 	}
 
 	if todo.DueDate() != nil {
@@ -67,6 +823,12 @@ func MapTodoToResponse(todo *domain.Todo) *TodoResponse {
 		response.DueDate = &dueDate
 	}
 
+	if todo.MergedInto() != nil {
+		response.MergedInto = todo.MergedInto().String()
+	}
+
+	response.ArchivedAt = todo.ArchivedAt()
+
 	return response
 }
 
@@ -78,3 +840,37 @@ func MapTodosToResponse(todos []*domain.Todo) []*TodoResponse {
 	}
 	return responses
 }
+
+// OperationResponse represents a long-running operation
+// Done of Total items are processed, Total being zero while unknown;
+// Progress is the percentage done, nil while unknown. Result is the JSON
+// outcome of a finished operation, only returned by GetOperation
+type OperationResponse struct {
+	ID              string
+	Kind            string
+	Status          string
+	Done            int
+	Total           int
+	Progress        *int
+	Result          []byte
+	Error           string
+	CancelRequested bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FinishedAt      *time.Time
+}
+
+// OperationsRequest represents a page of the operations of the caller
+// Cursor is the NextCursor of the previous page; a Limit of zero selects
+// DefaultOperationLimit
+type OperationsRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// OperationPage represents a page of operations, newest first
+// NextCursor is empty on the last page
+type OperationPage struct {
+	Operations []*OperationResponse
+	NextCursor string
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Edit lock durations
+// Editors keep their lock by acquiring it again before it expires
+const (
+	DefaultEditLockTTL = 2 * time.Minute
+	MaxEditLockTTL     = 15 * time.Minute
+)
+
+// ErrEditLocked is wrapped by EditLockedError
+var ErrEditLocked = errors.New("todo is being edited by another user")
+
+// ErrEditLockNotFound is returned when releasing an edit lock the user does
+// not hold
+var ErrEditLockNotFound = errors.New("edit lock not found")
+
+// EditLockedError reports an edit lock refused because another user holds
+// the lock of the todo
+type EditLockedError struct {
+	Lock *EditLockResponse
+}
+
+// Error names the holder of the lock
+func (e *EditLockedError) Error() string {
+	return fmt.Sprintf("todo is being edited by %s until %s", e.Lock.HolderID, e.Lock.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+// Unwrap returns ErrEditLocked
+func (e *EditLockedError) Unwrap() error {
+	return ErrEditLocked
+}
+
+// WithEditLocks enables edit locks, with which users tell the others they
+// are editing a todo; GetTodo then returns the lock of the todo
+// Edit locks are advisory: updates neither check them nor release them, and
+// concurrent updates are still settled by UpdateTodo conflict strategies
+func WithEditLocks(store ports.EditLockStore) Option {
+	return func(s *TodoApplicationService) {
+		s.editLocks = store
+	}
+}
+
+// AcquireEditLock locks a todo for the authenticated user during ttl, which
+// is DefaultEditLockTTL when zero or less and capped to MaxEditLockTTL
+// Acquiring a lock already held extends it; a lock held by another user is
+// refused with an EditLockedError
+func (s *TodoApplicationService) AcquireEditLock(ctx context.Context, id string, ttl time.Duration) (*EditLockResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.editLocks == nil {
+		return nil, ErrNotSupported
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	todo, err := s.findTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ttl <= 0:
+		ttl = DefaultEditLockTTL
+	case ttl > MaxEditLockTTL:
+		ttl = MaxEditLockTTL
+	}
+
+	lock, err := s.editLocks.Acquire(ctx, todo.ID(), userID, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring edit lock: %w", err)
+	}
+
+	if lock.HolderID != userID {
+		return nil, &EditLockedError{Lock: mapEditLock(lock)}
+	}
+
+	return mapEditLock(lock), nil
+}
+
+// ReleaseEditLock releases the lock of the authenticated user on a todo
+func (s *TodoApplicationService) ReleaseEditLock(ctx context.Context, id string) error {
+	if s.editLocks == nil {
+		return ErrNotSupported
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	released, err := s.editLocks.Release(ctx, todoID, userID)
+	if err != nil {
+		return fmt.Errorf("releasing edit lock: %w", err)
+	}
+	if !released {
+		return ErrEditLockNotFound
+	}
+
+	return nil
+}
+
+// findEditLock returns the lock on a todo, nil when there is none
+// The lock being advisory, a read does not fail when it cannot be found
+func (s *TodoApplicationService) findEditLock(ctx context.Context, todoID domain.TodoID) *EditLockResponse {
+	if s.editLocks == nil {
+		return nil
+	}
+
+	lock, err := s.editLocks.Find(ctx, todoID)
+	if err != nil || lock == nil {
+		return nil
+	}
+
+	return mapEditLock(*lock)
+}
+
+// mapEditLock converts an EditLock to an EditLockResponse DTO
+func mapEditLock(lock ports.EditLock) *EditLockResponse {
+	return &EditLockResponse{
+		TodoID:     lock.TodoID.String(),
+		HolderID:   lock.HolderID,
+		AcquiredAt: lock.AcquiredAt,
+		ExpiresAt:  lock.ExpiresAt,
+	}
+}
@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockEditLockStore keeps edit locks in memory
+type MockEditLockStore struct {
+	Locks   map[domain.TodoID]ports.EditLock
+	LastTTL time.Duration
+}
+
+func (m *MockEditLockStore) Acquire(ctx context.Context, todoID domain.TodoID, holderID string, ttl time.Duration) (ports.EditLock, error) {
+	if m.Locks == nil {
+		m.Locks = make(map[domain.TodoID]ports.EditLock)
+	}
+	m.LastTTL = ttl
+	now := time.Now()
+	lock, ok := m.Locks[todoID]
+	switch {
+	case ok && lock.ExpiresAt.After(now) && lock.HolderID != holderID:
+		return lock, nil
+	case !ok || !lock.ExpiresAt.After(now):
+		lock = ports.EditLock{TodoID: todoID, HolderID: holderID, AcquiredAt: now}
+	}
+	lock.ExpiresAt = now.Add(ttl)
+	m.Locks[todoID] = lock
+	return lock, nil
+}
+
+func (m *MockEditLockStore) Release(ctx context.Context, todoID domain.TodoID, holderID string) (bool, error) {
+	lock, ok := m.Locks[todoID]
+	if !ok || lock.HolderID != holderID {
+		return false, nil
+	}
+	delete(m.Locks, todoID)
+	return true, nil
+}
+
+func (m *MockEditLockStore) Find(ctx context.Context, todoID domain.TodoID) (*ports.EditLock, error) {
+	lock, ok := m.Locks[todoID]
+	if !ok || !lock.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return &lock, nil
+}
+
+func TestTodoService_EditLock_Lifecycle(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	store := &MockEditLockStore{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithEditLocks(store))
+	id := testTodo.ID().String()
+	alice := ContextWithUserID(context.Background(), "alice")
+	bob := ContextWithUserID(context.Background(), "bob")
+
+	lock, err := service.AcquireEditLock(alice, id, 0)
+	if err != nil {
+		t.Fatalf("AcquireEditLock() unexpected error: %v", err)
+	}
+	if lock.HolderID != "alice" || lock.TodoID != id || store.LastTTL != DefaultEditLockTTL {
+		t.Errorf("AcquireEditLock() = %+v with TTL %v, want alice's lock for %v", lock, store.LastTTL, DefaultEditLockTTL)
+	}
+
+	// Another user sees who is editing, and is refused the lock
+	todo, err := service.GetTodo(bob, id)
+	if err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+	if todo.EditLock == nil || todo.EditLock.HolderID != "alice" {
+		t.Errorf("GetTodo().EditLock = %+v, want alice's lock", todo.EditLock)
+	}
+
+	_, err = service.AcquireEditLock(bob, id, time.Minute)
+	var locked *EditLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrEditLocked) || locked.Lock.HolderID != "alice" {
+		t.Fatalf("AcquireEditLock() by bob error = %v, want alice's EditLockedError", err)
+	}
+
+	if err := service.ReleaseEditLock(bob, id); !errors.Is(err, ErrEditLockNotFound) {
+		t.Errorf("ReleaseEditLock() by bob error = %v, want %v", err, ErrEditLockNotFound)
+	}
+	if err := service.ReleaseEditLock(alice, id); err != nil {
+		t.Fatalf("ReleaseEditLock() unexpected error: %v", err)
+	}
+
+	todo, err = service.GetTodo(bob, id)
+	if err != nil || todo.EditLock != nil {
+		t.Errorf("GetTodo() after release = %+v, %v, want no lock", todo, err)
+	}
+
+	if _, err := service.AcquireEditLock(bob, id, time.Hour); err != nil {
+		t.Fatalf("AcquireEditLock() by bob after release unexpected error: %v", err)
+	}
+	if store.LastTTL != MaxEditLockTTL {
+		t.Errorf("TTL = %v, want it capped to %v", store.LastTTL, MaxEditLockTTL)
+	}
+}
+
+func TestTodoService_AcquireEditLock_Errors(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	user := ContextWithUserID(context.Background(), "alice")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		options []Option
+		want    error
+	}{
+		{"not supported", user, nil, ErrNotSupported},
+		{"unauthenticated", context.Background(), []Option{WithEditLocks(&MockEditLockStore{})}, ErrUnauthenticated},
+		{"maintenance", user, []Option{WithEditLocks(&MockEditLockStore{}), WithMaintenanceMode(NewMaintenanceMode(true, ""))}, ErrMaintenanceMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, tt.options...)
+
+			if _, err := service.AcquireEditLock(tt.ctx, testTodo.ID().String(), 0); !errors.Is(err, tt.want) {
+				t.Errorf("AcquireEditLock() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
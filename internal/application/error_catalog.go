@@ -0,0 +1,105 @@
+package application
+
+import (
+	"errors"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+)
+
+// ErrorRule is an error the service returns, as client teams handle it
+// Code is the name of its Connect code, e.g. "not_found". Message is a
+// template of the message, its variable parts in braces. Retryable tells
+// whether the same call may succeed later unchanged
+type ErrorRule struct {
+	ID        string `json:"rule"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	// matches reports whether an error is this rule; nil for the rules
+	// raised outside the service, such as authentication
+	matches func(err error) bool
+}
+
+// Rule IDs of the errors raised outside the service, whose rules match no
+// error of the service
+const (
+	RuleUnauthenticated = "auth.unauthenticated"
+	RuleAuthUnavailable = "auth.unavailable"
+	RuleMissingScope    = "auth.missing_scope"
+	RuleInvalidETag     = "request.invalid_etag"
+	RuleInternal        = "internal"
+)
+
+// is matches the errors wrapping target
+func is(target error) func(err error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// isValidationError matches the domain validation errors, by value or
+// pointer
+func isValidationError(err error) bool {
+	var validationErr domain.ValidationError
+	var validationPtr *domain.ValidationError
+	return errors.As(err, &validationErr) || errors.As(err, &validationPtr)
+}
+
+// isBusinessRuleError matches the domain business rule errors, by value or
+// pointer
+func isBusinessRuleError(err error) bool {
+	var ruleErr domain.BusinessRuleError
+	var rulePtr *domain.BusinessRuleError
+	return errors.As(err, &ruleErr) || errors.As(err, &rulePtr)
+}
+
+// errorCatalog lists the errors of the service, the first matching rule of
+// an error being its rule
+var errorCatalog = []ErrorRule{
+	{ID: "todo.not_found", Code: "not_found", Message: "todo not found", matches: is(domain.ErrTodoNotFound)},
+	{ID: "service.maintenance", Code: "unavailable", Message: "service is in maintenance mode: {message}", Retryable: true, matches: is(ErrMaintenanceMode)},
+	{ID: "service.dependency_unavailable", Code: "unavailable", Message: "circuit breaker is open", Retryable: true, matches: is(circuitbreaker.ErrOpen)},
+	{ID: "quota.expensive_queries", Code: "resource_exhausted", Message: "too many expensive queries: at most {budget} pages past offset {offset} per {window}, retry in {retry} or narrow the filters", Retryable: true, matches: is(ErrQueryThrottled)},
+	{ID: "quota.concurrent_queries", Code: "resource_exhausted", Message: "too many concurrent calls", Retryable: true, matches: is(bulkhead.ErrFull)},
+	{ID: "auth.forbidden", Code: "permission_denied", Message: "{action}: permission denied", matches: is(ErrForbidden)},
+	{ID: RuleUnauthenticated, Code: "unauthenticated", Message: "authentication required", matches: is(ErrUnauthenticated)},
+	{ID: "todo.legal_hold", Code: "failed_precondition", Message: "todo is on legal hold", matches: is(ErrLegalHold)},
+	{ID: "todo.frozen", Code: "failed_precondition", Message: "todo is read-only: its milestone is archived", matches: is(ErrTodoFrozen)},
+	{ID: "todo.concurrent_modification", Code: "aborted", Message: "todo was modified concurrently", Retryable: true, matches: is(domain.ErrConcurrentModification)},
+	{ID: "todo.edit_conflict", Code: "aborted", Message: "todo was modified since it was read: {fields}", matches: is(ErrEditConflict)},
+	{ID: "todo.invalid_title", Code: "invalid_argument", Message: "title must be between 1 and 200 characters", matches: is(domain.ErrInvalidTitle)},
+	{ID: "todo.invalid_due_date", Code: "invalid_argument", Message: "due date must be in the future", matches: is(domain.ErrInvalidDueDate)},
+	{ID: "todo.invalid_priority", Code: "invalid_argument", Message: "invalid priority value", matches: is(domain.ErrInvalidPriority)},
+	{ID: "todo.invalid_status", Code: "invalid_argument", Message: "invalid status value", matches: is(domain.ErrInvalidStatus)},
+	{ID: "todo.invalid_id", Code: "invalid_argument", Message: "invalid todo ID", matches: is(domain.ErrInvalidID)},
+	{ID: "request.invalid_field", Code: "invalid_argument", Message: "{field}: {message}", matches: isValidationError},
+	{ID: RuleInvalidETag, Code: "invalid_argument", Message: "invalid If-Match ETag"},
+	{ID: "todo.completed", Code: "failed_precondition", Message: "cannot modify a completed task", matches: is(domain.ErrCannotModifyCompleted)},
+	{ID: "todo.cancelled", Code: "failed_precondition", Message: "cannot complete a cancelled task", matches: is(domain.ErrCannotCompleteCancelled)},
+	{ID: "todo.invalid_transition", Code: "failed_precondition", Message: "invalid status transition", matches: is(domain.ErrInvalidStatusTransition)},
+	{ID: "todo.business_rule", Code: "failed_precondition", Message: "{rule}: {message}", matches: isBusinessRuleError},
+	{ID: "service.not_supported", Code: "unimplemented", Message: "operation not supported by this deployment", matches: is(ErrNotSupported)},
+	{ID: RuleAuthUnavailable, Code: "unavailable", Message: "cannot verify {credential}", Retryable: true},
+	{ID: RuleMissingScope, Code: "permission_denied", Message: "{procedure} requires the {scope} scope"},
+	internalRule,
+}
+
+// internalRule is the rule of the unexpected errors, matched by none
+var internalRule = ErrorRule{ID: RuleInternal, Code: "internal", Message: "{error}"}
+
+// ErrorCatalog returns every error rule of the service, in matching order
+func ErrorCatalog() []ErrorRule {
+	rules := make([]ErrorRule, len(errorCatalog))
+	copy(rules, errorCatalog)
+	return rules
+}
+
+// ErrorRuleOf returns the rule of err, the internal rule when none matches
+func ErrorRuleOf(err error) ErrorRule {
+	for _, rule := range errorCatalog {
+		if rule.matches != nil && rule.matches(err) {
+			return rule
+		}
+	}
+	return internalRule
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/bulkhead"
+	"github.com/pivaldi/mmw/todo/internal/pkg/circuitbreaker"
+)
+
+func TestErrorRuleOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "not found", err: fmt.Errorf("getting: %w", domain.ErrTodoNotFound), want: "todo.not_found"},
+		{name: "maintenance", err: ErrMaintenanceMode, want: "service.maintenance"},
+		{name: "circuit open", err: circuitbreaker.ErrOpen, want: "service.dependency_unavailable"},
+		{name: "throttled", err: fmt.Errorf("%w: retry in 30s", ErrQueryThrottled), want: "quota.expensive_queries"},
+		{name: "bulkhead full", err: bulkhead.ErrFull, want: "quota.concurrent_queries"},
+		{name: "forbidden", err: ErrForbidden, want: "auth.forbidden"},
+		{name: "concurrent modification", err: domain.ErrConcurrentModification, want: "todo.concurrent_modification"},
+		{name: "invalid title", err: domain.ErrInvalidTitle, want: "todo.invalid_title"},
+		{name: "validation error", err: domain.NewValidationError("sort_by", "is unknown"), want: "request.invalid_field"},
+		{name: "completed", err: domain.ErrCannotModifyCompleted, want: "todo.completed"},
+		{name: "not supported", err: ErrNotSupported, want: "service.not_supported"},
+		{name: "unexpected", err: errors.New("boom"), want: RuleInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorRuleOf(tt.err).ID; got != tt.want {
+				t.Errorf("ErrorRuleOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCatalog(t *testing.T) {
+	rules := ErrorCatalog()
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.ID == "" || rule.Code == "" || rule.Message == "" {
+			t.Errorf("rule %+v is incomplete", rule)
+		}
+		if seen[rule.ID] {
+			t.Errorf("rule %s is listed twice", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	for _, id := range []string{RuleUnauthenticated, RuleAuthUnavailable, RuleMissingScope, RuleInvalidETag, RuleInternal} {
+		if !seen[id] {
+			t.Errorf("rule %s is not in the catalog", id)
+		}
+	}
+
+	rules[0].ID = "changed"
+	if ErrorCatalog()[0].ID == "changed" {
+		t.Error("ErrorCatalog() shares its rules with the caller")
+	}
+}
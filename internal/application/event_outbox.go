@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithEventOutbox records the events of each write in outbox, within the
+// transaction of transactor the write runs in, for an EventRelay to publish
+// them; the dispatcher of the service gets them once the write is committed
+func WithEventOutbox(transactor ports.Transactor, outbox ports.EventOutbox) Option {
+	return func(s *TodoApplicationService) {
+		s.transactor = transactor
+		s.outbox = outbox
+	}
+}
+
+// commit runs write, which returns the events it raised, then dispatches
+// them
+// With an event outbox, write and the recording of its events share a
+// transaction, so that the events are published if and only if the write is
+// committed; they are dispatched afterwards, marked as recorded (see
+// ports.ContextWithRecordedEvents)
+func (s *TodoApplicationService) commit(ctx context.Context, write func(ctx context.Context) ([]domain.DomainEvent, error)) error {
+	var events []domain.DomainEvent
+	if s.outbox == nil {
+		var err error
+		if events, err = write(ctx); err != nil {
+			return err
+		}
+	} else {
+		err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if events, err = write(ctx); err != nil {
+				return err
+			}
+			if err := s.outbox.Record(ctx, events); err != nil {
+				return fmt.Errorf("recording events: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		ctx = ports.ContextWithRecordedEvents(ctx, events)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := s.dispatcher.Dispatch(ctx, events); err != nil {
+		return fmt.Errorf("dispatching events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// EventRelayOptions controls how the EventRelay publishes the outbox
+type EventRelayOptions struct {
+	// BatchSize is the largest number of events relayed at once
+	BatchSize int
+	// RetryInterval is the delay between two attempts at relaying the
+	// outbox, besides the ones following each dispatch
+	RetryInterval time.Duration
+}
+
+// DefaultEventRelayOptions relays the outbox by 100 events, every 5 seconds
+func DefaultEventRelayOptions() EventRelayOptions {
+	return EventRelayOptions{
+		BatchSize:     100,
+		RetryInterval: 5 * time.Second,
+	}
+}
+
+// EventRelay publishes the events of an outbox to the event broker, so that
+// writes do not fail while the broker is down
+// The service records the events of its writes in the outbox within their
+// transaction (see WithEventOutbox); as a dispatcher, the relay records the
+// other events, such as reminders, then wakes Run up to publish them
+// The relay runs degraded while the broker refuses the outbox, and events
+// are published at least once, since a failed dispatch may have published
+// some of them
+type EventRelay struct {
+	outbox  ports.EventOutbox
+	next    ports.EventDispatcher
+	logger  *slog.Logger
+	options EventRelayOptions
+	runs    *JobTracker
+	now     func() time.Time
+	wake    chan struct{}
+
+	mu            sync.Mutex
+	degradedSince time.Time
+	buffered      int
+	relayed       int64
+}
+
+// NewEventRelay creates an EventRelay publishing outbox to next
+func NewEventRelay(outbox ports.EventOutbox, next ports.EventDispatcher, logger *slog.Logger, options EventRelayOptions) *EventRelay {
+	return &EventRelay{
+		outbox:  outbox,
+		next:    next,
+		logger:  logger,
+		options: options,
+		runs:    NewJobTracker("event_relay", options.RetryInterval),
+		now:     time.Now,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Runs returns the tracker of the attempts made by Run; its counter is the
+// events relayed
+func (r *EventRelay) Runs() *JobTracker {
+	return r.runs
+}
+
+// Dispatch records in the outbox the events not recorded with their write
+// yet (see ports.UnrecordedEvents), and wakes Run up to publish them
+func (r *EventRelay) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if unrecorded := ports.UnrecordedEvents(ctx, events); len(unrecorded) > 0 {
+		if err := r.outbox.Record(ctx, unrecorded); err != nil {
+			return fmt.Errorf("recording events in the outbox: %w", err)
+		}
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Degraded reports whether the broker refuses the events of the outbox
+func (r *EventRelay) Degraded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !r.degradedSince.IsZero()
+}
+
+// EventDeliveryStats reports the outbox and the deliveries to the broker
+func (r *EventRelay) EventDeliveryStats() ports.EventDeliveryStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := ports.EventDeliveryStats{
+		Degraded: !r.degradedSince.IsZero(),
+		Buffered: r.buffered,
+		Relayed:  r.relayed,
+	}
+	if stats.Degraded {
+		since := r.degradedSince
+		stats.DegradedSince = &since
+	}
+	return stats
+}
+
+// Run relays the outbox after each dispatch and every retry interval, until
+// ctx is done; the events left wait in the outbox for the next start
+func (r *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.options.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+
+		if err := r.relay(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn("event broker unavailable, keeping domain events in the outbox",
+				"buffered", r.EventDeliveryStats().Buffered, "retry_in", r.options.RetryInterval, "error", err)
+		}
+	}
+}
+
+// relay publishes the outbox to the broker by batches, oldest first, and
+// leaves degraded mode once it is empty
+// It stops at the first batch that cannot be published, entering degraded
+// mode when the broker refused it
+func (r *EventRelay) relay(ctx context.Context) error {
+	publish := func(ctx context.Context, events []domain.DomainEvent) error {
+		if err := r.next.Dispatch(ctx, events); err != nil {
+			r.degrade(err)
+			return err
+		}
+		return nil
+	}
+
+	for {
+		relayed, err := r.outbox.Relay(ctx, r.options.BatchSize, publish)
+		r.runs.Record(r.now(), err)
+		if err != nil {
+			r.countBuffered(ctx)
+			return err
+		}
+
+		r.mu.Lock()
+		r.relayed += int64(relayed)
+		r.mu.Unlock()
+		r.runs.Count("relayed", int64(relayed))
+		if relayed < r.options.BatchSize {
+			r.restore()
+			return nil
+		}
+	}
+}
+
+// degrade enters degraded mode, if not already, as the broker refused the
+// events with cause
+func (r *EventRelay) degrade(cause error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.degradedSince.IsZero() {
+		r.degradedSince = r.now()
+		r.logger.Warn("event broker unavailable, holding domain events in the outbox", "error", cause)
+	}
+}
+
+// restore leaves degraded mode, if in it, as the outbox was relayed
+func (r *EventRelay) restore() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buffered = 0
+	if !r.degradedSince.IsZero() {
+		r.logger.Info("event broker available again, outbox relayed",
+			"degraded_for", r.now().Sub(r.degradedSince))
+		r.degradedSince = time.Time{}
+	}
+}
+
+// countBuffered counts the events left in the outbox, keeping the last
+// count when the outbox cannot be read either
+func (r *EventRelay) countBuffered(ctx context.Context) {
+	buffered, err := r.outbox.Unpublished(ctx)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buffered = buffered
+}
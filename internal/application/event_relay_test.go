@@ -0,0 +1,272 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// errBrokerDown is the error of a broker that is unavailable
+var errBrokerDown = errors.New("broker unavailable")
+
+// memEventOutbox is an EventOutbox in memory; recordErr fails Record
+type memEventOutbox struct {
+	events    []domain.DomainEvent
+	published int
+	recordErr error
+}
+
+func (o *memEventOutbox) Record(ctx context.Context, events []domain.DomainEvent) error {
+	if o.recordErr != nil {
+		return o.recordErr
+	}
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func (o *memEventOutbox) Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []domain.DomainEvent) error) (int, error) {
+	pending := o.events[o.published:min(o.published+limit, len(o.events))]
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if err := publish(ctx, pending); err != nil {
+		return 0, err
+	}
+	o.published += len(pending)
+	return len(pending), nil
+}
+
+func (o *memEventOutbox) Unpublished(ctx context.Context) (int, error) {
+	return len(o.events) - o.published, nil
+}
+
+// fakeTransactor runs fn as is, counting the transactions committed
+type fakeTransactor struct {
+	committed int
+}
+
+func (t *fakeTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	t.committed++
+	return nil
+}
+
+// newTestEventRelay creates an EventRelay publishing outbox to broker, by 2
+// events
+func newTestEventRelay(outbox *memEventOutbox, broker *MockEventDispatcher) *EventRelay {
+	return NewEventRelay(outbox, broker, slog.New(slog.NewTextHandler(io.Discard, nil)), EventRelayOptions{
+		BatchSize:     2,
+		RetryInterval: time.Millisecond,
+	})
+}
+
+// updatedEvents returns n TodoUpdated events, of distinct todos
+func updatedEvents(n int) []domain.DomainEvent {
+	events := make([]domain.DomainEvent, n)
+	for i := range events {
+		events[i] = domain.NewTodoUpdatedEvent(domain.NewTodoID())
+	}
+	return events
+}
+
+func TestEventRelay_BrokerDown(t *testing.T) {
+	broker := &MockEventDispatcher{DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+		return errBrokerDown
+	}}
+	outbox := &memEventOutbox{}
+	transactor := &fakeTransactor{}
+	relay := newTestEventRelay(outbox, broker)
+	var saved *domain.Todo
+	repo := &MockTodoRepository{
+		SaveFunc: func(ctx context.Context, todo *domain.Todo) error {
+			saved = todo
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return saved, nil
+		},
+	}
+	service := NewTodoApplicationService(repo, relay, WithEventOutbox(transactor, outbox))
+	ctx := context.Background()
+
+	// Writes succeed, their events recorded in the outbox with them, once
+	created, err := service.CreateTodo(ctx, CreateTodoRequest{Title: "Buy groceries", Priority: "medium"})
+	if err != nil {
+		t.Fatalf("CreateTodo() unexpected error while the broker is down: %v", err)
+	}
+	if _, err := service.CreateTodo(ctx, CreateTodoRequest{Title: "Call mom", Priority: "low"}); err != nil {
+		t.Fatalf("CreateTodo() unexpected error: %v", err)
+	}
+	if len(outbox.events) != 2 || transactor.committed != 2 {
+		t.Errorf("outbox holds %d events from %d transactions, want 2 from 2", len(outbox.events), transactor.committed)
+	}
+
+	// Reads do not depend on the broker
+	if _, err := service.GetTodo(ctx, created.ID); err != nil {
+		t.Errorf("GetTodo() unexpected error: %v", err)
+	}
+
+	if err := relay.relay(ctx); !errors.Is(err, errBrokerDown) {
+		t.Errorf("relay() error = %v, want errBrokerDown", err)
+	}
+	stats := relay.EventDeliveryStats()
+	if !stats.Degraded || stats.DegradedSince == nil || stats.Buffered != 2 {
+		t.Errorf("EventDeliveryStats() = %+v, want degraded with 2 events buffered", stats)
+	}
+	if outbox.published != 0 {
+		t.Errorf("%d events marked published, want none", outbox.published)
+	}
+}
+
+func TestEventRelay_Dispatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		recorded  bool
+		recordErr error
+		wantErr   bool
+		wantCount int
+	}{
+		{name: "events without a write", wantCount: 3},
+		{name: "events recorded with their write", recorded: true, wantCount: 0},
+		{name: "outbox unavailable", recordErr: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &memEventOutbox{recordErr: tt.recordErr}
+			relay := newTestEventRelay(outbox, &MockEventDispatcher{})
+			events := updatedEvents(3)
+			ctx := context.Background()
+			if tt.recorded {
+				ctx = ports.ContextWithRecordedEvents(ctx, events)
+			}
+
+			err := relay.Dispatch(ctx, events)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dispatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(outbox.events) != tt.wantCount {
+				t.Errorf("outbox holds %d events, want %d", len(outbox.events), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestTodoApplicationService_Commit_RecordFails(t *testing.T) {
+	broker := &MockEventDispatcher{}
+	outbox := &memEventOutbox{recordErr: errors.New("connection refused")}
+	transactor := &fakeTransactor{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, broker, WithEventOutbox(transactor, outbox))
+
+	if _, err := service.CreateTodo(context.Background(), CreateTodoRequest{Title: "Buy groceries", Priority: "medium"}); err == nil {
+		t.Fatal("CreateTodo() succeeded without recording its events")
+	}
+	if transactor.committed != 0 || len(broker.DispatchedEvents) != 0 {
+		t.Errorf("%d transactions committed and %d events dispatched, want none", transactor.committed, len(broker.DispatchedEvents))
+	}
+}
+
+func TestEventRelay_Relay(t *testing.T) {
+	tests := []struct {
+		name          string
+		brokerUp      bool
+		wantDegraded  bool
+		wantBuffered  int
+		wantRelayed   int64
+		wantPublished int
+	}{
+		{
+			name:          "broker back",
+			brokerUp:      true,
+			wantDegraded:  false,
+			wantRelayed:   5,
+			wantPublished: 5,
+		},
+		{
+			name:         "broker still down",
+			wantDegraded: true,
+			wantBuffered: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := false
+			var delivered []domain.DomainEvent
+			broker := &MockEventDispatcher{DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+				if !up {
+					return errBrokerDown
+				}
+				delivered = append(delivered, events...)
+				return nil
+			}}
+			outbox := &memEventOutbox{}
+			relay := newTestEventRelay(outbox, broker)
+			events := updatedEvents(5)
+			if err := relay.Dispatch(context.Background(), events); err != nil {
+				t.Fatalf("Dispatch() unexpected error: %v", err)
+			}
+			if err := relay.relay(context.Background()); err == nil {
+				t.Fatal("relay() succeeded while the broker is down")
+			}
+
+			up = tt.brokerUp
+			err := relay.relay(context.Background())
+			if (err != nil) == tt.brokerUp {
+				t.Errorf("relay() error = %v", err)
+			}
+
+			stats := relay.EventDeliveryStats()
+			if stats.Degraded != tt.wantDegraded || stats.Buffered != tt.wantBuffered || stats.Relayed != tt.wantRelayed {
+				t.Errorf("EventDeliveryStats() = %+v, want degraded %v, %d buffered and %d relayed",
+					stats, tt.wantDegraded, tt.wantBuffered, tt.wantRelayed)
+			}
+			if outbox.published != tt.wantPublished {
+				t.Errorf("%d events marked published, want %d", outbox.published, tt.wantPublished)
+			}
+			for i, event := range delivered {
+				if event != events[i] {
+					t.Fatalf("event %d relayed out of order", i)
+				}
+			}
+		})
+	}
+}
+
+func TestEventRelay_Run_Shutdown(t *testing.T) {
+	broker := &MockEventDispatcher{DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+		return errBrokerDown
+	}}
+	outbox := &memEventOutbox{}
+	relay := newTestEventRelay(outbox, broker)
+	relay.options.RetryInterval = time.Hour
+	if err := relay.Dispatch(context.Background(), updatedEvents(3)); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after ctx was done")
+	}
+	// The events left wait in the outbox for the next start
+	if unpublished, _ := outbox.Unpublished(context.Background()); unpublished != 3 {
+		t.Errorf("%d events left in the outbox, want 3", unpublished)
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ExportPageSize is the number of todos ExportTodos reads at a time
+const ExportPageSize = 500
+
+// ExportTodos passes every todo of the caller matching filters to emit,
+// oldest first, for backups and reports
+// Todos are read ExportPageSize at a time, so exports of any size use
+// little memory; emit failing stops the export with its error. Each page is
+// read separately: a todo deleted during an export shifts the next pages,
+// and the todo following it may be left out. Cancelling ctx stops the
+// export at the next todo, with the error of ctx
+func (s *TodoApplicationService) ExportTodos(ctx context.Context, filters ExportFilters, emit func(*TodoResponse) error) error {
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return err
+	}
+
+	repoFilters := ports.Filters{SortBy: ports.SortByCreatedAt, SortOrder: ports.SortAscending}
+
+	if filters.Status != nil {
+		status, err := domain.NewTaskStatus(*filters.Status)
+		if err != nil {
+			return fmt.Errorf("invalid status filter: %w", err)
+		}
+		repoFilters.Status = &status
+	}
+
+	if filters.Priority != nil {
+		priority, err := domain.NewPriority(*filters.Priority)
+		if err != nil {
+			return fmt.Errorf("invalid priority filter: %w", err)
+		}
+		repoFilters.Priority = &priority
+	}
+
+	archived := filters.Archived
+	if archived == nil {
+		include := ArchivedInclude
+		archived = &include
+	}
+	if err := applyArchived(&repoFilters, archived); err != nil {
+		return err
+	}
+
+	limit := ExportPageSize
+	repoFilters.Limit = &limit
+	for offset := 0; ; offset += ExportPageSize {
+		repoFilters.Offset = &offset
+		todos, err := s.repository.FindAll(ctx, repoFilters)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("finding todos: %w", err)
+		}
+
+		for _, todo := range todos {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := emit(MapTodoToResponse(todo)); err != nil {
+				return err
+			}
+		}
+
+		if len(todos) < ExportPageSize {
+			return nil
+		}
+	}
+}
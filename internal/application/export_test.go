@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestTodoService_ExportTodos(t *testing.T) {
+	todos := make([]*domain.Todo, ExportPageSize+1)
+	for i := range todos {
+		todos[i] = createTestTodo()
+	}
+	var pages []ports.Filters
+	mockRepo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			pages = append(pages, filters)
+			start := min(*filters.Offset, len(todos))
+			return todos[start:min(start+*filters.Limit, len(todos))], nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+
+	var exported []string
+	err := service.ExportTodos(context.Background(), ExportFilters{}, func(todo *TodoResponse) error {
+		exported = append(exported, todo.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportTodos() unexpected error: %v", err)
+	}
+
+	if len(exported) != len(todos) || exported[ExportPageSize] != todos[ExportPageSize].ID().String() {
+		t.Errorf("exported %d todos, want all %d in order", len(exported), len(todos))
+	}
+	if len(pages) != 2 || pages[0].Archived != nil || pages[0].SortBy != ports.SortByCreatedAt || pages[0].SortOrder != ports.SortAscending {
+		t.Errorf("pages = %+v, want 2 pages of every todo, oldest first", pages)
+	}
+}
+
+func TestTodoService_ExportTodos_Errors(t *testing.T) {
+	emitErr := errors.New("client gone")
+	unknown := "someday"
+
+	tests := []struct {
+		name    string
+		filters ExportFilters
+		emitErr error
+		wantErr error
+	}{
+		{name: "unknown status", filters: ExportFilters{Status: &unknown}, wantErr: domain.ErrInvalidStatus},
+		{name: "unknown priority", filters: ExportFilters{Priority: &unknown}, wantErr: domain.ErrInvalidPriority},
+		{name: "emit failure", emitErr: emitErr, wantErr: emitErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockTodoRepository{
+				FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+					return []*domain.Todo{createTestTodo(), createTestTodo()}, nil
+				},
+			}
+			service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+
+			emitted := 0
+			err := service.ExportTodos(context.Background(), tt.filters, func(todo *TodoResponse) error {
+				emitted++
+				return tt.emitErr
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExportTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.emitErr != nil && emitted != 1 {
+				t.Errorf("emitted %d todos, want the export stopped at the first", emitted)
+			}
+		})
+	}
+
+	var validationErr domain.ValidationError
+	archived := "sometimes"
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if err := service.ExportTodos(context.Background(), ExportFilters{Archived: &archived}, nil); !errors.As(err, &validationErr) {
+		t.Errorf("ExportTodos() unknown archival filter error = %v, want a validation error", err)
+	}
+}
+
+func TestTodoService_ExportTodos_Cancelled(t *testing.T) {
+	todos := make([]*domain.Todo, 3)
+	for i := range todos {
+		todos[i] = createTestTodo()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pages := 0
+	mockRepo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			pages++
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return todos, nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+
+	emitted := 0
+	err := service.ExportTodos(ctx, ExportFilters{}, func(todo *TodoResponse) error {
+		emitted++
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) || err.Error() != context.Canceled.Error() {
+		t.Errorf("ExportTodos() error = %v, want context.Canceled", err)
+	}
+	if emitted != 1 || pages != 1 {
+		t.Errorf("emitted %d todos of %d pages, want the export stopped at the next todo", emitted, pages)
+	}
+}
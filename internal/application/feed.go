@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Activity feed page sizes
+const (
+	DefaultActivityLimit = 50
+	MaxActivityLimit     = 200
+)
+
+// WithActivityFeed enables ListActivity
+func WithActivityFeed(feed ports.TodoActivityFeed) Option {
+	return func(s *TodoApplicationService) {
+		s.feed = feed
+	}
+}
+
+// ListActivity returns a page of the changes to all todos, most recent
+// first, for a team dashboard
+// cursor is empty for the first page, then the NextCursor of the previous
+// page; a limit of zero or less selects DefaultActivityLimit and larger
+// limits are capped to MaxActivityLimit
+func (s *TodoApplicationService) ListActivity(ctx context.Context, cursor string, limit int) (*ActivityFeedPage, error) {
+	if s.feed == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	var before int64
+	if cursor != "" {
+		var err error
+		before, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil || before <= 0 {
+			return nil, domain.NewValidationError("cursor", "is invalid")
+		}
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultActivityLimit
+	case limit > MaxActivityLimit:
+		limit = MaxActivityLimit
+	}
+
+	// One more event than requested tells whether there is a next page
+	events, err := s.feed.ListActivity(ctx, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("listing activity: %w", err)
+	}
+
+	page := &ActivityFeedPage{}
+	if len(events) > limit {
+		events = events[:limit]
+		page.NextCursor = strconv.FormatInt(events[limit-1].Seq, 10)
+	}
+
+	page.Entries = make([]*ActivityEntry, len(events))
+	for i, event := range events {
+		page.Entries[i] = &ActivityEntry{
+			TodoID:     event.TodoID.String(),
+			Title:      event.Title,
+			Kind:       string(event.Kind),
+			OccurredAt: event.OccurredAt,
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockActivityFeed serves events below the requested sequence number
+type MockActivityFeed struct {
+	Events    []ports.ActivityEvent
+	GotBefore int64
+	GotLimit  int
+	Err       error
+}
+
+func (m *MockActivityFeed) ListActivity(ctx context.Context, before int64, limit int) ([]ports.ActivityEvent, error) {
+	m.GotBefore, m.GotLimit = before, limit
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	var events []ports.ActivityEvent
+	for _, event := range m.Events {
+		if (before == 0 || event.Seq < before) && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func TestTodoService_ListActivity_Paginates(t *testing.T) {
+	id := domain.NewTodoID()
+	now := time.Now()
+	feed := &MockActivityFeed{Events: []ports.ActivityEvent{
+		{Seq: 3, TodoID: id, Title: "Buy milk", Kind: ports.ActivityCompleted, OccurredAt: now},
+		{Seq: 2, TodoID: id, Title: "Buy milk", Kind: ports.ActivityUpdated, OccurredAt: now.Add(-time.Minute)},
+		{Seq: 1, TodoID: id, Title: "Buy milk", Kind: ports.ActivityCreated, OccurredAt: now.Add(-time.Hour)},
+	}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithActivityFeed(feed))
+
+	first, err := service.ListActivity(context.Background(), "", 2)
+	if err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+	if len(first.Entries) != 2 || first.Entries[0].Kind != "completed" || first.Entries[1].Kind != "updated" {
+		t.Fatalf("first page = %+v, want the completed and updated events", first.Entries)
+	}
+	if first.NextCursor != "2" {
+		t.Errorf("NextCursor = %q, want %q", first.NextCursor, "2")
+	}
+
+	second, err := service.ListActivity(context.Background(), first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+	if feed.GotBefore != 2 {
+		t.Errorf("ListActivity() before = %d, want 2", feed.GotBefore)
+	}
+	if len(second.Entries) != 1 || second.Entries[0].Kind != "created" || second.Entries[0].TodoID != id.String() {
+		t.Errorf("second page = %+v, want the created event", second.Entries)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("NextCursor = %q, want none on the last page", second.NextCursor)
+	}
+}
+
+func TestTodoService_ListActivity_CapsLimit(t *testing.T) {
+	feed := &MockActivityFeed{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithActivityFeed(feed))
+
+	if _, err := service.ListActivity(context.Background(), "", 0); err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+	if feed.GotLimit != DefaultActivityLimit+1 {
+		t.Errorf("limit = %d, want %d", feed.GotLimit, DefaultActivityLimit+1)
+	}
+
+	if _, err := service.ListActivity(context.Background(), "", 10000); err != nil {
+		t.Fatalf("ListActivity() unexpected error: %v", err)
+	}
+	if feed.GotLimit != MaxActivityLimit+1 {
+		t.Errorf("limit = %d, want %d", feed.GotLimit, MaxActivityLimit+1)
+	}
+}
+
+func TestTodoService_ListActivity_Errors(t *testing.T) {
+	var validationErr domain.ValidationError
+
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithActivityFeed(&MockActivityFeed{}))
+	for _, cursor := range []string{"abc", "-1", "0"} {
+		if _, err := service.ListActivity(context.Background(), cursor, 10); !errors.As(err, &validationErr) {
+			t.Errorf("ListActivity(cursor %q) error = %v, want a ValidationError", cursor, err)
+		}
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.ListActivity(context.Background(), "", 10); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListActivity() error = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// AuditActionForceUpdate is the audit log action of ForceUpdateTodo
+const AuditActionForceUpdate = "force_update"
+
+// ErrAuditLogRequired is returned by privileged operations when no audit log is configured
+var ErrAuditLogRequired = errors.New("an audit log is required for privileged operations")
+
+// ForceUpdateTodo applies an administrative correction to a todo, bypassing
+// the completed-task modification and status transition rules
+// The override is recorded in the audit log before it is persisted, so no
+// forced change can go unaudited
+func (s *TodoApplicationService) ForceUpdateTodo(
+	ctx context.Context,
+	id string,
+	req ForceUpdateTodoRequest,
+) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.auditLog == nil {
+		return nil, ErrAuditLogRequired
+	}
+
+	// Parse and validate ID
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	changes, recorded, err := buildForcedChanges(req.UpdateTodoRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve existing todo
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	// Apply the override
+	if err := todo.ForceUpdate(changes, req.Actor, req.Reason); err != nil {
+		return nil, fmt.Errorf("forcing update: %w", err)
+	}
+
+	if len(todo.Events()) == 0 {
+		return MapTodoToResponse(todo), nil
+	}
+
+	// Audit before persisting
+	entry := ports.AuditEntry{
+		Action:     AuditActionForceUpdate,
+		TodoID:     todoID.String(),
+		Actor:      req.Actor,
+		Reason:     req.Reason,
+		Changes:    recorded,
+		OccurredAt: todo.UpdatedAt(),
+	}
+	if err := s.auditLog.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("recording audit entry: %w", err)
+	}
+
+	// Persist changes and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Update(ctx, todo); err != nil {
+			return nil, fmt.Errorf("updating todo: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Clear events after dispatching
+	todo.ClearEvents()
+
+	// Map to response DTO
+	return MapTodoToResponse(todo), nil
+}
+
+// buildForcedChanges validates an update request into domain ForcedChanges,
+// along with the field values to record in the audit log
+func buildForcedChanges(req UpdateTodoRequest) (domain.ForcedChanges, map[string]string, error) {
+	var changes domain.ForcedChanges
+	recorded := map[string]string{}
+
+	if req.Title != nil {
+		title, err := domain.NewTaskTitle(*req.Title)
+		if err != nil {
+			return changes, nil, fmt.Errorf("invalid title: %w", err)
+		}
+		changes.Title = &title
+		recorded["title"] = title.String()
+	}
+
+	if req.Description != nil {
+		changes.Description = req.Description
+		recorded["description"] = *req.Description
+	}
+
+	if req.Priority != nil {
+		priority, err := domain.NewPriority(*req.Priority)
+		if err != nil {
+			return changes, nil, fmt.Errorf("invalid priority: %w", err)
+		}
+		changes.Priority = &priority
+		recorded["priority"] = priority.String()
+	}
+
+	// A zero due date clears it, as in UpdateTodo
+	if req.DueDate != nil {
+		if *req.DueDate == (time.Time{}) {
+			changes.ClearDueDate = true
+			recorded["due_date"] = ""
+		} else {
+			dueDate, err := domain.NewDueDate(*req.DueDate)
+			if err != nil {
+				return changes, nil, fmt.Errorf("invalid due date: %w", err)
+			}
+			changes.DueDate = &dueDate
+			recorded["due_date"] = dueDate.Time().Format(time.RFC3339)
+		}
+	}
+
+	if req.Status != nil {
+		status, err := domain.NewTaskStatus(*req.Status)
+		if err != nil {
+			return changes, nil, fmt.Errorf("invalid status: %w", err)
+		}
+		changes.Status = &status
+		recorded["status"] = status.String()
+	}
+
+	return changes, recorded, nil
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockAuditLog records audit entries in memory
+type MockAuditLog struct {
+	RecordFunc func(ctx context.Context, entry ports.AuditEntry) error
+	Entries    []ports.AuditEntry
+}
+
+func (m *MockAuditLog) Record(ctx context.Context, entry ports.AuditEntry) error {
+	if m.RecordFunc != nil {
+		if err := m.RecordFunc(ctx, entry); err != nil {
+			return err
+		}
+	}
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+func completedTestTodo() *domain.Todo {
+	todo := createTestTodo()
+	_ = todo.Complete()
+	todo.ClearEvents()
+	return todo
+}
+
+func TestTodoService_ForceUpdateTodo_CompletedTodo_Success(t *testing.T) {
+	testTodo := completedTestTodo()
+	updated := false
+
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+			updated = true
+			return nil
+		},
+	}
+	mockDispatcher := &MockEventDispatcher{}
+	auditLog := &MockAuditLog{}
+	service := NewTodoApplicationService(mockRepo, mockDispatcher, WithAuditLog(auditLog))
+
+	newTitle := "Corrected title"
+	result, err := service.ForceUpdateTodo(context.Background(), testTodo.ID().String(), ForceUpdateTodoRequest{
+		UpdateTodoRequest: UpdateTodoRequest{Title: &newTitle},
+		Actor:             "ops@example.com",
+		Reason:            "Title typo on a closed ticket",
+	})
+
+	if err != nil {
+		t.Fatalf("ForceUpdateTodo() unexpected error: %v", err)
+	}
+
+	if result.Title != newTitle || result.Status != "completed" {
+		t.Errorf("Result = %+v, want corrected title on a completed todo", result)
+	}
+
+	if !updated {
+		t.Error("Expected repository Update to be called")
+	}
+
+	if len(auditLog.Entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(auditLog.Entries))
+	}
+
+	entry := auditLog.Entries[0]
+	if entry.Action != AuditActionForceUpdate || entry.Reason != "Title typo on a closed ticket" || entry.Changes["title"] != newTitle {
+		t.Errorf("Audit entry = %+v, want force_update with reason and title change", entry)
+	}
+
+	if len(mockDispatcher.DispatchedEvents) != 1 || mockDispatcher.DispatchedEvents[0].EventType() != "TodoForceUpdated" {
+		t.Errorf("Dispatched events = %v, want one TodoForceUpdated", mockDispatcher.DispatchedEvents)
+	}
+}
+
+func TestTodoService_ForceUpdateTodo_MissingReason_ReturnsError(t *testing.T) {
+	testTodo := completedTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	auditLog := &MockAuditLog{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithAuditLog(auditLog))
+
+	newTitle := "Corrected title"
+	_, err := service.ForceUpdateTodo(context.Background(), testTodo.ID().String(), ForceUpdateTodoRequest{
+		UpdateTodoRequest: UpdateTodoRequest{Title: &newTitle},
+	})
+
+	if !errors.Is(err, domain.ErrMissingReason) {
+		t.Errorf("ForceUpdateTodo() error = %v, want %v", err, domain.ErrMissingReason)
+	}
+
+	if len(auditLog.Entries) != 0 {
+		t.Errorf("Expected no audit entry, got %d", len(auditLog.Entries))
+	}
+}
+
+func TestTodoService_ForceUpdateTodo_AuditFailure_DoesNotPersist(t *testing.T) {
+	testTodo := completedTestTodo()
+	errAudit := errors.New("audit store down")
+
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+			t.Error("Update should not be called when auditing fails")
+			return nil
+		},
+	}
+	auditLog := &MockAuditLog{
+		RecordFunc: func(ctx context.Context, entry ports.AuditEntry) error {
+			return errAudit
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithAuditLog(auditLog))
+
+	status := "pending"
+	_, err := service.ForceUpdateTodo(context.Background(), testTodo.ID().String(), ForceUpdateTodoRequest{
+		UpdateTodoRequest: UpdateTodoRequest{Status: &status},
+		Actor:             "ops@example.com",
+		Reason:            "Completed by mistake",
+	})
+
+	if !errors.Is(err, errAudit) {
+		t.Errorf("ForceUpdateTodo() error = %v, want %v", err, errAudit)
+	}
+}
+
+func TestTodoService_ForceUpdateTodo_WithoutAuditLog_ReturnsError(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+
+	_, err := service.ForceUpdateTodo(context.Background(), domain.NewTodoID().String(), ForceUpdateTodoRequest{
+		Reason: "Completed by mistake",
+	})
+
+	if !errors.Is(err, ErrAuditLogRequired) {
+		t.Errorf("ForceUpdateTodo() error = %v, want %v", err, ErrAuditLogRequired)
+	}
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/lru"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Completion heatmap caching
+const (
+	// HeatmapCacheTTL bounds how stale a heatmap can be when another
+	// instance records a completion without a cache invalidation bus;
+	// completions through this instance refresh it at once
+	HeatmapCacheTTL = 5 * time.Minute
+	// HeatmapCacheSize is the number of users whose heatmap is cached
+	HeatmapCacheSize = 1024
+)
+
+// heatmapDays is the number of days before today covered by a heatmap
+const heatmapDays = 365
+
+// cachedHeatmap is a computed heatmap, when it was computed and when it
+// expires
+type cachedHeatmap struct {
+	heatmap    *CompletionHeatmap
+	computedAt time.Time
+	expires    time.Time
+}
+
+// WithCompletionLog records which authenticated user completes each todo,
+// and enables GetCompletionHeatmap
+func WithCompletionLog(log ports.CompletionLog) Option {
+	return func(s *TodoApplicationService) {
+		s.completions = log
+		s.heatmaps = lru.New[string, cachedHeatmap](HeatmapCacheSize)
+	}
+}
+
+// GetCompletionHeatmap returns the todos the authenticated user completed on
+// each UTC day over the past year, including today
+func (s *TodoApplicationService) GetCompletionHeatmap(ctx context.Context) (*CompletionHeatmap, error) {
+	if s.completions == nil {
+		return nil, ErrNotSupported
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	now := time.Now()
+	// Callers echoing a consistency token never see a heatmap older than
+	// their write, e.g. one computed by an instance not yet invalidated
+	if cached, ok := s.heatmaps.Get(userID); ok && now.Before(cached.expires) && freshEnough(ctx, cached.computedAt) {
+		return cached.heatmap, nil
+	}
+
+	counts, err := s.completions.DailyCompletions(ctx, userID, now.UTC().AddDate(0, 0, -heatmapDays))
+	if err != nil {
+		return nil, fmt.Errorf("counting completions: %w", err)
+	}
+
+	heatmap := &CompletionHeatmap{Days: make([]*HeatmapDay, len(counts))}
+	for i, count := range counts {
+		heatmap.Days[i] = &HeatmapDay{Date: count.Day, Count: count.Count}
+		heatmap.Total += count.Count
+		heatmap.Max = max(heatmap.Max, count.Count)
+	}
+
+	s.heatmaps.Add(userID, cachedHeatmap{heatmap: heatmap, computedAt: now, expires: now.Add(HeatmapCacheTTL)})
+
+	return heatmap, nil
+}
+
+// recordCompletion records that the authenticated user completed a todo
+// Recording is best effort: it must never fail the completion itself
+func (s *TodoApplicationService) recordCompletion(ctx context.Context, todoID domain.TodoID) {
+	if s.completions == nil {
+		return
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if err := s.completions.RecordCompletion(ctx, userID, todoID, time.Now()); err == nil {
+		s.invalidateCache(ctx, ports.CacheInvalidation{Cache: CacheHeatmaps, Key: userID})
+	}
+}
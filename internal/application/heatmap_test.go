@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockCompletionLog records completions and counts calls to DailyCompletions
+type MockCompletionLog struct {
+	Recorded []domain.TodoID
+	Counts   []ports.DailyCount
+	Queries  int
+}
+
+func (m *MockCompletionLog) RecordCompletion(ctx context.Context, userID string, todoID domain.TodoID, at time.Time) error {
+	m.Recorded = append(m.Recorded, todoID)
+	return nil
+}
+
+func (m *MockCompletionLog) DailyCompletions(ctx context.Context, userID string, since time.Time) ([]ports.DailyCount, error) {
+	m.Queries++
+	return m.Counts, nil
+}
+
+func TestTodoService_GetCompletionHeatmap_CachedUntilCompletion(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	log := &MockCompletionLog{Counts: []ports.DailyCount{
+		{Day: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+		{Day: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Day: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), Count: 5},
+	}}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithCompletionLog(log))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	heatmap, err := service.GetCompletionHeatmap(ctx)
+	if err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+	if len(heatmap.Days) != 3 || heatmap.Total != 7 || heatmap.Max != 5 {
+		t.Errorf("heatmap = %d days, total %d, max %d, want 3 days, total 7, max 5", len(heatmap.Days), heatmap.Total, heatmap.Max)
+	}
+
+	if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+	if log.Queries != 1 {
+		t.Errorf("DailyCompletions() called %d times, want the second read cached", log.Queries)
+	}
+
+	// Completing a todo records it and refreshes the heatmap
+	if _, err := service.CompleteTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("CompleteTodo() unexpected error: %v", err)
+	}
+	if len(log.Recorded) != 1 || log.Recorded[0] != testTodo.ID() {
+		t.Errorf("Recorded = %v, want [%v]", log.Recorded, testTodo.ID())
+	}
+
+	if _, err := service.GetCompletionHeatmap(ctx); err != nil {
+		t.Fatalf("GetCompletionHeatmap() unexpected error: %v", err)
+	}
+	if log.Queries != 2 {
+		t.Errorf("DailyCompletions() called %d times, want a refresh after the completion", log.Queries)
+	}
+}
+
+func TestTodoService_CompleteTodo_AnonymousCompletionNotRecorded(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	log := &MockCompletionLog{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithCompletionLog(log))
+
+	if _, err := service.CompleteTodo(context.Background(), testTodo.ID().String()); err != nil {
+		t.Fatalf("CompleteTodo() unexpected error: %v", err)
+	}
+	if len(log.Recorded) != 0 {
+		t.Errorf("Recorded = %v, want no anonymous completion", log.Recorded)
+	}
+}
+
+func TestTodoService_GetCompletionHeatmap_Errors(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCompletionLog(&MockCompletionLog{}))
+	if _, err := service.GetCompletionHeatmap(context.Background()); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("GetCompletionHeatmap() error = %v, want ErrUnauthenticated", err)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	ctx := ContextWithUserID(context.Background(), "alice")
+	if _, err := unsupported.GetCompletionHeatmap(ctx); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("GetCompletionHeatmap() error = %v, want ErrNotSupported", err)
+	}
+}
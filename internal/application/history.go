@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithHistory enables GetTodoAsOf
+func WithHistory(history ports.TodoHistory) Option {
+	return func(s *TodoApplicationService) {
+		s.history = history
+	}
+}
+
+// GetTodoAsOf returns a todo as it was at a past moment, e.g. to settle a
+// dispute or debug an automation
+// The todo is not found if it did not exist yet, or was deleted, at that moment
+func (s *TodoApplicationService) GetTodoAsOf(ctx context.Context, id string, at time.Time) (*TodoResponse, error) {
+	if s.history == nil {
+		return nil, ErrNotSupported
+	}
+
+	if at.After(time.Now()) {
+		return nil, domain.NewValidationError("as_of", "must not be in the future")
+	}
+
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.history.FindAsOf(ctx, todoID, at)
+	if err != nil {
+		return nil, fmt.Errorf("finding todo as of %s: %w", at.Format(time.RFC3339), err)
+	}
+
+	if err := s.authorize(ctx, ActionRead, todo); err != nil {
+		return nil, err
+	}
+
+	return MapTodoToResponse(todo), nil
+}
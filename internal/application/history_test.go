@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoHistory is a configurable TodoHistory
+type MockTodoHistory struct {
+	FindAsOfFunc func(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error)
+}
+
+func (m *MockTodoHistory) FindAsOf(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+	return m.FindAsOfFunc(ctx, id, at)
+}
+
+func TestTodoService_GetTodoAsOf_Success(t *testing.T) {
+	testTodo := createTestTodo()
+	at := time.Now().Add(-time.Hour)
+
+	var gotAt time.Time
+	history := &MockTodoHistory{
+		FindAsOfFunc: func(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+			gotAt = at
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithHistory(history))
+
+	result, err := service.GetTodoAsOf(context.Background(), testTodo.ID().String(), at)
+
+	if err != nil {
+		t.Fatalf("GetTodoAsOf() unexpected error: %v", err)
+	}
+
+	if result.ID != testTodo.ID().String() {
+		t.Errorf("GetTodoAsOf() ID = %v, want %v", result.ID, testTodo.ID())
+	}
+
+	if !gotAt.Equal(at) {
+		t.Errorf("FindAsOf() at = %v, want %v", gotAt, at)
+	}
+}
+
+func TestTodoService_GetTodoAsOf_Errors(t *testing.T) {
+	history := &MockTodoHistory{
+		FindAsOfFunc: func(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error) {
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithHistory(history))
+	id := domain.NewTodoID().String()
+
+	if _, err := service.GetTodoAsOf(context.Background(), id, time.Now().Add(-time.Hour)); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("GetTodoAsOf() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	var validationErr domain.ValidationError
+	if _, err := service.GetTodoAsOf(context.Background(), id, time.Now().Add(time.Hour)); !errors.As(err, &validationErr) {
+		t.Errorf("GetTodoAsOf() future error = %v, want a validation error", err)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.GetTodoAsOf(context.Background(), id, time.Now()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("GetTodoAsOf() without history error = %v, want %v", err, ErrNotSupported)
+	}
+}
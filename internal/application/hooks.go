@@ -0,0 +1,214 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrInboundHookNotFound is returned for unknown inbound hook IDs and tokens
+var ErrInboundHookNotFound = errors.New("inbound hook not found")
+
+// MaxHookNameLength bounds the name of an inbound hook
+const MaxHookNameLength = 100
+
+// hookTokenBytes is the entropy of an inbound hook token
+const hookTokenBytes = 32
+
+// WithInboundHooks enables inbound webhooks creating todos from JSON payloads
+func WithInboundHooks(store ports.InboundHookStore) Option {
+	return func(s *TodoApplicationService) {
+		s.hooks = store
+	}
+}
+
+// CreateInboundHook registers an inbound hook and returns it with its token
+// Only a hash of the token is stored: it cannot be shown again
+func (s *TodoApplicationService) CreateInboundHook(ctx context.Context, req InboundHookRequest) (*InboundHookCreated, error) {
+	if s.hooks == nil {
+		return nil, ErrNotSupported
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > MaxHookNameLength {
+		return nil, domain.NewValidationError("name", fmt.Sprintf("must be 1 to %d characters", MaxHookNameLength))
+	}
+
+	hook := ports.InboundHook{
+		ID:   uuid.New().String(),
+		Name: name,
+		Mapping: ports.HookMapping{
+			Title:       req.TitleTemplate,
+			Description: req.DescriptionTemplate,
+			Priority:    req.PriorityTemplate,
+			DueDate:     req.DueDateTemplate,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if strings.TrimSpace(hook.Mapping.Title) == "" {
+		return nil, domain.NewValidationError("title_template", "is required")
+	}
+	if _, err := parseHookMapping(hook.Mapping); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, hookTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generating hook token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	if err := s.hooks.Create(ctx, hook, hashHookToken(token)); err != nil {
+		return nil, fmt.Errorf("creating inbound hook: %w", err)
+	}
+
+	return &InboundHookCreated{Hook: mapInboundHook(hook), Token: token}, nil
+}
+
+// ListInboundHooks returns every inbound hook, oldest first
+func (s *TodoApplicationService) ListInboundHooks(ctx context.Context) ([]*InboundHookResponse, error) {
+	if s.hooks == nil {
+		return nil, ErrNotSupported
+	}
+
+	hooks, err := s.hooks.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing inbound hooks: %w", err)
+	}
+
+	responses := make([]*InboundHookResponse, len(hooks))
+	for i, hook := range hooks {
+		responses[i] = mapInboundHook(hook)
+	}
+	return responses, nil
+}
+
+// DeleteInboundHook removes an inbound hook, revoking its token
+func (s *TodoApplicationService) DeleteInboundHook(ctx context.Context, id string) error {
+	if s.hooks == nil {
+		return ErrNotSupported
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrInboundHookNotFound
+	}
+
+	deleted, err := s.hooks.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("deleting inbound hook: %w", err)
+	}
+	if !deleted {
+		return ErrInboundHookNotFound
+	}
+
+	return nil
+}
+
+// ReceiveHook creates a todo from the JSON payload posted to the hook of
+// token, mapped by the hook templates
+// A template referring to a field missing from the payload is a
+// ValidationError; an empty priority selects medium
+func (s *TodoApplicationService) ReceiveHook(ctx context.Context, token string, payload any) (*TodoResponse, error) {
+	if s.hooks == nil {
+		return nil, ErrNotSupported
+	}
+
+	hook, err := s.hooks.FindByTokenHash(ctx, hashHookToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("finding inbound hook: %w", err)
+	}
+	if hook == nil {
+		return nil, ErrInboundHookNotFound
+	}
+
+	templates, err := parseHookMapping(hook.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("inbound hook %s: %w", hook.ID, err)
+	}
+
+	fields := make(map[string]string, len(templates))
+	for field, tmpl := range templates {
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, payload); err != nil {
+			return nil, domain.NewValidationError(field, "cannot be mapped from the payload: "+err.Error())
+		}
+		fields[field] = strings.TrimSpace(out.String())
+	}
+
+	req := CreateTodoRequest{
+		Title:       fields["title"],
+		Description: fields["description"],
+		Priority:    fields["priority"],
+	}
+	if req.Priority == "" {
+		req.Priority = domain.PriorityMedium.String()
+	}
+	if raw := fields["due_date"]; raw != "" {
+		dueDate, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, domain.NewValidationError("due_date", "must map to an RFC 3339 timestamp")
+		}
+		req.DueDate = &dueDate
+	}
+
+	return s.CreateTodo(ctx, req)
+}
+
+// parseHookMapping parses the non-empty templates of a mapping, keyed by
+// the todo field they produce
+// Missing payload fields fail the execution instead of printing "<no value>"
+func parseHookMapping(mapping ports.HookMapping) (map[string]*template.Template, error) {
+	sources := map[string]string{
+		"title":       mapping.Title,
+		"description": mapping.Description,
+		"priority":    mapping.Priority,
+		"due_date":    mapping.DueDate,
+	}
+
+	templates := make(map[string]*template.Template, len(sources))
+	for field, source := range sources {
+		if source == "" {
+			continue
+		}
+
+		tmpl, err := template.New(field).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, domain.NewValidationError(field+"_template", err.Error())
+		}
+		templates[field] = tmpl
+	}
+
+	return templates, nil
+}
+
+// hashHookToken returns the stored form of an inbound hook token
+func hashHookToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// mapInboundHook converts a stored hook to its response DTO
+func mapInboundHook(hook ports.InboundHook) *InboundHookResponse {
+	return &InboundHookResponse{
+		ID:                  hook.ID,
+		Name:                hook.Name,
+		TitleTemplate:       hook.Mapping.Title,
+		DescriptionTemplate: hook.Mapping.Description,
+		PriorityTemplate:    hook.Mapping.Priority,
+		DueDateTemplate:     hook.Mapping.DueDate,
+		CreatedAt:           hook.CreatedAt,
+	}
+}
@@ -0,0 +1,168 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// storedHook is a hook kept by MockInboundHookStore
+type storedHook struct {
+	hook      ports.InboundHook
+	tokenHash []byte
+}
+
+// MockInboundHookStore keeps hooks in memory
+type MockInboundHookStore struct {
+	Hooks []storedHook
+}
+
+func (m *MockInboundHookStore) Create(ctx context.Context, hook ports.InboundHook, tokenHash []byte) error {
+	m.Hooks = append(m.Hooks, storedHook{hook, tokenHash})
+	return nil
+}
+
+func (m *MockInboundHookStore) FindByTokenHash(ctx context.Context, tokenHash []byte) (*ports.InboundHook, error) {
+	for _, stored := range m.Hooks {
+		if bytes.Equal(stored.tokenHash, tokenHash) {
+			return &stored.hook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockInboundHookStore) List(ctx context.Context) ([]ports.InboundHook, error) {
+	hooks := make([]ports.InboundHook, len(m.Hooks))
+	for i, stored := range m.Hooks {
+		hooks[i] = stored.hook
+	}
+	return hooks, nil
+}
+
+func (m *MockInboundHookStore) Delete(ctx context.Context, id string) (bool, error) {
+	for i, stored := range m.Hooks {
+		if stored.hook.ID == id {
+			m.Hooks = append(m.Hooks[:i], m.Hooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestTodoService_ReceiveHook_CreatesMappedTodo(t *testing.T) {
+	var saved *domain.Todo
+	mockRepo := &MockTodoRepository{
+		SaveFunc: func(ctx context.Context, todo *domain.Todo) error {
+			saved = todo
+			return nil
+		},
+	}
+	store := &MockInboundHookStore{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithInboundHooks(store))
+
+	created, err := service.CreateInboundHook(context.Background(), InboundHookRequest{
+		Name:                "Alertmanager",
+		TitleTemplate:       "[{{.status}}] {{.alert.name}}",
+		DescriptionTemplate: "{{range .alert.hosts}}{{.}} {{end}}",
+		PriorityTemplate:    `{{if eq .status "firing"}}urgent{{else}}low{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateInboundHook() unexpected error: %v", err)
+	}
+	if created.Token == "" {
+		t.Fatal("CreateInboundHook() returned no token")
+	}
+	if bytes.Contains(store.Hooks[0].tokenHash, []byte(created.Token)) {
+		t.Error("token is stored in clear")
+	}
+
+	payload := map[string]any{
+		"status": "firing",
+		"alert":  map[string]any{"name": "DiskFull", "hosts": []any{"db1", "db2"}},
+	}
+	todo, err := service.ReceiveHook(context.Background(), created.Token, payload)
+	if err != nil {
+		t.Fatalf("ReceiveHook() unexpected error: %v", err)
+	}
+
+	if todo.Title != "[firing] DiskFull" || todo.Description != "db1 db2" || todo.Priority != "urgent" {
+		t.Errorf("todo = %q / %q / %q, want the mapped payload", todo.Title, todo.Description, todo.Priority)
+	}
+	if saved == nil || saved.ID().String() != todo.ID {
+		t.Error("mapped todo was not saved")
+	}
+}
+
+func TestTodoService_ReceiveHook_Errors(t *testing.T) {
+	var validationErr domain.ValidationError
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithInboundHooks(&MockInboundHookStore{}))
+
+	created, err := service.CreateInboundHook(context.Background(), InboundHookRequest{
+		Name:            "Form",
+		TitleTemplate:   "{{.subject}}",
+		DueDateTemplate: "{{.deadline}}",
+	})
+	if err != nil {
+		t.Fatalf("CreateInboundHook() unexpected error: %v", err)
+	}
+
+	if _, err := service.ReceiveHook(context.Background(), "unknown", map[string]any{}); !errors.Is(err, ErrInboundHookNotFound) {
+		t.Errorf("ReceiveHook(unknown token) error = %v, want ErrInboundHookNotFound", err)
+	}
+
+	missing := map[string]any{"deadline": "2030-01-01T00:00:00Z"}
+	if _, err := service.ReceiveHook(context.Background(), created.Token, missing); !errors.As(err, &validationErr) {
+		t.Errorf("ReceiveHook(missing field) error = %v, want a ValidationError", err)
+	}
+
+	badDate := map[string]any{"subject": "Call back", "deadline": "tomorrow"}
+	if _, err := service.ReceiveHook(context.Background(), created.Token, badDate); !errors.As(err, &validationErr) {
+		t.Errorf("ReceiveHook(invalid due date) error = %v, want a ValidationError", err)
+	}
+}
+
+func TestTodoService_CreateInboundHook_Validation(t *testing.T) {
+	var validationErr domain.ValidationError
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithInboundHooks(&MockInboundHookStore{}))
+
+	tests := []struct {
+		name string
+		req  InboundHookRequest
+	}{
+		{"missing name", InboundHookRequest{TitleTemplate: "{{.title}}"}},
+		{"missing title template", InboundHookRequest{Name: "Form"}},
+		{"invalid template", InboundHookRequest{Name: "Form", TitleTemplate: "{{.title"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateInboundHook(context.Background(), tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("CreateInboundHook() error = %v, want a ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestTodoService_DeleteInboundHook_RevokesToken(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithInboundHooks(&MockInboundHookStore{}))
+
+	created, err := service.CreateInboundHook(context.Background(), InboundHookRequest{Name: "Form", TitleTemplate: "{{.subject}}"})
+	if err != nil {
+		t.Fatalf("CreateInboundHook() unexpected error: %v", err)
+	}
+
+	if err := service.DeleteInboundHook(context.Background(), created.Hook.ID); err != nil {
+		t.Fatalf("DeleteInboundHook() unexpected error: %v", err)
+	}
+	if err := service.DeleteInboundHook(context.Background(), created.Hook.ID); !errors.Is(err, ErrInboundHookNotFound) {
+		t.Errorf("DeleteInboundHook() again error = %v, want ErrInboundHookNotFound", err)
+	}
+
+	if _, err := service.ReceiveHook(context.Background(), created.Token, map[string]any{"subject": "x"}); !errors.Is(err, ErrInboundHookNotFound) {
+		t.Errorf("ReceiveHook() after deletion error = %v, want ErrInboundHookNotFound", err)
+	}
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrUnauthenticated is returned by per-user operations called without a user
+var ErrUnauthenticated = errors.New("authentication required")
+
+// userIDKey is the context key of the authenticated user ID
+type userIDKey struct{}
+
+// scopesKey is the context key of the scopes granted to the caller's token
+type scopesKey struct{}
+
+// rolesKey is the context key of the roles carried by the caller's token
+type rolesKey struct{}
+
+// apiKeyIDKey is the context key of the API key the caller authenticated with
+type apiKeyIDKey struct{}
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user ID
+// Adapters call it once they have authenticated the caller. Repository
+// queries are then scoped to the todos the user owns
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	ctx = ports.ContextWithOwner(ctx, userID)
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user ID carried by ctx, if any
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// ContextWithScopes returns a copy of ctx carrying the scopes granted to the
+// caller's token, possibly none
+// Adapters call it when their authentication mechanism grants scopes
+func ContextWithScopes(ctx context.Context, scopes []string) context.Context {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// ScopesFromContext returns the scopes granted to the caller, and false when
+// the caller was not authenticated by a mechanism granting scopes
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(scopesKey{}).([]string)
+	return scopes, ok
+}
+
+// ContextWithRoles returns a copy of ctx carrying the roles of the caller,
+// possibly none
+// Adapters call it when their authentication mechanism carries roles
+func ContextWithRoles(ctx context.Context, roles []string) context.Context {
+	if roles == nil {
+		roles = []string{}
+	}
+	return context.WithValue(ctx, rolesKey{}, roles)
+}
+
+// RolesFromContext returns the roles of the caller, and false when the
+// caller was not authenticated by a mechanism carrying roles
+func RolesFromContext(ctx context.Context) ([]string, bool) {
+	roles, ok := ctx.Value(rolesKey{}).([]string)
+	return roles, ok
+}
+
+// ContextWithAPIKeyID returns a copy of ctx carrying the ID of the API key
+// the caller authenticated with
+// Adapters call it alongside ContextWithUserID, so that per-client state is
+// kept apart for each key of a user
+func ContextWithAPIKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, keyID)
+}
+
+// APIKeyIDFromContext returns the ID of the API key carried by ctx, and false
+// when the caller did not authenticate with one
+func APIKeyIDFromContext(ctx context.Context) (string, bool) {
+	keyID, ok := ctx.Value(apiKeyIDKey{}).(string)
+	return keyID, ok && keyID != ""
+}
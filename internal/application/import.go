@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+const (
+	// MaxImportRows is the largest number of todos a single import can create
+	MaxImportRows = 5000
+
+	// ImportBatchSize is the number of todos an import saves per transaction
+	ImportBatchSize = 100
+)
+
+// WithImports enables ImportTodos
+func WithImports(saver ports.TodoBatchSaver) Option {
+	return func(s *TodoApplicationService) {
+		s.batchSaver = saver
+	}
+}
+
+// importedRow is a validated todo waiting to be saved
+type importedRow struct {
+	line int
+	todo *domain.Todo
+}
+
+// ImportTodos creates the todos of req.Rows, each as CreateTodo would,
+// keeping the past due dates of the rows marked KeepPastDueDate, then moves
+// them to the status of their row; completed ones are completed as of the
+// import
+// A row that could not be read, is invalid or is forbidden is reported as a
+// failure and left out. The other todos are saved in batches of
+// ImportBatchSize, one transaction each; when a batch cannot be saved, the
+// import stops and the rows of this batch and the next ones are reported
+// with its error, while earlier batches stay imported. Imported todos are
+// not tracked as recent activity
+// Cancelling ctx stops the import before its next batch: the response so
+// far is returned with the error of ctx, the rows left listed as skipped.
+// The events of the batches saved are dispatched even once ctx is cancelled
+func (s *TodoApplicationService) ImportTodos(ctx context.Context, req ImportTodosRequest) (*ImportTodosResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.batchSaver == nil {
+		return nil, ErrNotSupported
+	}
+
+	if len(req.Rows) == 0 {
+		return nil, domain.NewValidationError("rows", "at least one todo is required")
+	}
+	if len(req.Rows) > MaxImportRows {
+		return nil, domain.NewValidationError("rows", fmt.Sprintf("at most %d todos can be imported at once", MaxImportRows))
+	}
+
+	response := &ImportTodosResponse{Imported: []ImportedTodo{}, Failures: []ImportFailure{}, Skipped: []int{}}
+	var pending []importedRow
+	for _, row := range req.Rows {
+		if row.Err != nil {
+			response.Failures = append(response.Failures, ImportFailure{Line: row.Line, Err: row.Err})
+			continue
+		}
+
+		todo, err := s.newTodo(ctx, row.Request, row.KeepPastDueDate)
+		if err == nil {
+			err = applyImportedStatus(todo, row.Status)
+		}
+		if err != nil {
+			if !isRejection(err) {
+				return nil, err
+			}
+			response.Failures = append(response.Failures, ImportFailure{Line: row.Line, Err: err})
+			continue
+		}
+		pending = append(pending, importedRow{line: row.Line, todo: todo})
+	}
+
+	for start := 0; start < len(pending); start += ImportBatchSize {
+		if err := ctx.Err(); err != nil {
+			for _, row := range pending[start:] {
+				response.Skipped = append(response.Skipped, row.line)
+			}
+			sortFailures(response.Failures)
+			return response, fmt.Errorf("import cancelled: %w", err)
+		}
+
+		batch := pending[start:min(start+ImportBatchSize, len(pending))]
+		todos := make([]*domain.Todo, len(batch))
+		for i, row := range batch {
+			todos[i] = row.todo
+		}
+
+		// A saved batch is announced even when ctx is cancelled meanwhile
+		var saveErr error
+		err := s.commit(context.WithoutCancel(ctx), func(ctx context.Context) ([]domain.DomainEvent, error) {
+			if err := s.batchSaver.SaveMany(ctx, todos); err != nil {
+				saveErr = fmt.Errorf("saving batch: %w", err)
+				return nil, saveErr
+			}
+			var events []domain.DomainEvent
+			for _, todo := range todos {
+				events = append(events, todo.Events()...)
+			}
+			return events, nil
+		})
+		if saveErr != nil {
+			for _, row := range pending[start:] {
+				response.Failures = append(response.Failures, ImportFailure{Line: row.line, Err: saveErr})
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range batch {
+			row.todo.ClearEvents()
+			response.Imported = append(response.Imported, ImportedTodo{Line: row.line, Todo: MapTodoToResponse(row.todo)})
+		}
+		if req.Progress != nil {
+			req.Progress(ImportProgress{Saved: len(response.Imported), Failed: len(response.Failures), Total: len(req.Rows)})
+		}
+	}
+
+	sortFailures(response.Failures)
+	return response, nil
+}
+
+// applyImportedStatus moves a todo just created to status, leaving it
+// pending when status is empty
+func applyImportedStatus(todo *domain.Todo, status string) error {
+	if status == "" {
+		return nil
+	}
+
+	taskStatus, err := domain.NewTaskStatus(status)
+	if err != nil {
+		return fmt.Errorf("invalid status: %w", err)
+	}
+
+	switch taskStatus {
+	case domain.StatusInProgress:
+		return todo.MarkInProgress()
+	case domain.StatusCompleted:
+		return todo.Complete()
+	case domain.StatusCancelled:
+		return todo.Cancel()
+	default:
+		return nil
+	}
+}
+
+// sortFailures sorts the failures of an import in line order
+func sortFailures(failures []ImportFailure) {
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Line < failures[j].Line
+	})
+}
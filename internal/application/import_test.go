@@ -0,0 +1,265 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoBatchSaver records the batches it saves
+type MockTodoBatchSaver struct {
+	SaveManyFunc func(ctx context.Context, todos []*domain.Todo) error
+	Batches      [][]*domain.Todo
+}
+
+func (m *MockTodoBatchSaver) SaveMany(ctx context.Context, todos []*domain.Todo) error {
+	if m.SaveManyFunc != nil {
+		if err := m.SaveManyFunc(ctx, todos); err != nil {
+			return err
+		}
+	}
+	m.Batches = append(m.Batches, todos)
+	return nil
+}
+
+// importRows returns n valid rows, from line 2 on
+func importRows(n int) []ImportRow {
+	rows := make([]ImportRow, n)
+	for i := range rows {
+		rows[i] = ImportRow{Line: i + 2, Request: CreateTodoRequest{Title: fmt.Sprintf("Todo %d", i), Priority: "medium"}}
+	}
+	return rows
+}
+
+func TestTodoService_ImportTodos_ReportsFailedRows(t *testing.T) {
+	saver := &MockTodoBatchSaver{}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(newMergeTestRepository(), dispatcher, WithImports(saver))
+	past := time.Now().Add(-24 * time.Hour)
+
+	rows := []ImportRow{
+		{Line: 2, Request: CreateTodoRequest{Title: "Write the report", Priority: "high"}},
+		{Line: 3, Request: CreateTodoRequest{Title: "", Priority: "high"}},
+		{Line: 4, Err: domain.NewValidationError("due_date", "is not a date")},
+		{Line: 5, Request: CreateTodoRequest{Title: "Plan the launch", Priority: "whenever"}},
+		{Line: 6, Request: CreateTodoRequest{Title: "Renew the domain", Priority: "low", DueDate: &past}},
+		{Line: 7, Request: CreateTodoRequest{Title: "Book the venue", Priority: "low"}},
+	}
+
+	result, err := service.ImportTodos(ContextWithUserID(context.Background(), "alice"), ImportTodosRequest{Rows: rows})
+
+	if err != nil {
+		t.Fatalf("ImportTodos() unexpected error: %v", err)
+	}
+	if len(result.Imported) != 2 || result.Imported[0].Line != 2 || result.Imported[1].Line != 7 {
+		t.Fatalf("Imported = %+v, want lines 2 and 7", result.Imported)
+	}
+	if result.Imported[0].Todo.OwnerID != "alice" {
+		t.Errorf("OwnerID = %q, want alice", result.Imported[0].Todo.OwnerID)
+	}
+
+	wantFailures := []struct {
+		line int
+		err  error
+	}{
+		{3, nil},
+		{4, nil},
+		{5, domain.ErrInvalidPriority},
+		{6, domain.ErrInvalidDueDate},
+	}
+	if len(result.Failures) != len(wantFailures) {
+		t.Fatalf("Failures = %+v, want %d", result.Failures, len(wantFailures))
+	}
+	for i, want := range wantFailures {
+		failure := result.Failures[i]
+		if failure.Line != want.line || (want.err != nil && !errors.Is(failure.Err, want.err)) {
+			t.Errorf("Failures[%d] = %+v, want line %d with %v", i, failure, want.line, want.err)
+		}
+	}
+
+	if len(saver.Batches) != 1 || len(saver.Batches[0]) != 2 {
+		t.Errorf("SaveMany() batches = %v, want one of 2 todos", saver.Batches)
+	}
+	if len(dispatcher.DispatchedEvents) != 2 {
+		t.Errorf("dispatched %d events, want 2", len(dispatcher.DispatchedEvents))
+	}
+}
+
+func TestTodoService_ImportTodos_Statuses(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name       string
+		row        ImportRow
+		wantStatus domain.TaskStatus
+		wantErr    error
+	}{
+		{"no status", ImportRow{}, domain.StatusPending, nil},
+		{"pending", ImportRow{Status: "pending"}, domain.StatusPending, nil},
+		{"in progress", ImportRow{Status: "in_progress"}, domain.StatusInProgress, nil},
+		{"completed", ImportRow{Status: "Completed"}, domain.StatusCompleted, nil},
+		{"cancelled", ImportRow{Status: "cancelled"}, domain.StatusCancelled, nil},
+		{"completed overdue", ImportRow{Status: "completed", Request: CreateTodoRequest{DueDate: &past}, KeepPastDueDate: true}, domain.StatusCompleted, nil},
+		{"overdue refused", ImportRow{Request: CreateTodoRequest{DueDate: &past}}, "", domain.ErrInvalidDueDate},
+		{"unknown status", ImportRow{Status: "done"}, "", domain.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saver := &MockTodoBatchSaver{}
+			dispatcher := &MockEventDispatcher{}
+			service := NewTodoApplicationService(newMergeTestRepository(), dispatcher, WithImports(saver))
+			row := tt.row
+			row.Line = 2
+			row.Request.Title = "Pay the invoice"
+			row.Request.Priority = "high"
+
+			result, err := service.ImportTodos(context.Background(), ImportTodosRequest{Rows: []ImportRow{row}})
+			if err != nil {
+				t.Fatalf("ImportTodos() unexpected error: %v", err)
+			}
+
+			if tt.wantErr != nil {
+				if len(result.Failures) != 1 || !errors.Is(result.Failures[0].Err, tt.wantErr) {
+					t.Errorf("Failures = %+v, want %v", result.Failures, tt.wantErr)
+				}
+				return
+			}
+			if len(saver.Batches) != 1 || len(saver.Batches[0]) != 1 {
+				t.Fatalf("saved %v, want the todo", saver.Batches)
+			}
+			todo := saver.Batches[0][0]
+			if todo.Status() != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", todo.Status(), tt.wantStatus)
+			}
+			if tt.wantStatus == domain.StatusCompleted && todo.CompletedAt() == nil {
+				t.Error("CompletedAt() = nil, want the time of the import")
+			}
+			if row.Request.DueDate != nil && (todo.DueDate() == nil || !todo.DueDate().Time().Equal(past)) {
+				t.Errorf("DueDate() = %v, want %v", todo.DueDate(), past)
+			}
+		})
+	}
+}
+
+func TestTodoService_ImportTodos_SavesInBatches(t *testing.T) {
+	errSave := errors.New("connection reset")
+	saver := &MockTodoBatchSaver{SaveManyFunc: func(ctx context.Context, todos []*domain.Todo) error {
+		if todos[0].Title().String() == fmt.Sprintf("Todo %d", 2*ImportBatchSize) {
+			return errSave
+		}
+		return nil
+	}}
+	service := NewTodoApplicationService(newMergeTestRepository(), &MockEventDispatcher{}, WithImports(saver))
+	rows := importRows(2*ImportBatchSize + 10)
+	var progress []ImportProgress
+
+	result, err := service.ImportTodos(context.Background(), ImportTodosRequest{
+		Rows:     rows,
+		Progress: func(p ImportProgress) { progress = append(progress, p) },
+	})
+
+	if err != nil {
+		t.Fatalf("ImportTodos() unexpected error: %v", err)
+	}
+	if len(saver.Batches) != 2 || len(saver.Batches[1]) != ImportBatchSize {
+		t.Errorf("SaveMany() saved %d batches, want 2 of %d todos", len(saver.Batches), ImportBatchSize)
+	}
+	// Earlier batches stay imported, the rows of the failed one are reported
+	if len(result.Imported) != 2*ImportBatchSize {
+		t.Errorf("Imported %d todos, want %d", len(result.Imported), 2*ImportBatchSize)
+	}
+	if len(result.Failures) != 10 || result.Failures[0].Line != rows[2*ImportBatchSize].Line || !errors.Is(result.Failures[0].Err, errSave) {
+		t.Errorf("Failures = %+v, want the 10 last rows with the save error", result.Failures)
+	}
+	// The failed batch is not reported as progress
+	if len(progress) != 2 || progress[1] != (ImportProgress{Saved: 2 * ImportBatchSize, Total: len(rows)}) {
+		t.Errorf("progress = %+v, want a report per saved batch", progress)
+	}
+}
+
+func TestTodoService_ImportTodos_Errors(t *testing.T) {
+	repo := newMergeTestRepository()
+	errDispatch := errors.New("broker down")
+
+	tests := []struct {
+		name    string
+		service *TodoApplicationService
+		rows    []ImportRow
+		want    error
+	}{
+		{"not configured", NewTodoApplicationService(repo, &MockEventDispatcher{}), importRows(1), ErrNotSupported},
+		{"read only", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithImports(&MockTodoBatchSaver{}), WithMaintenanceMode(NewMaintenanceMode(true, ""))), importRows(1), ErrMaintenanceMode},
+		{"dispatch fails", NewTodoApplicationService(repo, &MockEventDispatcher{DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+			return errDispatch
+		}}, WithImports(&MockTodoBatchSaver{})), importRows(1), errDispatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.service.ImportTodos(context.Background(), ImportTodosRequest{Rows: tt.rows}); !errors.Is(err, tt.want) {
+				t.Errorf("ImportTodos() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithImports(&MockTodoBatchSaver{}))
+	for _, rows := range [][]ImportRow{nil, importRows(MaxImportRows + 1)} {
+		var validationErr domain.ValidationError
+		if _, err := service.ImportTodos(context.Background(), ImportTodosRequest{Rows: rows}); !errors.As(err, &validationErr) {
+			t.Errorf("ImportTodos() of %d rows error = %v, want a validation error", len(rows), err)
+		}
+	}
+}
+
+func TestTodoService_ImportTodos_Denied(t *testing.T) {
+	saver := &MockTodoBatchSaver{}
+	service := NewTodoApplicationService(newMergeTestRepository(), &MockEventDispatcher{}, WithImports(saver), WithAuthorizer(&MockAuthorizer{Allow: false}))
+
+	result, err := service.ImportTodos(context.Background(), ImportTodosRequest{Rows: importRows(2)})
+
+	if err != nil {
+		t.Fatalf("ImportTodos() unexpected error: %v", err)
+	}
+	if len(result.Failures) != 2 || !errors.Is(result.Failures[0].Err, ErrForbidden) {
+		t.Errorf("Failures = %+v, want both rows forbidden", result.Failures)
+	}
+	if len(saver.Batches) != 0 {
+		t.Errorf("SaveMany() called %d times, want none", len(saver.Batches))
+	}
+}
+
+func TestTodoService_ImportTodos_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The client goes away while the first batch is saved
+	saver := &MockTodoBatchSaver{SaveManyFunc: func(context.Context, []*domain.Todo) error {
+		cancel()
+		return nil
+	}}
+	dispatcher := &MockEventDispatcher{DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+		return ctx.Err()
+	}}
+	service := NewTodoApplicationService(newMergeTestRepository(), dispatcher, WithImports(saver))
+	rows := importRows(2*ImportBatchSize + 10)
+	rows[len(rows)-1].Err = domain.NewValidationError("due_date", "is not a date")
+
+	result, err := service.ImportTodos(ctx, ImportTodosRequest{Rows: rows})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ImportTodos() error = %v, want context.Canceled", err)
+	}
+	// The saved batch is kept and announced, the next ones are skipped
+	if len(saver.Batches) != 1 || len(result.Imported) != ImportBatchSize {
+		t.Errorf("imported %d todos in %d batches, want the first batch", len(result.Imported), len(saver.Batches))
+	}
+	if len(result.Skipped) != ImportBatchSize+9 || result.Skipped[0] != rows[ImportBatchSize].Line {
+		t.Errorf("Skipped = %v, want the %d valid rows after the first batch", result.Skipped, ImportBatchSize+9)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Line != rows[len(rows)-1].Line {
+		t.Errorf("Failures = %+v, want the invalid row", result.Failures)
+	}
+}
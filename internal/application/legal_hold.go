@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Audit log actions of legal holds
+const (
+	AuditActionLegalHold        = "legal_hold"
+	AuditActionLegalHoldRelease = "legal_hold_release"
+)
+
+// ErrLegalHold is returned when deleting a todo that is on legal hold
+var ErrLegalHold = errors.New("todo is on legal hold")
+
+// ErrLegalHoldNotFound is returned when releasing a todo that is not on hold
+var ErrLegalHoldNotFound = errors.New("legal hold not found")
+
+// WithLegalHolds enables legal holds, which keep todos from being deleted
+// or purged until they are released
+func WithLegalHolds(store ports.LegalHoldStore) Option {
+	return func(s *TodoApplicationService) {
+		s.legalHolds = store
+	}
+}
+
+// PlaceLegalHold puts a todo on legal hold, replacing any hold already placed
+// The hold is recorded in the audit log before it is placed
+func (s *TodoApplicationService) PlaceLegalHold(ctx context.Context, id string, req LegalHoldRequest) (*LegalHoldResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.legalHolds == nil {
+		return nil, ErrNotSupported
+	}
+
+	if s.auditLog == nil {
+		return nil, ErrAuditLogRequired
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ErrMissingReason
+	}
+
+	todo, err := s.findTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := ports.LegalHold{
+		TodoID:   todo.ID(),
+		Reason:   req.Reason,
+		PlacedBy: req.Actor,
+		PlacedAt: time.Now(),
+	}
+
+	if err := s.auditLegalHold(ctx, AuditActionLegalHold, hold.TodoID, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.legalHolds.Place(ctx, hold); err != nil {
+		return nil, fmt.Errorf("placing legal hold: %w", err)
+	}
+
+	return mapLegalHold(hold), nil
+}
+
+// ReleaseLegalHold releases the legal hold on a todo
+// The release is recorded in the audit log before the hold is removed
+func (s *TodoApplicationService) ReleaseLegalHold(ctx context.Context, id string, req LegalHoldRequest) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.legalHolds == nil {
+		return ErrNotSupported
+	}
+
+	if s.auditLog == nil {
+		return ErrAuditLogRequired
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return domain.ErrMissingReason
+	}
+
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	held, err := s.legalHolds.Held(ctx, []domain.TodoID{todoID})
+	if err != nil {
+		return fmt.Errorf("checking legal hold: %w", err)
+	}
+	if !held[todoID] {
+		return ErrLegalHoldNotFound
+	}
+
+	if err := s.auditLegalHold(ctx, AuditActionLegalHoldRelease, todoID, req); err != nil {
+		return err
+	}
+
+	if _, err := s.legalHolds.Release(ctx, todoID); err != nil {
+		return fmt.Errorf("releasing legal hold: %w", err)
+	}
+
+	return nil
+}
+
+// ListLegalHolds returns every legal hold, oldest first
+func (s *TodoApplicationService) ListLegalHolds(ctx context.Context) ([]*LegalHoldResponse, error) {
+	if s.legalHolds == nil {
+		return nil, ErrNotSupported
+	}
+
+	holds, err := s.legalHolds.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing legal holds: %w", err)
+	}
+
+	responses := make([]*LegalHoldResponse, len(holds))
+	for i, hold := range holds {
+		responses[i] = mapLegalHold(hold)
+	}
+
+	return responses, nil
+}
+
+// checkNotHeld returns ErrLegalHold when the todo is on legal hold
+func (s *TodoApplicationService) checkNotHeld(ctx context.Context, todoID domain.TodoID) error {
+	if s.legalHolds == nil {
+		return nil
+	}
+
+	held, err := s.legalHolds.Held(ctx, []domain.TodoID{todoID})
+	if err != nil {
+		return fmt.Errorf("checking legal hold: %w", err)
+	}
+	if held[todoID] {
+		return ErrLegalHold
+	}
+
+	return nil
+}
+
+// withoutHeld splits todos into those that may be deleted and the number of
+// todos on legal hold
+func (s *TodoApplicationService) withoutHeld(ctx context.Context, todos []*domain.Todo) ([]*domain.Todo, int, error) {
+	if s.legalHolds == nil || len(todos) == 0 {
+		return todos, 0, nil
+	}
+
+	ids := make([]domain.TodoID, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID()
+	}
+
+	held, err := s.legalHolds.Held(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("checking legal holds: %w", err)
+	}
+
+	free := make([]*domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if !held[todo.ID()] {
+			free = append(free, todo)
+		}
+	}
+
+	return free, len(todos) - len(free), nil
+}
+
+// auditLegalHold records placing or releasing a legal hold
+func (s *TodoApplicationService) auditLegalHold(ctx context.Context, action string, todoID domain.TodoID, req LegalHoldRequest) error {
+	entry := ports.AuditEntry{
+		Action:     action,
+		TodoID:     todoID.String(),
+		Actor:      req.Actor,
+		Reason:     req.Reason,
+		OccurredAt: time.Now(),
+	}
+	if err := s.auditLog.Record(ctx, entry); err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// mapLegalHold converts a LegalHold to a LegalHoldResponse DTO
+func mapLegalHold(hold ports.LegalHold) *LegalHoldResponse {
+	return &LegalHoldResponse{
+		TodoID:   hold.TodoID.String(),
+		Reason:   hold.Reason,
+		PlacedBy: hold.PlacedBy,
+		PlacedAt: hold.PlacedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockLegalHoldStore keeps legal holds in memory
+type MockLegalHoldStore struct {
+	Holds map[domain.TodoID]ports.LegalHold
+}
+
+func (m *MockLegalHoldStore) Place(ctx context.Context, hold ports.LegalHold) error {
+	if m.Holds == nil {
+		m.Holds = make(map[domain.TodoID]ports.LegalHold)
+	}
+	m.Holds[hold.TodoID] = hold
+	return nil
+}
+
+func (m *MockLegalHoldStore) Release(ctx context.Context, todoID domain.TodoID) (bool, error) {
+	_, ok := m.Holds[todoID]
+	delete(m.Holds, todoID)
+	return ok, nil
+}
+
+func (m *MockLegalHoldStore) Held(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error) {
+	held := make(map[domain.TodoID]bool)
+	for _, id := range ids {
+		if _, ok := m.Holds[id]; ok {
+			held[id] = true
+		}
+	}
+	return held, nil
+}
+
+func (m *MockLegalHoldStore) List(ctx context.Context) ([]ports.LegalHold, error) {
+	holds := make([]ports.LegalHold, 0, len(m.Holds))
+	for _, hold := range m.Holds {
+		holds = append(holds, hold)
+	}
+	return holds, nil
+}
+
+func TestTodoService_LegalHold_BlocksDeletion(t *testing.T) {
+	testTodo := createTestTodo()
+	deleted := false
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		DeleteFunc: func(ctx context.Context, id domain.TodoID) error {
+			deleted = true
+			return nil
+		},
+	}
+	auditLog := &MockAuditLog{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{},
+		WithAuditLog(auditLog),
+		WithLegalHolds(&MockLegalHoldStore{}),
+	)
+	ctx := context.Background()
+	req := LegalHoldRequest{Actor: "legal@example.com", Reason: "Case 42"}
+
+	hold, err := service.PlaceLegalHold(ctx, testTodo.ID().String(), req)
+	if err != nil {
+		t.Fatalf("PlaceLegalHold() unexpected error: %v", err)
+	}
+	if hold.TodoID != testTodo.ID().String() || hold.PlacedBy != req.Actor {
+		t.Errorf("PlaceLegalHold() = %+v", hold)
+	}
+
+	if err := service.DeleteTodo(ctx, testTodo.ID().String()); !errors.Is(err, ErrLegalHold) {
+		t.Fatalf("DeleteTodo() error = %v, want %v", err, ErrLegalHold)
+	}
+	if deleted {
+		t.Fatal("held todo was deleted")
+	}
+
+	if err := service.ReleaseLegalHold(ctx, testTodo.ID().String(), req); err != nil {
+		t.Fatalf("ReleaseLegalHold() unexpected error: %v", err)
+	}
+	if err := service.DeleteTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("DeleteTodo() after release unexpected error: %v", err)
+	}
+
+	if len(auditLog.Entries) != 2 ||
+		auditLog.Entries[0].Action != AuditActionLegalHold ||
+		auditLog.Entries[1].Action != AuditActionLegalHoldRelease {
+		t.Errorf("audit entries = %+v, want the hold and its release", auditLog.Entries)
+	}
+}
+
+func TestTodoService_LegalHold_Errors(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{},
+		WithAuditLog(&MockAuditLog{}),
+		WithLegalHolds(&MockLegalHoldStore{}),
+	)
+	ctx := context.Background()
+
+	_, err := service.PlaceLegalHold(ctx, testTodo.ID().String(), LegalHoldRequest{Actor: "legal@example.com"})
+	if !errors.Is(err, domain.ErrMissingReason) {
+		t.Errorf("PlaceLegalHold() error = %v, want %v", err, domain.ErrMissingReason)
+	}
+
+	err = service.ReleaseLegalHold(ctx, testTodo.ID().String(), LegalHoldRequest{Actor: "legal@example.com", Reason: "Closed"})
+	if !errors.Is(err, ErrLegalHoldNotFound) {
+		t.Errorf("ReleaseLegalHold() error = %v, want %v", err, ErrLegalHoldNotFound)
+	}
+
+	unsupported := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithAuditLog(&MockAuditLog{}))
+	if _, err := unsupported.ListLegalHolds(ctx); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListLegalHolds() error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_PurgeTodos_KeepsHeldTodos(t *testing.T) {
+	held, free := createTestTodo(), createTestTodo()
+	purger := &MockTodoPurger{Todos: []*domain.Todo{held, free}}
+	holds := &MockLegalHoldStore{}
+	_ = holds.Place(context.Background(), ports.LegalHold{TodoID: held.ID(), Reason: "Case 42"})
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{},
+		WithAuditLog(&MockAuditLog{}),
+		WithPurge(purger, []byte("secret")),
+		WithLegalHolds(holds),
+	)
+
+	status := "cancelled"
+	req := PurgeTodosRequest{Status: &status, Actor: "ops@example.com", Reason: "Retention"}
+
+	preview, err := service.PreviewPurge(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PreviewPurge() unexpected error: %v", err)
+	}
+	if preview.MatchCount != 1 || preview.HeldCount != 1 {
+		t.Errorf("preview = %d matched, %d held, want 1 and 1", preview.MatchCount, preview.HeldCount)
+	}
+
+	req.ConfirmationToken = preview.ConfirmationToken
+	if _, err := service.PurgeTodos(context.Background(), req); err != nil {
+		t.Fatalf("PurgeTodos() unexpected error: %v", err)
+	}
+	if len(purger.Deleted) != 1 || purger.Deleted[0] != free.ID() {
+		t.Errorf("Deleted = %v, want only %v", purger.Deleted, free.ID())
+	}
+}
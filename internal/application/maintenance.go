@@ -0,0 +1,75 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrMaintenanceMode is returned by write operations while maintenance mode is enabled
+var ErrMaintenanceMode = errors.New("service is in maintenance mode")
+
+// DefaultMaintenanceMessage is shown to clients when no message was provided
+const DefaultMaintenanceMessage = "writes are temporarily disabled, please retry later"
+
+// MaintenanceMode is an operator-controlled switch that rejects writes
+// while keeping reads available, e.g. during migrations and backfills
+type MaintenanceMode struct {
+	mu      sync.RWMutex
+	enabled bool
+	message string
+}
+
+// NewMaintenanceMode creates a new MaintenanceMode in the given state
+func NewMaintenanceMode(enabled bool, message string) *MaintenanceMode {
+	m := &MaintenanceMode{}
+	if enabled {
+		m.Enable(message)
+	}
+	return m
+}
+
+// Enable turns maintenance mode on with a message shown to clients
+func (m *MaintenanceMode) Enable(message string) {
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = true
+	m.message = message
+}
+
+// Disable turns maintenance mode off
+func (m *MaintenanceMode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = false
+	m.message = ""
+}
+
+// Status returns whether maintenance mode is enabled and its message
+func (m *MaintenanceMode) Status() (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.enabled, m.message
+}
+
+// CheckWritable returns ErrMaintenanceMode, with the operator message,
+// when writes are currently rejected
+func (m *MaintenanceMode) CheckWritable() error {
+	if m == nil {
+		return nil
+	}
+
+	enabled, message := m.Status()
+	if !enabled {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrMaintenanceMode, message)
+}
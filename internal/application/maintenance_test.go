@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestMaintenanceMode_EnableDisable(t *testing.T) {
+	m := NewMaintenanceMode(false, "")
+
+	if err := m.CheckWritable(); err != nil {
+		t.Errorf("CheckWritable() unexpected error: %v", err)
+	}
+
+	m.Enable("")
+	enabled, message := m.Status()
+	if !enabled || message != DefaultMaintenanceMessage {
+		t.Errorf("Status() = %v, %q, want true, %q", enabled, message, DefaultMaintenanceMessage)
+	}
+
+	if err := m.CheckWritable(); !errors.Is(err, ErrMaintenanceMode) {
+		t.Errorf("CheckWritable() error = %v, want %v", err, ErrMaintenanceMode)
+	}
+
+	m.Disable()
+	if err := m.CheckWritable(); err != nil {
+		t.Errorf("CheckWritable() after Disable unexpected error: %v", err)
+	}
+}
+
+func TestTodoService_MaintenanceMode_RejectsWritesKeepsReads(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	mockDispatcher := &MockEventDispatcher{}
+	maintenance := NewMaintenanceMode(true, "database migration in progress")
+	service := NewTodoApplicationService(mockRepo, mockDispatcher, WithMaintenanceMode(maintenance))
+
+	_, err := service.CreateTodo(context.Background(), CreateTodoRequest{Title: "Test", Priority: "medium"})
+	if !errors.Is(err, ErrMaintenanceMode) {
+		t.Errorf("CreateTodo() error = %v, want %v", err, ErrMaintenanceMode)
+	}
+
+	if err := service.DeleteTodo(context.Background(), testTodo.ID().String()); !errors.Is(err, ErrMaintenanceMode) {
+		t.Errorf("DeleteTodo() error = %v, want %v", err, ErrMaintenanceMode)
+	}
+
+	if _, err := service.GetTodo(context.Background(), testTodo.ID().String()); err != nil {
+		t.Errorf("GetTodo() unexpected error: %v", err)
+	}
+
+	if len(mockDispatcher.DispatchedEvents) != 0 {
+		t.Errorf("Expected no events dispatched, got %d", len(mockDispatcher.DispatchedEvents))
+	}
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithMerger enables MergeTodos
+func WithMerger(merger ports.TodoMerger) Option {
+	return func(s *TodoApplicationService) {
+		s.merger = merger
+	}
+}
+
+// MergeTodos merges a duplicate into a canonical todo: the duplicate's
+// description is appended to the canonical one, and the duplicate is
+// cancelled with a reference to the canonical todo
+// Both todos are saved in a single transaction
+func (s *TodoApplicationService) MergeTodos(
+	ctx context.Context,
+	canonicalID, duplicateID string,
+) (*MergeTodosResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.merger == nil {
+		return nil, ErrNotSupported
+	}
+
+	canonical, err := s.findTodo(ctx, canonicalID)
+	if err != nil {
+		return nil, fmt.Errorf("canonical todo: %w", err)
+	}
+
+	duplicate, err := s.findTodo(ctx, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("duplicate todo: %w", err)
+	}
+
+	if err := s.authorize(ctx, ActionMerge, canonical); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, ActionMerge, duplicate); err != nil {
+		return nil, err
+	}
+
+	if err := duplicate.MergeInto(canonical); err != nil {
+		return nil, fmt.Errorf("merging todos: %w", err)
+	}
+
+	// Persist the merge and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.merger.SaveMerge(ctx, canonical, duplicate); err != nil {
+			return nil, fmt.Errorf("saving merge: %w", err)
+		}
+		events := make([]domain.DomainEvent, 0, len(canonical.Events())+len(duplicate.Events()))
+		events = append(events, canonical.Events()...)
+		return append(events, duplicate.Events()...), nil
+	}); err != nil {
+		return nil, err
+	}
+	canonical.ClearEvents()
+	duplicate.ClearEvents()
+
+	// Track per-user recent activity
+	s.trackActivity(ctx, canonical.ID(), ports.ActivityModified)
+	s.trackActivity(ctx, duplicate.ID(), ports.ActivityModified)
+
+	return &MergeTodosResponse{
+		Canonical: MapTodoToResponse(canonical),
+		Duplicate: MapTodoToResponse(duplicate),
+	}, nil
+}
+
+// findTodo resolves id, which may be a short code, and loads its todo,
+// raising an alert when it is a canary
+func (s *TodoApplicationService) findTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid todo ID: %w", err)
+	}
+
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoMerger records the merges it saves
+type MockTodoMerger struct {
+	SaveMergeFunc func(ctx context.Context, canonical, duplicate *domain.Todo) error
+	Saved         int
+}
+
+func (m *MockTodoMerger) SaveMerge(ctx context.Context, canonical, duplicate *domain.Todo) error {
+	m.Saved++
+	if m.SaveMergeFunc != nil {
+		return m.SaveMergeFunc(ctx, canonical, duplicate)
+	}
+	return nil
+}
+
+func newMergeTestRepository(todos ...*domain.Todo) *MockTodoRepository {
+	return &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			for _, todo := range todos {
+				if todo.ID() == id {
+					return todo, nil
+				}
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+}
+
+func TestTodoService_MergeTodos_Success(t *testing.T) {
+	canonical, duplicate := createTestTodo(), createTestTodo()
+	merger := &MockTodoMerger{}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(newMergeTestRepository(canonical, duplicate), dispatcher, WithMerger(merger))
+
+	result, err := service.MergeTodos(context.Background(), canonical.ID().String(), duplicate.ID().String())
+
+	if err != nil {
+		t.Fatalf("MergeTodos() unexpected error: %v", err)
+	}
+
+	if result.Duplicate.Status != "cancelled" || result.Duplicate.MergedInto != canonical.ID().String() {
+		t.Errorf("Duplicate = %+v, want cancelled and merged into %v", result.Duplicate, canonical.ID())
+	}
+
+	if merger.Saved != 1 {
+		t.Errorf("SaveMerge() called %d times, want 1", merger.Saved)
+	}
+
+	var merged bool
+	for _, event := range dispatcher.DispatchedEvents {
+		merged = merged || event.EventType() == "TodoMerged"
+	}
+	if !merged {
+		t.Errorf("Dispatched events = %v, want a TodoMerged event", dispatcher.DispatchedEvents)
+	}
+}
+
+func TestTodoService_MergeTodos_Errors(t *testing.T) {
+	canonical, duplicate := createTestTodo(), createTestTodo()
+	repo := newMergeTestRepository(canonical, duplicate)
+	errSave := errors.New("transaction aborted")
+
+	tests := []struct {
+		name      string
+		service   *TodoApplicationService
+		duplicate string
+		want      error
+	}{
+		{"not configured", NewTodoApplicationService(repo, &MockEventDispatcher{}), duplicate.ID().String(), ErrNotSupported},
+		{"into itself", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMerger(&MockTodoMerger{})), canonical.ID().String(), domain.ErrCannotMergeIntoSelf},
+		{"unknown duplicate", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMerger(&MockTodoMerger{})), domain.NewTodoID().String(), domain.ErrTodoNotFound},
+		{"save fails", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMerger(&MockTodoMerger{
+			SaveMergeFunc: func(ctx context.Context, canonical, duplicate *domain.Todo) error { return errSave },
+		})), duplicate.ID().String(), errSave},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.service.MergeTodos(context.Background(), canonical.ID().String(), tt.duplicate); !errors.Is(err, tt.want) {
+				t.Errorf("MergeTodos() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,522 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxMilestoneNameLength is the longest milestone name, in characters
+const MaxMilestoneNameLength = 200
+
+// ErrMilestoneNotFound is returned when a milestone does not exist or
+// belongs to another user
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+// ErrMilestoneTodoNotFound is returned when detaching a todo from a
+// milestone it is not attached to
+var ErrMilestoneTodoNotFound = errors.New("todo not attached to milestone")
+
+// ErrMilestoneArchived is returned when changing the todos of an archived
+// milestone, or deleting it, before unarchiving it
+var ErrMilestoneArchived = errors.New("milestone is archived")
+
+// ErrTodoFrozen is returned when changing a todo attached to an archived
+// milestone
+var ErrTodoFrozen = errors.New("todo is read-only: its milestone is archived")
+
+// MilestoneCompleted is dispatched once when every todo of a milestone is
+// done, i.e. completed or cancelled with at least one completed
+// AggregateID is the milestone, not a todo
+type MilestoneCompleted struct {
+	MilestoneID string
+	Name        string
+	TargetDate  time.Time
+	occurredAt  time.Time
+}
+
+// EventType returns the event type
+func (e MilestoneCompleted) EventType() string {
+	return "MilestoneCompleted"
+}
+
+// AggregateID returns the ID of the milestone
+func (e MilestoneCompleted) AggregateID() string {
+	return e.MilestoneID
+}
+
+// OccurredAt returns when the last todo of the milestone was done
+func (e MilestoneCompleted) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// WithMilestones enables milestones
+// Their completion is followed by a MilestoneTracker wrapping the
+// dispatcher of the service
+func WithMilestones(store ports.MilestoneStore) Option {
+	return func(s *TodoApplicationService) {
+		s.milestones = store
+	}
+}
+
+// CreateMilestone creates a milestone owned by the caller
+func (s *TodoApplicationService) CreateMilestone(ctx context.Context, req CreateMilestoneRequest) (*MilestoneResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.milestones == nil {
+		return nil, ErrNotSupported
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > MaxMilestoneNameLength {
+		return nil, domain.NewValidationError("name", fmt.Sprintf("must be between 1 and %d characters", MaxMilestoneNameLength))
+	}
+	targetDate, err := time.Parse(calendarDayLayout, req.TargetDate)
+	if err != nil {
+		return nil, domain.NewValidationError("target_date", "must be a day as YYYY-MM-DD")
+	}
+
+	if err := s.authorize(ctx, ActionCreate, nil); err != nil {
+		return nil, err
+	}
+
+	milestone := ports.Milestone{
+		ID:         uuid.New().String(),
+		Name:       name,
+		TargetDate: targetDate,
+		CreatedAt:  time.Now(),
+	}
+	milestone.OwnerID, _ = ports.OwnerFromContext(ctx)
+	if err := s.milestones.Create(ctx, milestone); err != nil {
+		return nil, fmt.Errorf("creating milestone: %w", err)
+	}
+
+	return mapMilestone(milestone), nil
+}
+
+// ListMilestones returns the milestones of the caller, earliest target
+// first
+// archived is one of exclude, the default, only or include, as for todos
+func (s *TodoApplicationService) ListMilestones(ctx context.Context, archived *string) ([]*MilestoneResponse, error) {
+	if s.milestones == nil {
+		return nil, ErrNotSupported
+	}
+
+	var filters ports.Filters
+	if err := applyArchived(&filters, archived); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.milestones.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing milestones: %w", err)
+	}
+
+	responses := []*MilestoneResponse{}
+	for _, milestone := range milestones {
+		if filters.Archived != nil && *filters.Archived != (milestone.ArchivedAt != nil) {
+			continue
+		}
+		responses = append(responses, mapMilestone(milestone))
+	}
+	return responses, nil
+}
+
+// ArchiveMilestone takes a milestone out of default listings and freezes
+// its todos: until it is unarchived, they are read-only and it cannot be
+// deleted nor be given todos
+// Archiving an archived milestone changes nothing
+func (s *TodoApplicationService) ArchiveMilestone(ctx context.Context, id string) (*MilestoneResponse, error) {
+	return s.changeMilestoneArchival(ctx, id, true)
+}
+
+// UnarchiveMilestone brings an archived milestone back into default
+// listings, its todos writable again
+// Unarchiving a milestone that is not archived changes nothing
+func (s *TodoApplicationService) UnarchiveMilestone(ctx context.Context, id string) (*MilestoneResponse, error) {
+	return s.changeMilestoneArchival(ctx, id, false)
+}
+
+// changeMilestoneArchival archives or unarchives the milestone id
+func (s *TodoApplicationService) changeMilestoneArchival(ctx context.Context, id string, archive bool) (*MilestoneResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.milestones == nil {
+		return nil, ErrNotSupported
+	}
+
+	milestone, err := s.findMilestone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, ActionArchive, nil); err != nil {
+		return nil, err
+	}
+
+	if (milestone.ArchivedAt != nil) == archive {
+		return mapMilestone(*milestone), nil
+	}
+	milestone.ArchivedAt = nil
+	if archive {
+		now := time.Now()
+		milestone.ArchivedAt = &now
+	}
+
+	changed, err := s.milestones.SetArchived(ctx, milestone.ID, milestone.ArchivedAt)
+	if err != nil {
+		return nil, fmt.Errorf("archiving milestone: %w", err)
+	}
+	if !changed {
+		return nil, ErrMilestoneNotFound
+	}
+
+	return mapMilestone(*milestone), nil
+}
+
+// DeleteMilestone deletes a milestone, detaching its todos
+func (s *TodoApplicationService) DeleteMilestone(ctx context.Context, id string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.milestones == nil {
+		return ErrNotSupported
+	}
+
+	milestone, err := s.findMilestone(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, ActionDelete, nil); err != nil {
+		return err
+	}
+	if milestone.ArchivedAt != nil {
+		return ErrMilestoneArchived
+	}
+
+	deleted, err := s.milestones.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("deleting milestone: %w", err)
+	}
+	if !deleted {
+		return ErrMilestoneNotFound
+	}
+
+	return nil
+}
+
+// AttachTodo attaches the todo todoID to a milestone, weighted by
+// estimateMinutes in its progress, zero when not estimated
+// A todo belongs to one milestone at most: attaching it to another one
+// moves it. Attaching a todo again updates its estimate
+func (s *TodoApplicationService) AttachTodo(ctx context.Context, milestoneID, todoID string, estimateMinutes int) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.milestones == nil {
+		return ErrNotSupported
+	}
+
+	if estimateMinutes < 0 {
+		return domain.NewValidationError("estimate_minutes", "must not be negative")
+	}
+
+	milestone, todo, err := s.findMilestoneTodo(ctx, milestoneID, todoID)
+	if err != nil {
+		return err
+	}
+	if milestone.ArchivedAt != nil {
+		return ErrMilestoneArchived
+	}
+
+	previous, err := s.milestones.Attach(ctx, milestone.ID, ports.MilestoneTodo{TodoID: todo.ID(), EstimateMinutes: estimateMinutes})
+	if err != nil {
+		return fmt.Errorf("attaching todo: %w", err)
+	}
+
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
+	return refreshMilestones(ctx, s.milestones, s.dispatcher, []string{milestone.ID, previous})
+}
+
+// DetachTodo detaches the todo todoID from a milestone
+func (s *TodoApplicationService) DetachTodo(ctx context.Context, milestoneID, todoID string) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
+	if s.milestones == nil {
+		return ErrNotSupported
+	}
+
+	milestone, todo, err := s.findMilestoneTodo(ctx, milestoneID, todoID)
+	if err != nil {
+		return err
+	}
+
+	detached, err := s.milestones.Detach(ctx, milestone.ID, todo.ID())
+	if err != nil {
+		return fmt.Errorf("detaching todo: %w", err)
+	}
+	if !detached {
+		return ErrMilestoneTodoNotFound
+	}
+
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
+	return refreshMilestones(ctx, s.milestones, s.dispatcher, []string{milestone.ID})
+}
+
+// GetMilestoneProgress returns how far the todos of a milestone are done
+func (s *TodoApplicationService) GetMilestoneProgress(ctx context.Context, id string) (*MilestoneProgress, error) {
+	if s.milestones == nil {
+		return nil, ErrNotSupported
+	}
+
+	milestone, err := s.findMilestone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, ActionRead, nil); err != nil {
+		return nil, err
+	}
+
+	todos, err := s.milestones.Todos(ctx, milestone.ID)
+	if err != nil {
+		return nil, fmt.Errorf("finding milestone todos: %w", err)
+	}
+
+	progress := measureMilestone(todos)
+	progress.Milestone = mapMilestone(*milestone)
+	return progress, nil
+}
+
+// findMilestone returns the milestone id of the caller
+func (s *TodoApplicationService) findMilestone(ctx context.Context, id string) (*ports.Milestone, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrMilestoneNotFound
+	}
+
+	milestone, err := s.milestones.Find(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding milestone: %w", err)
+	}
+	if milestone == nil {
+		return nil, ErrMilestoneNotFound
+	}
+
+	return milestone, nil
+}
+
+// findMilestoneTodo resolves a milestone of the caller and a todo it may
+// update
+func (s *TodoApplicationService) findMilestoneTodo(ctx context.Context, milestoneID, todoID string) (*ports.Milestone, *domain.Todo, error) {
+	milestone, err := s.findMilestone(ctx, milestoneID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	todo, err := s.findTodo(ctx, todoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+		return nil, nil, err
+	}
+
+	return milestone, todo, nil
+}
+
+// measureMilestone returns the progress of the todos of a milestone,
+// without the milestone itself
+func measureMilestone(todos []ports.MilestoneTodo) *MilestoneProgress {
+	progress := &MilestoneProgress{Todos: []MilestoneTodoProgress{}}
+	for _, todo := range todos {
+		progress.Todos = append(progress.Todos, MilestoneTodoProgress{
+			TodoID:          todo.TodoID.String(),
+			Status:          todo.Status.String(),
+			EstimateMinutes: todo.EstimateMinutes,
+		})
+		if todo.Status == domain.StatusCancelled {
+			continue
+		}
+
+		progress.Total++
+		progress.TotalEstimateMinutes += todo.EstimateMinutes
+		if todo.Status == domain.StatusCompleted {
+			progress.Completed++
+			progress.CompletedEstimateMinutes += todo.EstimateMinutes
+		}
+	}
+
+	switch {
+	case progress.TotalEstimateMinutes > 0:
+		progress.Percent = 100 * float64(progress.CompletedEstimateMinutes) / float64(progress.TotalEstimateMinutes)
+	case progress.Total > 0:
+		progress.Percent = 100 * float64(progress.Completed) / float64(progress.Total)
+	}
+
+	return progress
+}
+
+// done reports whether every counted todo of a milestone is completed; a
+// milestone without any is not done
+func (p *MilestoneProgress) done() bool {
+	return p.Total > 0 && p.Completed == p.Total
+}
+
+// mapMilestone converts a stored milestone to a MilestoneResponse DTO
+func mapMilestone(milestone ports.Milestone) *MilestoneResponse {
+	return &MilestoneResponse{
+		ID:          milestone.ID,
+		Name:        milestone.Name,
+		TargetDate:  milestone.TargetDate.Format(calendarDayLayout),
+		CreatedAt:   milestone.CreatedAt,
+		CompletedAt: milestone.CompletedAt,
+		ArchivedAt:  milestone.ArchivedAt,
+	}
+}
+
+// checkNotFrozen returns ErrTodoFrozen when the todo is attached to an
+// archived milestone, whoever owns the milestone
+func (s *TodoApplicationService) checkNotFrozen(ctx context.Context, todoID domain.TodoID) error {
+	if s.milestones == nil {
+		return nil
+	}
+
+	frozen, err := s.milestones.Frozen(ports.ContextWithSystemAccess(ctx), []domain.TodoID{todoID})
+	if err != nil {
+		return fmt.Errorf("checking milestone archival: %w", err)
+	}
+	if frozen[todoID] {
+		return ErrTodoFrozen
+	}
+
+	return nil
+}
+
+// refreshMilestones marks the milestones ids completed once all their todos
+// are done, dispatching a MilestoneCompleted event to dispatcher, and marks
+// those with an open todo again as not completed
+// Empty IDs are skipped. The store reports a single completion of a
+// milestone, so concurrent refreshes dispatch one event
+func refreshMilestones(ctx context.Context, store ports.MilestoneStore, dispatcher ports.EventDispatcher, ids []string) error {
+	// The milestones of the todos of any owner are refreshed
+	unscoped := ports.ContextWithSystemAccess(ctx)
+	now := time.Now()
+
+	var events []domain.DomainEvent
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		todos, err := store.Todos(unscoped, id)
+		if err != nil {
+			return fmt.Errorf("finding milestone todos: %w", err)
+		}
+		if !measureMilestone(todos).done() {
+			if _, err := store.SetCompleted(unscoped, id, nil); err != nil {
+				return fmt.Errorf("reopening milestone: %w", err)
+			}
+			continue
+		}
+
+		completed, err := store.SetCompleted(unscoped, id, &now)
+		if err != nil {
+			return fmt.Errorf("completing milestone: %w", err)
+		}
+		if !completed {
+			continue
+		}
+		milestone, err := store.Find(unscoped, id)
+		if err != nil {
+			return fmt.Errorf("finding milestone: %w", err)
+		}
+		// Deleted meanwhile
+		if milestone == nil {
+			continue
+		}
+		events = append(events, MilestoneCompleted{
+			MilestoneID: milestone.ID,
+			Name:        milestone.Name,
+			TargetDate:  milestone.TargetDate,
+			occurredAt:  now,
+		})
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := dispatcher.Dispatch(ctx, events); err != nil {
+		return fmt.Errorf("dispatching events: %w", err)
+	}
+	return nil
+}
+
+// MilestoneTracker refreshes the milestones of the todos changed by the
+// domain events it passes to the next dispatcher, dispatching a
+// MilestoneCompleted event when the last todo of a milestone is done
+// It is a dispatcher rather than a subscriber so that milestones follow
+// every change of status, by the service or the operators, without a
+// broker
+type MilestoneTracker struct {
+	next  ports.EventDispatcher
+	store ports.MilestoneStore
+}
+
+// NewMilestoneTracker creates a MilestoneTracker dispatching to next
+func NewMilestoneTracker(next ports.EventDispatcher, store ports.MilestoneStore) *MilestoneTracker {
+	return &MilestoneTracker{next: next, store: store}
+}
+
+// Dispatch dispatches events to the next dispatcher, then refreshes the
+// milestones of their todos
+// The changes are already saved, so milestones are refreshed even when the
+// events could not be dispatched; the request fails either way. A deleted
+// todo is already detached: its milestone is refreshed by the next change
+// of another of its todos
+func (t *MilestoneTracker) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	dispatchErr := t.next.Dispatch(ctx, events)
+
+	var todoIDs []domain.TodoID
+	for _, event := range events {
+		// Neither reminders nor application events change a status
+		switch event.(type) {
+		case domain.TodoDueSoon, domain.TodoOverdue, WeekPlanned, SecurityAnomalyDetected, MilestoneCompleted:
+			continue
+		}
+		if id, err := domain.ParseTodoID(event.AggregateID()); err == nil {
+			todoIDs = append(todoIDs, id)
+		}
+	}
+	if len(todoIDs) == 0 {
+		return dispatchErr
+	}
+
+	var refreshErr error
+	milestoneIDs, err := t.store.Containing(ports.ContextWithSystemAccess(ctx), todoIDs)
+	if err != nil {
+		refreshErr = fmt.Errorf("finding milestones of todos: %w", err)
+	} else {
+		refreshErr = refreshMilestones(ctx, t.store, t.next, milestoneIDs)
+	}
+
+	return errors.Join(dispatchErr, refreshErr)
+}
@@ -0,0 +1,403 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockMilestoneStore keeps milestones in memory; the status of their todos
+// is read from Statuses
+type MockMilestoneStore struct {
+	Milestones map[string]*ports.Milestone
+	Attached   map[domain.TodoID]string
+	Estimates  map[domain.TodoID]int
+	Statuses   map[domain.TodoID]domain.TaskStatus
+}
+
+func NewMockMilestoneStore() *MockMilestoneStore {
+	return &MockMilestoneStore{
+		Milestones: map[string]*ports.Milestone{},
+		Attached:   map[domain.TodoID]string{},
+		Estimates:  map[domain.TodoID]int{},
+		Statuses:   map[domain.TodoID]domain.TaskStatus{},
+	}
+}
+
+func (m *MockMilestoneStore) Create(ctx context.Context, milestone ports.Milestone) error {
+	m.Milestones[milestone.ID] = &milestone
+	return nil
+}
+
+func (m *MockMilestoneStore) Find(ctx context.Context, id string) (*ports.Milestone, error) {
+	milestone, ok := m.Milestones[id]
+	if ownerID, scoped := ports.OwnerFromContext(ctx); !ok || scoped && milestone.OwnerID != ownerID {
+		return nil, nil
+	}
+	found := *milestone
+	return &found, nil
+}
+
+func (m *MockMilestoneStore) List(ctx context.Context) ([]ports.Milestone, error) {
+	var milestones []ports.Milestone
+	for id := range m.Milestones {
+		if milestone, _ := m.Find(ctx, id); milestone != nil {
+			milestones = append(milestones, *milestone)
+		}
+	}
+	slices.SortFunc(milestones, func(a, b ports.Milestone) int { return a.TargetDate.Compare(b.TargetDate) })
+	return milestones, nil
+}
+
+func (m *MockMilestoneStore) Delete(ctx context.Context, id string) (bool, error) {
+	if milestone, _ := m.Find(ctx, id); milestone == nil {
+		return false, nil
+	}
+	delete(m.Milestones, id)
+	return true, nil
+}
+
+func (m *MockMilestoneStore) Attach(ctx context.Context, milestoneID string, todo ports.MilestoneTodo) (string, error) {
+	previous := m.Attached[todo.TodoID]
+	m.Attached[todo.TodoID] = milestoneID
+	m.Estimates[todo.TodoID] = todo.EstimateMinutes
+	if previous == milestoneID {
+		previous = ""
+	}
+	return previous, nil
+}
+
+func (m *MockMilestoneStore) Detach(ctx context.Context, milestoneID string, todoID domain.TodoID) (bool, error) {
+	if m.Attached[todoID] != milestoneID {
+		return false, nil
+	}
+	delete(m.Attached, todoID)
+	return true, nil
+}
+
+func (m *MockMilestoneStore) Todos(ctx context.Context, milestoneID string) ([]ports.MilestoneTodo, error) {
+	var todos []ports.MilestoneTodo
+	for todoID, id := range m.Attached {
+		if id == milestoneID {
+			todos = append(todos, ports.MilestoneTodo{TodoID: todoID, Status: m.Statuses[todoID], EstimateMinutes: m.Estimates[todoID]})
+		}
+	}
+	slices.SortFunc(todos, func(a, b ports.MilestoneTodo) int { return a.EstimateMinutes - b.EstimateMinutes })
+	return todos, nil
+}
+
+func (m *MockMilestoneStore) Containing(ctx context.Context, ids []domain.TodoID) ([]string, error) {
+	var milestoneIDs []string
+	for _, id := range ids {
+		if milestoneID, ok := m.Attached[id]; ok && !slices.Contains(milestoneIDs, milestoneID) {
+			milestoneIDs = append(milestoneIDs, milestoneID)
+		}
+	}
+	return milestoneIDs, nil
+}
+
+func (m *MockMilestoneStore) SetCompleted(ctx context.Context, id string, at *time.Time) (bool, error) {
+	milestone, ok := m.Milestones[id]
+	if !ok || (milestone.CompletedAt == nil) == (at == nil) {
+		return false, nil
+	}
+	milestone.CompletedAt = at
+	return true, nil
+}
+
+func (m *MockMilestoneStore) SetArchived(ctx context.Context, id string, at *time.Time) (bool, error) {
+	if milestone, _ := m.Find(ctx, id); milestone == nil {
+		return false, nil
+	}
+	m.Milestones[id].ArchivedAt = at
+	return true, nil
+}
+
+func (m *MockMilestoneStore) Frozen(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error) {
+	frozen := map[domain.TodoID]bool{}
+	for _, id := range ids {
+		if milestone, ok := m.Milestones[m.Attached[id]]; ok && milestone.ArchivedAt != nil {
+			frozen[id] = true
+		}
+	}
+	return frozen, nil
+}
+
+func (m *MockMilestoneStore) FrozenTodos(ctx context.Context) ([]domain.TodoID, error) {
+	var frozen []domain.TodoID
+	for id, milestoneID := range m.Attached {
+		if milestone, ok := m.Milestones[milestoneID]; ok && milestone.ArchivedAt != nil {
+			frozen = append(frozen, id)
+		}
+	}
+	return frozen, nil
+}
+
+// newMilestoneService returns a service finding todos, whose dispatcher is
+// a MilestoneTracker in front of dispatcher
+func newMilestoneService(store *MockMilestoneStore, dispatcher *MockEventDispatcher, todos ...*domain.Todo) *TodoApplicationService {
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			for _, todo := range todos {
+				if todo.ID() == id {
+					return todo, nil
+				}
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	for _, todo := range todos {
+		store.Statuses[todo.ID()] = todo.Status()
+	}
+	return NewTodoApplicationService(repo, NewMilestoneTracker(dispatcher, store), WithMilestones(store))
+}
+
+func TestTodoService_MilestoneProgress(t *testing.T) {
+	design, build, dropped := createTestTodo(), createTestTodo(), createTestTodo()
+	store := NewMockMilestoneStore()
+	dispatcher := &MockEventDispatcher{}
+	service := newMilestoneService(store, dispatcher, design, build, dropped)
+	ctx := context.Background()
+
+	milestone, err := service.CreateMilestone(ctx, CreateMilestoneRequest{Name: " Beta ", TargetDate: "2026-11-30"})
+	if err != nil {
+		t.Fatalf("CreateMilestone() unexpected error: %v", err)
+	}
+	if milestone.Name != "Beta" || milestone.TargetDate != "2026-11-30" {
+		t.Errorf("CreateMilestone() = %+v, want Beta on 2026-11-30", milestone)
+	}
+
+	for todo, estimate := range map[*domain.Todo]int{design: 90, build: 30, dropped: 600} {
+		if err := service.AttachTodo(ctx, milestone.ID, todo.ID().String(), estimate); err != nil {
+			t.Fatalf("AttachTodo() unexpected error: %v", err)
+		}
+	}
+	store.Statuses[design.ID()] = domain.StatusCompleted
+	store.Statuses[dropped.ID()] = domain.StatusCancelled
+
+	progress, err := service.GetMilestoneProgress(ctx, milestone.ID)
+	if err != nil {
+		t.Fatalf("GetMilestoneProgress() unexpected error: %v", err)
+	}
+	// The cancelled todo is not counted
+	if progress.Total != 2 || progress.Completed != 1 || progress.TotalEstimateMinutes != 120 || progress.Percent != 75 {
+		t.Errorf("progress = %+v, want 1 of 2 done, 75%% by estimate", progress)
+	}
+	if len(progress.Todos) != 3 || progress.Milestone.ID != milestone.ID {
+		t.Errorf("progress = %+v, want the 3 todos of the milestone", progress)
+	}
+
+	// Without estimates, progress is counted by todo
+	store.Estimates = map[domain.TodoID]int{}
+	progress, _ = service.GetMilestoneProgress(ctx, milestone.ID)
+	if progress.Percent != 50 {
+		t.Errorf("Percent = %v without estimates, want 50", progress.Percent)
+	}
+}
+
+func TestMilestoneTracker_Dispatch(t *testing.T) {
+	design, build := createTestTodo(), createTestTodo()
+	store := NewMockMilestoneStore()
+	dispatcher := &MockEventDispatcher{}
+	service := newMilestoneService(store, dispatcher, design, build)
+	ctx := context.Background()
+
+	milestone, _ := service.CreateMilestone(ctx, CreateMilestoneRequest{Name: "Beta", TargetDate: "2026-11-30"})
+	for _, todo := range []*domain.Todo{design, build} {
+		if err := service.AttachTodo(ctx, milestone.ID, todo.ID().String(), 0); err != nil {
+			t.Fatalf("AttachTodo() unexpected error: %v", err)
+		}
+	}
+	tracker := NewMilestoneTracker(dispatcher, store)
+	complete := func(todo *domain.Todo) {
+		store.Statuses[todo.ID()] = domain.StatusCompleted
+		if err := tracker.Dispatch(ctx, []domain.DomainEvent{domain.NewTodoCompletedEvent(todo.ID(), time.Now())}); err != nil {
+			t.Fatalf("Dispatch() unexpected error: %v", err)
+		}
+	}
+	completedEvents := func() []MilestoneCompleted {
+		var events []MilestoneCompleted
+		for _, event := range dispatcher.DispatchedEvents {
+			if completed, ok := event.(MilestoneCompleted); ok {
+				events = append(events, completed)
+			}
+		}
+		return events
+	}
+
+	complete(design)
+	if events := completedEvents(); len(events) != 0 {
+		t.Fatalf("dispatched %+v with a todo open, want nothing", events)
+	}
+
+	complete(build)
+	complete(build)
+	events := completedEvents()
+	if len(events) != 1 || events[0].AggregateID() != milestone.ID || events[0].Name != "Beta" {
+		t.Fatalf("dispatched %+v, want one MilestoneCompleted for Beta", events)
+	}
+	if store.Milestones[milestone.ID].CompletedAt == nil {
+		t.Error("CompletedAt not set on the completed milestone")
+	}
+
+	// Attaching an open todo reopens the milestone
+	extra := createTestTodo()
+	service = newMilestoneService(store, dispatcher, extra)
+	if err := service.AttachTodo(ctx, milestone.ID, extra.ID().String(), 0); err != nil {
+		t.Fatalf("AttachTodo() unexpected error: %v", err)
+	}
+	if store.Milestones[milestone.ID].CompletedAt != nil {
+		t.Error("CompletedAt still set with an open todo attached")
+	}
+	if len(completedEvents()) != 1 {
+		t.Errorf("dispatched %d MilestoneCompleted events, want 1", len(completedEvents()))
+	}
+}
+
+func TestTodoService_Milestones_Errors(t *testing.T) {
+	todo := createTestTodo()
+	store := NewMockMilestoneStore()
+	service := newMilestoneService(store, &MockEventDispatcher{}, todo)
+	alice := ContextWithUserID(context.Background(), "alice")
+	milestone, err := service.CreateMilestone(alice, CreateMilestoneRequest{Name: "Beta", TargetDate: "2026-11-30"})
+	if err != nil {
+		t.Fatalf("CreateMilestone() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func(ctx context.Context) error
+		wantErr error
+	}{
+		{name: "empty name", call: func(ctx context.Context) error {
+			_, err := service.CreateMilestone(ctx, CreateMilestoneRequest{Name: " ", TargetDate: "2026-11-30"})
+			return err
+		}},
+		{name: "not a day", call: func(ctx context.Context) error {
+			_, err := service.CreateMilestone(ctx, CreateMilestoneRequest{Name: "Beta", TargetDate: "soon"})
+			return err
+		}},
+		{name: "negative estimate", call: func(ctx context.Context) error {
+			return service.AttachTodo(ctx, milestone.ID, todo.ID().String(), -1)
+		}},
+		{name: "invalid milestone ID", call: func(ctx context.Context) error {
+			_, err := service.GetMilestoneProgress(ctx, "nope")
+			return err
+		}, wantErr: ErrMilestoneNotFound},
+		{name: "milestone of another user", call: func(ctx context.Context) error {
+			return service.DeleteMilestone(ContextWithUserID(ctx, "bob"), milestone.ID)
+		}, wantErr: ErrMilestoneNotFound},
+		{name: "unknown todo", call: func(ctx context.Context) error {
+			return service.AttachTodo(ctx, milestone.ID, domain.NewTodoID().String(), 0)
+		}, wantErr: domain.ErrTodoNotFound},
+		{name: "todo not attached", call: func(ctx context.Context) error {
+			return service.DetachTodo(ctx, milestone.ID, todo.ID().String())
+		}, wantErr: ErrMilestoneTodoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(alice)
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.ListMilestones(context.Background(), nil); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListMilestones() without milestones error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_MilestoneArchival(t *testing.T) {
+	frozen, other := createTestTodo(), createTestTodo()
+	store := NewMockMilestoneStore()
+	service := newMilestoneService(store, &MockEventDispatcher{}, frozen, other)
+	ctx := context.Background()
+
+	beta, err := service.CreateMilestone(ctx, CreateMilestoneRequest{Name: "Beta", TargetDate: "2026-11-30"})
+	if err != nil {
+		t.Fatalf("CreateMilestone() unexpected error: %v", err)
+	}
+	if err := service.AttachTodo(ctx, beta.ID, frozen.ID().String(), 0); err != nil {
+		t.Fatalf("AttachTodo() unexpected error: %v", err)
+	}
+
+	archived, err := service.ArchiveMilestone(ctx, beta.ID)
+	if err != nil || archived.ArchivedAt == nil {
+		t.Fatalf("ArchiveMilestone() = %+v, %v, want archived", archived, err)
+	}
+	// Archiving again keeps the first date
+	if again, err := service.ArchiveMilestone(ctx, beta.ID); err != nil || !again.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Errorf("ArchiveMilestone() again = %+v, %v, want unchanged", again, err)
+	}
+
+	include, only := ArchivedInclude, ArchivedOnly
+	for _, tt := range []struct {
+		archived *string
+		want     int
+	}{{nil, 0}, {&include, 1}, {&only, 1}} {
+		if milestones, err := service.ListMilestones(ctx, tt.archived); err != nil || len(milestones) != tt.want {
+			t.Errorf("ListMilestones(%v) = %d milestones, %v, want %d", tt.archived, len(milestones), err, tt.want)
+		}
+	}
+
+	title := "Renamed"
+	writes := map[string]func() error{
+		"update": func() error {
+			_, err := service.UpdateTodo(ctx, frozen.ID().String(), UpdateTodoRequest{Title: &title})
+			return err
+		},
+		"complete": func() error {
+			_, err := service.CompleteTodo(ctx, frozen.ID().String())
+			return err
+		},
+		"delete": func() error { return service.DeleteTodo(ctx, frozen.ID().String()) },
+		"archive": func() error {
+			_, err := service.ArchiveTodo(ctx, frozen.ID().String())
+			return err
+		},
+		"detach": func() error { return service.DetachTodo(ctx, beta.ID, frozen.ID().String()) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrTodoFrozen) {
+			t.Errorf("%s of a frozen todo error = %v, want %v", name, err, ErrTodoFrozen)
+		}
+	}
+	if _, err := service.GetTodo(ctx, frozen.ID().String()); err != nil {
+		t.Errorf("GetTodo() of a frozen todo unexpected error: %v", err)
+	}
+	if err := service.AttachTodo(ctx, beta.ID, other.ID().String(), 0); !errors.Is(err, ErrMilestoneArchived) {
+		t.Errorf("AttachTodo() to an archived milestone error = %v, want %v", err, ErrMilestoneArchived)
+	}
+	if err := service.DeleteMilestone(ctx, beta.ID); !errors.Is(err, ErrMilestoneArchived) {
+		t.Errorf("DeleteMilestone() of an archived milestone error = %v, want %v", err, ErrMilestoneArchived)
+	}
+
+	completer := &MockBulkCompleter{}
+	bulk := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithMilestones(store), WithBulkCompletion(completer))
+	if _, err := bulk.BulkCompleteTodos(ctx, BulkCompleteRequest{Overdue: true}); err != nil {
+		t.Fatalf("BulkCompleteTodos() unexpected error: %v", err)
+	}
+	if excluded := completer.Filters[0].Exclude; len(excluded) != 1 || excluded[0] != frozen.ID() {
+		t.Errorf("CompleteMatching() excluded %v, want the frozen todo", excluded)
+	}
+
+	if unarchived, err := service.UnarchiveMilestone(ctx, beta.ID); err != nil || unarchived.ArchivedAt != nil {
+		t.Fatalf("UnarchiveMilestone() = %+v, %v, want active", unarchived, err)
+	}
+	if _, err := service.UpdateTodo(ctx, frozen.ID().String(), UpdateTodoRequest{Title: &title}); err != nil {
+		t.Errorf("UpdateTodo() after unarchiving unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithMover enables MoveTodo
+func WithMover(mover ports.TodoMover) Option {
+	return func(s *TodoApplicationService) {
+		s.mover = mover
+	}
+}
+
+// MoveTodo transfers a todo of the caller to the user ownerID, whatever its
+// status
+// The todo keeps its ID, so its dependencies, milestone, audit history and
+// completions follow it; the owner of its earlier versions is changed in
+// the same transaction, so the new owner reads them too. The caller no
+// longer sees the todo once moved
+func (s *TodoApplicationService) MoveTodo(ctx context.Context, id, ownerID string) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.mover == nil {
+		return nil, ErrNotSupported
+	}
+
+	todo, err := s.findTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionMove, todo); err != nil {
+		return nil, err
+	}
+
+	if err := todo.MoveTo(strings.TrimSpace(ownerID)); err != nil {
+		return nil, fmt.Errorf("moving todo: %w", err)
+	}
+
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.mover.SaveMove(ctx, todo); err != nil {
+			return nil, fmt.Errorf("saving move: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
+	}
+	todo.ClearEvents()
+
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
+	return MapTodoToResponse(todo), nil
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoMover records the moves it saves
+type MockTodoMover struct {
+	SaveMoveFunc func(ctx context.Context, todo *domain.Todo) error
+	Saved        []string
+}
+
+func (m *MockTodoMover) SaveMove(ctx context.Context, todo *domain.Todo) error {
+	m.Saved = append(m.Saved, todo.OwnerID())
+	if m.SaveMoveFunc != nil {
+		return m.SaveMoveFunc(ctx, todo)
+	}
+	return nil
+}
+
+func TestTodoService_MoveTodo_Success(t *testing.T) {
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	todo.ClearEvents()
+	mover := &MockTodoMover{}
+	dispatcher := &MockEventDispatcher{}
+	service := NewTodoApplicationService(newMergeTestRepository(todo), dispatcher, WithMover(mover))
+
+	result, err := service.MoveTodo(ContextWithUserID(context.Background(), "alice"), todo.ID().String(), " bob ")
+
+	if err != nil {
+		t.Fatalf("MoveTodo() unexpected error: %v", err)
+	}
+	if result.OwnerID != "bob" {
+		t.Errorf("OwnerID = %q, want bob", result.OwnerID)
+	}
+	if len(mover.Saved) != 1 || mover.Saved[0] != "bob" {
+		t.Errorf("SaveMove() saved owners %v, want [bob]", mover.Saved)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 1 {
+		t.Fatalf("dispatched %d events, want 1", len(dispatcher.DispatchedEvents))
+	}
+	moved, ok := dispatcher.DispatchedEvents[0].(domain.TodoMoved)
+	if !ok || moved.FromOwner != "alice" || moved.ToOwner != "bob" {
+		t.Errorf("event = %+v, want TodoMoved from alice to bob", dispatcher.DispatchedEvents[0])
+	}
+}
+
+func TestTodoService_MoveTodo_Errors(t *testing.T) {
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	repo := newMergeTestRepository(todo)
+	errSave := errors.New("transaction aborted")
+
+	tests := []struct {
+		name    string
+		service *TodoApplicationService
+		id      string
+		owner   string
+		want    error
+	}{
+		{"not configured", NewTodoApplicationService(repo, &MockEventDispatcher{}), todo.ID().String(), "bob", ErrNotSupported},
+		{"to the owner", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMover(&MockTodoMover{})), todo.ID().String(), "alice", domain.ErrAlreadyOwned},
+		{"unknown todo", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMover(&MockTodoMover{})), domain.NewTodoID().String(), "bob", domain.ErrTodoNotFound},
+		{"denied", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMover(&MockTodoMover{}), WithAuthorizer(&MockAuthorizer{Allow: false})), todo.ID().String(), "bob", ErrForbidden},
+		{"save fails", NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMover(&MockTodoMover{
+			SaveMoveFunc: func(ctx context.Context, todo *domain.Todo) error { return errSave },
+		})), todo.ID().String(), "bob", errSave},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A refused move leaves the todo with its owner
+			todo.AssignOwner("alice")
+
+			if _, err := tt.service.MoveTodo(context.Background(), tt.id, tt.owner); !errors.Is(err, tt.want) {
+				t.Errorf("MoveTodo() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	var validationErr domain.ValidationError
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithMover(&MockTodoMover{}))
+	if _, err := service.MoveTodo(context.Background(), todo.ID().String(), " "); !errors.As(err, &validationErr) {
+		t.Errorf("MoveTodo() to no owner error = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,362 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Operation errors
+var (
+	// ErrOperationNotFound is returned for unknown operation IDs, or those
+	// of the operations of another user
+	ErrOperationNotFound = errors.New("operation not found")
+	// ErrOperationFinished is returned when cancelling an operation that
+	// already ended
+	ErrOperationFinished = errors.New("operation already finished")
+	// ErrOperationsBusy is returned when starting an operation while
+	// MaxRunningOperations are running on this instance, or while it shuts
+	// down
+	ErrOperationsBusy = errors.New("too many operations running, try again later")
+)
+
+// Operation kinds
+const (
+	OperationImport       = "import"
+	OperationBulkComplete = "bulk_complete"
+)
+
+// Operation limits
+const (
+	// MaxRunningOperations is the number of operations an instance runs at
+	// once
+	MaxRunningOperations = 16
+	// OperationRetention is how long a finished operation is kept
+	OperationRetention = 7 * 24 * time.Hour
+	// operationPruneInterval is the delay between two prunings
+	operationPruneInterval = time.Hour
+)
+
+// Operation list page sizes
+const (
+	DefaultOperationLimit = 20
+	MaxOperationLimit     = 100
+)
+
+// OperationFunc runs a long-running operation, calling progress as done of
+// total items are processed, total being zero while unknown
+// Its result is stored as JSON, even alongside an error, so that a failed
+// or cancelled operation can tell what it did
+type OperationFunc func(ctx context.Context, progress func(done, total int)) (any, error)
+
+// runningOperation is an operation running on this instance
+type runningOperation struct {
+	cancel          context.CancelFunc
+	cancelRequested bool
+}
+
+// OperationService runs long-running operations in the background of the
+// requests starting them, and reports on and cancels them
+// Operations are stored, so that any instance can report on them and flag
+// them for cancellation; the instance running one stops it at its next
+// progress update. An operation dies with its instance: one interrupted by
+// a shutdown fails, and one whose instance crashed stays running
+type OperationService struct {
+	store  ports.OperationStore
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	running map[string]*runningOperation
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewOperationService creates a new OperationService
+func NewOperationService(store ports.OperationStore, logger *slog.Logger) *OperationService {
+	return &OperationService{
+		store:   store,
+		logger:  logger,
+		now:     time.Now,
+		running: map[string]*runningOperation{},
+	}
+}
+
+// Run prunes the operations finished for OperationRetention until ctx is
+// cancelled, then cancels the running operations and waits for them
+func (s *OperationService) Run(ctx context.Context) {
+	prune := time.NewTicker(operationPruneInterval)
+	defer prune.Stop()
+	s.prune(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.stopped = true
+			for _, operation := range s.running {
+				operation.cancel()
+			}
+			s.mu.Unlock()
+			s.wg.Wait()
+			return
+		case <-prune.C:
+			s.prune(ctx)
+		}
+	}
+}
+
+// Start runs an operation of kind for the caller in the background and
+// returns it at once
+// run gets a context carrying the identity of the caller, which outlives
+// the request
+func (s *OperationService) Start(ctx context.Context, kind string, run OperationFunc) (*OperationResponse, error) {
+	id := uuid.NewString()
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	// The slot is taken before the operation is stored, so that concurrent
+	// starts cannot exceed the limit
+	s.mu.Lock()
+	if s.stopped || len(s.running) >= MaxRunningOperations {
+		s.mu.Unlock()
+		cancel()
+		return nil, ErrOperationsBusy
+	}
+	running := &runningOperation{cancel: cancel}
+	s.running[id] = running
+	s.mu.Unlock()
+
+	ownerID, _ := UserIDFromContext(ctx)
+	now := s.now()
+	operation := &ports.Operation{
+		ID:        id,
+		Kind:      kind,
+		OwnerID:   ownerID,
+		Status:    ports.OperationRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.Create(ctx, operation); err != nil {
+		s.release(id)
+		return nil, fmt.Errorf("storing operation: %w", err)
+	}
+
+	// Mapped before execute starts updating it
+	response := mapOperation(operation)
+	s.wg.Add(1)
+	go s.execute(runCtx, running, operation, run)
+
+	return response, nil
+}
+
+// execute runs operation and stores its progress and outcome
+func (s *OperationService) execute(ctx context.Context, running *runningOperation, operation *ports.Operation, run OperationFunc) {
+	defer s.wg.Done()
+	defer s.release(operation.ID)
+
+	// The progress and outcome are saved even once ctx is cancelled
+	storeCtx := context.WithoutCancel(ctx)
+	progress := func(done, total int) {
+		operation.Done, operation.Total = done, total
+		operation.UpdatedAt = s.now()
+		cancelRequested, err := s.store.Update(storeCtx, operation)
+		if err != nil {
+			s.logger.Warn("saving operation progress failed", "operation_id", operation.ID, "error", err)
+			return
+		}
+		if cancelRequested {
+			s.requestCancel(operation.ID)
+		}
+	}
+
+	result, err := run(ctx, progress)
+
+	s.mu.Lock()
+	cancelRequested, stopped := running.cancelRequested, s.stopped
+	s.mu.Unlock()
+
+	switch {
+	case cancelRequested:
+		operation.Status = ports.OperationCancelled
+		operation.CancelRequested = true
+	case err != nil && stopped:
+		operation.Status = ports.OperationFailed
+		operation.Error = "interrupted by a shutdown: " + err.Error()
+	case err != nil:
+		operation.Status = ports.OperationFailed
+		operation.Error = err.Error()
+	default:
+		operation.Status = ports.OperationSucceeded
+	}
+
+	if result != nil {
+		operation.Result, err = json.Marshal(result)
+		if err != nil {
+			operation.Status = ports.OperationFailed
+			operation.Error = fmt.Sprintf("encoding result: %v", err)
+		}
+	}
+
+	now := s.now()
+	operation.UpdatedAt, operation.FinishedAt = now, &now
+	if _, err := s.store.Update(storeCtx, operation); err != nil {
+		s.logger.Error("saving operation outcome failed", "operation_id", operation.ID, "status", operation.Status, "error", err)
+		return
+	}
+	s.logger.Info("operation finished", "operation_id", operation.ID, "kind", operation.Kind, "status", operation.Status)
+}
+
+// GetOperation returns an operation of the caller, with its result
+func (s *OperationService) GetOperation(ctx context.Context, id string) (*OperationResponse, error) {
+	operation, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapOperation(operation), nil
+}
+
+// ListOperations returns a page of the operations of the caller, newest
+// first, without their results
+func (s *OperationService) ListOperations(ctx context.Context, req OperationsRequest) (*OperationPage, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultOperationLimit
+	}
+	if limit > MaxOperationLimit {
+		limit = MaxOperationLimit
+	}
+	if req.Cursor != "" {
+		if _, err := uuid.Parse(req.Cursor); err != nil {
+			return nil, domain.NewValidationError("cursor", "is invalid")
+		}
+	}
+
+	ownerID, _ := UserIDFromContext(ctx)
+	operations, err := s.store.List(ctx, ports.OperationQuery{OwnerID: ownerID, Before: req.Cursor, Limit: limit + 1})
+	if err != nil {
+		return nil, fmt.Errorf("listing operations: %w", err)
+	}
+
+	page := &OperationPage{Operations: []*OperationResponse{}}
+	if len(operations) > limit {
+		operations = operations[:limit]
+		page.NextCursor = operations[limit-1].ID
+	}
+	for _, operation := range operations {
+		page.Operations = append(page.Operations, mapOperation(operation))
+	}
+	return page, nil
+}
+
+// CancelOperation asks a running operation of the caller to stop, and
+// returns it
+// Cancellation is asynchronous: the operation is still running until the
+// instance running it notices, then ends as cancelled
+func (s *OperationService) CancelOperation(ctx context.Context, id string) (*OperationResponse, error) {
+	operation, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if operation.Status != ports.OperationRunning {
+		return nil, ErrOperationFinished
+	}
+
+	ok, err := s.store.RequestCancel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("cancelling operation: %w", err)
+	}
+	if !ok {
+		return nil, ErrOperationFinished
+	}
+	// Operations running elsewhere stop at their next progress update
+	s.requestCancel(id)
+
+	operation.CancelRequested = true
+	return mapOperation(operation), nil
+}
+
+// find returns an operation of the caller
+func (s *OperationService) find(ctx context.Context, id string) (*ports.Operation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrOperationNotFound
+	}
+
+	operation, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding operation: %w", err)
+	}
+	ownerID, _ := UserIDFromContext(ctx)
+	if operation == nil || operation.OwnerID != ownerID {
+		return nil, ErrOperationNotFound
+	}
+	return operation, nil
+}
+
+// requestCancel cancels the context of an operation running on this
+// instance, if it is
+func (s *OperationService) requestCancel(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if running, ok := s.running[id]; ok {
+		running.cancelRequested = true
+		running.cancel()
+	}
+}
+
+// release frees the slot of an operation that ended
+func (s *OperationService) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if running, ok := s.running[id]; ok {
+		running.cancel()
+		delete(s.running, id)
+	}
+}
+
+// prune deletes the operations finished for OperationRetention
+func (s *OperationService) prune(ctx context.Context) {
+	pruned, err := s.store.DeleteFinishedBefore(ctx, s.now().Add(-OperationRetention))
+	if err != nil {
+		s.logger.Error("pruning operations failed", "error", err)
+		return
+	}
+	if pruned > 0 {
+		s.logger.Info("pruned finished operations", "count", pruned)
+	}
+}
+
+// mapOperation converts a stored operation to its response
+func mapOperation(operation *ports.Operation) *OperationResponse {
+	response := &OperationResponse{
+		ID:              operation.ID,
+		Kind:            operation.Kind,
+		Status:          operation.Status,
+		Done:            operation.Done,
+		Total:           operation.Total,
+		Result:          operation.Result,
+		Error:           operation.Error,
+		CancelRequested: operation.CancelRequested,
+		CreatedAt:       operation.CreatedAt,
+		UpdatedAt:       operation.UpdatedAt,
+		FinishedAt:      operation.FinishedAt,
+	}
+
+	switch {
+	case operation.Total > 0:
+		progress := min(operation.Done*100/operation.Total, 100)
+		response.Progress = &progress
+	case operation.Status == ports.OperationSucceeded:
+		progress := 100
+		response.Progress = &progress
+	}
+	return response
+}
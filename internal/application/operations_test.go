@@ -0,0 +1,374 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockOperationStore keeps operations in memory, by ID
+type MockOperationStore struct {
+	mu         sync.Mutex
+	Operations map[string]ports.Operation
+	Err        error
+}
+
+func (m *MockOperationStore) Create(ctx context.Context, operation *ports.Operation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Operations == nil {
+		m.Operations = map[string]ports.Operation{}
+	}
+	m.Operations[operation.ID] = *operation
+	return nil
+}
+
+func (m *MockOperationStore) Update(ctx context.Context, operation *ports.Operation) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.Operations[operation.ID]
+	if !ok {
+		return false, errors.New("operation not found")
+	}
+	updated := *operation
+	updated.CancelRequested = stored.CancelRequested
+	m.Operations[operation.ID] = updated
+	return stored.CancelRequested, nil
+}
+
+func (m *MockOperationStore) Get(ctx context.Context, id string) (*ports.Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	operation, ok := m.Operations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &operation, nil
+}
+
+func (m *MockOperationStore) List(ctx context.Context, query ports.OperationQuery) ([]*ports.Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var operations []*ports.Operation
+	for _, operation := range m.Operations {
+		if operation.OwnerID == query.OwnerID {
+			operation.Result = nil
+			operations = append(operations, &operation)
+		}
+	}
+	slices.SortFunc(operations, func(a, b *ports.Operation) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	if query.Before != "" {
+		i := slices.IndexFunc(operations, func(o *ports.Operation) bool { return o.ID == query.Before })
+		operations = operations[i+1:]
+	}
+	return operations[:min(query.Limit, len(operations))], nil
+}
+
+func (m *MockOperationStore) RequestCancel(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	operation, ok := m.Operations[id]
+	if !ok || operation.Status != ports.OperationRunning {
+		return false, nil
+	}
+	operation.CancelRequested = true
+	m.Operations[id] = operation
+	return true, nil
+}
+
+func (m *MockOperationStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := 0
+	for id, operation := range m.Operations {
+		if operation.FinishedAt != nil && operation.FinishedAt.Before(before) {
+			delete(m.Operations, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// get returns an operation as stored
+func (m *MockOperationStore) get(id string) ports.Operation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Operations[id]
+}
+
+// runOperations runs service until the test ends, returning the function
+// stopping it early
+func runOperations(t *testing.T, service *OperationService) context.CancelFunc {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// waitFinished waits for an operation to finish and returns it
+func waitFinished(t *testing.T, store *MockOperationStore, id string) ports.Operation {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if operation := store.get(id); operation.FinishedAt != nil {
+			return operation
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", id)
+	return ports.Operation{}
+}
+
+func newOperationTestService(store *MockOperationStore) *OperationService {
+	return NewOperationService(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestOperationService_Start(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	runOperations(t, service)
+	alice := ContextWithUserID(context.Background(), "alice")
+
+	tests := []struct {
+		name         string
+		run          OperationFunc
+		wantStatus   string
+		wantResult   string
+		wantError    string
+		wantProgress string
+	}{
+		{
+			name: "succeeded",
+			run: func(ctx context.Context, progress func(done, total int)) (any, error) {
+				if userID, _ := UserIDFromContext(ctx); userID != "alice" {
+					t.Errorf("operation run as %q, want alice", userID)
+				}
+				progress(1, 2)
+				progress(2, 2)
+				return map[string]int{"imported": 2}, nil
+			},
+			wantStatus:   ports.OperationSucceeded,
+			wantResult:   `{"imported":2}`,
+			wantProgress: "100",
+		},
+		{
+			name: "failed with a partial result",
+			run: func(ctx context.Context, progress func(done, total int)) (any, error) {
+				progress(1, 0)
+				return map[string]int{"completed": 1}, errors.New("database unavailable")
+			},
+			wantStatus:   ports.OperationFailed,
+			wantResult:   `{"completed":1}`,
+			wantError:    "database unavailable",
+			wantProgress: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, err := service.Start(alice, OperationImport, tt.run)
+			if err != nil {
+				t.Fatalf("Start() unexpected error: %v", err)
+			}
+			if started.Status != ports.OperationRunning || started.Kind != OperationImport {
+				t.Errorf("Start() = %+v, want a running import", started)
+			}
+
+			finished := waitFinished(t, store, started.ID)
+			if finished.Status != tt.wantStatus || string(finished.Result) != tt.wantResult || finished.Error != tt.wantError {
+				t.Errorf("operation = %+v, result %s, want %s with %s and error %q", finished, finished.Result, tt.wantStatus, tt.wantResult, tt.wantError)
+			}
+
+			got, err := service.GetOperation(alice, started.ID)
+			if err != nil {
+				t.Fatalf("GetOperation() unexpected error: %v", err)
+			}
+			progress := "unknown"
+			if got.Progress != nil {
+				progress = strconv.Itoa(*got.Progress)
+			}
+			if progress != tt.wantProgress || string(got.Result) != tt.wantResult {
+				t.Errorf("GetOperation() progress = %s, result %s, want %s and %s", progress, got.Result, tt.wantProgress, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestOperationService_CancelOperation(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	runOperations(t, service)
+	alice := ContextWithUserID(context.Background(), "alice")
+
+	running := make(chan struct{})
+	started, err := service.Start(alice, OperationBulkComplete, func(ctx context.Context, progress func(done, total int)) (any, error) {
+		progress(10, 0)
+		close(running)
+		<-ctx.Done()
+		return map[string]int{"completed": 10}, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+	<-running
+
+	bob := ContextWithUserID(context.Background(), "bob")
+	if _, err := service.CancelOperation(bob, started.ID); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("CancelOperation() by another user error = %v, want ErrOperationNotFound", err)
+	}
+
+	cancelled, err := service.CancelOperation(alice, started.ID)
+	if err != nil {
+		t.Fatalf("CancelOperation() unexpected error: %v", err)
+	}
+	if !cancelled.CancelRequested {
+		t.Errorf("CancelOperation() = %+v, want the cancellation requested", cancelled)
+	}
+
+	finished := waitFinished(t, store, started.ID)
+	if finished.Status != ports.OperationCancelled || finished.Error != "" || string(finished.Result) != `{"completed":10}` {
+		t.Errorf("operation = %+v, result %s, want cancelled with its partial result", finished, finished.Result)
+	}
+
+	if _, err := service.CancelOperation(alice, started.ID); !errors.Is(err, ErrOperationFinished) {
+		t.Errorf("CancelOperation() again error = %v, want ErrOperationFinished", err)
+	}
+}
+
+func TestOperationService_CancelRequestedElsewhere(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	runOperations(t, service)
+
+	proceed := make(chan struct{})
+	started, err := service.Start(context.Background(), OperationImport, func(ctx context.Context, progress func(done, total int)) (any, error) {
+		<-proceed
+		// The flag set by another instance is read at the progress update
+		progress(1, 3)
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+
+	if ok, _ := store.RequestCancel(context.Background(), started.ID); !ok {
+		t.Fatal("RequestCancel() = false, want true")
+	}
+	close(proceed)
+
+	if finished := waitFinished(t, store, started.ID); finished.Status != ports.OperationCancelled {
+		t.Errorf("status = %s, want cancelled", finished.Status)
+	}
+}
+
+func TestOperationService_Shutdown(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	stop := runOperations(t, service)
+
+	running := make(chan struct{})
+	started, err := service.Start(context.Background(), OperationImport, func(ctx context.Context, progress func(done, total int)) (any, error) {
+		close(running)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+	<-running
+	stop()
+
+	finished := store.get(started.ID)
+	if finished.Status != ports.OperationFailed || !strings.HasPrefix(finished.Error, "interrupted by a shutdown") {
+		t.Errorf("operation = %+v, want failed by the shutdown", finished)
+	}
+	if _, err := service.Start(context.Background(), OperationImport, nil); !errors.Is(err, ErrOperationsBusy) {
+		t.Errorf("Start() after shutdown error = %v, want ErrOperationsBusy", err)
+	}
+}
+
+func TestOperationService_Busy(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	runOperations(t, service)
+
+	release := make(chan struct{})
+	defer close(release)
+	block := func(ctx context.Context, progress func(done, total int)) (any, error) {
+		<-release
+		return nil, nil
+	}
+	for range MaxRunningOperations {
+		if _, err := service.Start(context.Background(), OperationImport, block); err != nil {
+			t.Fatalf("Start() unexpected error: %v", err)
+		}
+	}
+
+	if _, err := service.Start(context.Background(), OperationImport, block); !errors.Is(err, ErrOperationsBusy) {
+		t.Errorf("Start() error = %v, want ErrOperationsBusy", err)
+	}
+}
+
+func TestOperationService_ListOperations(t *testing.T) {
+	store := &MockOperationStore{}
+	service := newOperationTestService(store)
+	start := time.Now()
+	for i, owner := range []string{"alice", "alice", "alice", "bob"} {
+		_ = store.Create(context.Background(), &ports.Operation{
+			ID:        uuid.NewString(),
+			Kind:      OperationImport,
+			OwnerID:   owner,
+			Status:    ports.OperationRunning,
+			CreatedAt: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+	alice := ContextWithUserID(context.Background(), "alice")
+
+	page, err := service.ListOperations(alice, OperationsRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListOperations() unexpected error: %v", err)
+	}
+	if len(page.Operations) != 2 || page.NextCursor != page.Operations[1].ID {
+		t.Fatalf("ListOperations() = %+v, want 2 operations and a cursor", page)
+	}
+
+	page, err = service.ListOperations(alice, OperationsRequest{Cursor: page.NextCursor, Limit: 2})
+	if err != nil || len(page.Operations) != 1 || page.NextCursor != "" {
+		t.Errorf("ListOperations() last page = %+v, %v, want 1 operation", page, err)
+	}
+
+	if _, err := service.ListOperations(alice, OperationsRequest{Cursor: "next"}); err == nil {
+		t.Error("ListOperations() with an invalid cursor error = nil")
+	}
+	if _, err := service.GetOperation(alice, "42"); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("GetOperation() of an invalid ID error = %v, want ErrOperationNotFound", err)
+	}
+}
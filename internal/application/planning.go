@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxPlannedTodos is the largest number of todos a PlanWeek call can schedule
+const MaxPlannedTodos = 200
+
+// Reasons todos are skipped by PlanWeek
+const (
+	PlanSkipCompleted = "completed"
+	PlanSkipCancelled = "cancelled"
+)
+
+// WeekPlanned is dispatched once by PlanWeek for all the todos it
+// scheduled, instead of a TodoUpdated event per todo
+// AggregateID is empty: the plan spans many todos
+type WeekPlanned struct {
+	// DueDates maps the IDs of the planned todos to their new due date
+	DueDates   map[string]time.Time
+	occurredAt time.Time
+}
+
+// EventType returns the event type
+func (e WeekPlanned) EventType() string {
+	return "WeekPlanned"
+}
+
+// AggregateID returns an empty ID, the plan spanning many todos
+func (e WeekPlanned) AggregateID() string {
+	return ""
+}
+
+// OccurredAt returns when the plan was saved
+func (e WeekPlanned) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// TodoIDs returns the IDs of the planned todos, sorted
+func (e WeekPlanned) TodoIDs() []string {
+	ids := make([]string, 0, len(e.DueDates))
+	for id := range e.DueDates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// PlanWeek sets the due dates of many todos at once, each to the end of its
+// planned day
+// Days must be working days of the business calendar, not in the past, and all within 7 days of each
+// other; completed and cancelled todos are skipped. The due dates are saved
+// in a single transaction, all or none, and announced by one WeekPlanned
+// event
+func (s *TodoApplicationService) PlanWeek(ctx context.Context, req PlanWeekRequest) (*PlanWeekResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.batchUpdater == nil {
+		return nil, ErrNotSupported
+	}
+
+	dueDates, err := s.planDueDates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(dueDates))
+	for id := range dueDates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	response := &PlanWeekResponse{Planned: []*TodoResponse{}, Skipped: []PlanSkip{}}
+	var planned []*domain.Todo
+	seen := make(map[domain.TodoID]bool, len(ids))
+	for _, id := range ids {
+		todo, err := s.findTodo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// An ID and a short code may name the same todo
+		if seen[todo.ID()] {
+			return nil, domain.NewValidationError("days", fmt.Sprintf("todo %s is planned twice", todo.ID()))
+		}
+		seen[todo.ID()] = true
+
+		if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case todo.Status().IsCompleted():
+			response.Skipped = append(response.Skipped, PlanSkip{TodoID: todo.ID().String(), Reason: PlanSkipCompleted})
+			continue
+		case todo.Status().IsCancelled():
+			response.Skipped = append(response.Skipped, PlanSkip{TodoID: todo.ID().String(), Reason: PlanSkipCancelled})
+			continue
+		}
+
+		dueDate := dueDates[id]
+		if err := todo.UpdateDueDate(&dueDate); err != nil {
+			return nil, fmt.Errorf("planning %s: %w", todo.ID(), err)
+		}
+		planned = append(planned, todo)
+	}
+
+	if len(planned) == 0 {
+		return response, nil
+	}
+
+	// One event for the whole plan replaces the TodoUpdated of each todo
+	event := WeekPlanned{DueDates: make(map[string]time.Time, len(planned)), occurredAt: time.Now()}
+	for _, todo := range planned {
+		todo.ClearEvents()
+		event.DueDates[todo.ID().String()] = todo.DueDate().Time()
+		response.Planned = append(response.Planned, MapTodoToResponse(todo))
+	}
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.batchUpdater.UpdateMany(ctx, planned); err != nil {
+			return nil, fmt.Errorf("saving plan: %w", err)
+		}
+		return []domain.DomainEvent{event}, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, todo := range planned {
+		s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+	}
+
+	return response, nil
+}
+
+// planDueDates validates the days of req and returns the due date of each
+// todo: the end of its day
+func (s *TodoApplicationService) planDueDates(ctx context.Context, req PlanWeekRequest) (map[string]domain.DueDate, error) {
+	if len(req.Days) == 0 {
+		return nil, domain.NewValidationError("days", "at least one todo is required")
+	}
+	if len(req.Days) > MaxPlannedTodos {
+		return nil, domain.NewValidationError("days", fmt.Sprintf("at most %d todos can be planned at once", MaxPlannedTodos))
+	}
+
+	calendar, err := s.businessCalendar().load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDates := make(map[string]domain.DueDate, len(req.Days))
+	var first, last time.Time
+	for id, value := range req.Days {
+		field := fmt.Sprintf("days[%s]", id)
+		day, err := time.ParseInLocation(calendarDayLayout, value, calendar.options.Location)
+		if err != nil {
+			return nil, domain.NewValidationError(field, "must be a day as YYYY-MM-DD")
+		}
+		if name, ok := calendar.holiday(day); ok {
+			return nil, domain.NewValidationError(field, value+" is a holiday: "+name)
+		}
+		if !calendar.isWorkingDay(day) {
+			return nil, domain.NewValidationError(field, day.Weekday().String()+" is not a working day")
+		}
+
+		dueDate, err := domain.NewDueDate(day.AddDate(0, 0, 1).Add(-time.Second))
+		if err != nil {
+			return nil, domain.NewValidationError(field, "must not be in the past")
+		}
+		dueDates[id] = dueDate
+
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+
+	if !last.Before(first.AddDate(0, 0, 7)) {
+		return nil, domain.NewValidationError("days", "must all fall within 7 days")
+	}
+
+	return dueDates, nil
+}
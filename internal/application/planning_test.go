@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// nextWorkingDay returns the first Monday to Friday after today, in UTC,
+// plus offset days
+func nextWorkingDay(offset int) time.Time {
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	for day.Weekday() != time.Monday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.AddDate(0, 0, offset)
+}
+
+func newPlanningService(updater *MockBatchUpdater, dispatcher ports.EventDispatcher, todos ...*domain.Todo) *TodoApplicationService {
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			for _, todo := range todos {
+				if todo.ID() == id {
+					return todo, nil
+				}
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	return NewTodoApplicationService(repo, dispatcher, WithBatchUpdates(updater))
+}
+
+func TestTodoService_PlanWeek(t *testing.T) {
+	monday, tuesday := nextWorkingDay(0), nextWorkingDay(1)
+	first, second, completed := createTestTodo(), createTestTodo(), createTestTodo()
+	_ = completed.Complete()
+	for _, todo := range []*domain.Todo{first, second, completed} {
+		todo.ClearEvents()
+	}
+	updater := &MockBatchUpdater{}
+	dispatcher := &MockEventDispatcher{}
+	service := newPlanningService(updater, dispatcher, first, second, completed)
+
+	resp, err := service.PlanWeek(context.Background(), PlanWeekRequest{Days: map[string]string{
+		first.ID().String():     monday.Format("2006-01-02"),
+		second.ID().String():    tuesday.Format("2006-01-02"),
+		completed.ID().String(): tuesday.Format("2006-01-02"),
+	}})
+	if err != nil {
+		t.Fatalf("PlanWeek() unexpected error: %v", err)
+	}
+
+	endOfMonday := monday.AddDate(0, 0, 1).Add(-time.Second)
+	if first.DueDate() == nil || !first.DueDate().Time().Equal(endOfMonday) {
+		t.Errorf("first due date = %v, want %v", first.DueDate(), endOfMonday)
+	}
+	if len(resp.Planned) != 2 || len(resp.Skipped) != 1 || resp.Skipped[0].TodoID != completed.ID().String() || resp.Skipped[0].Reason != PlanSkipCompleted {
+		t.Errorf("response = %+v, want 2 planned and the completed todo skipped", resp)
+	}
+	if len(updater.Batches) != 1 || len(updater.Batches[0]) != 2 {
+		t.Errorf("batches = %v, want both todos saved at once", updater.Batches)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 1 {
+		t.Fatalf("dispatched %d events, want one plan", len(dispatcher.DispatchedEvents))
+	}
+	plan, ok := dispatcher.DispatchedEvents[0].(WeekPlanned)
+	if !ok || len(plan.DueDates) != 2 || !plan.DueDates[second.ID().String()].Equal(tuesday.AddDate(0, 0, 1).Add(-time.Second)) {
+		t.Errorf("event = %+v, want the plan of both todos", dispatcher.DispatchedEvents[0])
+	}
+	if len(first.Events()) != 0 {
+		t.Errorf("first todo events = %v, want none left", first.Events())
+	}
+}
+
+func TestTodoService_PlanWeek_WorkingDays(t *testing.T) {
+	todo := createTestTodo()
+	sunday := nextWorkingDay(-1)
+	service := newPlanningService(&MockBatchUpdater{}, &MockEventDispatcher{}, todo)
+	req := PlanWeekRequest{Days: map[string]string{todo.ID().String(): sunday.Format("2006-01-02")}}
+
+	var validationErr domain.ValidationError
+	if _, err := service.PlanWeek(context.Background(), req); !errors.As(err, &validationErr) {
+		t.Errorf("PlanWeek(sunday) error = %v, want a validation error", err)
+	}
+
+	everyDay := []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	holidays := &MockHolidayStore{}
+	WithBusinessCalendar(NewBusinessCalendar(CalendarOptions{WorkingDays: everyDay, Location: time.UTC}, holidays))(service)
+	if _, err := service.PlanWeek(context.Background(), req); err != nil {
+		t.Errorf("PlanWeek(sunday) with every day working error = %v, want nil", err)
+	}
+
+	holidays.Holidays = map[time.Time]string{sunday: "Harvest Festival"}
+	if _, err := service.PlanWeek(context.Background(), req); !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "Harvest Festival") {
+		t.Errorf("PlanWeek(holiday) error = %v, want a validation error naming the holiday", err)
+	}
+}
+
+func TestTodoService_PlanWeek_Errors(t *testing.T) {
+	todo := createTestTodo()
+	monday := nextWorkingDay(0).Format("2006-01-02")
+	saveErr := errors.New("connection lost")
+
+	tests := []struct {
+		name    string
+		days    map[string]string
+		updater *MockBatchUpdater
+		wantErr error
+	}{
+		{name: "no todo", days: nil, updater: &MockBatchUpdater{}},
+		{name: "not a day", days: map[string]string{todo.ID().String(): "next monday"}, updater: &MockBatchUpdater{}},
+		{name: "past day", days: map[string]string{todo.ID().String(): "2020-01-06"}, updater: &MockBatchUpdater{}},
+		{name: "more than a week", days: map[string]string{
+			todo.ID().String():          monday,
+			domain.NewTodoID().String(): nextWorkingDay(7).Format("2006-01-02"),
+		}, updater: &MockBatchUpdater{}},
+		{name: "unknown todo", days: map[string]string{domain.NewTodoID().String(): monday}, updater: &MockBatchUpdater{}, wantErr: domain.ErrTodoNotFound},
+		{name: "save failure", days: map[string]string{todo.ID().String(): monday}, updater: &MockBatchUpdater{Err: saveErr}, wantErr: saveErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := &MockEventDispatcher{}
+			service := newPlanningService(tt.updater, dispatcher, todo)
+
+			_, err := service.PlanWeek(context.Background(), PlanWeekRequest{Days: tt.days})
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("PlanWeek() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("PlanWeek() error = %v, want a validation error", err)
+			}
+			if len(dispatcher.DispatchedEvents) != 0 {
+				t.Errorf("dispatched %d events, want none", len(dispatcher.DispatchedEvents))
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.PlanWeek(context.Background(), PlanWeekRequest{Days: map[string]string{todo.ID().String(): monday}}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("PlanWeek() without batch updates error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoWatch_Next_ExpandsPlans(t *testing.T) {
+	first, second := createTestTodo(), createTestTodo()
+	todos := map[domain.TodoID]*domain.Todo{first.ID(): first, second.ID(): second}
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return todos[id], nil
+		},
+	}
+	subscriber := &MockResumableSubscriber{Positioned: make(chan ports.PositionedEvent, 2)}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithEventSubscriber(subscriber))
+
+	watch, err := service.WatchTodos(context.Background(), WatchFilters{After: "p1"})
+	if err != nil {
+		t.Fatalf("WatchTodos() unexpected error: %v", err)
+	}
+
+	planned := time.Now()
+	plan := WeekPlanned{DueDates: map[string]time.Time{first.ID().String(): planned, second.ID().String(): planned}, occurredAt: planned}
+	subscriber.Positioned <- ports.PositionedEvent{Event: plan, Position: "p2"}
+
+	// Resuming before the last change replays the whole plan
+	for i, wantToken := range []string{"p1", "p2"} {
+		change, err := watch.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %v", err)
+		}
+		if change.Kind != "updated" || change.Todo.ID != plan.TodoIDs()[i] || change.ResumeToken != wantToken || !change.OccurredAt.Equal(planned) {
+			t.Errorf("change %d = %+v, want the update of %s with token %s", i, change, plan.TodoIDs()[i], wantToken)
+		}
+	}
+}
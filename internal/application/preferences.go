@@ -0,0 +1,200 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MaxDefaultPageSize bounds the default page size a client can store
+const MaxDefaultPageSize = 100
+
+// Preferences are the ListTodos defaults of a client, applied when a request
+// omits the matching parameter
+// DefaultSort and DefaultSortOrder take the values of ListFilters.SortBy and
+// SortOrder; a default sort without an order uses the field's natural one
+type Preferences struct {
+	DefaultPageSize  *int
+	DefaultStatus    *string
+	DefaultPriority  *string
+	DefaultSort      *string
+	DefaultSortOrder *string
+	UpdatedAt        *time.Time
+}
+
+// WithClientProfiles enables per-client preferences and applies them to ListTodos
+func WithClientProfiles(store ports.ClientProfileStore) Option {
+	return func(s *TodoApplicationService) {
+		s.profiles = store
+	}
+}
+
+// GetPreferences returns the preferences of the authenticated client
+// A client that never saved any gets empty preferences
+func (s *TodoApplicationService) GetPreferences(ctx context.Context) (*Preferences, error) {
+	clientID, err := s.profileClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.Get(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("finding client profile: %w", err)
+	}
+
+	if profile == nil {
+		return &Preferences{}, nil
+	}
+
+	return mapProfileToPreferences(profile), nil
+}
+
+// UpdatePreferences replaces the preferences of the authenticated client
+func (s *TodoApplicationService) UpdatePreferences(ctx context.Context, req Preferences) (*Preferences, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	clientID, err := s.profileClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := ports.ClientProfile{UpdatedAt: time.Now()}
+
+	if req.DefaultPageSize != nil {
+		if *req.DefaultPageSize < 1 || *req.DefaultPageSize > MaxDefaultPageSize {
+			return nil, domain.NewValidationError("default_page_size", fmt.Sprintf("must be between 1 and %d", MaxDefaultPageSize))
+		}
+		pageSize := *req.DefaultPageSize
+		profile.PageSize = &pageSize
+	}
+
+	if req.DefaultStatus != nil {
+		status, err := domain.NewTaskStatus(*req.DefaultStatus)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default status: %w", err)
+		}
+		profile.Status = &status
+	}
+
+	if req.DefaultPriority != nil {
+		priority, err := domain.NewPriority(*req.DefaultPriority)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default priority: %w", err)
+		}
+		profile.Priority = &priority
+	}
+
+	if req.DefaultSort != nil || req.DefaultSortOrder != nil {
+		var sort ports.Filters
+		if err := applySort(&sort, req.DefaultSort, req.DefaultSortOrder); err != nil {
+			return nil, err
+		}
+		profile.SortBy = &sort.SortBy
+		profile.SortOrder = &sort.SortOrder
+	}
+
+	if err := s.profiles.Save(ctx, clientID, profile); err != nil {
+		return nil, fmt.Errorf("saving client profile: %w", err)
+	}
+
+	return mapProfileToPreferences(&profile), nil
+}
+
+// applyClientProfile fills the filters a ListTodos request omitted from the
+// profile of the authenticated client, if any; sorted reports whether the
+// request chose its ordering
+func (s *TodoApplicationService) applyClientProfile(ctx context.Context, filters *ports.Filters, sorted bool) error {
+	if s.profiles == nil {
+		return nil
+	}
+
+	clientID, ok := clientIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	profile, err := s.profiles.Get(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("finding client profile: %w", err)
+	}
+
+	if profile == nil {
+		return nil
+	}
+
+	if filters.Limit == nil {
+		filters.Limit = profile.PageSize
+	}
+
+	if filters.Status == nil {
+		filters.Status = profile.Status
+	}
+
+	if filters.Priority == nil {
+		filters.Priority = profile.Priority
+	}
+
+	if !sorted && profile.SortBy != nil && profile.SortOrder != nil {
+		filters.SortBy = *profile.SortBy
+		filters.SortOrder = *profile.SortOrder
+	}
+
+	return nil
+}
+
+// profileClientID returns the client whose profile preference operations manage
+func (s *TodoApplicationService) profileClientID(ctx context.Context) (string, error) {
+	if s.profiles == nil {
+		return "", ErrNotSupported
+	}
+
+	clientID, ok := clientIDFromContext(ctx)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	return clientID, nil
+}
+
+// clientIDFromContext returns the client a profile belongs to: the API key
+// the caller authenticated with, else the user
+// Each API key of a user thus has a profile of its own, apart from the one
+// of the user's other clients
+func clientIDFromContext(ctx context.Context) (string, bool) {
+	if keyID, ok := APIKeyIDFromContext(ctx); ok {
+		return "api_key:" + keyID, true
+	}
+	return UserIDFromContext(ctx)
+}
+
+// mapProfileToPreferences converts a stored client profile to Preferences
+func mapProfileToPreferences(profile *ports.ClientProfile) *Preferences {
+	updatedAt := profile.UpdatedAt
+	preferences := &Preferences{
+		DefaultPageSize: profile.PageSize,
+		UpdatedAt:       &updatedAt,
+	}
+
+	if profile.Status != nil {
+		status := profile.Status.String()
+		preferences.DefaultStatus = &status
+	}
+
+	if profile.Priority != nil {
+		priority := profile.Priority.String()
+		preferences.DefaultPriority = &priority
+	}
+
+	if profile.SortBy != nil && profile.SortOrder != nil {
+		sortBy, sortOrder := string(*profile.SortBy), string(*profile.SortOrder)
+		preferences.DefaultSort = &sortBy
+		preferences.DefaultSortOrder = &sortOrder
+	}
+
+	return preferences
+}
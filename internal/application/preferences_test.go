@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockClientProfileStore keeps client profiles in memory
+type MockClientProfileStore struct {
+	Profiles map[string]ports.ClientProfile
+}
+
+func (m *MockClientProfileStore) Get(ctx context.Context, clientID string) (*ports.ClientProfile, error) {
+	profile, ok := m.Profiles[clientID]
+	if !ok {
+		return nil, nil
+	}
+	return &profile, nil
+}
+
+func (m *MockClientProfileStore) Save(ctx context.Context, clientID string, profile ports.ClientProfile) error {
+	if m.Profiles == nil {
+		m.Profiles = map[string]ports.ClientProfile{}
+	}
+	m.Profiles[clientID] = profile
+	return nil
+}
+
+func TestTodoService_UpdatePreferences_ReplacesProfile(t *testing.T) {
+	store := &MockClientProfileStore{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithClientProfiles(store))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	pageSize, status := 20, "in_progress"
+	if _, err := service.UpdatePreferences(ctx, Preferences{DefaultPageSize: &pageSize, DefaultStatus: &status}); err != nil {
+		t.Fatalf("UpdatePreferences() unexpected error: %v", err)
+	}
+
+	priority := "high"
+	if _, err := service.UpdatePreferences(ctx, Preferences{DefaultPriority: &priority}); err != nil {
+		t.Fatalf("UpdatePreferences() unexpected error: %v", err)
+	}
+
+	prefs, err := service.GetPreferences(ctx)
+	if err != nil {
+		t.Fatalf("GetPreferences() unexpected error: %v", err)
+	}
+
+	if prefs.DefaultPageSize != nil || prefs.DefaultStatus != nil {
+		t.Errorf("GetPreferences() = %+v, want earlier defaults cleared", prefs)
+	}
+	if prefs.DefaultPriority == nil || *prefs.DefaultPriority != "high" {
+		t.Errorf("DefaultPriority = %v, want high", prefs.DefaultPriority)
+	}
+}
+
+func TestTodoService_UpdatePreferences_Errors(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithClientProfiles(&MockClientProfileStore{}))
+	alice := ContextWithUserID(context.Background(), "alice")
+
+	if _, err := service.UpdatePreferences(context.Background(), Preferences{}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("UpdatePreferences() anonymous error = %v, want %v", err, ErrUnauthenticated)
+	}
+
+	var validationErr domain.ValidationError
+	pageSize := MaxDefaultPageSize + 1
+	if _, err := service.UpdatePreferences(alice, Preferences{DefaultPageSize: &pageSize}); !errors.As(err, &validationErr) {
+		t.Errorf("UpdatePreferences() page size error = %v, want a validation error", err)
+	}
+
+	status := "someday"
+	if _, err := service.UpdatePreferences(alice, Preferences{DefaultStatus: &status}); !errors.Is(err, domain.ErrInvalidStatus) {
+		t.Errorf("UpdatePreferences() status error = %v, want %v", err, domain.ErrInvalidStatus)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.GetPreferences(alice); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("GetPreferences() without store error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestTodoService_ListTodos_AppliesClientProfile(t *testing.T) {
+	pageSize := 5
+	status := domain.StatusPending
+	store := &MockClientProfileStore{Profiles: map[string]ports.ClientProfile{
+		"alice": {PageSize: &pageSize, Status: &status},
+	}}
+
+	var got ports.Filters
+	mockRepo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			got = filters
+			return nil, nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithClientProfiles(store))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	// Omitted parameters use the profile
+	if _, err := service.ListTodos(ctx, ListFilters{}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got.Limit == nil || *got.Limit != 5 || got.Status == nil || *got.Status != domain.StatusPending {
+		t.Errorf("Filters = %+v, want the profile defaults", got)
+	}
+
+	// Explicit parameters win
+	limit, completed := 50, "completed"
+	if _, err := service.ListTodos(ctx, ListFilters{Limit: &limit, Status: &completed}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if *got.Limit != 50 || *got.Status != domain.StatusCompleted {
+		t.Errorf("Filters = %+v, want the request parameters", got)
+	}
+
+	// Anonymous requests are unaffected
+	if _, err := service.ListTodos(context.Background(), ListFilters{}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got.Limit != nil || got.Status != nil {
+		t.Errorf("Filters = %+v, want no defaults", got)
+	}
+}
+
+func TestTodoService_ListTodos_AppliesProfileSort(t *testing.T) {
+	store := &MockClientProfileStore{}
+	var got ports.Filters
+	mockRepo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			got = filters
+			return nil, nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithClientProfiles(store))
+	ctx := ContextWithUserID(context.Background(), "alice")
+
+	// A default sort without an order takes the field's natural one
+	sort := "due_date"
+	prefs, err := service.UpdatePreferences(ctx, Preferences{DefaultSort: &sort})
+	if err != nil {
+		t.Fatalf("UpdatePreferences() unexpected error: %v", err)
+	}
+	if prefs.DefaultSortOrder == nil || *prefs.DefaultSortOrder != "asc" {
+		t.Errorf("DefaultSortOrder = %v, want asc", prefs.DefaultSortOrder)
+	}
+
+	if _, err := service.ListTodos(ctx, ListFilters{}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got.SortBy != ports.SortByDueDate || got.SortOrder != ports.SortAscending {
+		t.Errorf("sort = %s %s, want the profile's due_date asc", got.SortBy, got.SortOrder)
+	}
+
+	// An explicit ordering wins
+	order := "desc"
+	if _, err := service.ListTodos(ctx, ListFilters{SortOrder: &order}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+	if got.SortBy != ports.SortByCreatedAt || got.SortOrder != ports.SortDescending {
+		t.Errorf("sort = %s %s, want the request's created_at desc", got.SortBy, got.SortOrder)
+	}
+
+	var validationErr domain.ValidationError
+	invalid := "color"
+	if _, err := service.UpdatePreferences(ctx, Preferences{DefaultSort: &invalid}); !errors.As(err, &validationErr) {
+		t.Errorf("UpdatePreferences() sort error = %v, want a validation error", err)
+	}
+}
+
+func TestTodoService_Preferences_KeyedByAPIKey(t *testing.T) {
+	store := &MockClientProfileStore{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithClientProfiles(store))
+	alice := ContextWithUserID(context.Background(), "alice")
+	script := ContextWithAPIKeyID(alice, "key-1")
+
+	pageSize := 10
+	if _, err := service.UpdatePreferences(script, Preferences{DefaultPageSize: &pageSize}); err != nil {
+		t.Fatalf("UpdatePreferences() unexpected error: %v", err)
+	}
+	if _, ok := store.Profiles["api_key:key-1"]; !ok {
+		t.Fatalf("profiles = %v, want one for the API key", store.Profiles)
+	}
+
+	// The user's other clients keep their own profile
+	prefs, err := service.GetPreferences(alice)
+	if err != nil {
+		t.Fatalf("GetPreferences() unexpected error: %v", err)
+	}
+	if prefs.DefaultPageSize != nil {
+		t.Errorf("GetPreferences() = %+v, want the user's profile empty", prefs)
+	}
+}
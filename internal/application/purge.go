@@ -0,0 +1,258 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// AuditActionPurge is the audit log action of PurgeTodos
+const AuditActionPurge = "purge"
+
+// Purge limits
+const (
+	// PurgeConfirmationTTL is how long a purge preview can be confirmed
+	PurgeConfirmationTTL = 10 * time.Minute
+	// MaxPurgeSize is the largest number of todos a single purge can delete
+	MaxPurgeSize = 10000
+)
+
+// ErrPurgeConfirmation is returned by PurgeTodos when the confirmation token
+// is invalid or expired, or the matching todos changed since the preview
+var ErrPurgeConfirmation = errors.New("purge confirmation is invalid, expired or no longer matches; preview again")
+
+// WithPurge enables PreviewPurge and PurgeTodos
+// secret signs confirmation tokens; every instance must share it so a preview
+// can be confirmed on any of them
+func WithPurge(purger ports.TodoPurger, secret []byte) Option {
+	return func(s *TodoApplicationService) {
+		s.purger = purger
+		s.purgeSecret = secret
+	}
+}
+
+// PreviewPurge counts the todos a purge would delete and returns the token
+// that confirms deleting exactly those todos
+func (s *TodoApplicationService) PreviewPurge(ctx context.Context, req PurgeTodosRequest) (*PurgePreview, error) {
+	if s.purger == nil {
+		return nil, ErrNotSupported
+	}
+
+	filter, err := buildPurgeFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, held, err := s.findPurgeable(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(PurgeConfirmationTTL).Truncate(time.Second)
+
+	return &PurgePreview{
+		MatchCount:        len(todos),
+		HeldCount:         held,
+		ConfirmationToken: s.purgeToken(filter, todos, expiresAt),
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// PurgeTodos hard-deletes the todos matching a filter, once confirmed by the
+// token of a PreviewPurge of the same filter
+// The purge is refused if the matching todos changed since the preview; each
+// deletion is recorded in the audit log before anything is deleted
+func (s *TodoApplicationService) PurgeTodos(ctx context.Context, req PurgeTodosRequest) (*PurgeReport, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.purger == nil {
+		return nil, ErrNotSupported
+	}
+
+	if s.auditLog == nil {
+		return nil, ErrAuditLogRequired
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ErrMissingReason
+	}
+
+	filter, err := buildPurgeFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, _, err := s.findPurgeable(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.verifyPurgeToken(req.ConfirmationToken, filter, todos) {
+		return nil, ErrPurgeConfirmation
+	}
+
+	report := &PurgeReport{
+		Actor:      req.Actor,
+		Reason:     req.Reason,
+		PurgedAt:   time.Now(),
+		Tombstones: make([]TodoTombstone, len(todos)),
+	}
+	ids := make([]domain.TodoID, len(todos))
+
+	// Audit before deleting
+	for i, todo := range todos {
+		entry := ports.AuditEntry{
+			Action: AuditActionPurge,
+			TodoID: todo.ID().String(),
+			Actor:  req.Actor,
+			Reason: req.Reason,
+			Changes: map[string]string{
+				"title":  todo.Title().String(),
+				"status": todo.Status().String(),
+			},
+			OccurredAt: report.PurgedAt,
+		}
+		if err := s.auditLog.Record(ctx, entry); err != nil {
+			return nil, fmt.Errorf("recording audit entry: %w", err)
+		}
+
+		ids[i] = todo.ID()
+		report.Tombstones[i] = mapTodoToTombstone(todo)
+	}
+
+	// Purge and dispatch deleted events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if _, err := s.purger.DeleteMany(ctx, ids); err != nil {
+			return nil, fmt.Errorf("purging todos: %w", err)
+		}
+		events := make([]domain.DomainEvent, len(todos))
+		for i, todo := range todos {
+			events[i] = domain.NewTodoDeletedEvent(todo.ID(), todo.OwnerID())
+		}
+		return events, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// findPurgeable returns every todo matching filter that is not on legal
+// hold, with the number of held todos, refusing filters that match more
+// than MaxPurgeSize todos
+func (s *TodoApplicationService) findPurgeable(ctx context.Context, filter ports.PurgeFilter) ([]*domain.Todo, int, error) {
+	todos, err := s.purger.FindPurgeable(ctx, filter, MaxPurgeSize+1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("finding purgeable todos: %w", err)
+	}
+
+	if len(todos) > MaxPurgeSize {
+		return nil, 0, domain.NewValidationError("filter", fmt.Sprintf("matches more than %d todos, narrow it", MaxPurgeSize))
+	}
+
+	return s.withoutHeld(ctx, todos)
+}
+
+// purgeToken signs the filter and the matched todos until expiresAt
+// Its format is "<expiry unix seconds>.<signature>"
+func (s *TodoApplicationService) purgeToken(filter ports.PurgeFilter, todos []*domain.Todo, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + s.purgeSignature(expiry, filter, todos)
+}
+
+// verifyPurgeToken reports whether token confirms purging todos with filter
+func (s *TodoApplicationService) verifyPurgeToken(token string, filter ports.PurgeFilter, todos []*domain.Todo) bool {
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.purgeSignature(expiry, filter, todos)))
+}
+
+// purgeSignature computes the HMAC binding a token to its expiry, filter and
+// matched todos
+func (s *TodoApplicationService) purgeSignature(expiry string, filter ports.PurgeFilter, todos []*domain.Todo) string {
+	mac := hmac.New(sha256.New, s.purgeSecret)
+
+	fmt.Fprintf(mac, "expiry=%s\n", expiry)
+	if filter.Status != nil {
+		fmt.Fprintf(mac, "status=%s\n", filter.Status.String())
+	}
+	if filter.Priority != nil {
+		fmt.Fprintf(mac, "priority=%s\n", filter.Priority.String())
+	}
+	if filter.UpdatedBefore != nil {
+		fmt.Fprintf(mac, "updated_before=%d\n", filter.UpdatedBefore.UnixNano())
+	}
+
+	// FindPurgeable returns todos in a stable order
+	for _, todo := range todos {
+		fmt.Fprintf(mac, "todo=%s\n", todo.ID())
+	}
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// buildPurgeFilter validates a purge request into a repository filter
+func buildPurgeFilter(req PurgeTodosRequest) (ports.PurgeFilter, error) {
+	var filter ports.PurgeFilter
+
+	if req.Status == nil && req.Priority == nil && req.UpdatedBefore == nil {
+		return filter, domain.NewValidationError("filter", "at least one criterion is required")
+	}
+
+	if req.Status != nil {
+		status, err := domain.NewTaskStatus(*req.Status)
+		if err != nil {
+			return filter, fmt.Errorf("invalid status filter: %w", err)
+		}
+		filter.Status = &status
+	}
+
+	if req.Priority != nil {
+		priority, err := domain.NewPriority(*req.Priority)
+		if err != nil {
+			return filter, fmt.Errorf("invalid priority filter: %w", err)
+		}
+		filter.Priority = &priority
+	}
+
+	filter.UpdatedBefore = req.UpdatedBefore
+
+	return filter, nil
+}
+
+// mapTodoToTombstone records the identifying fields of a purged todo
+func mapTodoToTombstone(todo *domain.Todo) TodoTombstone {
+	tombstone := TodoTombstone{
+		ID:        todo.ID().String(),
+		Title:     todo.Title().String(),
+		Status:    todo.Status().String(),
+		Priority:  todo.Priority().String(),
+		CreatedAt: todo.CreatedAt(),
+		UpdatedAt: todo.UpdatedAt(),
+	}
+
+	if !todo.ShortCode().IsZero() {
+		tombstone.ShortCode = todo.ShortCode().String()
+	}
+
+	return tombstone
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockTodoPurger serves Todos as the purgeable todos and records deletions
+type MockTodoPurger struct {
+	Todos   []*domain.Todo
+	Deleted []domain.TodoID
+}
+
+func (m *MockTodoPurger) FindPurgeable(ctx context.Context, filter ports.PurgeFilter, limit int) ([]*domain.Todo, error) {
+	if len(m.Todos) > limit {
+		return m.Todos[:limit], nil
+	}
+	return m.Todos, nil
+}
+
+func (m *MockTodoPurger) DeleteMany(ctx context.Context, ids []domain.TodoID) (int, error) {
+	m.Deleted = append(m.Deleted, ids...)
+	return len(ids), nil
+}
+
+func newPurgeTestService(purger *MockTodoPurger, auditLog *MockAuditLog, dispatcher *MockEventDispatcher) *TodoApplicationService {
+	return NewTodoApplicationService(&MockTodoRepository{}, dispatcher,
+		WithAuditLog(auditLog),
+		WithPurge(purger, []byte("secret")),
+	)
+}
+
+func TestTodoService_PurgeTodos_AfterPreview(t *testing.T) {
+	purger := &MockTodoPurger{Todos: []*domain.Todo{createTestTodo(), createTestTodo()}}
+	auditLog := &MockAuditLog{}
+	dispatcher := &MockEventDispatcher{}
+	service := newPurgeTestService(purger, auditLog, dispatcher)
+
+	status := "cancelled"
+	req := PurgeTodosRequest{Status: &status, Actor: "ops@example.com", Reason: "Retention"}
+
+	preview, err := service.PreviewPurge(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PreviewPurge() unexpected error: %v", err)
+	}
+	if preview.MatchCount != 2 {
+		t.Errorf("MatchCount = %d, want 2", preview.MatchCount)
+	}
+
+	req.ConfirmationToken = preview.ConfirmationToken
+	report, err := service.PurgeTodos(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PurgeTodos() unexpected error: %v", err)
+	}
+
+	if len(report.Tombstones) != 2 || len(purger.Deleted) != 2 {
+		t.Errorf("Purged %d todos with %d tombstones, want 2", len(purger.Deleted), len(report.Tombstones))
+	}
+
+	if len(auditLog.Entries) != 2 || auditLog.Entries[0].Action != AuditActionPurge {
+		t.Errorf("Audit entries = %+v, want one purge entry per todo", auditLog.Entries)
+	}
+
+	if len(dispatcher.DispatchedEvents) != 2 || dispatcher.DispatchedEvents[0].EventType() != "TodoDeleted" {
+		t.Errorf("Dispatched events = %v, want one TodoDeleted per todo", dispatcher.DispatchedEvents)
+	}
+}
+
+func TestTodoService_PurgeTodos_RejectsStaleConfirmation(t *testing.T) {
+	purger := &MockTodoPurger{Todos: []*domain.Todo{createTestTodo()}}
+	service := newPurgeTestService(purger, &MockAuditLog{}, &MockEventDispatcher{})
+
+	status := "cancelled"
+	req := PurgeTodosRequest{Status: &status, Actor: "ops", Reason: "Retention"}
+
+	preview, err := service.PreviewPurge(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PreviewPurge() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		mutate func()
+	}{
+		{"missing token", "", func() {}},
+		{"tampered token", preview.ConfirmationToken + "x", func() {}},
+		{"matches changed", preview.ConfirmationToken, func() { purger.Todos = append(purger.Todos, createTestTodo()) }},
+		{"different filter", preview.ConfirmationToken, func() { priority := "low"; req.Priority = &priority }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mutate()
+			req.ConfirmationToken = tt.token
+
+			if _, err := service.PurgeTodos(context.Background(), req); !errors.Is(err, ErrPurgeConfirmation) {
+				t.Errorf("PurgeTodos() error = %v, want %v", err, ErrPurgeConfirmation)
+			}
+		})
+	}
+
+	if len(purger.Deleted) != 0 {
+		t.Errorf("Deleted = %v, want nothing deleted", purger.Deleted)
+	}
+}
+
+func TestTodoService_PurgeTodos_Validation(t *testing.T) {
+	service := newPurgeTestService(&MockTodoPurger{}, &MockAuditLog{}, &MockEventDispatcher{})
+	status := "cancelled"
+
+	var validationErr domain.ValidationError
+	if _, err := service.PreviewPurge(context.Background(), PurgeTodosRequest{}); !errors.As(err, &validationErr) {
+		t.Errorf("PreviewPurge() without criteria error = %v, want a validation error", err)
+	}
+
+	if _, err := service.PurgeTodos(context.Background(), PurgeTodosRequest{Status: &status}); !errors.Is(err, domain.ErrMissingReason) {
+		t.Errorf("PurgeTodos() without reason error = %v, want %v", err, domain.ErrMissingReason)
+	}
+
+	unaudited := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithPurge(&MockTodoPurger{}, nil))
+	req := PurgeTodosRequest{Status: &status, Reason: "Retention"}
+	if _, err := unaudited.PurgeTodos(context.Background(), req); !errors.Is(err, ErrAuditLogRequired) {
+		t.Errorf("PurgeTodos() without audit log error = %v, want %v", err, ErrAuditLogRequired)
+	}
+}
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Device pruning
+const (
+	// DeviceRetention is how long a device that stopped registering its
+	// token is kept; FCM expires the tokens of devices inactive for 270 days
+	DeviceRetention = 270 * 24 * time.Hour
+	// devicePruneInterval is the delay between two prunings
+	devicePruneInterval = 24 * time.Hour
+)
+
+// PushNotifier sends push notifications to the devices of the owner of a
+// todo when it is due soon, overdue, or moved to them
+// Devices whose token the provider rejects for good are forgotten, and
+// those not seen for DeviceRetention are pruned
+type PushNotifier struct {
+	devices    ports.DeviceStore
+	sender     ports.PushSender
+	repository ports.TodoRepository
+	subscriber ports.EventSubscriber
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewPushNotifier creates a new PushNotifier
+func NewPushNotifier(
+	devices ports.DeviceStore,
+	sender ports.PushSender,
+	repository ports.TodoRepository,
+	subscriber ports.EventSubscriber,
+	logger *slog.Logger,
+) *PushNotifier {
+	return &PushNotifier{
+		devices:    devices,
+		sender:     sender,
+		repository: repository,
+		subscriber: subscriber,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run notifies the dispatched events and prunes the stale devices until ctx
+// is cancelled
+func (n *PushNotifier) Run(ctx context.Context) {
+	prune := time.NewTicker(devicePruneInterval)
+	defer prune.Stop()
+	n.prune(ctx)
+
+	events := n.subscriber.Subscribe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-prune.C:
+			n.prune(ctx)
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				n.logger.Warn("push notifications fell behind the events, resubscribing")
+				events = n.subscriber.Subscribe(ctx)
+				continue
+			}
+			if err := n.Notify(ctx, event); err != nil {
+				n.logger.Error("sending push notifications failed", "event_type", event.EventType(), "error", err)
+			}
+		}
+	}
+}
+
+// Notify sends the notification of event to the devices of its recipient
+// Events without a notification, and todos without an owner, are ignored
+func (n *PushNotifier) Notify(ctx context.Context, event domain.DomainEvent) error {
+	var title, recipient string
+	switch e := event.(type) {
+	case domain.TodoDueSoon:
+		title = "Due soon"
+	case domain.TodoOverdue:
+		title = "Overdue"
+	case domain.TodoMoved:
+		title, recipient = "Moved to you", e.ToOwner
+	default:
+		return nil
+	}
+
+	id, err := domain.ParseTodoID(event.AggregateID())
+	if err != nil {
+		return fmt.Errorf("parsing todo ID: %w", err)
+	}
+	// Events are not scoped to a user: load the todo whoever owns it
+	todo, err := n.repository.FindByID(ports.ContextWithSystemAccess(ctx), id)
+	if errors.Is(err, domain.ErrTodoNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding todo: %w", err)
+	}
+	if todo.IsCanary() {
+		return nil
+	}
+	if recipient == "" {
+		recipient = todo.OwnerID()
+	}
+	if recipient == "" {
+		return nil
+	}
+
+	devices, err := n.devices.ListByUser(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	message := ports.PushMessage{
+		Title: title,
+		Body:  todo.Title().String(),
+		Data: map[string]string{
+			"todo_id":    id.String(),
+			"event_type": event.EventType(),
+		},
+		// A newer notification on the todo replaces the older one
+		CollapseKey: id.String(),
+	}
+
+	var errs []error
+	for _, device := range devices {
+		err := n.sender.Send(ctx, device, message)
+		if errors.Is(err, ports.ErrDeviceUnregistered) {
+			n.logger.Info("forgetting unregistered device", "user_id", device.UserID, "provider", device.Provider)
+			if _, err := n.devices.Delete(ctx, device.UserID, device.Token); err != nil {
+				errs = append(errs, fmt.Errorf("deleting device: %w", err))
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sending to %s device: %w", device.Provider, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// prune deletes the devices not seen for DeviceRetention
+func (n *PushNotifier) prune(ctx context.Context) {
+	pruned, err := n.devices.DeleteSeenBefore(ctx, n.now().Add(-DeviceRetention))
+	if err != nil {
+		n.logger.Error("pruning devices failed", "error", err)
+		return
+	}
+	if pruned > 0 {
+		n.logger.Info("pruned stale devices", "count", pruned)
+	}
+}
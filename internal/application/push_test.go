@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockPushSender records the messages it sends, failing for the tokens of
+// Errors
+type MockPushSender struct {
+	Sent   map[string][]ports.PushMessage
+	Errors map[string]error
+}
+
+func (m *MockPushSender) Send(ctx context.Context, device ports.Device, message ports.PushMessage) error {
+	if err := m.Errors[device.Token]; err != nil {
+		return err
+	}
+	if m.Sent == nil {
+		m.Sent = map[string][]ports.PushMessage{}
+	}
+	m.Sent[device.Token] = append(m.Sent[device.Token], message)
+	return nil
+}
+
+// newPushTestNotifier returns a notifier for todo, sending to a device of
+// alice and one of bob
+func newPushTestNotifier(todo *domain.Todo, sender *MockPushSender) (*PushNotifier, *MockDeviceStore) {
+	store := &MockDeviceStore{Devices: map[string]ports.Device{
+		"alice-phone": {Token: "alice-phone", UserID: "alice", Provider: ports.PushProviderAPNs, LastSeenAt: time.Now()},
+		"bob-phone":   {Token: "bob-phone", UserID: "bob", Provider: ports.PushProviderFCM, LastSeenAt: time.Now()},
+	}}
+	repository := newMergeTestRepository(todo)
+	return NewPushNotifier(store, sender, repository, &MockEventSubscriber{}, slog.New(slog.NewTextHandler(io.Discard, nil))), store
+}
+
+// pushTestDueDate is the due date of the reminder events
+func pushTestDueDate() domain.DueDate {
+	due, _ := domain.NewDueDate(time.Now().Add(time.Hour))
+	return due
+}
+
+func TestPushNotifier_Notify(t *testing.T) {
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	due := pushTestDueDate()
+
+	tests := []struct {
+		name      string
+		event     domain.DomainEvent
+		recipient string
+		title     string
+	}{
+		{"due soon", domain.NewTodoDueSoonEvent(todo.ID(), due), "alice-phone", "Due soon"},
+		{"overdue", domain.NewTodoOverdueEvent(todo.ID(), due), "alice-phone", "Overdue"},
+		{"moved", domain.NewTodoMovedEvent(todo.ID(), "alice", "bob"), "bob-phone", "Moved to you"},
+		{"not notified", domain.NewTodoUpdatedEvent(todo.ID()), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &MockPushSender{}
+			notifier, _ := newPushTestNotifier(todo, sender)
+
+			if err := notifier.Notify(context.Background(), tt.event); err != nil {
+				t.Fatalf("Notify() unexpected error: %v", err)
+			}
+
+			if tt.recipient == "" {
+				if len(sender.Sent) != 0 {
+					t.Errorf("sent %v, want nothing", sender.Sent)
+				}
+				return
+			}
+			if len(sender.Sent) != 1 || len(sender.Sent[tt.recipient]) != 1 {
+				t.Fatalf("sent %v, want one message to %s", sender.Sent, tt.recipient)
+			}
+			message := sender.Sent[tt.recipient][0]
+			if message.Title != tt.title || message.Body != todo.Title().String() {
+				t.Errorf("message = %+v, want %q about the todo", message, tt.title)
+			}
+			if message.Data["todo_id"] != todo.ID().String() || message.Data["event_type"] != tt.event.EventType() || message.CollapseKey != todo.ID().String() {
+				t.Errorf("message = %+v, want the todo ID, event type and collapse key", message)
+			}
+		})
+	}
+}
+
+func TestPushNotifier_Notify_ForgetsUnregisteredDevices(t *testing.T) {
+	todo := createTestTodo()
+	todo.AssignOwner("alice")
+	errUnavailable := errors.New("503 Service Unavailable")
+	due := pushTestDueDate()
+
+	sender := &MockPushSender{Errors: map[string]error{"alice-phone": ports.ErrDeviceUnregistered}}
+	notifier, store := newPushTestNotifier(todo, sender)
+	if err := notifier.Notify(context.Background(), domain.NewTodoOverdueEvent(todo.ID(), due)); err != nil {
+		t.Fatalf("Notify() unexpected error: %v", err)
+	}
+	if _, ok := store.Devices["alice-phone"]; ok {
+		t.Error("the unregistered device was kept")
+	}
+
+	sender = &MockPushSender{Errors: map[string]error{"alice-phone": errUnavailable}}
+	notifier, store = newPushTestNotifier(todo, sender)
+	if err := notifier.Notify(context.Background(), domain.NewTodoOverdueEvent(todo.ID(), due)); !errors.Is(err, errUnavailable) {
+		t.Errorf("Notify() error = %v, want %v", err, errUnavailable)
+	}
+	if _, ok := store.Devices["alice-phone"]; !ok {
+		t.Error("the device was forgotten on a transient error")
+	}
+}
+
+func TestPushNotifier_Notify_Skips(t *testing.T) {
+	unowned := createTestTodo()
+	canary := createTestTodo()
+	canary.AssignOwner("alice")
+	canary.RestoreCanary()
+	due := pushTestDueDate()
+
+	for name, todo := range map[string]*domain.Todo{"unowned": unowned, "canary": canary} {
+		t.Run(name, func(t *testing.T) {
+			sender := &MockPushSender{}
+			notifier, _ := newPushTestNotifier(todo, sender)
+			if err := notifier.Notify(context.Background(), domain.NewTodoOverdueEvent(todo.ID(), due)); err != nil {
+				t.Fatalf("Notify() unexpected error: %v", err)
+			}
+			if len(sender.Sent) != 0 {
+				t.Errorf("sent %v, want nothing", sender.Sent)
+			}
+		})
+	}
+}
+
+func TestPushNotifier_Prune(t *testing.T) {
+	sender := &MockPushSender{}
+	notifier, store := newPushTestNotifier(createTestTodo(), sender)
+	stale := store.Devices["bob-phone"]
+	stale.LastSeenAt = time.Now().Add(-DeviceRetention - time.Hour)
+	store.Devices["bob-phone"] = stale
+
+	notifier.prune(context.Background())
+
+	if _, ok := store.Devices["bob-phone"]; ok {
+		t.Error("the stale device was kept")
+	}
+	if _, ok := store.Devices["alice-phone"]; !ok {
+		t.Error("the recent device was pruned")
+	}
+}
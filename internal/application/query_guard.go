@@ -0,0 +1,200 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrQueryThrottled is returned when a caller made too many expensive
+// queries in the current window
+var ErrQueryThrottled = errors.New("too many expensive queries")
+
+// QueryGuardOptions bounds the cost of the ListTodos and SearchTodos queries
+type QueryGuardOptions struct {
+	// MaxLimit caps the page size of ListTodos; larger and missing limits
+	// are rewritten to it
+	MaxLimit int
+	// MaxOffset rejects deeper pages, which the database reads and discards
+	MaxOffset int
+	// ExpensiveOffset makes deeper pages count against the budget
+	ExpensiveOffset int
+	// MaxSearchTerms rejects searches with more keywords
+	MaxSearchTerms int
+	// Budget is the number of expensive queries a caller may make per
+	// Window; zero disables throttling
+	Budget int
+	Window time.Duration
+}
+
+// DefaultQueryGuardOptions returns pages of at most 500 todos, up to an
+// offset of 10,000, and allows 10 queries past an offset of 1,000 per
+// minute and caller
+func DefaultQueryGuardOptions() QueryGuardOptions {
+	return QueryGuardOptions{
+		MaxLimit:        500,
+		MaxOffset:       10_000,
+		ExpensiveOffset: 1_000,
+		MaxSearchTerms:  10,
+		Budget:          10,
+		Window:          time.Minute,
+	}
+}
+
+// QueryGuard rewrites or rejects the queries that would load the database
+// more than any regular client needs, with guidance on how to get the
+// same results cheaply
+// Callers are told apart by user ID; anonymous callers share a budget
+type QueryGuard struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	options QueryGuardOptions
+	windows map[string]*queryWindow
+}
+
+// queryWindow counts the expensive queries of a caller since start
+type queryWindow struct {
+	start time.Time
+	count int
+}
+
+// NewQueryGuard creates a new QueryGuard
+func NewQueryGuard(options QueryGuardOptions) *QueryGuard {
+	return &QueryGuard{
+		options: options,
+		now:     time.Now,
+		windows: make(map[string]*queryWindow),
+	}
+}
+
+// SetOptions applies options to the next queries
+// The expensive queries already counted stay counted against the new budget
+func (g *QueryGuard) SetOptions(options QueryGuardOptions) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.options = options
+}
+
+// currentOptions returns the options in effect
+func (g *QueryGuard) currentOptions() QueryGuardOptions {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.options
+}
+
+// WithQueryGuard bounds the cost of ListTodos and SearchTodos
+func WithQueryGuard(guard *QueryGuard) Option {
+	return func(s *TodoApplicationService) {
+		s.queryGuard = guard
+	}
+}
+
+// guardList rewrites the page of filters and returns the warnings for the
+// client, or rejects the query
+func (g *QueryGuard) guardList(ctx context.Context, filters *ports.Filters) ([]string, error) {
+	var warnings []string
+	options := g.currentOptions()
+
+	limit := options.MaxLimit
+	switch {
+	case filters.Limit == nil:
+		warnings = append(warnings, fmt.Sprintf(
+			"no limit given, returning the first %d todos; page with limit and offset", limit))
+		filters.Limit = &limit
+	case *filters.Limit > limit:
+		warnings = append(warnings, fmt.Sprintf(
+			"limit %d lowered to %d; page with limit and offset", *filters.Limit, limit))
+		filters.Limit = &limit
+	}
+
+	offset := 0
+	if filters.Offset != nil {
+		offset = *filters.Offset
+	}
+	if offset > options.MaxOffset {
+		return nil, domain.NewValidationError("offset", fmt.Sprintf(
+			"cannot exceed %d; narrow the status, priority or archived filters, or reverse the sort order, to reach these todos",
+			options.MaxOffset))
+	}
+	if err := g.spend(ctx, offset); err != nil {
+		return nil, err
+	}
+
+	return warnings, nil
+}
+
+// guardSearch rejects the searches matching too broadly or paging too deep
+func (g *QueryGuard) guardSearch(ctx context.Context, query string, offset int) error {
+	options := g.currentOptions()
+	if offset > options.MaxOffset {
+		return domain.NewValidationError("offset", fmt.Sprintf(
+			"cannot exceed %d; add keywords to narrow the search", options.MaxOffset))
+	}
+
+	// Keywords follow the websearch syntax: "or" joins alternatives and a
+	// leading "-" excludes a keyword
+	terms, included := 0, 0
+	for _, word := range strings.Fields(query) {
+		word = strings.Trim(word, `"`)
+		if word == "" || strings.EqualFold(word, "or") {
+			continue
+		}
+		terms++
+		if !strings.HasPrefix(word, "-") {
+			included++
+		}
+	}
+	if included == 0 {
+		return domain.NewValidationError("query", "needs a keyword that is not excluded; excluding keywords alone matches nearly every todo")
+	}
+	if terms > options.MaxSearchTerms {
+		return domain.NewValidationError("query", fmt.Sprintf(
+			"cannot have more than %d keywords; split the search", options.MaxSearchTerms))
+	}
+
+	return g.spend(ctx, offset)
+}
+
+// spend counts a query past ExpensiveOffset against the budget of the
+// caller, and fails once the budget of the window is spent
+func (g *QueryGuard) spend(ctx context.Context, offset int) error {
+	caller, _ := UserIDFromContext(ctx)
+	now := g.now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.options.Budget == 0 || offset <= g.options.ExpensiveOffset {
+		return nil
+	}
+
+	// Ended windows are dropped, so idle callers are not kept
+	for id, window := range g.windows {
+		if now.Sub(window.start) >= g.options.Window {
+			delete(g.windows, id)
+		}
+	}
+
+	window, ok := g.windows[caller]
+	if !ok {
+		window = &queryWindow{start: now}
+		g.windows[caller] = window
+	}
+	if window.count >= g.options.Budget {
+		retry := window.start.Add(g.options.Window).Sub(now).Round(time.Second)
+		return fmt.Errorf("%w: at most %d pages past offset %d per %s, retry in %s or narrow the filters",
+			ErrQueryThrottled, g.options.Budget, g.options.ExpensiveOffset, g.options.Window, retry)
+	}
+	window.count++
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestTodoService_ListTodos_QueryGuard(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name         string
+		filters      ListFilters
+		wantLimit    int
+		wantWarnings int
+		wantErr      bool
+	}{
+		{name: "page within bounds", filters: ListFilters{Limit: intPtr(50), Offset: intPtr(100)}, wantLimit: 50},
+		{name: "missing limit", filters: ListFilters{}, wantLimit: 500, wantWarnings: 1},
+		{name: "huge limit", filters: ListFilters{Limit: intPtr(1_000_000)}, wantLimit: 500, wantWarnings: 1},
+		{name: "offset in the millions", filters: ListFilters{Limit: intPtr(10), Offset: intPtr(5_000_000)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ports.Filters
+			repo := &MockTodoRepository{
+				FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+					got = filters
+					return nil, nil
+				},
+			}
+			service := NewTodoApplicationService(repo, &MockEventDispatcher{},
+				WithQueryGuard(NewQueryGuard(DefaultQueryGuardOptions())))
+
+			result, err := service.ListTodos(context.Background(), tt.filters)
+			if tt.wantErr {
+				var validationErr domain.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("ListTodos() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTodos() unexpected error: %v", err)
+			}
+			if got.Limit == nil || *got.Limit != tt.wantLimit {
+				t.Errorf("repository limit = %v, want %d", got.Limit, tt.wantLimit)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %q, want %d", result.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestQueryGuard_GuardSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		offset  int
+		wantErr bool
+	}{
+		{name: "keywords", query: `groceries "weekly plan" -done`},
+		{name: "exclusions only", query: "-done -archived", wantErr: true},
+		{name: "alternatives only joined", query: "or OR or", wantErr: true},
+		{name: "too many keywords", query: "a or b or c or d or e or f or g or h or i or j or k", wantErr: true},
+		{name: "offset too deep", query: "groceries", offset: 20_000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := NewQueryGuard(DefaultQueryGuardOptions())
+			err := guard.guardSearch(context.Background(), tt.query, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("guardSearch(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryGuard_ThrottlesExpensiveQueries(t *testing.T) {
+	options := DefaultQueryGuardOptions()
+	options.Budget = 2
+	guard := NewQueryGuard(options)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+
+	alice := ContextWithUserID(context.Background(), "alice")
+	bob := ContextWithUserID(context.Background(), "bob")
+	deep := options.ExpensiveOffset + 1
+
+	for i := 0; i < 2; i++ {
+		if err := guard.spend(alice, deep); err != nil {
+			t.Fatalf("spend() %d unexpected error: %v", i, err)
+		}
+	}
+	if err := guard.spend(alice, deep); !errors.Is(err, ErrQueryThrottled) {
+		t.Errorf("spend() past the budget = %v, want ErrQueryThrottled", err)
+	}
+	if err := guard.spend(alice, options.ExpensiveOffset); err != nil {
+		t.Errorf("spend() of a cheap query = %v, want no throttling", err)
+	}
+	if err := guard.spend(bob, deep); err != nil {
+		t.Errorf("spend() of another caller = %v, want its own budget", err)
+	}
+
+	now = now.Add(options.Window)
+	if err := guard.spend(alice, deep); err != nil {
+		t.Errorf("spend() in the next window = %v, want a new budget", err)
+	}
+}
+
+func TestQueryGuard_SetOptions(t *testing.T) {
+	options := DefaultQueryGuardOptions()
+	options.Budget = 1
+	guard := NewQueryGuard(options)
+	alice := ContextWithUserID(context.Background(), "alice")
+	deep := options.ExpensiveOffset + 1
+
+	if err := guard.spend(alice, deep); err != nil {
+		t.Fatalf("spend() unexpected error: %v", err)
+	}
+	if err := guard.spend(alice, deep); !errors.Is(err, ErrQueryThrottled) {
+		t.Fatalf("spend() past the budget = %v, want ErrQueryThrottled", err)
+	}
+
+	options.Budget = 2
+	options.MaxOffset = 100
+	guard.SetOptions(options)
+	if err := guard.spend(alice, deep); err != nil {
+		t.Errorf("spend() within the raised budget = %v, want no throttling", err)
+	}
+	if err := guard.guardSearch(alice, "groceries", 101); err == nil {
+		t.Error("guardSearch() past the lowered MaxOffset succeeded, want a validation error")
+	}
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Recent todos limits
+const (
+	DefaultRecentLimit = 10
+	MaxRecentLimit     = 50
+)
+
+// WithRecentActivity tracks which todos each authenticated user views and
+// modifies, and enables ListRecentTodos
+func WithRecentActivity(store ports.RecentActivityStore) Option {
+	return func(s *TodoApplicationService) {
+		s.recent = store
+	}
+}
+
+// ListRecentTodos returns the todos the authenticated user most recently
+// viewed or modified, most recent first
+// kind is "viewed" or "modified"; a limit of zero or less selects
+// DefaultRecentLimit and larger limits are capped to MaxRecentLimit
+func (s *TodoApplicationService) ListRecentTodos(
+	ctx context.Context,
+	kind string,
+	limit int,
+) ([]*TodoResponse, error) {
+	if s.recent == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	activity := ports.ActivityKind(kind)
+	if activity != ports.ActivityViewed && activity != ports.ActivityModified {
+		return nil, domain.NewValidationError("kind", "must be viewed or modified")
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultRecentLimit
+	case limit > MaxRecentLimit:
+		limit = MaxRecentLimit
+	}
+
+	ids, err := s.recent.ListRecent(ctx, userID, activity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing recent todos: %w", err)
+	}
+
+	todos := make([]*TodoResponse, 0, len(ids))
+	for _, id := range ids {
+		todo, err := s.repository.FindByID(ctx, id)
+		if errors.Is(err, domain.ErrTodoNotFound) {
+			continue // Deleted since
+		}
+		if err != nil {
+			return nil, fmt.Errorf("finding todo: %w", err)
+		}
+		todos = append(todos, MapTodoToResponse(todo))
+	}
+
+	return todos, nil
+}
+
+// trackActivity records activity of the authenticated user on a todo
+// Tracking is best effort: it must never fail the operation being tracked
+func (s *TodoApplicationService) trackActivity(ctx context.Context, todoID domain.TodoID, kind ports.ActivityKind) {
+	if s.recent == nil {
+		return
+	}
+
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	_ = s.recent.Touch(ctx, userID, todoID, kind, time.Now())
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// touch is an activity recorded by MockRecentActivityStore
+type touch struct {
+	userID string
+	todoID domain.TodoID
+	kind   ports.ActivityKind
+}
+
+// MockRecentActivityStore records touches and lists configured IDs
+type MockRecentActivityStore struct {
+	Touches []touch
+	Recent  []domain.TodoID
+	Err     error
+}
+
+func (m *MockRecentActivityStore) Touch(ctx context.Context, userID string, todoID domain.TodoID, kind ports.ActivityKind, at time.Time) error {
+	m.Touches = append(m.Touches, touch{userID, todoID, kind})
+	return m.Err
+}
+
+func (m *MockRecentActivityStore) ListRecent(ctx context.Context, userID string, kind ports.ActivityKind, limit int) ([]domain.TodoID, error) {
+	return m.Recent, m.Err
+}
+
+func TestTodoService_GetTodo_TracksViewForAuthenticatedUser(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	store := &MockRecentActivityStore{Err: errors.New("store down")}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithRecentActivity(store))
+
+	// Anonymous reads are not tracked
+	if _, err := service.GetTodo(context.Background(), testTodo.ID().String()); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	// Tracking failures do not fail the read
+	ctx := ContextWithUserID(context.Background(), "alice")
+	if _, err := service.GetTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	want := []touch{{"alice", testTodo.ID(), ports.ActivityViewed}}
+	if len(store.Touches) != 1 || store.Touches[0] != want[0] {
+		t.Errorf("Touches = %v, want %v", store.Touches, want)
+	}
+}
+
+func TestTodoService_CompleteTodo_TracksModification(t *testing.T) {
+	testTodo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	store := &MockRecentActivityStore{}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithRecentActivity(store))
+
+	ctx := ContextWithUserID(context.Background(), "alice")
+	if _, err := service.CompleteTodo(ctx, testTodo.ID().String()); err != nil {
+		t.Fatalf("CompleteTodo() unexpected error: %v", err)
+	}
+
+	if len(store.Touches) != 1 || store.Touches[0].kind != ports.ActivityModified {
+		t.Errorf("Touches = %v, want one modification", store.Touches)
+	}
+}
+
+func TestTodoService_ListRecentTodos_SkipsDeletedTodos(t *testing.T) {
+	kept := createTestTodo()
+	deleted := domain.NewTodoID()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if id == kept.ID() {
+				return kept, nil
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	store := &MockRecentActivityStore{Recent: []domain.TodoID{deleted, kept.ID()}}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithRecentActivity(store))
+
+	todos, err := service.ListRecentTodos(ContextWithUserID(context.Background(), "alice"), "viewed", 0)
+
+	if err != nil {
+		t.Fatalf("ListRecentTodos() unexpected error: %v", err)
+	}
+
+	if len(todos) != 1 || todos[0].ID != kept.ID().String() {
+		t.Errorf("ListRecentTodos() = %v, want only %v", todos, kept.ID())
+	}
+}
+
+func TestTodoService_ListRecentTodos_Errors(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithRecentActivity(&MockRecentActivityStore{}))
+	alice := ContextWithUserID(context.Background(), "alice")
+
+	if _, err := service.ListRecentTodos(context.Background(), "viewed", 10); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("ListRecentTodos() anonymous error = %v, want %v", err, ErrUnauthenticated)
+	}
+
+	var validationErr domain.ValidationError
+	if _, err := service.ListRecentTodos(alice, "starred", 10); !errors.As(err, &validationErr) {
+		t.Errorf("ListRecentTodos() invalid kind error = %v, want a validation error", err)
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.ListRecentTodos(alice, "viewed", 10); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListRecentTodos() without store error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// reconcileBatchSize is the number of drifted todos of each kind repaired at
+// a time by a pass
+const reconcileBatchSize = 500
+
+// ReconcilerOptions controls the outbox reconciliation
+type ReconcilerOptions struct {
+	// Interval is the delay between two passes
+	Interval time.Duration
+	// DryRun reports the drift of the first batch without repairing it
+	DryRun bool
+}
+
+// ReconcileReport counts the drift found, and repaired, by a pass
+type ReconcileReport struct {
+	Unrecorded int
+	Orphaned   int
+	// Repaired is the number of events recorded, zero in dry run
+	Repaired int
+}
+
+// OutboxReconciler periodically repairs the drift between the outbox and the
+// todos: todos without a TodoCreated event and events of todos deleted
+// without a TodoDeleted one
+// The totals of each pass are added to the "unrecorded", "orphaned" and
+// "repaired" counters of its JobTracker
+type OutboxReconciler struct {
+	reconciler ports.OutboxReconciler
+	logger     *slog.Logger
+	runs       *JobTracker
+	// reset wakes Run up to apply new options
+	reset chan struct{}
+
+	mu      sync.Mutex
+	options ReconcilerOptions
+}
+
+// NewOutboxReconciler creates a new OutboxReconciler
+func NewOutboxReconciler(
+	reconciler ports.OutboxReconciler,
+	logger *slog.Logger,
+	options ReconcilerOptions,
+) *OutboxReconciler {
+	return &OutboxReconciler{
+		reconciler: reconciler,
+		logger:     logger,
+		options:    options,
+		runs:       NewJobTracker("outbox_reconciliation", options.Interval),
+		reset:      make(chan struct{}, 1),
+	}
+}
+
+// SetOptions applies options from the next pass; Interval must be positive
+func (r *OutboxReconciler) SetOptions(options ReconcilerOptions) {
+	r.mu.Lock()
+	r.options = options
+	r.mu.Unlock()
+
+	r.runs.SetInterval(options.Interval)
+	select {
+	case r.reset <- struct{}{}:
+	default:
+	}
+}
+
+// currentOptions returns the options in effect
+func (r *OutboxReconciler) currentOptions() ReconcilerOptions {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.options
+}
+
+// Runs returns the tracker of the passes made by Run
+func (r *OutboxReconciler) Runs() *JobTracker {
+	return r.runs
+}
+
+// Run reconciles every Interval until ctx is done
+func (r *OutboxReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.currentOptions().Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.reset:
+			ticker.Reset(r.currentOptions().Interval)
+		case <-ticker.C:
+			_, err := r.Reconcile(ctx)
+			r.runs.Record(time.Now(), err)
+			if err != nil {
+				r.logger.Error("outbox reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Reconcile repairs the drift batch by batch until none is left, and
+// returns what it found and repaired, including on error
+func (r *OutboxReconciler) Reconcile(ctx context.Context) (ReconcileReport, error) {
+	var report ReconcileReport
+	defer r.record(&report)
+
+	for {
+		drift, err := r.reconciler.FindDrift(ctx, reconcileBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("finding drift: %w", err)
+		}
+		if len(drift.Unrecorded) == 0 && len(drift.Orphaned) == 0 {
+			return report, nil
+		}
+		report.Unrecorded += len(drift.Unrecorded)
+		report.Orphaned += len(drift.Orphaned)
+
+		if r.currentOptions().DryRun {
+			r.logger.Warn("outbox drift found",
+				"unrecorded", drift.Unrecorded, "orphaned", drift.Orphaned, "dry_run", true)
+			return report, nil
+		}
+
+		repaired, err := r.reconciler.RepairDrift(ctx, drift)
+		report.Repaired += repaired
+		if err != nil {
+			return report, fmt.Errorf("repairing drift: %w", err)
+		}
+		r.logger.Warn("outbox drift repaired",
+			"unrecorded", drift.Unrecorded, "orphaned", drift.Orphaned, "repaired", repaired)
+
+		// Stops once a batch is not full; a repair racing with another
+		// instance records nothing and would otherwise find the same drift
+		if repaired == 0 || (len(drift.Unrecorded) < reconcileBatchSize && len(drift.Orphaned) < reconcileBatchSize) {
+			return report, nil
+		}
+	}
+}
+
+// record adds report to the counters of the tracker
+func (r *OutboxReconciler) record(report *ReconcileReport) {
+	r.runs.Count("unrecorded", int64(report.Unrecorded))
+	r.runs.Count("orphaned", int64(report.Orphaned))
+	r.runs.Count("repaired", int64(report.Repaired))
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockOutboxReconciler serves its drift until it is repaired
+type MockOutboxReconciler struct {
+	Drift     ports.OutboxDrift
+	RepairErr error
+	Repairs   int
+}
+
+func (m *MockOutboxReconciler) FindDrift(ctx context.Context, limit int) (ports.OutboxDrift, error) {
+	drift := ports.OutboxDrift{
+		Unrecorded: m.Drift.Unrecorded[:min(limit, len(m.Drift.Unrecorded))],
+		Orphaned:   m.Drift.Orphaned[:min(limit, len(m.Drift.Orphaned))],
+	}
+	return drift, nil
+}
+
+func (m *MockOutboxReconciler) RepairDrift(ctx context.Context, drift ports.OutboxDrift) (int, error) {
+	if m.RepairErr != nil {
+		return 0, m.RepairErr
+	}
+	m.Repairs++
+	m.Drift.Unrecorded = m.Drift.Unrecorded[len(drift.Unrecorded):]
+	m.Drift.Orphaned = m.Drift.Orphaned[len(drift.Orphaned):]
+	return len(drift.Unrecorded) + len(drift.Orphaned), nil
+}
+
+func driftIDs(n int) []domain.TodoID {
+	ids := make([]domain.TodoID, n)
+	for i := range ids {
+		ids[i] = domain.NewTodoID()
+	}
+	return ids
+}
+
+func TestOutboxReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name         string
+		unrecorded   int
+		orphaned     int
+		dryRun       bool
+		repairErr    error
+		wantReport   ReconcileReport
+		wantRepairs  int
+		wantErr      bool
+		wantLeftover int
+	}{
+		{
+			name: "no drift",
+		},
+		{
+			name:        "repairs in batches",
+			unrecorded:  reconcileBatchSize + 1,
+			orphaned:    2,
+			wantReport:  ReconcileReport{Unrecorded: reconcileBatchSize + 1, Orphaned: 2, Repaired: reconcileBatchSize + 3},
+			wantRepairs: 2,
+		},
+		{
+			name:         "dry run reports the first batch",
+			unrecorded:   reconcileBatchSize + 1,
+			orphaned:     2,
+			dryRun:       true,
+			wantReport:   ReconcileReport{Unrecorded: reconcileBatchSize, Orphaned: 2},
+			wantLeftover: reconcileBatchSize + 3,
+		},
+		{
+			name:         "failed repair",
+			orphaned:     1,
+			repairErr:    errors.New("database down"),
+			wantReport:   ReconcileReport{Orphaned: 1},
+			wantErr:      true,
+			wantLeftover: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MockOutboxReconciler{
+				Drift:     ports.OutboxDrift{Unrecorded: driftIDs(tt.unrecorded), Orphaned: driftIDs(tt.orphaned)},
+				RepairErr: tt.repairErr,
+			}
+			reconciler := NewOutboxReconciler(
+				store,
+				slog.New(slog.NewTextHandler(io.Discard, nil)),
+				ReconcilerOptions{Interval: time.Minute, DryRun: tt.dryRun},
+			)
+
+			report, err := reconciler.Reconcile(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if report != tt.wantReport {
+				t.Errorf("Reconcile() = %+v, want %+v", report, tt.wantReport)
+			}
+			if store.Repairs != tt.wantRepairs {
+				t.Errorf("repairs = %d, want %d", store.Repairs, tt.wantRepairs)
+			}
+			if leftover := len(store.Drift.Unrecorded) + len(store.Drift.Orphaned); leftover != tt.wantLeftover {
+				t.Errorf("leftover drift = %d, want %d", leftover, tt.wantLeftover)
+			}
+
+			counters := reconciler.Runs().Status().Counters
+			if counters["unrecorded"] != int64(tt.wantReport.Unrecorded) ||
+				counters["orphaned"] != int64(tt.wantReport.Orphaned) ||
+				counters["repaired"] != int64(tt.wantReport.Repaired) {
+				t.Errorf("counters = %v, want the report %+v", counters, tt.wantReport)
+			}
+		})
+	}
+}
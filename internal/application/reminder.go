@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// reminderBatchSize is the number of due todos loaded at a time by a scan
+const reminderBatchSize = 500
+
+// ReminderOptions controls the due date reminders
+type ReminderOptions struct {
+	// Interval is the delay between two scans
+	Interval time.Duration
+	// Lead is how long before its due date a todo is reported due soon
+	Lead time.Duration
+}
+
+// DefaultReminderOptions scans every minute and reports todos due within a day
+func DefaultReminderOptions() ReminderOptions {
+	return ReminderOptions{
+		Interval: time.Minute,
+		Lead:     24 * time.Hour,
+	}
+}
+
+// ReminderScheduler periodically dispatches TodoDueSoon and TodoOverdue
+// events for open todos
+// Each scan covers the time elapsed since the previous one, so every todo
+// is reported once when its due date comes within the lead time and once
+// when it passes; a todo created or rescheduled already within the lead
+// time is only reported overdue
+// With a business calendar, the lead time only counts working days: on a
+// Friday, a one day lead reports the todos due by Monday
+type ReminderScheduler struct {
+	finder     ports.DueTodoFinder
+	dispatcher ports.EventDispatcher
+	logger     *slog.Logger
+	runs       *JobTracker
+	// reset wakes Run up to apply new options
+	reset chan struct{}
+
+	mu       sync.Mutex
+	options  ReminderOptions
+	calendar *BusinessCalendar
+}
+
+// NewReminderScheduler creates a new ReminderScheduler
+func NewReminderScheduler(
+	finder ports.DueTodoFinder,
+	dispatcher ports.EventDispatcher,
+	logger *slog.Logger,
+	options ReminderOptions,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		finder:     finder,
+		dispatcher: dispatcher,
+		logger:     logger,
+		options:    options,
+		runs:       NewJobTracker("reminders", options.Interval),
+		reset:      make(chan struct{}, 1),
+	}
+}
+
+// SetOptions applies options from the next scan; Interval must be positive
+func (s *ReminderScheduler) SetOptions(options ReminderOptions) {
+	s.mu.Lock()
+	s.options = options
+	s.mu.Unlock()
+
+	s.runs.SetInterval(options.Interval)
+	select {
+	case s.reset <- struct{}{}:
+	default:
+	}
+}
+
+// SetCalendar makes the lead time skip the weekends and holidays of
+// calendar from the next scan; a nil calendar counts every day
+func (s *ReminderScheduler) SetCalendar(calendar *BusinessCalendar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calendar = calendar
+}
+
+// currentCalendar returns the calendar in effect, if any
+func (s *ReminderScheduler) currentCalendar() *BusinessCalendar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calendar
+}
+
+// currentOptions returns the options in effect
+func (s *ReminderScheduler) currentOptions() ReminderOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.options
+}
+
+// Runs returns the tracker of the scans made by Run
+func (s *ReminderScheduler) Runs() *JobTracker {
+	return s.runs
+}
+
+// Run scans every Interval until ctx is done
+// Reminders start from the time Run is called; a failed scan is retried,
+// over the same period, at the next interval
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.currentOptions().Interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.reset:
+			ticker.Reset(s.currentOptions().Interval)
+		case <-ticker.C:
+			current := time.Now()
+			err := s.Scan(ctx, last, current)
+			s.runs.Record(time.Now(), err)
+			if err != nil {
+				s.logger.Error("reminder scan failed", "error", err)
+				continue
+			}
+			last = current
+		}
+	}
+}
+
+// Scan dispatches the reminders of the period after from and up to to
+func (s *ReminderScheduler) Scan(ctx context.Context, from, to time.Time) error {
+	lead := s.currentOptions().Lead
+	soonFrom, soonTo := from.Add(lead), to.Add(lead)
+	if calendar := s.currentCalendar(); calendar != nil {
+		days, err := calendar.load(ctx)
+		if err != nil {
+			return fmt.Errorf("due soon reminders: %w", err)
+		}
+		soonFrom, soonTo = days.addWorkingTime(from, lead), days.addWorkingTime(to, lead)
+	}
+
+	dueSoon, err := s.remind(ctx, soonFrom, soonTo, func(todo *domain.Todo) domain.DomainEvent {
+		return domain.NewTodoDueSoonEvent(todo.ID(), *todo.DueDate())
+	})
+	if err != nil {
+		return fmt.Errorf("due soon reminders: %w", err)
+	}
+
+	overdue, err := s.remind(ctx, from, to, func(todo *domain.Todo) domain.DomainEvent {
+		return domain.NewTodoOverdueEvent(todo.ID(), *todo.DueDate())
+	})
+	if err != nil {
+		return fmt.Errorf("overdue reminders: %w", err)
+	}
+
+	if dueSoon+overdue > 0 {
+		s.logger.Info("reminders dispatched", "due_soon", dueSoon, "overdue", overdue)
+	}
+
+	return nil
+}
+
+// remind dispatches an event for every open todo due after from and up to
+// to, batch by batch, and returns how many were dispatched
+func (s *ReminderScheduler) remind(
+	ctx context.Context,
+	from, to time.Time,
+	event func(*domain.Todo) domain.DomainEvent,
+) (int, error) {
+	count := 0
+	for {
+		todos, err := s.finder.FindDueBetween(ctx, from, to, reminderBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("finding due todos: %w", err)
+		}
+		if len(todos) == 0 {
+			return count, nil
+		}
+
+		events := make([]domain.DomainEvent, len(todos))
+		for i, todo := range todos {
+			events[i] = event(todo)
+		}
+		if err := s.dispatcher.Dispatch(ctx, events); err != nil {
+			return count, fmt.Errorf("dispatching events: %w", err)
+		}
+		count += len(todos)
+
+		if len(todos) < reminderBatchSize {
+			return count, nil
+		}
+		from = todos[len(todos)-1].DueDate().Time()
+	}
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockDueTodoFinder serves the todos due in each queried period
+type MockDueTodoFinder struct {
+	Todos []*domain.Todo
+}
+
+func (m *MockDueTodoFinder) FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error) {
+	var due []*domain.Todo
+	for _, todo := range m.Todos {
+		at := todo.DueDate().Time()
+		if at.After(from) && !at.After(to) && len(due) < limit {
+			due = append(due, todo)
+		}
+	}
+	return due, nil
+}
+
+func dueTestTodo(at time.Time) *domain.Todo {
+	title, _ := domain.NewTaskTitle("Due todo")
+	dueDate := domain.ReconstituteDueDate(at)
+	return domain.NewTodo(title, "", domain.PriorityMedium, &dueDate)
+}
+
+func TestReminderScheduler_Scan_DispatchesReminders(t *testing.T) {
+	from := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+
+	overdue := dueTestTodo(from.Add(30 * time.Second))
+	dueSoon := dueTestTodo(from.Add(24*time.Hour + 30*time.Second))
+	notYet := dueTestTodo(from.Add(48 * time.Hour))
+	alreadyReported := dueTestTodo(from.Add(-time.Second))
+
+	dispatcher := &MockEventDispatcher{}
+	scheduler := NewReminderScheduler(
+		&MockDueTodoFinder{Todos: []*domain.Todo{overdue, dueSoon, notYet, alreadyReported}},
+		dispatcher,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ReminderOptions{Interval: time.Minute, Lead: 24 * time.Hour},
+	)
+
+	if err := scheduler.Scan(context.Background(), from, to); err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+
+	events := dispatcher.DispatchedEvents
+	if len(events) != 2 {
+		t.Fatalf("dispatched %d events, want 2", len(events))
+	}
+	if events[0].EventType() != "TodoDueSoon" || events[0].AggregateID() != dueSoon.ID().String() {
+		t.Errorf("first event = %s for %s, want TodoDueSoon for %v", events[0].EventType(), events[0].AggregateID(), dueSoon.ID())
+	}
+	if events[1].EventType() != "TodoOverdue" || events[1].AggregateID() != overdue.ID().String() {
+		t.Errorf("second event = %s for %s, want TodoOverdue for %v", events[1].EventType(), events[1].AggregateID(), overdue.ID())
+	}
+}
+
+func TestReminderScheduler_Scan_CountsWorkingDays(t *testing.T) {
+	// Friday, before a weekend and a Monday holiday
+	from := calendarDay(6).Add(9 * time.Hour)
+	to := from.Add(time.Minute)
+
+	afterHoliday := dueTestTodo(calendarDay(10).Add(9*time.Hour + 30*time.Second))
+	saturday := dueTestTodo(from.Add(24*time.Hour + 30*time.Second))
+
+	dispatcher := &MockEventDispatcher{}
+	scheduler := NewReminderScheduler(
+		&MockDueTodoFinder{Todos: []*domain.Todo{afterHoliday, saturday}},
+		dispatcher,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ReminderOptions{Interval: time.Minute, Lead: 24 * time.Hour},
+	)
+	holidays := &MockHolidayStore{Holidays: map[time.Time]string{calendarDay(9): "Founders' Day"}}
+	scheduler.SetCalendar(NewBusinessCalendar(DefaultCalendarOptions(), holidays))
+
+	if err := scheduler.Scan(context.Background(), from, to); err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+
+	events := dispatcher.DispatchedEvents
+	if len(events) != 1 || events[0].EventType() != "TodoDueSoon" || events[0].AggregateID() != afterHoliday.ID().String() {
+		t.Errorf("dispatched %v, want TodoDueSoon for the todo due after the holiday", events)
+	}
+}
+
+func TestReminderScheduler_Scan_PagesThroughDueTodos(t *testing.T) {
+	from := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	finder := &MockDueTodoFinder{}
+	for i := 0; i < reminderBatchSize+10; i++ {
+		finder.Todos = append(finder.Todos, dueTestTodo(from.Add(time.Duration(i+1)*time.Second)))
+	}
+
+	dispatcher := &MockEventDispatcher{}
+	scheduler := NewReminderScheduler(finder, dispatcher, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ReminderOptions{Interval: time.Minute, Lead: 24 * time.Hour})
+
+	if err := scheduler.Scan(context.Background(), from, to); err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+	if len(dispatcher.DispatchedEvents) != reminderBatchSize+10 {
+		t.Errorf("dispatched %d events, want %d", len(dispatcher.DispatchedEvents), reminderBatchSize+10)
+	}
+}
+
+func TestReminderScheduler_Scan_DispatchFailure(t *testing.T) {
+	from := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	errBroker := errors.New("broker unavailable")
+
+	dispatcher := &MockEventDispatcher{
+		DispatchFunc: func(ctx context.Context, events []domain.DomainEvent) error {
+			return errBroker
+		},
+	}
+	scheduler := NewReminderScheduler(
+		&MockDueTodoFinder{Todos: []*domain.Todo{dueTestTodo(from.Add(time.Second))}},
+		dispatcher,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		DefaultReminderOptions(),
+	)
+
+	if err := scheduler.Scan(context.Background(), from, from.Add(time.Minute)); !errors.Is(err, errBroker) {
+		t.Errorf("Scan() error = %v, want %v", err, errBroker)
+	}
+}
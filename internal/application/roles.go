@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Roles carried by auth tokens, each granting the actions of the previous one
+const (
+	// RoleViewer may read and list todos
+	RoleViewer = "viewer"
+	// RoleEditor may also create and change todos
+	RoleEditor = "editor"
+	// RoleAdmin may also delete todos
+	RoleAdmin = "admin"
+)
+
+// roleActions lists the actions each role grants
+var roleActions = map[string][]string{
+	RoleViewer: {ActionRead, ActionList},
+	RoleEditor: {ActionRead, ActionList, ActionCreate, ActionUpdate, ActionComplete, ActionReopen, ActionMerge, ActionArchive, ActionMove},
+	RoleAdmin:  {ActionRead, ActionList, ActionCreate, ActionUpdate, ActionComplete, ActionReopen, ActionMerge, ActionArchive, ActionMove, ActionDelete},
+}
+
+// RoleAllows reports whether one of roles grants action
+// Unknown roles grant nothing
+func RoleAllows(roles []string, action string) bool {
+	for _, role := range roles {
+		if slices.Contains(roleActions[role], action) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRole denies action when the caller has roles and none grants it
+// Callers whose authentication carries no roles are left to the policy
+func checkRole(ctx context.Context, action string) error {
+	roles, ok := RolesFromContext(ctx)
+	if !ok || RoleAllows(roles, action) {
+		return nil
+	}
+	return fmt.Errorf("%s requires another role: %w", action, ErrForbidden)
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		roles  []string
+		action string
+		want   bool
+	}{
+		{[]string{RoleViewer}, ActionRead, true},
+		{[]string{RoleViewer}, ActionList, true},
+		{[]string{RoleViewer}, ActionCreate, false},
+		{[]string{RoleEditor}, ActionUpdate, true},
+		{[]string{RoleEditor}, ActionMerge, true},
+		{[]string{RoleEditor}, ActionArchive, true},
+		{[]string{RoleViewer}, ActionArchive, false},
+		{[]string{RoleEditor}, ActionMove, true},
+		{[]string{RoleViewer}, ActionMove, false},
+		{[]string{RoleEditor}, ActionDelete, false},
+		{[]string{RoleAdmin}, ActionDelete, true},
+		{[]string{RoleViewer, RoleAdmin}, ActionDelete, true},
+		{[]string{"owner"}, ActionRead, false},
+		{[]string{}, ActionRead, false},
+	}
+
+	for _, tt := range tests {
+		if got := RoleAllows(tt.roles, tt.action); got != tt.want {
+			t.Errorf("RoleAllows(%v, %s) = %v, want %v", tt.roles, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestTodoService_Roles_GateOperations(t *testing.T) {
+	testTodo := createTestTodo()
+	changed := false
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+		UpdateFunc: func(ctx context.Context, todo *domain.Todo) error {
+			changed = true
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, id domain.TodoID) error {
+			changed = true
+			return nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+	id := testTodo.ID().String()
+	title := "Renamed"
+
+	tests := []struct {
+		name    string
+		roles   []string
+		call    func(ctx context.Context) error
+		allowed bool
+	}{
+		{"viewer reads", []string{RoleViewer}, func(ctx context.Context) error {
+			_, err := service.GetTodo(ctx, id)
+			return err
+		}, true},
+		{"viewer updates", []string{RoleViewer}, func(ctx context.Context) error {
+			_, err := service.UpdateTodo(ctx, id, UpdateTodoRequest{Title: &title})
+			return err
+		}, false},
+		{"editor updates", []string{RoleEditor}, func(ctx context.Context) error {
+			_, err := service.UpdateTodo(ctx, id, UpdateTodoRequest{Title: &title})
+			return err
+		}, true},
+		{"editor deletes", []string{RoleEditor}, func(ctx context.Context) error {
+			return service.DeleteTodo(ctx, id)
+		}, false},
+		{"admin deletes", []string{RoleAdmin}, func(ctx context.Context) error {
+			return service.DeleteTodo(ctx, id)
+		}, true},
+		{"no role lists", []string{}, func(ctx context.Context) error {
+			_, err := service.ListTodos(ctx, ListFilters{})
+			return err
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed = false
+			err := tt.call(ContextWithRoles(context.Background(), tt.roles))
+			if tt.allowed && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.allowed {
+				if !errors.Is(err, ErrForbidden) {
+					t.Fatalf("error = %v, want ErrForbidden", err)
+				}
+				if changed {
+					t.Error("a denied operation changed the repository")
+				}
+			}
+		})
+	}
+}
+
+func TestTodoService_Roles_SubmittedToPolicy(t *testing.T) {
+	authorizer := &MockAuthorizer{Allow: true}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithAuthorizer(authorizer))
+
+	// Callers without roles are only subject to the policy
+	if _, err := service.ListTodos(context.Background(), ListFilters{}); err != nil {
+		t.Fatalf("ListTodos() without roles unexpected error: %v", err)
+	}
+	ctx := ContextWithRoles(context.Background(), []string{RoleViewer})
+	if _, err := service.ListTodos(ctx, ListFilters{}); err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+
+	if len(authorizer.Requests) != 2 {
+		t.Fatalf("Authorize() called %d times, want 2", len(authorizer.Requests))
+	}
+	if authorizer.Requests[0].Roles != nil || !slices.Equal(authorizer.Requests[1].Roles, []string{RoleViewer}) {
+		t.Errorf("request roles = %v then %v, want none then [viewer]", authorizer.Requests[0].Roles, authorizer.Requests[1].Roles)
+	}
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// Scheduling limits and defaults
+const (
+	// MaxScheduleSize is the largest number of open todos SuggestSchedule
+	// can go through
+	MaxScheduleSize = 5000
+	// MaxScheduleHorizonDays is the longest span SuggestSchedule schedules
+	MaxScheduleHorizonDays = 90
+	// DefaultScheduleHorizonDays is the span scheduled when none is given
+	DefaultScheduleHorizonDays = 28
+	// DefaultDailyCapacityMinutes is the work a working day holds when no
+	// capacity is given
+	DefaultDailyCapacityMinutes = 360
+	// DefaultEstimateMinutes is the work of a todo without estimate when no
+	// default is given
+	DefaultEstimateMinutes = 60
+)
+
+// priorityRanks ranks priorities by urgency, most urgent first
+var priorityRanks = map[domain.Priority]int{
+	domain.PriorityUrgent: 0,
+	domain.PriorityHigh:   1,
+	domain.PriorityMedium: 2,
+	domain.PriorityLow:    3,
+}
+
+// schedule is the work planned on the working days of a horizon, which
+// ends before end
+type schedule struct {
+	end       time.Time
+	days      []time.Time
+	capacity  int
+	committed []int
+	suggested []int
+}
+
+// commit loads the day of due, or the last working day before it, with
+// minutes of work already due then; work due before the horizon loads its
+// first day, and work due after it is ignored
+func (p *schedule) commit(due time.Time, minutes int) {
+	if !due.Before(p.end) {
+		return
+	}
+
+	i := sort.Search(len(p.days), func(i int) bool { return p.days[i].After(due) }) - 1
+	p.committed[max(i, 0)] += minutes
+}
+
+// fill books minutes of work on the free capacity of the days, from the
+// day at cursor on, and returns the day the work ends
+// It books nothing and returns false when the work does not fit in the
+// horizon
+func (p *schedule) fill(cursor, minutes int) (int, bool) {
+	booked := make(map[int]int)
+	for i := cursor; i < len(p.days); i++ {
+		free := p.capacity - p.committed[i] - p.suggested[i]
+		if free <= 0 {
+			continue
+		}
+		take := min(free, minutes)
+		booked[i] = take
+		if minutes -= take; minutes == 0 {
+			for day, taken := range booked {
+				p.suggested[day] += taken
+			}
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// SuggestSchedule proposes due dates for the undated open todos of the
+// caller, without changing them
+// Todos are scheduled most urgent first, then oldest first, one after the
+// other on the working days of the business calendar. Each day holds
+// DailyCapacityMinutes of work, less the estimates of the todos already due
+// that day; a todo is due at the end of the day its work ends. Todos without
+// an estimate count DefaultEstimateMinutes, and those that do not fit in the
+// horizon are listed as unscheduled. The suggestions can be accepted at once
+// with BatchUpdateTodos
+func (s *TodoApplicationService) SuggestSchedule(ctx context.Context, req SuggestScheduleRequest) (*SuggestScheduleResponse, error) {
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.businessCalendar().load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := newSchedule(req, calendar, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	estimates, err := s.resolveEstimates(ctx, req.Estimates)
+	if err != nil {
+		return nil, err
+	}
+	defaultEstimate := req.DefaultEstimateMinutes
+	switch {
+	case defaultEstimate < 0:
+		return nil, domain.NewValidationError("default_estimate_minutes", "must not be negative")
+	case defaultEstimate == 0:
+		defaultEstimate = DefaultEstimateMinutes
+	}
+	estimate := func(todo *domain.Todo) int {
+		if minutes, ok := estimates[todo.ID()]; ok {
+			return minutes
+		}
+		return defaultEstimate
+	}
+
+	todos, more, err := s.findOpenTodos(ctx, MaxScheduleSize)
+	if err != nil {
+		return nil, err
+	}
+	if more {
+		return nil, domain.NewValidationError("todos", fmt.Sprintf("more than %d open todos to schedule", MaxScheduleSize))
+	}
+
+	var undated []*domain.Todo
+	for _, todo := range todos {
+		if todo.DueDate() == nil {
+			undated = append(undated, todo)
+			continue
+		}
+		plan.commit(todo.DueDate().Time().In(calendar.options.Location), estimate(todo))
+	}
+	sort.SliceStable(undated, func(i, j int) bool {
+		if rank, other := priorityRanks[undated[i].Priority()], priorityRanks[undated[j].Priority()]; rank != other {
+			return rank < other
+		}
+		if !undated[i].CreatedAt().Equal(undated[j].CreatedAt()) {
+			return undated[i].CreatedAt().Before(undated[j].CreatedAt())
+		}
+		return undated[i].ID() < undated[j].ID()
+	})
+
+	response := &SuggestScheduleResponse{Suggestions: []ScheduleSuggestion{}, Unscheduled: []string{}}
+	cursor := 0
+	for _, todo := range undated {
+		minutes := estimate(todo)
+		end, ok := plan.fill(cursor, minutes)
+		if !ok {
+			response.Unscheduled = append(response.Unscheduled, todo.ID().String())
+			continue
+		}
+		cursor = end
+		response.Suggestions = append(response.Suggestions, ScheduleSuggestion{
+			TodoID:          todo.ID().String(),
+			Title:           todo.Title().String(),
+			Priority:        todo.Priority().String(),
+			EstimateMinutes: minutes,
+			DueDate:         plan.days[end].AddDate(0, 0, 1).Add(-time.Second),
+		})
+	}
+
+	response.Days = make([]ScheduleDay, len(plan.days))
+	for i, day := range plan.days {
+		response.Days[i] = ScheduleDay{Day: day.Format(calendarDayLayout), Committed: plan.committed[i], Suggested: plan.suggested[i]}
+	}
+
+	return response, nil
+}
+
+// newSchedule validates the span and capacity of req and returns an empty
+// schedule of the working days of its horizon, starting today by default
+func newSchedule(req SuggestScheduleRequest, calendar workingDays, now time.Time) (*schedule, error) {
+	location := calendar.options.Location
+	year, month, day := now.In(location).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, location)
+
+	start := today
+	if req.Start != "" {
+		var err error
+		start, err = time.ParseInLocation(calendarDayLayout, req.Start, location)
+		if err != nil {
+			return nil, domain.NewValidationError("start", "must be a day as YYYY-MM-DD")
+		}
+		if start.Before(today) {
+			return nil, domain.NewValidationError("start", "must not be in the past")
+		}
+	}
+
+	horizon := req.HorizonDays
+	switch {
+	case horizon < 0 || horizon > MaxScheduleHorizonDays:
+		return nil, domain.NewValidationError("horizon_days", fmt.Sprintf("must be between 1 and %d", MaxScheduleHorizonDays))
+	case horizon == 0:
+		horizon = DefaultScheduleHorizonDays
+	}
+
+	capacity := req.DailyCapacityMinutes
+	switch {
+	case capacity < 0 || capacity > 24*60:
+		return nil, domain.NewValidationError("daily_capacity_minutes", "must be between 1 and 1440")
+	case capacity == 0:
+		capacity = DefaultDailyCapacityMinutes
+	}
+
+	plan := &schedule{end: start.AddDate(0, 0, horizon), capacity: capacity}
+	for day := start; day.Before(plan.end); day = day.AddDate(0, 0, 1) {
+		if calendar.isWorkingDay(day) {
+			plan.days = append(plan.days, day)
+		}
+	}
+	if len(plan.days) == 0 {
+		return nil, domain.NewValidationError("horizon_days", "the horizon has no working day")
+	}
+	plan.committed = make([]int, len(plan.days))
+	plan.suggested = make([]int, len(plan.days))
+
+	return plan, nil
+}
+
+// resolveEstimates validates the estimates of a SuggestScheduleRequest and
+// keys them by todo ID
+func (s *TodoApplicationService) resolveEstimates(ctx context.Context, estimates map[string]int) (map[domain.TodoID]int, error) {
+	resolved := make(map[domain.TodoID]int, len(estimates))
+	for id, minutes := range estimates {
+		field := fmt.Sprintf("estimates[%s]", id)
+		if minutes <= 0 {
+			return nil, domain.NewValidationError(field, "must be a positive number of minutes")
+		}
+		todoID, err := s.resolveTodoID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid todo ID %s: %w", id, err)
+		}
+		resolved[todoID] = minutes
+	}
+	return resolved, nil
+}
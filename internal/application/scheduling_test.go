@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// scheduledTodo returns an open todo of priority created age ago, due at
+// dueDate unless nil
+func scheduledTodo(priority domain.Priority, age time.Duration, dueDate *time.Time) *domain.Todo {
+	title, _ := domain.NewTaskTitle("Scheduled")
+	var due *domain.DueDate
+	if dueDate != nil {
+		d := domain.ReconstituteDueDate(*dueDate)
+		due = &d
+	}
+	now := time.Now()
+	return domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusPending, priority, due, now.Add(-age), now, nil)
+}
+
+func newSchedulingService(holidays *MockHolidayStore, todos ...*domain.Todo) *TodoApplicationService {
+	repo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			var found []*domain.Todo
+			for _, todo := range todos {
+				if todo.Status() == *filters.Status {
+					found = append(found, todo)
+				}
+			}
+			return found, nil
+		},
+	}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	calendar := NewBusinessCalendar(CalendarOptions{WorkingDays: weekdays, Location: time.UTC}, holidays)
+	return NewTodoApplicationService(repo, &MockEventDispatcher{}, WithBusinessCalendar(calendar))
+}
+
+func TestTodoService_SuggestSchedule(t *testing.T) {
+	monday := nextWorkingDay(0)
+	dueMonday := monday.Add(12 * time.Hour)
+	committed := scheduledTodo(domain.PriorityLow, time.Hour, &dueMonday)
+	urgent := scheduledTodo(domain.PriorityUrgent, time.Hour, nil)
+	huge := scheduledTodo(domain.PriorityHigh, time.Hour, nil)
+	older := scheduledTodo(domain.PriorityMedium, 2*time.Hour, nil)
+	newer := scheduledTodo(domain.PriorityMedium, time.Hour, nil)
+	holidays := &MockHolidayStore{Holidays: map[time.Time]string{monday.AddDate(0, 0, 2): "Harvest Festival"}}
+	service := newSchedulingService(holidays, newer, committed, huge, urgent, older)
+
+	resp, err := service.SuggestSchedule(context.Background(), SuggestScheduleRequest{
+		Start:                monday.Format("2006-01-02"),
+		HorizonDays:          7,
+		DailyCapacityMinutes: 120,
+		Estimates: map[string]int{
+			committed.ID().String(): 60,
+			urgent.ID().String():    90,
+			huge.ID().String():      10000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("SuggestSchedule() unexpected error: %v", err)
+	}
+
+	// Monday holds 60 free minutes, Wednesday is a holiday
+	endOf := func(day time.Time) time.Time { return day.AddDate(0, 0, 1).Add(-time.Second) }
+	want := []struct {
+		todo    *domain.Todo
+		dueDate time.Time
+	}{
+		{urgent, endOf(monday.AddDate(0, 0, 1))},
+		{older, endOf(monday.AddDate(0, 0, 1))},
+		{newer, endOf(monday.AddDate(0, 0, 3))},
+	}
+	if len(resp.Suggestions) != len(want) {
+		t.Fatalf("Suggestions = %+v, want %d", resp.Suggestions, len(want))
+	}
+	for i, w := range want {
+		suggestion := resp.Suggestions[i]
+		if suggestion.TodoID != w.todo.ID().String() || !suggestion.DueDate.Equal(w.dueDate) {
+			t.Errorf("suggestion %d = %+v, want %s due %v", i, suggestion, w.todo.ID(), w.dueDate)
+		}
+	}
+	if len(resp.Unscheduled) != 1 || resp.Unscheduled[0] != huge.ID().String() {
+		t.Errorf("Unscheduled = %v, want the huge todo", resp.Unscheduled)
+	}
+
+	wantDays := []ScheduleDay{
+		{Day: monday.Format("2006-01-02"), Committed: 60, Suggested: 60},
+		{Day: monday.AddDate(0, 0, 1).Format("2006-01-02"), Suggested: 120},
+		{Day: monday.AddDate(0, 0, 3).Format("2006-01-02"), Suggested: 30},
+		{Day: monday.AddDate(0, 0, 4).Format("2006-01-02")},
+	}
+	if len(resp.Days) != len(wantDays) {
+		t.Fatalf("Days = %+v, want %+v", resp.Days, wantDays)
+	}
+	for i, day := range wantDays {
+		if resp.Days[i] != day {
+			t.Errorf("day %d = %+v, want %+v", i, resp.Days[i], day)
+		}
+	}
+	if urgent.DueDate() != nil {
+		t.Error("SuggestSchedule() set a due date, want the todos unchanged")
+	}
+}
+
+func TestTodoService_SuggestSchedule_Errors(t *testing.T) {
+	todo := scheduledTodo(domain.PriorityMedium, time.Hour, nil)
+	monday := nextWorkingDay(0).Format("2006-01-02")
+
+	tests := []struct {
+		name    string
+		req     SuggestScheduleRequest
+		wantErr error
+	}{
+		{name: "not a day", req: SuggestScheduleRequest{Start: "next monday"}},
+		{name: "past start", req: SuggestScheduleRequest{Start: "2020-01-06"}},
+		{name: "horizon too long", req: SuggestScheduleRequest{HorizonDays: MaxScheduleHorizonDays + 1}},
+		{name: "no working day", req: SuggestScheduleRequest{Start: nextWorkingDay(-2).Format("2006-01-02"), HorizonDays: 2}},
+		{name: "capacity over a day", req: SuggestScheduleRequest{Start: monday, DailyCapacityMinutes: 1441}},
+		{name: "negative default estimate", req: SuggestScheduleRequest{Start: monday, DefaultEstimateMinutes: -1}},
+		{name: "empty estimate", req: SuggestScheduleRequest{Start: monday, Estimates: map[string]int{todo.ID().String(): 0}}},
+		{name: "invalid todo ID", req: SuggestScheduleRequest{Start: monday, Estimates: map[string]int{"nope": 30}}, wantErr: domain.ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newSchedulingService(&MockHolidayStore{}, todo)
+
+			_, err := service.SuggestSchedule(context.Background(), tt.req)
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("SuggestSchedule() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("SuggestSchedule() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Search limits
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// MaxSearchQueryLength bounds the keywords of a single search
+	MaxSearchQueryLength = 200
+)
+
+// WithSearcher enables SearchTodos
+func WithSearcher(searcher ports.TodoSearcher) Option {
+	return func(s *TodoApplicationService) {
+		s.searcher = searcher
+	}
+}
+
+// SearchTodos finds todos by keywords in their title or description, best
+// match first
+// Larger limits are capped to MaxSearchLimit
+func (s *TodoApplicationService) SearchTodos(ctx context.Context, req SearchTodosRequest) (*SearchTodosResponse, error) {
+	if s.searcher == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, domain.NewValidationError("query", "cannot be empty")
+	}
+	if len(query) > MaxSearchQueryLength {
+		return nil, domain.NewValidationError("query", fmt.Sprintf("cannot exceed %d characters", MaxSearchQueryLength))
+	}
+	if req.Limit < 0 || req.Offset < 0 {
+		return nil, domain.NewValidationError("page", "limit and offset cannot be negative")
+	}
+	if s.queryGuard != nil {
+		if err := s.queryGuard.guardSearch(ctx, query, req.Offset); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := req.Limit
+	switch {
+	case limit == 0:
+		limit = DefaultSearchLimit
+	case limit > MaxSearchLimit:
+		limit = MaxSearchLimit
+	}
+
+	// One extra result tells whether there is a next page
+	todos, err := s.searcher.Search(ctx, query, limit+1, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("searching todos: %w", err)
+	}
+
+	response := &SearchTodosResponse{}
+	if len(todos) > limit {
+		todos = todos[:limit]
+		response.NextOffset = req.Offset + limit
+	}
+
+	response.Todos = make([]*TodoResponse, len(todos))
+	for i, todo := range todos {
+		response.Todos[i] = MapTodoToResponse(todo)
+	}
+
+	return response, nil
+}
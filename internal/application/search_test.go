@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoSearcher pages through Todos and records the arguments of Search
+type MockTodoSearcher struct {
+	Todos     []*domain.Todo
+	GotQuery  string
+	GotLimit  int
+	GotOffset int
+}
+
+func (m *MockTodoSearcher) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Todo, error) {
+	m.GotQuery, m.GotLimit, m.GotOffset = query, limit, offset
+	if offset >= len(m.Todos) {
+		return nil, nil
+	}
+	return m.Todos[offset:min(offset+limit, len(m.Todos))], nil
+}
+
+func TestTodoService_SearchTodos_Pages(t *testing.T) {
+	searcher := &MockTodoSearcher{Todos: []*domain.Todo{createTestTodo(), createTestTodo(), createTestTodo()}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithSearcher(searcher))
+	ctx := context.Background()
+
+	first, err := service.SearchTodos(ctx, SearchTodosRequest{Query: " invoice ", Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchTodos() unexpected error: %v", err)
+	}
+	if searcher.GotQuery != "invoice" {
+		t.Errorf("Search() query = %q, want %q", searcher.GotQuery, "invoice")
+	}
+	if len(first.Todos) != 2 || first.NextOffset != 2 {
+		t.Fatalf("first page = %d todos, next offset %d, want 2 and 2", len(first.Todos), first.NextOffset)
+	}
+
+	last, err := service.SearchTodos(ctx, SearchTodosRequest{Query: "invoice", Limit: 2, Offset: first.NextOffset})
+	if err != nil {
+		t.Fatalf("SearchTodos() unexpected error: %v", err)
+	}
+	if len(last.Todos) != 1 || last.NextOffset != 0 {
+		t.Errorf("last page = %d todos, next offset %d, want 1 and 0", len(last.Todos), last.NextOffset)
+	}
+	if last.Todos[0].ID != searcher.Todos[2].ID().String() {
+		t.Errorf("last page = %s, want %v", last.Todos[0].ID, searcher.Todos[2].ID())
+	}
+
+	if _, err := service.SearchTodos(ctx, SearchTodosRequest{Query: "invoice"}); err != nil || searcher.GotLimit != DefaultSearchLimit+1 {
+		t.Errorf("default limit searched %d, want %d", searcher.GotLimit, DefaultSearchLimit+1)
+	}
+}
+
+func TestTodoService_SearchTodos_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.SearchTodos(ctx, SearchTodosRequest{Query: "invoice"}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("without searcher error = %v, want %v", err, ErrNotSupported)
+	}
+
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithSearcher(&MockTodoSearcher{}))
+	tests := []struct {
+		name string
+		req  SearchTodosRequest
+	}{
+		{"empty query", SearchTodosRequest{Query: "  "}},
+		{"long query", SearchTodosRequest{Query: strings.Repeat("a", MaxSearchQueryLength+1)}},
+		{"negative offset", SearchTodosRequest{Query: "invoice", Offset: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validationErr domain.ValidationError
+			if _, err := service.SearchTodos(ctx, tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+		})
+	}
+}
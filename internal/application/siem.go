@@ -0,0 +1,283 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Severities of the security records, on the CEF scale
+const (
+	// auditSeverity is the severity of the privileged actions
+	auditSeverity = 5
+	// anomalySeverity is the severity of the security anomalies
+	anomalySeverity = 8
+)
+
+// SIEM export retries
+const (
+	// siemInitialBackoff is the delay before exporting a batch again
+	siemInitialBackoff = time.Second
+	// siemMaxBackoff bounds the delay between two attempts
+	siemMaxBackoff = time.Minute
+	// siemShutdownTimeout bounds the export of the records left at shutdown
+	siemShutdownTimeout = 5 * time.Second
+)
+
+// SIEMOptions controls the buffering of the SIEMForwarder
+type SIEMOptions struct {
+	// BufferSize is the number of records held while the SIEM is slow or
+	// unreachable; the records forwarded once it is full are dropped
+	BufferSize int
+	// BatchSize is the largest number of records exported at once
+	BatchSize int
+	// FlushInterval is the longest a record waits for its batch to fill
+	FlushInterval time.Duration
+}
+
+// DefaultSIEMOptions buffers 10000 records and exports them by 100, at
+// least every 5 seconds
+func DefaultSIEMOptions() SIEMOptions {
+	return SIEMOptions{
+		BufferSize:    10000,
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+	}
+}
+
+// SIEMForwarder exports the audit entries and the security anomalies to a
+// SIEM, append-only and in order
+// Records are buffered so that recording an action never waits for the
+// SIEM: a failed batch is retried with backoff while new records queue up,
+// and those arriving once the buffer is full are dropped and counted
+type SIEMForwarder struct {
+	exporter   ports.SecurityExporter
+	subscriber ports.EventSubscriber
+	logger     *slog.Logger
+	options    SIEMOptions
+	records    chan ports.SecurityRecord
+	runs       *JobTracker
+	dropping   atomic.Bool
+	backoff    time.Duration
+	now        func() time.Time
+
+	events <-chan domain.DomainEvent
+}
+
+// NewSIEMForwarder creates a new SIEMForwarder
+// subscriber may be nil, which forwards the audit entries only
+func NewSIEMForwarder(
+	exporter ports.SecurityExporter,
+	subscriber ports.EventSubscriber,
+	logger *slog.Logger,
+	options SIEMOptions,
+) *SIEMForwarder {
+	return &SIEMForwarder{
+		exporter:   exporter,
+		subscriber: subscriber,
+		logger:     logger,
+		options:    options,
+		records:    make(chan ports.SecurityRecord, options.BufferSize),
+		runs:       NewJobTracker("siem_export", options.FlushInterval),
+		backoff:    siemInitialBackoff,
+		now:        time.Now,
+	}
+}
+
+// Runs returns the tracker of the exports made by Run; its counters are the
+// records exported and dropped
+func (f *SIEMForwarder) Runs() *JobTracker {
+	return f.runs
+}
+
+// AuditLog returns an audit log recording the entries to log, then
+// forwarding those recorded
+func (f *SIEMForwarder) AuditLog(log ports.AuditLog) ports.AuditLog {
+	return &forwardedAuditLog{log: log, forwarder: f}
+}
+
+// Forward queues record for export without waiting, and reports whether
+// there was room for it in the buffer
+func (f *SIEMForwarder) Forward(record ports.SecurityRecord) bool {
+	select {
+	case f.records <- record:
+		return true
+	default:
+	}
+
+	f.runs.Count("dropped", 1)
+	if !f.dropping.Swap(true) {
+		f.logger.Warn("SIEM buffer full, dropping security records until it drains", "buffer_size", f.options.BufferSize)
+	}
+	return false
+}
+
+// Run exports the forwarded records and the security anomalies dispatched
+// until ctx is done, then makes a last attempt at exporting those left
+func (f *SIEMForwarder) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.options.FlushInterval)
+	defer ticker.Stop()
+
+	f.events = f.subscribe(ctx)
+	batch := make([]ports.SecurityRecord, 0, f.options.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			f.shutdown(batch)
+			return
+		case event, ok := <-f.events:
+			f.observe(ctx, event, ok)
+		case record := <-f.records:
+			batch = append(batch, record)
+			if len(batch) >= f.options.BatchSize {
+				batch = f.export(ctx, batch)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				batch = f.export(ctx, batch)
+			}
+		}
+	}
+}
+
+// export sends batch until it is received or ctx is done, and returns the
+// records left to send
+func (f *SIEMForwarder) export(ctx context.Context, batch []ports.SecurityRecord) []ports.SecurityRecord {
+	backoff := f.backoff
+	for {
+		err := f.exporter.Export(ctx, batch)
+		f.runs.Record(f.now(), err)
+		if err == nil {
+			f.runs.Count("exported", int64(len(batch)))
+			f.dropping.Store(false)
+			return batch[:0]
+		}
+		if ctx.Err() != nil {
+			return batch
+		}
+
+		f.logger.Error("exporting security records to the SIEM failed",
+			"records", len(batch), "retry_in", backoff, "error", err)
+		if !f.wait(ctx, backoff) {
+			return batch
+		}
+		backoff = min(2*backoff, siemMaxBackoff)
+	}
+}
+
+// wait waits for delay, still receiving the anomalies dispatched, and
+// reports whether ctx is not done
+func (f *SIEMForwarder) wait(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return true
+		case event, ok := <-f.events:
+			f.observe(ctx, event, ok)
+		}
+	}
+}
+
+// shutdown makes a last attempt at exporting batch and the buffered
+// records, counting those it cannot send as dropped
+func (f *SIEMForwarder) shutdown(batch []ports.SecurityRecord) {
+	for drained := false; !drained; {
+		select {
+		case record := <-f.records:
+			batch = append(batch, record)
+		default:
+			drained = true
+		}
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), siemShutdownTimeout)
+	defer cancel()
+	for len(batch) > 0 {
+		n := min(len(batch), f.options.BatchSize)
+		err := f.exporter.Export(ctx, batch[:n])
+		f.runs.Record(f.now(), err)
+		if err != nil {
+			f.runs.Count("dropped", int64(len(batch)))
+			f.logger.Error("exporting security records to the SIEM at shutdown failed, dropping them",
+				"records", len(batch), "error", err)
+			return
+		}
+		f.runs.Count("exported", int64(n))
+		batch = batch[n:]
+	}
+}
+
+// subscribe subscribes to the dispatched events, if there is a subscriber;
+// a nil channel is never ready
+func (f *SIEMForwarder) subscribe(ctx context.Context) <-chan domain.DomainEvent {
+	if f.subscriber == nil {
+		return nil
+	}
+	return f.subscriber.Subscribe(ctx)
+}
+
+// observe forwards event when it is a security anomaly, and resubscribes
+// when the subscription was dropped for falling behind
+func (f *SIEMForwarder) observe(ctx context.Context, event domain.DomainEvent, ok bool) {
+	if !ok {
+		f.events = nil
+		if ctx.Err() == nil {
+			f.logger.Warn("SIEM forwarder fell behind the events, resubscribing")
+			f.events = f.subscribe(ctx)
+		}
+		return
+	}
+
+	anomaly, isAnomaly := event.(SecurityAnomalyDetected)
+	if !isAnomaly {
+		return
+	}
+	f.Forward(ports.SecurityRecord{
+		Category:   ports.SecurityCategoryAnomaly,
+		Name:       anomaly.Kind,
+		Severity:   anomalySeverity,
+		Actor:      anomaly.Actor,
+		TodoID:     anomaly.AggregateID(),
+		Message:    anomaly.Detail,
+		OccurredAt: anomaly.OccurredAt(),
+	})
+}
+
+// forwardedAuditLog is an audit log forwarding the entries it records to a
+// SIEMForwarder
+type forwardedAuditLog struct {
+	log       ports.AuditLog
+	forwarder *SIEMForwarder
+}
+
+// Record records entry, then forwards it; an entry that could not be
+// recorded is not forwarded, as the action it audits is refused
+func (l *forwardedAuditLog) Record(ctx context.Context, entry ports.AuditEntry) error {
+	if err := l.log.Record(ctx, entry); err != nil {
+		return err
+	}
+
+	l.forwarder.Forward(ports.SecurityRecord{
+		Category:   ports.SecurityCategoryAudit,
+		Name:       entry.Action,
+		Severity:   auditSeverity,
+		Actor:      entry.Actor,
+		TodoID:     entry.TodoID,
+		Message:    entry.Reason,
+		Changes:    entry.Changes,
+		OccurredAt: entry.OccurredAt,
+	})
+	return nil
+}
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// fakeSecurityExporter records the batches exported, failing the first
+// Failures attempts
+type fakeSecurityExporter struct {
+	mu       sync.Mutex
+	Failures int
+	Batches  [][]ports.SecurityRecord
+	Exported chan struct{}
+}
+
+func (e *fakeSecurityExporter) Export(ctx context.Context, records []ports.SecurityRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.Failures > 0 {
+		e.Failures--
+		return errors.New("SIEM unreachable")
+	}
+	e.Batches = append(e.Batches, append([]ports.SecurityRecord(nil), records...))
+	if e.Exported != nil {
+		e.Exported <- struct{}{}
+	}
+	return nil
+}
+
+func (e *fakeSecurityExporter) records() []ports.SecurityRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var records []ports.SecurityRecord
+	for _, batch := range e.Batches {
+		records = append(records, batch...)
+	}
+	return records
+}
+
+func newTestSIEMForwarder(exporter ports.SecurityExporter, subscriber ports.EventSubscriber, options SIEMOptions) *SIEMForwarder {
+	forwarder := NewSIEMForwarder(exporter, subscriber, slog.New(slog.NewTextHandler(io.Discard, nil)), options)
+	forwarder.backoff = time.Millisecond
+	return forwarder
+}
+
+func TestSIEMForwarder_AuditLog(t *testing.T) {
+	forwarder := newTestSIEMForwarder(&fakeSecurityExporter{}, nil, SIEMOptions{BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	recorder := &MockAuditLog{}
+	auditLog := forwarder.AuditLog(recorder)
+	at := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+
+	entry := ports.AuditEntry{
+		Action:     AuditActionForceUpdate,
+		TodoID:     "todo-1",
+		Actor:      "ops",
+		Reason:     "Fix",
+		Changes:    map[string]string{"status": "pending"},
+		OccurredAt: at,
+	}
+	if err := auditLog.Record(context.Background(), entry); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+	if len(recorder.Entries) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(recorder.Entries))
+	}
+
+	// An entry that could not be recorded is not forwarded
+	recorder.RecordFunc = func(ctx context.Context, entry ports.AuditEntry) error {
+		return errors.New("database down")
+	}
+	if err := auditLog.Record(context.Background(), entry); err == nil {
+		t.Fatal("Record() expected the error of the audit log")
+	}
+
+	if len(forwarder.records) != 1 {
+		t.Fatalf("forwarded %d records, want 1", len(forwarder.records))
+	}
+	record := <-forwarder.records
+	want := ports.SecurityRecord{
+		Category:   ports.SecurityCategoryAudit,
+		Name:       AuditActionForceUpdate,
+		Severity:   auditSeverity,
+		Actor:      "ops",
+		TodoID:     "todo-1",
+		Message:    "Fix",
+		Changes:    map[string]string{"status": "pending"},
+		OccurredAt: at,
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("forwarded %+v, want %+v", record, want)
+	}
+}
+
+func TestSIEMForwarder_Forward_BufferFull(t *testing.T) {
+	forwarder := newTestSIEMForwarder(&fakeSecurityExporter{}, nil, SIEMOptions{BufferSize: 2, BatchSize: 10, FlushInterval: time.Hour})
+
+	var accepted []bool
+	for range 3 {
+		accepted = append(accepted, forwarder.Forward(ports.SecurityRecord{Name: "purge"}))
+	}
+
+	if !accepted[0] || !accepted[1] || accepted[2] {
+		t.Errorf("Forward() = %v, want the record beyond the buffer dropped", accepted)
+	}
+	if dropped := forwarder.Runs().Status().Counters["dropped"]; dropped != 1 {
+		t.Errorf("dropped counter = %d, want 1", dropped)
+	}
+}
+
+func TestSIEMForwarder_Run(t *testing.T) {
+	events := make(chan domain.DomainEvent, 2)
+	exporter := &fakeSecurityExporter{Failures: 1, Exported: make(chan struct{}, 10)}
+	forwarder := newTestSIEMForwarder(exporter, &MockEventSubscriber{Events: events},
+		SIEMOptions{BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		forwarder.Run(ctx)
+	}()
+
+	// Only the anomalies of the events are forwarded
+	events <- domain.NewTodoDeletedEvent(domain.NewTodoID(), "")
+	events <- SecurityAnomalyDetected{Kind: AnomalyMassDeletion, Detail: "51 todos deleted within 5m0s"}
+	forwarder.Forward(ports.SecurityRecord{Category: ports.SecurityCategoryAudit, Name: AuditActionPurge})
+
+	// The full batch is exported, after a failed attempt
+	select {
+	case <-exporter.Exported:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the batch was not exported")
+	}
+
+	// The records left are exported at shutdown
+	forwarder.Forward(ports.SecurityRecord{Category: ports.SecurityCategoryAudit, Name: AuditActionLegalHold})
+	cancel()
+	<-done
+
+	records := exporter.records()
+	if len(records) != 3 {
+		t.Fatalf("exported %d records, want 3", len(records))
+	}
+	names := map[string]bool{}
+	for _, record := range records {
+		names[record.Name] = true
+	}
+	for _, name := range []string{AnomalyMassDeletion, AuditActionPurge, AuditActionLegalHold} {
+		if !names[name] {
+			t.Errorf("record %s was not exported, got %+v", name, records)
+		}
+	}
+	if len(exporter.Batches[0]) != 2 {
+		t.Errorf("first batch has %d records, want BatchSize", len(exporter.Batches[0]))
+	}
+
+	status := forwarder.Runs().Status()
+	if status.Failures != 1 || status.Counters["exported"] != 3 || status.Counters["dropped"] != 0 {
+		t.Errorf("status = %+v, want 1 failure and 3 records exported", status)
+	}
+}
+
+func TestSIEMForwarder_Run_ShutdownFailure(t *testing.T) {
+	exporter := &fakeSecurityExporter{Failures: 1}
+	forwarder := newTestSIEMForwarder(exporter, nil, SIEMOptions{BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	forwarder.Forward(ports.SecurityRecord{Name: AuditActionPurge})
+	forwarder.Forward(ports.SecurityRecord{Name: AuditActionPurge})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	forwarder.Run(ctx)
+
+	if dropped := forwarder.Runs().Status().Counters["dropped"]; dropped != 2 {
+		t.Errorf("dropped counter = %d, want the 2 records left", dropped)
+	}
+}
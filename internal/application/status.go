@@ -0,0 +1,248 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/pkg/lru"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// JobStatus reports the runs of a background job
+type JobStatus struct {
+	Name     string
+	Interval time.Duration
+	// LastRunAt is when the last run ended, nil before the first one
+	LastRunAt *time.Time
+	// LastSuccessAt is when the last successful run ended
+	LastSuccessAt *time.Time
+	// LastError is the error of the last run, empty if it succeeded
+	LastError string
+	Runs      int64
+	Failures  int64
+	// Counters are the totals added by the job with Count, nil before the
+	// first one
+	Counters map[string]int64
+}
+
+// JobTracker records the runs of a background job; it is safe for
+// concurrent use
+type JobTracker struct {
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// NewJobTracker creates a JobTracker for the job name running every interval
+func NewJobTracker(name string, interval time.Duration) *JobTracker {
+	return &JobTracker{status: JobStatus{Name: name, Interval: interval}}
+}
+
+// SetInterval records that the job now runs every interval
+func (t *JobTracker) SetInterval(interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.status.Interval = interval
+}
+
+// Record records a run ended at, failed when err is not nil
+func (t *JobTracker) Record(at time.Time, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.status.Runs++
+	t.status.LastRunAt = &at
+	if err != nil {
+		t.status.Failures++
+		t.status.LastError = err.Error()
+		return
+	}
+	t.status.LastSuccessAt = &at
+	t.status.LastError = ""
+}
+
+// Count adds n to the counter name
+func (t *JobTracker) Count(name string, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status.Counters == nil {
+		t.status.Counters = make(map[string]int64)
+	}
+	t.status.Counters[name] += n
+}
+
+// Status returns the runs recorded so far
+func (t *JobTracker) Status() JobStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status := t.status
+	if t.status.Counters != nil {
+		status.Counters = make(map[string]int64, len(t.status.Counters))
+		for name, n := range t.status.Counters {
+			status.Counters[name] = n
+		}
+	}
+	return status
+}
+
+// OutboxStatus reports the domain events waiting to be published
+// Lag is the age of the oldest pending event; Error is set, and the other
+// fields left empty, when the backlog could not be read
+type OutboxStatus struct {
+	Pending         int
+	OldestPendingAt *time.Time
+	Lag             time.Duration
+	Error           string
+}
+
+// EventDeliveryStatus reports the delivery of the domain events to the
+// broker; while it is Degraded, writes succeed and their events wait in the
+// outbox
+type EventDeliveryStatus struct {
+	ports.EventDeliveryStats
+	// DegradedFor is how long the broker has been unavailable
+	DegradedFor time.Duration
+}
+
+// CacheStatus reports the size and the lookups of an in-process cache
+// HitRate is zero before the first lookup
+type CacheStatus struct {
+	Name     string
+	Entries  int
+	Capacity int
+	Hits     uint64
+	Misses   uint64
+	HitRate  float64
+}
+
+// PoolStatus reports the use of the database connection pool
+// Saturation is the share of MaxConns in use, from 0 to 1
+type PoolStatus struct {
+	ports.PoolStats
+	Saturation float64
+}
+
+// SystemStatus is a snapshot of the state of this instance for on-call
+// triage; sections whose source is not configured are nil
+type SystemStatus struct {
+	GeneratedAt   time.Time
+	Maintenance   bool
+	Jobs          []JobStatus
+	Outbox        *OutboxStatus
+	Events        *EventDeliveryStatus
+	Caches        []CacheStatus
+	Pool          *PoolStatus
+	ActiveStreams *int
+}
+
+// CacheReporter reports an in-process cache, false when it is disabled
+type CacheReporter interface {
+	CacheStatus() (CacheStatus, bool)
+}
+
+// StatusSources lists what a SystemStatusReporter reports on
+// Every field is optional
+type StatusSources struct {
+	Maintenance *MaintenanceMode
+	Jobs        []*JobTracker
+	Outbox      ports.OutboxMonitor
+	Events      ports.EventDeliveryMonitor
+	Caches      []CacheReporter
+	Pool        ports.PoolMonitor
+	Streams     ports.StreamCounter
+}
+
+// SystemStatusReporter gathers the state of the instance in one report
+type SystemStatusReporter struct {
+	sources StatusSources
+}
+
+// NewSystemStatusReporter creates a SystemStatusReporter over sources
+func NewSystemStatusReporter(sources StatusSources) *SystemStatusReporter {
+	return &SystemStatusReporter{sources: sources}
+}
+
+// SystemStatus returns the current state of the instance
+// A failing source is reported in its section rather than failing the
+// report, since the report is most needed when something is failing
+func (r *SystemStatusReporter) SystemStatus(ctx context.Context) (*SystemStatus, error) {
+	now := time.Now()
+	status := &SystemStatus{GeneratedAt: now, Jobs: []JobStatus{}, Caches: []CacheStatus{}}
+
+	if r.sources.Maintenance != nil {
+		status.Maintenance, _ = r.sources.Maintenance.Status()
+	}
+
+	for _, job := range r.sources.Jobs {
+		status.Jobs = append(status.Jobs, job.Status())
+	}
+
+	if r.sources.Outbox != nil {
+		status.Outbox = &OutboxStatus{}
+		backlog, err := r.sources.Outbox.OutboxBacklog(ctx)
+		switch {
+		case err != nil:
+			status.Outbox.Error = err.Error()
+		default:
+			status.Outbox.Pending = backlog.Pending
+			status.Outbox.OldestPendingAt = backlog.OldestPendingAt
+			if backlog.OldestPendingAt != nil {
+				status.Outbox.Lag = now.Sub(*backlog.OldestPendingAt)
+			}
+		}
+	}
+
+	if r.sources.Events != nil {
+		status.Events = &EventDeliveryStatus{EventDeliveryStats: r.sources.Events.EventDeliveryStats()}
+		if since := status.Events.DegradedSince; since != nil {
+			status.Events.DegradedFor = now.Sub(*since)
+		}
+	}
+
+	for _, cache := range r.sources.Caches {
+		if cacheStatus, ok := cache.CacheStatus(); ok {
+			status.Caches = append(status.Caches, cacheStatus)
+		}
+	}
+
+	if r.sources.Pool != nil {
+		stats := r.sources.Pool.PoolStats()
+		status.Pool = &PoolStatus{PoolStats: stats}
+		if stats.MaxConns > 0 {
+			status.Pool.Saturation = float64(stats.AcquiredConns) / float64(stats.MaxConns)
+		}
+	}
+
+	if r.sources.Streams != nil {
+		streams := r.sources.Streams.ActiveStreams()
+		status.ActiveStreams = &streams
+	}
+
+	return status, nil
+}
+
+// newCacheStatus reports the stats of the cache name
+func newCacheStatus(name string, stats lru.Stats) CacheStatus {
+	status := CacheStatus{
+		Name:     name,
+		Entries:  stats.Len,
+		Capacity: stats.Capacity,
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		status.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return status
+}
+
+// CacheStatus reports the heatmap cache, false when heatmaps are disabled
+func (s *TodoApplicationService) CacheStatus() (CacheStatus, bool) {
+	if s.heatmaps == nil {
+		return CacheStatus{}, false
+	}
+	return newCacheStatus(CacheHeatmaps, s.heatmaps.Stats()), true
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// fakeOutbox reports a fixed backlog
+type fakeOutbox struct {
+	backlog ports.OutboxBacklog
+	err     error
+}
+
+func (f fakeOutbox) OutboxBacklog(ctx context.Context) (ports.OutboxBacklog, error) {
+	return f.backlog, f.err
+}
+
+// fakePool reports fixed pool stats
+type fakePool ports.PoolStats
+
+func (f fakePool) PoolStats() ports.PoolStats {
+	return ports.PoolStats(f)
+}
+
+// fakeStreams reports a fixed stream count
+type fakeStreams int
+
+func (f fakeStreams) ActiveStreams() int {
+	return int(f)
+}
+
+func TestJobTracker_Record(t *testing.T) {
+	tracker := NewJobTracker("reminders", time.Minute)
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tracker.Record(first, nil)
+	tracker.Record(first.Add(time.Minute), errors.New("database down"))
+
+	status := tracker.Status()
+	if status.Runs != 2 || status.Failures != 1 || status.LastError != "database down" {
+		t.Errorf("Status() = %+v, want 2 runs, 1 failure with its error", status)
+	}
+	if !status.LastRunAt.Equal(first.Add(time.Minute)) || !status.LastSuccessAt.Equal(first) {
+		t.Errorf("Status() last run %v, last success %v, want %v and %v", status.LastRunAt, status.LastSuccessAt, first.Add(time.Minute), first)
+	}
+
+	tracker.Record(first.Add(2*time.Minute), nil)
+	if status := tracker.Status(); status.LastError != "" {
+		t.Errorf("LastError after a success = %q, want empty", status.LastError)
+	}
+}
+
+func TestJobTracker_Count(t *testing.T) {
+	tracker := NewJobTracker("outbox_reconciliation", time.Minute)
+	if status := tracker.Status(); status.Counters != nil {
+		t.Errorf("Counters before the first count = %v, want nil", status.Counters)
+	}
+
+	tracker.Count("orphaned", 2)
+	tracker.Count("orphaned", 3)
+	status := tracker.Status()
+	tracker.Count("orphaned", 1)
+
+	if status.Counters["orphaned"] != 5 {
+		t.Errorf("Counters = %v, want 5 orphaned", status.Counters)
+	}
+}
+
+// fakeEventDelivery reports fixed delivery stats
+type fakeEventDelivery ports.EventDeliveryStats
+
+func (f fakeEventDelivery) EventDeliveryStats() ports.EventDeliveryStats {
+	return ports.EventDeliveryStats(f)
+}
+
+func TestSystemStatusReporter_SystemStatus(t *testing.T) {
+	oldest := time.Now().Add(-time.Hour)
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithCompletionLog(&MockCompletionLog{}))
+	service.heatmaps.Get("alice")
+
+	reporter := NewSystemStatusReporter(StatusSources{
+		Maintenance: NewMaintenanceMode(true, ""),
+		Jobs:        []*JobTracker{NewJobTracker("reminders", time.Minute)},
+		Outbox:      fakeOutbox{backlog: ports.OutboxBacklog{Pending: 3, OldestPendingAt: &oldest}},
+		Caches:      []CacheReporter{service, NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})},
+		Pool:        fakePool{MaxConns: 4, AcquiredConns: 3},
+		Streams:     fakeStreams(2),
+		Events:      fakeEventDelivery{Degraded: true, DegradedSince: &oldest, Buffered: 5},
+	})
+
+	status, err := reporter.SystemStatus(context.Background())
+	if err != nil {
+		t.Fatalf("SystemStatus() unexpected error: %v", err)
+	}
+
+	if !status.Maintenance || len(status.Jobs) != 1 || status.Jobs[0].Name != "reminders" {
+		t.Errorf("SystemStatus() maintenance %v, jobs %+v", status.Maintenance, status.Jobs)
+	}
+	if status.Outbox == nil || status.Outbox.Pending != 3 || status.Outbox.Lag < time.Hour {
+		t.Errorf("Outbox = %+v, want 3 pending for an hour", status.Outbox)
+	}
+	if status.Events == nil || !status.Events.Degraded || status.Events.Buffered != 5 || status.Events.DegradedFor < time.Hour {
+		t.Errorf("Events = %+v, want 5 events buffered for an hour", status.Events)
+	}
+	if len(status.Caches) != 1 || status.Caches[0].Name != "heatmaps" || status.Caches[0].Misses != 1 {
+		t.Errorf("Caches = %+v, want the heatmap cache with 1 miss", status.Caches)
+	}
+	if status.Pool == nil || status.Pool.Saturation != 0.75 {
+		t.Errorf("Pool = %+v, want a saturation of 0.75", status.Pool)
+	}
+	if status.ActiveStreams == nil || *status.ActiveStreams != 2 {
+		t.Errorf("ActiveStreams = %v, want 2", status.ActiveStreams)
+	}
+}
+
+func TestSystemStatusReporter_FailingSource(t *testing.T) {
+	reporter := NewSystemStatusReporter(StatusSources{Outbox: fakeOutbox{err: errors.New("database down")}})
+
+	status, err := reporter.SystemStatus(context.Background())
+	if err != nil {
+		t.Fatalf("SystemStatus() unexpected error: %v", err)
+	}
+	if status.Outbox == nil || status.Outbox.Error == "" {
+		t.Errorf("Outbox = %+v, want the error reported", status.Outbox)
+	}
+	if status.Pool != nil || status.ActiveStreams != nil || status.Events != nil {
+		t.Errorf("unconfigured sections = %+v, %v, %+v, want nil", status.Pool, status.ActiveStreams, status.Events)
+	}
+}
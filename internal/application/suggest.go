@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Suggestion limits
+const (
+	DefaultSuggestionLimit = 5
+	MaxSuggestionLimit     = 10
+)
+
+// ErrNotSupported is returned by operations the configured adapters cannot serve
+var ErrNotSupported = errors.New("operation not supported by this deployment")
+
+// TodoSuggestion is a lightweight todo match for autocomplete
+type TodoSuggestion struct {
+	ID        string
+	ShortCode string
+	Title     string
+	Status    string
+}
+
+// WithSuggester enables SuggestTodos
+func WithSuggester(suggester ports.TodoSuggester) Option {
+	return func(s *TodoApplicationService) {
+		s.suggester = suggester
+	}
+}
+
+// SuggestTodos returns a handful of todos whose title matches query, for
+// as-you-type autocomplete
+// A limit of zero or less selects DefaultSuggestionLimit; larger limits are
+// capped to MaxSuggestionLimit
+func (s *TodoApplicationService) SuggestTodos(
+	ctx context.Context,
+	query string,
+	limit int,
+) ([]*TodoSuggestion, error) {
+	if s.suggester == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	// No title can match an empty or over-long query
+	if query == "" || len(query) > 200 {
+		return []*TodoSuggestion{}, nil
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultSuggestionLimit
+	case limit > MaxSuggestionLimit:
+		limit = MaxSuggestionLimit
+	}
+
+	todos, err := s.suggester.Suggest(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting todos: %w", err)
+	}
+
+	suggestions := make([]*TodoSuggestion, len(todos))
+	for i, todo := range todos {
+		suggestions[i] = &TodoSuggestion{
+			ID:        todo.ID().String(),
+			ShortCode: todo.ShortCode().String(),
+			Title:     todo.Title().String(),
+			Status:    todo.Status().String(),
+		}
+	}
+
+	return suggestions, nil
+}
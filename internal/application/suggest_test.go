@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// MockTodoSuggester records the arguments of Suggest
+type MockTodoSuggester struct {
+	Todos    []*domain.Todo
+	GotQuery string
+	GotLimit int
+	Calls    int
+}
+
+func (m *MockTodoSuggester) Suggest(ctx context.Context, query string, limit int) ([]*domain.Todo, error) {
+	m.Calls++
+	m.GotQuery, m.GotLimit = query, limit
+	return m.Todos, nil
+}
+
+func TestTodoService_SuggestTodos_Success(t *testing.T) {
+	testTodo := createTestTodo()
+	suggester := &MockTodoSuggester{Todos: []*domain.Todo{testTodo}}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithSuggester(suggester))
+
+	suggestions, err := service.SuggestTodos(context.Background(), "  Test ", 0)
+
+	if err != nil {
+		t.Fatalf("SuggestTodos() unexpected error: %v", err)
+	}
+
+	if suggester.GotQuery != "Test" || suggester.GotLimit != DefaultSuggestionLimit {
+		t.Errorf("Suggest() called with %q, %d, want %q, %d", suggester.GotQuery, suggester.GotLimit, "Test", DefaultSuggestionLimit)
+	}
+
+	if len(suggestions) != 1 || suggestions[0].ID != testTodo.ID().String() {
+		t.Errorf("SuggestTodos() = %v, want the test todo", suggestions)
+	}
+}
+
+func TestTodoService_SuggestTodos_CapsLimit(t *testing.T) {
+	suggester := &MockTodoSuggester{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithSuggester(suggester))
+
+	_, _ = service.SuggestTodos(context.Background(), "Test", 1000)
+
+	if suggester.GotLimit != MaxSuggestionLimit {
+		t.Errorf("Suggest() limit = %d, want %d", suggester.GotLimit, MaxSuggestionLimit)
+	}
+}
+
+func TestTodoService_SuggestTodos_EmptyQuery_SkipsLookup(t *testing.T) {
+	suggester := &MockTodoSuggester{}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithSuggester(suggester))
+
+	suggestions, err := service.SuggestTodos(context.Background(), "   ", 5)
+
+	if err != nil || len(suggestions) != 0 {
+		t.Errorf("SuggestTodos() = %v, %v, want no suggestions", suggestions, err)
+	}
+
+	if suggester.Calls != 0 {
+		t.Errorf("Suggest() calls = %d, want 0", suggester.Calls)
+	}
+}
+
+func TestTodoService_SuggestTodos_WithoutSuggester_NotSupported(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+
+	_, err := service.SuggestTodos(context.Background(), "Test", 5)
+
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SuggestTodos() error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Sync page sizes and request bounds
+const (
+	DefaultSyncLimit  = 100
+	MaxSyncLimit      = 500
+	MaxSyncOperations = 500
+)
+
+// Sync operations
+const (
+	SyncCreate   = "create"
+	SyncUpdate   = "update"
+	SyncComplete = "complete"
+	SyncDelete   = "delete"
+)
+
+// Outcomes of sync operations
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict"
+	SyncRejected = "rejected"
+	SyncFailed   = "failed"
+)
+
+// Conflict reasons and resolutions of sync operations
+const (
+	SyncReasonModified = "modified"
+	SyncReasonDeleted  = "deleted"
+	SyncServerWins     = "server_wins"
+)
+
+// WithChangeLog enables Sync
+func WithChangeLog(changes ports.TodoChangeLog) Option {
+	return func(s *TodoApplicationService) {
+		s.changes = changes
+	}
+}
+
+// Sync pushes the changes a client made offline, then pulls the todos
+// changed on the server since the client cursor
+// Operations apply in order through the regular operations, so policies,
+// legal holds and events apply as online. An operation on a todo modified on
+// the server after BaseUpdatedAt, or deleted there, is a conflict settled by
+// keeping the server state, returned for the client to adopt
+// The pulled changes include those just pushed, and a limit of zero or less
+// selects DefaultSyncLimit while larger limits are capped to MaxSyncLimit
+func (s *TodoApplicationService) Sync(ctx context.Context, req SyncRequest) (*SyncResponse, error) {
+	if s.changes == nil {
+		return nil, ErrNotSupported
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	var after int64
+	if req.Cursor != "" {
+		var err error
+		after, err = strconv.ParseInt(req.Cursor, 10, 64)
+		if err != nil || after < 0 {
+			return nil, domain.NewValidationError("cursor", "is invalid")
+		}
+	}
+	if len(req.Operations) > MaxSyncOperations {
+		return nil, domain.NewValidationError("operations", fmt.Sprintf("must be at most %d", MaxSyncOperations))
+	}
+
+	limit := req.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultSyncLimit
+	case limit > MaxSyncLimit:
+		limit = MaxSyncLimit
+	}
+
+	response := &SyncResponse{Cursor: req.Cursor}
+	if len(req.Operations) > 0 {
+		// Offline changes are not applied in part because of maintenance
+		if err := s.maintenance.CheckWritable(); err != nil {
+			return nil, err
+		}
+	}
+
+	created := make(map[string]string)
+	response.Results = make([]*SyncOperationResult, len(req.Operations))
+	for i, op := range req.Operations {
+		if id, ok := created[op.TodoID]; ok && op.Op != SyncCreate {
+			op.TodoID = id
+		}
+		result := s.applySyncOperation(ctx, op)
+		if op.Op == SyncCreate && result.Status == SyncApplied && op.Ref != "" {
+			created[op.Ref] = result.TodoID
+		}
+		response.Results[i] = result
+	}
+
+	// One more todo than requested tells whether there are more changes
+	marks, err := s.changes.ListChangedSince(ctx, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("listing changes: %w", err)
+	}
+	if len(marks) > limit {
+		marks = marks[:limit]
+		response.HasMore = true
+	}
+
+	response.Changes = make([]*SyncedTodo, len(marks))
+	for i, mark := range marks {
+		change := &SyncedTodo{TodoID: mark.TodoID.String(), Deleted: mark.Deleted}
+		if !mark.Deleted {
+			todo, err := s.repository.FindByID(ctx, mark.TodoID)
+			switch {
+			case errors.Is(err, domain.ErrTodoNotFound):
+				// Deleted after being listed
+				change.Deleted = true
+			case err != nil:
+				return nil, fmt.Errorf("finding todo: %w", err)
+			default:
+				change.Todo = MapTodoToResponse(todo)
+			}
+		}
+		response.Changes[i] = change
+		response.Cursor = strconv.FormatInt(mark.Seq, 10)
+	}
+
+	return response, nil
+}
+
+// applySyncOperation applies op, reporting errors in its result
+// The conflict check and the change are not atomic: a change made in between
+// is overwritten
+func (s *TodoApplicationService) applySyncOperation(ctx context.Context, op SyncOperation) *SyncOperationResult {
+	result := &SyncOperationResult{Ref: op.Ref, Status: SyncApplied, TodoID: op.TodoID}
+
+	if op.Op == SyncCreate {
+		todo, err := s.CreateTodo(ctx, createRequestFromFields(op.Fields))
+		if err != nil {
+			return syncFailure(result, err)
+		}
+		result.TodoID, result.Todo = todo.ID, todo
+		return result
+	}
+
+	switch op.Op {
+	case SyncUpdate, SyncComplete, SyncDelete:
+	default:
+		return syncFailure(result, domain.NewValidationError("op", "must be one of create, update, complete or delete"))
+	}
+
+	todoID, err := s.resolveTodoID(ctx, op.TodoID)
+	if err != nil {
+		return syncFailure(result, fmt.Errorf("invalid todo ID: %w", err))
+	}
+	result.TodoID = todoID.String()
+
+	current, err := s.repository.FindByID(ctx, todoID)
+	if errors.Is(err, domain.ErrTodoNotFound) {
+		if op.Op == SyncDelete {
+			// Already done on the server
+			return result
+		}
+		result.Status, result.Reason, result.Resolution = SyncConflict, SyncReasonDeleted, SyncServerWins
+		return result
+	}
+	if err != nil {
+		return syncFailure(result, fmt.Errorf("finding todo: %w", err))
+	}
+
+	// Stored timestamps have microsecond precision
+	if !op.BaseUpdatedAt.IsZero() &&
+		current.UpdatedAt().Truncate(time.Microsecond).After(op.BaseUpdatedAt.Truncate(time.Microsecond)) {
+		result.Status, result.Reason, result.Resolution = SyncConflict, SyncReasonModified, SyncServerWins
+		result.Todo = MapTodoToResponse(current)
+		return result
+	}
+
+	switch op.Op {
+	case SyncUpdate:
+		result.Todo, err = s.UpdateTodo(ctx, result.TodoID, op.Fields)
+	case SyncComplete:
+		result.Todo, err = s.CompleteTodo(ctx, result.TodoID)
+	case SyncDelete:
+		err = s.DeleteTodo(ctx, result.TodoID)
+	}
+	if err != nil {
+		result.Todo = MapTodoToResponse(current)
+		return syncFailure(result, err)
+	}
+
+	return result
+}
+
+// createRequestFromFields returns the request creating a todo with fields
+func createRequestFromFields(fields UpdateTodoRequest) CreateTodoRequest {
+	req := CreateTodoRequest{DueDate: fields.DueDate}
+	if fields.Title != nil {
+		req.Title = *fields.Title
+	}
+	if fields.Description != nil {
+		req.Description = *fields.Description
+	}
+	if fields.Priority != nil {
+		req.Priority = *fields.Priority
+	}
+	return req
+}
+
+// syncFailure records err in result, as rejected when pushing the operation
+// again cannot succeed
+func syncFailure(result *SyncOperationResult, err error) *SyncOperationResult {
+	result.Status, result.Reason = SyncFailed, err.Error()
+	if isRejection(err) {
+		result.Status = SyncRejected
+	}
+
+	return result
+}
+
+// isRejection reports whether err refuses a change to a todo for good:
+// making the same change again cannot succeed
+func isRejection(err error) bool {
+	var validationErr domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, domain.ErrInvalidID),
+		errors.Is(err, domain.ErrInvalidShortCode),
+		errors.Is(err, domain.ErrInvalidTitle),
+		errors.Is(err, domain.ErrInvalidDueDate),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrCannotCompleteCancelled),
+		errors.Is(err, domain.ErrCannotModifyCompleted),
+		errors.Is(err, domain.ErrAlreadyMerged),
+		errors.Is(err, ErrForbidden),
+		errors.Is(err, ErrLegalHold),
+		errors.Is(err, ErrTodoFrozen):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockChangeLog serves marks above the requested sequence number
+type MockChangeLog struct {
+	Marks    []ports.TodoChangeMark
+	GotAfter int64
+	GotLimit int
+}
+
+func (m *MockChangeLog) ListChangedSince(ctx context.Context, after int64, limit int) ([]ports.TodoChangeMark, error) {
+	m.GotAfter, m.GotLimit = after, limit
+
+	var marks []ports.TodoChangeMark
+	for _, mark := range m.Marks {
+		if mark.Seq > after && len(marks) < limit {
+			marks = append(marks, mark)
+		}
+	}
+	return marks, nil
+}
+
+func TestTodoService_Sync_PushesOperations(t *testing.T) {
+	stale, fresh := createTestTodo(), createTestTodo()
+	todos := map[domain.TodoID]*domain.Todo{stale.ID(): stale, fresh.ID(): fresh}
+	repo := memoryTodoRepository(todos)
+	repo.DeleteFunc = func(ctx context.Context, id domain.TodoID) error {
+		delete(todos, id)
+		return nil
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithChangeLog(&MockChangeLog{}))
+
+	title, renamed, priority := "Buy milk", "Buy oat milk", "low"
+	missing := domain.NewTodoID().String()
+	response, err := service.Sync(context.Background(), SyncRequest{Operations: []SyncOperation{
+		{Ref: "local-1", Op: SyncCreate, Fields: UpdateTodoRequest{Title: &title, Priority: &priority}},
+		{Ref: "local-2", Op: SyncUpdate, TodoID: "local-1", Fields: UpdateTodoRequest{Title: &renamed}},
+		{Ref: "local-3", Op: SyncUpdate, TodoID: stale.ID().String(), BaseUpdatedAt: stale.UpdatedAt().Add(-time.Hour), Fields: UpdateTodoRequest{Title: &renamed}},
+		{Ref: "local-4", Op: SyncComplete, TodoID: fresh.ID().String(), BaseUpdatedAt: fresh.UpdatedAt()},
+		{Ref: "local-5", Op: SyncComplete, TodoID: missing},
+		{Ref: "local-6", Op: SyncDelete, TodoID: missing},
+		{Ref: "local-7", Op: "archive", TodoID: fresh.ID().String()},
+	}})
+	if err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+
+	want := []struct {
+		status, reason string
+	}{
+		{SyncApplied, ""},
+		{SyncApplied, ""},
+		{SyncConflict, SyncReasonModified},
+		{SyncApplied, ""},
+		{SyncConflict, SyncReasonDeleted},
+		{SyncApplied, ""},
+		{SyncRejected, ""},
+	}
+	if len(response.Results) != len(want) {
+		t.Fatalf("Sync() = %d results, want %d", len(response.Results), len(want))
+	}
+	for i, result := range response.Results {
+		if result.Status != want[i].status || (want[i].reason != "" && result.Reason != want[i].reason) {
+			t.Errorf("result %d = %s (%s), want %s (%s)", i, result.Status, result.Reason, want[i].status, want[i].reason)
+		}
+	}
+
+	created := response.Results[0]
+	if response.Results[1].TodoID != created.TodoID || response.Results[1].Todo.Title != renamed {
+		t.Errorf("update of the created todo = %+v, want %s renamed", response.Results[1], created.TodoID)
+	}
+	conflict := response.Results[2]
+	if conflict.Resolution != SyncServerWins || conflict.Todo == nil || conflict.Todo.Title != "Test Todo" {
+		t.Errorf("conflict = %+v, want the server state kept", conflict)
+	}
+	if stale.Title().String() != "Test Todo" {
+		t.Errorf("stale todo renamed to %q despite the conflict", stale.Title())
+	}
+	if !fresh.Status().IsCompleted() {
+		t.Errorf("fresh todo status = %s, want completed", fresh.Status())
+	}
+}
+
+func TestTodoService_Sync_PullsChanges(t *testing.T) {
+	kept, gone := createTestTodo(), createTestTodo()
+	changes := &MockChangeLog{Marks: []ports.TodoChangeMark{
+		{Seq: 3, TodoID: kept.ID()},
+		{Seq: 5, TodoID: domain.NewTodoID(), Deleted: true},
+		{Seq: 8, TodoID: gone.ID()},
+		{Seq: 9, TodoID: domain.NewTodoID(), Deleted: true},
+	}}
+	repo := memoryTodoRepository(map[domain.TodoID]*domain.Todo{kept.ID(): kept})
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithChangeLog(changes))
+
+	first, err := service.Sync(context.Background(), SyncRequest{Limit: 3})
+	if err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+	if len(first.Changes) != 3 || first.Cursor != "8" || !first.HasMore {
+		t.Fatalf("first sync = %d changes up to %q (more: %v), want 3 up to 8 with more", len(first.Changes), first.Cursor, first.HasMore)
+	}
+	if first.Changes[0].Todo == nil || first.Changes[0].Todo.ID != kept.ID().String() {
+		t.Errorf("first change = %+v, want the state of %v", first.Changes[0], kept.ID())
+	}
+	if !first.Changes[1].Deleted || !first.Changes[2].Deleted || first.Changes[2].Todo != nil {
+		t.Errorf("changes = %+v, %+v, want deletions, including the todo gone since listed", first.Changes[1], first.Changes[2])
+	}
+
+	second, err := service.Sync(context.Background(), SyncRequest{Cursor: first.Cursor, Limit: 3})
+	if err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+	if changes.GotAfter != 8 || len(second.Changes) != 1 || second.Cursor != "9" || second.HasMore {
+		t.Errorf("second sync = %d changes up to %q (more: %v), want the last one", len(second.Changes), second.Cursor, second.HasMore)
+	}
+
+	idle, err := service.Sync(context.Background(), SyncRequest{Cursor: second.Cursor})
+	if err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+	if len(idle.Changes) != 0 || idle.Cursor != "9" {
+		t.Errorf("idle sync = %d changes up to %q, want none and the same cursor", len(idle.Changes), idle.Cursor)
+	}
+}
+
+func TestTodoService_Sync_Errors(t *testing.T) {
+	var validationErr domain.ValidationError
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.Sync(context.Background(), SyncRequest{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Sync() without change log error = %v, want ErrNotSupported", err)
+	}
+
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{},
+		WithChangeLog(&MockChangeLog{}),
+		WithMaintenanceMode(NewMaintenanceMode(true, "")))
+	if _, err := service.Sync(context.Background(), SyncRequest{Cursor: "-1"}); !errors.As(err, &validationErr) {
+		t.Errorf("Sync(invalid cursor) error = %v, want a ValidationError", err)
+	}
+	if _, err := service.Sync(context.Background(), SyncRequest{Operations: make([]SyncOperation, MaxSyncOperations+1)}); !errors.As(err, &validationErr) {
+		t.Errorf("Sync(too many operations) error = %v, want a ValidationError", err)
+	}
+
+	// Pulling works during maintenance, pushing does not
+	if _, err := service.Sync(context.Background(), SyncRequest{}); err != nil {
+		t.Errorf("Sync() pull during maintenance unexpected error: %v", err)
+	}
+	push := SyncRequest{Operations: []SyncOperation{{Op: SyncDelete, TodoID: domain.NewTodoID().String()}}}
+	if _, err := service.Sync(context.Background(), push); !errors.Is(err, ErrMaintenanceMode) {
+		t.Errorf("Sync() push during maintenance error = %v, want ErrMaintenanceMode", err)
+	}
+}
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// WithTodoAudit enables GetTodoAuditLog, reading the entries recorded by a
+// TodoAuditRecorder
+func WithTodoAudit(trail ports.TodoAuditTrail) Option {
+	return func(s *TodoApplicationService) {
+		s.auditTrail = trail
+	}
+}
+
+// GetTodoAuditLog returns the history of a todo: every domain event it
+// emitted with who caused it and the fields it set, oldest first
+func (s *TodoApplicationService) GetTodoAuditLog(ctx context.Context, id string) ([]TodoAuditLogEntry, error) {
+	if s.auditTrail == nil {
+		return nil, ErrNotSupported
+	}
+
+	todoID, err := s.resolveTodoID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	if err := s.authorize(ctx, ActionRead, todo); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditTrail.ListByTodo(ctx, todoID.String())
+	if err != nil {
+		return nil, fmt.Errorf("listing todo audit entries: %w", err)
+	}
+
+	log := make([]TodoAuditLogEntry, len(entries))
+	for i, entry := range entries {
+		log[i] = TodoAuditLogEntry{
+			OccurredAt: entry.OccurredAt,
+			Actor:      entry.Actor,
+			EventType:  entry.EventType,
+			Changes:    entry.Changes,
+		}
+	}
+
+	return log, nil
+}
+
+// TodoAuditRecorder records the domain events of todos in the todo audit
+// trail before passing them to the next dispatcher
+// It is a dispatcher rather than a subscriber because the actor is only
+// known from the context of the request that caused the events
+type TodoAuditRecorder struct {
+	next  ports.EventDispatcher
+	trail ports.TodoAuditTrail
+}
+
+// NewTodoAuditRecorder creates a TodoAuditRecorder dispatching to next
+func NewTodoAuditRecorder(next ports.EventDispatcher, trail ports.TodoAuditTrail) *TodoAuditRecorder {
+	return &TodoAuditRecorder{next: next, trail: trail}
+}
+
+// Dispatch records events, then dispatches them to the next dispatcher
+// The changes are already saved, so events are dispatched even when they
+// could not be recorded; the request fails either way
+func (r *TodoAuditRecorder) Dispatch(ctx context.Context, events []domain.DomainEvent) error {
+	actor, _ := UserIDFromContext(ctx)
+
+	entries := make([]ports.TodoAuditEntry, 0, len(events))
+	for _, event := range events {
+		// A plan is recorded in the history of each of its todos
+		if plan, ok := event.(WeekPlanned); ok {
+			for _, id := range plan.TodoIDs() {
+				entries = append(entries, ports.TodoAuditEntry{
+					TodoID:     id,
+					EventType:  plan.EventType(),
+					Actor:      actor,
+					Changes:    map[string]string{"due_date": plan.DueDates[id].UTC().Format(time.RFC3339)},
+					OccurredAt: plan.OccurredAt(),
+				})
+			}
+			continue
+		}
+
+		changes, ok := auditedChanges(event)
+		if !ok {
+			continue
+		}
+		entry := ports.TodoAuditEntry{
+			TodoID:     event.AggregateID(),
+			EventType:  event.EventType(),
+			Actor:      actor,
+			Changes:    changes,
+			OccurredAt: event.OccurredAt(),
+		}
+		// Overrides name their administrator, who may act on behalf of no user
+		if forced, ok := event.(domain.TodoForceUpdated); ok {
+			entry.Actor = forced.Actor
+		}
+		entries = append(entries, entry)
+	}
+
+	var recordErr error
+	if err := r.trail.Append(ctx, entries); err != nil {
+		recordErr = fmt.Errorf("recording todo audit entries: %w", err)
+	}
+
+	return errors.Join(recordErr, r.next.Dispatch(ctx, events))
+}
+
+// auditedChanges maps the fields set by a todo event to their new value,
+// times in RFC 3339; ok is false for the events of other aggregates
+func auditedChanges(event domain.DomainEvent) (changes map[string]string, ok bool) {
+	changes = map[string]string{}
+	set := func(field string, value *string) {
+		if value != nil {
+			changes[field] = *value
+		}
+	}
+	setTime := func(field string, value *time.Time) {
+		if value != nil {
+			changes[field] = value.UTC().Format(time.RFC3339)
+		}
+	}
+
+	switch e := event.(type) {
+	case domain.TodoCreated:
+		changes["title"] = e.Title
+		changes["description"] = e.Description
+		changes["priority"] = e.Priority
+		setTime("due_date", e.DueDate)
+	case domain.TodoUpdated:
+		set("title", e.Title)
+		set("description", e.Description)
+		set("priority", e.Priority)
+		setTime("due_date", e.DueDate)
+		if e.DueDateCleared {
+			changes["due_date"] = ""
+		}
+		set("status", e.Status)
+	case domain.TodoCompleted:
+		changes["status"] = domain.StatusCompleted.String()
+		setTime("completed_at", &e.CompletedAt)
+	case domain.TodoReopened:
+		changes["status"] = domain.StatusPending.String()
+		changes["previous_status"] = e.PreviousStatus
+	case domain.TodoForceUpdated:
+		changes["reason"] = e.Reason
+		changes["fields"] = strings.Join(e.Fields, ",")
+	case domain.TodoMerged:
+		changes["canonical_id"] = e.CanonicalID
+	case domain.TodoMoved:
+		changes["owner_id"] = e.ToOwner
+		changes["previous_owner_id"] = e.FromOwner
+	case domain.TodoArchived:
+		setTime("archived_at", &e.ArchivedAt)
+	case domain.TodoDueSoon:
+		setTime("due_date", &e.DueDate)
+	case domain.TodoOverdue:
+		setTime("due_date", &e.DueDate)
+	case domain.TodoDeleted, domain.TodoUnarchived:
+	default:
+		return nil, false
+	}
+
+	return changes, true
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockTodoAuditTrail keeps the appended entries in memory
+type MockTodoAuditTrail struct {
+	AppendErr error
+	Entries   []ports.TodoAuditEntry
+}
+
+func (m *MockTodoAuditTrail) Append(ctx context.Context, entries []ports.TodoAuditEntry) error {
+	if m.AppendErr != nil {
+		return m.AppendErr
+	}
+	m.Entries = append(m.Entries, entries...)
+	return nil
+}
+
+func (m *MockTodoAuditTrail) ListByTodo(ctx context.Context, todoID string) ([]ports.TodoAuditEntry, error) {
+	var entries []ports.TodoAuditEntry
+	for _, entry := range m.Entries {
+		if entry.TodoID == todoID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestTodoAuditRecorder_Dispatch(t *testing.T) {
+	todo := createTestTodo()
+	todo.ClearEvents()
+	title, _ := domain.NewTaskTitle("Renamed")
+	_ = todo.UpdateTitle(title)
+	_ = todo.UpdateDueDate(nil)
+	_ = todo.Complete()
+	forced := domain.NewTodoForceUpdatedEvent(todo.ID(), "ops@example.com", "Completed by mistake", []string{"status", "priority"})
+	planned := time.Date(2026, 10, 19, 23, 59, 59, 0, time.UTC)
+	plan := WeekPlanned{DueDates: map[string]time.Time{todo.ID().String(): planned}, occurredAt: planned}
+	moved := domain.NewTodoMovedEvent(todo.ID(), "alice", "bob")
+	events := append(todo.Events(), forced, moved, plan, SecurityAnomalyDetected{Kind: AnomalyMassDeletion})
+
+	trail := &MockTodoAuditTrail{}
+	next := &MockEventDispatcher{}
+	recorder := NewTodoAuditRecorder(next, trail)
+
+	if err := recorder.Dispatch(ContextWithUserID(context.Background(), "alice"), events); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	if len(next.DispatchedEvents) != len(events) {
+		t.Errorf("dispatched %d events, want %d", len(next.DispatchedEvents), len(events))
+	}
+
+	tests := []struct {
+		eventType string
+		actor     string
+		field     string
+		value     string
+	}{
+		{eventType: "TodoUpdated", actor: "alice", field: "title", value: "Renamed"},
+		{eventType: "TodoUpdated", actor: "alice", field: "due_date", value: ""},
+		{eventType: "TodoCompleted", actor: "alice", field: "status", value: "completed"},
+		{eventType: "TodoForceUpdated", actor: "ops@example.com", field: "fields", value: "status,priority"},
+		{eventType: "TodoMoved", actor: "alice", field: "owner_id", value: "bob"},
+		{eventType: "WeekPlanned", actor: "alice", field: "due_date", value: "2026-10-19T23:59:59Z"},
+	}
+	if len(trail.Entries) != len(tests) {
+		t.Fatalf("recorded %+v, want %d entries without the anomaly", trail.Entries, len(tests))
+	}
+	for i, tt := range tests {
+		entry := trail.Entries[i]
+		value, ok := entry.Changes[tt.field]
+		if entry.TodoID != todo.ID().String() || entry.EventType != tt.eventType || entry.Actor != tt.actor || !ok || value != tt.value {
+			t.Errorf("entry %d = %+v, want %s by %s setting %s to %q", i, entry, tt.eventType, tt.actor, tt.field, tt.value)
+		}
+	}
+}
+
+func TestTodoAuditRecorder_Dispatch_RecordFailure(t *testing.T) {
+	recordErr := errors.New("database unavailable")
+	next := &MockEventDispatcher{}
+	recorder := NewTodoAuditRecorder(next, &MockTodoAuditTrail{AppendErr: recordErr})
+
+	err := recorder.Dispatch(context.Background(), createTestTodo().Events())
+
+	if !errors.Is(err, recordErr) {
+		t.Errorf("Dispatch() error = %v, want %v", err, recordErr)
+	}
+	if len(next.DispatchedEvents) != 1 {
+		t.Errorf("dispatched %d events, want the creation dispatched anyway", len(next.DispatchedEvents))
+	}
+}
+
+func TestTodoService_GetTodoAuditLog(t *testing.T) {
+	testTodo := createTestTodo()
+	trail := &MockTodoAuditTrail{}
+	recorder := NewTodoAuditRecorder(&MockEventDispatcher{}, trail)
+	if err := recorder.Dispatch(ContextWithUserID(context.Background(), "alice"), testTodo.Events()); err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoAudit(trail))
+
+	log, err := service.GetTodoAuditLog(context.Background(), testTodo.ID().String())
+
+	if err != nil {
+		t.Fatalf("GetTodoAuditLog() unexpected error: %v", err)
+	}
+	if len(log) != 1 || log[0].EventType != "TodoCreated" || log[0].Actor != "alice" || log[0].Changes["title"] != "Test Todo" {
+		t.Errorf("GetTodoAuditLog() = %+v, want the creation by alice", log)
+	}
+}
+
+func TestTodoService_GetTodoAuditLog_Errors(t *testing.T) {
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoAudit(&MockTodoAuditTrail{}))
+	id := domain.NewTodoID().String()
+
+	if _, err := service.GetTodoAuditLog(context.Background(), id); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("GetTodoAuditLog() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+
+	unsupported := NewTodoApplicationService(repo, &MockEventDispatcher{})
+	if _, err := unsupported.GetTodoAuditLog(context.Background(), id); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("GetTodoAuditLog() without audit error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/lru"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// CacheTodos names the todo cache, keyed by todo ID
+const CacheTodos = "todos"
+
+// cachedTodo is a todo as the repository returned it to the owner scope
+// scope, when it was read and when it expires
+type cachedTodo struct {
+	todo       *domain.Todo
+	scope      string
+	computedAt time.Time
+	expires    time.Time
+}
+
+// TodoCache keeps the todos read by GetTodo, so that polling clients do not
+// hit the database
+// Entries are evicted by a CacheInvalidator as the todos change; TTL bounds
+// how stale an entry can be when an eviction is missed, e.g. on an instance
+// without a cache invalidation bus
+type TodoCache struct {
+	entries *lru.Cache[string, cachedTodo]
+	ttl     time.Duration
+	// epoch changes with every eviction, so that a todo read before one is
+	// not cached after it
+	epoch atomic.Uint64
+}
+
+// NewTodoCache creates a TodoCache of size todos kept for ttl at most
+func NewTodoCache(size int, ttl time.Duration) *TodoCache {
+	return &TodoCache{
+		entries: lru.New[string, cachedTodo](size),
+		ttl:     ttl,
+	}
+}
+
+// WithTodoCache serves GetTodo from cache
+func WithTodoCache(cache *TodoCache) Option {
+	return func(s *TodoApplicationService) {
+		s.todos = cache
+	}
+}
+
+// CacheStatus reports the todo cache
+func (c *TodoCache) CacheStatus() (CacheStatus, bool) {
+	return newCacheStatus(CacheTodos, c.entries.Stats()), true
+}
+
+// Invalidate evicts the todo of id, or every todo when id is empty
+func (c *TodoCache) Invalidate(id string) {
+	c.epoch.Add(1)
+	if id == "" {
+		c.entries.Purge()
+		return
+	}
+	c.entries.Remove(id)
+}
+
+// get returns the todo of id cached for the owner scope of ctx, unless it
+// expired or is older than the write of the consistency token of ctx
+func (c *TodoCache) get(ctx context.Context, id string, now time.Time) (*domain.Todo, bool) {
+	scope, scoped := ownerScope(ctx)
+	if !scoped {
+		return nil, false
+	}
+	cached, ok := c.entries.Get(id)
+	if !ok || cached.scope != scope || !now.Before(cached.expires) || !freshEnough(ctx, cached.computedAt) {
+		return nil, false
+	}
+	return cached.todo, true
+}
+
+// add caches todo, read at readAt for the owner scope of ctx, unless a todo
+// was evicted since epoch
+func (c *TodoCache) add(ctx context.Context, todo *domain.Todo, epoch uint64, readAt time.Time) {
+	scope, scoped := ownerScope(ctx)
+	if c.epoch.Load() != epoch || !scoped {
+		return
+	}
+	c.entries.Add(todo.ID().String(), cachedTodo{
+		todo:       todo,
+		scope:      scope,
+		computedAt: readAt,
+		expires:    readAt.Add(c.ttl),
+	})
+}
+
+// ownerScope returns the owner the repository reads of ctx are scoped to,
+// empty when they have system access, and false when they reach no todo
+func ownerScope(ctx context.Context) (string, bool) {
+	if owner, ok := ports.OwnerFromContext(ctx); ok {
+		return owner, true
+	}
+	return "", ports.HasSystemAccess(ctx)
+}
+
+// cachedFindByID returns the todo of id, from the todo cache when it holds it
+// Cached todos are shared, so callers must not change them
+func (s *TodoApplicationService) cachedFindByID(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+	if s.todos == nil {
+		return s.repository.FindByID(ctx, id)
+	}
+
+	now := time.Now()
+	if todo, ok := s.todos.get(ctx, id.String(), now); ok {
+		return todo, nil
+	}
+
+	epoch := s.todos.epoch.Load()
+	todo, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.todos.add(ctx, todo, epoch, now)
+
+	return todo, nil
+}
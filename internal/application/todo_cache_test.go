@@ -0,0 +1,173 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+func TestTodoService_GetTodo_Cached(t *testing.T) {
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		second      func(ctx context.Context) context.Context
+		between     func(cache *TodoCache, id string)
+		wantQueries int
+	}{
+		{
+			name:        "served from cache",
+			ttl:         time.Minute,
+			wantQueries: 1,
+		},
+		{
+			name:        "todo evicted",
+			ttl:         time.Minute,
+			between:     func(cache *TodoCache, id string) { cache.Invalidate(id) },
+			wantQueries: 2,
+		},
+		{
+			name:        "another todo evicted",
+			ttl:         time.Minute,
+			between:     func(cache *TodoCache, id string) { cache.Invalidate(domain.NewTodoID().String()) },
+			wantQueries: 1,
+		},
+		{
+			name:        "every todo evicted",
+			ttl:         time.Minute,
+			between:     func(cache *TodoCache, id string) { cache.Invalidate("") },
+			wantQueries: 2,
+		},
+		{
+			name:        "expired",
+			ttl:         0,
+			wantQueries: 2,
+		},
+		{
+			name: "another owner scope",
+			ttl:  time.Minute,
+			second: func(ctx context.Context) context.Context {
+				return ports.ContextWithOwner(ctx, "bob")
+			},
+			wantQueries: 2,
+		},
+		{
+			name: "consistency token after the read",
+			ttl:  time.Minute,
+			second: func(ctx context.Context) context.Context {
+				return ports.ContextWithWrittenAt(ctx, time.Now().Add(time.Second))
+			},
+			wantQueries: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testTodo := createTestTodo()
+			queries := 0
+			repo := &MockTodoRepository{
+				FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+					queries++
+					return testTodo, nil
+				},
+			}
+			cache := NewTodoCache(10, tt.ttl)
+			service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoCache(cache))
+			ctx := ports.ContextWithOwner(context.Background(), "alice")
+			id := testTodo.ID().String()
+
+			if _, err := service.GetTodo(ctx, id); err != nil {
+				t.Fatalf("GetTodo() unexpected error: %v", err)
+			}
+			if tt.between != nil {
+				tt.between(cache, id)
+			}
+			if tt.second != nil {
+				ctx = tt.second(ctx)
+			}
+			response, err := service.GetTodo(ctx, id)
+			if err != nil {
+				t.Fatalf("GetTodo() unexpected error: %v", err)
+			}
+
+			if response.ID != id {
+				t.Errorf("GetTodo() ID = %s, want %s", response.ID, id)
+			}
+			if queries != tt.wantQueries {
+				t.Errorf("FindByID() called %d times, want %d", queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodo_AnonymousBypassesCache(t *testing.T) {
+	testTodo := createTestTodo()
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if !ports.HasSystemAccess(ctx) {
+				return nil, domain.ErrTodoNotFound
+			}
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoCache(NewTodoCache(10, time.Minute)))
+	id := testTodo.ID().String()
+
+	// A todo cached for system access is not served to anonymous requests
+	if _, err := service.GetTodo(ports.ContextWithSystemAccess(context.Background()), id); err != nil {
+		t.Fatalf("GetTodo() with system access unexpected error: %v", err)
+	}
+	if _, err := service.GetTodo(context.Background(), id); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("GetTodo() anonymous error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestTodoService_GetTodo_EvictedWhileRead(t *testing.T) {
+	testTodo := createTestTodo()
+	cache := NewTodoCache(10, time.Minute)
+	queries := 0
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			queries++
+			// The todo changes while it is read: the read may be stale
+			if queries == 1 {
+				cache.Invalidate(id.String())
+			}
+			return testTodo, nil
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoCache(cache))
+
+	for range 2 {
+		if _, err := service.GetTodo(context.Background(), testTodo.ID().String()); err != nil {
+			t.Fatalf("GetTodo() unexpected error: %v", err)
+		}
+	}
+	if queries != 2 {
+		t.Errorf("FindByID() called %d times, want the read made during the eviction not cached", queries)
+	}
+}
+
+func TestTodoService_GetTodo_NotFoundNotCached(t *testing.T) {
+	queries := 0
+	repo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			queries++
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	service := NewTodoApplicationService(repo, &MockEventDispatcher{}, WithTodoCache(NewTodoCache(10, time.Minute)))
+	id := domain.NewTodoID().String()
+
+	for range 2 {
+		if _, err := service.GetTodo(context.Background(), id); err == nil {
+			t.Fatal("GetTodo() expected an error")
+		}
+	}
+	if queries != 2 {
+		t.Errorf("FindByID() called %d times, want 2", queries)
+	}
+}
@@ -3,9 +3,11 @@ package application
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/pkg/lru"
 	"github.com/pivaldi/mmw/todo/internal/ports"
 )
 
@@ -24,19 +26,93 @@ type TodoService interface {
 // TodoApplicationService implements the TodoService port
 // It orchestrates domain operations and coordinates infrastructure concerns
 type TodoApplicationService struct {
-	repository ports.TodoRepository
-	dispatcher ports.EventDispatcher
+	repository    ports.TodoRepository
+	dispatcher    ports.EventDispatcher
+	transactor    ports.Transactor
+	outbox        ports.EventOutbox
+	maintenance   *MaintenanceMode
+	auditLog      ports.AuditLog
+	shortCodes    ports.ShortCodeResolver
+	suggester     ports.TodoSuggester
+	searcher      ports.TodoSearcher
+	recent        ports.RecentActivityStore
+	profiles      ports.ClientProfileStore
+	history       ports.TodoHistory
+	versions      ports.TodoVersionFinder
+	feed          ports.TodoActivityFeed
+	changes       ports.TodoChangeLog
+	analytics     ports.TodoAnalytics
+	completions   ports.CompletionLog
+	heatmaps      *lru.Cache[string, cachedHeatmap]
+	todos         *TodoCache
+	invalidations ports.CacheInvalidationBus
+	hooks         ports.InboundHookStore
+	authorizer    ports.Authorizer
+	subscriber    ports.EventSubscriber
+	legalHolds    ports.LegalHoldStore
+	editLocks     ports.EditLockStore
+	canaries      ports.CanaryFinder
+	auditReader   ports.AuditLogReader
+	auditScanner  ports.TodoAuditScanner
+	viewScanner   ports.RecentActivityScanner
+	auditTrail    ports.TodoAuditTrail
+	purger        ports.TodoPurger
+	merger        ports.TodoMerger
+	mover         ports.TodoMover
+	batchSaver    ports.TodoBatchSaver
+	batchUpdater  ports.TodoBatchUpdater
+	batchDeleter  ports.TodoBatchDeleter
+	bulkCompleter ports.TodoBulkCompleter
+	dependencies  ports.DependencyStore
+	milestones    ports.MilestoneStore
+	calendar      *BusinessCalendar
+	datedFinder   ports.DatedTodoFinder
+	devices       ports.DeviceStore
+	pushProviders []ports.PushProvider
+	purgeSecret   []byte
+	queryGuard    *QueryGuard
+}
+
+// Option configures optional collaborators of the TodoApplicationService
+type Option func(*TodoApplicationService)
+
+// WithMaintenanceMode makes write operations honor the given maintenance switch
+func WithMaintenanceMode(maintenance *MaintenanceMode) Option {
+	return func(s *TodoApplicationService) {
+		s.maintenance = maintenance
+	}
+}
+
+// WithAuditLog records privileged operations such as ForceUpdateTodo
+func WithAuditLog(auditLog ports.AuditLog) Option {
+	return func(s *TodoApplicationService) {
+		s.auditLog = auditLog
+	}
+}
+
+// WithShortCodes accepts short codes (e.g. TD-1042) wherever a todo ID is expected
+func WithShortCodes(resolver ports.ShortCodeResolver) Option {
+	return func(s *TodoApplicationService) {
+		s.shortCodes = resolver
+	}
 }
 
 // NewTodoApplicationService creates a new TodoApplicationService
 func NewTodoApplicationService(
 	repository ports.TodoRepository,
 	dispatcher ports.EventDispatcher,
+	opts ...Option,
 ) *TodoApplicationService {
-	return &TodoApplicationService{
+	s := &TodoApplicationService{
 		repository: repository,
 		dispatcher: dispatcher,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // CreateTodo creates a new todo
@@ -44,6 +120,39 @@ func (s *TodoApplicationService) CreateTodo(
 	ctx context.Context,
 	req CreateTodoRequest,
 ) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.newTodo(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the todo and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Save(ctx, todo); err != nil {
+			return nil, fmt.Errorf("saving todo: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Clear events after dispatching
+	todo.ClearEvents()
+
+	// Track per-user recent activity
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
+	// Map to response DTO
+	return MapTodoToResponse(todo), nil
+}
+
+// newTodo validates req and builds the todo it creates, owned by the
+// authenticated user; keepPastDueDate restores a due date already past, as
+// for a todo brought from elsewhere, instead of refusing it
+func (s *TodoApplicationService) newTodo(ctx context.Context, req CreateTodoRequest, keepPastDueDate bool) (*domain.Todo, error) {
 	// Create value objects from request
 	title, err := domain.NewTaskTitle(req.Title)
 	if err != nil {
@@ -59,29 +168,25 @@ func (s *TodoApplicationService) CreateTodo(
 	if req.DueDate != nil {
 		dd, err := domain.NewDueDate(*req.DueDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid due date: %w", err)
+			if !keepPastDueDate {
+				return nil, fmt.Errorf("invalid due date: %w", err)
+			}
+			dd = domain.ReconstituteDueDate(*req.DueDate)
 		}
 		dueDate = &dd
 	}
 
-	// Create todo using domain factory
+	// Create todo using domain factory, owned by the authenticated user
 	todo := domain.NewTodo(title, req.Description, priority, dueDate)
-
-	// Persist the todo
-	if err := s.repository.Save(ctx, todo); err != nil {
-		return nil, fmt.Errorf("saving todo: %w", err)
+	if ownerID, ok := ports.OwnerFromContext(ctx); ok {
+		todo.AssignOwner(ownerID)
 	}
 
-	// Dispatch domain events
-	if err := s.dispatcher.Dispatch(ctx, todo.Events()); err != nil {
-		return nil, fmt.Errorf("dispatching events: %w", err)
+	if err := s.authorize(ctx, ActionCreate, todo); err != nil {
+		return nil, err
 	}
 
-	// Clear events after dispatching
-	todo.ClearEvents()
-
-	// Map to response DTO
-	return MapTodoToResponse(todo), nil
+	return todo, nil
 }
 
 // GetTodo retrieves a todo by ID
@@ -90,19 +195,33 @@ func (s *TodoApplicationService) GetTodo(
 	id string,
 ) (*TodoResponse, error) {
 	// Parse and validate ID
-	todoID, err := domain.ParseTodoID(id)
+	todoID, err := s.resolveTodoID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid todo ID: %w", err)
 	}
 
-	// Retrieve from repository
-	todo, err := s.repository.FindByID(ctx, todoID)
+	// Retrieve from the cache or the repository
+	todo, err := s.cachedFindByID(ctx, todoID)
 	if err != nil {
 		return nil, fmt.Errorf("finding todo: %w", err)
 	}
 
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionRead, todo); err != nil {
+		return nil, err
+	}
+
+	// Track per-user recent activity
+	s.trackActivity(ctx, todo.ID(), ports.ActivityViewed)
+
 	// Map to response DTO
-	return MapTodoToResponse(todo), nil
+	response := MapTodoToResponse(todo)
+	response.EditLock = s.findEditLock(ctx, todo.ID())
+
+	return response, nil
 }
 
 // UpdateTodo updates an existing todo
@@ -111,8 +230,12 @@ func (s *TodoApplicationService) UpdateTodo(
 	id string,
 	req UpdateTodoRequest,
 ) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
 	// Parse and validate ID
-	todoID, err := domain.ParseTodoID(id)
+	todoID, err := s.resolveTodoID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid todo ID: %w", err)
 	}
@@ -123,74 +246,40 @@ func (s *TodoApplicationService) UpdateTodo(
 		return nil, fmt.Errorf("finding todo: %w", err)
 	}
 
-	// Update title if provided
-	if req.Title != nil {
-		title, err := domain.NewTaskTitle(*req.Title)
-		if err != nil {
-			return nil, fmt.Errorf("invalid title: %w", err)
-		}
-		if err := todo.UpdateTitle(title); err != nil {
-			return nil, fmt.Errorf("updating title: %w", err)
-		}
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return nil, err
 	}
 
-	// Update description if provided
-	if req.Description != nil {
-		if err := todo.UpdateDescription(*req.Description); err != nil {
-			return nil, fmt.Errorf("updating description: %w", err)
-		}
+	if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+		return nil, err
 	}
 
-	// Update priority if provided
-	if req.Priority != nil {
-		priority, err := domain.NewPriority(*req.Priority)
-		if err != nil {
-			return nil, fmt.Errorf("invalid priority: %w", err)
-		}
-		if err := todo.UpdatePriority(priority); err != nil {
-			return nil, fmt.Errorf("updating priority: %w", err)
-		}
+	// Settle the changes made since the version the client edited
+	req, err = s.resolveEditConflict(ctx, todo, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update due date if provided
-	if req.DueDate != nil {
-		var dueDate *domain.DueDate
-		if *req.DueDate != (time.Time{}) {
-			dd, err := domain.NewDueDate(*req.DueDate)
-			if err != nil {
-				return nil, fmt.Errorf("invalid due date: %w", err)
-			}
-			dueDate = &dd
-		}
-		if err := todo.UpdateDueDate(dueDate); err != nil {
-			return nil, fmt.Errorf("updating due date: %w", err)
-		}
+	if err := applyUpdate(todo, req); err != nil {
+		return nil, err
 	}
 
-	// Update status if provided
-	if req.Status != nil {
-		status, err := domain.NewTaskStatus(*req.Status)
-		if err != nil {
-			return nil, fmt.Errorf("invalid status: %w", err)
+	// Persist changes and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Update(ctx, todo); err != nil {
+			return nil, fmt.Errorf("updating todo: %w", err)
 		}
-		if err := todo.UpdateStatus(status); err != nil {
-			return nil, fmt.Errorf("updating status: %w", err)
-		}
-	}
-
-	// Persist changes
-	if err := s.repository.Update(ctx, todo); err != nil {
-		return nil, fmt.Errorf("updating todo: %w", err)
-	}
-
-	// Dispatch domain events
-	if err := s.dispatcher.Dispatch(ctx, todo.Events()); err != nil {
-		return nil, fmt.Errorf("dispatching events: %w", err)
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
 	}
 
 	// Clear events after dispatching
 	todo.ClearEvents()
 
+	// Track per-user recent activity
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
 	// Map to response DTO
 	return MapTodoToResponse(todo), nil
 }
@@ -200,8 +289,12 @@ func (s *TodoApplicationService) CompleteTodo(
 	ctx context.Context,
 	id string,
 ) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
 	// Parse and validate ID
-	todoID, err := domain.ParseTodoID(id)
+	todoID, err := s.resolveTodoID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid todo ID: %w", err)
 	}
@@ -212,24 +305,36 @@ func (s *TodoApplicationService) CompleteTodo(
 		return nil, fmt.Errorf("finding todo: %w", err)
 	}
 
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionComplete, todo); err != nil {
+		return nil, err
+	}
+
 	// Complete the todo
 	if err := todo.Complete(); err != nil {
 		return nil, fmt.Errorf("completing todo: %w", err)
 	}
 
-	// Persist changes
-	if err := s.repository.Update(ctx, todo); err != nil {
-		return nil, fmt.Errorf("updating todo: %w", err)
-	}
-
-	// Dispatch domain events
-	if err := s.dispatcher.Dispatch(ctx, todo.Events()); err != nil {
-		return nil, fmt.Errorf("dispatching events: %w", err)
+	// Persist changes and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Update(ctx, todo); err != nil {
+			return nil, fmt.Errorf("updating todo: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
 	}
 
 	// Clear events after dispatching
 	todo.ClearEvents()
 
+	// Track per-user recent activity and completions
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+	s.recordCompletion(ctx, todo.ID())
+
 	// Map to response DTO
 	return MapTodoToResponse(todo), nil
 }
@@ -239,8 +344,12 @@ func (s *TodoApplicationService) ReopenTodo(
 	ctx context.Context,
 	id string,
 ) (*TodoResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
 	// Parse and validate ID
-	todoID, err := domain.ParseTodoID(id)
+	todoID, err := s.resolveTodoID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid todo ID: %w", err)
 	}
@@ -251,50 +360,174 @@ func (s *TodoApplicationService) ReopenTodo(
 		return nil, fmt.Errorf("finding todo: %w", err)
 	}
 
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionReopen, todo); err != nil {
+		return nil, err
+	}
+
 	// Reopen the todo
 	if err := todo.Reopen(); err != nil {
 		return nil, fmt.Errorf("reopening todo: %w", err)
 	}
 
-	// Persist changes
-	if err := s.repository.Update(ctx, todo); err != nil {
-		return nil, fmt.Errorf("updating todo: %w", err)
-	}
-
-	// Dispatch domain events
-	if err := s.dispatcher.Dispatch(ctx, todo.Events()); err != nil {
-		return nil, fmt.Errorf("dispatching events: %w", err)
+	// Persist changes and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Update(ctx, todo); err != nil {
+			return nil, fmt.Errorf("updating todo: %w", err)
+		}
+		return todo.Events(), nil
+	}); err != nil {
+		return nil, err
 	}
 
 	// Clear events after dispatching
 	todo.ClearEvents()
 
+	// Track per-user recent activity
+	s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+
 	// Map to response DTO
 	return MapTodoToResponse(todo), nil
 }
 
+// applyUpdate applies the fields set in req to todo through its domain
+// methods
+func applyUpdate(todo *domain.Todo, req UpdateTodoRequest) error {
+	// Update title if provided
+	if req.Title != nil {
+		title, err := domain.NewTaskTitle(*req.Title)
+		if err != nil {
+			return fmt.Errorf("invalid title: %w", err)
+		}
+		if err := todo.UpdateTitle(title); err != nil {
+			return fmt.Errorf("updating title: %w", err)
+		}
+	}
+
+	// Update description if provided
+	if req.Description != nil {
+		if err := todo.UpdateDescription(*req.Description); err != nil {
+			return fmt.Errorf("updating description: %w", err)
+		}
+	}
+
+	// Update priority if provided
+	if req.Priority != nil {
+		priority, err := domain.NewPriority(*req.Priority)
+		if err != nil {
+			return fmt.Errorf("invalid priority: %w", err)
+		}
+		if err := todo.UpdatePriority(priority); err != nil {
+			return fmt.Errorf("updating priority: %w", err)
+		}
+	}
+
+	// Update due date if provided
+	if req.DueDate != nil {
+		var dueDate *domain.DueDate
+		if *req.DueDate != (time.Time{}) {
+			dd, err := domain.NewDueDate(*req.DueDate)
+			if err != nil {
+				return fmt.Errorf("invalid due date: %w", err)
+			}
+			dueDate = &dd
+		}
+		if err := todo.UpdateDueDate(dueDate); err != nil {
+			return fmt.Errorf("updating due date: %w", err)
+		}
+	}
+
+	// Update status if provided
+	if req.Status != nil {
+		status, err := domain.NewTaskStatus(*req.Status)
+		if err != nil {
+			return fmt.Errorf("invalid status: %w", err)
+		}
+		if err := todo.UpdateStatus(status); err != nil {
+			return fmt.Errorf("updating status: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // DeleteTodo deletes a todo
 func (s *TodoApplicationService) DeleteTodo(
 	ctx context.Context,
 	id string,
 ) error {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return err
+	}
+
 	// Parse and validate ID
-	todoID, err := domain.ParseTodoID(id)
+	todoID, err := s.resolveTodoID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("invalid todo ID: %w", err)
 	}
 
-	// Delete from repository
-	if err := s.repository.Delete(ctx, todoID); err != nil {
-		return fmt.Errorf("deleting todo: %w", err)
+	// Roles do not depend on the todo, so they are checked without loading it
+	if err := checkRole(ctx, ActionDelete); err != nil {
+		return err
+	}
+
+	if err := s.checkNotHeld(ctx, todoID); err != nil {
+		return err
+	}
+	if err := s.checkNotFrozen(ctx, todoID); err != nil {
+		return err
 	}
 
-	// Create and dispatch deleted event
-	deletedEvent := domain.NewTodoDeletedEvent(todoID)
-	if err := s.dispatcher.Dispatch(ctx, []domain.DomainEvent{deletedEvent}); err != nil {
-		return fmt.Errorf("dispatching events: %w", err)
+	// Policies decide on the todo being deleted, canaries must be noticed,
+	// and the deleted event carries the owner, for watchers
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("finding todo: %w", err)
+	}
+	if err := s.tripCanary(ctx, todo); err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, ActionDelete, todo); err != nil {
+		return err
+	}
+
+	// Delete from repository and dispatch the deleted event
+	return s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.repository.Delete(ctx, todoID); err != nil {
+			return nil, fmt.Errorf("deleting todo: %w", err)
+		}
+		return []domain.DomainEvent{domain.NewTodoDeletedEvent(todoID, todo.OwnerID())}, nil
+	})
+}
+
+// applySort validates the requested ordering and sets it on filters
+func applySort(filters *ports.Filters, sortBy, sortOrder *string) error {
+	field := ports.SortByCreatedAt
+	if sortBy != nil {
+		field = ports.SortField(strings.ToLower(*sortBy))
+	}
+
+	order := ports.SortDescending
+	switch field {
+	case ports.SortByCreatedAt, ports.SortByUpdatedAt, ports.SortByPriority:
+	case ports.SortByDueDate, ports.SortByTitle:
+		order = ports.SortAscending
+	default:
+		return domain.NewValidationError("sort_by", "must be one of created_at, updated_at, due_date, priority or title")
+	}
+
+	if sortOrder != nil {
+		order = ports.SortOrder(strings.ToLower(*sortOrder))
+		if order != ports.SortAscending && order != ports.SortDescending {
+			return domain.NewValidationError("sort_order", "must be asc or desc")
+		}
 	}
 
+	filters.SortBy = field
+	filters.SortOrder = order
 	return nil
 }
 
@@ -303,6 +536,10 @@ func (s *TodoApplicationService) ListTodos(
 	ctx context.Context,
 	filters ListFilters,
 ) (*ListTodosResponse, error) {
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
 	// Convert application filters to repository filters
 	repoFilters := ports.Filters{
 		Limit:  filters.Limit,
@@ -325,15 +562,70 @@ func (s *TodoApplicationService) ListTodos(
 		repoFilters.Priority = &priority
 	}
 
+	if err := applySort(&repoFilters, filters.SortBy, filters.SortOrder); err != nil {
+		return nil, err
+	}
+
+	if err := applyArchived(&repoFilters, filters.Archived); err != nil {
+		return nil, err
+	}
+
+	descriptionLimit, err := parseDescriptionMode(filters.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	// Omitted parameters fall back to the client's stored defaults
+	sorted := filters.SortBy != nil || filters.SortOrder != nil
+	if err := s.applyClientProfile(ctx, &repoFilters, sorted); err != nil {
+		return nil, err
+	}
+
+	// Pages too large or too deep for the database are rewritten or rejected
+	var warnings []string
+	if s.queryGuard != nil {
+		var err error
+		if warnings, err = s.queryGuard.guardList(ctx, &repoFilters); err != nil {
+			return nil, err
+		}
+	}
+
 	// Retrieve todos from repository
 	todos, err := s.repository.FindAll(ctx, repoFilters)
 	if err != nil {
 		return nil, fmt.Errorf("finding todos: %w", err)
 	}
 
-	// Map to response DTOs
+	// A page needs a separate count of every matching todo
+	totalCount := len(todos)
+	if repoFilters.Limit != nil || repoFilters.Offset != nil {
+		totalCount, err = s.repository.Count(ctx, repoFilters)
+		if err != nil {
+			return nil, fmt.Errorf("counting todos: %w", err)
+		}
+	}
+
+	// Map to response DTOs, slimming the descriptions when asked to
+	responses := MapTodosToResponse(todos)
+	trimDescriptions(responses, descriptionLimit)
 	return &ListTodosResponse{
-		Todos:      MapTodosToResponse(todos),
-		TotalCount: len(todos),
+		Todos:      responses,
+		TotalCount: totalCount,
+		Warnings:   warnings,
 	}, nil
 }
+
+// resolveTodoID parses a todo ID, resolving short codes when enabled
+func (s *TodoApplicationService) resolveTodoID(ctx context.Context, id string) (domain.TodoID, error) {
+	if s.shortCodes != nil {
+		if code, err := domain.ParseShortCode(id); err == nil {
+			todoID, err := s.shortCodes.FindIDByShortCode(ctx, code)
+			if err != nil {
+				return "", fmt.Errorf("short code %s: %w", code, err)
+			}
+			return todoID, nil
+		}
+	}
+
+	return domain.ParseTodoID(id)
+}
@@ -16,6 +16,7 @@ type MockTodoRepository struct {
 	SaveFunc     func(ctx context.Context, todo *domain.Todo) error
 	FindByIDFunc func(ctx context.Context, id domain.TodoID) (*domain.Todo, error)
 	FindAllFunc  func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error)
+	CountFunc    func(ctx context.Context, filters ports.Filters) (int, error)
 	UpdateFunc   func(ctx context.Context, todo *domain.Todo) error
 	DeleteFunc   func(ctx context.Context, id domain.TodoID) error
 }
@@ -41,6 +42,13 @@ func (m *MockTodoRepository) FindAll(ctx context.Context, filters ports.Filters)
 	return []*domain.Todo{}, nil
 }
 
+func (m *MockTodoRepository) Count(ctx context.Context, filters ports.Filters) (int, error) {
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx, filters)
+	}
+	return 0, nil
+}
+
 func (m *MockTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, todo)
@@ -124,6 +132,35 @@ func TestTodoService_CreateTodo_ValidRequest_Success(t *testing.T) {
 	}
 }
 
+func TestTodoService_CreateTodo_AssignsAuthenticatedOwner(t *testing.T) {
+	var saved *domain.Todo
+	mockRepo := &MockTodoRepository{
+		SaveFunc: func(ctx context.Context, todo *domain.Todo) error {
+			saved = todo
+			return nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+	req := CreateTodoRequest{Title: "Buy groceries", Priority: "medium"}
+
+	ctx := ContextWithUserID(context.Background(), "alice")
+	result, err := service.CreateTodo(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateTodo() unexpected error: %v", err)
+	}
+	if saved.OwnerID() != "alice" || result.OwnerID != "alice" {
+		t.Errorf("OwnerID = %q, want alice", saved.OwnerID())
+	}
+
+	// Anonymous todos are unowned
+	if _, err := service.CreateTodo(context.Background(), req); err != nil {
+		t.Fatalf("CreateTodo() unexpected error: %v", err)
+	}
+	if saved.OwnerID() != "" {
+		t.Errorf("OwnerID = %q, want unowned", saved.OwnerID())
+	}
+}
+
 func TestTodoService_CreateTodo_InvalidTitle_ReturnsError(t *testing.T) {
 	mockRepo := &MockTodoRepository{}
 	mockDispatcher := &MockEventDispatcher{}
@@ -348,7 +385,11 @@ func TestTodoService_ReopenTodo_CompletedTodo_Success(t *testing.T) {
 
 func TestTodoService_DeleteTodo_ExistingTodo_Success(t *testing.T) {
 	testTodo := createTestTodo()
-	mockRepo := &MockTodoRepository{}
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return testTodo, nil
+		},
+	}
 	mockDispatcher := &MockEventDispatcher{}
 	service := NewTodoApplicationService(mockRepo, mockDispatcher)
 
@@ -399,6 +440,81 @@ func TestTodoService_ListTodos_NoFilters_ReturnsAll(t *testing.T) {
 	}
 }
 
+func TestTodoService_ListTodos_Paged_CountsAllMatches(t *testing.T) {
+	var counted ports.Filters
+	mockRepo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			return []*domain.Todo{createTestTodo(), createTestTodo()}, nil
+		},
+		CountFunc: func(ctx context.Context, filters ports.Filters) (int, error) {
+			counted = filters
+			return 42, nil
+		},
+	}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+
+	limit, offset, status := 2, 10, "pending"
+	result, err := service.ListTodos(context.Background(), ListFilters{Status: &status, Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("ListTodos() unexpected error: %v", err)
+	}
+
+	if len(result.Todos) != 2 || result.TotalCount != 42 {
+		t.Errorf("got %d todos of %d, want 2 of 42", len(result.Todos), result.TotalCount)
+	}
+	if counted.Status == nil || *counted.Status != domain.StatusPending {
+		t.Errorf("Count() filters = %+v, want the status filter", counted)
+	}
+}
+
+func TestTodoService_ListTodos_Sort(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		sortBy    *string
+		sortOrder *string
+		wantBy    ports.SortField
+		wantOrder ports.SortOrder
+		wantErr   bool
+	}{
+		{name: "default", wantBy: ports.SortByCreatedAt, wantOrder: ports.SortDescending},
+		{name: "due date ascends", sortBy: str("due_date"), wantBy: ports.SortByDueDate, wantOrder: ports.SortAscending},
+		{name: "priority descends", sortBy: str("PRIORITY"), wantBy: ports.SortByPriority, wantOrder: ports.SortDescending},
+		{name: "explicit order", sortBy: str("title"), sortOrder: str("desc"), wantBy: ports.SortByTitle, wantOrder: ports.SortDescending},
+		{name: "unknown field", sortBy: str("status"), wantErr: true},
+		{name: "unknown order", sortOrder: str("up"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ports.Filters
+			mockRepo := &MockTodoRepository{
+				FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+					got = filters
+					return nil, nil
+				},
+			}
+			service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{})
+
+			_, err := service.ListTodos(context.Background(), ListFilters{SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+			if tt.wantErr {
+				var validationErr domain.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("ListTodos() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTodos() unexpected error: %v", err)
+			}
+
+			if got.SortBy != tt.wantBy || got.SortOrder != tt.wantOrder {
+				t.Errorf("Filters sort = %s %s, want %s %s", got.SortBy, got.SortOrder, tt.wantBy, tt.wantOrder)
+			}
+		})
+	}
+}
+
 func TestTodoService_ListTodos_WithStatusFilter_FiltersCorrectly(t *testing.T) {
 	mockRepo := &MockTodoRepository{
 		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
@@ -465,3 +581,59 @@ func TestTodoService_CreateTodo_WithPastDueDate_ReturnsError(t *testing.T) {
 		t.Error("CreateTodo() expected error for past due date, got nil")
 	}
 }
+
+// MockShortCodeResolver resolves short codes from a map
+type MockShortCodeResolver map[domain.ShortCode]domain.TodoID
+
+func (m MockShortCodeResolver) FindIDByShortCode(ctx context.Context, code domain.ShortCode) (domain.TodoID, error) {
+	if id, ok := m[code]; ok {
+		return id, nil
+	}
+	return "", domain.ErrTodoNotFound
+}
+
+func TestTodoService_GetTodo_ByShortCode_Success(t *testing.T) {
+	testTodo := createTestTodo()
+	testTodo.AssignShortCode(1042)
+
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if id != testTodo.ID() {
+				return nil, domain.ErrTodoNotFound
+			}
+			return testTodo, nil
+		},
+	}
+	resolver := MockShortCodeResolver{1042: testTodo.ID()}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithShortCodes(resolver))
+
+	result, err := service.GetTodo(context.Background(), "TD-1042")
+
+	if err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	if result.ID != testTodo.ID().String() || result.ShortCode != "TD-1042" {
+		t.Errorf("GetTodo() = %s (%s), want %s (TD-1042)", result.ID, result.ShortCode, testTodo.ID())
+	}
+}
+
+func TestTodoService_GetTodo_UnknownShortCode_ReturnsNotFound(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithShortCodes(MockShortCodeResolver{}))
+
+	_, err := service.GetTodo(context.Background(), "TD-7")
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("GetTodo() error = %v, want %v", err, domain.ErrTodoNotFound)
+	}
+}
+
+func TestTodoService_GetTodo_ShortCodesDisabled_ReturnsInvalidID(t *testing.T) {
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+
+	_, err := service.GetTodo(context.Background(), "TD-7")
+
+	if !errors.Is(err, domain.ErrInvalidID) {
+		t.Errorf("GetTodo() error = %v, want %v", err, domain.ErrInvalidID)
+	}
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// todoistPriorities maps the priorities of the Todoist API, 4 being the p1
+// of the apps, onto those of todos
+var todoistPriorities = map[int]domain.Priority{
+	4: domain.PriorityUrgent,
+	3: domain.PriorityHigh,
+	2: domain.PriorityMedium,
+	1: domain.PriorityLow,
+}
+
+// ImportFromTodoist creates a todo from each active task of the Todoist
+// account read by client, as ImportTodos would
+// Todos have no projects: the name of the project of a task is added to
+// the end of its description. Tasks due on a day are due by its end, and
+// recurring tasks at their next occurrence. A task already overdue keeps
+// its past due date, as a restore does
+func (s *TodoApplicationService) ImportFromTodoist(ctx context.Context, client ports.TodoistClient, req TodoistImportRequest) (*TodoistImportResponse, error) {
+	if err := s.maintenance.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if s.batchSaver == nil {
+		return nil, ErrNotSupported
+	}
+
+	location := req.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	projects, err := client.Projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+	for _, name := range req.Projects {
+		if !slices.ContainsFunc(projects, func(p ports.TodoistProject) bool { return p.Name == name }) {
+			return nil, domain.NewValidationError("projects", fmt.Sprintf("no Todoist project is named %q", name))
+		}
+	}
+
+	tasks, err := client.Tasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []ports.TodoistTask
+	for _, task := range tasks {
+		if len(req.Projects) == 0 || slices.Contains(req.Projects, projectNames[task.ProjectID]) {
+			selected = append(selected, task)
+		}
+	}
+	if len(selected) == 0 {
+		return &TodoistImportResponse{Failures: []TodoistImportFailure{}}, nil
+	}
+
+	// Rows are numbered after the tasks, from 1
+	rows := make([]ImportRow, len(selected))
+	for i, task := range selected {
+		rows[i] = ImportRow{Line: i + 1, KeepPastDueDate: true}
+		rows[i].Request, rows[i].Err = mapTodoistTask(task, projectNames[task.ProjectID], location)
+	}
+
+	imported, err := s.ImportTodos(ctx, ImportTodosRequest{Rows: rows, Progress: req.Progress})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TodoistImportResponse{Imported: len(imported.Imported), Failures: []TodoistImportFailure{}}
+	for _, failure := range imported.Failures {
+		task := selected[failure.Line-1]
+		response.Failures = append(response.Failures, TodoistImportFailure{TaskID: task.ID, Content: task.Content, Err: failure.Err})
+	}
+	return response, nil
+}
+
+// mapTodoistTask converts a Todoist task of project to the request creating
+// its todo; floating due dates are read in location
+func mapTodoistTask(task ports.TodoistTask, project string, location *time.Location) (CreateTodoRequest, error) {
+	req := CreateTodoRequest{
+		Title:       strings.TrimSpace(task.Content),
+		Description: strings.TrimSpace(task.Description),
+		Priority:    domain.DefaultPriority().String(),
+	}
+	if priority, ok := todoistPriorities[task.Priority]; ok {
+		req.Priority = priority.String()
+	}
+	if project != "" {
+		if req.Description != "" {
+			req.Description += "\n\n"
+		}
+		req.Description += "Todoist project: " + project
+	}
+
+	if task.Due != nil {
+		dueDate, err := todoistDueDate(*task.Due, location)
+		if err != nil {
+			return req, err
+		}
+		req.DueDate = &dueDate
+	}
+	return req, nil
+}
+
+// todoistDueDate returns the time a task is due: its time of day, or the end
+// of its day
+func todoistDueDate(due ports.TodoistDue, location *time.Location) (time.Time, error) {
+	if due.Timezone != "" {
+		zone, err := time.LoadLocation(due.Timezone)
+		if err != nil {
+			return time.Time{}, domain.NewValidationError("due_date", fmt.Sprintf("unknown time zone %q", due.Timezone))
+		}
+		location = zone
+	}
+
+	if due.Clock == "" {
+		day, err := time.ParseInLocation(time.DateOnly, due.Date, location)
+		if err != nil {
+			return time.Time{}, domain.NewValidationError("due_date", fmt.Sprintf("invalid day %q", due.Date))
+		}
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	at, err := time.ParseInLocation(time.DateTime, due.Date+" "+due.Clock, location)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError("due_date", fmt.Sprintf("invalid time %q %q", due.Date, due.Clock))
+	}
+	return at, nil
+}
@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockTodoistClient serves the projects and tasks of a Todoist account
+type MockTodoistClient struct {
+	ProjectList []ports.TodoistProject
+	TaskList    []ports.TodoistTask
+	Err         error
+}
+
+func (m *MockTodoistClient) Projects(ctx context.Context) ([]ports.TodoistProject, error) {
+	return m.ProjectList, m.Err
+}
+
+func (m *MockTodoistClient) Tasks(ctx context.Context) ([]ports.TodoistTask, error) {
+	return m.TaskList, m.Err
+}
+
+// todoistAccount has an Inbox and a Work project, with a task in each
+// and an overdue one at work
+func todoistAccount() *MockTodoistClient {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	return &MockTodoistClient{
+		ProjectList: []ports.TodoistProject{{ID: "p1", Name: "Inbox"}, {ID: "p2", Name: "Work"}},
+		TaskList: []ports.TodoistTask{
+			{ID: "t1", ProjectID: "p1", Content: "Buy milk", Priority: 1},
+			{ID: "t2", ProjectID: "p2", Content: "Ship the release", Description: "v2.0", Priority: 4, Due: &ports.TodoistDue{Date: tomorrow, Clock: "09:30:00", Timezone: "Europe/Paris"}},
+			{ID: "t3", ProjectID: "p2", Content: "File the expenses", Priority: 3, Due: &ports.TodoistDue{Date: "2020-01-02"}},
+		},
+	}
+}
+
+func TestTodoService_ImportFromTodoist(t *testing.T) {
+	saver := &MockTodoBatchSaver{}
+	service := NewTodoApplicationService(newMergeTestRepository(), &MockEventDispatcher{}, WithImports(saver))
+	var progress []ImportProgress
+
+	result, err := service.ImportFromTodoist(ContextWithUserID(context.Background(), "alice"), todoistAccount(), TodoistImportRequest{
+		Progress: func(p ImportProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("ImportFromTodoist() unexpected error: %v", err)
+	}
+
+	if result.Imported != 3 || len(result.Failures) != 0 {
+		t.Errorf("ImportFromTodoist() = %+v, want 3 todos imported", result)
+	}
+	if len(progress) != 1 || progress[0] != (ImportProgress{Saved: 3, Failed: 0, Total: 3}) {
+		t.Errorf("progress = %+v, want one report of the batch", progress)
+	}
+
+	if len(saver.Batches) != 1 || len(saver.Batches[0]) != 3 {
+		t.Fatalf("saved %v, want one batch of 3 todos", saver.Batches)
+	}
+	milk, release, expenses := saver.Batches[0][0], saver.Batches[0][1], saver.Batches[0][2]
+	if milk.Priority() != domain.PriorityLow || milk.Description() != "Todoist project: Inbox" || milk.DueDate() != nil {
+		t.Errorf("first todo = %q %s %q, want a low priority Inbox todo without due date", milk.Title(), milk.Priority(), milk.Description())
+	}
+	if release.Priority() != domain.PriorityUrgent || release.Description() != "v2.0\n\nTodoist project: Work" || release.OwnerID() != "alice" {
+		t.Errorf("second todo = %s %q owned by %q, want an urgent Work todo of alice", release.Priority(), release.Description(), release.OwnerID())
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	if due := release.DueDate(); due == nil || due.Time().In(paris).Format("15:04") != "09:30" {
+		t.Errorf("second todo due %v, want 09:30 in Paris", due)
+	}
+
+	// The overdue task keeps its past due date, the end of its day
+	wantDue := time.Date(2020, 1, 2, 23, 59, 59, 0, time.UTC)
+	if due := expenses.DueDate(); due == nil || !due.Time().Equal(wantDue) || !due.IsPast() {
+		t.Errorf("overdue todo due %v, want %v", due, wantDue)
+	}
+}
+
+func TestTodoService_ImportFromTodoist_Projects(t *testing.T) {
+	saver := &MockTodoBatchSaver{}
+	service := NewTodoApplicationService(newMergeTestRepository(), &MockEventDispatcher{}, WithImports(saver))
+
+	result, err := service.ImportFromTodoist(context.Background(), todoistAccount(), TodoistImportRequest{Projects: []string{"Inbox"}})
+	if err != nil {
+		t.Fatalf("ImportFromTodoist() unexpected error: %v", err)
+	}
+	if result.Imported != 1 || len(result.Failures) != 0 {
+		t.Errorf("ImportFromTodoist() = %+v, want only the Inbox task", result)
+	}
+
+	var validationErr domain.ValidationError
+	if _, err := service.ImportFromTodoist(context.Background(), todoistAccount(), TodoistImportRequest{Projects: []string{"Home"}}); !errors.As(err, &validationErr) {
+		t.Errorf("ImportFromTodoist() unknown project error = %v, want a validation error", err)
+	}
+}
+
+func TestTodoService_ImportFromTodoist_Errors(t *testing.T) {
+	errTodoist := errors.New("Todoist refused the token: 401 Unauthorized")
+
+	tests := []struct {
+		name    string
+		service *TodoApplicationService
+		client  *MockTodoistClient
+		want    error
+	}{
+		{"not supported", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}), todoistAccount(), ErrNotSupported},
+		{"maintenance", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithImports(&MockTodoBatchSaver{}), WithMaintenanceMode(NewMaintenanceMode(true, ""))), todoistAccount(), ErrMaintenanceMode},
+		{"todoist error", NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithImports(&MockTodoBatchSaver{})), &MockTodoistClient{Err: errTodoist}, errTodoist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.service.ImportFromTodoist(context.Background(), tt.client, TodoistImportRequest{}); !errors.Is(err, tt.want) {
+				t.Errorf("ImportFromTodoist() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoistDueDate(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name    string
+		due     ports.TodoistDue
+		want    time.Time
+		wantErr bool
+	}{
+		{"day", ports.TodoistDue{Date: "2030-01-02"}, time.Date(2030, 1, 2, 23, 59, 59, 0, newYork), false},
+		{"floating time", ports.TodoistDue{Date: "2030-01-02", Clock: "09:30:00"}, time.Date(2030, 1, 2, 9, 30, 0, 0, newYork), false},
+		{"fixed time", ports.TodoistDue{Date: "2030-01-02", Clock: "08:00:00", Timezone: "UTC"}, time.Date(2030, 1, 2, 8, 0, 0, 0, time.UTC), false},
+		{"unknown zone", ports.TodoistDue{Date: "2030-01-02", Timezone: "Mars/Olympus"}, time.Time{}, true},
+		{"invalid day", ports.TodoistDue{Date: "next week"}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := todoistDueDate(tt.due, newYork)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("todoistDueDate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("todoistDueDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapTodoistTask_UnknownPriority(t *testing.T) {
+	req, err := mapTodoistTask(ports.TodoistTask{Content: "  Water the plants  "}, "", time.UTC)
+	if err != nil {
+		t.Fatalf("mapTodoistTask() unexpected error: %v", err)
+	}
+	if req.Title != "Water the plants" || req.Priority != domain.DefaultPriority().String() || strings.Contains(req.Description, "project") {
+		t.Errorf("mapTodoistTask() = %+v, want the trimmed title and the default priority", req)
+	}
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Triage limits
+const (
+	// MaxTriageRules is the largest number of rules of a triage pass
+	MaxTriageRules = 20
+	// MaxTriageSize is the largest number of open todos a triage pass can
+	// go through
+	MaxTriageSize = 5000
+)
+
+// WithBatchUpdates enables TriageTodos
+func WithBatchUpdates(updater ports.TodoBatchUpdater) Option {
+	return func(s *TodoApplicationService) {
+		s.batchUpdater = updater
+	}
+}
+
+// triageRule is a validated TriageRule
+type triageRule struct {
+	TriageRule
+	priority    *domain.Priority
+	setPriority domain.Priority
+}
+
+// matches reports whether todo meets every condition of the rule at now
+func (r triageRule) matches(todo *domain.Todo, now time.Time) bool {
+	if r.Overdue && !todo.IsDue() {
+		return false
+	}
+	if r.NoDueDate && todo.DueDate() != nil {
+		return false
+	}
+	if r.OlderThanDays > 0 && !todo.CreatedAt().Before(now.AddDate(0, 0, -r.OlderThanDays)) {
+		return false
+	}
+	if r.priority != nil && todo.Priority() != *r.priority {
+		return false
+	}
+	return true
+}
+
+// TriageTodos sets the priority of the open todos of the caller by rule,
+// e.g. every overdue todo to urgent: each todo gets the priority of the
+// first rule it matches
+// The changes are saved in a single transaction, all or none; with DryRun
+// they are only listed. Passes over more than MaxTriageSize open todos are
+// refused rather than partial
+func (s *TodoApplicationService) TriageTodos(ctx context.Context, req TriageRequest) (*TriageReport, error) {
+	if !req.DryRun {
+		if err := s.maintenance.CheckWritable(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.batchUpdater == nil {
+		return nil, ErrNotSupported
+	}
+
+	rules, err := buildTriageRules(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	// The todos are read for a write, so never from a lagging replica
+	todos, more, err := s.findOpenTodos(ports.ContextWithWrittenAt(ctx, time.Now()), MaxTriageSize)
+	if err != nil {
+		return nil, err
+	}
+	if more {
+		return nil, domain.NewValidationError("rules", fmt.Sprintf("more than %d open todos to triage", MaxTriageSize))
+	}
+
+	report := &TriageReport{DryRun: req.DryRun, Changes: []TriageChange{}}
+	var changed []*domain.Todo
+	var priorities []domain.Priority
+	now := time.Now()
+	for _, todo := range todos {
+		for i, rule := range rules {
+			if !rule.matches(todo, now) {
+				continue
+			}
+			if todo.Priority() != rule.setPriority {
+				if err := s.authorize(ctx, ActionUpdate, todo); err != nil {
+					return nil, err
+				}
+				report.Changes = append(report.Changes, TriageChange{
+					TodoID: todo.ID().String(),
+					Title:  todo.Title().String(),
+					Rule:   i,
+					From:   todo.Priority().String(),
+					To:     rule.setPriority.String(),
+				})
+				changed = append(changed, todo)
+				priorities = append(priorities, rule.setPriority)
+			}
+			break
+		}
+	}
+
+	if req.DryRun || len(changed) == 0 {
+		return report, nil
+	}
+
+	for i, todo := range changed {
+		if err := todo.UpdatePriority(priorities[i]); err != nil {
+			return nil, fmt.Errorf("updating priority of %s: %w", todo.ID(), err)
+		}
+	}
+
+	// Persist changes and dispatch domain events
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.DomainEvent, error) {
+		if err := s.batchUpdater.UpdateMany(ctx, changed); err != nil {
+			return nil, fmt.Errorf("saving triage: %w", err)
+		}
+		var events []domain.DomainEvent
+		for _, todo := range changed {
+			events = append(events, todo.Events()...)
+		}
+		return events, nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, todo := range changed {
+		todo.ClearEvents()
+	}
+
+	for _, todo := range changed {
+		s.trackActivity(ctx, todo.ID(), ports.ActivityModified)
+	}
+
+	return report, nil
+}
+
+// buildTriageRules validates rules
+func buildTriageRules(rules []TriageRule) ([]triageRule, error) {
+	if len(rules) == 0 {
+		return nil, domain.NewValidationError("rules", "at least one rule is required")
+	}
+	if len(rules) > MaxTriageRules {
+		return nil, domain.NewValidationError("rules", fmt.Sprintf("at most %d rules are allowed", MaxTriageRules))
+	}
+
+	built := make([]triageRule, len(rules))
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if !rule.Overdue && !rule.NoDueDate && rule.OlderThanDays == 0 && rule.Priority == nil {
+			return nil, domain.NewValidationError(field, "at least one condition is required")
+		}
+		if rule.Overdue && rule.NoDueDate {
+			return nil, domain.NewValidationError(field, "a todo without due date cannot be overdue")
+		}
+		if rule.OlderThanDays < 0 {
+			return nil, domain.NewValidationError(field+".older_than_days", "must not be negative")
+		}
+
+		built[i] = triageRule{TriageRule: rule}
+		if rule.Priority != nil {
+			priority, err := domain.NewPriority(*rule.Priority)
+			if err != nil {
+				return nil, fmt.Errorf("%s.priority: %w", field, err)
+			}
+			built[i].priority = &priority
+		}
+		setPriority, err := domain.NewPriority(rule.SetPriority)
+		if err != nil {
+			return nil, fmt.Errorf("%s.set_priority: %w", field, err)
+		}
+		built[i].setPriority = setPriority
+	}
+
+	return built, nil
+}
+
+// findOpenTodos returns at most max pending and in progress todos that are
+// not archived, oldest first within each status, and whether there are more
+func (s *TodoApplicationService) findOpenTodos(ctx context.Context, max int) ([]*domain.Todo, bool, error) {
+	var todos []*domain.Todo
+	archived := false
+	for _, status := range []domain.TaskStatus{domain.StatusPending, domain.StatusInProgress} {
+		limit := max + 1 - len(todos)
+		if limit == 0 {
+			break
+		}
+		found, err := s.repository.FindAll(ctx, ports.Filters{
+			Status:    &status,
+			Archived:  &archived,
+			Limit:     &limit,
+			SortBy:    ports.SortByCreatedAt,
+			SortOrder: ports.SortAscending,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("finding %s todos: %w", status, err)
+		}
+		todos = append(todos, found...)
+	}
+
+	if len(todos) > max {
+		return todos[:max], true, nil
+	}
+
+	return todos, false, nil
+}
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockBatchUpdater records the batches it is asked to save
+type MockBatchUpdater struct {
+	Err     error
+	Batches [][]*domain.Todo
+}
+
+func (m *MockBatchUpdater) UpdateMany(ctx context.Context, todos []*domain.Todo) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Batches = append(m.Batches, todos)
+	return nil
+}
+
+// triageTodos returns an overdue todo, an old todo without due date and a
+// recent todo without due date, all of medium priority
+func triageTodos() (overdue, stale, fresh *domain.Todo) {
+	title, _ := domain.NewTaskTitle("Triaged")
+	now := time.Now()
+	dueDate := domain.ReconstituteDueDate(now.Add(-24 * time.Hour))
+	overdue = domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusPending, domain.PriorityMedium, &dueDate, now.AddDate(0, 0, -3), now, nil)
+	stale = domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusInProgress, domain.PriorityMedium, nil, now.AddDate(0, 0, -45), now, nil)
+	fresh = domain.ReconstituteTodo(domain.NewTodoID(), title, "", domain.StatusPending, domain.PriorityMedium, nil, now.AddDate(0, 0, -1), now, nil)
+	return overdue, stale, fresh
+}
+
+func newTriageService(updater *MockBatchUpdater, dispatcher *MockEventDispatcher, todos ...*domain.Todo) *TodoApplicationService {
+	repo := &MockTodoRepository{
+		FindAllFunc: func(ctx context.Context, filters ports.Filters) ([]*domain.Todo, error) {
+			var found []*domain.Todo
+			for _, todo := range todos {
+				if todo.Status() == *filters.Status {
+					found = append(found, todo)
+				}
+			}
+			return found, nil
+		},
+	}
+	return NewTodoApplicationService(repo, dispatcher, WithBatchUpdates(updater))
+}
+
+func triageRules() []TriageRule {
+	medium := "medium"
+	return []TriageRule{
+		{Overdue: true, SetPriority: "urgent"},
+		{NoDueDate: true, OlderThanDays: 30, Priority: &medium, SetPriority: "low"},
+	}
+}
+
+func TestTodoService_TriageTodos(t *testing.T) {
+	overdue, stale, fresh := triageTodos()
+	updater := &MockBatchUpdater{}
+	dispatcher := &MockEventDispatcher{}
+	service := newTriageService(updater, dispatcher, overdue, stale, fresh)
+
+	report, err := service.TriageTodos(context.Background(), TriageRequest{Rules: triageRules()})
+	if err != nil {
+		t.Fatalf("TriageTodos() unexpected error: %v", err)
+	}
+
+	want := map[string]TriageChange{
+		overdue.ID().String(): {Rule: 0, From: "medium", To: "urgent"},
+		stale.ID().String():   {Rule: 1, From: "medium", To: "low"},
+	}
+	if len(report.Changes) != len(want) {
+		t.Fatalf("Changes = %+v, want the overdue and the stale todos", report.Changes)
+	}
+	for _, change := range report.Changes {
+		w, ok := want[change.TodoID]
+		if !ok || change.Rule != w.Rule || change.From != w.From || change.To != w.To {
+			t.Errorf("change = %+v, want %+v", change, w)
+		}
+	}
+
+	if overdue.Priority() != domain.PriorityUrgent || stale.Priority() != domain.PriorityLow || fresh.Priority() != domain.PriorityMedium {
+		t.Errorf("priorities = %v, %v, %v, want urgent, low, medium", overdue.Priority(), stale.Priority(), fresh.Priority())
+	}
+	if len(updater.Batches) != 1 || len(updater.Batches[0]) != 2 {
+		t.Errorf("batches = %v, want both changes saved at once", updater.Batches)
+	}
+	if len(dispatcher.DispatchedEvents) != 2 {
+		t.Errorf("dispatched %d events, want 2", len(dispatcher.DispatchedEvents))
+	}
+
+	// A second pass has nothing left to change
+	report, err = service.TriageTodos(context.Background(), TriageRequest{Rules: triageRules()})
+	if err != nil {
+		t.Fatalf("TriageTodos() unexpected error: %v", err)
+	}
+	if len(report.Changes) != 0 || len(updater.Batches) != 1 {
+		t.Errorf("second pass changes = %+v, want none", report.Changes)
+	}
+}
+
+func TestTodoService_TriageTodos_DryRun(t *testing.T) {
+	overdue, stale, fresh := triageTodos()
+	updater := &MockBatchUpdater{}
+	dispatcher := &MockEventDispatcher{}
+	service := newTriageService(updater, dispatcher, overdue, stale, fresh)
+
+	report, err := service.TriageTodos(context.Background(), TriageRequest{Rules: triageRules(), DryRun: true})
+	if err != nil {
+		t.Fatalf("TriageTodos() unexpected error: %v", err)
+	}
+
+	if !report.DryRun || len(report.Changes) != 2 {
+		t.Errorf("report = %+v, want 2 previewed changes", report)
+	}
+	if overdue.Priority() != domain.PriorityMedium || len(updater.Batches) != 0 || len(dispatcher.DispatchedEvents) != 0 {
+		t.Error("dry run changed todos, want nothing saved")
+	}
+}
+
+func TestTodoService_TriageTodos_Errors(t *testing.T) {
+	saveErr := errors.New("connection lost")
+	unknown := "critical"
+
+	tests := []struct {
+		name    string
+		rules   []TriageRule
+		updater *MockBatchUpdater
+		wantErr error
+	}{
+		{name: "no rule", rules: nil, updater: &MockBatchUpdater{}},
+		{name: "rule without condition", rules: []TriageRule{{SetPriority: "low"}}, updater: &MockBatchUpdater{}},
+		{name: "contradictory rule", rules: []TriageRule{{Overdue: true, NoDueDate: true, SetPriority: "low"}}, updater: &MockBatchUpdater{}},
+		{name: "unknown priority", rules: []TriageRule{{Priority: &unknown, SetPriority: "low"}}, updater: &MockBatchUpdater{}, wantErr: domain.ErrInvalidPriority},
+		{name: "unknown target priority", rules: []TriageRule{{Overdue: true, SetPriority: unknown}}, updater: &MockBatchUpdater{}, wantErr: domain.ErrInvalidPriority},
+		{name: "save failure", rules: triageRules(), updater: &MockBatchUpdater{Err: saveErr}, wantErr: saveErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overdue, stale, fresh := triageTodos()
+			dispatcher := &MockEventDispatcher{}
+			service := newTriageService(tt.updater, dispatcher, overdue, stale, fresh)
+
+			_, err := service.TriageTodos(context.Background(), TriageRequest{Rules: tt.rules})
+
+			var validationErr domain.ValidationError
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("TriageTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !errors.As(err, &validationErr) {
+				t.Errorf("TriageTodos() error = %v, want a validation error", err)
+			}
+			if len(dispatcher.DispatchedEvents) != 0 {
+				t.Errorf("dispatched %d events, want none", len(dispatcher.DispatchedEvents))
+			}
+		})
+	}
+
+	unsupported := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{})
+	if _, err := unsupported.TriageTodos(context.Background(), TriageRequest{Rules: triageRules()}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("TriageTodos() without batch updates error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,267 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// ErrWatchLagged is returned when a watcher falls too far behind the changes
+// and is disconnected; it should watch again and reload what it displays
+var ErrWatchLagged = errors.New("watcher fell behind the changes")
+
+// ErrWatchResumeExpired is returned when a watch cannot be resumed after its
+// resume token; the watcher should watch from now on and reload what it
+// displays
+var ErrWatchResumeExpired = errors.New("watch can no longer be resumed")
+
+// WithEventSubscriber enables WatchTodos
+func WithEventSubscriber(subscriber ports.EventSubscriber) Option {
+	return func(s *TodoApplicationService) {
+		s.subscriber = subscriber
+	}
+}
+
+// TodoWatch is a subscription to the changes to todos matching filters,
+// opened by WatchTodos
+type TodoWatch struct {
+	service    *TodoApplicationService
+	ctx        context.Context
+	events     <-chan domain.DomainEvent
+	positioned <-chan ports.PositionedEvent
+	position   string
+	status     *domain.TaskStatus
+	priority   *domain.Priority
+	// pending are the changes of a plan not returned yet, the plan being
+	// consumed at pendingPosition once they all are
+	pending         []*TodoChange
+	pendingPosition string
+}
+
+// WatchTodos subscribes to the changes to todos matching filters, from now
+// until ctx is done
+// Deletions of the caller's todos are always reported, since a deleted todo
+// no longer has a status or a priority to filter on
+func (s *TodoApplicationService) WatchTodos(ctx context.Context, filters WatchFilters) (*TodoWatch, error) {
+	if s.subscriber == nil {
+		return nil, ErrNotSupported
+	}
+
+	watch := &TodoWatch{service: s, ctx: ctx}
+
+	if filters.Status != nil {
+		status, err := domain.NewTaskStatus(*filters.Status)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status filter: %w", err)
+		}
+		watch.status = &status
+	}
+
+	if filters.Priority != nil {
+		priority, err := domain.NewPriority(*filters.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority filter: %w", err)
+		}
+		watch.priority = &priority
+	}
+
+	if err := s.authorize(ctx, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	// Only resumable subscribers provide resume tokens
+	resumable, ok := s.subscriber.(ports.ResumableEventSubscriber)
+	if !ok {
+		if filters.After != "" {
+			return nil, ErrWatchResumeExpired
+		}
+		watch.events = s.subscriber.Subscribe(ctx)
+		return watch, nil
+	}
+
+	positioned, position, err := resumable.SubscribeFrom(ctx, filters.After)
+	if errors.Is(err, ports.ErrEventPositionExpired) {
+		return nil, ErrWatchResumeExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to changes: %w", err)
+	}
+	watch.positioned, watch.position = positioned, position
+	return watch, nil
+}
+
+// ResumeToken returns the token resuming the watch after the last event
+// Next consumed, whether it was a matching change or not, empty when the
+// watch cannot be resumed
+func (w *TodoWatch) ResumeToken() string {
+	return w.position
+}
+
+// Next waits for the next matching change until ctx is done
+// It returns the context error once ctx or the watch ends, and
+// ErrWatchLagged when the watcher fell behind and was disconnected
+// A ctx shorter than the watch only bounds the wait: the watch goes on
+func (w *TodoWatch) Next(ctx context.Context) (*TodoChange, error) {
+	for {
+		// Until its last change, resuming replays the whole plan
+		if len(w.pending) > 0 {
+			change := w.pending[0]
+			w.pending = w.pending[1:]
+			if len(w.pending) == 0 {
+				w.position = w.pendingPosition
+			}
+			change.ResumeToken = w.position
+			return change, nil
+		}
+
+		var event domain.DomainEvent
+		var position string
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case event, ok = <-w.events:
+		case positioned, open := <-w.positioned:
+			event, position, ok = positioned.Event, positioned.Position, open
+		}
+		if !ok {
+			if w.ctx.Err() != nil {
+				return nil, w.ctx.Err()
+			}
+			return nil, ErrWatchLagged
+		}
+
+		// The event is consumed: loading it must not be cut short by ctx
+		if plan, ok := event.(WeekPlanned); ok {
+			changes, err := w.service.planChanges(w.ctx, plan, w.status, w.priority)
+			if err != nil {
+				return nil, err
+			}
+			if len(changes) == 0 {
+				w.position = position
+			}
+			w.pending, w.pendingPosition = changes, position
+			continue
+		}
+
+		change, err := w.service.todoChange(w.ctx, event, w.status, w.priority)
+		if err != nil {
+			return nil, err
+		}
+		w.position = position
+		if change != nil {
+			change.ResumeToken = position
+			return change, nil
+		}
+	}
+}
+
+// planChanges converts a plan to the changes sent to watchers, one per
+// planned todo matching the filters
+func (s *TodoApplicationService) planChanges(
+	ctx context.Context,
+	plan WeekPlanned,
+	status *domain.TaskStatus,
+	priority *domain.Priority,
+) ([]*TodoChange, error) {
+	var changes []*TodoChange
+	for _, id := range plan.TodoIDs() {
+		todoID, err := domain.ParseTodoID(id)
+		if err != nil {
+			return nil, err
+		}
+		change, err := s.todoChange(ctx, domain.NewTodoUpdatedEvent(todoID), status, priority)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			change.OccurredAt = plan.OccurredAt()
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// todoChange converts event to the change sent to watchers, nil when the
+// todo does not match the filters or no longer exists
+func (s *TodoApplicationService) todoChange(
+	ctx context.Context,
+	event domain.DomainEvent,
+	status *domain.TaskStatus,
+	priority *domain.Priority,
+) (*TodoChange, error) {
+	// Reminders, security alerts and milestones are not changes of a todo
+	switch event.(type) {
+	case domain.TodoDueSoon, domain.TodoOverdue, SecurityAnomalyDetected, MilestoneCompleted:
+		return nil, nil
+	}
+
+	change := &TodoChange{
+		Kind:       string(watchKind(event)),
+		TodoID:     event.AggregateID(),
+		OccurredAt: event.OccurredAt(),
+	}
+	// A deleted todo cannot be loaded, so its owner is the one the event
+	// carries
+	if deleted, ok := event.(domain.TodoDeleted); ok {
+		if !ownsDeleted(ctx, deleted) {
+			return nil, nil
+		}
+		return change, nil
+	}
+
+	todoID, err := domain.ParseTodoID(event.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the current state: a burst of events on one todo each carry the
+	// latest state, which is what watchers display
+	todo, err := s.repository.FindByID(ctx, todoID)
+	if errors.Is(err, domain.ErrTodoNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding todo: %w", err)
+	}
+
+	if todo.IsCanary() {
+		return nil, nil
+	}
+	if status != nil && todo.Status() != *status {
+		return nil, nil
+	}
+	if priority != nil && todo.Priority() != *priority {
+		return nil, nil
+	}
+
+	change.Todo = MapTodoToResponse(todo)
+	return change, nil
+}
+
+// ownsDeleted reports whether the deleted todo belonged to the owner of
+// ctx; without an owner, only a ctx with system access sees it, as for
+// repository reads
+func ownsDeleted(ctx context.Context, event domain.TodoDeleted) bool {
+	if ownerID, ok := ports.OwnerFromContext(ctx); ok {
+		return event.OwnerID() == ownerID
+	}
+	return ports.HasSystemAccess(ctx)
+}
+
+// watchKind maps a domain event to the kind of change reported to watchers
+func watchKind(event domain.DomainEvent) ports.ActivityEventKind {
+	switch event.(type) {
+	case domain.TodoCreated:
+		return ports.ActivityCreated
+	case domain.TodoCompleted:
+		return ports.ActivityCompleted
+	case domain.TodoDeleted:
+		return ports.ActivityDeleted
+	default:
+		return ports.ActivityUpdated
+	}
+}
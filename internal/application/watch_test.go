@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockEventSubscriber delivers the events sent on Events to its subscriber
+type MockEventSubscriber struct {
+	Events chan domain.DomainEvent
+}
+
+func (m *MockEventSubscriber) Subscribe(ctx context.Context) <-chan domain.DomainEvent {
+	return m.Events
+}
+
+// MockResumableSubscriber replays Positioned after the requested position
+type MockResumableSubscriber struct {
+	MockEventSubscriber
+	Positioned chan ports.PositionedEvent
+	From       string
+	Expired    bool
+}
+
+func (m *MockResumableSubscriber) SubscribeFrom(ctx context.Context, position string) (<-chan ports.PositionedEvent, string, error) {
+	if m.Expired {
+		return nil, "", ports.ErrEventPositionExpired
+	}
+	m.From = position
+	if position == "" {
+		position = "p0"
+	}
+	return m.Positioned, position, nil
+}
+
+func TestTodoService_WatchTodos_FiltersChanges(t *testing.T) {
+	medium := createTestTodo()
+	title, _ := domain.NewTaskTitle("Urgent")
+	urgent := domain.NewTodo(title, "", domain.PriorityUrgent, nil)
+	todos := map[domain.TodoID]*domain.Todo{medium.ID(): medium, urgent.ID(): urgent}
+
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			if todo, ok := todos[id]; ok {
+				return todo, nil
+			}
+			return nil, domain.ErrTodoNotFound
+		},
+	}
+	subscriber := &MockEventSubscriber{Events: make(chan domain.DomainEvent, 4)}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithEventSubscriber(subscriber))
+
+	priority := "urgent"
+	watch, err := service.WatchTodos(ContextWithUserID(context.Background(), "alice"), WatchFilters{Priority: &priority})
+	if err != nil {
+		t.Fatalf("WatchTodos() unexpected error: %v", err)
+	}
+
+	deleted := domain.NewTodoID()
+	subscriber.Events <- domain.NewTodoUpdatedEvent(medium.ID())
+	subscriber.Events <- domain.NewTodoCompletedEvent(urgent.ID(), time.Now())
+	subscriber.Events <- domain.NewTodoUpdatedEvent(domain.NewTodoID())
+	subscriber.Events <- domain.NewTodoDeletedEvent(deleted, "alice")
+	close(subscriber.Events)
+
+	change, err := watch.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if change.Kind != "completed" || change.Todo == nil || change.Todo.ID != urgent.ID().String() {
+		t.Errorf("change = %+v, want the completion of the urgent todo", change)
+	}
+
+	// Deletions of the watcher's todos are reported whatever the filters
+	change, err = watch.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if change.Kind != "deleted" || change.TodoID != deleted.String() || change.Todo != nil {
+		t.Errorf("change = %+v, want the deletion of %v", change, deleted)
+	}
+
+	if _, err := watch.Next(context.Background()); !errors.Is(err, ErrWatchLagged) {
+		t.Errorf("Next() error = %v, want %v", err, ErrWatchLagged)
+	}
+}
+
+func TestTodoService_WatchTodos_OnlyOwnDeletions(t *testing.T) {
+	alices, bobs := domain.NewTodoID(), domain.NewTodoID()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []domain.TodoID
+	}{
+		{"alice", ContextWithUserID(context.Background(), "alice"), []domain.TodoID{alices}},
+		{"bob", ContextWithUserID(context.Background(), "bob"), []domain.TodoID{bobs}},
+		{"anonymous", context.Background(), nil},
+		{"system access", ports.ContextWithSystemAccess(context.Background()), []domain.TodoID{alices, bobs}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriber := &MockEventSubscriber{Events: make(chan domain.DomainEvent, 2)}
+			service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithEventSubscriber(subscriber))
+			watch, err := service.WatchTodos(tt.ctx, WatchFilters{})
+			if err != nil {
+				t.Fatalf("WatchTodos() unexpected error: %v", err)
+			}
+
+			subscriber.Events <- domain.NewTodoDeletedEvent(alices, "alice")
+			subscriber.Events <- domain.NewTodoDeletedEvent(bobs, "bob")
+			close(subscriber.Events)
+
+			var got []domain.TodoID
+			for {
+				change, err := watch.Next(context.Background())
+				if errors.Is(err, ErrWatchLagged) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next() unexpected error: %v", err)
+				}
+				got = append(got, domain.TodoID(change.TodoID))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("deletions = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("deletions = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoService_WatchTodos_Errors(t *testing.T) {
+	subscriber := &MockEventSubscriber{Events: make(chan domain.DomainEvent)}
+	invalid := "someday"
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		filters WatchFilters
+		wantErr error
+	}{
+		{"not supported", nil, WatchFilters{}, ErrNotSupported},
+		{"invalid status", []Option{WithEventSubscriber(subscriber)}, WatchFilters{Status: &invalid}, domain.ErrInvalidStatus},
+		{"invalid priority", []Option{WithEventSubscriber(subscriber)}, WatchFilters{Priority: &invalid}, domain.ErrInvalidPriority},
+		{
+			"forbidden",
+			[]Option{WithEventSubscriber(subscriber), WithAuthorizer(&MockAuthorizer{Allow: false})},
+			WatchFilters{},
+			ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, tt.opts...)
+
+			_, err := service.WatchTodos(context.Background(), tt.filters)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WatchTodos() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoWatch_Next_EndsWithContext(t *testing.T) {
+	subscriber := &MockEventSubscriber{Events: make(chan domain.DomainEvent)}
+	service := NewTodoApplicationService(&MockTodoRepository{}, &MockEventDispatcher{}, WithEventSubscriber(subscriber))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watch, err := service.WatchTodos(ctx, WatchFilters{})
+	if err != nil {
+		t.Fatalf("WatchTodos() unexpected error: %v", err)
+	}
+	cancel()
+
+	if _, err := watch.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTodoService_WatchTodos_Resumes(t *testing.T) {
+	todo := createTestTodo()
+	mockRepo := &MockTodoRepository{
+		FindByIDFunc: func(ctx context.Context, id domain.TodoID) (*domain.Todo, error) {
+			return todo, nil
+		},
+	}
+	subscriber := &MockResumableSubscriber{Positioned: make(chan ports.PositionedEvent, 2)}
+	service := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithEventSubscriber(subscriber))
+
+	watch, err := service.WatchTodos(ports.ContextWithSystemAccess(context.Background()), WatchFilters{After: "p4"})
+	if err != nil {
+		t.Fatalf("WatchTodos() unexpected error: %v", err)
+	}
+	if subscriber.From != "p4" || watch.ResumeToken() != "p4" {
+		t.Errorf("resumed from %q with token %q, want p4", subscriber.From, watch.ResumeToken())
+	}
+
+	subscriber.Positioned <- ports.PositionedEvent{Event: domain.NewTodoUpdatedEvent(todo.ID()), Position: "p5"}
+	change, err := watch.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if change.ResumeToken != "p5" || watch.ResumeToken() != "p5" {
+		t.Errorf("change token %q, watch token %q, want p5", change.ResumeToken, watch.ResumeToken())
+	}
+
+	// Waiting past a short context leaves the watch open
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := watch.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Next() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	subscriber.Positioned <- ports.PositionedEvent{Event: domain.NewTodoDeletedEvent(todo.ID(), ""), Position: "p6"}
+	if change, err := watch.Next(context.Background()); err != nil || change.ResumeToken != "p6" {
+		t.Errorf("Next() = %+v, %v, want the p6 deletion", change, err)
+	}
+
+	subscriber.Expired = true
+	if _, err := service.WatchTodos(context.Background(), WatchFilters{After: "p1"}); !errors.Is(err, ErrWatchResumeExpired) {
+		t.Errorf("WatchTodos(expired) error = %v, want %v", err, ErrWatchResumeExpired)
+	}
+
+	plain := NewTodoApplicationService(mockRepo, &MockEventDispatcher{}, WithEventSubscriber(&MockEventSubscriber{}))
+	if _, err := plain.WatchTodos(context.Background(), WatchFilters{After: "p1"}); !errors.Is(err, ErrWatchResumeExpired) {
+		t.Errorf("WatchTodos(not resumable) error = %v, want %v", err, ErrWatchResumeExpired)
+	}
+}
@@ -0,0 +1,780 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// Webhook errors
+var (
+	// ErrWebhookNotFound is returned for unknown webhook endpoint IDs
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrWebhookDeliveryNotFound is returned for unknown delivery IDs, or
+	// those of the deliveries to another endpoint
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrWebhookNotRedeliverable is returned when redelivering an attempt
+	// whose payload was not kept
+	ErrWebhookNotRedeliverable = errors.New("webhook delivery has no payload to redeliver")
+	// ErrWebhookBackfillsBusy is returned when registering an endpoint with
+	// a backfill while MaxPendingWebhookBackfills are waiting
+	ErrWebhookBackfillsBusy = errors.New("too many webhook backfills pending, try again later")
+)
+
+// MaxWebhookURLLength bounds the URL of a webhook endpoint
+const MaxWebhookURLLength = 2048
+
+// webhookSecretBytes is the entropy of a webhook signing secret
+const webhookSecretBytes = 32
+
+// Webhook backfill limits
+const (
+	// DefaultWebhookBackfillRate is the number of past events posted per
+	// second by a backfill, unless requested otherwise
+	DefaultWebhookBackfillRate = 10
+	// MaxWebhookBackfillRate bounds the rate of a backfill
+	MaxWebhookBackfillRate = 100
+	// MaxPendingWebhookBackfills is the number of backfills waiting for the
+	// one running to end
+	MaxPendingWebhookBackfills = 8
+	// webhookBackfillPage is the number of audit entries read at once
+	webhookBackfillPage = 100
+)
+
+// Webhook delivery log page sizes
+const (
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 200
+)
+
+// Webhook digest limits
+const (
+	// MaxWebhookDigestWindow bounds the digest window of an endpoint
+	MaxWebhookDigestWindow = time.Hour
+	// MaxDigestEvents is the number of events closing a digest before the
+	// end of its window
+	MaxDigestEvents = 100
+)
+
+// WebhookOptions controls the delivery of events to webhook endpoints
+type WebhookOptions struct {
+	// MaxAttempts is the number of times an event is posted to an endpoint
+	// before giving up
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; each next retry
+	// waits twice as long, up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Workers is the number of deliveries made at once
+	Workers int
+	// Queue is the number of deliveries waiting for a worker; events
+	// arriving when it is full are not delivered
+	Queue int
+}
+
+// DefaultWebhookOptions makes 8 attempts over about 2 minutes, 4 at a time
+func DefaultWebhookOptions() WebhookOptions {
+	return WebhookOptions{
+		MaxAttempts:    8,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Workers:        4,
+		Queue:          256,
+	}
+}
+
+// webhookDelivery is an event waiting to be posted to an endpoint, encoded
+// as body
+type webhookDelivery struct {
+	endpoint  ports.WebhookEndpoint
+	eventID   string
+	eventType string
+	body      []byte
+	attempt   int
+}
+
+// webhookBackfill is the replay of the past events of a period to a new
+// endpoint, at rate events per second
+type webhookBackfill struct {
+	endpoint ports.WebhookEndpoint
+	from     time.Time
+	to       time.Time
+	rate     int
+}
+
+// digestKey identifies the digest of the events on a todo for an endpoint
+type digestKey struct {
+	endpointID string
+	todoID     string
+}
+
+// openDigest collects the events on a todo for an endpoint until the end of
+// its digest window
+type openDigest struct {
+	endpoint ports.WebhookEndpoint
+	events   []domain.DomainEvent
+}
+
+// WebhookService manages the webhook endpoints and posts the dispatched
+// domain events to them
+// Deliveries run in the background, so a slow or failing endpoint never
+// delays the change that raised the event. Each attempt is recorded.
+// Endpoints with a digest window receive the events on a todo within the
+// window as a single ports.EventDigest, instead of one request per event.
+// New endpoints can be backfilled with the past events of the todo audit
+// trail, one backfill at a time
+type WebhookService struct {
+	store      ports.WebhookStore
+	sender     ports.WebhookSender
+	subscriber ports.EventSubscriber
+	history    ports.TodoAuditScanner
+	logger     *slog.Logger
+	options    WebhookOptions
+	queue      chan webhookDelivery
+	backfills  chan webhookBackfill
+	// after runs f after d, for the retries and the digest windows
+	after func(d time.Duration, f func())
+	// wait pauses a backfill for d, reporting false when ctx is done first
+	wait func(ctx context.Context, d time.Duration) bool
+
+	mu      sync.Mutex
+	digests map[digestKey]*openDigest
+}
+
+// NewWebhookService creates a new WebhookService
+// history is the audit trail replayed by backfills, nil to disable them
+func NewWebhookService(
+	store ports.WebhookStore,
+	sender ports.WebhookSender,
+	subscriber ports.EventSubscriber,
+	history ports.TodoAuditScanner,
+	logger *slog.Logger,
+	options WebhookOptions,
+) *WebhookService {
+	defaults := DefaultWebhookOptions()
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	if options.Workers < 1 {
+		options.Workers = defaults.Workers
+	}
+	if options.Queue < 1 {
+		options.Queue = defaults.Queue
+	}
+	return &WebhookService{
+		store:      store,
+		sender:     sender,
+		subscriber: subscriber,
+		history:    history,
+		logger:     logger,
+		options:    options,
+		queue:      make(chan webhookDelivery, options.Queue),
+		backfills:  make(chan webhookBackfill, MaxPendingWebhookBackfills),
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		wait: func(ctx context.Context, d time.Duration) bool {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		},
+		digests: map[digestKey]*openDigest{},
+	}
+}
+
+// Register adds an endpoint and returns it with its signing secret
+// The secret is only ever returned here. With a Backfill, the past events
+// of its period are then posted to the endpoint in the background
+func (s *WebhookService) Register(ctx context.Context, req WebhookRequest) (*WebhookCreated, error) {
+	rawURL := strings.TrimSpace(req.URL)
+	if len(rawURL) > MaxWebhookURLLength {
+		return nil, domain.NewValidationError("url", fmt.Sprintf("must be at most %d characters", MaxWebhookURLLength))
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, domain.NewValidationError("url", "must be an absolute http or https URL")
+	}
+
+	eventTypes := []string{}
+	for _, eventType := range req.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || strings.ContainsAny(eventType, " ,") {
+			return nil, domain.NewValidationError("event_types", fmt.Sprintf("invalid event type %q", eventType))
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	if req.DigestWindow < 0 || req.DigestWindow > MaxWebhookDigestWindow {
+		return nil, domain.NewValidationError("digest_window", fmt.Sprintf("must be between 0 and %s", MaxWebhookDigestWindow))
+	}
+
+	var backfill *webhookBackfill
+	if req.Backfill != nil {
+		var err error
+		if backfill, err = s.validateBackfill(*req.Backfill); err != nil {
+			return nil, err
+		}
+	}
+
+	random := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("generating webhook secret: %w", err)
+	}
+
+	endpoint := ports.WebhookEndpoint{
+		ID:           uuid.New().String(),
+		URL:          rawURL,
+		EventTypes:   eventTypes,
+		Secret:       base64.RawURLEncoding.EncodeToString(random),
+		DigestWindow: req.DigestWindow,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.store.Create(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("creating webhook: %w", err)
+	}
+
+	created := &WebhookCreated{Webhook: mapWebhook(endpoint), Secret: endpoint.Secret}
+	if backfill != nil {
+		backfill.endpoint = endpoint
+		select {
+		case s.backfills <- *backfill:
+			created.Backfilling = true
+		default:
+			// Another registration took the last place since the check
+			s.logger.Warn("webhook backfill queue full, backfill not started", "webhook_id", endpoint.ID)
+		}
+	}
+	return created, nil
+}
+
+// validateBackfill checks the backfill requested with a new endpoint
+// The period ends at the registration by default
+func (s *WebhookService) validateBackfill(req WebhookBackfillRequest) (*webhookBackfill, error) {
+	if s.history == nil {
+		return nil, ErrNotSupported
+	}
+
+	backfill := &webhookBackfill{from: req.From, to: req.To, rate: req.Rate}
+	now := time.Now()
+	if backfill.to.IsZero() || backfill.to.After(now) {
+		backfill.to = now
+	}
+	if backfill.from.IsZero() || !backfill.from.Before(backfill.to) {
+		return nil, domain.NewValidationError("backfill.from", "must be set, before the end of the backfill")
+	}
+	switch {
+	case backfill.rate == 0:
+		backfill.rate = DefaultWebhookBackfillRate
+	case backfill.rate < 0 || backfill.rate > MaxWebhookBackfillRate:
+		return nil, domain.NewValidationError("backfill.rate", fmt.Sprintf("must be between 1 and %d events per second", MaxWebhookBackfillRate))
+	}
+
+	if len(s.backfills) >= MaxPendingWebhookBackfills {
+		return nil, ErrWebhookBackfillsBusy
+	}
+	return backfill, nil
+}
+
+// List returns every endpoint, oldest first, without their secrets
+func (s *WebhookService) List(ctx context.Context) ([]*WebhookResponse, error) {
+	endpoints, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+
+	responses := make([]*WebhookResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		responses[i] = mapWebhook(endpoint)
+	}
+	return responses, nil
+}
+
+// ListDeliveries returns a page of the delivery attempts of an endpoint,
+// most recent first, so that integrators can see why deliveries failed
+// cursor is empty for the first page, then the NextCursor of the previous
+// page; a limit of zero or less selects DefaultWebhookDeliveryLimit and
+// larger limits are capped to MaxWebhookDeliveryLimit. The attempts of a
+// deleted endpoint are still listed
+func (s *WebhookService) ListDeliveries(ctx context.Context, endpointID string, req WebhookDeliveriesRequest) (*WebhookDeliveryPage, error) {
+	if _, err := uuid.Parse(endpointID); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	var before int64
+	if req.Cursor != "" {
+		var err error
+		before, err = strconv.ParseInt(req.Cursor, 10, 64)
+		if err != nil || before <= 0 {
+			return nil, domain.NewValidationError("cursor", "is invalid")
+		}
+	}
+
+	limit := req.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultWebhookDeliveryLimit
+	case limit > MaxWebhookDeliveryLimit:
+		limit = MaxWebhookDeliveryLimit
+	}
+
+	// One more attempt than requested tells whether there is a next page
+	deliveries, err := s.store.ListDeliveries(ctx, ports.WebhookDeliveryQuery{
+		EndpointID: endpointID,
+		FailedOnly: req.FailedOnly,
+		Before:     before,
+		Limit:      limit + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+
+	page := &WebhookDeliveryPage{}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		page.NextCursor = strconv.FormatInt(deliveries[limit-1].ID, 10)
+	}
+
+	page.Deliveries = make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		page.Deliveries[i] = mapWebhookDelivery(delivery)
+	}
+	return page, nil
+}
+
+// Redeliver posts the payload of a past delivery attempt to its endpoint
+// again, with the same event ID, and returns the new attempt
+// The endpoint is posted to once, at its current URL and signed with its
+// current secret; a failure is recorded and returned as the outcome of the
+// attempt, not as an error, and is not retried
+func (s *WebhookService) Redeliver(ctx context.Context, endpointID string, deliveryID int64) (*WebhookDeliveryResponse, error) {
+	if _, err := uuid.Parse(endpointID); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	past, err := s.store.FindDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("loading webhook delivery: %w", err)
+	}
+	if past == nil || past.EndpointID != endpointID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if past.Payload == nil {
+		return nil, ErrWebhookNotRedeliverable
+	}
+
+	endpoints, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	i := slices.IndexFunc(endpoints, func(endpoint ports.WebhookEndpoint) bool {
+		return endpoint.ID == endpointID
+	})
+	if i < 0 {
+		return nil, ErrWebhookNotFound
+	}
+
+	delivery := webhookDelivery{
+		endpoint:  endpoints[i],
+		eventID:   past.EventID,
+		eventType: past.EventType,
+		body:      past.Payload,
+		attempt:   1,
+	}
+	start := time.Now()
+	reply, sendErr := s.sender.Send(ctx, delivery.endpoint, delivery.eventID, delivery.eventType, delivery.body)
+	attempt := newWebhookAttempt(delivery, reply, time.Since(start), sendErr)
+	attempt.RedeliveryOf = past.ID
+
+	attempt.ID, err = s.store.RecordDelivery(ctx, attempt)
+	if err != nil {
+		return nil, fmt.Errorf("recording webhook delivery: %w", err)
+	}
+
+	return mapWebhookDelivery(attempt), nil
+}
+
+// Delete removes an endpoint
+// Retries already scheduled for it still run
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrWebhookNotFound
+	}
+
+	deleted, err := s.store.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("deleting webhook: %w", err)
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Run posts the dispatched events to the endpoints, and runs the backfills,
+// until ctx is done
+// Events dispatched while the subscription lags behind are not delivered;
+// pending retries, open digests and backfills are dropped on shutdown
+func (s *WebhookService) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case backfill := <-s.backfills:
+				s.backfill(ctx, backfill)
+			}
+		}
+	}()
+	for range s.options.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-s.queue:
+					s.deliver(ctx, delivery)
+				}
+			}
+		}()
+	}
+	defer workers.Wait()
+
+	events := s.subscriber.Subscribe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				s.logger.Warn("webhooks fell behind the events, resubscribing")
+				events = s.subscriber.Subscribe(ctx)
+				continue
+			}
+			if err := s.Notify(ctx, event); err != nil {
+				s.logger.Error("notifying webhooks failed", "event_type", event.EventType(), "error", err)
+			}
+		}
+	}
+}
+
+// Notify queues the delivery of event to the endpoints accepting it, or
+// adds it to their digest of its todo for those with a digest window
+// A delivery not fitting the queue is recorded as failed and dropped
+func (s *WebhookService) Notify(ctx context.Context, event domain.DomainEvent) error {
+	endpoints, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing webhooks: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Accepts(event.EventType()) {
+			continue
+		}
+
+		if endpoint.DigestWindow > 0 {
+			s.collect(ctx, endpoint, event)
+			continue
+		}
+		s.enqueue(ctx, endpoint, event)
+	}
+	return nil
+}
+
+// enqueue queues the first attempt of the delivery of event to endpoint
+// An event failing to encode is recorded as failed and dropped
+func (s *WebhookService) enqueue(ctx context.Context, endpoint ports.WebhookEndpoint, event domain.DomainEvent) {
+	delivery := webhookDelivery{endpoint: endpoint, eventID: webhookEventID(event), eventType: event.EventType(), attempt: 1}
+
+	body, err := s.sender.Encode(event)
+	if err != nil {
+		s.logger.Error("encoding webhook event failed",
+			"webhook_id", endpoint.ID, "event_type", event.EventType(), "error", err)
+		s.record(ctx, delivery, ports.WebhookReply{}, 0, err)
+		return
+	}
+	delivery.body = body
+
+	select {
+	case s.queue <- delivery:
+	default:
+		s.logger.Warn("webhook queue full, event not delivered",
+			"webhook_id", endpoint.ID, "event_type", event.EventType())
+		s.record(ctx, delivery, ports.WebhookReply{}, 0, errors.New("delivery queue full"))
+	}
+}
+
+// collect adds event to the digest of its todo for endpoint, opening one
+// closed at the end of the digest window of endpoint
+// A digest reaching MaxDigestEvents is closed at once, and the next event
+// opens a new one
+func (s *WebhookService) collect(ctx context.Context, endpoint ports.WebhookEndpoint, event domain.DomainEvent) {
+	key := digestKey{endpointID: endpoint.ID, todoID: event.AggregateID()}
+
+	s.mu.Lock()
+	digest, open := s.digests[key]
+	if !open {
+		digest = &openDigest{endpoint: endpoint}
+		s.digests[key] = digest
+	}
+	digest.events = append(digest.events, event)
+	full := len(digest.events) >= MaxDigestEvents
+	if full {
+		delete(s.digests, key)
+	}
+	s.mu.Unlock()
+
+	if full {
+		s.enqueue(ctx, endpoint, digest.event())
+		return
+	}
+	if !open {
+		s.after(endpoint.DigestWindow, func() {
+			s.closeDigest(ctx, key, digest)
+		})
+	}
+}
+
+// closeDigest queues the delivery of digest at the end of its window,
+// unless it was closed already or ctx is done
+func (s *WebhookService) closeDigest(ctx context.Context, key digestKey, digest *openDigest) {
+	s.mu.Lock()
+	if s.digests[key] != digest {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.digests, key)
+	s.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	s.enqueue(ctx, digest.endpoint, digest.event())
+}
+
+// event returns the event delivering the digest: its only event when no
+// other one came within the window, an EventDigest otherwise
+func (d *openDigest) event() domain.DomainEvent {
+	if len(d.events) == 1 {
+		return d.events[0]
+	}
+	return ports.EventDigest{TodoID: d.events[0].AggregateID(), Events: d.events}
+}
+
+// deliver makes an attempt of delivery, and schedules the next one after a
+// failure that may be temporary
+func (s *WebhookService) deliver(ctx context.Context, delivery webhookDelivery) {
+	start := time.Now()
+	reply, err := s.sender.Send(ctx, delivery.endpoint, delivery.eventID, delivery.eventType, delivery.body)
+	s.record(ctx, delivery, reply, time.Since(start), err)
+	if err == nil {
+		return
+	}
+
+	if delivery.attempt >= s.options.MaxAttempts || !retryableWebhookStatus(reply.StatusCode) {
+		s.logger.Warn("webhook delivery abandoned",
+			"webhook_id", delivery.endpoint.ID,
+			"event_type", delivery.eventType,
+			"attempts", delivery.attempt,
+			"error", err,
+		)
+		return
+	}
+
+	backoff := s.backoff(delivery.attempt)
+	delivery.attempt++
+	s.after(backoff, func() {
+		select {
+		case s.queue <- delivery:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// backfill posts the events recorded in the audit trail during the period
+// of backfill to its endpoint, oldest first, one at a time
+// Each event is retried like a live one, synchronously; the backfill stops
+// at the first event it gives up on, or when the endpoint is deleted
+func (s *WebhookService) backfill(ctx context.Context, backfill webhookBackfill) {
+	endpoint := backfill.endpoint
+	logger := s.logger.With("webhook_id", endpoint.ID)
+	logger.Info("webhook backfill started", "from", backfill.from, "to", backfill.to)
+
+	interval := time.Second / time.Duration(backfill.rate)
+	var after int64
+	sent := 0
+	for {
+		entries, err := s.history.ListBetween(ctx, backfill.from, backfill.to, after, webhookBackfillPage)
+		if err != nil {
+			logger.Error("webhook backfill failed", "events", sent, "error", err)
+			return
+		}
+		if len(entries) == 0 {
+			logger.Info("webhook backfill done", "events", sent)
+			return
+		}
+
+		endpoints, err := s.store.List(ctx)
+		if err != nil {
+			logger.Error("webhook backfill failed", "events", sent, "error", err)
+			return
+		}
+		if !slices.ContainsFunc(endpoints, func(e ports.WebhookEndpoint) bool { return e.ID == endpoint.ID }) {
+			logger.Info("webhook backfill stopped, endpoint deleted", "events", sent)
+			return
+		}
+
+		for _, entry := range entries {
+			after = entry.Seq
+			if !endpoint.Accepts(entry.EventType) {
+				continue
+			}
+			if !s.wait(ctx, interval) {
+				return
+			}
+
+			event := ports.ReplayedEvent{Entry: entry}
+			delivery := webhookDelivery{endpoint: endpoint, eventID: webhookEventID(event), eventType: event.EventType(), attempt: 1}
+			if delivery.body, err = s.sender.Encode(event); err != nil {
+				s.record(ctx, delivery, ports.WebhookReply{}, 0, err)
+				logger.Warn("webhook backfill abandoned", "events", sent, "occurred_at", entry.OccurredAt, "error", err)
+				return
+			}
+			if !s.deliverNow(ctx, delivery) {
+				logger.Warn("webhook backfill abandoned", "events", sent, "occurred_at", entry.OccurredAt)
+				return
+			}
+			sent++
+		}
+	}
+}
+
+// deliverNow makes the attempts of a delivery until one succeeds, waiting
+// between them like deliver, and reports whether the delivery succeeded
+func (s *WebhookService) deliverNow(ctx context.Context, delivery webhookDelivery) bool {
+	for {
+		start := time.Now()
+		reply, err := s.sender.Send(ctx, delivery.endpoint, delivery.eventID, delivery.eventType, delivery.body)
+		s.record(ctx, delivery, reply, time.Since(start), err)
+		if err == nil {
+			return true
+		}
+
+		if delivery.attempt >= s.options.MaxAttempts || !retryableWebhookStatus(reply.StatusCode) {
+			return false
+		}
+		if !s.wait(ctx, s.backoff(delivery.attempt)) {
+			return false
+		}
+		delivery.attempt++
+	}
+}
+
+// record logs a delivery attempt, which must not fail the delivery
+func (s *WebhookService) record(ctx context.Context, delivery webhookDelivery, reply ports.WebhookReply, duration time.Duration, err error) {
+	attempt := newWebhookAttempt(delivery, reply, duration, err)
+	if _, err := s.store.RecordDelivery(ctx, attempt); err != nil {
+		s.logger.Error("recording webhook delivery failed", "webhook_id", delivery.endpoint.ID, "error", err)
+	}
+}
+
+// newWebhookAttempt returns the log of an attempt of delivery, answered by
+// reply after duration
+func newWebhookAttempt(delivery webhookDelivery, reply ports.WebhookReply, duration time.Duration, err error) ports.WebhookDelivery {
+	attempt := ports.WebhookDelivery{
+		EndpointID:      delivery.endpoint.ID,
+		EventID:         delivery.eventID,
+		EventType:       delivery.eventType,
+		Attempt:         delivery.attempt,
+		StatusCode:      reply.StatusCode,
+		ResponseSnippet: reply.Body,
+		Payload:         delivery.body,
+		Duration:        duration,
+		AttemptedAt:     time.Now(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	return attempt
+}
+
+// backoff returns the delay before the retry following attempt
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	backoff := s.options.InitialBackoff
+	for i := 1; i < attempt && backoff < s.options.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.options.MaxBackoff)
+}
+
+// retryableWebhookStatus reports whether a failed delivery may succeed
+// later: no response, a server error, a timeout or throttling
+// Other client errors and redirects will fail the same way again
+func retryableWebhookStatus(status int) bool {
+	return status == 0 ||
+		status >= http.StatusInternalServerError ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests
+}
+
+// webhookEventID identifies event across the attempts of its deliveries
+func webhookEventID(event domain.DomainEvent) string {
+	return fmt.Sprintf("%s-%s-%d", event.EventType(), event.AggregateID(), event.OccurredAt().UnixNano())
+}
+
+// mapWebhook converts a stored endpoint to its response DTO
+func mapWebhook(endpoint ports.WebhookEndpoint) *WebhookResponse {
+	return &WebhookResponse{
+		ID:           endpoint.ID,
+		URL:          endpoint.URL,
+		EventTypes:   endpoint.EventTypes,
+		DigestWindow: endpoint.DigestWindow,
+		CreatedAt:    endpoint.CreatedAt,
+	}
+}
+
+// mapWebhookDelivery converts a delivery attempt to its response DTO
+func mapWebhookDelivery(delivery ports.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:              delivery.ID,
+		EventID:         delivery.EventID,
+		EventType:       delivery.EventType,
+		Attempt:         delivery.Attempt,
+		StatusCode:      delivery.StatusCode,
+		Error:           delivery.Error,
+		ResponseSnippet: delivery.ResponseSnippet,
+		RedeliveryOf:    delivery.RedeliveryOf,
+		Duration:        delivery.Duration,
+		AttemptedAt:     delivery.AttemptedAt,
+	}
+}
@@ -0,0 +1,689 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+	"github.com/pivaldi/mmw/todo/internal/ports"
+)
+
+// MockWebhookStore keeps endpoints and delivery attempts in memory
+type MockWebhookStore struct {
+	Endpoints  []ports.WebhookEndpoint
+	Deliveries []ports.WebhookDelivery
+}
+
+func (m *MockWebhookStore) Create(ctx context.Context, endpoint ports.WebhookEndpoint) error {
+	m.Endpoints = append(m.Endpoints, endpoint)
+	return nil
+}
+
+func (m *MockWebhookStore) List(ctx context.Context) ([]ports.WebhookEndpoint, error) {
+	return m.Endpoints, nil
+}
+
+func (m *MockWebhookStore) Delete(ctx context.Context, id string) (bool, error) {
+	for i, endpoint := range m.Endpoints {
+		if endpoint.ID == id {
+			m.Endpoints = append(m.Endpoints[:i], m.Endpoints[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockWebhookStore) RecordDelivery(ctx context.Context, delivery ports.WebhookDelivery) (int64, error) {
+	delivery.ID = int64(len(m.Deliveries) + 1)
+	m.Deliveries = append(m.Deliveries, delivery)
+	return delivery.ID, nil
+}
+
+func (m *MockWebhookStore) ListDeliveries(ctx context.Context, query ports.WebhookDeliveryQuery) ([]ports.WebhookDelivery, error) {
+	var deliveries []ports.WebhookDelivery
+	for _, delivery := range slices.Backward(m.Deliveries) {
+		if delivery.EndpointID != query.EndpointID ||
+			(query.FailedOnly && delivery.Error == "") ||
+			(query.Before > 0 && delivery.ID >= query.Before) {
+			continue
+		}
+		delivery.Payload = nil
+		deliveries = append(deliveries, delivery)
+		if len(deliveries) == query.Limit {
+			break
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookStore) FindDelivery(ctx context.Context, id int64) (*ports.WebhookDelivery, error) {
+	for _, delivery := range m.Deliveries {
+		if delivery.ID == id {
+			return &delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+// MockWebhookSender answers each call with the next of Statuses, failing
+// outside 2xx; events are encoded as their type
+type MockWebhookSender struct {
+	Statuses []int
+	Sent     []string
+	Bodies   []string
+}
+
+func (m *MockWebhookSender) Encode(event domain.DomainEvent) ([]byte, error) {
+	return []byte(event.EventType()), nil
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, endpoint ports.WebhookEndpoint, eventID, eventType string, body []byte) (ports.WebhookReply, error) {
+	m.Sent = append(m.Sent, endpoint.ID+" "+eventType)
+	m.Bodies = append(m.Bodies, string(body))
+	status := m.Statuses[0]
+	if len(m.Statuses) > 1 {
+		m.Statuses = m.Statuses[1:]
+	}
+	reply := ports.WebhookReply{StatusCode: status, Body: http.StatusText(status)}
+	if status < 200 || status > 299 {
+		return reply, errors.New("delivery failed")
+	}
+	return reply, nil
+}
+
+// newTestWebhookService creates a WebhookService whose retries run at once,
+// recording their delays
+func newTestWebhookService(store *MockWebhookStore, sender *MockWebhookSender) (*WebhookService, *[]time.Duration) {
+	service := NewWebhookService(store, sender, &MockEventSubscriber{}, nil,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultWebhookOptions())
+	var delays []time.Duration
+	service.after = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		f()
+	}
+	return service, &delays
+}
+
+// drain makes the queued deliveries, including the retries they schedule
+func drain(service *WebhookService) {
+	for {
+		select {
+		case delivery := <-service.queue:
+			service.deliver(context.Background(), delivery)
+		default:
+			return
+		}
+	}
+}
+
+func TestWebhookService_Lifecycle(t *testing.T) {
+	store := &MockWebhookStore{}
+	service, _ := newTestWebhookService(store, &MockWebhookSender{})
+	ctx := context.Background()
+
+	created, err := service.Register(ctx, WebhookRequest{
+		URL:        " https://example.com/hooks ",
+		EventTypes: []string{"TodoCreated", "TodoCreated", "TodoDeleted"},
+	})
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+	if created.Secret == "" || created.Webhook.URL != "https://example.com/hooks" || len(created.Webhook.EventTypes) != 2 {
+		t.Errorf("Register() = %+v, want the trimmed URL, 2 event types and a secret", created)
+	}
+
+	webhooks, err := service.List(ctx)
+	if err != nil || len(webhooks) != 1 || webhooks[0].ID != created.Webhook.ID {
+		t.Fatalf("List() = %+v, %v, want the registered webhook", webhooks, err)
+	}
+
+	if err := service.Delete(ctx, created.Webhook.ID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if err := service.Delete(ctx, created.Webhook.ID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("Delete() again error = %v, want %v", err, ErrWebhookNotFound)
+	}
+	if err := service.Delete(ctx, "not-a-uuid"); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("Delete(invalid) error = %v, want %v", err, ErrWebhookNotFound)
+	}
+}
+
+func TestWebhookService_Register_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  WebhookRequest
+	}{
+		{name: "relative URL", req: WebhookRequest{URL: "/hooks"}},
+		{name: "unsupported scheme", req: WebhookRequest{URL: "ftp://example.com/hooks"}},
+		{name: "empty event type", req: WebhookRequest{URL: "https://example.com", EventTypes: []string{""}}},
+		{name: "event type list", req: WebhookRequest{URL: "https://example.com", EventTypes: []string{"TodoCreated,TodoDeleted"}}},
+		{name: "negative digest window", req: WebhookRequest{URL: "https://example.com", DigestWindow: -time.Second}},
+		{name: "digest window too long", req: WebhookRequest{URL: "https://example.com", DigestWindow: MaxWebhookDigestWindow + time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestWebhookService(&MockWebhookStore{}, &MockWebhookSender{})
+
+			_, err := service.Register(context.Background(), tt.req)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Register() error = %v, want a ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Notify_FiltersEventTypes(t *testing.T) {
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{
+		{ID: "all"},
+		{ID: "deletions", EventTypes: []string{"TodoDeleted"}},
+	}}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+	service, _ := newTestWebhookService(store, sender)
+	id := domain.NewTodoID()
+
+	for _, event := range []domain.DomainEvent{domain.NewTodoUnarchivedEvent(id), domain.NewTodoDeletedEvent(id, "")} {
+		if err := service.Notify(context.Background(), event); err != nil {
+			t.Fatalf("Notify() unexpected error: %v", err)
+		}
+	}
+	drain(service)
+
+	want := []string{"all TodoUnarchived", "all TodoDeleted", "deletions TodoDeleted"}
+	if len(sender.Sent) != len(want) {
+		t.Fatalf("sent %v, want %v", sender.Sent, want)
+	}
+	for i := range want {
+		if sender.Sent[i] != want[i] {
+			t.Errorf("sent %v, want %v", sender.Sent, want)
+			break
+		}
+	}
+	if len(store.Deliveries) != 3 || store.Deliveries[0].StatusCode != http.StatusOK || store.Deliveries[0].Error != "" {
+		t.Errorf("deliveries = %+v, want 3 successful attempts", store.Deliveries)
+	}
+}
+
+func TestWebhookService_Deliver_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "succeeds after server errors",
+			statuses:     []int{0, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusNoContent},
+			wantAttempts: 4,
+			wantDelays:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:         "gives up after MaxAttempts",
+			statuses:     []int{http.StatusServiceUnavailable},
+			wantAttempts: 8,
+			wantDelays: []time.Duration{
+				time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+				16 * time.Second, 32 * time.Second, 64 * time.Second,
+			},
+		},
+		{
+			name:         "client error is not retried",
+			statuses:     []int{http.StatusGone},
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{{ID: "hook"}}}
+			service, delays := newTestWebhookService(store, &MockWebhookSender{Statuses: tt.statuses})
+
+			if err := service.Notify(context.Background(), domain.NewTodoDeletedEvent(domain.NewTodoID(), "")); err != nil {
+				t.Fatalf("Notify() unexpected error: %v", err)
+			}
+			drain(service)
+
+			if len(store.Deliveries) != tt.wantAttempts {
+				t.Fatalf("recorded %d attempts, want %d", len(store.Deliveries), tt.wantAttempts)
+			}
+			for i, delivery := range store.Deliveries {
+				if delivery.Attempt != i+1 || delivery.EventID != store.Deliveries[0].EventID {
+					t.Errorf("attempt %d = %+v, want attempt %d of the same event", i, delivery, i+1)
+				}
+			}
+			if len(*delays) != len(tt.wantDelays) {
+				t.Fatalf("delays = %v, want %v", *delays, tt.wantDelays)
+			}
+			for i, want := range tt.wantDelays {
+				if (*delays)[i] != want {
+					t.Errorf("delays = %v, want %v", *delays, tt.wantDelays)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookService_Notify_QueueFull(t *testing.T) {
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{{ID: "hook"}}}
+	options := DefaultWebhookOptions()
+	options.Queue = 1
+	service := NewWebhookService(store, &MockWebhookSender{}, &MockEventSubscriber{}, nil,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), options)
+	id := domain.NewTodoID()
+
+	for _, event := range []domain.DomainEvent{domain.NewTodoUnarchivedEvent(id), domain.NewTodoDeletedEvent(id, "")} {
+		if err := service.Notify(context.Background(), event); err != nil {
+			t.Fatalf("Notify() unexpected error: %v", err)
+		}
+	}
+
+	if len(store.Deliveries) != 1 || store.Deliveries[0].EventType != "TodoDeleted" || store.Deliveries[0].Error == "" {
+		t.Errorf("deliveries = %+v, want the dropped TodoDeleted recorded as failed", store.Deliveries)
+	}
+}
+
+// newDigestTestService creates a WebhookService whose delayed functions
+// wait for the returned function to run them, recording their delays
+func newDigestTestService(store *MockWebhookStore, sender *MockWebhookSender) (*WebhookService, *[]time.Duration, func()) {
+	service := NewWebhookService(store, sender, &MockEventSubscriber{}, nil,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultWebhookOptions())
+	var delays []time.Duration
+	var pending []func()
+	service.after = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		pending = append(pending, f)
+	}
+	elapse := func() {
+		timers := pending
+		pending = nil
+		for _, f := range timers {
+			f()
+		}
+	}
+	return service, &delays, elapse
+}
+
+func TestWebhookService_Notify_Digests(t *testing.T) {
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{
+		{ID: "each"},
+		{ID: "digest", DigestWindow: 30 * time.Second},
+	}}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+	service, delays, elapse := newDigestTestService(store, sender)
+	busy, quiet := domain.NewTodoID(), domain.NewTodoID()
+
+	for _, event := range []domain.DomainEvent{
+		domain.NewTodoArchivedEvent(busy, time.Now()),
+		domain.NewTodoDeletedEvent(quiet, ""),
+		domain.NewTodoUnarchivedEvent(busy),
+		domain.NewTodoDeletedEvent(busy, ""),
+	} {
+		if err := service.Notify(context.Background(), event); err != nil {
+			t.Fatalf("Notify() unexpected error: %v", err)
+		}
+	}
+	drain(service)
+
+	if len(sender.Sent) != 4 {
+		t.Fatalf("sent %v before the end of the window, want the 4 events to each", sender.Sent)
+	}
+	if len(*delays) != 2 || (*delays)[0] != 30*time.Second {
+		t.Errorf("delays = %v, want a window of 30s per todo", *delays)
+	}
+
+	elapse()
+	drain(service)
+
+	want := []string{"digest " + ports.EventDigestType, "digest TodoDeleted"}
+	if len(sender.Sent) != 6 || sender.Sent[4] != want[0] || sender.Sent[5] != want[1] {
+		t.Errorf("sent %v after the window, want %v", sender.Sent[4:], want)
+	}
+	last := store.Deliveries[len(store.Deliveries)-2]
+	if last.EndpointID != "digest" || last.EventType != ports.EventDigestType {
+		t.Errorf("delivery = %+v, want the digest recorded", last)
+	}
+
+	// The next event on the todo opens a new window
+	if err := service.Notify(context.Background(), domain.NewTodoUnarchivedEvent(busy)); err != nil {
+		t.Fatalf("Notify() unexpected error: %v", err)
+	}
+	if len(*delays) != 3 {
+		t.Errorf("delays = %v, want a new window", *delays)
+	}
+}
+
+func TestWebhookService_Notify_DigestFull(t *testing.T) {
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{{ID: "digest", DigestWindow: time.Minute}}}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+	service, _, elapse := newDigestTestService(store, sender)
+	id := domain.NewTodoID()
+
+	for range MaxDigestEvents {
+		if err := service.Notify(context.Background(), domain.NewTodoUnarchivedEvent(id)); err != nil {
+			t.Fatalf("Notify() unexpected error: %v", err)
+		}
+	}
+	drain(service)
+
+	if len(sender.Sent) != 1 || sender.Sent[0] != "digest "+ports.EventDigestType {
+		t.Fatalf("sent %v, want the full digest at once", sender.Sent)
+	}
+
+	// The window of the digest closed early ends without a delivery
+	elapse()
+	drain(service)
+	if len(sender.Sent) != 1 {
+		t.Errorf("sent %v, want nothing more at the end of the window", sender.Sent)
+	}
+}
+
+func TestWebhookService_Notify_DigestDroppedOnShutdown(t *testing.T) {
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{{ID: "digest", DigestWindow: time.Minute}}}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+	service, _, elapse := newDigestTestService(store, sender)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := service.Notify(ctx, domain.NewTodoDeletedEvent(domain.NewTodoID(), "")); err != nil {
+		t.Fatalf("Notify() unexpected error: %v", err)
+	}
+	cancel()
+	elapse()
+	drain(service)
+
+	if len(sender.Sent) != 0 {
+		t.Errorf("sent %v, want the open digest dropped", sender.Sent)
+	}
+}
+
+func TestWebhookService_ListDeliveries(t *testing.T) {
+	const hook = "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"
+	store := &MockWebhookStore{Endpoints: []ports.WebhookEndpoint{{ID: hook}}}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusBadGateway, http.StatusOK}}
+	service, _ := newTestWebhookService(store, sender)
+	ctx := context.Background()
+
+	// Attempts 1 and 3 fail, then 2 and 4 succeed after a retry each
+	for range 2 {
+		if err := service.Notify(ctx, domain.NewTodoDeletedEvent(domain.NewTodoID(), "")); err != nil {
+			t.Fatalf("Notify() unexpected error: %v", err)
+		}
+		drain(service)
+		sender.Statuses = []int{http.StatusBadGateway, http.StatusOK}
+	}
+
+	page, err := service.ListDeliveries(ctx, hook, WebhookDeliveriesRequest{Limit: 3})
+	if err != nil {
+		t.Fatalf("ListDeliveries() unexpected error: %v", err)
+	}
+	if len(page.Deliveries) != 3 || page.Deliveries[0].ID != 4 || page.NextCursor != "2" {
+		t.Fatalf("ListDeliveries() = %+v, want attempts 4 to 2 and a cursor", page)
+	}
+	if got := page.Deliveries[1]; got.StatusCode != http.StatusBadGateway || got.ResponseSnippet != "Bad Gateway" || got.Error == "" {
+		t.Errorf("delivery = %+v, want the failed attempt with its response", got)
+	}
+
+	page, err = service.ListDeliveries(ctx, hook, WebhookDeliveriesRequest{Cursor: page.NextCursor, Limit: 3})
+	if err != nil || len(page.Deliveries) != 1 || page.Deliveries[0].ID != 1 || page.NextCursor != "" {
+		t.Errorf("ListDeliveries() next page = %+v, %v, want the first attempt only", page, err)
+	}
+
+	page, err = service.ListDeliveries(ctx, hook, WebhookDeliveriesRequest{FailedOnly: true})
+	if err != nil || len(page.Deliveries) != 2 || page.Deliveries[0].ID != 3 || page.Deliveries[1].ID != 1 {
+		t.Errorf("ListDeliveries(failed) = %+v, %v, want attempts 3 and 1", page, err)
+	}
+}
+
+func TestWebhookService_ListDeliveries_Errors(t *testing.T) {
+	service, _ := newTestWebhookService(&MockWebhookStore{}, &MockWebhookSender{})
+
+	_, err := service.ListDeliveries(context.Background(), "not-a-uuid", WebhookDeliveriesRequest{})
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("ListDeliveries(bad id) error = %v, want ErrWebhookNotFound", err)
+	}
+
+	_, err = service.ListDeliveries(context.Background(), "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d", WebhookDeliveriesRequest{Cursor: "-1"})
+	var validationErr domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("ListDeliveries(bad cursor) error = %v, want a ValidationError", err)
+	}
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	const (
+		hook    = "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"
+		deleted = "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	)
+	failed := ports.WebhookDelivery{
+		ID: 1, EndpointID: hook, EventID: "event-1", EventType: "TodoDeleted", Attempt: 8,
+		StatusCode: http.StatusBadGateway, Error: "endpoint answered 502", Payload: []byte(`{"event_type":"TodoDeleted"}`),
+	}
+	newStore := func() *MockWebhookStore {
+		return &MockWebhookStore{
+			Endpoints: []ports.WebhookEndpoint{{ID: hook, URL: "https://example.com/hooks"}},
+			Deliveries: []ports.WebhookDelivery{
+				failed,
+				{ID: 2, EndpointID: hook, EventID: "event-0", EventType: "TodoCreated", Attempt: 1, Error: "connection refused"},
+				{ID: 3, EndpointID: deleted, EventID: "event-2", EventType: "TodoCreated", Attempt: 1, Payload: []byte(`{}`)},
+			},
+		}
+	}
+
+	t.Run("posts the payload again", func(t *testing.T) {
+		store := newStore()
+		sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+		service, delays := newTestWebhookService(store, sender)
+
+		got, err := service.Redeliver(context.Background(), hook, 1)
+		if err != nil {
+			t.Fatalf("Redeliver() unexpected error: %v", err)
+		}
+		if len(sender.Bodies) != 1 || sender.Bodies[0] != string(failed.Payload) || sender.Sent[0] != hook+" TodoDeleted" {
+			t.Errorf("sent %v %v, want the stored payload", sender.Sent, sender.Bodies)
+		}
+		if got.ID != 4 || got.EventID != "event-1" || got.Attempt != 1 || got.RedeliveryOf != 1 || got.StatusCode != http.StatusOK || got.Error != "" {
+			t.Errorf("Redeliver() = %+v, want a successful attempt redelivering 1", got)
+		}
+		if len(store.Deliveries) != 4 || string(store.Deliveries[3].Payload) != string(failed.Payload) {
+			t.Errorf("deliveries = %+v, want the attempt recorded with its payload", store.Deliveries)
+		}
+		if len(*delays) != 0 {
+			t.Errorf("delays = %v, want no retry", *delays)
+		}
+	})
+
+	t.Run("failure is the outcome", func(t *testing.T) {
+		store := newStore()
+		service, delays := newTestWebhookService(store, &MockWebhookSender{Statuses: []int{http.StatusServiceUnavailable}})
+
+		got, err := service.Redeliver(context.Background(), hook, 1)
+		if err != nil || got.StatusCode != http.StatusServiceUnavailable || got.Error == "" {
+			t.Errorf("Redeliver() = %+v, %v, want the failed attempt", got, err)
+		}
+		if len(*delays) != 0 {
+			t.Errorf("delays = %v, want no retry", *delays)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		endpointID string
+		deliveryID int64
+		want       error
+	}{
+		{"invalid endpoint ID", "hook", 1, ErrWebhookNotFound},
+		{"unknown delivery", hook, 42, ErrWebhookDeliveryNotFound},
+		{"delivery to another endpoint", deleted, 1, ErrWebhookDeliveryNotFound},
+		{"no payload", hook, 2, ErrWebhookNotRedeliverable},
+		{"deleted endpoint", deleted, 3, ErrWebhookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &MockWebhookSender{Statuses: []int{http.StatusOK}}
+			service, _ := newTestWebhookService(newStore(), sender)
+
+			_, err := service.Redeliver(context.Background(), tt.endpointID, tt.deliveryID)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Redeliver() error = %v, want %v", err, tt.want)
+			}
+			if len(sender.Sent) != 0 {
+				t.Errorf("sent %v, want nothing", sender.Sent)
+			}
+		})
+	}
+}
+
+// MockTodoAuditScanner lists Entries, numbered from 1 when Seq is unset
+type MockTodoAuditScanner struct {
+	Entries []ports.TodoAuditEntry
+}
+
+func (m *MockTodoAuditScanner) ListBetween(ctx context.Context, from, to time.Time, after int64, limit int) ([]ports.TodoAuditEntry, error) {
+	var entries []ports.TodoAuditEntry
+	for i, entry := range m.Entries {
+		if entry.Seq == 0 {
+			entry.Seq = int64(i + 1)
+		}
+		if entry.Seq <= after || entry.OccurredAt.Before(from) || !entry.OccurredAt.Before(to) {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) == limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// newBackfillTestService creates a WebhookService backfilling history,
+// whose waits return at once and are recorded
+func newBackfillTestService(store *MockWebhookStore, sender *MockWebhookSender, history ports.TodoAuditScanner) (*WebhookService, *[]time.Duration) {
+	service := NewWebhookService(store, sender, &MockEventSubscriber{}, history,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultWebhookOptions())
+	var waits []time.Duration
+	service.wait = func(ctx context.Context, d time.Duration) bool {
+		waits = append(waits, d)
+		return ctx.Err() == nil
+	}
+	return service, &waits
+}
+
+func TestWebhookService_Register_Backfill(t *testing.T) {
+	start := time.Now().Add(-24 * time.Hour)
+	history := &MockTodoAuditScanner{Entries: []ports.TodoAuditEntry{
+		{TodoID: "a", EventType: "TodoCreated", OccurredAt: start.Add(-time.Minute)},
+		{TodoID: "b", EventType: "TodoCreated", Changes: map[string]string{"title": "Pay rent"}, OccurredAt: start},
+		{TodoID: "b", EventType: "TodoUpdated", OccurredAt: start.Add(time.Minute)},
+		{TodoID: "b", EventType: "TodoCompleted", OccurredAt: start.Add(2 * time.Minute)},
+	}}
+	store := &MockWebhookStore{}
+	sender := &MockWebhookSender{Statuses: []int{http.StatusBadGateway, http.StatusOK}}
+	service, waits := newBackfillTestService(store, sender, history)
+	ctx := context.Background()
+
+	created, err := service.Register(ctx, WebhookRequest{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{"TodoCreated", "TodoCompleted"},
+		Backfill:   &WebhookBackfillRequest{From: start, Rate: 20},
+	})
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+	if !created.Backfilling || len(service.backfills) != 1 {
+		t.Fatalf("Register() = %+v, want a backfill queued", created)
+	}
+	service.backfill(ctx, <-service.backfills)
+
+	id := created.Webhook.ID
+	want := []string{id + " TodoCreated", id + " TodoCreated", id + " TodoCompleted"}
+	if strings.Join(sender.Sent, ",") != strings.Join(want, ",") {
+		t.Errorf("sent %v, want %v", sender.Sent, want)
+	}
+	wantWaits := []time.Duration{50 * time.Millisecond, time.Second, 50 * time.Millisecond}
+	if len(*waits) != len(wantWaits) || (*waits)[0] != wantWaits[0] || (*waits)[1] != wantWaits[1] || (*waits)[2] != wantWaits[2] {
+		t.Errorf("waits = %v, want %v: the rate, then a backoff", *waits, wantWaits)
+	}
+	if len(store.Deliveries) != 3 || store.Deliveries[1].Attempt != 2 || store.Deliveries[1].EventID != store.Deliveries[0].EventID {
+		t.Errorf("deliveries = %+v, want the retry of the first event recorded", store.Deliveries)
+	}
+}
+
+func TestWebhookService_Backfill_Stops(t *testing.T) {
+	const hook = "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"
+	start := time.Now().Add(-time.Hour)
+	history := &MockTodoAuditScanner{Entries: []ports.TodoAuditEntry{
+		{TodoID: "a", EventType: "TodoCreated", OccurredAt: start},
+		{TodoID: "b", EventType: "TodoCreated", OccurredAt: start},
+	}}
+	backfill := webhookBackfill{endpoint: ports.WebhookEndpoint{ID: hook}, from: start, to: time.Now(), rate: 10}
+
+	tests := []struct {
+		name      string
+		endpoints []ports.WebhookEndpoint
+		statuses  []int
+		wantSent  int
+	}{
+		{"at the first event given up on", []ports.WebhookEndpoint{{ID: hook}}, []int{http.StatusBadRequest}, 1},
+		{"when the endpoint is deleted", nil, []int{http.StatusOK}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &MockWebhookSender{Statuses: tt.statuses}
+			service, _ := newBackfillTestService(&MockWebhookStore{Endpoints: tt.endpoints}, sender, history)
+
+			service.backfill(context.Background(), backfill)
+
+			if len(sender.Sent) != tt.wantSent {
+				t.Errorf("sent %v, want %d events", sender.Sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Register_BackfillErrors(t *testing.T) {
+	now := time.Now()
+	var validationErr domain.ValidationError
+
+	tests := []struct {
+		name    string
+		history ports.TodoAuditScanner
+		pending int
+		req     WebhookBackfillRequest
+		wantErr func(error) bool
+	}{
+		{"no history", nil, 0, WebhookBackfillRequest{From: now.Add(-time.Hour)}, func(err error) bool { return errors.Is(err, ErrNotSupported) }},
+		{"no start", &MockTodoAuditScanner{}, 0, WebhookBackfillRequest{}, func(err error) bool { return errors.As(err, &validationErr) }},
+		{"start after end", &MockTodoAuditScanner{}, 0, WebhookBackfillRequest{From: now.Add(-time.Hour), To: now.Add(-2 * time.Hour)}, func(err error) bool { return errors.As(err, &validationErr) }},
+		{"rate too high", &MockTodoAuditScanner{}, 0, WebhookBackfillRequest{From: now.Add(-time.Hour), Rate: MaxWebhookBackfillRate + 1}, func(err error) bool { return errors.As(err, &validationErr) }},
+		{"queue full", &MockTodoAuditScanner{}, MaxPendingWebhookBackfills, WebhookBackfillRequest{From: now.Add(-time.Hour)}, func(err error) bool { return errors.Is(err, ErrWebhookBackfillsBusy) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MockWebhookStore{}
+			service, _ := newBackfillTestService(store, &MockWebhookSender{}, tt.history)
+			for range tt.pending {
+				service.backfills <- webhookBackfill{}
+			}
+
+			req := tt.req
+			_, err := service.Register(context.Background(), WebhookRequest{URL: "https://example.com/hooks", Backfill: &req})
+			if !tt.wantErr(err) {
+				t.Errorf("Register() error = %v", err)
+			}
+			if len(store.Endpoints) != 0 {
+				t.Errorf("endpoints = %+v, want none registered", store.Endpoints)
+			}
+		})
+	}
+}
@@ -7,17 +7,23 @@ import "errors"
 
 var (
 	// Validation errors
-	ErrInvalidTitle    = errors.New("title must be between 1 and 200 characters")
-	ErrInvalidDueDate  = errors.New("due date must be in the future")
-	ErrInvalidPriority = errors.New("invalid priority value")
-	ErrInvalidStatus   = errors.New("invalid status value")
-	ErrInvalidID       = errors.New("invalid todo ID")
+	ErrInvalidTitle     = errors.New("title must be between 1 and 200 characters")
+	ErrInvalidDueDate   = errors.New("due date must be in the future")
+	ErrInvalidPriority  = errors.New("invalid priority value")
+	ErrInvalidStatus    = errors.New("invalid status value")
+	ErrInvalidID        = errors.New("invalid todo ID")
+	ErrInvalidShortCode = errors.New("invalid todo short code")
+	ErrMissingReason    = errors.New("a reason is required to override business rules")
 
 	// Business rule errors
 	ErrCannotCompleteCancelled = errors.New("cannot complete a cancelled task")
 	ErrCannotModifyCompleted   = errors.New("cannot modify a completed task")
 	ErrTodoNotFound            = errors.New("todo not found")
 	ErrTodoAlreadyExists       = errors.New("todo already exists")
+	ErrCannotMergeIntoSelf     = errors.New("cannot merge a todo into itself")
+	ErrAlreadyMerged           = errors.New("todo has already been merged into another")
+	ErrAlreadyOwned            = errors.New("todo already belongs to this owner")
+	ErrConcurrentModification  = errors.New("todo was modified concurrently")
 
 	// State transition errors
 	ErrInvalidStatusTransition = errors.New("invalid status transition")
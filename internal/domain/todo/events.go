@@ -63,13 +63,16 @@ func NewTodoCreatedEvent(id TodoID, title TaskTitle, description string, priorit
 }
 
 // TodoUpdated event is emitted when a todo is modified
+// It carries the new values of the fields changed; DueDateCleared is set
+// when the due date was removed
 type TodoUpdated struct {
 	BaseDomainEvent
-	Title       *string
-	Description *string
-	Priority    *string
-	DueDate     *time.Time
-	Status      *string
+	Title          *string
+	Description    *string
+	Priority       *string
+	DueDate        *time.Time
+	DueDateCleared bool
+	Status         *string
 }
 
 // EventType returns the event type
@@ -87,6 +90,14 @@ func NewTodoUpdatedEvent(id TodoID) TodoUpdated {
 	}
 }
 
+// newTodoStatusUpdatedEvent creates a TodoUpdated event for a status change
+func newTodoStatusUpdatedEvent(id TodoID, status TaskStatus) TodoUpdated {
+	event := NewTodoUpdatedEvent(id)
+	value := status.String()
+	event.Status = &value
+	return event
+}
+
 // TodoCompleted event is emitted when a todo is marked as completed
 type TodoCompleted struct {
 	BaseDomainEvent
@@ -134,6 +145,14 @@ func NewTodoReopenedEvent(id TodoID, previousStatus TaskStatus) TodoReopened {
 // TodoDeleted event is emitted when a todo is deleted
 type TodoDeleted struct {
 	BaseDomainEvent
+	// ownerID is kept in the process, for watchers to only see their own
+	// deletions, and is not published
+	ownerID string
+}
+
+// OwnerID returns the user the deleted todo belonged to (empty if unowned)
+func (e TodoDeleted) OwnerID() string {
+	return e.ownerID
 }
 
 // EventType returns the event type
@@ -141,12 +160,176 @@ func (e TodoDeleted) EventType() string {
 	return "TodoDeleted"
 }
 
-// NewTodoDeletedEvent creates a new TodoDeleted event
-func NewTodoDeletedEvent(id TodoID) TodoDeleted {
+// NewTodoDeletedEvent creates a new TodoDeleted event for the todo id of
+// ownerID
+func NewTodoDeletedEvent(id TodoID, ownerID string) TodoDeleted {
 	return TodoDeleted{
 		BaseDomainEvent: BaseDomainEvent{
 			aggregateID: id.String(),
 			occurredAt:  time.Now(),
 		},
+		ownerID: ownerID,
+	}
+}
+
+// TodoForceUpdated event is emitted when an administrator overrides a todo,
+// bypassing the usual business rules
+type TodoForceUpdated struct {
+	BaseDomainEvent
+	Actor  string
+	Reason string
+	Fields []string
+}
+
+// EventType returns the event type
+func (e TodoForceUpdated) EventType() string {
+	return "TodoForceUpdated"
+}
+
+// NewTodoForceUpdatedEvent creates a new TodoForceUpdated event
+func NewTodoForceUpdatedEvent(id TodoID, actor, reason string, fields []string) TodoForceUpdated {
+	return TodoForceUpdated{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		Actor:  actor,
+		Reason: reason,
+		Fields: fields,
+	}
+}
+
+// TodoMerged event is emitted when a duplicate todo is merged into a
+// canonical one and cancelled
+type TodoMerged struct {
+	BaseDomainEvent
+	CanonicalID string
+}
+
+// EventType returns the event type
+func (e TodoMerged) EventType() string {
+	return "TodoMerged"
+}
+
+// NewTodoMergedEvent creates a new TodoMerged event for the duplicate id
+func NewTodoMergedEvent(id, canonicalID TodoID) TodoMerged {
+	return TodoMerged{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		CanonicalID: canonicalID.String(),
+	}
+}
+
+// TodoMoved event is emitted when a todo is moved to another owner
+type TodoMoved struct {
+	BaseDomainEvent
+	// FromOwner is empty when the todo was unowned
+	FromOwner string
+	ToOwner   string
+}
+
+// EventType returns the event type
+func (e TodoMoved) EventType() string {
+	return "TodoMoved"
+}
+
+// NewTodoMovedEvent creates a new TodoMoved event
+func NewTodoMovedEvent(id TodoID, fromOwner, toOwner string) TodoMoved {
+	return TodoMoved{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		FromOwner: fromOwner,
+		ToOwner:   toOwner,
+	}
+}
+
+// TodoArchived event is emitted when a todo is archived
+type TodoArchived struct {
+	BaseDomainEvent
+	ArchivedAt time.Time
+}
+
+// EventType returns the event type
+func (e TodoArchived) EventType() string {
+	return "TodoArchived"
+}
+
+// NewTodoArchivedEvent creates a new TodoArchived event
+func NewTodoArchivedEvent(id TodoID, archivedAt time.Time) TodoArchived {
+	return TodoArchived{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		ArchivedAt: archivedAt,
+	}
+}
+
+// TodoUnarchived event is emitted when an archived todo is brought back
+type TodoUnarchived struct {
+	BaseDomainEvent
+}
+
+// EventType returns the event type
+func (e TodoUnarchived) EventType() string {
+	return "TodoUnarchived"
+}
+
+// NewTodoUnarchivedEvent creates a new TodoUnarchived event
+func NewTodoUnarchivedEvent(id TodoID) TodoUnarchived {
+	return TodoUnarchived{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+	}
+}
+
+// TodoDueSoon event is emitted when an open todo's due date comes within
+// the reminder lead time
+type TodoDueSoon struct {
+	BaseDomainEvent
+	DueDate time.Time
+}
+
+// EventType returns the event type
+func (e TodoDueSoon) EventType() string {
+	return "TodoDueSoon"
+}
+
+// NewTodoDueSoonEvent creates a new TodoDueSoon event
+func NewTodoDueSoonEvent(id TodoID, dueDate DueDate) TodoDueSoon {
+	return TodoDueSoon{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		DueDate: dueDate.Time(),
+	}
+}
+
+// TodoOverdue event is emitted when an open todo's due date passes
+type TodoOverdue struct {
+	BaseDomainEvent
+	DueDate time.Time
+}
+
+// EventType returns the event type
+func (e TodoOverdue) EventType() string {
+	return "TodoOverdue"
+}
+
+// NewTodoOverdueEvent creates a new TodoOverdue event
+func NewTodoOverdueEvent(id TodoID, dueDate DueDate) TodoOverdue {
+	return TodoOverdue{
+		BaseDomainEvent: BaseDomainEvent{
+			aggregateID: id.String(),
+			occurredAt:  time.Now(),
+		},
+		DueDate: dueDate.Time(),
 	}
 }
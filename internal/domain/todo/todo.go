@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Todo is the aggregate root for the todo domain
 // It enforces all business rules and maintains consistency
@@ -14,6 +17,12 @@ type Todo struct {
 	createdAt   time.Time
 	updatedAt   time.Time
 	completedAt *time.Time
+	shortCode   ShortCode
+	mergedInto  *TodoID
+	canary      bool
+	archivedAt  *time.Time
+	ownerID     string
+	version     int64
 	events      []DomainEvent
 }
 
@@ -31,6 +40,7 @@ func NewTodo(title TaskTitle, description string, priority Priority, dueDate *Du
 		dueDate:     dueDate,
 		createdAt:   now,
 		updatedAt:   now,
+		version:     1,
 		events:      []DomainEvent{},
 	}
 
@@ -40,6 +50,15 @@ func NewTodo(title TaskTitle, description string, priority Priority, dueDate *Du
 	return todo
 }
 
+// NewCanaryTodo creates a decoy todo whose access reveals an intruder
+// Canaries are never announced: no TodoCreated event is emitted
+func NewCanaryTodo(title TaskTitle, description string) *Todo {
+	todo := NewTodo(title, description, PriorityMedium, nil)
+	todo.canary = true
+	todo.ClearEvents()
+	return todo
+}
+
 // ReconstituteTodo reconstitutes a Todo from stored data (used by repository)
 func ReconstituteTodo(
 	id TodoID,
@@ -112,6 +131,76 @@ func (t *Todo) CompletedAt() *time.Time {
 	return t.completedAt
 }
 
+// ShortCode returns the human-friendly identifier (zero if not yet assigned)
+func (t *Todo) ShortCode() ShortCode {
+	return t.shortCode
+}
+
+// AssignShortCode records the short code allocated by the repository
+func (t *Todo) AssignShortCode(code ShortCode) {
+	t.shortCode = code
+}
+
+// OwnerID returns the user the todo belongs to (empty if unowned)
+func (t *Todo) OwnerID() string {
+	return t.ownerID
+}
+
+// AssignOwner records the user the todo belongs to, on creation or
+// reconstitution
+func (t *Todo) AssignOwner(ownerID string) {
+	t.ownerID = ownerID
+}
+
+// IsCanary reports whether the todo is a decoy created by NewCanaryTodo
+func (t *Todo) IsCanary() bool {
+	return t.canary
+}
+
+// RestoreCanary marks, on reconstitution, a todo created as a canary
+func (t *Todo) RestoreCanary() {
+	t.canary = true
+}
+
+// MergedInto returns the canonical todo this duplicate was merged into (nil if not merged)
+func (t *Todo) MergedInto() *TodoID {
+	return t.mergedInto
+}
+
+// RestoreMergedInto records, on reconstitution, the todo this one was merged into
+func (t *Todo) RestoreMergedInto(canonicalID TodoID) {
+	t.mergedInto = &canonicalID
+}
+
+// IsArchived reports whether the todo was archived, taking it out of default
+// listings
+func (t *Todo) IsArchived() bool {
+	return t.archivedAt != nil
+}
+
+// ArchivedAt returns when the todo was archived (nil if not archived)
+func (t *Todo) ArchivedAt() *time.Time {
+	return t.archivedAt
+}
+
+// RestoreArchived records, on reconstitution, when the todo was archived
+func (t *Todo) RestoreArchived(at time.Time) {
+	t.archivedAt = &at
+}
+
+// Version returns the number of saves of the todo, from 1 on creation (zero
+// if the repository does not keep versions)
+// Repositories refuse to save a todo whose stored version moved on since it
+// was read, with ErrConcurrentModification
+func (t *Todo) Version() int64 {
+	return t.version
+}
+
+// AssignVersion records the version read or saved by the repository
+func (t *Todo) AssignVersion(version int64) {
+	t.version = version
+}
+
 // Events returns the unpublished domain events
 func (t *Todo) Events() []DomainEvent {
 	return t.events
@@ -132,7 +221,10 @@ func (t *Todo) UpdateTitle(newTitle TaskTitle) error {
 
 	t.title = newTitle
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	event := NewTodoUpdatedEvent(t.id)
+	title := newTitle.String()
+	event.Title = &title
+	t.addEvent(event)
 
 	return nil
 }
@@ -145,7 +237,9 @@ func (t *Todo) UpdateDescription(newDescription string) error {
 
 	t.description = newDescription
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	event := NewTodoUpdatedEvent(t.id)
+	event.Description = &newDescription
+	t.addEvent(event)
 
 	return nil
 }
@@ -158,7 +252,10 @@ func (t *Todo) UpdatePriority(newPriority Priority) error {
 
 	t.priority = newPriority
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	event := NewTodoUpdatedEvent(t.id)
+	priority := newPriority.String()
+	event.Priority = &priority
+	t.addEvent(event)
 
 	return nil
 }
@@ -171,7 +268,14 @@ func (t *Todo) UpdateDueDate(newDueDate *DueDate) error {
 
 	t.dueDate = newDueDate
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	event := NewTodoUpdatedEvent(t.id)
+	if newDueDate != nil {
+		dueDate := newDueDate.Time()
+		event.DueDate = &dueDate
+	} else {
+		event.DueDateCleared = true
+	}
+	t.addEvent(event)
 
 	return nil
 }
@@ -187,7 +291,7 @@ func (t *Todo) UpdateStatus(newStatus TaskStatus) error {
 
 	t.status = newStatus
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	t.addEvent(newTodoStatusUpdatedEvent(t.id, newStatus))
 
 	return nil
 }
@@ -240,7 +344,7 @@ func (t *Todo) Cancel() error {
 
 	t.status = StatusCancelled
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	t.addEvent(newTodoStatusUpdatedEvent(t.id, StatusCancelled))
 
 	return nil
 }
@@ -257,11 +361,155 @@ func (t *Todo) MarkInProgress() error {
 
 	t.status = StatusInProgress
 	t.updatedAt = time.Now()
-	t.addEvent(NewTodoUpdatedEvent(t.id))
+	t.addEvent(newTodoStatusUpdatedEvent(t.id, StatusInProgress))
+
+	return nil
+}
+
+// ForcedChanges lists the fields overridden by ForceUpdate
+// Nil fields are left unchanged
+type ForcedChanges struct {
+	Title        *TaskTitle
+	Description  *string
+	Priority     *Priority
+	DueDate      *DueDate
+	ClearDueDate bool
+	Status       *TaskStatus
+}
+
+// ForceUpdate applies an administrative correction, bypassing the
+// completed-task and status transition rules
+// A reason is mandatory; it is carried by the emitted TodoForceUpdated event
+func (t *Todo) ForceUpdate(changes ForcedChanges, actor, reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return ErrMissingReason
+	}
+
+	var fields []string
+
+	if changes.Title != nil {
+		t.title = *changes.Title
+		fields = append(fields, "title")
+	}
+
+	if changes.Description != nil {
+		t.description = *changes.Description
+		fields = append(fields, "description")
+	}
+
+	if changes.Priority != nil {
+		t.priority = *changes.Priority
+		fields = append(fields, "priority")
+	}
+
+	if changes.DueDate != nil || changes.ClearDueDate {
+		t.dueDate = changes.DueDate
+		fields = append(fields, "due_date")
+	}
+
+	now := time.Now()
+
+	if changes.Status != nil && *changes.Status != t.status {
+		t.status = *changes.Status
+		if t.status.IsCompleted() {
+			t.completedAt = &now
+		} else {
+			t.completedAt = nil
+		}
+		fields = append(fields, "status")
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	t.updatedAt = now
+	t.addEvent(NewTodoForceUpdatedEvent(t.id, actor, reason, fields))
+
+	return nil
+}
+
+// MergeInto merges this duplicate into canonical: its description is appended
+// to the canonical one, and it is cancelled with a reference to canonical
+// Neither todo may be completed or already merged
+func (t *Todo) MergeInto(canonical *Todo) error {
+	if t.id == canonical.id {
+		return ErrCannotMergeIntoSelf
+	}
+
+	if t.mergedInto != nil || canonical.mergedInto != nil {
+		return ErrAlreadyMerged
+	}
+
+	if t.status.IsCompleted() || canonical.status.IsCompleted() {
+		return ErrCannotModifyCompleted
+	}
+
+	now := time.Now()
+
+	if description := strings.TrimSpace(t.description); description != "" {
+		merged := "Merged from \"" + t.title.String() + "\":\n" + description
+		if strings.TrimSpace(canonical.description) != "" {
+			merged = canonical.description + "\n\n" + merged
+		}
+		canonical.description = merged
+		canonical.updatedAt = now
+		event := NewTodoUpdatedEvent(canonical.id)
+		event.Description = &merged
+		canonical.addEvent(event)
+	}
+
+	canonicalID := canonical.id
+	t.status = StatusCancelled
+	t.mergedInto = &canonicalID
+	t.updatedAt = now
+	t.addEvent(NewTodoMergedEvent(t.id, canonicalID))
+
+	return nil
+}
+
+// MoveTo transfers the todo to the user ownerID, whatever its status
+// The todo keeps its ID, so everything recorded about it follows it
+func (t *Todo) MoveTo(ownerID string) error {
+	if ownerID == "" {
+		return NewValidationError("owner_id", "is required")
+	}
+	if ownerID == t.ownerID {
+		return ErrAlreadyOwned
+	}
+
+	from := t.ownerID
+	t.ownerID = ownerID
+	t.updatedAt = time.Now()
+	t.addEvent(NewTodoMovedEvent(t.id, from, ownerID))
 
 	return nil
 }
 
+// Archive takes the todo out of default listings, whatever its status
+// Archived todos are kept, unlike deleted ones, and can still be changed
+func (t *Todo) Archive() {
+	if t.archivedAt != nil {
+		return // Already archived, idempotent
+	}
+
+	now := time.Now()
+	t.archivedAt = &now
+	t.updatedAt = now
+	t.addEvent(NewTodoArchivedEvent(t.id, now))
+}
+
+// Unarchive brings an archived todo back into default listings
+func (t *Todo) Unarchive() {
+	if t.archivedAt == nil {
+		return // Not archived, idempotent
+	}
+
+	t.archivedAt = nil
+	t.updatedAt = time.Now()
+	t.addEvent(NewTodoUnarchivedEvent(t.id))
+}
+
 // IsDue checks if the todo has a due date and it has passed
 func (t *Todo) IsDue() bool {
 	if t.dueDate == nil {
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -58,6 +59,9 @@ func TestNewTodo(t *testing.T) {
 	if todo.CompletedAt() != nil {
 		t.Error("CompletedAt should be nil for new todo")
 	}
+	if todo.Version() != 1 {
+		t.Errorf("Version = %d, want 1", todo.Version())
+	}
 
 	// Verify TodoCreated event was emitted
 	events := todo.Events()
@@ -70,6 +74,21 @@ func TestNewTodo(t *testing.T) {
 }
 
 // TestTodo_Complete tests completing a todo
+func TestNewCanaryTodo(t *testing.T) {
+	title, _ := NewTaskTitle("Payroll credentials")
+	todo := NewCanaryTodo(title, "Decoy")
+
+	if !todo.IsCanary() {
+		t.Error("Expected a canary todo")
+	}
+	if len(todo.Events()) != 0 {
+		t.Errorf("Expected no events for a canary, got %d", len(todo.Events()))
+	}
+	if createValidTodo(t).IsCanary() {
+		t.Error("Expected NewTodo not to create a canary")
+	}
+}
+
 func TestTodo_Complete(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -541,7 +560,217 @@ func TestTodo_ClearEvents(t *testing.T) {
 	}
 }
 
+func TestTodo_ForceUpdate_BypassesCompletedRule(t *testing.T) {
+	todo := createTodoWithStatus(t, StatusCompleted)
+
+	title, _ := NewTaskTitle("Corrected title")
+	status := StatusInProgress
+
+	err := todo.ForceUpdate(ForcedChanges{Title: &title, Status: &status}, "ops@example.com", "Typo in ticket")
+
+	if err != nil {
+		t.Fatalf("ForceUpdate() unexpected error: %v", err)
+	}
+
+	if todo.Title() != title {
+		t.Errorf("Title = %v, want %v", todo.Title(), title)
+	}
+
+	if todo.Status() != StatusInProgress {
+		t.Errorf("Status = %v, want %v", todo.Status(), StatusInProgress)
+	}
+
+	if todo.CompletedAt() != nil {
+		t.Error("CompletedAt should be nil after forcing an open status")
+	}
+
+	if len(todo.Events()) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(todo.Events()))
+	}
+
+	event, ok := todo.Events()[0].(TodoForceUpdated)
+	if !ok {
+		t.Fatalf("Expected TodoForceUpdated event, got %T", todo.Events()[0])
+	}
+
+	if event.Reason != "Typo in ticket" || event.Actor != "ops@example.com" {
+		t.Errorf("Event = %+v, want reason and actor recorded", event)
+	}
+
+	if len(event.Fields) != 2 {
+		t.Errorf("Event fields = %v, want [title status]", event.Fields)
+	}
+}
+
+func TestTodo_ForceUpdate_RequiresReason(t *testing.T) {
+	todo := createTodoWithStatus(t, StatusCompleted)
+	title, _ := NewTaskTitle("Corrected title")
+
+	err := todo.ForceUpdate(ForcedChanges{Title: &title}, "ops@example.com", "  ")
+
+	if err != ErrMissingReason {
+		t.Errorf("ForceUpdate() error = %v, want %v", err, ErrMissingReason)
+	}
+
+	if todo.Title() == title {
+		t.Error("Title should not change without a reason")
+	}
+}
+
+func TestTodo_ForceUpdate_NoChanges_NoEvent(t *testing.T) {
+	todo := createTodoWithStatus(t, StatusPending)
+
+	if err := todo.ForceUpdate(ForcedChanges{}, "ops@example.com", "Nothing to do"); err != nil {
+		t.Fatalf("ForceUpdate() unexpected error: %v", err)
+	}
+
+	if len(todo.Events()) != 0 {
+		t.Errorf("Expected no events, got %d", len(todo.Events()))
+	}
+}
+
 // TestReconstituteTodo tests reconstituting a todo from stored data
+func TestTodo_MergeInto(t *testing.T) {
+	canonical := createTodoWithStatus(t, StatusPending)
+	duplicate := createTodoWithStatus(t, StatusInProgress)
+	duplicate.description = "Also buy eggs"
+
+	if err := duplicate.MergeInto(canonical); err != nil {
+		t.Fatalf("MergeInto() unexpected error: %v", err)
+	}
+
+	if duplicate.Status() != StatusCancelled {
+		t.Errorf("Duplicate status = %v, want %v", duplicate.Status(), StatusCancelled)
+	}
+
+	if duplicate.MergedInto() == nil || *duplicate.MergedInto() != canonical.ID() {
+		t.Errorf("MergedInto() = %v, want %v", duplicate.MergedInto(), canonical.ID())
+	}
+
+	want := "Test description\n\nMerged from \"Test Todo\":\nAlso buy eggs"
+	if canonical.Description() != want {
+		t.Errorf("Canonical description = %q, want %q", canonical.Description(), want)
+	}
+
+	if len(duplicate.Events()) != 1 {
+		t.Fatalf("Expected 1 duplicate event, got %d", len(duplicate.Events()))
+	}
+	if event, ok := duplicate.Events()[0].(TodoMerged); !ok || event.CanonicalID != canonical.ID().String() {
+		t.Errorf("Duplicate event = %+v, want TodoMerged into the canonical todo", duplicate.Events()[0])
+	}
+
+	if len(canonical.Events()) != 1 {
+		t.Errorf("Expected 1 canonical event, got %d", len(canonical.Events()))
+	}
+}
+
+func TestTodo_MergeInto_EmptyDescriptionLeavesCanonicalUnchanged(t *testing.T) {
+	canonical := createTodoWithStatus(t, StatusPending)
+	duplicate := createTodoWithStatus(t, StatusPending)
+	duplicate.description = "  "
+
+	if err := duplicate.MergeInto(canonical); err != nil {
+		t.Fatalf("MergeInto() unexpected error: %v", err)
+	}
+
+	if canonical.Description() != "Test description" || len(canonical.Events()) != 0 {
+		t.Errorf("Canonical = %q with %d events, want it unchanged", canonical.Description(), len(canonical.Events()))
+	}
+}
+
+func TestTodo_MergeInto_Errors(t *testing.T) {
+	merged := createTodoWithStatus(t, StatusPending)
+	merged.RestoreMergedInto(NewTodoID())
+	self := createTodoWithStatus(t, StatusPending)
+
+	tests := []struct {
+		name      string
+		duplicate *Todo
+		canonical *Todo
+		want      error
+	}{
+		{"into itself", self, self, ErrCannotMergeIntoSelf},
+		{"already merged duplicate", merged, createTodoWithStatus(t, StatusPending), ErrAlreadyMerged},
+		{"into a merged todo", createTodoWithStatus(t, StatusPending), merged, ErrAlreadyMerged},
+		{"completed duplicate", createTodoWithStatus(t, StatusCompleted), createTodoWithStatus(t, StatusPending), ErrCannotModifyCompleted},
+		{"completed canonical", createTodoWithStatus(t, StatusPending), createTodoWithStatus(t, StatusCompleted), ErrCannotModifyCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.duplicate.MergeInto(tt.canonical); err != tt.want {
+				t.Errorf("MergeInto() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodo_Archive(t *testing.T) {
+	todo := createTodoWithStatus(t, StatusCompleted)
+	todo.ClearEvents()
+
+	todo.Archive()
+	if !todo.IsArchived() || todo.ArchivedAt() == nil {
+		t.Fatal("Archive() left the todo unarchived")
+	}
+	if todo.Status() != StatusCompleted {
+		t.Errorf("Status = %v, want it unchanged", todo.Status())
+	}
+	archivedAt := *todo.ArchivedAt()
+
+	// Archiving twice keeps the first archival, with no event
+	todo.Archive()
+	if !todo.ArchivedAt().Equal(archivedAt) || len(todo.Events()) != 1 {
+		t.Errorf("second Archive() = %v with %d events, want %v and 1 event", todo.ArchivedAt(), len(todo.Events()), archivedAt)
+	}
+	if _, ok := todo.Events()[0].(TodoArchived); !ok {
+		t.Errorf("event = %T, want TodoArchived", todo.Events()[0])
+	}
+
+	todo.Unarchive()
+	todo.Unarchive()
+	if todo.IsArchived() || todo.ArchivedAt() != nil {
+		t.Error("Unarchive() left the todo archived")
+	}
+	if len(todo.Events()) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(todo.Events()))
+	}
+	if _, ok := todo.Events()[1].(TodoUnarchived); !ok {
+		t.Errorf("event = %T, want TodoUnarchived", todo.Events()[1])
+	}
+}
+
+func TestTodo_MoveTo(t *testing.T) {
+	todo := createTodoWithStatus(t, StatusCompleted)
+	todo.AssignOwner("alice")
+	todo.ClearEvents()
+
+	if err := todo.MoveTo("bob"); err != nil {
+		t.Fatalf("MoveTo() unexpected error: %v", err)
+	}
+	if todo.OwnerID() != "bob" || todo.Status() != StatusCompleted {
+		t.Errorf("owner = %q, status = %v, want bob and completed", todo.OwnerID(), todo.Status())
+	}
+	if len(todo.Events()) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(todo.Events()))
+	}
+	moved, ok := todo.Events()[0].(TodoMoved)
+	if !ok || moved.FromOwner != "alice" || moved.ToOwner != "bob" {
+		t.Errorf("event = %+v, want TodoMoved from alice to bob", todo.Events()[0])
+	}
+
+	if err := todo.MoveTo("bob"); err != ErrAlreadyOwned {
+		t.Errorf("MoveTo() to the owner error = %v, want %v", err, ErrAlreadyOwned)
+	}
+	var validationErr ValidationError
+	if err := todo.MoveTo(""); !errors.As(err, &validationErr) {
+		t.Errorf("MoveTo() to no owner error = %v, want a validation error", err)
+	}
+	if len(todo.Events()) != 1 {
+		t.Errorf("Expected no event for refused moves, got %d", len(todo.Events())-1)
+	}
+}
+
 func TestReconstituteTodo(t *testing.T) {
 	id, _ := ParseTodoID("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
 	title, _ := NewTaskTitle("Reconstituted Todo")
@@ -585,3 +814,74 @@ func TestReconstituteTodo(t *testing.T) {
 		t.Errorf("Reconstituted todo should have 0 events, got %d", len(todo.Events()))
 	}
 }
+
+// TestTodo_UpdatedEventsCarryChanges tests that TodoUpdated events carry the
+// new values of the fields changed
+func TestTodo_UpdatedEventsCarryChanges(t *testing.T) {
+	title, _ := NewTaskTitle("Renamed")
+	dueDate, _ := NewDueDate(time.Now().Add(24 * time.Hour))
+
+	tests := []struct {
+		name   string
+		change func(todo *Todo) error
+		check  func(event TodoUpdated) bool
+	}{
+		{
+			name:   "title",
+			change: func(todo *Todo) error { return todo.UpdateTitle(title) },
+			check:  func(e TodoUpdated) bool { return e.Title != nil && *e.Title == "Renamed" },
+		},
+		{
+			name:   "description",
+			change: func(todo *Todo) error { return todo.UpdateDescription("New") },
+			check:  func(e TodoUpdated) bool { return e.Description != nil && *e.Description == "New" },
+		},
+		{
+			name:   "priority",
+			change: func(todo *Todo) error { return todo.UpdatePriority(PriorityUrgent) },
+			check:  func(e TodoUpdated) bool { return e.Priority != nil && *e.Priority == PriorityUrgent.String() },
+		},
+		{
+			name:   "due date",
+			change: func(todo *Todo) error { return todo.UpdateDueDate(&dueDate) },
+			check: func(e TodoUpdated) bool {
+				return e.DueDate != nil && e.DueDate.Equal(dueDate.Time()) && !e.DueDateCleared
+			},
+		},
+		{
+			name:   "cleared due date",
+			change: func(todo *Todo) error { return todo.UpdateDueDate(nil) },
+			check:  func(e TodoUpdated) bool { return e.DueDate == nil && e.DueDateCleared },
+		},
+		{
+			name:   "status",
+			change: func(todo *Todo) error { return todo.MarkInProgress() },
+			check:  func(e TodoUpdated) bool { return e.Status != nil && *e.Status == StatusInProgress.String() },
+		},
+		{
+			name:   "cancellation",
+			change: func(todo *Todo) error { return todo.Cancel() },
+			check:  func(e TodoUpdated) bool { return e.Status != nil && *e.Status == StatusCancelled.String() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := createValidTodo(t)
+			todo.ClearEvents()
+
+			if err := tt.change(todo); err != nil {
+				t.Fatalf("change unexpected error: %v", err)
+			}
+
+			events := todo.Events()
+			if len(events) != 1 {
+				t.Fatalf("Expected 1 event, got %d", len(events))
+			}
+			event, ok := events[0].(TodoUpdated)
+			if !ok || !tt.check(event) {
+				t.Errorf("event = %+v, want the change carried", events[0])
+			}
+		})
+	}
+}
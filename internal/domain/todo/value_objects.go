@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +33,41 @@ func (id TodoID) String() string {
 	return string(id)
 }
 
+// ShortCodePrefix prefixes human-friendly todo identifiers, e.g. TD-1042
+const ShortCodePrefix = "TD-"
+
+// ShortCode is a short, human-friendly secondary identifier of a todo
+// It is assigned from a sequence when the todo is first persisted; the zero
+// value means no code has been assigned yet
+type ShortCode int64
+
+// ParseShortCode parses a string such as "TD-1042" (case-insensitive prefix)
+func ParseShortCode(code string) (ShortCode, error) {
+	if len(code) <= len(ShortCodePrefix) || !strings.EqualFold(code[:len(ShortCodePrefix)], ShortCodePrefix) {
+		return 0, ErrInvalidShortCode
+	}
+
+	n, err := strconv.ParseInt(code[len(ShortCodePrefix):], 10, 64)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidShortCode
+	}
+
+	return ShortCode(n), nil
+}
+
+// IsZero reports whether no code has been assigned
+func (c ShortCode) IsZero() bool {
+	return c == 0
+}
+
+// String returns the string representation of ShortCode, or "" if unassigned
+func (c ShortCode) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return ShortCodePrefix + strconv.FormatInt(int64(c), 10)
+}
+
 // IsEmpty checks if the TodoID is empty
 func (id TodoID) IsEmpty() bool {
 	return string(id) == ""
@@ -160,6 +196,12 @@ func NewDueDate(date time.Time) (DueDate, error) {
 	return DueDate{value: date}, nil
 }
 
+// ReconstituteDueDate restores a stored due date without the future-date check,
+// since it may have passed since it was set
+func ReconstituteDueDate(date time.Time) DueDate {
+	return DueDate{value: date}
+}
+
 // Time returns the time.Time value
 func (d DueDate) Time() time.Time {
 	return d.value
@@ -74,6 +74,53 @@ func TestParseTodoID(t *testing.T) {
 	}
 }
 
+func TestParseShortCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ShortCode
+		wantErr bool
+	}{
+		{name: "valid code", input: "TD-1042", want: 1042},
+		{name: "lowercase prefix", input: "td-7", want: 7},
+		{name: "missing number", input: "TD-", wantErr: true},
+		{name: "missing prefix", input: "1042", wantErr: true},
+		{name: "zero", input: "TD-0", wantErr: true},
+		{name: "not a number", input: "TD-abc", wantErr: true},
+		{name: "UUID", input: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := ParseShortCode(tt.input)
+
+			if tt.wantErr {
+				if err != ErrInvalidShortCode {
+					t.Errorf("ParseShortCode() error = %v, want %v", err, ErrInvalidShortCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseShortCode() unexpected error: %v", err)
+			}
+			if code != tt.want {
+				t.Errorf("ParseShortCode() = %v, want %v", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortCode_String(t *testing.T) {
+	if got := ShortCode(1042).String(); got != "TD-1042" {
+		t.Errorf("String() = %q, want %q", got, "TD-1042")
+	}
+
+	if got := ShortCode(0).String(); got != "" {
+		t.Errorf("String() of unassigned code = %q, want empty", got)
+	}
+}
+
 // TestTaskTitle tests TaskTitle validation
 func TestNewTaskTitle(t *testing.T) {
 	tests := []struct {
@@ -505,3 +552,17 @@ func TestDueDate_IsPast(t *testing.T) {
 	// This is correct behavior - once created, a DueDate is in the future
 	// It only becomes past as time progresses
 }
+
+func TestReconstituteDueDate_AllowsPastDates(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour)
+
+	dueDate := ReconstituteDueDate(past)
+
+	if !dueDate.Time().Equal(past) {
+		t.Errorf("Time() = %v, want %v", dueDate.Time(), past)
+	}
+
+	if !dueDate.IsPast() {
+		t.Error("IsPast() should return true for a reconstituted past date")
+	}
+}
@@ -0,0 +1,117 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrFull is returned when a key still has no free slot after the wait
+var ErrFull = errors.New("too many concurrent calls")
+
+// Bulkhead caps the concurrent calls of each key, so that one key cannot
+// use up a shared resource such as a connection pool
+// It is safe for concurrent use
+type Bulkhead struct {
+	limit int
+	wait  time.Duration
+
+	mu    sync.Mutex
+	slots map[string]*slots
+}
+
+// slots are the slots of one key; refs counts its holders and waiters, so
+// that idle keys are forgotten
+type slots struct {
+	sem  chan struct{}
+	refs int
+}
+
+// New creates a Bulkhead letting limit concurrent calls per key, each
+// waiting at most wait for a free slot
+// A limit lower than 1 is treated as 1
+func New(limit int, wait time.Duration) *Bulkhead {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Bulkhead{
+		limit: limit,
+		wait:  wait,
+		slots: make(map[string]*slots),
+	}
+}
+
+// Acquire takes a slot of key and returns the function releasing it
+// It returns ErrFull when no slot frees up within the wait, and the error
+// of ctx when it ends first
+func (b *Bulkhead) Acquire(ctx context.Context, key string) (func(), error) {
+	s := b.ref(key)
+
+	select {
+	case s.sem <- struct{}{}:
+		return b.releaser(key, s), nil
+	default:
+	}
+
+	timer := time.NewTimer(b.wait)
+	defer timer.Stop()
+
+	select {
+	case s.sem <- struct{}{}:
+		return b.releaser(key, s), nil
+	case <-timer.C:
+		b.unref(key, s)
+		return nil, ErrFull
+	case <-ctx.Done():
+		b.unref(key, s)
+		return nil, ctx.Err()
+	}
+}
+
+// InUse returns the slots of key taken
+func (b *Bulkhead) InUse(key string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.slots[key]; ok {
+		return len(s.sem)
+	}
+	return 0
+}
+
+// ref returns the slots of key, creating them if needed
+func (b *Bulkhead) ref(key string) *slots {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.slots[key]
+	if !ok {
+		s = &slots{sem: make(chan struct{}, b.limit)}
+		b.slots[key] = s
+	}
+	s.refs++
+	return s
+}
+
+// unref forgets the slots of key once nobody holds or waits for them
+func (b *Bulkhead) unref(key string, s *slots) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s.refs--
+	if s.refs == 0 {
+		delete(b.slots, key)
+	}
+}
+
+// releaser returns the function releasing a slot of key, once
+func (b *Bulkhead) releaser(key string, s *slots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-s.sem
+			b.unref(key, s)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBulkhead_CapsEachKey(t *testing.T) {
+	b := New(2, 10*time.Millisecond)
+	ctx := context.Background()
+
+	var releases []func()
+	for range 2 {
+		release, err := b.Acquire(ctx, "noisy")
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		releases = append(releases, release)
+	}
+
+	if _, err := b.Acquire(ctx, "noisy"); !errors.Is(err, ErrFull) {
+		t.Errorf("Acquire() past the limit error = %v, want ErrFull", err)
+	}
+
+	release, err := b.Acquire(ctx, "quiet")
+	if err != nil {
+		t.Fatalf("Acquire() of another key error = %v", err)
+	}
+	release()
+
+	releases[0]()
+	releases[0]() // releasing twice frees a single slot
+	if got := b.InUse("noisy"); got != 1 {
+		t.Errorf("InUse() = %d, want 1", got)
+	}
+
+	releases[1]()
+	if len(b.slots) != 0 {
+		t.Errorf("slots = %v, want idle keys forgotten", b.slots)
+	}
+}
+
+func TestBulkhead_WaitsForAFreeSlot(t *testing.T) {
+	b := New(1, time.Second)
+	ctx := context.Background()
+
+	release, err := b.Acquire(ctx, "noisy")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		release()
+	}()
+
+	next, err := b.Acquire(ctx, "noisy")
+	if err != nil {
+		t.Fatalf("Acquire() after a release error = %v", err)
+	}
+	next()
+}
+
+func TestBulkhead_Acquire_ContextCanceled(t *testing.T) {
+	b := New(1, time.Second)
+
+	release, err := b.Acquire(context.Background(), "noisy")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.Acquire(ctx, "noisy"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() error = %v, want context.Canceled", err)
+	}
+}
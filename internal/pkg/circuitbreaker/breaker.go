@@ -0,0 +1,238 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when a call is rejected because the circuit is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the current state of a Breaker
+type State int
+
+const (
+	// StateClosed lets every call through and counts consecutive failures
+	StateClosed State = iota
+	// StateOpen rejects every call until the open timeout elapses
+	StateOpen
+	// StateHalfOpen lets a limited number of trial calls through
+	StateHalfOpen
+)
+
+// String returns the string representation of State
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings configures a Breaker
+type Settings struct {
+	// Name identifies the protected dependency in logs and status reports
+	Name string
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before allowing trial calls
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of successful trial calls needed to close the circuit
+	HalfOpenMaxCalls int
+	// IsFailure decides whether an error counts as a dependency failure
+	// Defaults to treating every non-nil error as a failure
+	IsFailure func(err error) bool
+	// OnStateChange is called whenever the breaker changes state, outside
+	// of its lock, so it may call the breaker
+	OnStateChange func(name string, from, to State)
+}
+
+// DefaultSettings returns sensible settings for a named dependency
+func DefaultSettings(name string) Settings {
+	return Settings{
+		Name:             name,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenMaxCalls: 1,
+	}
+}
+
+// Breaker is a concurrency-safe circuit breaker
+// It stops calling a failing dependency so callers fail fast instead of
+// piling up on timeouts, then probes it again after a cool-down period
+type Breaker struct {
+	settings Settings
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// New creates a new Breaker, in the closed state
+func New(settings Settings) *Breaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.HalfOpenMaxCalls < 1 {
+		settings.HalfOpenMaxCalls = 1
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return err != nil }
+	}
+
+	return &Breaker{
+		settings: settings,
+		now:      time.Now,
+	}
+}
+
+// Name returns the name of the protected dependency
+func (b *Breaker) Name() string {
+	return b.settings.Name
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	change := b.refresh()
+	state := b.state
+	b.mu.Unlock()
+
+	b.notify(change)
+	return state
+}
+
+// Execute runs fn if the circuit allows it and records its outcome
+// It returns ErrOpen without calling fn when the circuit is open
+// A panicking fn is recorded as a failure before the panic goes on, so it
+// cannot hold a half-open slot
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.before(); err != nil {
+		return err
+	}
+
+	settled := false
+	defer func() {
+		if !settled {
+			b.settle(true)
+		}
+	}()
+
+	err := fn()
+	settled = true
+	b.settle(b.settings.IsFailure(err))
+
+	return err
+}
+
+// before checks whether a call may proceed
+func (b *Breaker) before() error {
+	b.mu.Lock()
+	change, err := b.admit()
+	b.mu.Unlock()
+
+	b.notify(change)
+	return err
+}
+
+// admit takes a call slot if the circuit allows it
+// Must be called with the lock held
+func (b *Breaker) admit() (*stateChange, error) {
+	change := b.refresh()
+
+	switch b.state {
+	case StateOpen:
+		return change, ErrOpen
+	case StateHalfOpen:
+		if b.inFlight >= b.settings.HalfOpenMaxCalls {
+			return change, ErrOpen
+		}
+	}
+
+	b.inFlight++
+	return change, nil
+}
+
+// settle releases the slot of a call and records whether it failed
+func (b *Breaker) settle(failed bool) {
+	b.mu.Lock()
+	change := b.record(failed)
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// record records the outcome of a call
+// Must be called with the lock held
+func (b *Breaker) record(failed bool) *stateChange {
+	b.inFlight--
+
+	if failed {
+		b.failures++
+		if b.state == StateHalfOpen || b.failures >= b.settings.FailureThreshold {
+			return b.transition(StateOpen)
+		}
+		return nil
+	}
+
+	b.failures = 0
+	if b.state == StateHalfOpen {
+		b.successes++
+		if b.successes >= b.settings.HalfOpenMaxCalls {
+			return b.transition(StateClosed)
+		}
+	}
+	return nil
+}
+
+// refresh moves an open circuit to half-open once the timeout has elapsed
+// Must be called with the lock held
+func (b *Breaker) refresh() *stateChange {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.settings.OpenTimeout {
+		return b.transition(StateHalfOpen)
+	}
+	return nil
+}
+
+// stateChange is a transition, reported once the lock is released
+type stateChange struct {
+	from State
+	to   State
+}
+
+// transition changes the state and resets counters, and returns the change
+// to report, nil when the state is unchanged
+// Must be called with the lock held
+func (b *Breaker) transition(to State) *stateChange {
+	from := b.state
+	if from == to {
+		return nil
+	}
+
+	b.state = to
+	b.failures = 0
+	b.successes = 0
+	if to == StateOpen {
+		b.openedAt = b.now()
+	}
+
+	return &stateChange{from: from, to: to}
+}
+
+// notify calls OnStateChange with change, if any
+// Must be called without the lock, so the callback may use the breaker
+func (b *Breaker) notify(change *stateChange) {
+	if change != nil && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.settings.Name, change.from, change.to)
+	}
+}
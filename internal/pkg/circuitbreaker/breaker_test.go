@@ -0,0 +1,185 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDependency = errors.New("dependency down")
+
+func newTestBreaker(now *time.Time) *Breaker {
+	b := New(Settings{
+		Name:             "test",
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		HalfOpenMaxCalls: 1,
+	})
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	for i := 0; i < 2; i++ {
+		if err := b.Execute(func() error { return errDependency }); !errors.Is(err, errDependency) {
+			t.Fatalf("Execute() error = %v, want %v", err, errDependency)
+		}
+	}
+
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %v, want %v", b.State(), StateOpen)
+	}
+
+	called := false
+	err := b.Execute(func() error {
+		called = true
+		return nil
+	})
+
+	if !errors.Is(err, ErrOpen) {
+		t.Errorf("Execute() error = %v, want %v", err, ErrOpen)
+	}
+
+	if called {
+		t.Error("Expected fn not to be called while the circuit is open")
+	}
+}
+
+func TestBreaker_SuccessResetsFailureCount(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	_ = b.Execute(func() error { return errDependency })
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return errDependency })
+
+	if b.State() != StateClosed {
+		t.Errorf("State() = %v, want %v", b.State(), StateClosed)
+	}
+}
+
+func TestBreaker_HalfOpen_ClosesOnSuccess(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	_ = b.Execute(func() error { return errDependency })
+	_ = b.Execute(func() error { return errDependency })
+
+	now = now.Add(2 * time.Minute)
+
+	if b.State() != StateHalfOpen {
+		t.Fatalf("State() = %v, want %v", b.State(), StateHalfOpen)
+	}
+
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if b.State() != StateClosed {
+		t.Errorf("State() = %v, want %v", b.State(), StateClosed)
+	}
+}
+
+func TestBreaker_HalfOpen_ReopensOnFailure(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	_ = b.Execute(func() error { return errDependency })
+	_ = b.Execute(func() error { return errDependency })
+
+	now = now.Add(2 * time.Minute)
+	_ = b.Execute(func() error { return errDependency })
+
+	if b.State() != StateOpen {
+		t.Errorf("State() = %v, want %v", b.State(), StateOpen)
+	}
+}
+
+func TestBreaker_IsFailure_IgnoresExpectedErrors(t *testing.T) {
+	errExpected := errors.New("not found")
+	b := New(Settings{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		IsFailure:        func(err error) bool { return err != nil && !errors.Is(err, errExpected) },
+	})
+
+	_ = b.Execute(func() error { return errExpected })
+
+	if b.State() != StateClosed {
+		t.Errorf("State() = %v, want %v", b.State(), StateClosed)
+	}
+}
+
+func TestBreaker_PanicReleasesHalfOpenSlot(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+	_ = b.Execute(func() error { return errDependency })
+	_ = b.Execute(func() error { return errDependency })
+	now = now.Add(time.Minute)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Execute() swallowed the panic of fn")
+			}
+		}()
+		_ = b.Execute(func() error { panic("boom") })
+	}()
+
+	// The panicking trial call failed, reopening the circuit
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %v, want %v", b.State(), StateOpen)
+	}
+
+	// Its slot was released, so the next trial call goes through
+	now = now.Add(time.Minute)
+	called := false
+	if err := b.Execute(func() error { called = true; return nil }); err != nil || !called {
+		t.Errorf("Execute() = %v, called %v, want the trial call made", err, called)
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State() = %v, want %v", b.State(), StateClosed)
+	}
+}
+
+func TestBreaker_OnStateChange_MayCallBreaker(t *testing.T) {
+	now := time.Now()
+	var b *Breaker
+	var states []State
+	b = New(Settings{
+		Name:             "test",
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		OnStateChange: func(name string, from, to State) {
+			states = append(states, b.State())
+		},
+	})
+	b.now = func() time.Time { return now }
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Execute(func() error { return errDependency })
+		now = now.Add(time.Minute)
+		_ = b.Execute(func() error { return nil })
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Execute() deadlocked in OnStateChange")
+	}
+	want := []State{StateOpen, StateHalfOpen, StateClosed}
+	if len(states) != len(want) {
+		t.Fatalf("OnStateChange saw %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Errorf("OnStateChange saw %v, want %v", states, want)
+			break
+		}
+	}
+}
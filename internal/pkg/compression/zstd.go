@@ -0,0 +1,71 @@
+package compression
+
+import (
+	"io"
+
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Zstd is the name of the Zstandard codec in Accept-Encoding, grpc-encoding
+// and Connect-Content-Encoding headers
+const Zstd = "zstd"
+
+// ZstdHandlerOption registers the Zstandard codec on a Connect handler
+func ZstdHandlerOption() connect.HandlerOption {
+	return connect.WithCompression(Zstd, newDecompressor, newCompressor)
+}
+
+// ZstdClientOption lets a Connect client accept Zstandard responses
+// Connect prefers the codec registered last, so the client asks for zstd
+// before gzip
+func ZstdClientOption() connect.ClientOption {
+	return connect.WithAcceptCompression(Zstd, newDecompressor, newCompressor)
+}
+
+func newCompressor() connect.Compressor     { return NewZstdCompressor() }
+func newDecompressor() connect.Decompressor { return NewZstdDecompressor() }
+
+// ZstdCompressor compresses a stream with Zstandard
+// It can be reset and reused after Close
+type ZstdCompressor struct {
+	*zstd.Encoder
+}
+
+// NewZstdCompressor creates a compressor with no destination
+// Call Reset before writing
+func NewZstdCompressor() *ZstdCompressor {
+	// Errors only come from invalid options
+	encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return &ZstdCompressor{Encoder: encoder}
+}
+
+// ZstdDecompressor decompresses a Zstandard stream
+// Unlike zstd.Decoder, it can be reset and reused after Close
+type ZstdDecompressor struct {
+	decoder *zstd.Decoder
+}
+
+// NewZstdDecompressor creates a decompressor with no source
+// Call Reset before reading
+func NewZstdDecompressor() *ZstdDecompressor {
+	// A single-threaded decoder runs no background goroutines, so it needs
+	// no cleanup once unused. Errors only come from invalid options
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	return &ZstdDecompressor{decoder: decoder}
+}
+
+// Read reads decompressed data
+func (d *ZstdDecompressor) Read(p []byte) (int, error) {
+	return d.decoder.Read(p)
+}
+
+// Reset starts decompressing a new source
+func (d *ZstdDecompressor) Reset(r io.Reader) error {
+	return d.decoder.Reset(r)
+}
+
+// Close detaches the decompressor from its source, which is not closed
+func (d *ZstdDecompressor) Close() error {
+	return d.decoder.Reset(nil)
+}
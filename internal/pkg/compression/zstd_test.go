@@ -0,0 +1,49 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestZstd_RoundTrip_ReusesCodecs(t *testing.T) {
+	compressor := NewZstdCompressor()
+	decompressor := NewZstdDecompressor()
+
+	// Connect closes and resets pooled codecs between messages
+	for _, message := range []string{strings.Repeat("todo ", 1000), "short"} {
+		var compressed bytes.Buffer
+		compressor.Reset(&compressed)
+		if _, err := compressor.Write([]byte(message)); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+		if err := compressor.Close(); err != nil {
+			t.Fatalf("Close() unexpected error: %v", err)
+		}
+
+		if err := decompressor.Reset(&compressed); err != nil {
+			t.Fatalf("Reset() unexpected error: %v", err)
+		}
+		got, err := io.ReadAll(decompressor)
+		if err != nil {
+			t.Fatalf("ReadAll() unexpected error: %v", err)
+		}
+		if err := decompressor.Close(); err != nil {
+			t.Fatalf("Close() unexpected error: %v", err)
+		}
+
+		if string(got) != message {
+			t.Errorf("round trip returned %d bytes, want %d", len(got), len(message))
+		}
+	}
+}
+
+func TestZstdDecompressor_CorruptInput_Fails(t *testing.T) {
+	decompressor := NewZstdDecompressor()
+	if err := decompressor.Reset(strings.NewReader("not zstd")); err == nil {
+		if _, err := io.ReadAll(decompressor); err == nil {
+			t.Error("ReadAll() on corrupt input succeeded, want an error")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package configfile reads nested YAML and TOML configuration files as flat
+// settings, keyed by their dotted path
+package configfile
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the syntax of a configuration file
+type Format string
+
+const (
+	// FormatYAML is YAML, read from .yaml and .yml files
+	FormatYAML Format = "yaml"
+	// FormatTOML is TOML, read from .toml files
+	FormatTOML Format = "toml"
+)
+
+// ReadFile reads the file at path in the format of its extension
+func ReadFile(path string) (map[string]string, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".toml":
+		format = FormatTOML
+	default:
+		return nil, fmt.Errorf("unknown format of %s, want .yaml, .yml or .toml", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Parse decodes a document in format and returns its values by dotted
+// path, e.g. "database.url" for the url key of the database table
+// Scalars are formatted as they would be typed in the environment, and
+// lists of scalars are joined with commas
+func Parse(data []byte, format Format) (map[string]string, error) {
+	var document map[string]any
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &document)
+	case FormatTOML:
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if err := flatten(values, "", document); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Paths returns the paths of values, sorted
+func Paths(values map[string]string) []string {
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// RedactURL hides the credentials of a URL, such as a database URL, for
+// logging: its password, or its user alone, which some servers take as a
+// token; a value that is not a URL with credentials is returned as is
+func RedactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), "xxxxx")
+	} else {
+		parsed.User = url.User("xxxxx")
+	}
+	return parsed.String()
+}
+
+// flatten adds the values of table to values, their paths prefixed
+func flatten(values map[string]string, prefix string, table map[string]any) error {
+	for key, value := range table {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			if err := flatten(values, path, nested); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text, err := format(path, value)
+		if err != nil {
+			return err
+		}
+		values[path] = text
+	}
+	return nil
+}
+
+// format formats the scalar or list of scalars at path
+func format(path string, value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case uint64:
+		return strconv.FormatUint(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	case []any:
+		items := make([]string, len(value))
+		for i, item := range value {
+			if _, ok := item.([]any); ok {
+				return "", fmt.Errorf("%s: lists of lists are not supported", path)
+			}
+			text, err := format(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = text
+		}
+		return strings.Join(items, ","), nil
+	case fmt.Stringer:
+		// TOML local dates and times
+		return value.String(), nil
+	default:
+		return "", fmt.Errorf("%s: unsupported %T value, want a scalar or a list of scalars", path, value)
+	}
+}
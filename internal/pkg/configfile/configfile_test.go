@@ -0,0 +1,150 @@
+package configfile
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// want is the settings of the yamlDocument and tomlDocument
+var want = map[string]string{
+	"server.port":                 "8090",
+	"server.cors_allowed_origins": "https://app.example.com,https://admin.example.com",
+	"database.url":                "postgres://todo:secret@db:5432/todo",
+	"database.replica_max_lag":    "10s",
+	"events.kafka.brokers":        "kafka-1:9092,kafka-2:9092",
+	"events.kafka.max_attempts":   "5",
+	"auth.api_keys":               "true",
+	"anomaly.ratio":               "0.5",
+	"auth.admin_token":            "",
+}
+
+const yamlDocument = `
+server:
+  port: 8090
+  cors_allowed_origins: [https://app.example.com, https://admin.example.com]
+database:
+  url: postgres://todo:secret@db:5432/todo
+  replica_max_lag: 10s
+events:
+  kafka:
+    brokers:
+      - kafka-1:9092
+      - kafka-2:9092
+    max_attempts: 5
+auth:
+  api_keys: true
+  admin_token:
+anomaly:
+  ratio: 0.5
+`
+
+const tomlDocument = `
+[server]
+port = 8090
+cors_allowed_origins = ["https://app.example.com", "https://admin.example.com"]
+
+[database]
+url = "postgres://todo:secret@db:5432/todo"
+replica_max_lag = "10s"
+
+[events.kafka]
+brokers = ["kafka-1:9092", "kafka-2:9092"]
+max_attempts = 5
+
+[auth]
+api_keys = true
+admin_token = ""
+
+[anomaly]
+ratio = 0.5
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		document string
+	}{
+		{"yaml", FormatYAML, yamlDocument},
+		{"toml", FormatTOML, tomlDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.document), tt.format)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("Parse() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		document string
+		want     string
+	}{
+		{"invalid yaml", FormatYAML, "server: [port", "yaml"},
+		{"invalid toml", FormatTOML, "[server\nport = 1", "toml"},
+		{"list of tables", FormatYAML, "webhooks:\n  - url: https://example.com\n", "webhooks[0]: unsupported"},
+		{"nested lists", FormatTOML, "hours = [[8, 12], [14, 19]]", "hours: lists of lists"},
+		{"unknown format", Format("ini"), "port = 1", "unknown format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.document), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"todo.yml": yamlDocument, "todo.toml": tomlDocument, "todo.ini": "port = 1"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"todo.yml", "todo.toml"} {
+		got, err := ReadFile(filepath.Join(dir, name))
+		if err != nil || got["server.port"] != "8090" {
+			t.Errorf("ReadFile(%s) = %v, %v, want the settings", name, got, err)
+		}
+	}
+	if _, err := ReadFile(filepath.Join(dir, "todo.ini")); err == nil {
+		t.Error("ReadFile(todo.ini) error = nil, want an unknown format")
+	}
+	if _, err := ReadFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("ReadFile(missing.yaml) error = nil")
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"postgres://todo:secret@db:5432/todo?sslmode=disable", "postgres://todo:xxxxx@db:5432/todo?sslmode=disable"},
+		{"nats://s3cr3t@nats:4222", "nats://xxxxx@nats:4222"},
+		{"amqp://rabbit:5672/", "amqp://rabbit:5672/"},
+		{"kafka-1:9092,kafka-2:9092", "kafka-1:9092,kafka-2:9092"},
+	}
+
+	for _, tt := range tests {
+		if got := RedactURL(tt.raw); got != tt.want {
+			t.Errorf("RedactURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line, without its CRLF, that
+// RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// timeLayout formats times in UTC, the form every calendar client accepts
+const timeLayout = "20060102T150405Z"
+
+// Writer writes an iCalendar stream (RFC 5545) one content line at a time
+// Lines end with CRLF and are folded at 75 octets without splitting UTF-8
+// sequences. The first write error is kept and returned by Flush, later
+// writes doing nothing
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter creates a Writer writing to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Begin opens a component, such as VCALENDAR, VEVENT or VTODO
+func (w *Writer) Begin(component string) {
+	w.line("BEGIN:" + component)
+}
+
+// End closes a component opened by Begin
+func (w *Writer) End(component string) {
+	w.line("END:" + component)
+}
+
+// Property writes a property whose value is used as is, such as a
+// VERSION, a STATUS or a PRIORITY
+func (w *Writer) Property(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text writes a property of type TEXT, escaping its value
+func (w *Writer) Text(name, value string) {
+	w.line(name + ":" + Escape(value))
+}
+
+// Time writes a DATE-TIME property, in UTC
+func (w *Writer) Time(name string, t time.Time) {
+	w.line(name + ":" + t.UTC().Format(timeLayout))
+}
+
+// Flush writes any buffered data and returns the first write error
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.w.Flush()
+	return w.err
+}
+
+// line writes a content line, folded
+func (w *Writer) line(content string) {
+	if w.err != nil {
+		return
+	}
+	for _, part := range fold(content) {
+		if _, w.err = w.w.WriteString(part); w.err != nil {
+			return
+		}
+	}
+}
+
+// fold splits a content line into CRLF terminated parts of at most
+// maxLineOctets octets, each continuation starting with a space
+func fold(content string) []string {
+	var parts []string
+	prefix := ""
+	for {
+		limit := maxLineOctets - len(prefix)
+		if len(content) <= limit {
+			return append(parts, prefix+content+"\r\n")
+		}
+
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		parts = append(parts, prefix+content[:cut]+"\r\n")
+		content = content[cut:]
+		prefix = " "
+	}
+}
+
+// textEscaper escapes the characters RFC 5545 reserves in TEXT values
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// Escape returns text as a TEXT value: backslashes, semicolons and commas
+// are escaped and line breaks become \n
+func Escape(text string) string {
+	return textEscaper.Replace(text)
+}
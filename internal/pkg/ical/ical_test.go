@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter_WritesContentLines(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Begin("VCALENDAR")
+	w.Property("VERSION", "2.0")
+	w.Begin("VTODO")
+	w.Text("SUMMARY", "Milk, eggs; bread")
+	w.Time("DUE", time.Date(2030, 1, 2, 16, 4, 5, 0, time.FixedZone("CET", 3600)))
+	w.End("VTODO")
+	w.End("VCALENDAR")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:Milk\\, eggs\\; bread\r\n" +
+		"DUE:20300102T150405Z\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("x", 100)
+	// é is two octets; the first fold would fall between them
+	accented := "SUMMARY:" + strings.Repeat("a", 66) + "é" + "b"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"short", "VERSION:2.0", []string{"VERSION:2.0\r\n"}},
+		{"exactly 75 octets", strings.Repeat("x", 75), []string{strings.Repeat("x", 75) + "\r\n"}},
+		{"long", long, []string{long[:75] + "\r\n", " " + long[75:] + "\r\n"}},
+		{"multibyte", accented, []string{accented[:74] + "\r\n", " " + accented[74:] + "\r\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fold(tt.content)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("fold(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+			for _, part := range got {
+				if len(part) > maxLineOctets+2 {
+					t.Errorf("part %q is %d octets long", part, len(part))
+				}
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{`C:\todo`, `C:\\todo`},
+		{"a,b;c", `a\,b\;c`},
+		{"two\r\nlines\nthree", `two\nlines\nthree`},
+	}
+
+	for _, tt := range tests {
+		if got := Escape(tt.text); got != tt.want {
+			t.Errorf("Escape(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriter_Flush_ReturnsWriteError(t *testing.T) {
+	w := NewWriter(failingWriter{})
+	w.Begin("VCALENDAR")
+	w.Text("DESCRIPTION", strings.Repeat("x", 5000))
+	w.End("VCALENDAR")
+
+	if err := w.Flush(); err == nil {
+		t.Error("Flush() error = nil, want the write error")
+	}
+}
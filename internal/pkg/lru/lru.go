@@ -0,0 +1,123 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a fixed-size, concurrency-safe least recently used cache
+// When full, adding a new key evicts the least recently used entry
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[K]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+// Stats reports the size and the lookups of a Cache
+type Stats struct {
+	Len      int
+	Capacity int
+	// Hits and Misses count the Get calls that found and missed their key
+	Hits   uint64
+	Misses uint64
+}
+
+// entry is the value stored in each list element
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// New creates a new Cache holding at most capacity entries
+// A capacity lower than 1 is treated as 1
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &Cache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+	}
+}
+
+// Get returns the value stored for key and marks it as recently used
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.hits++
+		c.order.MoveToFront(elem)
+		return elem.Value.(*entry[K, V]).value, true
+	}
+
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+// Add stores value for key, evicting the least recently used entry if needed
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Remove evicts key from the cache, reporting whether it was present
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	c.order.Remove(elem)
+	delete(c.items, key)
+
+	return true
+}
+
+// Len returns the number of cached entries
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats returns the current size and the lookups since the cache was created
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{Len: c.order.Len(), Capacity: c.capacity, Hits: c.hits, Misses: c.misses}
+}
+
+// Purge removes every entry from the cache
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = make(map[K]*list.Element, c.capacity)
+}
@@ -0,0 +1,88 @@
+package lru
+
+import "testing"
+
+func TestCache_GetAndAdd(t *testing.T) {
+	cache := New[string, int](2)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	if got, ok := cache.Get("a"); !ok || got != 1 {
+		t.Errorf("Get(a) = %v, %v, want 1, true", got, ok)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get(missing) expected miss")
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := New[string, int](2)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	cache.Get("a") // "b" is now the least recently used
+	cache.Add("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected a to be retained")
+	}
+
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+}
+
+func TestCache_AddExistingKey_ReplacesValue(t *testing.T) {
+	cache := New[string, int](2)
+
+	cache.Add("a", 1)
+	cache.Add("a", 10)
+
+	if got, _ := cache.Get("a"); got != 10 {
+		t.Errorf("Get(a) = %d, want 10", got)
+	}
+
+	if cache.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", cache.Len())
+	}
+}
+
+func TestCache_RemoveAndPurge(t *testing.T) {
+	cache := New[string, int](3)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	if !cache.Remove("a") {
+		t.Error("Remove(a) = false, want true")
+	}
+
+	if cache.Remove("a") {
+		t.Error("Remove(a) twice = true, want false")
+	}
+
+	cache.Purge()
+
+	if cache.Len() != 0 {
+		t.Errorf("Len() after Purge = %d, want 0", cache.Len())
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	cache := New[string, int](2)
+	cache.Add("a", 1)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+
+	want := Stats{Len: 1, Capacity: 2, Hits: 2, Misses: 1}
+	if got := cache.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
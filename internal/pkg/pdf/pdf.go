@@ -0,0 +1,212 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// A4 page geometry, in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// charWidth is the advance of a Courier glyph, relative to the font size
+// The monospaced standard fonts make wrapping exact without font metrics
+const charWidth = 0.6
+
+// lineSpacing is the line height, relative to the font size
+const lineSpacing = 1.3
+
+// line is a line of text placed on a page
+type line struct {
+	text string
+	x, y float64
+	size float64
+	bold bool
+}
+
+// Document is a text-only PDF document using the standard Courier fonts
+// Text is wrapped to the page width and flows onto new pages as needed
+type Document struct {
+	title string
+	pages [][]line
+	y     float64
+}
+
+// New creates an empty Document with the given title metadata
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+// Text adds a paragraph in the given font size, wrapped at word boundaries
+// and indented by indent points
+func (d *Document) Text(text string, size, indent float64, bold bool) {
+	maxChars := int((pageWidth - 2*margin - indent) / (size * charWidth))
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, wrapped := range wrap(paragraph, maxChars) {
+			height := size * lineSpacing
+			if d.y-height < margin {
+				d.newPage()
+			}
+			d.y -= height
+
+			page := len(d.pages) - 1
+			d.pages[page] = append(d.pages[page], line{text: wrapped, x: margin + indent, y: d.y, size: size, bold: bold})
+		}
+	}
+}
+
+// Space adds vertical space, starting a new page when it does not fit
+func (d *Document) Space(points float64) {
+	if d.y-points < margin {
+		d.newPage()
+		return
+	}
+	d.y -= points
+}
+
+// WriteTo writes the document in PDF format to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+
+	object := func(body string) {
+		offsets = append(offsets, counter.n)
+		fmt.Fprintf(counter, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts, 5 info; each page
+	// then takes a page object and a content stream object
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	fmt.Fprint(counter, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (Todo API) >>", literal(d.title)))
+
+	for i, lines := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1,
+		))
+
+		var content bytes.Buffer
+		for _, l := range lines {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %g Tf %.2f %.2f Td %s Tj ET\n", font, l.size, l.x, l.y, literal(l.text))
+		}
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := counter.n
+	fmt.Fprintf(counter, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(counter, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(counter, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if counter.err != nil {
+		return counter.n, counter.err
+	}
+	return counter.n, counter.w.Flush()
+}
+
+// newPage starts a new page, with the cursor at the top margin
+func (d *Document) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pageHeight - margin
+}
+
+// wrap splits text into lines of at most maxChars characters, breaking at
+// spaces when possible
+func wrap(text string, maxChars int) []string {
+	if maxChars < 1 {
+		maxChars = 1
+	}
+
+	var lines []string
+	for utf8.RuneCountInString(text) > maxChars {
+		runes := []rune(text)
+		cut := maxChars
+		for i := maxChars; i > 0; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		text = strings.TrimLeft(string(runes[cut:]), " ")
+	}
+
+	return append(lines, text)
+}
+
+// winAnsi maps the non-Latin-1 characters of the WinAnsi encoding
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// literal encodes s as a PDF literal string in WinAnsi encoding
+// Characters the standard fonts cannot display are replaced by '?'
+func literal(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if code, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", code)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// countingWriter counts the bytes written, to compute object offsets, and
+// keeps the first write error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteTo_ValidStructure(t *testing.T) {
+	doc := New("Checklist")
+	doc.Text("Buy groceries (milk & eggs)", 14, 0, true)
+	doc.Text("Pay rent", 11, 20, false)
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if n != int64(len(out)) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, len(out))
+	}
+
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatal("output is not framed as a PDF document")
+	}
+
+	if !strings.Contains(out, `(Buy groceries \(milk & eggs\))`) {
+		t.Error("parentheses are not escaped in the content stream")
+	}
+
+	// startxref must point at the xref table
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	offset, _ := strconv.Atoi(match[1])
+	if !strings.HasPrefix(out[offset:], "xref\n") {
+		t.Errorf("startxref %d does not point at the xref table", offset)
+	}
+
+	// Every xref entry must point at its object
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out, -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		want := strconv.Itoa(i+1) + " 0 obj"
+		if !strings.HasPrefix(out[offset:], want) {
+			t.Errorf("xref entry %d does not point at %q", i+1, want)
+		}
+	}
+}
+
+func TestDocument_Text_FlowsOntoNewPages(t *testing.T) {
+	doc := New("Long list")
+	for i := 0; i < 100; i++ {
+		doc.Text("Item "+strconv.Itoa(i), 12, 0, false)
+	}
+
+	if len(doc.pages) < 2 {
+		t.Errorf("pages = %d, want the text to flow onto several pages", len(doc.pages))
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "/Count "+strconv.Itoa(len(doc.pages))) {
+		t.Error("page tree count does not match the number of pages")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"unbreakableword", 5, []string{"unbre", "akabl", "eword"}},
+		{"", 10, []string{""}},
+	}
+
+	for _, tt := range tests {
+		got := wrap(tt.text, tt.maxChars)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.maxChars, got, tt.want)
+		}
+	}
+}
+
+func TestLiteral_EncodesWinAnsi(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`a\b`, `(a\\b)`},
+		{"café", `(caf\351)`},
+		{"“quoted” – ok", `(\223quoted\224 \226 ok)`},
+		{"日本", `(??)`},
+	}
+
+	for _, tt := range tests {
+		if got := literal(tt.in); got != tt.want {
+			t.Errorf("literal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
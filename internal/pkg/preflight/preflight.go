@@ -0,0 +1,105 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// checkTimeout bounds each check, so that an unreachable dependency cannot
+// hold the startup
+const checkTimeout = 5 * time.Second
+
+// Check verifies one precondition of the service before it serves requests
+type Check struct {
+	Name string
+	// Run returns nil when the check passes; Hint attaches the remediation
+	Run func(ctx context.Context) error
+	// Warning checks only log their failure, unless the run is strict
+	Warning bool
+}
+
+// HintedError is the failure of a check with the way to fix it
+type HintedError struct {
+	Err  error
+	Hint string
+}
+
+func (e *HintedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HintedError) Unwrap() error {
+	return e.Err
+}
+
+// Hint annotates err with the way to fix it; a nil err stays nil
+func Hint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &HintedError{Err: err, Hint: hint}
+}
+
+// Failure is a check that did not pass
+type Failure struct {
+	Check string
+	Err   error
+}
+
+// Error lists the checks that failed the preflight
+type Error struct {
+	Failures []Failure
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "preflight failed, %d check(s) did not pass:", len(e.Failures))
+	for _, failure := range e.Failures {
+		fmt.Fprintf(&b, "\n  - %s: %v", failure.Check, failure.Err)
+		var hinted *HintedError
+		if errors.As(failure.Err, &hinted) {
+			fmt.Fprintf(&b, "\n    fix: %s", hinted.Hint)
+		}
+	}
+	return b.String()
+}
+
+// Run runs every check, logging its outcome, and returns an *Error listing
+// the failed checks
+// Failed warning checks are only logged, unless strict
+func Run(ctx context.Context, logger *slog.Logger, checks []Check, strict bool) error {
+	var failures []Failure
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := check.Run(checkCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			logger.Debug("preflight check passed", "check", check.Name)
+		case check.Warning && !strict:
+			logger.Warn("preflight check failed", "check", check.Name, "error", err, "fix", hintOf(err))
+		default:
+			logger.Error("preflight check failed", "check", check.Name, "error", err, "fix", hintOf(err))
+			failures = append(failures, Failure{Check: check.Name, Err: err})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &Error{Failures: failures}
+	}
+	return nil
+}
+
+// hintOf returns the hint attached to err, if any
+func hintOf(err error) string {
+	var hinted *HintedError
+	if errors.As(err, &hinted) {
+		return hinted.Hint
+	}
+	return ""
+}
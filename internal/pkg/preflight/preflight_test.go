@@ -0,0 +1,67 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "database", Run: func(ctx context.Context) error { return nil }},
+		{Name: "migrations", Run: func(ctx context.Context) error {
+			return Hint(errors.New("2 migrations pending"), "run make db-migrate-up")
+		}, Warning: true},
+		{Name: "indexes", Run: func(ctx context.Context) error {
+			return Hint(errors.New("idx_todos_search missing"), "recreate it")
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		strict bool
+		want   []string
+	}{
+		{name: "warnings are logged", want: []string{"indexes"}},
+		{name: "strict fails on warnings", strict: true, want: []string{"migrations", "indexes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Run(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), checks, tt.strict)
+
+			var preflightErr *Error
+			if !errors.As(err, &preflightErr) {
+				t.Fatalf("Run() error = %v, want an *Error", err)
+			}
+			if len(preflightErr.Failures) != len(tt.want) {
+				t.Fatalf("failures = %+v, want %v", preflightErr.Failures, tt.want)
+			}
+			for i, name := range tt.want {
+				if preflightErr.Failures[i].Check != name {
+					t.Errorf("failure %d = %s, want %s", i, preflightErr.Failures[i].Check, name)
+				}
+			}
+			if !strings.Contains(err.Error(), "fix: recreate it") {
+				t.Errorf("Error() = %q, want the hint of the failure", err.Error())
+			}
+		})
+	}
+}
+
+func TestRun_AllPass(t *testing.T) {
+	checks := []Check{{Name: "database", Run: func(ctx context.Context) error { return nil }}}
+
+	if err := Run(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), checks, true); err != nil {
+		t.Errorf("Run() unexpected error: %v", err)
+	}
+}
+
+func TestHint_Nil(t *testing.T) {
+	if err := Hint(nil, "nothing to fix"); err != nil {
+		t.Errorf("Hint(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,96 @@
+package reqtrace
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stages of a request recorded by the adapters
+const (
+	// StageService is the time spent in the application service
+	StageService = "service"
+	// StageSQL is the time spent in database queries
+	StageSQL = "sql"
+	// StageDispatch is the time spent dispatching domain events
+	StageDispatch = "dispatch"
+)
+
+// Stage sums the time a request spent in one stage
+type Stage struct {
+	Count int
+	Total time.Duration
+	// Max is the longest single step and Slowest describes it, e.g. the
+	// SQL statement
+	Max     time.Duration
+	Slowest string
+}
+
+// Trace collects the stages of one request; it is safe for concurrent use
+type Trace struct {
+	mu     sync.Mutex
+	stages map[string]*Stage
+}
+
+// traceKey is the context key of the Trace of a request
+type traceKey struct{}
+
+// NewContext returns a copy of ctx carrying a new Trace, and the Trace
+func NewContext(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{stages: make(map[string]*Stage)}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// FromContext returns the Trace carried by ctx, nil outside a traced request
+func FromContext(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// Record adds a step of stage that lasted d, described by detail
+// It does nothing outside a traced request
+func Record(ctx context.Context, stage string, d time.Duration, detail string) {
+	trace := FromContext(ctx)
+	if trace == nil {
+		return
+	}
+
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+
+	s, ok := trace.stages[stage]
+	if !ok {
+		s = &Stage{}
+		trace.stages[stage] = s
+	}
+	s.Count++
+	s.Total += d
+	if d > s.Max {
+		s.Max = d
+		s.Slowest = detail
+	}
+}
+
+// Start starts a step of stage and returns the function ending it
+func Start(ctx context.Context, stage, detail string) func() {
+	if FromContext(ctx) == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		Record(ctx, stage, time.Since(start), detail)
+	}
+}
+
+// Stages returns a copy of the stages recorded so far
+func (t *Trace) Stages() map[string]Stage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stages := make(map[string]Stage, len(t.stages))
+	for name, s := range t.stages {
+		stages[name] = *s
+	}
+	return stages
+}
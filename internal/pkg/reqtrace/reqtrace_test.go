@@ -0,0 +1,47 @@
+package reqtrace
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecord_SumsStepsAndKeepsSlowest(t *testing.T) {
+	ctx, trace := NewContext(context.Background())
+
+	Record(ctx, StageSQL, 2*time.Millisecond, "SELECT 1")
+	Record(ctx, StageSQL, 5*time.Millisecond, "SELECT 2")
+	Record(ctx, StageSQL, 3*time.Millisecond, "SELECT 3")
+
+	sql := trace.Stages()[StageSQL]
+	if sql.Count != 3 || sql.Total != 10*time.Millisecond {
+		t.Errorf("sql = %+v, want 3 queries for 10ms", sql)
+	}
+	if sql.Max != 5*time.Millisecond || sql.Slowest != "SELECT 2" {
+		t.Errorf("slowest = %v %q, want 5ms %q", sql.Max, sql.Slowest, "SELECT 2")
+	}
+}
+
+func TestRecord_WithoutTrace_DoesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	Record(ctx, StageSQL, time.Second, "SELECT 1")
+	Start(ctx, StageDispatch, "TodoCreated")()
+
+	if FromContext(ctx) != nil {
+		t.Error("FromContext() expected no trace")
+	}
+}
+
+func TestStart_RecordsElapsedTime(t *testing.T) {
+	ctx, trace := NewContext(context.Background())
+
+	end := Start(ctx, StageDispatch, "TodoCreated")
+	time.Sleep(time.Millisecond)
+	end()
+
+	dispatch := trace.Stages()[StageDispatch]
+	if dispatch.Count != 1 || dispatch.Total < time.Millisecond || dispatch.Slowest != "TodoCreated" {
+		t.Errorf("dispatch = %+v, want one TodoCreated step of at least 1ms", dispatch)
+	}
+}
@@ -0,0 +1,138 @@
+package reqtrace
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hookPrefix is the path of the inbound hooks, followed by their token
+const hookPrefix = "/hooks/"
+
+// RedactPath returns path with the credential it may carry replaced, so
+// that it can be logged: the token of an inbound hook, POST /hooks/{token},
+// is the only credential of the hook
+func RedactPath(path string) string {
+	if strings.HasPrefix(path, hookPrefix) && len(path) > len(hookPrefix) {
+		return hookPrefix + "{token}"
+	}
+	return path
+}
+
+// Dump is the breakdown of a request slower than the threshold; Path is
+// redacted (see RedactPath)
+// Handler is the time spent outside the service, or, when the service was
+// not timed, outside the SQL queries and dispatches: routing, middlewares,
+// decoding and encoding
+type Dump struct {
+	At      time.Time
+	Method  string
+	Path    string
+	Status  int
+	Total   time.Duration
+	Handler time.Duration
+	Stages  map[string]Stage
+}
+
+// SlowLog keeps the latest dumps of slow requests; it is safe for
+// concurrent use
+type SlowLog struct {
+	mu    sync.Mutex
+	dumps []Dump
+	next  int
+	full  bool
+}
+
+// NewSlowLog creates a SlowLog keeping the latest capacity dumps
+// A capacity lower than 1 is treated as 1
+func NewSlowLog(capacity int) *SlowLog {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SlowLog{dumps: make([]Dump, capacity)}
+}
+
+// Add keeps dump, dropping the oldest one when full
+func (l *SlowLog) Add(dump Dump) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.dumps[l.next] = dump
+	l.next = (l.next + 1) % len(l.dumps)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns the dumps kept, latest first
+func (l *SlowLog) Recent() []Dump {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.dumps)
+	}
+	recent := make([]Dump, count)
+	for i := range recent {
+		recent[i] = l.dumps[(l.next-1-i+len(l.dumps))%len(l.dumps)]
+	}
+	return recent
+}
+
+// Middleware traces every request and passes the dump of those lasting
+// threshold or more to report
+// Event streams are long by design and never reported
+func Middleware(next http.Handler, threshold time.Duration, report func(Dump)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, trace := NewContext(r.Context())
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		total := time.Since(start)
+		if total < threshold || recorder.Header().Get("Content-Type") == "text/event-stream" {
+			return
+		}
+		report(newDump(start, r, recorder.status, total, trace.Stages()))
+	})
+}
+
+// newDump builds the dump of a request
+func newDump(start time.Time, r *http.Request, status int, total time.Duration, stages map[string]Stage) Dump {
+	handler := total
+	if service, ok := stages[StageService]; ok {
+		handler -= service.Total
+	} else {
+		handler -= stages[StageSQL].Total + stages[StageDispatch].Total
+	}
+
+	return Dump{
+		At:      start,
+		Method:  r.Method,
+		Path:    RedactPath(r.URL.Path),
+		Status:  status,
+		Total:   total,
+		Handler: max(handler, 0),
+		Stages:  stages,
+	}
+}
+
+// statusRecorder captures the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so that
+// streaming responses can flush
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,111 @@
+package reqtrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowLog_Recent_LatestFirst(t *testing.T) {
+	log := NewSlowLog(2)
+
+	log.Add(Dump{Path: "/a"})
+	log.Add(Dump{Path: "/b"})
+	log.Add(Dump{Path: "/c"})
+
+	recent := log.Recent()
+	if len(recent) != 2 || recent[0].Path != "/c" || recent[1].Path != "/b" {
+		t.Errorf("Recent() = %+v, want /c then /b", recent)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   time.Duration
+		contentType string
+		wantReport  bool
+	}{
+		{name: "slow request", threshold: time.Millisecond, wantReport: true},
+		{name: "fast request", threshold: time.Hour},
+		{name: "event stream", threshold: time.Millisecond, contentType: "text/event-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Record(r.Context(), StageService, 2*time.Millisecond, "/todo.v1.TodoService/GetTodo")
+				Record(r.Context(), StageSQL, time.Millisecond, "SELECT 1")
+				time.Sleep(3 * time.Millisecond)
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			var dumps []Dump
+			handler := Middleware(next, tt.threshold, func(dump Dump) {
+				dumps = append(dumps, dump)
+			})
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/todos/1", nil))
+
+			if !tt.wantReport {
+				if len(dumps) != 0 {
+					t.Errorf("reported %+v, want nothing", dumps)
+				}
+				return
+			}
+			if len(dumps) != 1 {
+				t.Fatalf("reported %d dumps, want 1", len(dumps))
+			}
+			dump := dumps[0]
+			if dump.Method != http.MethodGet || dump.Path != "/todos/1" || dump.Status != http.StatusNotFound {
+				t.Errorf("dump = %s %s %d, want GET /todos/1 404", dump.Method, dump.Path, dump.Status)
+			}
+			if dump.Handler != dump.Total-2*time.Millisecond {
+				t.Errorf("Handler = %v, want the total %v minus the service time", dump.Handler, dump.Total)
+			}
+			if dump.Stages[StageSQL].Slowest != "SELECT 1" {
+				t.Errorf("stages = %+v, want the SQL query", dump.Stages)
+			}
+		})
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/hooks/s3cr3t-token", "/hooks/{token}"},
+		{"/hooks/", "/hooks/"},
+		{"/api/todos/1", "/api/todos/1"},
+		{"/todo.v1.TodoService/GetTodo", "/todo.v1.TodoService/GetTodo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := RedactPath(tt.path); got != tt.want {
+				t.Errorf("RedactPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_RedactsHookToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+	})
+
+	var dumps []Dump
+	handler := Middleware(next, time.Millisecond, func(dump Dump) {
+		dumps = append(dumps, dump)
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/hooks/s3cr3t-token", nil))
+
+	if len(dumps) != 1 || strings.Contains(dumps[0].Path, "s3cr3t") {
+		t.Errorf("reported %+v, want one dump without the token", dumps)
+	}
+}
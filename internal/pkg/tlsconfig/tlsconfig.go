@@ -0,0 +1,142 @@
+// Package tlsconfig builds the TLS configuration of a server from its
+// settings
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Options are the TLS settings of a server
+// The certificate and its key are read from CertFile and KeyFile, or given
+// as PEM in CertPEM and KeyPEM
+type Options struct {
+	CertFile string
+	KeyFile  string
+	CertPEM  []byte
+	KeyPEM   []byte
+	// MinVersion is "1.2" or "1.3", "1.2" when empty
+	MinVersion string
+	// CipherSuites are the names of the TLS 1.2 cipher suites offered, as
+	// in tls.CipherSuiteName; the Go defaults when empty. TLS 1.3 suites
+	// cannot be configured
+	CipherSuites []string
+}
+
+// Enabled reports whether options configure a certificate
+func (o Options) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != "" || len(o.CertPEM) > 0 || len(o.KeyPEM) > 0
+}
+
+// http2CipherSuites are the TLS 1.2 suites HTTP/2 requires one of (RFC 7540
+// section 9.2.2)
+var http2CipherSuites = []uint16{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+}
+
+// New loads the certificate of options and returns the configuration
+// serving it, offering HTTP/2 and HTTP/1.1
+func New(options Options) (*tls.Config, error) {
+	certificate, err := loadCertificate(options)
+	if err != nil {
+		return nil, err
+	}
+
+	minVersion, err := ParseVersion(options.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   minVersion,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if len(options.CipherSuites) > 0 {
+		if minVersion == tls.VersionTLS13 {
+			return nil, errors.New("cipher suites cannot be configured with TLS 1.3 only")
+		}
+		config.CipherSuites, err = ParseCipherSuites(options.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(config.CipherSuites, func(id uint16) bool { return slices.Contains(http2CipherSuites, id) }) {
+			return nil, errors.New("cipher suites must include TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 or TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 for HTTP/2")
+		}
+	}
+
+	return config, nil
+}
+
+// loadCertificate reads the certificate and key of options, from files or
+// PEM but not both
+func loadCertificate(options Options) (tls.Certificate, error) {
+	files := options.CertFile != "" || options.KeyFile != ""
+	pem := len(options.CertPEM) > 0 || len(options.KeyPEM) > 0
+	switch {
+	case files && pem:
+		return tls.Certificate{}, errors.New("certificate given both as files and as PEM")
+	case files:
+		if options.CertFile == "" || options.KeyFile == "" {
+			return tls.Certificate{}, errors.New("certificate and key files go together")
+		}
+		certificate, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("loading certificate: %w", err)
+		}
+		return certificate, nil
+	case pem:
+		if len(options.CertPEM) == 0 || len(options.KeyPEM) == 0 {
+			return tls.Certificate{}, errors.New("certificate and key PEM go together")
+		}
+		certificate, err := tls.X509KeyPair(options.CertPEM, options.KeyPEM)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("parsing certificate: %w", err)
+		}
+		return certificate, nil
+	default:
+		return tls.Certificate{}, errors.New("no certificate configured")
+	}
+}
+
+// ParseVersion parses a TLS version, "1.2" or "1.3"; empty is "1.2"
+func ParseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q, want 1.2 or 1.3", version)
+	}
+}
+
+// ParseCipherSuites returns the IDs of the TLS 1.2 cipher suites named
+// Insecure suites are refused
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]*tls.CipherSuite)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite
+	}
+
+	var ids []uint16
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		suite, ok := known[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+		case !slices.Contains(suite.SupportedVersions, tls.VersionTLS12):
+			return nil, fmt.Errorf("cipher suite %q is not a TLS 1.2 suite", name)
+		}
+		ids = append(ids, suite.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,161 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSigned returns the PEM of a self-signed certificate for localhost
+// and of its key
+func selfSigned(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNew(t *testing.T) {
+	certPEM, keyPEM := selfSigned(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		options     Options
+		wantVersion uint16
+		wantSuites  int
+	}{
+		{"files", Options{CertFile: certFile, KeyFile: keyFile}, tls.VersionTLS12, 0},
+		{"PEM", Options{CertPEM: certPEM, KeyPEM: keyPEM, MinVersion: "1.3"}, tls.VersionTLS13, 0},
+		{"cipher suites", Options{CertPEM: certPEM, KeyPEM: keyPEM, CipherSuites: []string{
+			"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", " TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256", "",
+		}}, tls.VersionTLS12, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := New(tt.options)
+			if err != nil {
+				t.Fatalf("New() unexpected error: %v", err)
+			}
+			if len(config.Certificates) != 1 || config.MinVersion != tt.wantVersion || len(config.CipherSuites) != tt.wantSuites {
+				t.Errorf("New() = %+v, want a certificate, version %x and %d suites", config, tt.wantVersion, tt.wantSuites)
+			}
+			if !slices.Equal(config.NextProtos, []string{"h2", "http/1.1"}) {
+				t.Errorf("NextProtos = %v, want h2 then http/1.1", config.NextProtos)
+			}
+		})
+	}
+}
+
+func TestNew_ServesHTTP2(t *testing.T) {
+	certPEM, keyPEM := selfSigned(t)
+	config, err := New(Options{CertPEM: certPEM, KeyPEM: keyPEM})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	server := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, r.Proto) }),
+		TLSConfig: config,
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.ServeTLS(listener, "", "")
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "localhost"}, ForceAttemptHTTP2: true}}
+	resp, err := client.Get("https://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "HTTP/2.0" {
+		t.Errorf("protocol = %s, want HTTP/2.0", body)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	certPEM, keyPEM := selfSigned(t)
+
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{"no certificate", Options{}, "no certificate"},
+		{"files and PEM", Options{CertFile: "tls.crt", KeyFile: "tls.key", CertPEM: certPEM, KeyPEM: keyPEM}, "both"},
+		{"certificate file alone", Options{CertFile: "tls.crt"}, "go together"},
+		{"key PEM alone", Options{KeyPEM: keyPEM}, "go together"},
+		{"missing files", Options{CertFile: "missing.crt", KeyFile: "missing.key"}, "loading certificate"},
+		{"mismatched PEM", Options{CertPEM: keyPEM, KeyPEM: certPEM}, "parsing certificate"},
+		{"unknown version", Options{CertPEM: certPEM, KeyPEM: keyPEM, MinVersion: "1.1"}, "unsupported TLS version"},
+		{"suites with TLS 1.3", Options{CertPEM: certPEM, KeyPEM: keyPEM, MinVersion: "1.3", CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}}, "TLS 1.3 only"},
+		{"insecure suite", Options{CertPEM: certPEM, KeyPEM: keyPEM, CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, "insecure"},
+		{"TLS 1.3 suite", Options{CertPEM: certPEM, KeyPEM: keyPEM, CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}, "not a TLS 1.2 suite"},
+		{"no HTTP/2 suite", Options{CertPEM: certPEM, KeyPEM: keyPEM, CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"}}, "for HTTP/2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_Enabled(t *testing.T) {
+	if (Options{MinVersion: "1.3"}).Enabled() {
+		t.Error("Enabled() = true without a certificate")
+	}
+	if !(Options{KeyFile: "tls.key"}).Enabled() {
+		t.Error("Enabled() = false with a key file")
+	}
+}
@@ -0,0 +1,94 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// ActivityKind is the kind of per-user activity tracked on todos
+type ActivityKind string
+
+const (
+	// ActivityViewed is recorded when a user reads a todo
+	ActivityViewed ActivityKind = "viewed"
+	// ActivityModified is recorded when a user changes a todo
+	ActivityModified ActivityKind = "modified"
+)
+
+// RecentActivityStore keeps the last time each user viewed or modified each todo
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type RecentActivityStore interface {
+	// Touch records that userID had activity of the given kind on a todo at the given time
+	Touch(ctx context.Context, userID string, todoID domain.TodoID, kind ActivityKind, at time.Time) error
+
+	// ListRecent returns at most limit todo IDs with activity of the given
+	// kind by userID, most recent first
+	ListRecent(ctx context.Context, userID string, kind ActivityKind, limit int) ([]domain.TodoID, error)
+}
+
+// TodoView is the last time a user viewed a todo
+type TodoView struct {
+	UserID   string
+	TodoID   domain.TodoID
+	ViewedAt time.Time
+}
+
+// RecentActivityScanner reads the last views of every user, for compliance
+// reports
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type RecentActivityScanner interface {
+	// ListViewsBetween returns at most limit last views that happened at or
+	// after from and before to, oldest first
+	ListViewsBetween(ctx context.Context, from, to time.Time, limit int) ([]TodoView, error)
+}
+
+// ActivityEventKind is the kind of change reported by the activity feed
+type ActivityEventKind string
+
+const (
+	// ActivityCreated reports the creation of a todo
+	ActivityCreated ActivityEventKind = "created"
+	// ActivityUpdated reports any other change to a todo
+	ActivityUpdated ActivityEventKind = "updated"
+	// ActivityCompleted reports a todo moving to the completed status
+	ActivityCompleted ActivityEventKind = "completed"
+	// ActivityDeleted reports the deletion of a todo
+	ActivityDeleted ActivityEventKind = "deleted"
+)
+
+// ActivityEvent is a change to a todo, as listed by the activity feed
+type ActivityEvent struct {
+	// Seq orders events; later events have greater sequence numbers
+	Seq        int64
+	TodoID     domain.TodoID
+	Title      string
+	Kind       ActivityEventKind
+	OccurredAt time.Time
+}
+
+// TodoActivityFeed lists the changes to all todos
+// This is a secondary port (driven), implemented by repositories that keep history
+type TodoActivityFeed interface {
+	// ListActivity returns at most limit events with a sequence number below
+	// before (all events when before is zero), most recent first
+	ListActivity(ctx context.Context, before int64, limit int) ([]ActivityEvent, error)
+}
+
+// DailyCount is a number of events on a UTC day
+type DailyCount struct {
+	Day   time.Time
+	Count int
+}
+
+// CompletionLog records which user completed which todo, and when
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type CompletionLog interface {
+	// RecordCompletion records that userID completed a todo at the given time
+	RecordCompletion(ctx context.Context, userID string, todoID domain.TodoID, at time.Time) error
+
+	// DailyCompletions counts the completions by userID on each UTC day from
+	// since to today, including days without completions, oldest first
+	DailyCompletions(ctx context.Context, userID string, since time.Time) ([]DailyCount, error)
+}
@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AnalyticsQuery selects the buckets of an analytics time series
+type AnalyticsQuery struct {
+	// From is the start of the first bucket
+	From time.Time
+	// Buckets is the number of consecutive buckets
+	Buckets int
+	// BucketDays is the length of a bucket, in days
+	BucketDays int
+}
+
+// AnalyticsBucket counts todo activity over one bucket of time
+type AnalyticsBucket struct {
+	Start     time.Time
+	Created   int
+	Completed int
+	// Overdue counts the todos past due, and neither completed nor
+	// cancelled, at the end of the bucket
+	Overdue int
+}
+
+// AnalyticsSeries is an analytics time series with its summary
+type AnalyticsSeries struct {
+	Buckets []AnalyticsBucket
+	// Completed counts the todos completed over all buckets
+	Completed int
+	// AverageCycleTime is the mean time from creation to completion of the
+	// todos completed over all buckets, zero if none were
+	AverageCycleTime time.Duration
+}
+
+// TodoAnalytics computes trends over todos
+// This is a secondary port (driven), implemented by repositories that record completion times
+type TodoAnalytics interface {
+	Analytics(ctx context.Context, query AnalyticsQuery) (*AnalyticsSeries, error)
+}
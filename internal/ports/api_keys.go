@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// APIKey is a static credential of a machine client
+// Calls made with it are authenticated as UserID and granted Scopes
+type APIKey struct {
+	ID        string
+	Name      string
+	UserID    string
+	Scopes    []string
+	CreatedAt time.Time
+}
+
+// APIKeyStore persists API keys, identified by a hash of their secret
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type APIKeyStore interface {
+	// Create stores a new key whose secret hashes to keyHash
+	Create(ctx context.Context, key APIKey, keyHash []byte) error
+
+	// FindByKeyHash returns the key of a secret hash, or nil if there is none
+	FindByKeyHash(ctx context.Context, keyHash []byte) (*APIKey, error)
+
+	// List returns every key, oldest first
+	List(ctx context.Context) ([]APIKey, error)
+
+	// Delete removes a key, reporting whether it existed
+	Delete(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,72 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEntry records a privileged action taken on a todo
+type AuditEntry struct {
+	// Action identifies the operation, e.g. "force_update"
+	Action string
+	// TodoID is the todo the action applied to
+	TodoID string
+	// Actor identifies who performed the action
+	Actor string
+	// Reason is the justification given by the actor
+	Reason string
+	// Changes maps each modified field to its new value
+	Changes map[string]string
+	// OccurredAt is when the action was taken
+	OccurredAt time.Time
+}
+
+// AuditLog defines the interface for recording privileged actions
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type AuditLog interface {
+	// Record appends an entry to the audit log
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditLogReader reads the audit log back, for compliance reports
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type AuditLogReader interface {
+	// ListEntries returns at most limit entries that occurred at or after
+	// from and before to, oldest first
+	ListEntries(ctx context.Context, from, to time.Time, limit int) ([]AuditEntry, error)
+}
+
+// TodoAuditEntry records a domain event of a todo and who caused it
+// Seq orders the entries of all todos as they were recorded; it is set
+// when entries are read
+type TodoAuditEntry struct {
+	Seq    int64
+	TodoID string
+	// EventType is the type of the domain event, e.g. "TodoUpdated"
+	EventType string
+	// Actor identifies the user who caused the event, empty for the service
+	// itself
+	Actor string
+	// Changes maps each field set by the event to its new value
+	Changes    map[string]string
+	OccurredAt time.Time
+}
+
+// TodoAuditTrail keeps the history of the domain events of each todo
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type TodoAuditTrail interface {
+	// Append records entries, all or none
+	Append(ctx context.Context, entries []TodoAuditEntry) error
+
+	// ListByTodo returns the entries of a todo, oldest first
+	ListByTodo(ctx context.Context, todoID string) ([]TodoAuditEntry, error)
+}
+
+// TodoAuditScanner reads the todo audit trail of every todo, to replay the
+// domain events of a period
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type TodoAuditScanner interface {
+	// ListBetween returns at most limit entries that occurred at or after
+	// from and before to, with a Seq above after, in Seq order
+	ListBetween(ctx context.Context, from, to time.Time, after int64, limit int) ([]TodoAuditEntry, error)
+}
@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// TodoAttributes describes the todo an action applies to, for policies
+type TodoAttributes struct {
+	ID        string
+	Title     string
+	Status    string
+	Priority  string
+	DueDate   *time.Time
+	CreatedAt time.Time
+	// OwnerID is the user the todo belongs to, empty if unowned
+	OwnerID string
+}
+
+// AuthorizationRequest is an action a user attempts, with its context
+type AuthorizationRequest struct {
+	// UserID is the authenticated user, empty for anonymous requests
+	UserID string
+	// Roles are the roles of the user, nil when authentication carries none
+	Roles []string
+	// Action is the operation, e.g. "read", "update" or "list"
+	Action string
+	// Todo is the todo the action applies to; nil for actions on no
+	// single todo, such as listing
+	Todo *TodoAttributes
+	// Time is when the action is attempted
+	Time time.Time
+}
+
+// Authorizer decides whether a user may perform an action
+// This is a secondary port (driven), implemented by policy engine adapters
+type Authorizer interface {
+	// Authorize reports whether the request is allowed
+	Authorize(ctx context.Context, req AuthorizationRequest) (bool, error)
+}
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// BackfillJob is a batched, resumable data migration (e.g. populating a new column)
+// This is a secondary port (driven) - run by the application, implemented by adapters
+type BackfillJob interface {
+	// Name uniquely identifies the job and keys its progress record
+	Name() string
+
+	// Description explains what the job does, for operators
+	Description() string
+
+	// RunBatch processes up to batchSize items located after cursor
+	// An empty cursor means the job starts from the beginning
+	RunBatch(ctx context.Context, cursor string, batchSize int) (BackfillBatch, error)
+}
+
+// BackfillBatch is the outcome of a single batch of a BackfillJob
+type BackfillBatch struct {
+	// Cursor identifies the last processed item; the next batch resumes after it
+	Cursor string
+	// Processed is the number of items handled by the batch
+	Processed int
+	// Done reports that no items remain
+	Done bool
+}
+
+// BackfillProgress is the persisted state of a BackfillJob
+type BackfillProgress struct {
+	Name        string
+	Status      string
+	Cursor      string
+	Processed   int64
+	LastError   string
+	StartedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// BackfillProgressStore persists backfill progress so jobs can resume after interruption
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type BackfillProgressStore interface {
+	// Get returns the progress of a job, or nil if it never ran
+	Get(ctx context.Context, name string) (*BackfillProgress, error)
+
+	// Save creates or replaces the progress of a job
+	Save(ctx context.Context, progress *BackfillProgress) error
+
+	// List returns the progress of every job that ever ran
+	List(ctx context.Context) ([]*BackfillProgress, error)
+}
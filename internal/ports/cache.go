@@ -0,0 +1,20 @@
+package ports
+
+import "context"
+
+// CacheInvalidation evicts entries of the in-process caches
+// An empty Key evicts every entry of Cache, and an empty Cache every entry
+// of every cache
+type CacheInvalidation struct {
+	Cache string
+	Key   string
+}
+
+// CacheInvalidationBus broadcasts cache invalidations to every instance of
+// the service, so that a change through one instance does not leave stale
+// entries on the others
+// This is a secondary port (driven)
+type CacheInvalidationBus interface {
+	// Publish delivers invalidation to every instance, this one included
+	Publish(ctx context.Context, invalidation CacheInvalidation) error
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// writtenAtKey is the context key of the time of the caller's last write
+type writtenAtKey struct{}
+
+// ContextWithWrittenAt returns a copy of ctx reading data at least as fresh
+// as a write the caller made at writtenAt
+// Adapters call it with the time carried by the consistency token echoed by
+// the caller, for read-your-writes
+func ContextWithWrittenAt(ctx context.Context, writtenAt time.Time) context.Context {
+	return context.WithValue(ctx, writtenAtKey{}, writtenAt)
+}
+
+// WrittenAtFromContext returns the time of the caller's last write, if any
+// Data older than it, cached or on a lagging replica, must not be read
+func WrittenAtFromContext(ctx context.Context) (time.Time, bool) {
+	writtenAt, ok := ctx.Value(writtenAtKey{}).(time.Time)
+	return writtenAt, ok && !writtenAt.IsZero()
+}
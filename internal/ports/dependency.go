@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// Dependency records that a todo is blocked by another one
+type Dependency struct {
+	TodoID    domain.TodoID
+	BlockedBy domain.TodoID
+}
+
+// DependencyStore persists the blocked-by relations between todos
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type DependencyStore interface {
+	// Add stores a dependency; adding an existing one does nothing
+	Add(ctx context.Context, dependency Dependency) error
+
+	// Remove deletes a dependency, reporting whether there was one
+	Remove(ctx context.Context, dependency Dependency) (bool, error)
+
+	// Touching returns the dependencies with either end in ids
+	Touching(ctx context.Context, ids []domain.TodoID) ([]Dependency, error)
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// EditLock tells that a user is editing a todo, until it expires
+// It is advisory: it does not keep anyone from updating the todo
+type EditLock struct {
+	TodoID     domain.TodoID
+	HolderID   string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// EditLockStore persists the edit locks of todos
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type EditLockStore interface {
+	// Acquire locks a todo for holderID during ttl, unless another holder
+	// has an unexpired lock on it; acquiring a lock already held extends it
+	// It returns the lock on the todo afterwards, which is the other
+	// holder's when the lock was not acquired
+	Acquire(ctx context.Context, todoID domain.TodoID, holderID string, ttl time.Duration) (EditLock, error)
+
+	// Release removes the lock of holderID on a todo, reporting whether it
+	// held one
+	Release(ctx context.Context, todoID domain.TodoID, holderID string) (bool, error)
+
+	// Find returns the unexpired lock on a todo, nil when there is none
+	Find(ctx context.Context, todoID domain.TodoID) (*EditLock, error)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"errors"
 
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
 )
@@ -12,3 +13,33 @@ type EventDispatcher interface {
 	// Dispatch publishes one or more domain events
 	Dispatch(ctx context.Context, events []domain.DomainEvent) error
 }
+
+// EventSubscriber delivers dispatched domain events to live subscribers
+// The channel is closed when ctx is done, or when the subscriber falls too
+// far behind to keep up, so that it can resubscribe
+type EventSubscriber interface {
+	Subscribe(ctx context.Context) <-chan domain.DomainEvent
+}
+
+// ErrEventPositionExpired is returned when events after a position can no
+// longer be replayed, or the position comes from another process
+var ErrEventPositionExpired = errors.New("event position expired")
+
+// PositionedEvent is a dispatched event with its position in the stream
+// Position is an opaque resume token
+type PositionedEvent struct {
+	Event    domain.DomainEvent
+	Position string
+}
+
+// ResumableEventSubscriber is an EventSubscriber able to replay the recent
+// events, so that subscribers can reconnect without missing any
+type ResumableEventSubscriber interface {
+	EventSubscriber
+
+	// SubscribeFrom delivers the events dispatched after position, then the
+	// new ones, like Subscribe; an empty position starts from now
+	// It returns the position the subscription starts after, and
+	// ErrEventPositionExpired when position can no longer be resumed
+	SubscribeFrom(ctx context.Context, position string) (<-chan PositionedEvent, string, error)
+}
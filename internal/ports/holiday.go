@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Holiday is a day of the business calendar on which nobody works
+type Holiday struct {
+	// Day is the date of the holiday, at midnight UTC
+	Day  time.Time
+	Name string
+}
+
+// HolidayStore persists the holidays of the business calendar
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type HolidayStore interface {
+	// Save stores a holiday, replacing the holiday already on its day
+	Save(ctx context.Context, holiday Holiday) error
+
+	// Delete removes the holiday on day, reporting whether there was one
+	Delete(ctx context.Context, day time.Time) (bool, error)
+
+	// List returns every holiday, earliest first
+	List(ctx context.Context) ([]Holiday, error)
+}
@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// HookMapping maps an inbound webhook payload to a new todo
+// Each field is a Go text/template executed against the decoded JSON
+// payload; empty templates leave their todo field unset
+type HookMapping struct {
+	Title       string
+	Description string
+	Priority    string
+	DueDate     string
+}
+
+// InboundHook is a tokenized endpoint creating todos from arbitrary JSON
+type InboundHook struct {
+	ID        string
+	Name      string
+	Mapping   HookMapping
+	CreatedAt time.Time
+}
+
+// InboundHookStore persists inbound webhooks, identified by a hash of their token
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type InboundHookStore interface {
+	// Create stores a new hook reached with the token hashed to tokenHash
+	Create(ctx context.Context, hook InboundHook, tokenHash []byte) error
+
+	// FindByTokenHash returns the hook of a token hash, or nil if there is none
+	FindByTokenHash(ctx context.Context, tokenHash []byte) (*InboundHook, error)
+
+	// List returns every hook, oldest first
+	List(ctx context.Context) ([]InboundHook, error)
+
+	// Delete removes a hook, reporting whether it existed
+	Delete(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// LegalHold keeps a todo from being deleted until it is released
+type LegalHold struct {
+	TodoID   domain.TodoID
+	Reason   string
+	PlacedBy string
+	PlacedAt time.Time
+}
+
+// LegalHoldStore persists the legal holds placed on todos
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type LegalHoldStore interface {
+	// Place stores a hold, replacing the hold already placed on its todo
+	Place(ctx context.Context, hold LegalHold) error
+
+	// Release removes the hold on a todo, reporting whether there was one
+	Release(ctx context.Context, todoID domain.TodoID) (bool, error)
+
+	// Held returns which of ids are on hold
+	Held(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error)
+
+	// List returns every hold, oldest first
+	List(ctx context.Context) ([]LegalHold, error)
+}
@@ -0,0 +1,82 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// Milestone is a named target todos are attached to
+type Milestone struct {
+	ID   string
+	Name string
+	// TargetDate is the day the milestone is aimed at, at midnight UTC
+	TargetDate time.Time
+	// OwnerID is the user who created the milestone, empty if unowned
+	OwnerID   string
+	CreatedAt time.Time
+	// CompletedAt is when every todo of the milestone was done, nil while
+	// some are open
+	CompletedAt *time.Time
+	// ArchivedAt is when the milestone was archived, freezing its todos
+	// read-only, nil while active
+	ArchivedAt *time.Time
+}
+
+// MilestoneTodo is a todo attached to a milestone, with its current status
+type MilestoneTodo struct {
+	TodoID domain.TodoID
+	Status domain.TaskStatus
+	// EstimateMinutes weights the todo in the progress of the milestone,
+	// zero when not estimated
+	EstimateMinutes int
+}
+
+// MilestoneStore persists milestones and the todos attached to them
+// Find, List, Delete and SetArchived are scoped to the owner of ctx, if any
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type MilestoneStore interface {
+	// Create stores a new milestone
+	Create(ctx context.Context, milestone Milestone) error
+
+	// Find returns the milestone id, nil if there is none
+	Find(ctx context.Context, id string) (*Milestone, error)
+
+	// List returns the milestones, earliest target first
+	List(ctx context.Context) ([]Milestone, error)
+
+	// Delete removes a milestone, detaching its todos, and reports whether
+	// there was one
+	Delete(ctx context.Context, id string) (bool, error)
+
+	// Attach attaches a todo to a milestone, moving it from the milestone it
+	// was attached to, whose ID is returned, empty if none
+	Attach(ctx context.Context, milestoneID string, todo MilestoneTodo) (string, error)
+
+	// Detach detaches a todo from a milestone, reporting whether it was
+	// attached to it
+	Detach(ctx context.Context, milestoneID string, todoID domain.TodoID) (bool, error)
+
+	// Todos returns the todos attached to a milestone, oldest attachment first
+	Todos(ctx context.Context, milestoneID string) ([]MilestoneTodo, error)
+
+	// Containing returns the IDs of the milestones todos in ids are
+	// attached to
+	Containing(ctx context.Context, ids []domain.TodoID) ([]string, error)
+
+	// SetCompleted sets when a milestone was completed, nil to reopen it,
+	// and reports whether this changed it: completing a completed milestone
+	// or reopening an open one does nothing
+	SetCompleted(ctx context.Context, id string, at *time.Time) (bool, error)
+
+	// SetArchived sets when a milestone was archived, nil to unarchive it,
+	// and reports whether there was one
+	SetArchived(ctx context.Context, id string, at *time.Time) (bool, error)
+
+	// Frozen returns which of ids are attached to an archived milestone
+	Frozen(ctx context.Context, ids []domain.TodoID) (map[domain.TodoID]bool, error)
+
+	// FrozenTodos returns every todo attached to an archived milestone
+	FrozenTodos(ctx context.Context) ([]domain.TodoID, error)
+}
@@ -0,0 +1,67 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Operation statuses
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// Operation is a long-running operation, such as an import, run in the
+// background of the request that started it
+// Done of Total items are processed, Total being zero while unknown. Result
+// is the JSON outcome of a finished operation, Error why it failed
+type Operation struct {
+	ID              string
+	Kind            string
+	OwnerID         string
+	Status          string
+	Done            int
+	Total           int
+	Result          []byte
+	Error           string
+	CancelRequested bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FinishedAt      *time.Time
+}
+
+// OperationQuery selects a page of the operations of an owner, newest
+// first, those started before the operation Before when set
+type OperationQuery struct {
+	OwnerID string
+	Before  string
+	Limit   int
+}
+
+// OperationStore persists the long-running operations, so that any instance
+// can report on and cancel them
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type OperationStore interface {
+	// Create stores a new operation
+	Create(ctx context.Context, operation *Operation) error
+
+	// Update saves the progress, status, result and error of an operation
+	// and returns whether its cancellation was requested meanwhile
+	Update(ctx context.Context, operation *Operation) (bool, error)
+
+	// Get returns an operation, or nil if there is none with this ID
+	Get(ctx context.Context, id string) (*Operation, error)
+
+	// List returns a page of the operations of an owner, without results
+	List(ctx context.Context, query OperationQuery) ([]*Operation, error)
+
+	// RequestCancel flags a running operation for cancellation, reporting
+	// whether it was still running
+	RequestCancel(ctx context.Context, id string) (bool, error)
+
+	// DeleteFinishedBefore removes the operations finished before a time and
+	// returns how many there were
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,134 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// OutboxDrift lists the todos whose events disagree with the todos table
+type OutboxDrift struct {
+	// Unrecorded todos exist but have no TodoCreated event
+	Unrecorded []domain.TodoID
+	// Orphaned todos have events but no longer exist, without a
+	// TodoDeleted event
+	Orphaned []domain.TodoID
+}
+
+// OutboxReconciler finds and repairs drift between the outbox and the todos
+// This is a secondary port (driven), implemented by stores holding an outbox
+type OutboxReconciler interface {
+	// FindDrift returns at most limit unrecorded and limit orphaned todos
+	FindDrift(ctx context.Context, limit int) (OutboxDrift, error)
+
+	// RepairDrift records the missing TodoCreated and TodoDeleted events of
+	// drift, skipping the todos repaired since FindDrift, and returns how
+	// many events were recorded
+	RepairDrift(ctx context.Context, drift OutboxDrift) (int, error)
+}
+
+// Transactor runs functions within a database transaction
+// This is a secondary port (driven): the stores on the same database join
+// the transaction of their context, committed or rolled back with it
+type Transactor interface {
+	// InTransaction runs fn with a context carrying a new transaction,
+	// committed when fn succeeds and rolled back otherwise; a context
+	// already carrying one joins it
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventOutbox holds the domain events until they are published to the
+// broker: a write records its events within its own transaction, so that
+// they are published if and only if it is committed
+// This is a secondary port (driven), implemented by stores holding an outbox
+type EventOutbox interface {
+	// Record appends events to the outbox, within the transaction of ctx
+	// if any
+	Record(ctx context.Context, events []domain.DomainEvent) error
+
+	// Relay passes at most limit unpublished events, oldest first, to
+	// publish and marks them published once it succeeds, returning how many
+	// were published; the events another relay is publishing are skipped
+	Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []domain.DomainEvent) error) (int, error)
+
+	// Unpublished returns the number of events waiting in the outbox
+	Unpublished(ctx context.Context) (int, error)
+}
+
+// RecordedEvent is a domain event read back from the outbox
+// Its data are the fields of the event, as recorded
+type RecordedEvent struct {
+	Type      string
+	Aggregate string
+	At        time.Time
+	Data      json.RawMessage
+}
+
+// EventType returns the type of the recorded event
+func (e RecordedEvent) EventType() string {
+	return e.Type
+}
+
+// AggregateID returns the ID of the aggregate that emitted the event
+func (e RecordedEvent) AggregateID() string {
+	return e.Aggregate
+}
+
+// OccurredAt returns when the recorded event occurred
+func (e RecordedEvent) OccurredAt() time.Time {
+	return e.At
+}
+
+// MarshalJSON returns the recorded fields of the event
+func (e RecordedEvent) MarshalJSON() ([]byte, error) {
+	if len(e.Data) == 0 {
+		return []byte("{}"), nil
+	}
+	return e.Data, nil
+}
+
+// recordedEventsKey is the context key of the events recorded in the outbox
+// with the write of the context
+type recordedEventsKey struct{}
+
+// eventKey identifies a domain event
+type eventKey struct {
+	eventType   string
+	aggregateID string
+	occurredAt  int64
+}
+
+// keyOf returns the key of event
+func keyOf(event domain.DomainEvent) eventKey {
+	return eventKey{event.EventType(), event.AggregateID(), event.OccurredAt().UnixNano()}
+}
+
+// ContextWithRecordedEvents returns a copy of ctx dispatching events already
+// recorded in the outbox with their write, which are not recorded again
+func ContextWithRecordedEvents(ctx context.Context, events []domain.DomainEvent) context.Context {
+	recorded := make(map[eventKey]bool, len(events))
+	for _, event := range events {
+		recorded[keyOf(event)] = true
+	}
+	return context.WithValue(ctx, recordedEventsKey{}, recorded)
+}
+
+// UnrecordedEvents returns the events of events not recorded in the outbox
+// with the write of ctx (see ContextWithRecordedEvents), such as the ones
+// the dispatchers raise in turn
+func UnrecordedEvents(ctx context.Context, events []domain.DomainEvent) []domain.DomainEvent {
+	recorded, _ := ctx.Value(recordedEventsKey{}).(map[eventKey]bool)
+	if len(recorded) == 0 {
+		return events
+	}
+
+	var unrecorded []domain.DomainEvent
+	for _, event := range events {
+		if !recorded[keyOf(event)] {
+			unrecorded = append(unrecorded, event)
+		}
+	}
+	return unrecorded
+}
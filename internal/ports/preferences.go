@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// ClientProfile holds the ListTodos defaults stored for a client
+// Nil fields have no default
+type ClientProfile struct {
+	// PageSize is the limit applied when a request has none
+	PageSize *int
+	// Status is the status filter applied when a request has none
+	Status *domain.TaskStatus
+	// Priority is the priority filter applied when a request has none
+	Priority *domain.Priority
+	// SortBy and SortOrder are the ordering applied when a request has
+	// none; both are set or both are nil
+	SortBy    *SortField
+	SortOrder *SortOrder
+	// UpdatedAt is when the profile was last saved
+	UpdatedAt time.Time
+}
+
+// ClientProfileStore persists client profiles keyed by client ID
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type ClientProfileStore interface {
+	// Get returns the profile of clientID, or nil if it has none
+	Get(ctx context.Context, clientID string) (*ClientProfile, error)
+
+	// Save creates or replaces the profile of clientID
+	Save(ctx context.Context, clientID string, profile ClientProfile) error
+}
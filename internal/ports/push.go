@@ -0,0 +1,69 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// PushProvider is the service delivering the push notifications of a device
+type PushProvider string
+
+const (
+	// PushProviderFCM is Firebase Cloud Messaging, for Android and web apps
+	PushProviderFCM PushProvider = "fcm"
+	// PushProviderAPNs is the Apple Push Notification service
+	PushProviderAPNs PushProvider = "apns"
+)
+
+// ErrDeviceUnregistered is returned by a PushSender when the provider no
+// longer knows the token of a device, which must then be forgotten
+var ErrDeviceUnregistered = errors.New("device token is no longer registered")
+
+// Device is a mobile app install push notifications are sent to
+// Token is the registration token issued to the app by Provider
+type Device struct {
+	Token        string
+	UserID       string
+	Provider     PushProvider
+	RegisteredAt time.Time
+	// LastSeenAt is the last time the app registered the token again
+	LastSeenAt time.Time
+}
+
+// DeviceStore persists the devices of the users, identified by their token
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type DeviceStore interface {
+	// Save stores a device, or refreshes the user, provider and LastSeenAt
+	// of the device already holding its token
+	Save(ctx context.Context, device Device) error
+
+	// ListByUser returns the devices of a user, most recently seen first
+	ListByUser(ctx context.Context, userID string) ([]Device, error)
+
+	// Delete removes the device of a user holding token, reporting whether
+	// it existed
+	Delete(ctx context.Context, userID, token string) (bool, error)
+
+	// DeleteSeenBefore removes the devices last seen before a time and
+	// returns how many there were
+	DeleteSeenBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// PushMessage is a notification shown on a device
+// Data is passed to the app; notifications sharing a CollapseKey replace
+// each other on the device
+type PushMessage struct {
+	Title       string
+	Body        string
+	Data        map[string]string
+	CollapseKey string
+}
+
+// PushSender sends push notifications through the provider of a device
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type PushSender interface {
+	// Send shows message on device, returning ErrDeviceUnregistered when
+	// the provider rejects its token for good
+	Send(ctx context.Context, device Device, message PushMessage) error
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
 )
@@ -19,6 +20,10 @@ type TodoRepository interface {
 	// FindAll retrieves todos matching the given filters
 	FindAll(ctx context.Context, filters Filters) ([]*domain.Todo, error)
 
+	// Count returns the number of todos matching the given filters,
+	// ignoring Limit and Offset
+	Count(ctx context.Context, filters Filters) (int, error)
+
 	// Update updates an existing todo
 	Update(ctx context.Context, todo *domain.Todo) error
 
@@ -26,10 +31,178 @@ type TodoRepository interface {
 	Delete(ctx context.Context, id domain.TodoID) error
 }
 
+// ShortCodeResolver resolves human-friendly short codes (e.g. TD-1042) to todo IDs
+// This is a secondary port (driven), implemented by repositories that allocate short codes
+type ShortCodeResolver interface {
+	// FindIDByShortCode returns the ID of the todo with the given code
+	// Returns ErrTodoNotFound if not found
+	FindIDByShortCode(ctx context.Context, code domain.ShortCode) (domain.TodoID, error)
+}
+
+// TodoSuggester finds todos for as-you-type autocomplete
+// This is a secondary port (driven), implemented by repositories with a suitable index
+type TodoSuggester interface {
+	// Suggest returns at most limit todos whose title contains query,
+	// titles starting with query first
+	Suggest(ctx context.Context, query string, limit int) ([]*domain.Todo, error)
+}
+
+// TodoSearcher finds todos by keywords
+// This is a secondary port (driven), implemented by repositories with a full-text index
+type TodoSearcher interface {
+	// Search returns at most limit todos matching every keyword of query in
+	// their title or description, skipping the first offset, best match first
+	Search(ctx context.Context, query string, limit, offset int) ([]*domain.Todo, error)
+}
+
+// CanaryFinder lists the decoy todos hidden from listings
+// This is a secondary port (driven), implemented by repositories that store canaries
+type CanaryFinder interface {
+	// FindCanaries returns every canary todo, newest first
+	FindCanaries(ctx context.Context) ([]*domain.Todo, error)
+}
+
+// TodoHistory reads past versions of todos
+// This is a secondary port (driven), implemented by repositories that keep history
+type TodoHistory interface {
+	// FindAsOf returns the todo as it was at the given time
+	// Returns ErrTodoNotFound if it did not exist or was deleted at that time
+	FindAsOf(ctx context.Context, id domain.TodoID, at time.Time) (*domain.Todo, error)
+}
+
+// TodoVersionFinder reads the versions of todos, as saved by each change
+// This is a secondary port (driven), implemented by repositories that keep history
+type TodoVersionFinder interface {
+	// FindVersion returns the todo as saved with the given UpdatedAt
+	// Returns ErrTodoNotFound if no such version was recorded
+	FindVersion(ctx context.Context, id domain.TodoID, updatedAt time.Time) (*domain.Todo, error)
+}
+
+// TodoMerger persists duplicate merges
+// This is a secondary port (driven), implemented by repositories that store merge references
+type TodoMerger interface {
+	// SaveMerge persists the canonical todo and the merged duplicate atomically
+	SaveMerge(ctx context.Context, canonical, duplicate *domain.Todo) error
+}
+
+// TodoMover persists todos moved to another owner
+// This is a secondary port (driven), implemented by repositories that store owners
+type TodoMover interface {
+	// SaveMove persists a todo moved to another owner, with the history of
+	// its earlier versions, atomically
+	// Returns ErrTodoNotFound if the todo no longer exists
+	SaveMove(ctx context.Context, todo *domain.Todo) error
+}
+
+// TodoBatchUpdater persists changes to many todos at once
+// This is a secondary port (driven), implemented by repositories with transactions
+type TodoBatchUpdater interface {
+	// UpdateMany updates todos in a single transaction, all or none
+	// Returns ErrTodoNotFound, updating none, if one of them no longer exists
+	UpdateMany(ctx context.Context, todos []*domain.Todo) error
+}
+
+// TodoBatchSaver persists many new todos at once
+// This is a secondary port (driven), implemented by repositories with transactions
+type TodoBatchSaver interface {
+	// SaveMany saves new todos in a single transaction, all or none
+	SaveMany(ctx context.Context, todos []*domain.Todo) error
+}
+
+// TodoBatchDeleter deletes many todos at once
+// This is a secondary port (driven), implemented by repositories that support bulk deletion
+type TodoBatchDeleter interface {
+	// DeleteMany deletes the todos with the given IDs in a single statement,
+	// all or none, and returns how many existed
+	DeleteMany(ctx context.Context, ids []domain.TodoID) (int, error)
+}
+
+// CompletionFilter selects the open todos to complete in bulk
+// Nil fields match any open todo
+type CompletionFilter struct {
+	Status    *domain.TaskStatus
+	Priority  *domain.Priority
+	DueBefore *time.Time
+	// Exclude lists todos to leave open whatever they match
+	Exclude []domain.TodoID
+}
+
+// TodoBulkCompleter completes many todos at once
+// This is a secondary port (driven), implemented by repositories that support bulk updates
+type TodoBulkCompleter interface {
+	// CompleteMatching completes, in a single statement, at most limit
+	// pending or in progress, unarchived todos matching filter, oldest
+	// first, and returns them as completed at the given time
+	CompleteMatching(ctx context.Context, filter CompletionFilter, at time.Time, limit int) ([]*domain.Todo, error)
+}
+
+// PurgeFilter selects the todos to hard-delete in bulk
+// Nil fields match any todo
+type PurgeFilter struct {
+	Status        *domain.TaskStatus
+	Priority      *domain.Priority
+	UpdatedBefore *time.Time
+}
+
+// TodoPurger hard-deletes todos in bulk
+// This is a secondary port (driven), implemented by repositories that support bulk deletion
+type TodoPurger interface {
+	// FindPurgeable returns at most limit todos matching filter, least
+	// recently updated first
+	FindPurgeable(ctx context.Context, filter PurgeFilter, limit int) ([]*domain.Todo, error)
+
+	// DeleteMany deletes the todos with the given IDs and returns how many
+	// existed
+	DeleteMany(ctx context.Context, ids []domain.TodoID) (int, error)
+}
+
+// DueTodoFinder finds open todos by due date, for reminders
+// This is a secondary port (driven), implemented by repositories
+type DueTodoFinder interface {
+	// FindDueBetween returns at most limit todos neither completed nor
+	// cancelled whose due date is after from and at or before to, earliest
+	// due first
+	FindDueBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error)
+}
+
+// DatedTodoFinder finds the todos of the owner of ctx by due date, for
+// calendar feeds
+// This is a secondary port (driven), implemented by repositories
+type DatedTodoFinder interface {
+	// FindDatedBetween returns at most limit unarchived todos, whatever
+	// their status, whose due date is at or after from and before to,
+	// earliest due first
+	FindDatedBetween(ctx context.Context, from, to time.Time, limit int) ([]*domain.Todo, error)
+}
+
 // Filters represents query filters for finding todos
+// A zero SortBy orders todos newest first, and a nil Archived matches
+// archived and unarchived todos
 type Filters struct {
-	Status   *domain.TaskStatus
-	Priority *domain.Priority
-	Limit    *int
-	Offset   *int
+	Status    *domain.TaskStatus
+	Priority  *domain.Priority
+	Archived  *bool
+	Limit     *int
+	Offset    *int
+	SortBy    SortField
+	SortOrder SortOrder
 }
+
+// SortField is a todo attribute listings can be ordered by
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByDueDate   SortField = "due_date"
+	SortByPriority  SortField = "priority"
+	SortByTitle     SortField = "title"
+)
+
+// SortOrder is the direction of a sort
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Categories of the security records
+const (
+	// SecurityCategoryAudit is a privileged action of the audit log
+	SecurityCategoryAudit = "audit"
+	// SecurityCategoryAnomaly is a security anomaly raised by the detector
+	SecurityCategoryAnomaly = "anomaly"
+)
+
+// SecurityRecord is an audit entry or a security event exported to a SIEM
+type SecurityRecord struct {
+	// Category is SecurityCategoryAudit or SecurityCategoryAnomaly
+	Category string
+	// Name is the action of an audit entry, or the kind of an anomaly
+	Name string
+	// Severity ranks the record from 0, the lowest, to 10, as CEF does
+	Severity int
+	// Actor identifies who performed the action, if known
+	Actor string
+	// TodoID is the todo concerned, if any
+	TodoID string
+	// Message is the reason of an audit entry, or the detail of an anomaly
+	Message string
+	// Changes maps each field modified by an audited action to its new value
+	Changes map[string]string
+	// OccurredAt is when the action was taken or the anomaly detected
+	OccurredAt time.Time
+}
+
+// SecurityExporter sends security records to a SIEM
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type SecurityExporter interface {
+	// Export sends records, oldest first; it fails when some of them may not
+	// have been received, so that they are sent again
+	Export(ctx context.Context, records []SecurityRecord) error
+}
@@ -0,0 +1,69 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxBacklog describes the domain events waiting to be published
+type OutboxBacklog struct {
+	Pending int
+	// OldestPendingAt is when the oldest pending event occurred, nil when
+	// none is pending
+	OldestPendingAt *time.Time
+}
+
+// OutboxMonitor reports the backlog of the transactional outbox
+// This is a secondary port (driven), implemented by stores holding an outbox
+type OutboxMonitor interface {
+	OutboxBacklog(ctx context.Context) (OutboxBacklog, error)
+}
+
+// PoolStats describes the connections of a database pool
+type PoolStats struct {
+	MaxConns      int32
+	TotalConns    int32
+	AcquiredConns int32
+	IdleConns     int32
+	// Acquires counts the connections acquired from the pool
+	Acquires int64
+	// EmptyAcquires counts the acquisitions that waited for a connection
+	EmptyAcquires int64
+	// CanceledAcquires counts the acquisitions canceled before getting a
+	// connection
+	CanceledAcquires int64
+	AcquireDuration  time.Duration
+}
+
+// PoolMonitor reports the use of a database connection pool
+// This is a secondary port (driven), implemented by database adapters
+type PoolMonitor interface {
+	PoolStats() PoolStats
+}
+
+// StreamCounter reports the live event streams served by this process
+// This is a secondary port (driven), implemented by event subscribers
+type StreamCounter interface {
+	ActiveStreams() int
+}
+
+// EventDeliveryStats describes the delivery of the domain events to the
+// event broker
+type EventDeliveryStats struct {
+	// Degraded is set while the broker is unavailable and the events are
+	// held in the outbox
+	Degraded bool
+	// DegradedSince is when the broker became unavailable, nil when it is
+	// not degraded
+	DegradedSince *time.Time
+	// Buffered events wait in the outbox, as counted by the last relay
+	Buffered int
+	// Relayed counts the events of the outbox published to the broker
+	Relayed int64
+}
+
+// EventDeliveryMonitor reports whether the domain events reach the broker
+// This is a secondary port (driven), implemented by event relays
+type EventDeliveryMonitor interface {
+	EventDeliveryStats() EventDeliveryStats
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// TodoChangeMark tells that a todo changed, as listed for sync clients
+type TodoChangeMark struct {
+	// Seq is the sequence number of the latest change to the todo
+	Seq     int64
+	TodoID  domain.TodoID
+	Deleted bool
+}
+
+// TodoChangeLog lists the todos changed since a sync cursor
+// This is a secondary port (driven), implemented by repositories that keep history
+type TodoChangeLog interface {
+	// ListChangedSince returns at most limit todos whose latest change has a
+	// sequence number above after, each once, in sequence order
+	ListChangedSince(ctx context.Context, after int64, limit int) ([]TodoChangeMark, error)
+}
@@ -0,0 +1,44 @@
+package ports
+
+import "context"
+
+// ownerKey is the context key of the owner repository queries are scoped to
+type ownerKey struct{}
+
+// systemAccessKey is the context key marking operator and background job
+// contexts, whose repository queries are not scoped to an owner
+type systemAccessKey struct{}
+
+// ContextWithOwner returns a copy of ctx scoping repository reads and writes
+// to the todos of ownerID
+// A context without an owner reaches no todo, unless it has system access
+func ContextWithOwner(ctx context.Context, ownerID string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, ownerID)
+}
+
+// OwnerFromContext returns the owner ctx is scoped to, if any
+// Repositories supporting ownership only see the todos of this owner
+func OwnerFromContext(ctx context.Context) (string, bool) {
+	ownerID, ok := ctx.Value(ownerKey{}).(string)
+	return ownerID, ok && ownerID != ""
+}
+
+// ContextWithSystemAccess returns a copy of ctx lifting the owner scope, for
+// operators and background jobs acting on every todo
+// Only the admin API, the jobs and the operator commands set it; an owner
+// set afterwards scopes ctx again
+func ContextWithSystemAccess(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, ownerKey{}, "")
+	return context.WithValue(ctx, systemAccessKey{}, true)
+}
+
+// HasSystemAccess reports whether ctx reaches every todo: it has system
+// access and no owner
+// Repositories reach no todo with a context that has neither
+func HasSystemAccess(ctx context.Context) bool {
+	if _, ok := OwnerFromContext(ctx); ok {
+		return false
+	}
+	system, _ := ctx.Value(systemAccessKey{}).(bool)
+	return system
+}
@@ -0,0 +1,45 @@
+package ports
+
+import "context"
+
+// TodoistProject is a project of a Todoist account
+type TodoistProject struct {
+	ID   string
+	Name string
+}
+
+// TodoistTask is an active task of a Todoist account, as the Todoist API
+// describes it
+type TodoistTask struct {
+	ID          string
+	ProjectID   string
+	Content     string
+	Description string
+	// Priority goes from 1, normal, to 4, urgent; the apps show it
+	// reversed, 4 being p1
+	Priority int
+	Due      *TodoistDue
+}
+
+// TodoistDue is the due date of a task; recurring tasks are due at their
+// next occurrence
+type TodoistDue struct {
+	// Date is the day the task is due, as YYYY-MM-DD
+	Date string
+	// Clock is the time of day the task is due, as HH:MM:SS, empty when it
+	// is due by the end of Date
+	Clock string
+	// Timezone is the IANA zone of Date and Clock, empty when they float in
+	// the zone of the user
+	Timezone string
+}
+
+// TodoistClient reads a Todoist account
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type TodoistClient interface {
+	// Projects returns the projects of the account
+	Projects(ctx context.Context) ([]TodoistProject, error)
+
+	// Tasks returns the active tasks of the account, in every project
+	Tasks(ctx context.Context) ([]TodoistTask, error)
+}
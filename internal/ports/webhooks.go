@@ -0,0 +1,162 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	domain "github.com/pivaldi/mmw/todo/internal/domain/todo"
+)
+
+// WebhookEndpoint is a URL domain events are posted to
+// Requests are signed with Secret; an empty EventTypes receives every event.
+// With a DigestWindow, the events raised on a todo within the window after
+// the first one are posted together as an EventDigest
+type WebhookEndpoint struct {
+	ID           string
+	URL          string
+	EventTypes   []string
+	Secret       string
+	DigestWindow time.Duration
+	CreatedAt    time.Time
+}
+
+// Accepts reports whether the events of eventType are posted to the endpoint
+func (e WebhookEndpoint) Accepts(eventType string) bool {
+	return len(e.EventTypes) == 0 || slices.Contains(e.EventTypes, eventType)
+}
+
+// EventDigestType is the event type of an EventDigest
+const EventDigestType = "TodoEventDigest"
+
+// EventDigest consolidates the events raised on a todo within the digest
+// window of a webhook endpoint, oldest first, into a single delivery
+type EventDigest struct {
+	TodoID string
+	Events []domain.DomainEvent
+}
+
+// EventType returns EventDigestType
+func (d EventDigest) EventType() string {
+	return EventDigestType
+}
+
+// AggregateID returns the ID of the todo the events were raised on
+func (d EventDigest) AggregateID() string {
+	return d.TodoID
+}
+
+// OccurredAt returns when the last event of the digest occurred
+func (d EventDigest) OccurredAt() time.Time {
+	if len(d.Events) == 0 {
+		return time.Time{}
+	}
+	return d.Events[len(d.Events)-1].OccurredAt()
+}
+
+// ReplayedEvent is a past domain event of a todo, rebuilt from its audit
+// entry to backfill a new webhook endpoint
+// Its data are the fields the event set, as recorded, and "replayed": true
+type ReplayedEvent struct {
+	Entry TodoAuditEntry
+}
+
+// EventType returns the type of the recorded event
+func (e ReplayedEvent) EventType() string {
+	return e.Entry.EventType
+}
+
+// AggregateID returns the ID of the todo the event was raised on
+func (e ReplayedEvent) AggregateID() string {
+	return e.Entry.TodoID
+}
+
+// OccurredAt returns when the recorded event occurred
+func (e ReplayedEvent) OccurredAt() time.Time {
+	return e.Entry.OccurredAt
+}
+
+// MarshalJSON returns the recorded fields of the event and "replayed": true
+func (e ReplayedEvent) MarshalJSON() ([]byte, error) {
+	data := make(map[string]any, len(e.Entry.Changes)+1)
+	for field, value := range e.Entry.Changes {
+		data[field] = value
+	}
+	data["replayed"] = true
+	return json.Marshal(data)
+}
+
+// WebhookDelivery is an attempt to post an event to an endpoint
+// StatusCode is 0 when no response was received; Error is empty on success.
+// ResponseSnippet is the start of the response body, and Payload the body
+// posted, nil when the event could not be encoded. RedeliveryOf is the ID of
+// the attempt a manual redelivery repeated, 0 for automatic attempts
+type WebhookDelivery struct {
+	ID              int64
+	EndpointID      string
+	EventID         string
+	EventType       string
+	Attempt         int
+	StatusCode      int
+	Error           string
+	ResponseSnippet string
+	Payload         []byte
+	RedeliveryOf    int64
+	Duration        time.Duration
+	AttemptedAt     time.Time
+}
+
+// WebhookDeliveryQuery selects the delivery attempts of an endpoint
+// Before is the ID of the last attempt of the previous page, 0 for the first
+// page; FailedOnly leaves out the successful attempts
+type WebhookDeliveryQuery struct {
+	EndpointID string
+	FailedOnly bool
+	Before     int64
+	Limit      int
+}
+
+// WebhookStore persists webhook endpoints and their delivery attempts
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type WebhookStore interface {
+	// Create stores a new endpoint
+	Create(ctx context.Context, endpoint WebhookEndpoint) error
+
+	// List returns every endpoint, oldest first
+	List(ctx context.Context) ([]WebhookEndpoint, error)
+
+	// Delete removes an endpoint, reporting whether it existed
+	Delete(ctx context.Context, id string) (bool, error)
+
+	// RecordDelivery logs a delivery attempt and returns its ID
+	RecordDelivery(ctx context.Context, delivery WebhookDelivery) (int64, error)
+
+	// ListDeliveries returns the attempts matching query, most recent first,
+	// without their payload
+	ListDeliveries(ctx context.Context, query WebhookDeliveryQuery) ([]WebhookDelivery, error)
+
+	// FindDelivery returns an attempt with its payload, nil when there is
+	// no attempt with this ID
+	FindDelivery(ctx context.Context, id int64) (*WebhookDelivery, error)
+}
+
+// WebhookReply is the response of an endpoint to a delivery
+// StatusCode is 0 when none was received; Body is the start of the
+// response body, as valid UTF-8 text
+type WebhookReply struct {
+	StatusCode int
+	Body       string
+}
+
+// WebhookSender posts domain events to webhook endpoints
+// This is a secondary port (driven) - needed by the application, implemented by adapters
+type WebhookSender interface {
+	// Encode returns the body posting event
+	Encode(event domain.DomainEvent) ([]byte, error)
+
+	// Send posts body, the encoding of an event of eventType identified by
+	// eventID, to endpoint and returns its reply
+	// A response outside 2xx is an error
+	Send(ctx context.Context, endpoint WebhookEndpoint, eventID, eventType string, body []byte) (WebhookReply, error)
+}
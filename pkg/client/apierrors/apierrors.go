@@ -0,0 +1,249 @@
+// Package apierrors maps the errors of the Todo API, their Connect code and
+// detail payload, back into typed Go errors
+//
+// Errors returned by the Go client are already mapped; errors of a client
+// built otherwise are mapped with FromError. Mapped errors still unwrap to
+// their *connect.Error, so connect.CodeOf keeps working on them:
+//
+//	var validation *apierrors.ValidationError
+//	switch {
+//	case errors.Is(err, apierrors.ErrNotFound):
+//	case errors.As(err, &validation):
+//		fmt.Println(validation.Field, validation.Message)
+//	}
+//
+// RuleOf returns the rule of an error, its ID in the error catalog the
+// server lists at GET /api/errors, for handling finer than the codes
+package apierrors
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Errors of the codes the Todo API answers with
+var (
+	// ErrNotFound is returned when the todo does not exist, or not for the
+	// caller
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument is returned for invalid requests, as a
+	// *ValidationError
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrQuotaExceeded is returned when the caller spent a quota, as a
+	// *QuotaExceededError; retrying later or narrowing the query helps
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrConflict is returned when the todo changed since it was read, as an
+	// *EditConflictError when the server detailed the conflicting fields
+	ErrConflict = errors.New("conflict")
+	// ErrFailedPrecondition is returned when a business rule refuses the
+	// change, e.g. a completed todo or a legal hold
+	ErrFailedPrecondition = errors.New("failed precondition")
+	// ErrPermissionDenied is returned when the caller may not make the call
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrUnauthenticated is returned without valid credentials
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrUnavailable is returned during maintenance or while a dependency of
+	// the server is failing
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// sentinels maps the codes to their error
+var sentinels = map[connect.Code]error{
+	connect.CodeNotFound:           ErrNotFound,
+	connect.CodeInvalidArgument:    ErrInvalidArgument,
+	connect.CodeResourceExhausted:  ErrQuotaExceeded,
+	connect.CodeAborted:            ErrConflict,
+	connect.CodeFailedPrecondition: ErrFailedPrecondition,
+	connect.CodePermissionDenied:   ErrPermissionDenied,
+	connect.CodeUnauthenticated:    ErrUnauthenticated,
+	connect.CodeUnavailable:        ErrUnavailable,
+}
+
+// Error is a failed call mapped to the error of its code
+type Error struct {
+	sentinel error
+	cause    *connect.Error
+}
+
+func (e *Error) Error() string { return e.cause.Error() }
+
+// Is reports whether target is the error of the code
+func (e *Error) Is(target error) bool { return target == e.sentinel }
+
+// Unwrap returns the *connect.Error of the call
+func (e *Error) Unwrap() error { return e.cause }
+
+// ValidationError is an invalid request, naming the invalid field when the
+// server did
+type ValidationError struct {
+	Field   string
+	Message string
+	cause   *connect.Error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.cause.Error()
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Is reports whether target is ErrInvalidArgument
+func (e *ValidationError) Is(target error) bool { return target == ErrInvalidArgument }
+
+// Unwrap returns the *connect.Error of the call
+func (e *ValidationError) Unwrap() error { return e.cause }
+
+// QuotaExceededError is a spent quota: "expensive_queries" when the caller
+// made too many deep or costly queries in the current window,
+// "concurrent_queries" when the owner runs too many at once, empty when the
+// server did not tell
+type QuotaExceededError struct {
+	Quota string
+	cause *connect.Error
+}
+
+func (e *QuotaExceededError) Error() string { return e.cause.Error() }
+
+// Is reports whether target is ErrQuotaExceeded
+func (e *QuotaExceededError) Is(target error) bool { return target == ErrQuotaExceeded }
+
+// Unwrap returns the *connect.Error of the call
+func (e *QuotaExceededError) Unwrap() error { return e.cause }
+
+// ConflictField is a field changed both by the caller and since the
+// version it edited; Base is nil when that version is unknown
+type ConflictField struct {
+	Field     string
+	Base      *string
+	Current   string
+	Requested string
+}
+
+// EditConflictError is an update of a todo changed since the version the
+// caller edited; CurrentETag is the version to retry with, once the
+// conflicting Fields are resolved
+type EditConflictError struct {
+	Strategy    string
+	CurrentETag string
+	Fields      []ConflictField
+	cause       *connect.Error
+}
+
+func (e *EditConflictError) Error() string { return e.cause.Error() }
+
+// Is reports whether target is ErrConflict
+func (e *EditConflictError) Is(target error) bool { return target == ErrConflict }
+
+// Unwrap returns the *connect.Error of the call
+func (e *EditConflictError) Unwrap() error { return e.cause }
+
+// FromError maps the *connect.Error err is or wraps to its typed error
+// Other errors, codes without a typed error and already mapped errors are
+// returned unchanged
+func FromError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || isMapped(err) {
+		return err
+	}
+
+	sentinel, ok := sentinels[connectErr.Code()]
+	if !ok {
+		return err
+	}
+
+	details := structDetails(connectErr)
+	switch connectErr.Code() {
+	case connect.CodeInvalidArgument:
+		field, _ := details["field"].(string)
+		message, _ := details["message"].(string)
+		return &ValidationError{Field: field, Message: message, cause: connectErr}
+	case connect.CodeResourceExhausted:
+		quota, _ := details["quota"].(string)
+		return &QuotaExceededError{Quota: quota, cause: connectErr}
+	case connect.CodeAborted:
+		if _, ok := details["fields"]; ok {
+			return editConflict(details, connectErr)
+		}
+	}
+	return &Error{sentinel: sentinel, cause: connectErr}
+}
+
+// isMapped reports whether err was already mapped by FromError
+func isMapped(err error) bool {
+	var (
+		mapped     *Error
+		validation *ValidationError
+		quota      *QuotaExceededError
+		conflict   *EditConflictError
+	)
+	return errors.As(err, &mapped) || errors.As(err, &validation) ||
+		errors.As(err, &quota) || errors.As(err, &conflict)
+}
+
+// structDetails returns the fields of the first google.protobuf.Struct
+// detail of connectErr, nil without one
+func structDetails(connectErr *connect.Error) map[string]any {
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if details, ok := value.(*structpb.Struct); ok {
+			return details.AsMap()
+		}
+	}
+	return nil
+}
+
+// editConflict builds the EditConflictError of the conflict details
+func editConflict(details map[string]any, connectErr *connect.Error) *EditConflictError {
+	conflict := &EditConflictError{cause: connectErr}
+	conflict.Strategy, _ = details["strategy"].(string)
+	conflict.CurrentETag, _ = details["current_etag"].(string)
+
+	fields, _ := details["fields"].([]any)
+	for _, field := range fields {
+		values, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		var conflictField ConflictField
+		conflictField.Field, _ = values["field"].(string)
+		conflictField.Current, _ = values["current"].(string)
+		conflictField.Requested, _ = values["requested"].(string)
+		if base, ok := values["base"].(string); ok {
+			conflictField.Base = &base
+		}
+		conflict.Fields = append(conflict.Fields, conflictField)
+	}
+	return conflict
+}
+
+// RuleOf returns the ID of the catalog rule of the *connect.Error err is or
+// wraps, e.g. "todo.not_found"; empty when the server did not tell
+func RuleOf(err error) string {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return ""
+	}
+	rule, _ := structDetails(connectErr)["rule"].(string)
+	return rule
+}
+
+// NewInterceptor returns a client interceptor mapping the errors of unary
+// calls with FromError
+func NewInterceptor() connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, FromError(err)
+			}
+			return resp, nil
+		}
+	})
+}
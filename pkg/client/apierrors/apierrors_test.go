@@ -0,0 +1,168 @@
+package apierrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// detailedError returns a connect error of code detailed with fields, as the
+// server sends them
+func detailedError(t *testing.T, code connect.Code, fields map[string]any) *connect.Error {
+	t.Helper()
+	connectErr := connect.NewError(code, errors.New("boom"))
+	if fields == nil {
+		return connectErr
+	}
+	details, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatalf("NewStruct() failed: %v", err)
+	}
+	detail, err := connect.NewErrorDetail(details)
+	if err != nil {
+		t.Fatalf("NewErrorDetail() failed: %v", err)
+	}
+	connectErr.AddDetail(detail)
+	return connectErr
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     connect.Code
+		details  map[string]any
+		sentinel error
+		check    func(t *testing.T, err error)
+	}{
+		{name: "not found", code: connect.CodeNotFound, sentinel: ErrNotFound},
+		{name: "unavailable", code: connect.CodeUnavailable, sentinel: ErrUnavailable},
+		{name: "permission denied", code: connect.CodePermissionDenied, sentinel: ErrPermissionDenied},
+		{
+			name:     "validation with field",
+			code:     connect.CodeInvalidArgument,
+			details:  map[string]any{"field": "title", "message": "is required"},
+			sentinel: ErrInvalidArgument,
+			check: func(t *testing.T, err error) {
+				var validation *ValidationError
+				if !errors.As(err, &validation) || validation.Field != "title" || validation.Message != "is required" {
+					t.Errorf("FromError() = %#v, want a ValidationError of title", err)
+				}
+				if err.Error() != "title: is required" {
+					t.Errorf("Error() = %q, want %q", err.Error(), "title: is required")
+				}
+			},
+		},
+		{
+			name:     "validation without detail",
+			code:     connect.CodeInvalidArgument,
+			sentinel: ErrInvalidArgument,
+			check: func(t *testing.T, err error) {
+				var validation *ValidationError
+				if !errors.As(err, &validation) || validation.Field != "" {
+					t.Errorf("FromError() = %#v, want a ValidationError without field", err)
+				}
+			},
+		},
+		{
+			name:     "quota",
+			code:     connect.CodeResourceExhausted,
+			details:  map[string]any{"quota": "expensive_queries"},
+			sentinel: ErrQuotaExceeded,
+			check: func(t *testing.T, err error) {
+				var quota *QuotaExceededError
+				if !errors.As(err, &quota) || quota.Quota != "expensive_queries" {
+					t.Errorf("FromError() = %#v, want a QuotaExceededError of expensive_queries", err)
+				}
+			},
+		},
+		{
+			name: "edit conflict",
+			code: connect.CodeAborted,
+			details: map[string]any{
+				"strategy":     "merge",
+				"current_etag": `"abc"`,
+				"fields": []any{
+					map[string]any{"field": "title", "base": nil, "current": "theirs", "requested": "mine"},
+				},
+			},
+			sentinel: ErrConflict,
+			check: func(t *testing.T, err error) {
+				var conflict *EditConflictError
+				if !errors.As(err, &conflict) {
+					t.Fatalf("FromError() = %#v, want an EditConflictError", err)
+				}
+				if conflict.CurrentETag != `"abc"` || len(conflict.Fields) != 1 ||
+					conflict.Fields[0].Current != "theirs" || conflict.Fields[0].Base != nil {
+					t.Errorf("EditConflictError = %+v, want the detailed conflict", conflict)
+				}
+			},
+		},
+		{name: "concurrent modification", code: connect.CodeAborted, sentinel: ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromError(fmt.Errorf("calling: %w", detailedError(t, tt.code, tt.details)))
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("FromError() = %v, want %v", err, tt.sentinel)
+			}
+			if connect.CodeOf(err) != tt.code {
+				t.Errorf("CodeOf() = %v, want %v", connect.CodeOf(err), tt.code)
+			}
+			if FromError(err) != err {
+				t.Error("FromError() of a mapped error changed it")
+			}
+			if tt.check != nil {
+				tt.check(t, err)
+			}
+		})
+	}
+}
+
+func TestFromError_Unmapped(t *testing.T) {
+	plain := errors.New("boom")
+	internal := connect.NewError(connect.CodeInternal, plain)
+	for _, err := range []error{nil, plain, internal} {
+		if got := FromError(err); got != err {
+			t.Errorf("FromError(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestRuleOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "detailed", err: detailedError(t, connect.CodeFailedPrecondition, map[string]any{"rule": "todo.completed"}), want: "todo.completed"},
+		{name: "mapped", err: FromError(detailedError(t, connect.CodeNotFound, map[string]any{"rule": "todo.not_found"})), want: "todo.not_found"},
+		{name: "undetailed", err: detailedError(t, connect.CodeInternal, nil), want: ""},
+		{name: "not a connect error", err: errors.New("boom"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuleOf(fmt.Errorf("calling: %w", tt.err)); got != tt.want {
+				t.Errorf("RuleOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	unary := NewInterceptor().WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("todo not found"))
+	})
+
+	_, err := unary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	var mapped *Error
+	if !errors.As(err, &mapped) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("call error = %#v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+	"github.com/pivaldi/mmw/todo/internal/pkg/compression"
+	"github.com/pivaldi/mmw/todo/internal/pkg/lru"
+	"github.com/pivaldi/mmw/todo/pkg/client/apierrors"
+)
+
+// DefaultCacheSize is the number of todos kept in the client cache by default
+const DefaultCacheSize = 256
+
+// Client is a Go client for the Todo API
+// It wraps the generated Connect client and caches GetTodo results by ID,
+// revalidating them with conditional requests (ETag / If-None-Match).
+// Idempotent calls are retried according to the configured RetryPolicy, and
+// failed calls return the typed errors of package apierrors.
+// Responses may be compressed with zstd or gzip; requests are sent
+// uncompressed unless connect.WithSendCompression is passed
+type Client struct {
+	todov1connect.TodoServiceClient
+	cache *lru.Cache[string, cachedTodo]
+}
+
+// cachedTodo is a todo along with the entity tag it was served with
+type cachedTodo struct {
+	todo *todov1.Todo
+	etag string
+}
+
+// Option configures a Client
+type Option func(*options)
+
+type options struct {
+	cacheSize     int
+	retryPolicy   RetryPolicy
+	clientOptions []connect.ClientOption
+}
+
+// WithCacheSize sets the maximum number of cached todos
+// A size of zero disables caching
+func WithCacheSize(size int) Option {
+	return func(o *options) {
+		o.cacheSize = size
+	}
+}
+
+// WithClientOptions passes options to the underlying Connect client
+func WithClientOptions(opts ...connect.ClientOption) Option {
+	return func(o *options) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// New creates a new Client for the Todo API served at baseURL
+func New(httpClient connect.HTTPClient, baseURL string, opts ...Option) *Client {
+	o := options{
+		cacheSize:   DefaultCacheSize,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	clientOptions := append([]connect.ClientOption{compression.ZstdClientOption()}, o.clientOptions...)
+	if o.retryPolicy.MaxAttempts > 1 {
+		// Retries wrap every other interceptor so each attempt runs them all
+		clientOptions = append(
+			[]connect.ClientOption{connect.WithInterceptors(NewRetryInterceptor(o.retryPolicy))},
+			clientOptions...,
+		)
+	}
+	// Errors are typed once the last attempt failed
+	clientOptions = append(
+		[]connect.ClientOption{connect.WithInterceptors(apierrors.NewInterceptor())},
+		clientOptions...,
+	)
+
+	return newClient(todov1connect.NewTodoServiceClient(httpClient, baseURL, clientOptions...), o)
+}
+
+// newClient wraps an existing TodoServiceClient
+func newClient(inner todov1connect.TodoServiceClient, o options) *Client {
+	c := &Client{TodoServiceClient: inner}
+	if o.cacheSize > 0 {
+		c.cache = lru.New[string, cachedTodo](o.cacheSize)
+	}
+	return c
+}
+
+// GetTodo retrieves a todo by ID
+// Cached todos are revalidated with If-None-Match, so an unchanged todo costs
+// a round-trip without a body. Returned messages may be shared with the cache
+// and must not be modified
+func (c *Client) GetTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.GetTodoRequest],
+) (*connect.Response[todov1.GetTodoResponse], error) {
+	if c.cache == nil {
+		return c.TodoServiceClient.GetTodo(ctx, req)
+	}
+
+	id := req.Msg.Id
+	cached, hit := c.cache.Get(id)
+	if hit {
+		// The validator goes on a copy, so a reused request does not send a
+		// stale one
+		conditional := connect.NewRequest(req.Msg)
+		copyHeader(conditional.Header(), req.Header())
+		conditional.Header().Set("If-None-Match", cached.etag)
+		req = conditional
+	}
+
+	resp, err := c.TodoServiceClient.GetTodo(ctx, req)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			c.cache.Remove(id)
+		}
+		return nil, err
+	}
+
+	etag := resp.Header().Get("ETag")
+
+	// Not modified: serve the cached todo
+	if hit && resp.Msg.Todo == nil && etag == cached.etag {
+		notModified := connect.NewResponse(&todov1.GetTodoResponse{Todo: cached.todo})
+		copyHeader(notModified.Header(), resp.Header())
+		return notModified, nil
+	}
+
+	if etag != "" && resp.Msg.Todo != nil {
+		c.cache.Add(id, cachedTodo{todo: resp.Msg.Todo, etag: etag})
+	} else {
+		c.cache.Remove(id)
+	}
+
+	return resp, nil
+}
+
+// UpdateTodo updates a todo and evicts it from the cache
+func (c *Client) UpdateTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.UpdateTodoRequest],
+) (*connect.Response[todov1.UpdateTodoResponse], error) {
+	defer c.evict(req.Msg.Id)
+	return c.TodoServiceClient.UpdateTodo(ctx, req)
+}
+
+// CompleteTodo completes a todo and evicts it from the cache
+func (c *Client) CompleteTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.CompleteTodoRequest],
+) (*connect.Response[todov1.CompleteTodoResponse], error) {
+	defer c.evict(req.Msg.Id)
+	return c.TodoServiceClient.CompleteTodo(ctx, req)
+}
+
+// ReopenTodo reopens a todo and evicts it from the cache
+func (c *Client) ReopenTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.ReopenTodoRequest],
+) (*connect.Response[todov1.ReopenTodoResponse], error) {
+	defer c.evict(req.Msg.Id)
+	return c.TodoServiceClient.ReopenTodo(ctx, req)
+}
+
+// DeleteTodo deletes a todo and evicts it from the cache
+func (c *Client) DeleteTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.DeleteTodoRequest],
+) (*connect.Response[todov1.DeleteTodoResponse], error) {
+	defer c.evict(req.Msg.Id)
+	return c.TodoServiceClient.DeleteTodo(ctx, req)
+}
+
+// evict removes a todo from the cache, if caching is enabled
+func (c *Client) evict(id string) {
+	if c.cache != nil {
+		c.cache.Remove(id)
+	}
+}
+
+// copyHeader copies all header values from src to dst
+func copyHeader(dst, src http.Header) {
+	for key, values := range src {
+		for _, value := range values {
+			dst.Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+// fakeTodoServiceClient serves a single todo and honors If-None-Match
+type fakeTodoServiceClient struct {
+	todov1connect.TodoServiceClient
+	todo          *todov1.Todo
+	etag          string
+	getCalls      int
+	conditionalOK int
+}
+
+func (f *fakeTodoServiceClient) GetTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.GetTodoRequest],
+) (*connect.Response[todov1.GetTodoResponse], error) {
+	f.getCalls++
+
+	if req.Header().Get("If-None-Match") == f.etag {
+		f.conditionalOK++
+		resp := connect.NewResponse(&todov1.GetTodoResponse{})
+		resp.Header().Set("ETag", f.etag)
+		return resp, nil
+	}
+
+	resp := connect.NewResponse(&todov1.GetTodoResponse{Todo: f.todo})
+	resp.Header().Set("ETag", f.etag)
+	return resp, nil
+}
+
+func (f *fakeTodoServiceClient) DeleteTodo(
+	ctx context.Context,
+	req *connect.Request[todov1.DeleteTodoRequest],
+) (*connect.Response[todov1.DeleteTodoResponse], error) {
+	return connect.NewResponse(&todov1.DeleteTodoResponse{}), nil
+}
+
+func TestClient_GetTodo_RevalidatesCachedTodo(t *testing.T) {
+	fake := &fakeTodoServiceClient{
+		todo: &todov1.Todo{Id: "123", Title: "Cached"},
+		etag: `"123-1"`,
+	}
+	client := newClient(fake, options{cacheSize: 8})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+		if err != nil {
+			t.Fatalf("GetTodo() unexpected error: %v", err)
+		}
+		if resp.Msg.Todo == nil || resp.Msg.Todo.Title != "Cached" {
+			t.Fatalf("GetTodo() returned %+v, want cached todo", resp.Msg.Todo)
+		}
+	}
+
+	if fake.getCalls != 2 {
+		t.Errorf("GetTodo calls = %d, want 2", fake.getCalls)
+	}
+
+	if fake.conditionalOK != 1 {
+		t.Errorf("Not modified responses = %d, want 1", fake.conditionalOK)
+	}
+}
+
+func TestClient_GetTodo_LeavesRequestUnchanged(t *testing.T) {
+	fake := &fakeTodoServiceClient{
+		todo: &todov1.Todo{Id: "123"},
+		etag: `"123-1"`,
+	}
+	client := newClient(fake, options{cacheSize: 8})
+	req := connect.NewRequest(&todov1.GetTodoRequest{Id: "123"})
+	req.Header().Set("X-Request-Id", "abc")
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTodo(context.Background(), req); err != nil {
+			t.Fatalf("GetTodo() unexpected error: %v", err)
+		}
+	}
+
+	if got := req.Header().Get("If-None-Match"); got != "" {
+		t.Errorf("request If-None-Match = %q, want the caller's request unchanged", got)
+	}
+	if got := req.Header().Get("X-Request-Id"); got != "abc" {
+		t.Errorf("request X-Request-Id = %q, want abc", got)
+	}
+	if fake.conditionalOK != 1 {
+		t.Errorf("Not modified responses = %d, want 1", fake.conditionalOK)
+	}
+}
+
+func TestClient_DeleteTodo_EvictsCachedTodo(t *testing.T) {
+	fake := &fakeTodoServiceClient{
+		todo: &todov1.Todo{Id: "123"},
+		etag: `"123-1"`,
+	}
+	client := newClient(fake, options{cacheSize: 8})
+
+	if _, err := client.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"})); err != nil {
+		t.Fatalf("GetTodo() unexpected error: %v", err)
+	}
+
+	if _, err := client.DeleteTodo(context.Background(), connect.NewRequest(&todov1.DeleteTodoRequest{Id: "123"})); err != nil {
+		t.Fatalf("DeleteTodo() unexpected error: %v", err)
+	}
+
+	if client.cache.Len() != 0 {
+		t.Errorf("Cache length = %d, want 0", client.cache.Len())
+	}
+}
+
+func TestClient_CacheDisabled_PassesThrough(t *testing.T) {
+	fake := &fakeTodoServiceClient{
+		todo: &todov1.Todo{Id: "123"},
+		etag: `"123-1"`,
+	}
+	client := newClient(fake, options{})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTodo(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"})); err != nil {
+			t.Fatalf("GetTodo() unexpected error: %v", err)
+		}
+	}
+
+	if fake.conditionalOK != 0 {
+		t.Errorf("Not modified responses = %d, want 0", fake.conditionalOK)
+	}
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+
+	todov1connect "github.com/pivaldi/mmw/contracts/gen/go/todo/v1/todov1connect"
+)
+
+// idempotentHeader is set by the server on responses of retryable procedures
+// It must match the header used by the server's idempotency interceptor
+const idempotentHeader = "Todo-Idempotent"
+
+// knownIdempotentProcedures are retried even when the failure happened before
+// the server could mark the response (e.g. a proxy returning Unavailable)
+var knownIdempotentProcedures = map[string]bool{
+	todov1connect.TodoServiceGetTodoProcedure:   true,
+	todov1connect.TodoServiceListTodosProcedure: true,
+}
+
+// RetryPolicy configures automatic retries of idempotent calls
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	// A value of 1 or lower disables retries
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// BudgetRatio is the number of retries earned per request; together with
+	// BudgetCap it bounds the share of traffic that can be retries
+	BudgetRatio float64
+	// BudgetCap is the maximum number of retries that can be saved up
+	BudgetCap float64
+}
+
+// DefaultRetryPolicy returns the retry policy used by New
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		BudgetRatio:    0.1,
+		BudgetCap:      10,
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// NewRetryInterceptor creates a client interceptor that retries idempotent
+// calls failing with CodeUnavailable or CodeDeadlineExceeded, using jittered
+// exponential backoff and a retry budget to avoid retry storms
+func NewRetryInterceptor(policy RetryPolicy) connect.Interceptor {
+	budget := newRetryBudget(policy.BudgetRatio, policy.BudgetCap)
+
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			budget.deposit()
+
+			for attempt := 1; ; attempt++ {
+				resp, err := next(ctx, req)
+				if err == nil || attempt >= policy.MaxAttempts || !shouldRetry(req, err) {
+					return resp, err
+				}
+
+				if !budget.withdraw() {
+					return resp, err
+				}
+
+				timer := time.NewTimer(policy.backoff(attempt))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return resp, err
+				case <-timer.C:
+				}
+			}
+		}
+	})
+}
+
+// shouldRetry reports whether a failed call may be attempted again
+func shouldRetry(req connect.AnyRequest, err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded:
+	default:
+		return false
+	}
+
+	spec := req.Spec()
+	if spec.IdempotencyLevel != connect.IdempotencyUnknown || knownIdempotentProcedures[spec.Procedure] {
+		return true
+	}
+
+	var connectErr *connect.Error
+	return errors.As(err, &connectErr) && connectErr.Meta().Get(idempotentHeader) == "true"
+}
+
+// backoff returns the jittered delay before the given retry attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff << (attempt - 1)
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	// Full jitter: pick uniformly in [delay/2, delay)
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half)
+}
+
+// retryBudget is a token bucket limiting retries to a share of requests
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	ratio  float64
+	cap    float64
+}
+
+func newRetryBudget(ratio, cap float64) *retryBudget {
+	return &retryBudget{tokens: cap, ratio: ratio, cap: cap}
+}
+
+// deposit credits the budget for a new request
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.cap)
+}
+
+// withdraw consumes one retry, reporting whether the budget allowed it
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
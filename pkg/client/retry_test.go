@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	todov1 "github.com/pivaldi/mmw/contracts/gen/go/todo/v1"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		BudgetRatio:    0.1,
+		BudgetCap:      10,
+	}
+}
+
+// failingUnary returns a UnaryFunc failing with code and counting its calls
+func failingUnary(code connect.Code, idempotent bool, calls *int) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		*calls++
+		err := connect.NewError(code, errors.New("boom"))
+		if idempotent {
+			err.Meta().Set(idempotentHeader, "true")
+		}
+		return nil, err
+	}
+}
+
+func TestRetryInterceptor_RetriesIdempotentUnavailable(t *testing.T) {
+	calls := 0
+	unary := NewRetryInterceptor(testRetryPolicy()).WrapUnary(failingUnary(connect.CodeUnavailable, true, &calls))
+
+	_, err := unary(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Errorf("Error code = %v, want %v", connect.CodeOf(err), connect.CodeUnavailable)
+	}
+
+	if calls != 3 {
+		t.Errorf("Attempts = %d, want 3", calls)
+	}
+}
+
+func TestRetryInterceptor_DoesNotRetryNonIdempotent(t *testing.T) {
+	calls := 0
+	unary := NewRetryInterceptor(testRetryPolicy()).WrapUnary(failingUnary(connect.CodeUnavailable, false, &calls))
+
+	_, _ = unary(context.Background(), connect.NewRequest(&todov1.CreateTodoRequest{Title: "Test"}))
+
+	if calls != 1 {
+		t.Errorf("Attempts = %d, want 1", calls)
+	}
+}
+
+func TestRetryInterceptor_DoesNotRetryOtherCodes(t *testing.T) {
+	calls := 0
+	unary := NewRetryInterceptor(testRetryPolicy()).WrapUnary(failingUnary(connect.CodeNotFound, true, &calls))
+
+	_, _ = unary(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+
+	if calls != 1 {
+		t.Errorf("Attempts = %d, want 1", calls)
+	}
+}
+
+func TestRetryInterceptor_StopsWhenBudgetExhausted(t *testing.T) {
+	policy := testRetryPolicy()
+	policy.BudgetRatio = 0
+	policy.BudgetCap = 1
+
+	calls := 0
+	unary := NewRetryInterceptor(policy).WrapUnary(failingUnary(connect.CodeUnavailable, true, &calls))
+
+	_, _ = unary(context.Background(), connect.NewRequest(&todov1.GetTodoRequest{Id: "123"}))
+
+	if calls != 2 {
+		t.Errorf("Attempts = %d, want 2 (one retry allowed by the budget)", calls)
+	}
+}
+
+func TestRetryPolicy_Backoff_StaysWithinBounds(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := policy.backoff(attempt)
+		if delay <= 0 || delay > policy.MaxBackoff {
+			t.Errorf("backoff(%d) = %v, want within (0, %v]", attempt, delay, policy.MaxBackoff)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- Drop backfill progress table
+DROP TABLE IF EXISTS backfill_progress;
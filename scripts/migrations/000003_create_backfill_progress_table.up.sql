@@ -0,0 +1,16 @@
+-- Track progress of batched, resumable data backfills
+CREATE TABLE IF NOT EXISTS backfill_progress (
+    name VARCHAR(100) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    cursor TEXT NOT NULL DEFAULT '',
+    processed BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT valid_backfill_status CHECK (status IN ('running', 'completed', 'failed'))
+);
+
+COMMENT ON TABLE backfill_progress IS 'Checkpoints of backfill jobs run via todoctl';
+COMMENT ON COLUMN backfill_progress.cursor IS 'Last processed item; the next batch resumes after it';
@@ -0,0 +1,2 @@
+-- Drop completed_at column
+ALTER TABLE todos DROP COLUMN IF EXISTS completed_at;
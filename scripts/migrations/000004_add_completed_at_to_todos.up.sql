@@ -0,0 +1,5 @@
+-- Track when a todo was completed; populated for existing rows by the
+-- completed_at backfill job (todoctl backfill run completed_at)
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN todos.completed_at IS 'NULL unless status is completed';
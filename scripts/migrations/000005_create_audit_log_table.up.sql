@@ -0,0 +1,18 @@
+-- Append-only log of privileged actions (e.g. administrative overrides)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    todo_id UUID NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT reason_not_empty CHECK (length(trim(reason)) > 0)
+);
+
+-- Index for querying the history of a todo
+CREATE INDEX idx_audit_log_by_todo ON audit_log(todo_id, occurred_at DESC);
+
+COMMENT ON TABLE audit_log IS 'Append-only record of privileged actions on todos';
+COMMENT ON COLUMN audit_log.changes IS 'Modified fields mapped to their new value';
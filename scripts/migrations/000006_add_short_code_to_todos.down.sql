@@ -0,0 +1,3 @@
+-- Drop short codes; the owned sequence is dropped with the column
+DROP INDEX IF EXISTS idx_todos_short_code;
+ALTER TABLE todos DROP COLUMN IF EXISTS short_code;
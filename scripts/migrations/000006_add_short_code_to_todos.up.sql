@@ -0,0 +1,10 @@
+-- Human-friendly todo identifiers (TD-<short_code>) allocated from a sequence
+CREATE SEQUENCE IF NOT EXISTS todo_short_code_seq;
+
+-- Existing rows are numbered when the column is added
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS short_code BIGINT NOT NULL DEFAULT nextval('todo_short_code_seq');
+ALTER SEQUENCE todo_short_code_seq OWNED BY todos.short_code;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todos_short_code ON todos(short_code);
+
+COMMENT ON COLUMN todos.short_code IS 'Displayed as TD-<short_code>';
@@ -0,0 +1,2 @@
+-- Drop trigram index; the pg_trgm extension is left in place for other users
+DROP INDEX IF EXISTS idx_todos_title_trgm;
@@ -0,0 +1,4 @@
+-- Trigram index serving substring title matches for autocomplete suggestions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_todos_title_trgm ON todos USING gin (title gin_trgm_ops);
@@ -0,0 +1,2 @@
+-- Drop todo activity table
+DROP TABLE IF EXISTS todo_activity;
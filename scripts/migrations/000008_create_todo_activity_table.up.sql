@@ -0,0 +1,15 @@
+-- Last time each user viewed or modified each todo, for "jump back in" lists
+CREATE TABLE IF NOT EXISTS todo_activity (
+    user_id VARCHAR(255) NOT NULL,
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    last_modified_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (user_id, todo_id)
+);
+
+-- Indexes for listing a user's recent todos
+CREATE INDEX idx_todo_activity_viewed ON todo_activity(user_id, last_viewed_at DESC) WHERE last_viewed_at IS NOT NULL;
+CREATE INDEX idx_todo_activity_modified ON todo_activity(user_id, last_modified_at DESC) WHERE last_modified_at IS NOT NULL;
+
+COMMENT ON TABLE todo_activity IS 'Per-user recently viewed and modified todos';
@@ -0,0 +1,2 @@
+-- Drop client profiles table
+DROP TABLE IF EXISTS client_profiles;
@@ -0,0 +1,14 @@
+-- Per-client ListTodos defaults, applied when a request omits a parameter
+CREATE TABLE IF NOT EXISTS client_profiles (
+    client_id VARCHAR(255) PRIMARY KEY,
+    page_size INTEGER,
+    status VARCHAR(20),
+    priority VARCHAR(20),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT valid_page_size CHECK (page_size IS NULL OR page_size > 0),
+    CONSTRAINT valid_status CHECK (status IS NULL OR status IN ('pending', 'in_progress', 'completed', 'cancelled')),
+    CONSTRAINT valid_priority CHECK (priority IS NULL OR priority IN ('low', 'medium', 'high', 'urgent'))
+);
+
+COMMENT ON TABLE client_profiles IS 'Default ListTodos behavior stored per client';
@@ -0,0 +1,4 @@
+-- Drop todo history
+DROP TRIGGER IF EXISTS todos_record_history ON todos;
+DROP FUNCTION IF EXISTS record_todo_history();
+DROP TABLE IF EXISTS todo_history;
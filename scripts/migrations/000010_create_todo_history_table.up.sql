@@ -0,0 +1,52 @@
+-- Every version of every todo, recorded by trigger so that no write path can
+-- skip it; used to read a todo as it was at a past moment
+CREATE TABLE IF NOT EXISTS todo_history (
+    id BIGSERIAL PRIMARY KEY,
+    todo_id UUID NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL,
+    priority VARCHAR(20) NOT NULL,
+    due_date TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    short_code BIGINT,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Index for finding the version of a todo current at a given time
+CREATE INDEX idx_todo_history_as_of ON todo_history(todo_id, recorded_at DESC, id DESC);
+
+CREATE OR REPLACE FUNCTION record_todo_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+            created_at, updated_at, completed_at, short_code, deleted)
+        VALUES (OLD.id, OLD.title, OLD.description, OLD.status, OLD.priority, OLD.due_date,
+            OLD.created_at, OLD.updated_at, OLD.completed_at, OLD.short_code, TRUE);
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+        created_at, updated_at, completed_at, short_code)
+    VALUES (NEW.id, NEW.title, NEW.description, NEW.status, NEW.priority, NEW.due_date,
+        NEW.created_at, NEW.updated_at, NEW.completed_at, NEW.short_code);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER todos_record_history
+    AFTER INSERT OR UPDATE OR DELETE ON todos
+    FOR EACH ROW EXECUTE FUNCTION record_todo_history();
+
+-- History starts with the current version of existing todos
+INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+    created_at, updated_at, completed_at, short_code, recorded_at)
+SELECT id, title, description, status, priority, due_date,
+    created_at, updated_at, completed_at, short_code, updated_at
+FROM todos;
+
+COMMENT ON TABLE todo_history IS 'Versions of todos, for time-travel reads';
+COMMENT ON COLUMN todo_history.recorded_at IS 'When the version became current';
@@ -0,0 +1,3 @@
+-- Remove merged_into column from todos
+DROP INDEX IF EXISTS idx_todos_merged_into;
+ALTER TABLE todos DROP COLUMN IF EXISTS merged_into;
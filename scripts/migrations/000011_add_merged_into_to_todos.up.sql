@@ -0,0 +1,6 @@
+-- Reference from a merged duplicate to the canonical todo it was merged into
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES todos(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_merged_into ON todos(merged_into) WHERE merged_into IS NOT NULL;
+
+COMMENT ON COLUMN todos.merged_into IS 'Canonical todo this cancelled duplicate was merged into';
@@ -0,0 +1,2 @@
+-- Drop todo completions table
+DROP TABLE IF EXISTS todo_completions;
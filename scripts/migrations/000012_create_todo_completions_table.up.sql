@@ -0,0 +1,13 @@
+-- Every completion of a todo by a user, kept when the todo is deleted, for
+-- the per-user completion heatmap
+CREATE TABLE IF NOT EXISTS todo_completions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    todo_id UUID NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Index for counting a user's completions per day
+CREATE INDEX idx_todo_completions_user ON todo_completions(user_id, completed_at);
+
+COMMENT ON TABLE todo_completions IS 'Completions of todos by user, for activity heatmaps';
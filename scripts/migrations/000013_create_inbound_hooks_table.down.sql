@@ -0,0 +1,2 @@
+-- Drop inbound hooks table
+DROP TABLE IF EXISTS inbound_hooks;
@@ -0,0 +1,15 @@
+-- Tokenized endpoints that create todos from arbitrary JSON payloads
+-- Only a SHA-256 hash of each token is stored
+CREATE TABLE IF NOT EXISTS inbound_hooks (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    title_template TEXT NOT NULL,
+    description_template TEXT NOT NULL DEFAULT '',
+    priority_template TEXT NOT NULL DEFAULT '',
+    due_date_template TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE inbound_hooks IS 'Inbound webhooks mapping JSON payloads to new todos';
+COMMENT ON COLUMN inbound_hooks.title_template IS 'Go text/template executed against the payload';
@@ -0,0 +1,2 @@
+-- Drop legal holds table
+DROP TABLE IF EXISTS legal_holds;
@@ -0,0 +1,10 @@
+-- Legal holds placed by administrators on todos
+-- A held todo cannot be deleted: the foreign key refuses it even outside the service
+CREATE TABLE IF NOT EXISTS legal_holds (
+    todo_id UUID PRIMARY KEY REFERENCES todos (id) ON DELETE RESTRICT,
+    reason TEXT NOT NULL,
+    placed_by VARCHAR(100) NOT NULL,
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE legal_holds IS 'Todos that must be kept until their legal hold is released';
@@ -0,0 +1,2 @@
+-- Drop the full-text search index
+DROP INDEX IF EXISTS idx_todos_search;
@@ -0,0 +1,6 @@
+-- Full-text index serving keyword search over titles and descriptions
+-- The expression must match todoSearchDocument in the Postgres repository
+CREATE INDEX IF NOT EXISTS idx_todos_search ON todos USING gin ((
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+));
@@ -0,0 +1,3 @@
+-- Remove canary column from todos
+DROP INDEX IF EXISTS idx_todos_canary;
+ALTER TABLE todos DROP COLUMN IF EXISTS canary;
@@ -0,0 +1,6 @@
+-- Decoy todos whose access raises a security alert
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS canary BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_todos_canary ON todos(id) WHERE canary;
+
+COMMENT ON COLUMN todos.canary IS 'Decoy todo hidden from listings; any access raises a security alert';
@@ -0,0 +1,23 @@
+-- Restore the history trigger of migration 000010
+CREATE OR REPLACE FUNCTION record_todo_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+            created_at, updated_at, completed_at, short_code, deleted)
+        VALUES (OLD.id, OLD.title, OLD.description, OLD.status, OLD.priority, OLD.due_date,
+            OLD.created_at, OLD.updated_at, OLD.completed_at, OLD.short_code, TRUE);
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+        created_at, updated_at, completed_at, short_code)
+    VALUES (NEW.id, NEW.title, NEW.description, NEW.status, NEW.priority, NEW.due_date,
+        NEW.created_at, NEW.updated_at, NEW.completed_at, NEW.short_code);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Remove user_id columns
+ALTER TABLE todo_history DROP COLUMN IF EXISTS user_id;
+DROP INDEX IF EXISTS idx_todos_user_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS user_id;
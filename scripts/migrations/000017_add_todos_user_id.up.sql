@@ -0,0 +1,30 @@
+-- Owner of each todo; queries of an authenticated user only see their own
+-- todos. Todos created before ownership stay unowned (NULL)
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS user_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_id ON todos(user_id, created_at DESC);
+
+COMMENT ON COLUMN todos.user_id IS 'Authenticated user owning the todo, NULL if unowned';
+
+-- Versions keep their owner, so history reads can be scoped too
+ALTER TABLE todo_history ADD COLUMN IF NOT EXISTS user_id TEXT;
+
+UPDATE todo_history h SET user_id = t.user_id FROM todos t WHERE t.id = h.todo_id;
+
+CREATE OR REPLACE FUNCTION record_todo_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+            created_at, updated_at, completed_at, short_code, user_id, deleted)
+        VALUES (OLD.id, OLD.title, OLD.description, OLD.status, OLD.priority, OLD.due_date,
+            OLD.created_at, OLD.updated_at, OLD.completed_at, OLD.short_code, OLD.user_id, TRUE);
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO todo_history (todo_id, title, description, status, priority, due_date,
+        created_at, updated_at, completed_at, short_code, user_id)
+    VALUES (NEW.id, NEW.title, NEW.description, NEW.status, NEW.priority, NEW.due_date,
+        NEW.created_at, NEW.updated_at, NEW.completed_at, NEW.short_code, NEW.user_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,13 @@
+-- Static API keys authenticating machine clients such as cron jobs and CI
+-- Only a SHA-256 hash of each key is stored
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    key_hash BYTEA NOT NULL UNIQUE,
+    user_id TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE api_keys IS 'API keys of machine clients, authenticating as user_id';
+COMMENT ON COLUMN api_keys.scopes IS 'Token scopes granted to the key (read, write, admin)';
@@ -0,0 +1,3 @@
+-- Remove archived_at column from todos
+DROP INDEX IF EXISTS idx_todos_unarchived;
+ALTER TABLE todos DROP COLUMN IF EXISTS archived_at;
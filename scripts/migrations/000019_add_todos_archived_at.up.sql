@@ -0,0 +1,6 @@
+-- Archived todos stay stored but are left out of default listings
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_todos_unarchived ON todos(created_at DESC) WHERE archived_at IS NULL;
+
+COMMENT ON COLUMN todos.archived_at IS 'When the todo was archived, NULL if it is not';
@@ -0,0 +1,31 @@
+-- Outbound webhooks: endpoints domain events are posted to, and every
+-- delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE webhook_endpoints IS 'URLs domain events are posted to as JSON';
+COMMENT ON COLUMN webhook_endpoints.event_types IS 'Event types posted to the endpoint, every type when empty';
+COMMENT ON COLUMN webhook_endpoints.secret IS 'Key of the HMAC-SHA256 signature of the requests';
+
+-- Attempts outlive their endpoint, so deliveries have no foreign key
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    endpoint_id UUID NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, attempted_at DESC);
+
+COMMENT ON TABLE webhook_deliveries IS 'Attempts to post domain events to webhook endpoints';
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'HTTP status of the response, NULL when none was received';
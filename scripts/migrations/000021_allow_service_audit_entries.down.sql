@@ -0,0 +1,5 @@
+DELETE FROM audit_log WHERE todo_id IS NULL;
+
+ALTER TABLE audit_log ALTER COLUMN todo_id SET NOT NULL;
+
+COMMENT ON COLUMN audit_log.todo_id IS NULL;
@@ -0,0 +1,5 @@
+-- Service-wide privileged actions, such as configuration reloads, apply to
+-- no todo
+ALTER TABLE audit_log ALTER COLUMN todo_id DROP NOT NULL;
+
+COMMENT ON COLUMN audit_log.todo_id IS 'Todo the action applied to, NULL for service-wide actions';
@@ -0,0 +1,2 @@
+-- Drop the todo event store
+DROP TABLE IF EXISTS todo_events;
@@ -0,0 +1,15 @@
+-- Event store of the event-sourced todo repository: the append-only stream
+-- of the changes of each todo, replayed to rebuild it
+CREATE TABLE IF NOT EXISTS todo_events (
+    todo_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- Two writers appending the same version conflict instead of forking the stream
+    PRIMARY KEY (todo_id, version)
+);
+
+COMMENT ON TABLE todo_events IS 'Append-only streams of todo changes, used when REPOSITORY=eventstore';
+COMMENT ON COLUMN todo_events.version IS 'Position of the event in the stream of the todo, from 1';
+COMMENT ON COLUMN todo_events.payload IS 'Fields set by the event, a null value clearing the field';
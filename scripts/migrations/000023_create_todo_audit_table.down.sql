@@ -0,0 +1,2 @@
+-- Drop the per-todo audit history
+DROP TABLE IF EXISTS todo_audit;
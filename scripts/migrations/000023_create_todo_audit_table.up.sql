@@ -0,0 +1,18 @@
+-- Every domain event of every todo with who caused it, for the per-todo
+-- audit history
+CREATE TABLE IF NOT EXISTS todo_audit (
+    id BIGSERIAL PRIMARY KEY,
+    -- No foreign key: the history outlives the deletion of the todo
+    todo_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Index for listing the history of a todo in order
+CREATE INDEX idx_todo_audit_by_todo ON todo_audit(todo_id, occurred_at, id);
+
+COMMENT ON TABLE todo_audit IS 'Append-only history of the domain events of each todo';
+COMMENT ON COLUMN todo_audit.actor IS 'User who caused the event, empty for the service itself';
+COMMENT ON COLUMN todo_audit.changes IS 'Fields set by the event mapped to their new value';
@@ -0,0 +1,2 @@
+-- Remove version column from todos
+ALTER TABLE todos DROP COLUMN IF EXISTS version;
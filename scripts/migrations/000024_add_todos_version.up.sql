@@ -0,0 +1,5 @@
+-- Version of each todo, incremented by every update, so that a write based
+-- on a stale read is refused instead of silently overwriting
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN todos.version IS 'Number of saves of the todo, from 1 on creation';
@@ -0,0 +1,9 @@
+-- Holidays of the business calendar
+-- Nobody works on these days: reminders and planning skip them
+CREATE TABLE IF NOT EXISTS holidays (
+    day DATE PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE holidays IS 'Non-working days of the business calendar';
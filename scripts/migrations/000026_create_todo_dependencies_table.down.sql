@@ -0,0 +1,2 @@
+-- Drop todo dependencies table
+DROP TABLE IF EXISTS todo_dependencies;
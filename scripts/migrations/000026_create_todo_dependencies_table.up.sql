@@ -0,0 +1,14 @@
+-- Blocked-by relations between todos: todo_id cannot start before blocked_by
+-- is done
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    blocked_by UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (todo_id, blocked_by),
+    CHECK (todo_id <> blocked_by)
+);
+
+-- Index for following the relations from the blocking todo
+CREATE INDEX idx_todo_dependencies_blocked_by ON todo_dependencies(blocked_by);
+
+COMMENT ON TABLE todo_dependencies IS 'Todos blocked by other todos; the service keeps the graph acyclic';
@@ -0,0 +1,3 @@
+-- Drop milestones tables
+DROP TABLE IF EXISTS milestone_todos;
+DROP TABLE IF EXISTS milestones;
@@ -0,0 +1,27 @@
+-- Milestones todos are attached to, tracking the progress towards a target
+-- date
+CREATE TABLE IF NOT EXISTS milestones (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    target_date DATE NOT NULL,
+    user_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE milestones IS 'Named targets grouping todos';
+COMMENT ON COLUMN milestones.user_id IS 'Authenticated user owning the milestone, NULL if unowned';
+COMMENT ON COLUMN milestones.completed_at IS 'When every todo of the milestone was done, NULL while some are open';
+
+-- A todo belongs to one milestone at most
+CREATE TABLE IF NOT EXISTS milestone_todos (
+    todo_id UUID PRIMARY KEY REFERENCES todos (id) ON DELETE CASCADE,
+    milestone_id UUID NOT NULL REFERENCES milestones (id) ON DELETE CASCADE,
+    estimate_minutes INTEGER NOT NULL DEFAULT 0 CHECK (estimate_minutes >= 0),
+    attached_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Index for loading the todos of a milestone
+CREATE INDEX idx_milestone_todos_milestone ON milestone_todos(milestone_id);
+
+COMMENT ON COLUMN milestone_todos.estimate_minutes IS 'Work of the todo weighting the progress of the milestone, 0 if not estimated';
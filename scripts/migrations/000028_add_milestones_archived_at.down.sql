@@ -0,0 +1,2 @@
+-- Drop milestones archival
+ALTER TABLE milestones DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,5 @@
+-- Archived milestones are hidden from default listings and freeze their
+-- todos read-only
+ALTER TABLE milestones ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN milestones.archived_at IS 'When the milestone was archived, freezing its todos, NULL if active';
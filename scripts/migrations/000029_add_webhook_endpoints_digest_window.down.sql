@@ -0,0 +1,2 @@
+-- Drop webhook digests
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS digest_window_ms;
@@ -0,0 +1,5 @@
+-- Endpoints with a digest window receive the events raised on a todo within
+-- the window as a single delivery
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS digest_window_ms INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN webhook_endpoints.digest_window_ms IS 'Window grouping the events on a todo into one delivery, 0 to post each event';
@@ -0,0 +1,2 @@
+-- Drop devices table
+DROP TABLE IF EXISTS devices;
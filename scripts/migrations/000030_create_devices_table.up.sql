@@ -0,0 +1,15 @@
+-- Mobile app installs push notifications are sent to
+CREATE TABLE IF NOT EXISTS devices (
+    token TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    provider TEXT NOT NULL CHECK (provider IN ('fcm', 'apns')),
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Index for loading the devices of a user
+CREATE INDEX idx_devices_user_id ON devices(user_id, last_seen_at DESC);
+
+COMMENT ON TABLE devices IS 'Push notification registration tokens of the users';
+COMMENT ON COLUMN devices.provider IS 'Service delivering the notifications: fcm or apns';
+COMMENT ON COLUMN devices.last_seen_at IS 'Last registration of the token by the app; stale tokens are pruned';
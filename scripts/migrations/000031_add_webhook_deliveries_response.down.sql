@@ -0,0 +1,5 @@
+-- Drop the responses and payloads of the webhook deliveries
+DROP INDEX IF EXISTS idx_webhook_deliveries_endpoint_id;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS redelivery_of;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS payload;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS response_snippet;
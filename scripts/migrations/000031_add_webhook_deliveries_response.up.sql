@@ -0,0 +1,12 @@
+-- Delivery attempts keep the start of the response and the posted body, so
+-- that integrators can see why an attempt failed and have it made again
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS response_snippet TEXT NOT NULL DEFAULT '';
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS payload BYTEA;
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS redelivery_of BIGINT;
+
+-- Index for paging through the attempts of an endpoint, newest first
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id ON webhook_deliveries (endpoint_id, id DESC);
+
+COMMENT ON COLUMN webhook_deliveries.response_snippet IS 'Start of the response body, as text';
+COMMENT ON COLUMN webhook_deliveries.payload IS 'Body posted to the endpoint, NULL for attempts recorded before it was kept';
+COMMENT ON COLUMN webhook_deliveries.redelivery_of IS 'Attempt a manual redelivery repeated, NULL for automatic attempts';
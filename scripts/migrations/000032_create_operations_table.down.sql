@@ -0,0 +1,2 @@
+-- Drop operations table
+DROP TABLE IF EXISTS operations;
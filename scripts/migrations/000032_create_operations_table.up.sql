@@ -0,0 +1,26 @@
+-- Long-running operations, such as imports, run in the background
+CREATE TABLE IF NOT EXISTS operations (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    owner_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed', 'cancelled')),
+    done INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for listing the operations of an owner, newest first
+CREATE INDEX idx_operations_owner_id ON operations(owner_id, created_at DESC, id DESC);
+
+-- Index for pruning the finished operations
+CREATE INDEX idx_operations_finished_at ON operations(finished_at) WHERE finished_at IS NOT NULL;
+
+COMMENT ON TABLE operations IS 'Long-running operations started by the API, with their progress and outcome';
+COMMENT ON COLUMN operations.total IS 'Items to process, 0 while unknown';
+COMMENT ON COLUMN operations.result IS 'JSON outcome of a finished operation';
+COMMENT ON COLUMN operations.cancel_requested IS 'Set by CancelOperation; the instance running it stops at its next progress update';
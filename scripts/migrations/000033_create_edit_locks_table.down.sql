@@ -0,0 +1,2 @@
+-- Drop edit locks table
+DROP TABLE IF EXISTS edit_locks;
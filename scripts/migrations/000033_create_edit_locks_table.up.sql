@@ -0,0 +1,11 @@
+-- Advisory locks of the users editing todos
+-- A todo has at most one row: an expired lock is replaced by the next holder
+CREATE TABLE IF NOT EXISTS edit_locks (
+    todo_id UUID PRIMARY KEY REFERENCES todos (id) ON DELETE CASCADE,
+    holder_id TEXT NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE edit_locks IS 'Users editing todos, shown to the other editors; updates do not check them';
+COMMENT ON COLUMN edit_locks.expires_at IS 'The lock is ignored past this time unless its holder refreshes it';
@@ -0,0 +1,5 @@
+-- Remove the default ordering from client profiles
+ALTER TABLE client_profiles DROP CONSTRAINT IF EXISTS valid_sort_order;
+ALTER TABLE client_profiles DROP CONSTRAINT IF EXISTS valid_sort_by;
+ALTER TABLE client_profiles DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE client_profiles DROP COLUMN IF EXISTS sort_by;
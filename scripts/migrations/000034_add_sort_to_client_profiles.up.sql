@@ -0,0 +1,10 @@
+-- Default ListTodos ordering of a client; both set or both NULL
+ALTER TABLE client_profiles ADD COLUMN IF NOT EXISTS sort_by VARCHAR(20);
+ALTER TABLE client_profiles ADD COLUMN IF NOT EXISTS sort_order VARCHAR(4);
+
+ALTER TABLE client_profiles ADD CONSTRAINT valid_sort_by
+    CHECK (sort_by IS NULL OR sort_by IN ('created_at', 'updated_at', 'due_date', 'priority', 'title'));
+ALTER TABLE client_profiles ADD CONSTRAINT valid_sort_order
+    CHECK ((sort_by IS NULL AND sort_order IS NULL) OR (sort_by IS NOT NULL AND sort_order IN ('asc', 'desc')));
+
+COMMENT ON COLUMN client_profiles.sort_by IS 'Ordering applied when a ListTodos request has none';
@@ -0,0 +1,15 @@
+-- Restrict the outbox to the five todo events again, dropping the others
+DELETE FROM domain_events
+WHERE aggregate_id IS NULL
+    OR event_type NOT IN ('TodoCreated', 'TodoUpdated', 'TodoCompleted', 'TodoReopened', 'TodoDeleted')
+    OR event_data ? 'backfilled';
+
+COMMENT ON COLUMN domain_events.aggregate_id IS NULL;
+ALTER TABLE domain_events ALTER COLUMN aggregate_id SET NOT NULL;
+ALTER TABLE domain_events ADD CONSTRAINT valid_event_type CHECK (event_type IN (
+    'TodoCreated',
+    'TodoUpdated',
+    'TodoCompleted',
+    'TodoReopened',
+    'TodoDeleted'
+));
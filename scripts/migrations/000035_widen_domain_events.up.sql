@@ -0,0 +1,19 @@
+-- The outbox records every domain event with the write raising it, for the
+-- relay to publish; some events have no aggregate
+ALTER TABLE domain_events DROP CONSTRAINT IF EXISTS valid_event_type;
+ALTER TABLE domain_events ALTER COLUMN aggregate_id DROP NOT NULL;
+
+-- The todos created before the outbox are recorded as created and already
+-- published, so that reconciliation does not announce them again
+INSERT INTO domain_events (aggregate_id, event_type, event_data, occurred_at, published_at)
+SELECT t.id, 'TodoCreated',
+    jsonb_build_object('title', t.title, 'description', t.description,
+        'priority', t.priority, 'due_date', t.due_date, 'backfilled', true),
+    t.created_at, now()
+FROM todos t
+WHERE NOT EXISTS (
+    SELECT 1 FROM domain_events e
+    WHERE e.aggregate_id = t.id AND e.event_type = 'TodoCreated'
+);
+
+COMMENT ON COLUMN domain_events.aggregate_id IS 'NULL for the events without an aggregate';